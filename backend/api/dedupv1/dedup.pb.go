@@ -0,0 +1,1031 @@
+// gRPC API of the image dedup server: scans, duplicate listing and deletion planning
+// for Go services and automation. Calls carry the API token (API_TOKEN) as
+// "authorization: Bearer <token>" metadata.
+//
+// Regenerate with go generate ./api/dedupv1 after changing this file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: dedup.proto
+
+package dedupv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StartScanRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Gallery folder or directory inside one, empty for all gallery folders
+	Directory     string `protobuf:"bytes,1,opt,name=directory,proto3" json:"directory,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartScanRequest) Reset() {
+	*x = StartScanRequest{}
+	mi := &file_dedup_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartScanRequest) ProtoMessage() {}
+
+func (x *StartScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartScanRequest.ProtoReflect.Descriptor instead.
+func (*StartScanRequest) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StartScanRequest) GetDirectory() string {
+	if x != nil {
+		return x.Directory
+	}
+	return ""
+}
+
+type StartScanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartScanResponse) Reset() {
+	*x = StartScanResponse{}
+	mi := &file_dedup_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartScanResponse) ProtoMessage() {}
+
+func (x *StartScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartScanResponse.ProtoReflect.Descriptor instead.
+func (*StartScanResponse) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StartScanResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type GetScanJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScanJobRequest) Reset() {
+	*x = GetScanJobRequest{}
+	mi := &file_dedup_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScanJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScanJobRequest) ProtoMessage() {}
+
+func (x *GetScanJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScanJobRequest.ProtoReflect.Descriptor instead.
+func (*GetScanJobRequest) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetScanJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type ScanJob struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind  string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// queued, running, done, failed or cancelled
+	State          string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Progress       string `protobuf:"bytes,4,opt,name=progress,proto3" json:"progress,omitempty"`
+	FilesProcessed int64  `protobuf:"varint,5,opt,name=files_processed,json=filesProcessed,proto3" json:"files_processed,omitempty"`
+	Errors         int64  `protobuf:"varint,6,opt,name=errors,proto3" json:"errors,omitempty"`
+	// Reason the job failed
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanJob) Reset() {
+	*x = ScanJob{}
+	mi := &file_dedup_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanJob) ProtoMessage() {}
+
+func (x *ScanJob) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanJob.ProtoReflect.Descriptor instead.
+func (*ScanJob) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ScanJob) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScanJob) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ScanJob) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ScanJob) GetProgress() string {
+	if x != nil {
+		return x.Progress
+	}
+	return ""
+}
+
+func (x *ScanJob) GetFilesProcessed() int64 {
+	if x != nil {
+		return x.FilesProcessed
+	}
+	return 0
+}
+
+func (x *ScanJob) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *ScanJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ScanJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ScanJob) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *ScanJob) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+// DuplicateFilter narrows the duplicate groups like the filters of GET /api/v1/duplicates
+type DuplicateFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// image (default) or video
+	Media string `protobuf:"bytes,1,opt,name=media,proto3" json:"media,omitempty"`
+	// Only files under this absolute directory
+	Dir string `protobuf:"bytes,2,opt,name=dir,proto3" json:"dir,omitempty"`
+	// Only files with these extensions, e.g. ".png"
+	Extensions []string `protobuf:"bytes,3,rep,name=extensions,proto3" json:"extensions,omitempty"`
+	// Only files at least or at most this large, in bytes; 0 = no limit
+	MinSize int64 `protobuf:"varint,4,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
+	MaxSize int64 `protobuf:"varint,5,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	// unreviewed, reviewed or deferred
+	Review string `protobuf:"bytes,6,opt,name=review,proto3" json:"review,omitempty"`
+	// Only groups with copies on all these hosts, "local" for the server itself
+	Hosts         []string `protobuf:"bytes,7,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateFilter) Reset() {
+	*x = DuplicateFilter{}
+	mi := &file_dedup_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateFilter) ProtoMessage() {}
+
+func (x *DuplicateFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateFilter.ProtoReflect.Descriptor instead.
+func (*DuplicateFilter) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DuplicateFilter) GetMedia() string {
+	if x != nil {
+		return x.Media
+	}
+	return ""
+}
+
+func (x *DuplicateFilter) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+func (x *DuplicateFilter) GetExtensions() []string {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+func (x *DuplicateFilter) GetMinSize() int64 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *DuplicateFilter) GetMaxSize() int64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *DuplicateFilter) GetReview() string {
+	if x != nil {
+		return x.Review
+	}
+	return ""
+}
+
+func (x *DuplicateFilter) GetHosts() []string {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type ListDuplicatesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *DuplicateFilter       `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// wasted-space, file-count, newest, oldest or path; empty for the largest files first
+	Sort string `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	// Groups to skip and to return, 50 by default and at most 1000
+	Offset        int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDuplicatesRequest) Reset() {
+	*x = ListDuplicatesRequest{}
+	mi := &file_dedup_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDuplicatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDuplicatesRequest) ProtoMessage() {}
+
+func (x *ListDuplicatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDuplicatesRequest.ProtoReflect.Descriptor instead.
+func (*ListDuplicatesRequest) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListDuplicatesRequest) GetFilter() *DuplicateFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListDuplicatesRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListDuplicatesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListDuplicatesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type File struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Path    string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size    int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	// Pinned against deletion
+	Protected bool `protobuf:"varint,4,opt,name=protected,proto3" json:"protected,omitempty"`
+	// Agent that reported the file, empty for the files of the server
+	Host          string `protobuf:"bytes,5,opt,name=host,proto3" json:"host,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *File) Reset() {
+	*x = File{}
+	mi := &file_dedup_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *File) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*File) ProtoMessage() {}
+
+func (x *File) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use File.ProtoReflect.Descriptor instead.
+func (*File) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *File) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *File) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *File) GetModTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ModTime
+	}
+	return nil
+}
+
+func (x *File) GetProtected() bool {
+	if x != nil {
+		return x.Protected
+	}
+	return false
+}
+
+func (x *File) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+type DuplicateGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Files         []*File                `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateGroup) Reset() {
+	*x = DuplicateGroup{}
+	mi := &file_dedup_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateGroup) ProtoMessage() {}
+
+func (x *DuplicateGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateGroup.ProtoReflect.Descriptor instead.
+func (*DuplicateGroup) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DuplicateGroup) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *DuplicateGroup) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DuplicateGroup) GetFiles() []*File {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type ListDuplicatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*DuplicateGroup      `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	TotalGroups   int64                  `protobuf:"varint,2,opt,name=total_groups,json=totalGroups,proto3" json:"total_groups,omitempty"`
+	TotalFiles    int64                  `protobuf:"varint,3,opt,name=total_files,json=totalFiles,proto3" json:"total_files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDuplicatesResponse) Reset() {
+	*x = ListDuplicatesResponse{}
+	mi := &file_dedup_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDuplicatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDuplicatesResponse) ProtoMessage() {}
+
+func (x *ListDuplicatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDuplicatesResponse.ProtoReflect.Descriptor instead.
+func (*ListDuplicatesResponse) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListDuplicatesResponse) GetGroups() []*DuplicateGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *ListDuplicatesResponse) GetTotalGroups() int64 {
+	if x != nil {
+		return x.TotalGroups
+	}
+	return 0
+}
+
+func (x *ListDuplicatesResponse) GetTotalFiles() int64 {
+	if x != nil {
+		return x.TotalFiles
+	}
+	return 0
+}
+
+type KeepRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Keep policy, as in POST /api/v1/auto-select, e.g. keep-oldest or
+	// keep-in-priority-directory with directories
+	Policy        string   `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	Directories   []string `protobuf:"bytes,2,rep,name=directories,proto3" json:"directories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeepRule) Reset() {
+	*x = KeepRule{}
+	mi := &file_dedup_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeepRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeepRule) ProtoMessage() {}
+
+func (x *KeepRule) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeepRule.ProtoReflect.Descriptor instead.
+func (*KeepRule) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *KeepRule) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *KeepRule) GetDirectories() []string {
+	if x != nil {
+		return x.Directories
+	}
+	return nil
+}
+
+type PlanDeletionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Applied in order, later rules break ties of earlier ones
+	KeepRules     []*KeepRule      `protobuf:"bytes,1,rep,name=keep_rules,json=keepRules,proto3" json:"keep_rules,omitempty"`
+	Filter        *DuplicateFilter `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanDeletionRequest) Reset() {
+	*x = PlanDeletionRequest{}
+	mi := &file_dedup_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanDeletionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanDeletionRequest) ProtoMessage() {}
+
+func (x *PlanDeletionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanDeletionRequest.ProtoReflect.Descriptor instead.
+func (*PlanDeletionRequest) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PlanDeletionRequest) GetKeepRules() []*KeepRule {
+	if x != nil {
+		return x.KeepRules
+	}
+	return nil
+}
+
+func (x *PlanDeletionRequest) GetFilter() *DuplicateFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type GroupPlan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Keep          string                 `protobuf:"bytes,2,opt,name=keep,proto3" json:"keep,omitempty"`
+	Remove        []string               `protobuf:"bytes,3,rep,name=remove,proto3" json:"remove,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupPlan) Reset() {
+	*x = GroupPlan{}
+	mi := &file_dedup_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupPlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupPlan) ProtoMessage() {}
+
+func (x *GroupPlan) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupPlan.ProtoReflect.Descriptor instead.
+func (*GroupPlan) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GroupPlan) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *GroupPlan) GetKeep() string {
+	if x != nil {
+		return x.Keep
+	}
+	return ""
+}
+
+func (x *GroupPlan) GetRemove() []string {
+	if x != nil {
+		return x.Remove
+	}
+	return nil
+}
+
+type PlanDeletionResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Groups           []*GroupPlan           `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	TotalRemove      int64                  `protobuf:"varint,2,opt,name=total_remove,json=totalRemove,proto3" json:"total_remove,omitempty"`
+	ReclaimableBytes int64                  `protobuf:"varint,3,opt,name=reclaimable_bytes,json=reclaimableBytes,proto3" json:"reclaimable_bytes,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PlanDeletionResponse) Reset() {
+	*x = PlanDeletionResponse{}
+	mi := &file_dedup_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanDeletionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanDeletionResponse) ProtoMessage() {}
+
+func (x *PlanDeletionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dedup_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanDeletionResponse.ProtoReflect.Descriptor instead.
+func (*PlanDeletionResponse) Descriptor() ([]byte, []int) {
+	return file_dedup_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PlanDeletionResponse) GetGroups() []*GroupPlan {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *PlanDeletionResponse) GetTotalRemove() int64 {
+	if x != nil {
+		return x.TotalRemove
+	}
+	return 0
+}
+
+func (x *PlanDeletionResponse) GetReclaimableBytes() int64 {
+	if x != nil {
+		return x.ReclaimableBytes
+	}
+	return 0
+}
+
+var File_dedup_proto protoreflect.FileDescriptor
+
+const file_dedup_proto_rawDesc = "" +
+	"\n" +
+	"\vdedup.proto\x12\rimagededup.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"0\n" +
+	"\x10StartScanRequest\x12\x1c\n" +
+	"\tdirectory\x18\x01 \x01(\tR\tdirectory\"*\n" +
+	"\x11StartScanResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"*\n" +
+	"\x11GetScanJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xe9\x02\n" +
+	"\aScanJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x1a\n" +
+	"\bprogress\x18\x04 \x01(\tR\bprogress\x12'\n" +
+	"\x0ffiles_processed\x18\x05 \x01(\x03R\x0efilesProcessed\x12\x16\n" +
+	"\x06errors\x18\x06 \x01(\x03R\x06errors\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"started_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\"\xbd\x01\n" +
+	"\x0fDuplicateFilter\x12\x14\n" +
+	"\x05media\x18\x01 \x01(\tR\x05media\x12\x10\n" +
+	"\x03dir\x18\x02 \x01(\tR\x03dir\x12\x1e\n" +
+	"\n" +
+	"extensions\x18\x03 \x03(\tR\n" +
+	"extensions\x12\x19\n" +
+	"\bmin_size\x18\x04 \x01(\x03R\aminSize\x12\x19\n" +
+	"\bmax_size\x18\x05 \x01(\x03R\amaxSize\x12\x16\n" +
+	"\x06review\x18\x06 \x01(\tR\x06review\x12\x14\n" +
+	"\x05hosts\x18\a \x03(\tR\x05hosts\"\x91\x01\n" +
+	"\x15ListDuplicatesRequest\x126\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1e.imagededup.v1.DuplicateFilterR\x06filter\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\x97\x01\n" +
+	"\x04File\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x125\n" +
+	"\bmod_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\amodTime\x12\x1c\n" +
+	"\tprotected\x18\x04 \x01(\bR\tprotected\x12\x12\n" +
+	"\x04host\x18\x05 \x01(\tR\x04host\"c\n" +
+	"\x0eDuplicateGroup\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12)\n" +
+	"\x05files\x18\x03 \x03(\v2\x13.imagededup.v1.FileR\x05files\"\x93\x01\n" +
+	"\x16ListDuplicatesResponse\x125\n" +
+	"\x06groups\x18\x01 \x03(\v2\x1d.imagededup.v1.DuplicateGroupR\x06groups\x12!\n" +
+	"\ftotal_groups\x18\x02 \x01(\x03R\vtotalGroups\x12\x1f\n" +
+	"\vtotal_files\x18\x03 \x01(\x03R\n" +
+	"totalFiles\"D\n" +
+	"\bKeepRule\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12 \n" +
+	"\vdirectories\x18\x02 \x03(\tR\vdirectories\"\x85\x01\n" +
+	"\x13PlanDeletionRequest\x126\n" +
+	"\n" +
+	"keep_rules\x18\x01 \x03(\v2\x17.imagededup.v1.KeepRuleR\tkeepRules\x126\n" +
+	"\x06filter\x18\x02 \x01(\v2\x1e.imagededup.v1.DuplicateFilterR\x06filter\"K\n" +
+	"\tGroupPlan\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x12\n" +
+	"\x04keep\x18\x02 \x01(\tR\x04keep\x12\x16\n" +
+	"\x06remove\x18\x03 \x03(\tR\x06remove\"\x98\x01\n" +
+	"\x14PlanDeletionResponse\x120\n" +
+	"\x06groups\x18\x01 \x03(\v2\x18.imagededup.v1.GroupPlanR\x06groups\x12!\n" +
+	"\ftotal_remove\x18\x02 \x01(\x03R\vtotalRemove\x12+\n" +
+	"\x11reclaimable_bytes\x18\x03 \x01(\x03R\x10reclaimableBytes2\xde\x02\n" +
+	"\fDedupService\x12N\n" +
+	"\tStartScan\x12\x1f.imagededup.v1.StartScanRequest\x1a .imagededup.v1.StartScanResponse\x12F\n" +
+	"\n" +
+	"GetScanJob\x12 .imagededup.v1.GetScanJobRequest\x1a\x16.imagededup.v1.ScanJob\x12]\n" +
+	"\x0eListDuplicates\x12$.imagededup.v1.ListDuplicatesRequest\x1a%.imagededup.v1.ListDuplicatesResponse\x12W\n" +
+	"\fPlanDeletion\x12\".imagededup.v1.PlanDeletionRequest\x1a#.imagededup.v1.PlanDeletionResponseB#Z!image-toolkit/api/dedupv1;dedupv1b\x06proto3"
+
+var (
+	file_dedup_proto_rawDescOnce sync.Once
+	file_dedup_proto_rawDescData []byte
+)
+
+func file_dedup_proto_rawDescGZIP() []byte {
+	file_dedup_proto_rawDescOnce.Do(func() {
+		file_dedup_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_dedup_proto_rawDesc), len(file_dedup_proto_rawDesc)))
+	})
+	return file_dedup_proto_rawDescData
+}
+
+var file_dedup_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_dedup_proto_goTypes = []any{
+	(*StartScanRequest)(nil),       // 0: imagededup.v1.StartScanRequest
+	(*StartScanResponse)(nil),      // 1: imagededup.v1.StartScanResponse
+	(*GetScanJobRequest)(nil),      // 2: imagededup.v1.GetScanJobRequest
+	(*ScanJob)(nil),                // 3: imagededup.v1.ScanJob
+	(*DuplicateFilter)(nil),        // 4: imagededup.v1.DuplicateFilter
+	(*ListDuplicatesRequest)(nil),  // 5: imagededup.v1.ListDuplicatesRequest
+	(*File)(nil),                   // 6: imagededup.v1.File
+	(*DuplicateGroup)(nil),         // 7: imagededup.v1.DuplicateGroup
+	(*ListDuplicatesResponse)(nil), // 8: imagededup.v1.ListDuplicatesResponse
+	(*KeepRule)(nil),               // 9: imagededup.v1.KeepRule
+	(*PlanDeletionRequest)(nil),    // 10: imagededup.v1.PlanDeletionRequest
+	(*GroupPlan)(nil),              // 11: imagededup.v1.GroupPlan
+	(*PlanDeletionResponse)(nil),   // 12: imagededup.v1.PlanDeletionResponse
+	(*timestamppb.Timestamp)(nil),  // 13: google.protobuf.Timestamp
+}
+var file_dedup_proto_depIdxs = []int32{
+	13, // 0: imagededup.v1.ScanJob.created_at:type_name -> google.protobuf.Timestamp
+	13, // 1: imagededup.v1.ScanJob.started_at:type_name -> google.protobuf.Timestamp
+	13, // 2: imagededup.v1.ScanJob.finished_at:type_name -> google.protobuf.Timestamp
+	4,  // 3: imagededup.v1.ListDuplicatesRequest.filter:type_name -> imagededup.v1.DuplicateFilter
+	13, // 4: imagededup.v1.File.mod_time:type_name -> google.protobuf.Timestamp
+	6,  // 5: imagededup.v1.DuplicateGroup.files:type_name -> imagededup.v1.File
+	7,  // 6: imagededup.v1.ListDuplicatesResponse.groups:type_name -> imagededup.v1.DuplicateGroup
+	9,  // 7: imagededup.v1.PlanDeletionRequest.keep_rules:type_name -> imagededup.v1.KeepRule
+	4,  // 8: imagededup.v1.PlanDeletionRequest.filter:type_name -> imagededup.v1.DuplicateFilter
+	11, // 9: imagededup.v1.PlanDeletionResponse.groups:type_name -> imagededup.v1.GroupPlan
+	0,  // 10: imagededup.v1.DedupService.StartScan:input_type -> imagededup.v1.StartScanRequest
+	2,  // 11: imagededup.v1.DedupService.GetScanJob:input_type -> imagededup.v1.GetScanJobRequest
+	5,  // 12: imagededup.v1.DedupService.ListDuplicates:input_type -> imagededup.v1.ListDuplicatesRequest
+	10, // 13: imagededup.v1.DedupService.PlanDeletion:input_type -> imagededup.v1.PlanDeletionRequest
+	1,  // 14: imagededup.v1.DedupService.StartScan:output_type -> imagededup.v1.StartScanResponse
+	3,  // 15: imagededup.v1.DedupService.GetScanJob:output_type -> imagededup.v1.ScanJob
+	8,  // 16: imagededup.v1.DedupService.ListDuplicates:output_type -> imagededup.v1.ListDuplicatesResponse
+	12, // 17: imagededup.v1.DedupService.PlanDeletion:output_type -> imagededup.v1.PlanDeletionResponse
+	14, // [14:18] is the sub-list for method output_type
+	10, // [10:14] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_dedup_proto_init() }
+func file_dedup_proto_init() {
+	if File_dedup_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_dedup_proto_rawDesc), len(file_dedup_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_dedup_proto_goTypes,
+		DependencyIndexes: file_dedup_proto_depIdxs,
+		MessageInfos:      file_dedup_proto_msgTypes,
+	}.Build()
+	File_dedup_proto = out.File
+	file_dedup_proto_goTypes = nil
+	file_dedup_proto_depIdxs = nil
+}
@@ -0,0 +1,5 @@
+// Package dedupv1 holds the protobuf types and gRPC stubs of the image dedup API,
+// generated from dedup.proto, for clients embedding the server into their services.
+package dedupv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative dedup.proto
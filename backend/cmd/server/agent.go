@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/config"
+	"image-toolkit/internal/infrastructure/database"
+	"image-toolkit/internal/infrastructure/objectstore"
+	"image-toolkit/internal/interfaces/dto"
+
+	"gorm.io/gorm"
+)
+
+// agentBatchSize is the number of files reported per request
+const agentBatchSize = 500
+
+// runAgentCommand reports local folders to a central server:
+// image-dedup agent -server <url> [flags] [dir...]
+func runAgentCommand(c command, args []string) int {
+	f := newCLIFlags(c)
+	f.addScanFlags()
+	server := f.fs.String("server", "", "URL of the central server to report to, e.g. http://central:5170")
+	f.fs.StringVar(&f.apiToken, "api-token", "", "API token of the central server (overrides API_TOKEN)")
+	name := f.fs.String("agent-name", "", "name the files are reported under (default: host name)")
+	verbose := f.fs.Bool("v", false, "print scan progress to stderr")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, _ := f.load()
+	cfg.ScanDirectories = append(cfg.ScanDirectories, f.fs.Args()...)
+	return runAgent(cfg, *server, *name, *verbose)
+}
+
+// runAgent scans the local gallery folders into the agent index and reports it to
+// the central server, returning the exit code. Unchanged files are not hashed again,
+// since the agent index is kept between runs.
+func runAgent(cfg *config.AppConfig, server, name string, verbose bool) int {
+	serverURL, err := url.Parse(strings.TrimSuffix(server, "/"))
+	if err != nil || (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+		slog.Error("Invalid -server (expected e.g. http://central:5170)", "server", server)
+		return 1
+	}
+	if name == "" {
+		host, _ := os.Hostname()
+		name = regexp.MustCompile(`[^A-Za-z0-9._-]+`).ReplaceAllString(host, "-")
+	}
+	if !imaging.ValidAgentName(name) {
+		slog.Error("Invalid -agent-name: letters, digits, dots, dashes and underscores", "name", name)
+		return 1
+	}
+
+	scanOptions, err := buildScanOptions(cfg)
+	if err != nil {
+		slog.Error("Invalid scan options", "error", err)
+		return 1
+	}
+	// The agent index is a local SQLite file unless a database is configured
+	if cfg.DatabaseURL == "" && !strings.HasPrefix(cfg.DBBackend, "sqlite:") {
+		path := agentDatabasePath()
+		cfg.DatabaseURL = "sqlite:" + path
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			slog.Error("Failed to create the agent index directory", "error", err)
+			return 1
+		}
+	}
+	db, err := database.InitDatabase(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize database", "error", err)
+		return 1
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	if err := database.ApplyStartupConfig(db, cfg); err != nil {
+		slog.Error("Failed to apply configuration", "error", err)
+		return 1
+	}
+
+	var roots []string
+	var folders []domain.GalleryFolder
+	db.Find(&folders)
+	for _, f := range folders {
+		if !objectstore.IsRemote(f.Path) {
+			roots = append(roots, f.Path)
+		}
+	}
+	if len(roots) == 0 {
+		slog.Error("No folders to scan: pass directories as arguments or set SCAN_DIRECTORIES")
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var progress func(string)
+	if verbose {
+		progress = func(msg string) { fmt.Fprintln(os.Stderr, msg) }
+	}
+	if err := imaging.RunScan(ctx, db, scanOptions, progress); err != nil {
+		slog.Error("Scan failed", "error", err)
+		return 1
+	}
+
+	client := &agentClient{server: serverURL.String(), token: cfg.APIToken, name: name, http: &http.Client{Timeout: 5 * time.Minute}}
+	reported, removed, err := client.report(ctx, db, roots)
+	if err != nil {
+		slog.Error("Failed to report to the central server", "server", client.server, "error", err)
+		return 1
+	}
+	slog.Info("Agent report complete", "agent", name, "server", client.server, "files", reported, "removed", removed)
+	return 0
+}
+
+// agentDatabasePath returns the default location of the agent index
+func agentDatabasePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "image-tool", "agent.db")
+	}
+	return filepath.Join(home, ".cache", "image-tool", "agent.db")
+}
+
+// agentClient reports the agent index to the central server, authenticated by its
+// API token
+type agentClient struct {
+	server string
+	token  string
+	name   string
+	http   *http.Client
+}
+
+// report sends every indexed file under roots, then lets the server remove the records
+// of the files that are gone. Returns the number of files reported and removed.
+func (a *agentClient) report(ctx context.Context, db *gorm.DB, roots []string) (int, int64, error) {
+	var start dto.AgentSyncStartResponse
+	if err := a.post(ctx, "/agent/sync/start", struct{}{}, &start); err != nil {
+		return 0, 0, err
+	}
+
+	reported := 0
+	var batch []domain.ImageFile
+	err := db.FindInBatches(&batch, agentBatchSize, func(tx *gorm.DB, _ int) error {
+		files := make([]domain.ImageFile, 0, len(batch))
+		for _, f := range batch {
+			if !objectstore.IsRemote(f.Path) {
+				files = append(files, f)
+			}
+		}
+		var resp dto.AgentFilesResponse
+		if err := a.post(ctx, "/agent/files", dto.AgentFilesRequest{Agent: a.name, Files: files}, &resp); err != nil {
+			return err
+		}
+		reported += resp.Stored
+		return nil
+	}).Error
+	if err != nil {
+		return reported, 0, err
+	}
+
+	var finish dto.AgentSyncFinishResponse
+	req := dto.AgentSyncFinishRequest{Agent: a.name, Roots: roots, Since: start.Since}
+	if err := a.post(ctx, "/agent/sync/finish", req, &finish); err != nil {
+		return reported, 0, err
+	}
+	return reported, finish.Removed, nil
+}
+
+// post sends a JSON request to the versioned API of the central server
+func (a *agentClient) post(ctx context.Context, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.server+"/api/v1"+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s: %s %s", path, resp.Status, apiErr.Error)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
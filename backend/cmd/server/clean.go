@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/config"
+)
+
+// cleanOptions are the flags of the clean command
+type cleanOptions struct {
+	keep        listFlag
+	priorityDir listFlag
+	trashDir    string
+	dryRun      bool
+}
+
+// runCleanCommand removes duplicates by keep policies: image-dedup clean [flags]
+func runCleanCommand(c command, args []string) int {
+	f := newCLIFlags(c)
+	f.addFilterFlags()
+	var opts cleanOptions
+	f.fs.Var(&opts.keep, "keep", "keep policy choosing the file to keep in each group, applied in order (repeatable, default keep-oldest): keep-oldest, keep-newest, keep-shortest-path, keep-largest-resolution, keep-in-priority-directory or keep-first-scan-dir")
+	f.fs.Var(&opts.priorityDir, "priority-dir", "keep-in-priority-directory: directory whose files are kept, highest priority first (repeatable)")
+	f.fs.StringVar(&opts.trashDir, "trash-dir", "", "move the removed files to this folder (default: the trash folder of the settings)")
+	f.fs.BoolVar(&opts.dryRun, "dry-run", false, "print the files that would be removed and exit")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, _ := f.load()
+	return runClean(cfg, opts)
+}
+
+// runClean plans the removal of all exact duplicates but the file chosen by the keep
+// policies, prints the plan and, unless it is a dry run, moves the planned files to
+// the trash folder, where they can be restored from. Returns the exit code.
+func runClean(cfg *config.AppConfig, opts cleanOptions) int {
+	if len(opts.keep) == 0 {
+		opts.keep = listFlag{string(imaging.KeepOldest)}
+	}
+	priorityDirs := make([]string, len(opts.priorityDir))
+	for i, dir := range opts.priorityDir {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			slog.Error("Invalid -priority-dir", "dir", dir, "error", err)
+			return exitError
+		}
+		priorityDirs[i] = filepath.ToSlash(abs)
+	}
+	rules := make([]imaging.KeepRule, len(opts.keep))
+	for i, policy := range opts.keep {
+		rules[i] = imaging.KeepRule{Policy: imaging.KeepPolicy(policy)}
+		if rules[i].Policy == imaging.KeepInPriorityDir {
+			rules[i].Directories = priorityDirs
+		}
+	}
+	if err := imaging.ValidateKeepRules(rules); err != nil {
+		slog.Error("Invalid -keep", "error", err)
+		return exitError
+	}
+
+	scanOptions, err := buildScanOptions(cfg)
+	if err != nil {
+		slog.Error("Invalid scan options", "error", err)
+		return exitError
+	}
+	db, err := openIndex(cfg)
+	if err != nil {
+		slog.Error("Failed to open the index", "error", err)
+		return exitError
+	}
+	defer closeIndex(db)
+
+	scanManager := imaging.NewScanManager(db, scanOptions)
+	plan, err := imaging.PlanDeletion(db, scanOptions.DuplicateFilter(imaging.MediaAll), scanManager.ResolveKeepRules(rules))
+	if err != nil {
+		slog.Error("Failed to plan the removal", "error", err)
+		return exitError
+	}
+	for _, group := range plan.Groups {
+		fmt.Printf("keep    %s\n", group.Keep)
+		for _, path := range group.Remove {
+			fmt.Printf("remove  %s\n", path)
+		}
+	}
+	if plan.Files == 0 {
+		fmt.Println("Nothing to remove")
+		return 0
+	}
+	fmt.Printf("\n%d files to remove, %s reclaimable\n", plan.Files, imaging.FormatSize(plan.ReclaimableBytes))
+	if opts.dryRun {
+		return 0
+	}
+
+	trashDir := opts.trashDir
+	if trashDir != "" {
+		abs, err := filepath.Abs(trashDir)
+		if err != nil {
+			slog.Error("Invalid -trash-dir", "error", err)
+			return exitError
+		}
+		trashDir = filepath.ToSlash(abs)
+	} else {
+		var settings domain.AppSettings
+		if db.First(&settings, 1).Error == nil {
+			trashDir = settings.TrashDir
+		}
+	}
+	if trashDir == "" {
+		slog.Error("No trash folder: pass -trash-dir, set TRASH_DIR or set one in the settings")
+		return exitError
+	}
+
+	result, err := imaging.ExecuteDeletionPlan(db, plan, trashDir, scanManager.GalleryPathGuard())
+	if err != nil {
+		slog.Error("Removal failed", "error", err)
+		return exitError
+	}
+	for _, failed := range result.FailedFiles {
+		fmt.Printf("failed  %s\n", failed)
+	}
+	fmt.Printf("Moved %d files to %s, %s freed\n", result.Deleted, trashDir, imaging.FormatSize(result.FreedBytes))
+	slog.Info("Duplicates removed", "deleted", result.Deleted, "failed", result.Failed, "batch", result.BatchID)
+	if result.Failed > 0 {
+		return exitProblems
+	}
+	return 0
+}
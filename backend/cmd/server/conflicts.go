@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/infrastructure/config"
+)
+
+// runConflictsCommand lists the names shared by files of different content:
+// image-dedup conflicts [flags] [dir]
+func runConflictsCommand(c command, args []string) int {
+	f := newCLIFlags(c)
+	f.addFilterFlags()
+	output := f.fs.String("output", outputText, "result format, text or json")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, _ := f.load()
+	return runConflicts(cfg, f.fs.Arg(0), *output)
+}
+
+// runConflicts prints the name conflicts of the index, under dir if set, and returns
+// the exit code: exitProblems when there are any, as two folders with conflicts
+// cannot be merged as they are
+func runConflicts(cfg *config.AppConfig, dir, output string) int {
+	if output != outputText && output != outputJSON {
+		slog.Error("Invalid -output (expected text or json)", "output", output)
+		return exitError
+	}
+	scanOptions, err := buildScanOptions(cfg)
+	if err != nil {
+		slog.Error("Invalid scan options", "error", err)
+		return exitError
+	}
+	filter := scanOptions.DuplicateFilter(imaging.MediaAll)
+	if dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			slog.Error("Invalid directory", "dir", dir, "error", err)
+			return exitError
+		}
+		filter = filter.Narrow(abs, nil, 0, 0)
+	}
+	db, err := openIndex(cfg)
+	if err != nil {
+		slog.Error("Failed to open the index", "error", err)
+		return exitError
+	}
+	defer closeIndex(db)
+
+	report, err := imaging.BuildNameConflictReport(db, filter)
+	if err != nil {
+		slog.Error("Failed to find name conflicts", "error", err)
+		return exitError
+	}
+	if output == outputJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeConflicts(os.Stdout, report)
+	}
+	if err != nil {
+		slog.Error("Failed to write the result", "error", err)
+		return exitError
+	}
+	if report.TotalConflicts > 0 {
+		return exitProblems
+	}
+	return 0
+}
+
+// writeConflicts prints name conflicts as plain text, the files of each name
+// numbered by their version of the content
+func writeConflicts(w io.Writer, report *imaging.NameConflictReport) error {
+	for _, conflict := range report.Conflicts {
+		if _, err := fmt.Fprintf(w, "%s (%d versions)\n", conflict.Name, conflict.Versions); err != nil {
+			return err
+		}
+		for _, f := range conflict.Files {
+			fmt.Fprintf(w, "  %d  %10s  %s\n", f.Version, imaging.FormatSize(f.Size), f.Path)
+		}
+		fmt.Fprintln(w)
+	}
+	_, err := fmt.Fprintf(w, "%d names shared by files of different content, %d files\n", report.TotalConflicts, report.TotalFiles)
+	return err
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/infrastructure/config"
+)
+
+// Export formats (-format) other than those of headless reports
+const (
+	exportCSV   = "csv"
+	exportIndex = "index" // index snapshot for import-index
+)
+
+// runExportCommand writes the duplicates of the index, or a snapshot of the whole
+// index, without scanning: image-dedup export [flags] [file]
+func runExportCommand(c command, args []string) int {
+	f := newCLIFlags(c)
+	f.addFilterFlags()
+	f.addThumbnailFlags()
+	format := f.fs.String("format", exportCSV, "csv, json or html duplicate report, or index for an index snapshot")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, _ := f.load()
+
+	file := f.fs.Arg(0)
+	if file == "" {
+		file = "-"
+	}
+	switch *format {
+	case exportIndex:
+		return exportIndexSnapshot(cfg, file)
+	case exportCSV, outputJSON, outputHTML:
+		return exportDuplicates(cfg, *format, file)
+	}
+	slog.Error("Invalid -format (expected csv, json, html or index)", "format", *format)
+	return exitUsage
+}
+
+// exportDuplicates writes the exact duplicate groups of the index in the given format
+// to file, "-" for stdout, and returns the exit code
+func exportDuplicates(cfg *config.AppConfig, format, file string) int {
+	scanOptions, err := buildScanOptions(cfg)
+	if err != nil {
+		slog.Error("Invalid scan options", "error", err)
+		return 1
+	}
+	db, err := openIndex(cfg)
+	if err != nil {
+		slog.Error("Failed to open the index", "error", err)
+		return 1
+	}
+	defer closeIndex(db)
+
+	report, err := imaging.BuildDuplicateReport(db, scanOptions.DuplicateFilter(imaging.MediaAll))
+	if err != nil {
+		slog.Error("Failed to build duplicate report", "error", err)
+		return 1
+	}
+
+	// Written in full before the file is created, so a failed export leaves no partial file
+	var buf bytes.Buffer
+	if format == exportCSV {
+		err = imaging.WriteCSVReport(&buf, report)
+	} else {
+		err = writeReport(&buf, report, format, reportThumbnails(cfg, db))
+	}
+	if err == nil {
+		if file == "-" {
+			_, err = io.Copy(os.Stdout, &buf)
+		} else {
+			err = os.WriteFile(file, buf.Bytes(), 0o644)
+		}
+	}
+	if err != nil {
+		slog.Error("Export failed", "error", err)
+		return 1
+	}
+	slog.Info("Duplicates exported", "groups", report.TotalGroups, "files", report.TotalFiles, "file", file)
+	return 0
+}
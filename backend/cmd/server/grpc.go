@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+
+	"image-toolkit/internal/application/auth"
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/infrastructure/config"
+	"image-toolkit/internal/interfaces/grpcapi"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"gorm.io/gorm"
+)
+
+// startGRPC serves the gRPC API on GRPC_PORT in the background, over TLS when the
+// API server uses HTTPS. Calls are authenticated with the API token, so it must be set.
+func startGRPC(cfg *config.AppConfig, db *gorm.DB, scanManager *imaging.ScanManager, authService *auth.AuthService, certFile, keyFile string) (*grpc.Server, error) {
+	if cfg.APIToken == "" {
+		return nil, fmt.Errorf("the gRPC API requires API_TOKEN")
+	}
+	var opts []grpc.ServerOption
+	if certFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	addr := net.JoinHostPort(cfg.ServerHost, cfg.GRPCPort)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	server := grpcapi.NewServer(grpcapi.NewService(db, scanManager), authService, opts...)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			slog.Error("gRPC server stopped", "error", err)
+		}
+	}()
+	slog.Info("Starting gRPC API server", "addr", addr, "tls", certFile != "")
+	return server, nil
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/application/thumbnail"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/config"
+	"image-toolkit/internal/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+// Exit codes of headless mode, meant for cron jobs and CI pipelines
+const (
+	exitNoDuplicates = 0
+	exitDuplicates   = 1
+	exitError        = 2
+)
+
+// Headless summary formats (-output)
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputHTML = "html"
+)
+
+// reportOptions are the flags of a headless duplicate report
+type reportOptions struct {
+	output     string // text, json or html
+	reportFile string
+	verbose    bool
+	noScan     bool
+}
+
+// addReportFlags registers the flags of a headless duplicate report
+func (f *cliFlags) addReportFlags() *reportOptions {
+	o := &reportOptions{}
+	f.fs.StringVar(&o.reportFile, "report-file", "", "also write the duplicate summary to this file")
+	f.fs.StringVar(&o.reportFile, "o", "", "shorthand for -report-file")
+	f.fs.StringVar(&o.output, "output", outputText, "summary format, text, json or html")
+	f.fs.StringVar(&o.output, "format", outputText, "alias for -output")
+	f.fs.BoolVar(&o.verbose, "v", false, "print scan progress to stderr")
+	return o
+}
+
+// runScanCommand scans the gallery folders: image-dedup scan [flags] [dir...]
+func runScanCommand(c command, args []string) int {
+	f := newCLIFlags(c)
+	f.addScanFlags()
+	verbose := f.fs.Bool("v", false, "print scan progress to stderr")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, _ := f.load()
+	cfg.ScanDirectories = append(cfg.ScanDirectories, f.fs.Args()...)
+	return runScan(cfg, *verbose)
+}
+
+// runReportCommand scans and prints a duplicate report: image-dedup report [flags] [dir...]
+func runReportCommand(c command, args []string) int {
+	f := newCLIFlags(c)
+	f.addScanFlags()
+	f.addThumbnailFlags()
+	report := f.addReportFlags()
+	f.fs.BoolVar(&report.noScan, "no-scan", false, "report the duplicates of the index as it is, without scanning first")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, _ := f.load()
+	cfg.ScanDirectories = append(cfg.ScanDirectories, f.fs.Args()...)
+	return runHeadless(cfg, *report)
+}
+
+// runScan scans all gallery folders into the index, prints a one-line duplicate
+// summary and returns the exit code
+func runScan(cfg *config.AppConfig, verbose bool) int {
+	scanOptions, err := buildScanOptions(cfg)
+	if err != nil {
+		slog.Error("Invalid scan options", "error", err)
+		return exitError
+	}
+	db, err := openIndex(cfg)
+	if err != nil {
+		slog.Error("Failed to open the index", "error", err)
+		return exitError
+	}
+	defer closeIndex(db)
+
+	if err := scanGallery(db, scanOptions, verbose); err != nil {
+		slog.Error("Scan failed", "error", err)
+		return exitError
+	}
+	totals, err := imaging.CountDuplicates(db, scanOptions.DuplicateFilter(imaging.MediaAll))
+	if err != nil {
+		slog.Error("Failed to count duplicates", "error", err)
+		return exitError
+	}
+	fmt.Println(summaryLine(int(totals.Groups), int(totals.Files), totals.ReclaimableBytes))
+	if totals.Groups > 0 {
+		return exitDuplicates
+	}
+	return exitNoDuplicates
+}
+
+// runHeadless scans all gallery folders without starting the API server, unless
+// opts.noScan is set, prints a duplicate summary in the given format to stdout (and to
+// the report file if set) and returns the exit code. An HTML report written to a file
+// is summarised as text on stdout.
+func runHeadless(cfg *config.AppConfig, opts reportOptions) int {
+	switch opts.output {
+	case outputText, outputJSON, outputHTML:
+	default:
+		slog.Error("Invalid -output (expected text, json or html)", "output", opts.output)
+		return exitError
+	}
+
+	scanOptions, err := buildScanOptions(cfg)
+	if err != nil {
+		slog.Error("Invalid scan options", "error", err)
+		return exitError
+	}
+	db, err := openIndex(cfg)
+	if err != nil {
+		slog.Error("Failed to open the index", "error", err)
+		return exitError
+	}
+	defer closeIndex(db)
+
+	if !opts.noScan {
+		if err := scanGallery(db, scanOptions, opts.verbose); err != nil {
+			slog.Error("Scan failed", "error", err)
+			return exitError
+		}
+	}
+
+	report, err := imaging.BuildDuplicateReport(db, scanOptions.DuplicateFilter(imaging.MediaAll))
+	if err != nil {
+		slog.Error("Failed to build duplicate report", "error", err)
+		return exitError
+	}
+
+	stdoutFormat := opts.output
+	if opts.output == outputHTML && opts.reportFile != "" {
+		stdoutFormat = outputText
+	}
+	thumbnails := reportThumbnails(cfg, db)
+	if err := writeReport(os.Stdout, report, stdoutFormat, thumbnails); err != nil {
+		slog.Error("Failed to write report", "error", err)
+		return exitError
+	}
+	if opts.reportFile != "" {
+		if err := writeReportFile(opts.reportFile, report, opts.output, thumbnails); err != nil {
+			slog.Error("Failed to write report", "error", err)
+			return exitError
+		}
+	}
+
+	if report.TotalGroups > 0 {
+		return exitDuplicates
+	}
+	return exitNoDuplicates
+}
+
+// errNoGalleryFolders is returned by scanGallery when there is nothing to scan
+var errNoGalleryFolders = errors.New("no gallery folders configured: pass directories as arguments or set SCAN_DIRECTORIES")
+
+// scanGallery scans all gallery folders into the index until done or interrupted,
+// printing the progress to stderr if verbose
+func scanGallery(db *gorm.DB, scanOptions imaging.ScanOptions, verbose bool) error {
+	var folders int64
+	db.Model(&domain.GalleryFolder{}).Count(&folders)
+	if folders == 0 {
+		return errNoGalleryFolders
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var progress func(string)
+	if verbose {
+		progress = func(msg string) { fmt.Fprintln(os.Stderr, msg) }
+	}
+	return imaging.RunScan(ctx, db, scanOptions, progress)
+}
+
+// openIndex opens the database of the commands that run without the API server and
+// adds the configured scan directories to the gallery
+func openIndex(cfg *config.AppConfig) (*gorm.DB, error) {
+	db, err := database.InitDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.ApplyStartupConfig(db, cfg); err != nil {
+		closeIndex(db)
+		return nil, err
+	}
+	return db, nil
+}
+
+// closeIndex closes the database opened by openIndex
+func closeIndex(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+// reportThumbnails returns the thumbnail source of HTML reports. Thumbnails come from
+// the on-disk cache of the server when it is enabled, so that repeated reports and
+// the web UI share them; the cache is only opened once a thumbnail is needed.
+func reportThumbnails(cfg *config.AppConfig, db *gorm.DB) func(string) (string, error) {
+	var once sync.Once
+	var generate func(string) (string, error)
+	return func(path string) (string, error) {
+		once.Do(func() {
+			if cfg.ThumbnailCacheEnabled {
+				service, err := thumbnail.NewService(&thumbnail.Config{
+					CacheDir: thumbnailCachePath(cfg, db),
+					MaxSize:  cfg.ThumbnailCacheMaxSize,
+					Quality:  cfg.ThumbnailCacheQuality,
+					Enabled:  true,
+					Format:   "webp",
+				})
+				if err == nil {
+					generate = service.GetOrGenerate
+					return
+				}
+				slog.Warn("Thumbnail cache unavailable, thumbnails are not cached", "error", err)
+			}
+			cache := imaging.NewThumbnailCache(cfg.ThumbnailMemoryEntries, int64(cfg.ThumbnailMemoryMB)<<20)
+			generate = func(path string) (string, error) {
+				return imaging.GenerateThumbnail(path, cache)
+			}
+		})
+		return generate(path)
+	}
+}
+
+// writeReport writes the duplicate report in the given format
+func writeReport(w io.Writer, report *imaging.DuplicateReport, output string, thumbnails func(string) (string, error)) error {
+	switch output {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case outputHTML:
+		return imaging.WriteHTMLReport(w, report, thumbnails)
+	}
+	writeSummary(w, report)
+	return nil
+}
+
+// writeSummary prints a plain text duplicate summary
+func writeSummary(w io.Writer, report *imaging.DuplicateReport) {
+	fmt.Fprintln(w, summaryLine(report.TotalGroups, report.TotalFiles, report.WastedBytes))
+	for i, g := range report.Groups {
+		fmt.Fprintf(w, "\n[%d] %s, %d files x %s\n", i+1, g.Hash, len(g.Files), imaging.FormatSize(g.Size))
+		for _, f := range g.Files {
+			fmt.Fprintf(w, "  %s\n", f.Path)
+		}
+	}
+}
+
+// summaryLine is the first line of a plain text duplicate summary
+func summaryLine(groups, files int, reclaimable int64) string {
+	if groups == 0 {
+		return "No duplicates found"
+	}
+	return fmt.Sprintf("Found %d duplicate groups (%d files), %s reclaimable", groups, files, imaging.FormatSize(reclaimable))
+}
+
+func writeReportFile(path string, report *imaging.DuplicateReport, output string, thumbnails func(string) (string, error)) error {
+	var buf bytes.Buffer
+	if err := writeReport(&buf, report, output, thumbnails); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
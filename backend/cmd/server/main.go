@@ -2,100 +2,112 @@ package main
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 
 	"image-toolkit/internal/application/auth"
+	"image-toolkit/internal/application/events"
 	"image-toolkit/internal/application/imaging"
 	"image-toolkit/internal/application/thumbnail"
 	"image-toolkit/internal/domain"
 	"image-toolkit/internal/infrastructure/config"
 	"image-toolkit/internal/infrastructure/database"
 	"image-toolkit/internal/infrastructure/geocoder"
+	"image-toolkit/internal/infrastructure/imagecodec"
+	"image-toolkit/internal/infrastructure/objectstore"
 	"image-toolkit/internal/infrastructure/ocr"
+	"image-toolkit/internal/infrastructure/tlscert"
+	"image-toolkit/internal/interfaces/email"
 	"image-toolkit/internal/interfaces/handler"
 	"image-toolkit/internal/interfaces/middleware"
+	"image-toolkit/internal/interfaces/telegram"
+	"image-toolkit/internal/interfaces/webhook"
 )
 
+// dotenvErr is reported once logging is configured
+var dotenvErr error
+
 // init is invoked before main()
 func init() {
-	if err := godotenv.Load(); err != nil {
-		log.Print("No .env file found")
-	}
+	dotenvErr = godotenv.Load()
 }
 
 func main() {
-	// Load configuration
-	cfg := config.LoadConfig()
+	os.Exit(run(os.Args[1:]))
+}
+
+// runServeCommand starts the API server: image-dedup [serve] [flags]
+func runServeCommand(c command, args []string) int {
+	f := newCLIFlags(c)
+	f.addServerFlags()
+	f.addScanFlags()
+	f.addThumbnailFlags()
+	migrateTo := f.fs.Int("migrate-to", 0, "migrate the database schema up or down to this version and exit, e.g. to roll back before a downgrade")
+	// Headless mode of earlier releases, now the report command
+	noServer := f.fs.Bool("no-server", false, "same as the report command: scan, print a duplicate summary and exit")
+	report := f.addReportFlags()
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, logger := f.load()
 
-	fmt.Printf("Image Dedup - API Server\n")
-	fmt.Printf("========================\n\n")
+	if *migrateTo != 0 {
+		return runMigrateTo(cfg, *migrateTo)
+	}
+	if *noServer {
+		cfg.ScanDirectories = append(cfg.ScanDirectories, f.fs.Args()...)
+		return runHeadless(cfg, *report)
+	}
+	serve(cfg, logger)
+	return 0
+}
+
+// serve runs the API server with its background jobs until it fails
+func serve(cfg *config.AppConfig, logger *slog.Logger) {
+	slog.Info("Image Dedup API server starting")
 
 	// Initialize database
-	fmt.Println("Connecting to PostgreSQL database...")
+	slog.Info("Connecting to database")
 	db, err := database.InitDatabase(cfg)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		fatal("Failed to initialize database", "error", err)
+	}
+	if err := database.ApplyStartupConfig(db, cfg); err != nil {
+		fatal("Failed to apply configuration", "error", err)
 	}
 
 	sqlDB, _ := db.DB()
 	defer sqlDB.Close()
 
-	fmt.Println("Database connected successfully!")
+	slog.Info("Database connected")
 
 	// Initialize offline geocoder
-	fmt.Println("Initializing offline geocoder...")
+	slog.Info("Initializing offline geocoder")
 	geoc := geocoder.NewGeocoder()
 	if geoc != nil {
-		fmt.Println("Geocoder initialized successfully!")
+		slog.Info("Geocoder initialized")
 	} else {
-		fmt.Println("Geocoder unavailable, geolocation will be disabled.")
+		slog.Warn("Geocoder unavailable, geolocation will be disabled")
 	}
 
 	// Initialize OCR classifier client and health check
-	var ocrCheckInterval int
 	if cfg.OCREnabled {
-		fmt.Println("Initializing OCR classifier client...")
-		ocrCheckInterval = cfg.OCRCheckInterval
-		fmt.Printf("OCR classifier enabled: host=%s, port=%s, check interval=%ds\n", cfg.OCRHost, cfg.OCRPort, ocrCheckInterval)
+		slog.Info("OCR classifier enabled", "host", cfg.OCRHost, "port", cfg.OCRPort, "check_interval_s", cfg.OCRCheckInterval)
 	} else {
-		fmt.Println("OCR classifier integration disabled")
-		ocrCheckInterval = 0
-	}
-
-	// Create scan manager (reads gallery folders from DB dynamically)
-	scanManager := imaging.NewScanManager(db, cfg.ScanWorkers)
-
-	// Create metadata manager (background EXIF extraction)
-	metadataManager := imaging.NewMetadataManager(db, geoc, cfg.MetadataWorkers, cfg.MetadataIntervalMin)
-	defer metadataManager.Stop()
-
-	// Create OCR manager (background classification)
-	var ocrManager *imaging.OcrManager
-	if cfg.OCREnabled {
-		ocrClient := ocr.NewClient(cfg.OCRHost, cfg.OCRPort)
-		ocrManager = imaging.NewOcrManager(db, ocrClient, cfg.OCRConcurrentRequests)
-		fmt.Printf("OCR manager initialized: max concurrent requests=%d\n", cfg.OCRConcurrentRequests)
+		slog.Info("OCR classifier integration disabled")
 	}
 
 	// Initialize thumbnail cache service
 	var thumbnailService *thumbnail.Service
 	// Initialize thumbnail cache service
-	fmt.Println("Initializing thumbnail cache service...")
-
-	// Load thumbnail cache path from database if available
-	cachePath := cfg.ThumbnailCachePath
-	if cfg.ThumbnailCachePath == "" {
-		// Check database for saved cache path
-		var appSettings domain.AppSettings
-		if result := db.First(&appSettings, 1); result.Error == nil && appSettings.ThumbnailCachePath != "" {
-			cachePath = appSettings.ThumbnailCachePath
-			fmt.Printf("Using thumbnail cache path from database: %s\n", cachePath)
-		}
-	}
+	slog.Info("Initializing thumbnail cache service")
+
+	cachePath := thumbnailCachePath(cfg, db)
 
 	tcConfig := &thumbnail.Config{
 		CacheDir:      cachePath,
@@ -107,45 +119,130 @@ func main() {
 	}
 	thumbnailService, err = thumbnail.NewService(tcConfig)
 	if err != nil {
-		log.Printf("Failed to initialize thumbnail cache: %v", err)
+		slog.Error("Failed to initialize thumbnail cache", "error", err)
 		thumbnailService = nil
 	} else {
-		if cfg.ThumbnailCacheEnabled {
-			fmt.Println("Thumbnail cache service initialized and enabled")
-		} else {
-			fmt.Println("Thumbnail cache service initialized (disabled)")
-		}
+		slog.Info("Thumbnail cache service initialized", "enabled", cfg.ThumbnailCacheEnabled)
 	}
 
 	// Start thumbnail service
 	if thumbnailService != nil {
 		if err := thumbnailService.Start(); err != nil {
-			log.Printf("Failed to start thumbnail service: %v", err)
+			slog.Error("Failed to start thumbnail service", "error", err)
 		}
 	}
 
+	// Create scan manager (reads gallery folders from DB dynamically)
+	scanOptions, err := buildScanOptions(cfg)
+	if err != nil {
+		fatal("Invalid scan options", "error", err)
+	}
+	if thumbnailService != nil {
+		// Scans cache thumbnails of the images they decode (THUMBNAIL_CACHE_PRELOAD_ON_SCAN)
+		scanOptions.Thumbnails = thumbnailService
+	}
+	scanManager := imaging.NewScanManager(db, scanOptions)
+	// Event bus feeding WebSocket clients (/api/ws)
+	scanManager.Events = events.NewBus()
+
+	// Webhooks of finished scans and batch deletes
+	if len(cfg.WebhookURLs) > 0 {
+		notifier, err := webhook.NewNotifier(cfg.WebhookURLs, cfg.WebhookSecret, db, scanManager)
+		if err != nil {
+			fatal("Invalid webhook configuration", "error", err)
+		}
+		defer notifier.Start()()
+		slog.Info("Webhooks enabled", "urls", len(cfg.WebhookURLs), "signed", cfg.WebhookSecret != "")
+	}
+
+	// Email reports of scheduled scans
+	if len(cfg.ReportEmailTo) > 0 {
+		reporter, err := email.NewReporter(email.Config{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			To:       cfg.ReportEmailTo,
+		}, scanManager)
+		if err != nil {
+			fatal("Invalid email report configuration", "error", err)
+		}
+		defer reporter.Start()()
+		slog.Info("Scan reports enabled", "smtp_host", cfg.SMTPHost, "recipients", len(cfg.ReportEmailTo))
+	}
+
+	// Telegram bot: scan summaries and deletion plans to approve
+	if cfg.TelegramBotToken != "" {
+		bot, err := telegram.NewBot(cfg.TelegramBotToken, cfg.TelegramChatIDs, cfg.TelegramKeepPolicies, db, scanManager)
+		if err != nil {
+			fatal("Invalid Telegram bot configuration", "error", err)
+		}
+		defer bot.Start()()
+		slog.Info("Telegram bot enabled", "chats", len(cfg.TelegramChatIDs))
+	}
+
+	// Create metadata manager (background EXIF extraction)
+	metadataManager := imaging.NewMetadataManager(db, geoc, cfg.MetadataWorkers, cfg.MetadataIntervalMin)
+	defer metadataManager.Stop()
+
+	// Create OCR manager (background classification)
+	var ocrManager *imaging.OcrManager
+	if cfg.OCREnabled {
+		ocrClient := ocr.NewClient(cfg.OCRHost, cfg.OCRPort)
+		ocrManager = imaging.NewOcrManager(db, ocrClient, cfg.OCRConcurrentRequests)
+		slog.Info("OCR manager initialized", "max_concurrent_requests", cfg.OCRConcurrentRequests)
+	}
+
 	// Create background sync manager
-	backgroundSync := imaging.NewBackgroundSyncManager(db, thumbnailService, cfg.BackgroundSyncIntervalMin)
+	backgroundSync := imaging.NewBackgroundSyncManager(db, thumbnailService, cfg.BackgroundSyncIntervalMin, scanOptions)
 	if cfg.BackgroundSyncEnabled {
 		backgroundSync.Start()
 		defer backgroundSync.Stop()
-		fmt.Printf("Background sync enabled (interval: %d min)\n", cfg.BackgroundSyncIntervalMin)
+		slog.Info("Background sync enabled", "interval_min", cfg.BackgroundSyncIntervalMin)
 	} else {
-		fmt.Println("Background sync disabled")
+		slog.Info("Background sync disabled")
 	}
 
 	// Wire scan complete callback to trigger metadata extraction and OCR classification
 	scanManager.OnScanComplete = func() {
 		if err := metadataManager.StartExtraction(); err != nil {
-			log.Printf("Metadata extraction not started: %v", err)
+			slog.Info("Metadata extraction not started", "reason", err)
 		}
 		if cfg.OCREnabled && ocrManager != nil {
 			if err := ocrManager.StartClassification(false); err != nil {
-				log.Printf("OCR classification not started: %v", err)
+				slog.Info("OCR classification not started", "reason", err)
 			}
 		}
 	}
 
+	// Create scan scheduler (recurring full scans)
+	scanScheduler := imaging.NewScanScheduler(db, scanManager)
+	if err := scanScheduler.Start(cfg.ScanSchedule); err != nil {
+		slog.Error("Failed to start scan scheduler", "error", err)
+	} else {
+		defer scanScheduler.Stop()
+		if spec, _ := scanScheduler.Schedule(); spec != "" {
+			slog.Info("Scan schedule enabled", "schedule", spec)
+		} else {
+			slog.Info("Scan schedule disabled")
+		}
+	}
+
+	// Create filesystem watcher (incremental updates between scans)
+	if cfg.WatchEnabled {
+		folderWatcher := imaging.NewFolderWatcher(db, thumbnailService, scanOptions)
+		folderWatcher.OnChange = scanManager.OnScanComplete
+		if err := folderWatcher.Start(); err != nil {
+			slog.Error("Failed to start folder watcher", "error", err)
+		} else {
+			defer folderWatcher.Stop()
+			slog.Info("Filesystem watch enabled")
+		}
+	} else {
+		slog.Info("Filesystem watch disabled")
+	}
+
 	// Initialize authentication components
 	sessionConfig := &auth.SessionConfig{
 		IdleTimeout:     time.Duration(cfg.SessionIdleHours) * time.Hour,
@@ -159,7 +256,9 @@ func main() {
 	loginLimiter := auth.NewLoginRateLimiter(10, 15*time.Minute, 30*time.Minute)
 	authService := auth.NewAuthService(db, bootstrap, sessionRepo, loginLimiter)
 	userService := auth.NewUserService(db, sessionRepo)
+	authService.SetAPIToken(cfg.APIToken, cfg.APITokenUser)
 	authMiddleware := middleware.NewAuthMiddleware(sessionRepo, authService)
+	authMiddleware.BasicAuthEnabled = cfg.BasicAuthEnabled
 	csrfProtection := middleware.NewCSRFProtection()
 	authHandlers := handler.NewAuthHandlers(authService, bootstrap, userService, sessionRepo, db)
 
@@ -168,30 +267,205 @@ func main() {
 	sessionCleanup.Start()
 	defer sessionCleanup.Stop()
 
-	fmt.Println("Authentication system initialized!")
+	// Start purging the records of removed files once their retention is over
+	indexPurge := imaging.NewIndexPurgeJob(db, time.Duration(cfg.IndexRetentionDays)*24*time.Hour, 1*time.Hour)
+	indexPurge.Start()
+	defer indexPurge.Stop()
+
+	slog.Info("Authentication system initialized", "api_token", cfg.APIToken != "", "basic_auth", cfg.BasicAuthEnabled)
 
 	// Create LLM OCR service
 	llmOcrService := imaging.NewLlmOcrService(db)
-	fmt.Println("LLM OCR service initialized")
+	slog.Info("LLM OCR service initialized")
 
 	// Start web server
-	server := handler.NewServer(db, scanManager, metadataManager, ocrManager, llmOcrService, thumbnailService, cfg)
-	router := server.SetupRouter(authMiddleware, csrfProtection, authHandlers)
+	server := handler.NewServer(db, scanManager, scanScheduler, metadataManager, ocrManager, llmOcrService, thumbnailService, cfg)
+	router := server.SetupRouter(logger, authMiddleware, csrfProtection, authHandlers)
 
 	// Start OCR health check if enabled
 	server.StartOCRHealthCheck()
 	defer server.StopOCRHealthCheck()
 
-	fmt.Printf("\nStarting API server on http://%s:%s\n", cfg.ServerHost, cfg.ServerPort)
-	fmt.Printf("Scan workers: %d\n", cfg.ScanWorkers)
-	fmt.Printf("Metadata workers: %d, interval: %d min\n", cfg.MetadataWorkers, cfg.MetadataIntervalMin)
-	fmt.Printf("CORS allowed origins: %s\n", strings.Join(cfg.CORSOrigins, ", "))
-	fmt.Printf("Thumbnail cache: enabled=%v, path=%s\n", cfg.ThumbnailCacheEnabled, cachePath)
-	fmt.Printf("Background sync: enabled=%v, interval=%d min\n", cfg.BackgroundSyncEnabled, cfg.BackgroundSyncIntervalMin)
-	fmt.Println("Configure gallery folders via the web UI Settings tab.")
-	fmt.Println("Press Ctrl+C to stop the server")
+	certFile, keyFile, err := tlsFiles(cfg)
+	if err != nil {
+		fatal("Failed to prepare TLS certificate", "error", err)
+	}
+	scheme := "http"
+	if certFile != "" {
+		scheme = "https"
+	}
 
-	if err := router.Run(fmt.Sprintf("%s:%s", cfg.ServerHost, cfg.ServerPort)); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	slog.Info("Scan configuration",
+		"workers", cfg.ScanWorkers, "content_hash", scanOptions.ContentHash, "two_stage", cfg.TwoStageHashEnabled, "size_prefilter", cfg.SizePrefilter,
+		"perceptual_hash", cfg.PerceptualHashEnabled, "perceptual_algo", scanOptions.HashAlgorithm, "perceptual_rotations", cfg.PerceptualRotations, "similarity_threshold", cfg.SimilarityThreshold, "burst_window", cfg.BurstWindowSeconds, "pixel_hash", cfg.PixelHashEnabled,
+		"videos", cfg.VideoScanEnabled, "min_size", cfg.MinFileSize, "max_size", cfg.MaxFileSize, "max_depth", cfg.MaxScanDepth, "follow_symlinks", cfg.FollowSymlinks, "include_hidden", cfg.IncludeHidden, "collapse_hardlinks", cfg.CollapseHardlinks, "case_insensitive_paths", cfg.CaseInsensitivePaths,
+		"throttle", cfg.ScanThrottle, "idle_priority", cfg.ScanIdlePriority, "memory_limit", cfg.ScanMemoryLimit)
+	if cfg.VideoScanEnabled && !imagecodec.FFmpegAvailable() {
+		slog.Warn("Video scanning enabled but ffmpeg was not found, videos will have no thumbnails", "ffmpeg", cfg.FFmpegPath)
+	}
+	slog.Info("Background job configuration",
+		"metadata_workers", cfg.MetadataWorkers, "metadata_interval_min", cfg.MetadataIntervalMin,
+		"thumbnail_cache", cfg.ThumbnailCacheEnabled, "thumbnail_cache_path", cachePath, "thumbnail_preload_on_scan", cfg.ThumbnailCachePreloadOnScan, "thumbnail_memory_entries", cfg.ThumbnailMemoryEntries, "thumbnail_memory_mb", cfg.ThumbnailMemoryMB,
+		"background_sync", cfg.BackgroundSyncEnabled, "background_sync_interval_min", cfg.BackgroundSyncIntervalMin,
+		"index_retention_days", cfg.IndexRetentionDays)
+	slog.Info("Starting API server", "url", fmt.Sprintf("%s://%s:%s", scheme, cfg.ServerHost, cfg.ServerPort), "cors_origins", strings.Join(cfg.CORSOrigins, ","))
+	slog.Info("Configure gallery folders via the web UI Settings tab. Press Ctrl+C to stop the server")
+
+	if cfg.GRPCPort != "" {
+		grpcServer, err := startGRPC(cfg, db, scanManager, authService, certFile, keyFile)
+		if err != nil {
+			fatal("Failed to start gRPC server", "error", err)
+		}
+		defer grpcServer.Stop()
+	}
+
+	addr := fmt.Sprintf("%s:%s", cfg.ServerHost, cfg.ServerPort)
+	if certFile != "" {
+		err = router.RunTLS(addr, certFile, keyFile)
+	} else {
+		err = router.Run(addr)
+	}
+	if err != nil {
+		fatal("Failed to start server", "error", err)
+	}
+}
+
+// listFlag collects the values of a repeatable flag
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// fatal logs an error and exits with status 1
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// tlsFiles returns the certificate and key to serve HTTPS with, generating a
+// self-signed pair if requested. Empty paths mean plain HTTP.
+func tlsFiles(cfg *config.AppConfig) (string, string, error) {
+	certFile, keyFile := cfg.TLSCertFile, cfg.TLSKeyFile
+	if !cfg.TLSSelfSigned {
+		if (certFile == "") != (keyFile == "") {
+			return "", "", fmt.Errorf("both TLS_CERT_FILE and TLS_KEY_FILE must be set")
+		}
+		return certFile, keyFile, nil
+	}
+
+	if certFile == "" || keyFile == "" {
+		certFile, keyFile = tlscert.DefaultPaths()
+	}
+	created, err := tlscert.EnsureSelfSigned(certFile, keyFile, tlscert.LocalHosts(cfg.ServerHost))
+	if err != nil {
+		return "", "", err
+	}
+	if created {
+		slog.Info("Generated self-signed certificate", "path", certFile)
+	} else {
+		slog.Info("Using self-signed certificate", "path", certFile)
+	}
+	return certFile, keyFile, nil
+}
+
+// thumbnailCachePath returns the thumbnail cache directory: THUMBNAIL_CACHE_PATH or
+// -thumb-cache-dir, otherwise the path saved in the settings. Empty selects the default.
+func thumbnailCachePath(cfg *config.AppConfig, db *gorm.DB) string {
+	if cfg.ThumbnailCachePath != "" {
+		return cfg.ThumbnailCachePath
+	}
+	var appSettings domain.AppSettings
+	if result := db.First(&appSettings, 1); result.Error == nil && appSettings.ThumbnailCachePath != "" {
+		slog.Info("Using thumbnail cache path from database", "path", appSettings.ThumbnailCachePath)
+		return appSettings.ThumbnailCachePath
+	}
+	return ""
+}
+
+// buildScanOptions validates the hashing configuration and converts it to scan options
+func buildScanOptions(cfg *config.AppConfig) (imaging.ScanOptions, error) {
+	contentHashAlgo, err := imaging.ParseContentHashAlgorithm(cfg.ContentHashAlgo)
+	if err != nil {
+		return imaging.ScanOptions{}, fmt.Errorf("invalid CONTENT_HASH_ALGO: %w", err)
+	}
+	hashAlgo, err := imaging.ParsePerceptualHashAlgorithm(cfg.PerceptualHashAlgo)
+	if err != nil {
+		return imaging.ScanOptions{}, fmt.Errorf("invalid PERCEPTUAL_HASH_ALGO: %w", err)
+	}
+	if cfg.SimilarityThreshold < 0 || cfg.SimilarityThreshold > imaging.MaxSimilarityThreshold {
+		return imaging.ScanOptions{}, fmt.Errorf("invalid SIMILARITY_THRESHOLD: %d (expected 0-%d)", cfg.SimilarityThreshold, imaging.MaxSimilarityThreshold)
+	}
+	if _, ok := imaging.BurstWindow(cfg.BurstWindowSeconds); !ok {
+		return imaging.ScanOptions{}, fmt.Errorf("invalid BURST_WINDOW_SECONDS: %d (expected 1-%d)", cfg.BurstWindowSeconds, int(imaging.MaxBurstWindow.Seconds()))
+	}
+	var minSize, maxSize int64
+	if cfg.MinFileSize != "" {
+		if minSize, err = imaging.ParseSize(cfg.MinFileSize); err != nil {
+			return imaging.ScanOptions{}, fmt.Errorf("invalid MIN_FILE_SIZE: %w", err)
+		}
+	}
+	if cfg.MaxFileSize != "" {
+		if maxSize, err = imaging.ParseSize(cfg.MaxFileSize); err != nil {
+			return imaging.ScanOptions{}, fmt.Errorf("invalid MAX_FILE_SIZE: %w", err)
+		}
+	}
+	if cfg.MaxScanDepth < 0 {
+		return imaging.ScanOptions{}, fmt.Errorf("invalid MAX_SCAN_DEPTH: %d (expected 0 for unlimited or a positive depth)", cfg.MaxScanDepth)
+	}
+	if maxSize > 0 && minSize > maxSize {
+		return imaging.ScanOptions{}, fmt.Errorf("MIN_FILE_SIZE (%s) is larger than MAX_FILE_SIZE (%s)", cfg.MinFileSize, cfg.MaxFileSize)
+	}
+	var throttle int64
+	if cfg.ScanThrottle != "" {
+		if throttle, err = imaging.ParseRate(cfg.ScanThrottle); err != nil {
+			return imaging.ScanOptions{}, fmt.Errorf("invalid SCAN_THROTTLE: %w", err)
+		}
+	}
+	var memoryLimit int64
+	if cfg.ScanMemoryLimit != "" {
+		if memoryLimit, err = imaging.ParseSize(cfg.ScanMemoryLimit); err != nil {
+			return imaging.ScanOptions{}, fmt.Errorf("invalid SCAN_MEMORY_LIMIT: %w", err)
+		}
 	}
+	return imaging.ScanOptions{
+		Workers:              cfg.ScanWorkers,
+		PerceptualHash:       cfg.PerceptualHashEnabled,
+		PixelHash:            cfg.PixelHashEnabled,
+		Rotations:            cfg.PerceptualRotations,
+		HashAlgorithm:        hashAlgo,
+		ContentHash:          contentHashAlgo,
+		TwoStageHash:         cfg.TwoStageHashEnabled,
+		SizePrefilter:        cfg.SizePrefilter,
+		ExcludePatterns:      cfg.ExcludePatterns,
+		Videos:               cfg.VideoScanEnabled,
+		MinSize:              minSize,
+		MaxSize:              maxSize,
+		MaxDepth:             cfg.MaxScanDepth,
+		FollowSymlinks:       cfg.FollowSymlinks,
+		IncludeHidden:        cfg.IncludeHidden,
+		CollapseHardlinks:    cfg.CollapseHardlinks,
+		CaseInsensitivePaths: cfg.CaseInsensitivePaths,
+		Throttle:             imaging.NewThrottle(throttle),
+		MemoryLimit:          imaging.NewMemoryLimit(memoryLimit),
+		ObjectStores: objectstore.Config{
+			S3: objectstore.S3Config{
+				Endpoint:        cfg.S3Endpoint,
+				Region:          cfg.S3Region,
+				AccessKeyID:     cfg.S3AccessKeyID,
+				SecretAccessKey: cfg.S3SecretAccessKey,
+				SessionToken:    cfg.S3SessionToken,
+			},
+			WebDAV: objectstore.WebDAVConfig{Username: cfg.WebDAVUsername, Password: cfg.WebDAVPassword},
+			SFTP: objectstore.SFTPConfig{
+				User:           cfg.SFTPUser,
+				Password:       cfg.SFTPPassword,
+				KeyFile:        cfg.SFTPKeyFile,
+				KnownHostsFile: cfg.SFTPKnownHostsFile,
+			},
+		},
+	}, nil
 }
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log/slog"
+
+	"image-toolkit/internal/infrastructure/config"
+	"image-toolkit/internal/infrastructure/database"
+)
+
+// runMigrateTo migrates the database schema to the given version for -migrate-to and
+// returns the exit code. Rolling back to the version an older release knows lets it
+// open the database again.
+func runMigrateTo(cfg *config.AppConfig, version int) int {
+	db, err := database.Open(cfg)
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		return 1
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	from, err := database.SchemaVersion(db)
+	if err != nil {
+		slog.Error("Failed to read schema version", "error", err)
+		return 1
+	}
+	if err := database.MigrateTo(db, version); err != nil {
+		slog.Error("Schema migration failed", "from", from, "to", version, "error", err)
+		return 1
+	}
+	slog.Info("Database schema migrated", "from", from, "to", version, "latest", database.LatestSchemaVersion())
+	return 0
+}
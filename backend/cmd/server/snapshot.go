@@ -0,0 +1,93 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/infrastructure/config"
+	"image-toolkit/internal/infrastructure/database"
+)
+
+// runImportIndexCommand reads an index snapshot: image-dedup import-index [flags] <file>
+func runImportIndexCommand(c command, args []string) int {
+	f := newCLIFlags(c)
+	var remap listFlag
+	f.fs.Var(&remap, "remap", "replace a path prefix of the snapshot, e.g. /mnt/nas=/volume1 (repeatable)")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, _ := f.load()
+	return importIndexSnapshot(cfg, f.fs.Arg(0), remap)
+}
+
+// exportIndexSnapshot writes an index snapshot for export -format index and returns
+// the exit code. file "-" is stdout.
+func exportIndexSnapshot(cfg *config.AppConfig, file string) int {
+	db, err := database.InitDatabase(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize database", "error", err)
+		return 1
+	}
+	defer closeIndex(db)
+
+	out := os.Stdout
+	if file != "-" {
+		f, err := os.Create(file)
+		if err != nil {
+			slog.Error("Failed to create snapshot file", "error", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	files, err := imaging.ExportIndex(db, out)
+	if err == nil && file != "-" {
+		err = out.Sync()
+	}
+	if err != nil {
+		slog.Error("Index export failed", "error", err)
+		return 1
+	}
+	slog.Info("Index exported", "files", files, "file", file)
+	return 0
+}
+
+// importIndexSnapshot runs the import-index command and returns the exit code.
+// file "-" is stdin.
+func importIndexSnapshot(cfg *config.AppConfig, file string, remap []string) int {
+	if file == "" {
+		slog.Error("Missing snapshot file: import-index [flags] <file>, - for standard input")
+		return 1
+	}
+	pathRemap, err := imaging.PrefixRemap(remap)
+	if err != nil {
+		slog.Error("Invalid -remap", "error", err)
+		return 1
+	}
+	db, err := database.InitDatabase(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize database", "error", err)
+		return 1
+	}
+	defer closeIndex(db)
+
+	in := io.Reader(os.Stdin)
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			slog.Error("Failed to open snapshot file", "error", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+	files, err := imaging.ImportIndex(db, in, pathRemap)
+	if err != nil {
+		slog.Error("Index import failed", "error", err)
+		return 1
+	}
+	slog.Info("Index imported", "files", files, "file", file)
+	return 0
+}
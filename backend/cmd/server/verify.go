@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/infrastructure/config"
+	"image-toolkit/internal/infrastructure/database"
+)
+
+// exitProblems is the exit code of verify, conflicts and clean when something needs a
+// look, as exitDuplicates is for reports
+const exitProblems = 1
+
+// runVerifyCommand checks the index against the disk: image-dedup verify [flags]
+func runVerifyCommand(c command, args []string) int {
+	f := newCLIFlags(c)
+	f.addThrottleFlags()
+	rehash := f.fs.Bool("rehash", false, "also hash the content of unchanged files again and compare it with the index")
+	output := f.fs.String("output", outputText, "result format, text or json")
+	verbose := f.fs.Bool("v", false, "print progress to stderr")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	cfg, _ := f.load()
+	return runVerify(cfg, *rehash, *output, *verbose)
+}
+
+// runVerify checks the indexed files of this machine against the disk, prints the
+// files that do not match and returns the exit code
+func runVerify(cfg *config.AppConfig, rehash bool, output string, verbose bool) int {
+	if output != outputText && output != outputJSON {
+		slog.Error("Invalid -output (expected text or json)", "output", output)
+		return exitError
+	}
+	var throttle int64
+	if cfg.ScanThrottle != "" {
+		var err error
+		if throttle, err = imaging.ParseRate(cfg.ScanThrottle); err != nil {
+			slog.Error("Invalid SCAN_THROTTLE", "error", err)
+			return exitError
+		}
+	}
+	db, err := database.InitDatabase(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize database", "error", err)
+		return exitError
+	}
+	defer closeIndex(db)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var progress func(string)
+	if verbose {
+		progress = func(msg string) { fmt.Fprintln(os.Stderr, msg) }
+	}
+	result, err := imaging.VerifyIndex(ctx, db, rehash, imaging.NewThrottle(throttle), progress)
+	if err != nil {
+		slog.Error("Verification failed", "error", err)
+		return exitError
+	}
+
+	if output == outputJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(result)
+	} else {
+		err = writeVerification(os.Stdout, result)
+	}
+	if err != nil {
+		slog.Error("Failed to write the result", "error", err)
+		return exitError
+	}
+	if len(result.Problems) > 0 {
+		return exitProblems
+	}
+	return 0
+}
+
+// writeVerification prints the result of a verification as plain text
+func writeVerification(w io.Writer, result *imaging.IndexVerification) error {
+	for _, p := range result.Problems {
+		line := fmt.Sprintf("%-10s %s", p.Problem, p.Path)
+		if p.Detail != "" {
+			line += " (" + p.Detail + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if len(result.Problems) > 0 {
+		fmt.Fprintln(w)
+	}
+	_, err := fmt.Fprintf(w, "Checked %d files (%d hashed): %d problems\n", result.Checked, result.Hashed, len(result.Problems))
+	return err
+}
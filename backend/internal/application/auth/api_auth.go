@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"image-toolkit/internal/domain"
+)
+
+// SetAPIToken enables bearer token authentication for non-browser clients.
+// Requests carrying the token act as the user with login userLogin, or as the
+// first active administrator if userLogin is empty. An empty token disables it.
+func (s *AuthService) SetAPIToken(token, userLogin string) {
+	s.apiToken = token
+	s.apiTokenUser = userLogin
+}
+
+// APITokenEnabled reports whether bearer token authentication is configured
+func (s *AuthService) APITokenEnabled() bool {
+	return s.apiToken != ""
+}
+
+// AuthenticateToken resolves the user of a bearer token
+func (s *AuthService) AuthenticateToken(token, ipAddress string) (*domain.User, error) {
+	if !s.loginLimiter.Allow(ipAddress) {
+		return nil, domain.ErrRateLimited
+	}
+	if s.apiToken == "" || !tokensEqual(token, s.apiToken) {
+		s.loginLimiter.RecordFailure(ipAddress)
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	var user domain.User
+	query := s.db.Where("is_active = ?", true)
+	if s.apiTokenUser != "" {
+		query = query.Where("login = ?", s.apiTokenUser)
+	} else {
+		query = query.Where("role = ?", domain.RoleAdmin).Order("id")
+	}
+	if err := query.First(&user).Error; err != nil {
+		return nil, domain.ErrUserDeactivated
+	}
+	return &user, nil
+}
+
+// AuthenticateBasic verifies HTTP basic auth credentials without creating a session
+func (s *AuthService) AuthenticateBasic(login, password, ipAddress string) (*domain.User, error) {
+	if !s.loginLimiter.Allow(ipAddress) {
+		return nil, domain.ErrRateLimited
+	}
+	return s.checkCredentials(login, password, ipAddress)
+}
+
+// tokensEqual compares tokens in constant time, independent of their lengths
+func tokensEqual(a, b string) bool {
+	ha := sha256.Sum256([]byte(a))
+	hb := sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestAuthService(t *testing.T) *AuthService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.User{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	hash, err := HashPassword("secret-password")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	for _, u := range []domain.User{
+		{Login: "viewer", PasswordHash: hash, Role: domain.RoleUser, IsActive: true},
+		{Login: "root", PasswordHash: hash, Role: domain.RoleAdmin, IsActive: true},
+	} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	limiter := NewLoginRateLimiter(3, time.Minute, time.Minute)
+	return NewAuthService(db, nil, nil, limiter)
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	s := newTestAuthService(t)
+
+	if _, err := s.AuthenticateToken("anything", "10.0.0.1"); err == nil {
+		t.Fatal("token auth must fail while no token is configured")
+	}
+
+	s.SetAPIToken("t0ken", "")
+	user, err := s.AuthenticateToken("t0ken", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("AuthenticateToken: %v", err)
+	}
+	if user.Login != "root" {
+		t.Errorf("token user = %s, want first administrator", user.Login)
+	}
+
+	s.SetAPIToken("t0ken", "viewer")
+	if user, err := s.AuthenticateToken("t0ken", "10.0.0.1"); err != nil || user.Login != "viewer" {
+		t.Errorf("token user = %v, %v; want viewer", user, err)
+	}
+
+	if _, err := s.AuthenticateToken("wrong", "10.0.0.2"); err != domain.ErrInvalidCredentials {
+		t.Errorf("wrong token: err = %v", err)
+	}
+}
+
+func TestAuthenticateBasicRateLimited(t *testing.T) {
+	s := newTestAuthService(t)
+
+	if user, err := s.AuthenticateBasic("viewer", "secret-password", "10.0.0.1"); err != nil || user.Login != "viewer" {
+		t.Fatalf("AuthenticateBasic = %v, %v", user, err)
+	}
+	for i := 0; i < 3; i++ {
+		s.AuthenticateBasic("viewer", "wrong", "10.0.0.3")
+	}
+	if _, err := s.AuthenticateBasic("viewer", "secret-password", "10.0.0.3"); err != domain.ErrRateLimited {
+		t.Errorf("err = %v, want ErrRateLimited", err)
+	}
+}
@@ -14,6 +14,10 @@ type AuthService struct {
 	bootstrap    *BootstrapService
 	sessionRepo  *SessionRepository
 	loginLimiter *LoginRateLimiter
+
+	// Bearer token for non-browser clients, see SetAPIToken
+	apiToken     string
+	apiTokenUser string
 }
 
 // NewAuthService creates a new auth service
@@ -63,6 +67,32 @@ func (s *AuthService) Login(login, password, ipAddress, userAgent string) (*Logi
 	}
 
 	// Normal user authentication
+	user, err = s.checkCredentials(login, password, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create session
+	token, err := s.sessionRepo.CreateSession(user.ID, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update last login time
+	now := time.Now()
+	s.db.Model(&user).Update("last_login_at", now)
+
+	return &LoginResult{
+		User:        user,
+		Token:       token,
+		IsBootstrap: false,
+	}, nil
+}
+
+// checkCredentials verifies login and password of an active user, recording the
+// attempt in the rate limiter
+func (s *AuthService) checkCredentials(login, password, ipAddress string) (*domain.User, error) {
+	var user domain.User
 	if err := s.db.Where("login = ?", login).First(&user).Error; err != nil {
 		s.loginLimiter.RecordFailure(ipAddress)
 		return nil, domain.ErrInvalidCredentials
@@ -82,22 +112,7 @@ func (s *AuthService) Login(login, password, ipAddress, userAgent string) (*Logi
 
 	// Rate limit success - reset counter
 	s.loginLimiter.RecordSuccess(ipAddress)
-
-	// Create session
-	token, err := s.sessionRepo.CreateSession(user.ID, ipAddress, userAgent)
-	if err != nil {
-		return nil, err
-	}
-
-	// Update last login time
-	now := time.Now()
-	s.db.Model(&user).Update("last_login_at", now)
-
-	return &LoginResult{
-		User:        user,
-		Token:       token,
-		IsBootstrap: false,
-	}, nil
+	return &user, nil
 }
 
 // Logout revokes a session
@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"log"
+	"log/slog"
 	"time"
 )
 
@@ -30,14 +30,14 @@ func (j *SessionCleanupJob) Start() {
 		ticker := time.NewTicker(j.interval)
 		defer ticker.Stop()
 
-		log.Printf("[SessionCleanup] Started, running every %v", j.interval)
+		slog.Info("Session cleanup started", "interval", j.interval)
 
 		for {
 			select {
 			case <-ticker.C:
 				j.runCleanup()
 			case <-j.stopCh:
-				log.Println("[SessionCleanup] Stopped")
+				slog.Info("Session cleanup stopped")
 				return
 			}
 		}
@@ -52,8 +52,8 @@ func (j *SessionCleanupJob) Stop() {
 // runCleanup performs a single cleanup operation
 func (j *SessionCleanupJob) runCleanup() {
 	if err := j.sessionRepo.CleanupExpiredSessions(); err != nil {
-		log.Printf("[SessionCleanup] Error cleaning up sessions: %v", err)
+		slog.Error("Session cleanup failed", "error", err)
 	} else {
-		log.Println("[SessionCleanup] Cleanup completed successfully")
+		slog.Debug("Session cleanup completed")
 	}
 }
@@ -42,6 +42,12 @@ func NewSessionRepository(db *gorm.DB, config *SessionConfig) *SessionRepository
 	}
 }
 
+// HashSessionToken returns the form a session token is stored in. It also keys data
+// kept per session, such as the duplicate view selection.
+func HashSessionToken(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
 // CreateSession creates a new session for a user and returns the session token
 func (r *SessionRepository) CreateSession(userID uint, ipAddress, userAgent string) (string, error) {
 	token, err := GenerateSecureToken(r.config.TokenLength)
@@ -50,7 +56,7 @@ func (r *SessionRepository) CreateSession(userID uint, ipAddress, userAgent stri
 	}
 
 	// Hash the token for storage
-	tokenHash := fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+	tokenHash := HashSessionToken(token)
 
 	now := time.Now()
 	session := domain.Session{
@@ -72,7 +78,7 @@ func (r *SessionRepository) CreateSession(userID uint, ipAddress, userAgent stri
 
 // GetSession retrieves a session by token and validates it
 func (r *SessionRepository) GetSession(token string) (*domain.Session, error) {
-	tokenHash := fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+	tokenHash := HashSessionToken(token)
 
 	var session domain.Session
 	if err := r.db.Where("session_token = ? AND revoked_at IS NULL", tokenHash).First(&session).Error; err != nil {
@@ -98,7 +104,7 @@ func (r *SessionRepository) GetSession(token string) (*domain.Session, error) {
 
 // UpdateLastSeen updates the last_seen_at timestamp for a session
 func (r *SessionRepository) UpdateLastSeen(token string) error {
-	tokenHash := fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+	tokenHash := HashSessionToken(token)
 	return r.db.Model(&domain.Session{}).
 		Where("session_token = ? AND revoked_at IS NULL", tokenHash).
 		Update("last_seen_at", time.Now()).Error
@@ -106,7 +112,7 @@ func (r *SessionRepository) UpdateLastSeen(token string) error {
 
 // RevokeSession marks a session as revoked
 func (r *SessionRepository) RevokeSession(token string) error {
-	tokenHash := fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+	tokenHash := HashSessionToken(token)
 	now := time.Now()
 	return r.db.Model(&domain.Session{}).
 		Where("session_token = ?", tokenHash).
@@ -123,10 +129,15 @@ func (r *SessionRepository) RevokeAllUserSessions(userID uint) error {
 		Update("revoked_at", now).Error
 }
 
-// CleanupExpiredSessions removes expired and revoked sessions from the database
+// CleanupExpiredSessions removes expired and revoked sessions from the database,
+// along with the selections stored for them
 func (r *SessionRepository) CleanupExpiredSessions() error {
 	now := time.Now()
-	return r.db.Where("expires_at < ? OR revoked_at IS NOT NULL", now).Delete(&domain.Session{}).Error
+	if err := r.db.Where("expires_at < ? OR revoked_at IS NOT NULL", now).Delete(&domain.Session{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("session_key NOT LIKE ? AND session_key NOT IN (?)", domain.UserSelectionKeyPrefix+"%",
+		r.db.Model(&domain.Session{}).Select("session_token")).Delete(&domain.Selection{}).Error
 }
 
 // GetSessionConfig returns the session configuration
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types published on the bus
+const (
+	TypeScanStatus    = "scan.status"    // Data: scan status snapshot
+	TypeScanFinished  = "scan.finished"  // Data: scan status snapshot
+	TypeScanSummary   = "scan.summary"   // Data: outcome of a finished scan and the duplicate totals after it
+	TypeDuplicatesNew = "duplicates.new" // Data: duplicate groups that appeared during a scan
+	TypeFilesDeleted  = "files.deleted"  // Data: result of a delete or batch delete request
+)
+
+// Event is a notification delivered to subscribers
+type Event struct {
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// subscriberBuffer is the number of events buffered per subscriber. Slow subscribers
+// lose events rather than blocking publishers.
+const subscriberBuffer = 64
+
+// Bus is an in-process publish/subscribe hub for application events.
+// A nil *Bus is valid and discards everything, so producers don't need nil checks.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewBus creates a new event bus
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe registers a new subscriber. The returned function unsubscribes and closes the channel.
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers an event to all subscribers without blocking
+func (b *Bus) Publish(eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+	event := Event{Type: eventType, Time: time.Now(), Data: data}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Subscriber is not keeping up, drop the event
+		}
+	}
+}
+
+// HasSubscribers reports whether anyone is listening, so producers can skip expensive payloads
+func (b *Bus) HasSubscribers() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers) > 0
+}
@@ -0,0 +1,49 @@
+package events
+
+import "testing"
+
+func TestBusDeliversToSubscribers(t *testing.T) {
+	bus := NewBus()
+	a, unsubA := bus.Subscribe()
+	b, unsubB := bus.Subscribe()
+	defer unsubB()
+
+	bus.Publish(TypeScanStatus, "hello")
+	for _, ch := range []<-chan Event{a, b} {
+		ev := <-ch
+		if ev.Type != TypeScanStatus || ev.Data != "hello" {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	}
+
+	unsubA()
+	unsubA() // idempotent
+	if _, ok := <-a; ok {
+		t.Fatal("channel should be closed after unsubscribe")
+	}
+	bus.Publish(TypeScanStatus, "again")
+	if ev := <-b; ev.Data != "again" {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+}
+
+func TestBusDropsWhenSubscriberIsSlow(t *testing.T) {
+	bus := NewBus()
+	ch, unsub := bus.Subscribe()
+	defer unsub()
+
+	for i := 0; i < subscriberBuffer*2; i++ {
+		bus.Publish(TypeScanStatus, i) // must not block
+	}
+	if len(ch) != subscriberBuffer {
+		t.Fatalf("buffered %d events, want %d", len(ch), subscriberBuffer)
+	}
+}
+
+func TestNilBusIsNoop(t *testing.T) {
+	var bus *Bus
+	bus.Publish(TypeScanStatus, nil)
+	if bus.HasSubscribers() {
+		t.Fatal("nil bus has no subscribers")
+	}
+}
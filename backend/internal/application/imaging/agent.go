@@ -0,0 +1,108 @@
+package imaging
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+
+	"gorm.io/gorm"
+)
+
+// Agents are instances of the application scanning the disks of other computers into
+// a local index and reporting it to a central server. The central server indexes their
+// files under agent://<name>/<path> next to its own, so duplicates are found across
+// machines; only the agent reads the files themselves.
+
+// ErrInvalidAgent is returned for an agent name or file path the central server rejects
+var ErrInvalidAgent = errors.New("invalid agent name or path")
+
+var agentNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidAgentName reports whether name can identify an agent: letters, digits, dots,
+// dashes and underscores, like a host name. "local" is reserved for this server.
+func ValidAgentName(name string) bool {
+	return agentNamePattern.MatchString(name) && name != LocalHost
+}
+
+// AgentPath returns the path a file of an agent is indexed under on the central server,
+// e.g. agent://laptop/home/me/a.jpg or agent://laptop/C:/Photos/a.jpg
+func AgentPath(agent, path string) string {
+	return objectstore.SchemeAgent + agent + "/" + strings.TrimPrefix(filepath.ToSlash(path), "/")
+}
+
+// agentPathOK reports whether an agent reported an absolute path the central server
+// can index: slash-separated, as stored in the agent index, and without .. elements
+func agentPathOK(path string) bool {
+	if strings.Contains(path, "\\") || objectstore.IsRemote(path) {
+		return false
+	}
+	// Unix or Windows drive paths
+	if !strings.HasPrefix(path, "/") && !(len(path) > 2 && path[1] == ':' && path[2] == '/') {
+		return false
+	}
+	for _, elem := range strings.Split(path, "/") {
+		if elem == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// StoreAgentFiles upserts the files reported by an agent, given with their paths on the
+// agent, and returns their number. Records of files the agent reported before are
+// updated and revived; their protected flag, set on the central server, is kept.
+func StoreAgentFiles(db *gorm.DB, agent string, files []domain.ImageFile) (int, error) {
+	if !ValidAgentName(agent) {
+		return 0, ErrInvalidAgent
+	}
+	now := time.Now()
+	records := make([]domain.ImageFile, 0, len(files))
+	for _, f := range files {
+		if !agentPathOK(f.Path) {
+			return 0, ErrInvalidAgent
+		}
+		f.ID = 0
+		f.Path = AgentPath(agent, f.Path)
+		f.Host = agent
+		f.Protected = false
+		f.DeletedAt = gorm.DeletedAt{}
+		// Set by the central server, as FinishAgentSync compares it with its own clock
+		f.UpdatedAt = now
+		records = append(records, f)
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+	err := db.Clauses(upsertOn("path")).CreateInBatches(&records, 500).Error
+	if err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// FinishAgentSync removes the records under the agent's scanned roots that the agent
+// did not report since the sync started, as their files are gone, and returns their
+// number. Like missing local files, they are soft-deleted.
+func FinishAgentSync(db *gorm.DB, agent string, roots []string, since time.Time) (int64, error) {
+	if !ValidAgentName(agent) {
+		return 0, ErrInvalidAgent
+	}
+	var removed int64
+	for _, root := range roots {
+		if !agentPathOK(root) {
+			return removed, ErrInvalidAgent
+		}
+		prefix := escapeLike(strings.TrimSuffix(AgentPath(agent, root), "/")) + "/%"
+		result := db.Where(`path LIKE ? ESCAPE '\' AND updated_at < ?`, prefix, since).Delete(&domain.ImageFile{})
+		if result.Error != nil {
+			return removed, result.Error
+		}
+		removed += result.RowsAffected
+	}
+	return removed, nil
+}
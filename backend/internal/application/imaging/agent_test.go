@@ -0,0 +1,99 @@
+package imaging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestAgentSync(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	db.Create(&domain.ImageFile{Path: "/photos/local.jpg", Hash: "hl", Size: 1})
+
+	files := []domain.ImageFile{
+		{Path: "/home/me/a.jpg", Hash: "ha", Size: 10},
+		{Path: "/home/me/b.jpg", Hash: "hb", Size: 20},
+		{Path: "/srv/other/c.jpg", Hash: "hc", Size: 30},
+	}
+	if n, err := StoreAgentFiles(db, "laptop", files); err != nil || n != 3 {
+		t.Fatalf("StoreAgentFiles = %d, %v", n, err)
+	}
+	db.Model(&domain.ImageFile{}).Where("path = ?", "agent://laptop/home/me/a.jpg").Update("protected", true)
+
+	// The next sync reports a.jpg with a new hash, while b.jpg is gone
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	if _, err := StoreAgentFiles(db, "laptop", []domain.ImageFile{{Path: "/home/me/a.jpg", Hash: "ha2", Size: 11}}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := FinishAgentSync(db, "laptop", []string{"/home/me"}, since); err != nil || n != 1 {
+		t.Fatalf("FinishAgentSync = %d, %v", n, err)
+	}
+
+	var got []string
+	var records []domain.ImageFile
+	db.Order("path").Find(&records)
+	for _, f := range records {
+		got = append(got, f.Path+":"+f.Hash)
+		if f.Path == "agent://laptop/home/me/a.jpg" && !f.Protected {
+			t.Error("the protected flag of a reported file was reset")
+		}
+		if f.Path != "/photos/local.jpg" && f.Host != "laptop" {
+			t.Errorf("host of %s = %q, want laptop", f.Path, f.Host)
+		}
+	}
+	want := "/photos/local.jpg:hl agent://laptop/home/me/a.jpg:ha2 agent://laptop/srv/other/c.jpg:hc"
+	if strings.Join(got, " ") != want {
+		t.Errorf("index = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	// b.jpg is revived when reported again
+	if _, err := StoreAgentFiles(db, "laptop", files[1:2]); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	db.Model(&domain.ImageFile{}).Where("path = ?", "agent://laptop/home/me/b.jpg").Count(&count)
+	if count != 1 {
+		t.Error("a file reported again was not revived")
+	}
+
+	for _, tc := range []struct{ agent, path string }{
+		{"bad/name", "/home/me/a.jpg"},
+		{"laptop", "relative/a.jpg"},
+		{"laptop", "/home/../etc/passwd"},
+		{"laptop", `C:\Photos\a.jpg`},
+		{"laptop", "s3://bucket/a.jpg"},
+	} {
+		if _, err := StoreAgentFiles(db, tc.agent, []domain.ImageFile{{Path: tc.path}}); !errors.Is(err, ErrInvalidAgent) {
+			t.Errorf("StoreAgentFiles(%q, %q) = %v, want ErrInvalidAgent", tc.agent, tc.path, err)
+		}
+	}
+	if AgentPath("pc", "C:/Photos/a.jpg") != "agent://pc/C:/Photos/a.jpg" {
+		t.Errorf("AgentPath = %s", AgentPath("pc", "C:/Photos/a.jpg"))
+	}
+}
+
+func TestFinishAgentSyncMatchesPrefixLiterally(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	file := []domain.ImageFile{{Path: "/home/me/a.jpg", Hash: "ha", Size: 10}}
+	for _, agent := range []string{"my_pc", "my-pc", "myXpc"} {
+		if _, err := StoreAgentFiles(db, agent, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The underscore of my_pc matches itself only, not any character
+	time.Sleep(10 * time.Millisecond)
+	if n, err := FinishAgentSync(db, "my_pc", []string{"/home/me"}, time.Now()); err != nil || n != 1 {
+		t.Fatalf("FinishAgentSync = %d, %v, want 1 file removed", n, err)
+	}
+	var left []string
+	db.Model(&domain.ImageFile{}).Order("path").Pluck("path", &left)
+	want := "agent://my-pc/home/me/a.jpg agent://myXpc/home/me/a.jpg"
+	if strings.Join(left, " ") != want {
+		t.Errorf("index = %s, want %s", strings.Join(left, " "), want)
+	}
+}
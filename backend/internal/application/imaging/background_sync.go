@@ -1,14 +1,18 @@
 package imaging
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"image-toolkit/internal/application/thumbnail"
 	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/imagecodec"
+	"image-toolkit/internal/infrastructure/objectstore"
 
 	"gorm.io/gorm"
 )
@@ -22,10 +26,11 @@ type BackgroundSyncManager struct {
 	db               *gorm.DB
 	thumbnailService *thumbnail.Service
 	syncInterval     time.Duration
+	options          ScanOptions
 }
 
 // NewBackgroundSyncManager creates a new background sync manager
-func NewBackgroundSyncManager(db *gorm.DB, thumbnailService *thumbnail.Service, syncIntervalMinutes int) *BackgroundSyncManager {
+func NewBackgroundSyncManager(db *gorm.DB, thumbnailService *thumbnail.Service, syncIntervalMinutes int, options ScanOptions) *BackgroundSyncManager {
 	interval := time.Duration(syncIntervalMinutes) * time.Minute
 	if interval <= 0 {
 		interval = 12 * 60 * time.Minute // Default: 30 minutes
@@ -35,6 +40,7 @@ func NewBackgroundSyncManager(db *gorm.DB, thumbnailService *thumbnail.Service,
 		db:               db,
 		thumbnailService: thumbnailService,
 		syncInterval:     interval,
+		options:          options,
 		stopCh:           make(chan struct{}),
 	}
 }
@@ -44,14 +50,14 @@ func (bsm *BackgroundSyncManager) Start() {
 	bsm.mu.Lock()
 	if bsm.running {
 		bsm.mu.Unlock()
-		log.Println("Background sync already running")
+		slog.Warn("Background sync already running")
 		return
 	}
 	bsm.running = true
 	bsm.stopCh = make(chan struct{})
 	bsm.mu.Unlock()
 
-	log.Printf("Starting background gallery sync (interval: %v)", bsm.syncInterval)
+	slog.Info("Starting background gallery sync", "interval", bsm.syncInterval)
 	go bsm.syncLoop()
 }
 
@@ -66,7 +72,7 @@ func (bsm *BackgroundSyncManager) Stop() {
 	close(bsm.stopCh)
 	bsm.mu.Unlock()
 
-	log.Println("Background gallery sync stopped")
+	slog.Info("Background gallery sync stopped")
 }
 
 // IsRunning returns whether the background sync is currently running
@@ -96,17 +102,17 @@ func (bsm *BackgroundSyncManager) syncLoop() {
 
 // syncOnce performs a single synchronization pass
 func (bsm *BackgroundSyncManager) syncOnce() {
-	log.Println("Background sync: starting gallery synchronization")
+	slog.Info("Background sync: starting gallery synchronization")
 
 	// Get all gallery folders
 	var folders []domain.GalleryFolder
 	if err := bsm.db.Find(&folders).Error; err != nil {
-		log.Printf("Background sync: failed to get gallery folders: %v", err)
+		slog.Error("Background sync: failed to get gallery folders", "error", err)
 		return
 	}
 
 	if len(folders) == 0 {
-		log.Println("Background sync: no gallery folders configured")
+		slog.Info("Background sync: no gallery folders configured")
 		return
 	}
 
@@ -117,11 +123,20 @@ func (bsm *BackgroundSyncManager) syncOnce() {
 	updatedFiles := 0
 	deletedFiles := 0
 	thumbnailGenerated := 0
+	var unavailable []string
 
 	for _, folder := range folders {
+		if objectstore.IsRemote(folder.Path) {
+			continue // object stores are synchronized by scans
+		}
+		if err := checkRoot(bsm.db, folder.Path, bsm.options); err != nil {
+			slog.Warn("Background sync: skipping gallery folder", "folder", folder.Path, "error", err)
+			unavailable = append(unavailable, rootPrefix(folder.Path, bsm.options))
+			continue
+		}
 		absPath, err := filepath.Abs(folder.Path)
 		if err != nil {
-			log.Printf("Background sync: failed to get absolute path for %s: %v", folder.Path, err)
+			slog.Error("Background sync: failed to get absolute path", "folder", folder.Path, "error", err)
 			continue
 		}
 
@@ -133,27 +148,42 @@ func (bsm *BackgroundSyncManager) syncOnce() {
 	}
 
 	// Clean up records for files that no longer exist
-	deletedFiles += bsm.cleanupMissingFiles()
+	deletedFiles += bsm.cleanupMissingFiles(unavailable)
 
-	log.Printf("Background sync: complete - %d new, %d updated, %d deleted, %d thumbnails generated",
-		newFiles, updatedFiles, deletedFiles, thumbnailGenerated)
+	// Complete size-prefiltered and two-stage hashing for new and modified files
+	if bsm.options.SizePrefilter || bsm.options.TwoStageHash {
+		bsm.completeDeferredHashing()
+	}
+
+	slog.Info("Background sync: complete",
+		"new", newFiles, "updated", updatedFiles, "deleted", deletedFiles, "thumbnails", thumbnailGenerated)
 }
 
 // syncFolder synchronizes a single folder sequentially
 func (bsm *BackgroundSyncManager) syncFolder(folderPath string, thumbnailEnabled bool) (newCount, updatedCount, deletedCount, thumbCount int) {
-	log.Printf("Background sync: scanning folder %s", folderPath)
+	slog.Info("Background sync: scanning folder", "folder", folderPath)
 
 	// Collect all image files from disk
 	diskFiles := make(map[string]os.FileInfo)
-	err := filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
+	ignore := newIgnoreMatcher()
+	err := walkTree(folderPath, bsm.options.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			log.Printf("Background sync: error accessing %s: %v", path, err)
+			slog.Warn("Background sync: error accessing path", "path", path, "error", err)
+			return nil
+		}
+		if path != folderPath && (bsm.options.isExcluded(path) || ignore.ignored(folderPath, path, info.IsDir())) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 		if info.IsDir() {
+			if bsm.options.beyondMaxDepth(folderPath, path, true) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
-		if !domain.IsImageFile(path) {
+		if !bsm.options.includes(path) || !bsm.options.inSizeRange(info.Size()) {
 			return nil
 		}
 		normalizedPath := filepath.ToSlash(path)
@@ -162,56 +192,106 @@ func (bsm *BackgroundSyncManager) syncFolder(folderPath string, thumbnailEnabled
 	})
 
 	if err != nil {
-		log.Printf("Background sync: failed to walk folder %s: %v", folderPath, err)
+		slog.Error("Background sync: failed to walk folder", "folder", folderPath, "error", err)
 		return
 	}
 
 	// Get all existing DB records for this folder
 	var dbFiles []domain.ImageFile
-	prefix := folderPath + "/"
-	if err := bsm.db.Where("path LIKE ?", prefix+"%").Find(&dbFiles).Error; err != nil {
-		log.Printf("Background sync: failed to query DB for folder %s: %v", folderPath, err)
+	prefix := bsm.options.pathKey(folderPath + "/")
+	if err := bsm.db.Unscoped().Where(bsm.options.pathColumn()+` LIKE ? ESCAPE '\'`, escapeLike(prefix)+"%").Find(&dbFiles).Error; err != nil {
+		slog.Error("Background sync: failed to query DB for folder", "folder", folderPath, "error", err)
 		return
 	}
 
-	// Build a map of DB files
-	dbFileMap := make(map[string]domain.ImageFile)
-	for _, dbFile := range dbFiles {
-		dbFileMap[dbFile.Path] = dbFile
+	// Build a map of DB files, dropping records of one file under differently cased paths
+	dbFileMap, variants := bsm.options.pickRecords(dbFiles, func(path string) bool {
+		_, ok := diskFiles[path]
+		return ok
+	})
+	for _, v := range variants {
+		if v.DeletedAt.Valid {
+			continue
+		}
+		if err := bsm.db.Delete(&v).Error; err != nil {
+			slog.Error("Background sync: failed to delete differently cased record", "path", v.Path, "error", err)
+			continue
+		}
+		deletedCount++
+	}
+
+	// Records of files that were moved or renamed into this folder are moved along
+	unknown := make(map[string]fileInfo)
+	var unknownFiles []fileInfo
+	for diskPath, diskInfo := range diskFiles {
+		if _, ok := dbFileMap[bsm.options.pathKey(diskPath)]; !ok {
+			fi := fileInfo{path: diskPath, normalizedPath: diskPath, size: diskInfo.Size(), modTime: diskInfo.ModTime(), symlink: isSymlinked(diskInfo)}
+			fi.device, fi.inode = inodeOf(diskPath, diskInfo)
+			unknown[diskPath] = fi
+			unknownFiles = append(unknownFiles, fi)
+		}
 	}
+	moves := findMoveSources(bsm.db, unknownFiles)
 
 	// Process each file on disk sequentially
 	for diskPath, diskInfo := range diskFiles {
 		// Check if we should stop
 		if !bsm.isRunning() {
-			log.Println("Background sync: stopped during folder scan")
+			slog.Info("Background sync: stopped during folder scan")
 			return
 		}
 
-		dbFile, existsInDB := dbFileMap[diskPath]
+		dbFile, existsInDB := dbFileMap[bsm.options.pathKey(diskPath)]
 
 		if !existsInDB {
+			if source := moves.claim(unknown[diskPath], bsm.options.Throttle); source != nil {
+				if err := moveFile(bsm.db, source, unknown[diskPath]); err != nil {
+					slog.Error("Background sync: failed to move record", "from", source.Path, "to", diskPath, "error", err)
+					continue
+				}
+				updatedCount++
+				slog.Debug("Background sync: moved file", "from", source.Path, "to", diskPath)
+				if bsm.thumbnailService != nil {
+					bsm.thumbnailService.Invalidate(source.Path)
+				}
+				if thumbnailEnabled && bsm.ensureThumbnail(diskPath) {
+					thumbCount++
+				}
+				continue
+			}
+
 			// New file - add to DB
-			hash, err := calculateFileHash(diskPath)
-			if err != nil {
-				log.Printf("Background sync: failed to hash new file %s: %v", diskPath, err)
+			hashed := hashFile(fileInfo{path: diskPath, normalizedPath: diskPath, size: diskInfo.Size()}, nil, bsm.options)
+			if hashed.err != nil {
+				slog.Warn("Background sync: failed to hash new file", "path", diskPath, "error", hashed.err)
 				continue
 			}
 
 			newFile := domain.ImageFile{
-				Path:    diskPath,
-				Size:    diskInfo.Size(),
-				Hash:    hash,
-				ModTime: diskInfo.ModTime(),
+				Path:       diskPath,
+				Size:       diskInfo.Size(),
+				Hash:       hashed.hash,
+				HashAlgo:   hashed.hashAlgo,
+				PrefixHash: hashed.prefixHash,
+				PHash:      hashed.pHash,
+				PHashAlgo:  hashed.pHashAlgo,
+				PHashXform: hashed.pHashXform,
+				PixelHash:  hashed.pixelHash,
+				Corrupt:    hashed.corrupt,
+				ModTime:    diskInfo.ModTime(),
+				IsVideo:    domain.IsVideoFile(diskPath),
+				IsSymlink:  isSymlinked(diskInfo),
+				Device:     unknown[diskPath].device,
+				Inode:      unknown[diskPath].inode,
 			}
 
 			if err := bsm.db.Create(&newFile).Error; err != nil {
-				log.Printf("Background sync: failed to create record for %s: %v", diskPath, err)
+				slog.Error("Background sync: failed to create record", "path", diskPath, "error", err)
 				continue
 			}
 
 			newCount++
-			log.Printf("Background sync: added new file %s", diskPath)
+			slog.Debug("Background sync: added new file", "path", diskPath)
 
 			// Generate thumbnail for new file
 			if thumbnailEnabled {
@@ -228,23 +308,36 @@ func (bsm *BackgroundSyncManager) syncFolder(folderPath string, thumbnailEnabled
 			}
 
 			if needsUpdate {
-				hash, err := calculateFileHash(diskPath)
-				if err != nil {
-					log.Printf("Background sync: failed to hash modified file %s: %v", diskPath, err)
+				hashed := hashFile(fileInfo{path: diskPath, normalizedPath: diskPath, size: diskInfo.Size()}, nil, bsm.options)
+				if hashed.err != nil {
+					slog.Warn("Background sync: failed to hash modified file", "path", diskPath, "error", hashed.err)
 					continue
 				}
 
+				if err := reviveRecord(bsm.db, &dbFile); err != nil {
+					slog.Error("Background sync: failed to revive record", "path", diskPath, "error", err)
+					continue
+				}
+				dbFile.Path = diskPath
 				dbFile.Size = diskInfo.Size()
-				dbFile.Hash = hash
+				dbFile.Hash = hashed.hash
+				dbFile.HashAlgo = hashed.hashAlgo
+				dbFile.PrefixHash = hashed.prefixHash
+				dbFile.PHash = hashed.pHash
+				dbFile.PHashAlgo = hashed.pHashAlgo
+				dbFile.PHashXform = hashed.pHashXform
+				dbFile.PixelHash = hashed.pixelHash
+				dbFile.Corrupt = hashed.corrupt
 				dbFile.ModTime = diskInfo.ModTime()
+				dbFile.Device, dbFile.Inode = inodeOf(diskPath, diskInfo)
 
 				if err := bsm.db.Save(&dbFile).Error; err != nil {
-					log.Printf("Background sync: failed to update record for %s: %v", diskPath, err)
+					slog.Error("Background sync: failed to update record", "path", diskPath, "error", err)
 					continue
 				}
 
 				updatedCount++
-				log.Printf("Background sync: updated file %s", diskPath)
+				slog.Debug("Background sync: updated file", "path", diskPath)
 
 				// Regenerate thumbnail for modified file (invalidate old one)
 				if thumbnailEnabled {
@@ -254,7 +347,10 @@ func (bsm *BackgroundSyncManager) syncFolder(folderPath string, thumbnailEnabled
 					}
 				}
 			} else {
-				// File unchanged - ensure thumbnail exists
+				// File unchanged - keep its path and inode current and ensure thumbnail exists
+				fi := fileInfo{path: diskPath, normalizedPath: diskPath, symlink: isSymlinked(diskInfo)}
+				fi.device, fi.inode = inodeOf(diskPath, diskInfo)
+				refreshUnchanged(bsm.db, &dbFile, fi)
 				if thumbnailEnabled && !bsm.thumbnailService.HasThumbnail(diskPath) {
 					if bsm.ensureThumbnail(diskPath) {
 						thumbCount++
@@ -267,6 +363,40 @@ func (bsm *BackgroundSyncManager) syncFolder(folderPath string, thumbnailEnabled
 	return
 }
 
+// completeDeferredHashing runs the postponed hashing passes, logging failures
+func (bsm *BackgroundSyncManager) completeDeferredHashing() {
+	completeDeferredHashingLogged(bsm.db, bsm.options, "Background sync")
+}
+
+// completeDeferredHashingLogged runs the size prefilter and two-stage hashing passes outside
+// of a ScanManager, writing errors and a summary to the log with the given prefix
+func completeDeferredHashingLogged(db *gorm.DB, opts ScanOptions, logPrefix string) {
+	progressChan := make(chan string, 100)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for msg := range progressChan {
+			if strings.HasPrefix(msg, "Error") {
+				slog.Warn(logPrefix+": deferred hashing failed", "detail", msg)
+			}
+		}
+	}()
+
+	var sized, resolved int
+	if opts.SizePrefilter {
+		sized = hashSizeCollisions(context.Background(), db, progressChan, opts)
+	}
+	if opts.TwoStageHash {
+		resolved = resolvePrefixCollisions(context.Background(), db, progressChan, opts)
+	}
+	close(progressChan)
+	<-done
+
+	if sized > 0 || resolved > 0 {
+		slog.Info(logPrefix+": deferred hashing complete", "size_matches", sized, "prefix_collisions", resolved)
+	}
+}
+
 // ensureThumbnail generates a thumbnail for a file if it doesn't exist
 func (bsm *BackgroundSyncManager) ensureThumbnail(filePath string) bool {
 	// Check if file still exists
@@ -279,35 +409,58 @@ func (bsm *BackgroundSyncManager) ensureThumbnail(filePath string) bool {
 		return false
 	}
 
+	// Video thumbnails need ffmpeg; without it every sync would log the same failure
+	if domain.IsVideoFile(filePath) && !imagecodec.FFmpegAvailable() {
+		return false
+	}
+
 	// Generate thumbnail
 	_, err := bsm.thumbnailService.GetOrGenerate(filePath)
 	if err != nil {
-		log.Printf("Background sync: failed to generate thumbnail for %s: %v", filePath, err)
+		slog.Warn("Background sync: failed to generate thumbnail", "path", filePath, "error", err)
 		return false
 	}
 
-	log.Printf("Background sync: generated thumbnail for %s", filePath)
+	slog.Debug("Background sync: generated thumbnail", "path", filePath)
 	return true
 }
 
-// cleanupMissingFiles removes DB records for files that no longer exist on disk
-func (bsm *BackgroundSyncManager) cleanupMissingFiles() int {
+// cleanupMissingFiles removes DB records for files that no longer exist on disk,
+// keeping the ones matching the LIKE patterns of unavailable gallery folders
+func (bsm *BackgroundSyncManager) cleanupMissingFiles(unavailable []string) int {
 	var files []domain.ImageFile
-	if err := bsm.db.Find(&files).Error; err != nil {
-		log.Printf("Background sync: failed to query all files for cleanup: %v", err)
+	query := bsm.db
+	for _, prefix := range unavailable {
+		query = query.Where(bsm.options.pathColumn()+` NOT LIKE ? ESCAPE '\'`, prefix)
+	}
+	if err := query.Find(&files).Error; err != nil {
+		slog.Error("Background sync: failed to query all files for cleanup", "error", err)
 		return 0
 	}
 
 	deletedCount := 0
+	_, variants := bsm.options.pickRecords(files, nil)
+	removed := make(map[uint]bool, len(variants))
+	for _, file := range variants {
+		if err := bsm.db.Delete(&file).Error; err != nil {
+			slog.Error("Background sync: failed to delete differently cased record", "path", file.Path, "error", err)
+			continue
+		}
+		removed[file.ID] = true
+		deletedCount++
+	}
 	for _, file := range files {
+		if removed[file.ID] || objectstore.IsRemote(file.Path) {
+			continue
+		}
 		if !bsm.isRunning() {
-			log.Println("Background sync: stopped during cleanup")
+			slog.Info("Background sync: stopped during cleanup")
 			break
 		}
 
 		if _, err := os.Stat(file.Path); os.IsNotExist(err) {
 			if err := bsm.db.Delete(&file).Error; err != nil {
-				log.Printf("Background sync: failed to delete record for missing file %s: %v", file.Path, err)
+				slog.Error("Background sync: failed to delete record for missing file", "path", file.Path, "error", err)
 				continue
 			}
 
@@ -317,7 +470,7 @@ func (bsm *BackgroundSyncManager) cleanupMissingFiles() int {
 			}
 
 			deletedCount++
-			log.Printf("Background sync: removed missing file record %s", file.Path)
+			slog.Debug("Background sync: removed missing file record", "path", file.Path)
 		}
 	}
 
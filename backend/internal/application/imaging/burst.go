@@ -0,0 +1,166 @@
+package imaging
+
+import (
+	"sort"
+	"time"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultBurstWindow is the longest gap between two consecutive shots of a burst
+	DefaultBurstWindow = 5 * time.Second
+	// MaxBurstWindow is the largest accepted burst window
+	MaxBurstWindow = 10 * time.Minute
+)
+
+// BurstWindow converts a burst window in seconds from configuration or a request,
+// reporting false when it is not between one second and MaxBurstWindow
+func BurstWindow(seconds int) (time.Duration, bool) {
+	window := time.Duration(seconds) * time.Second
+	return window, seconds >= 1 && window <= MaxBurstWindow
+}
+
+// shot is an image with a capture time taking part in burst detection
+type shot struct {
+	file    domain.ImageFile
+	hash    uint64
+	takenAt time.Time
+	dims    domain.Dimensions
+}
+
+// betterFrame reports whether a is a better frame to keep than b: the higher
+// resolution, then the larger file (more detail survives compression in a sharp
+// frame), then the earlier shot
+func betterFrame(a, b shot) bool {
+	pa := int64(a.dims.Width) * int64(a.dims.Height)
+	pb := int64(b.dims.Width) * int64(b.dims.Height)
+	if pa != pb {
+		return pa > pb
+	}
+	if a.file.Size != b.file.Size {
+		return a.file.Size > b.file.Size
+	}
+	return a.takenAt.Before(b.takenAt)
+}
+
+// clusterBursts groups shots taken within window of the previous shot of the group
+// whose perceptual hashes are within threshold bits. Shots must be sorted by capture
+// time. Each group is in capture order; single shots are dropped.
+func clusterBursts(shots []shot, threshold int, window time.Duration) [][]shot {
+	uf := newUnionFind(len(shots))
+	for i := range shots {
+		for j := i + 1; j < len(shots) && shots[j].takenAt.Sub(shots[i].takenAt) <= window; j++ {
+			if fileHashAlgorithm(&shots[i].file) == fileHashAlgorithm(&shots[j].file) &&
+				hammingDistance(shots[i].hash, shots[j].hash) <= threshold {
+				uf.union(i, j)
+			}
+		}
+	}
+
+	members := make(map[int][]shot)
+	var roots []int
+	for i := range shots {
+		root := uf.find(i)
+		if members[root] == nil {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], shots[i])
+	}
+
+	var bursts [][]shot
+	for _, root := range roots {
+		if len(members[root]) > 1 {
+			bursts = append(bursts, members[root])
+		}
+	}
+	return bursts
+}
+
+// findBurstGroups finds series of near-identical shots taken in quick succession,
+// using capture times from extracted metadata. Groups are ordered by their first
+// shot, newest first; the best frame (see betterFrame) is recommended for keeping.
+func findBurstGroups(db *gorm.DB, filter DuplicateFilter, threshold int, window time.Duration) ([]domain.DuplicateGroup, error) {
+	var files []domain.ImageFile
+	if err := filter.apply(db).Where("p_hash <> ''").Order("id").Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(files))
+	for i, f := range files {
+		ids[i] = f.ID
+	}
+	metadata := loadMetadata(db, ids, "width", "height", "date_taken")
+
+	var shots []shot
+	for _, f := range files {
+		m, ok := metadata[f.ID]
+		if !ok || m.DateTaken == nil {
+			continue
+		}
+		h, err := parsePerceptualHash(f.PHash)
+		if err != nil {
+			continue
+		}
+		shots = append(shots, shot{file: f, hash: h, takenAt: *m.DateTaken, dims: domain.Dimensions{Width: m.Width, Height: m.Height}})
+	}
+	sort.SliceStable(shots, func(i, j int) bool {
+		if !shots[i].takenAt.Equal(shots[j].takenAt) {
+			return shots[i].takenAt.Before(shots[j].takenAt)
+		}
+		return shots[i].file.Path < shots[j].file.Path
+	})
+
+	bursts := clusterBursts(shots, threshold, window)
+	groups := make([]domain.DuplicateGroup, len(bursts))
+	for i, burst := range bursts {
+		group := domain.DuplicateGroup{Hash: burst[0].file.PHash}
+		for k, s := range burst {
+			group.Files = append(group.Files, s.file)
+			group.Similarity = append(group.Similarity, similarityScore(hammingDistance(burst[0].hash, s.hash)))
+			group.Dimensions = append(group.Dimensions, s.dims)
+			group.TakenAt = append(group.TakenAt, s.takenAt)
+			if s.file.Size > group.Size {
+				group.Size = s.file.Size
+			}
+			if betterFrame(s, burst[group.Recommended]) {
+				group.Recommended = k
+			}
+		}
+		groups[i] = group
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].TakenAt[0].After(groups[j].TakenAt[0])
+	})
+	return groups, nil
+}
+
+// FindBurstsPaginated finds bursts among the files matching the filter in the given
+// order, with pagination
+func FindBurstsPaginated(db *gorm.DB, filter DuplicateFilter, order GroupOrder, offset, limit, threshold int, window time.Duration) ([]domain.DuplicateGroup, int, int, error) {
+	allGroups, err := findBurstGroups(db, filter, threshold, window)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	sortGroups(allGroups, order)
+
+	totalGroups := len(allGroups)
+	totalFiles := 0
+	for _, g := range allGroups {
+		totalFiles += len(g.Files)
+	}
+
+	if offset >= totalGroups {
+		return []domain.DuplicateGroup{}, totalGroups, totalFiles, nil
+	}
+
+	end := offset + limit
+	if end > totalGroups {
+		end = totalGroups
+	}
+
+	return allGroups[offset:end], totalGroups, totalFiles, nil
+}
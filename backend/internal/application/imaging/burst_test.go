@@ -0,0 +1,62 @@
+package imaging
+
+import (
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestClusterBursts(t *testing.T) {
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	shots := []shot{
+		{file: domain.ImageFile{ID: 1}, hash: 0x0, takenAt: base},
+		{file: domain.ImageFile{ID: 2}, hash: 0x1, takenAt: base.Add(2 * time.Second)},
+		// Within the window of the previous shot, so part of the same burst
+		{file: domain.ImageFile{ID: 3}, hash: 0x3, takenAt: base.Add(6 * time.Second)},
+		// Same time, different scene
+		{file: domain.ImageFile{ID: 4}, hash: 0xffff_ffff, takenAt: base.Add(7 * time.Second)},
+		// Same scene, but long after the burst
+		{file: domain.ImageFile{ID: 5}, hash: 0x0, takenAt: base.Add(time.Minute)},
+	}
+
+	bursts := clusterBursts(shots, 4, 5*time.Second)
+	if len(bursts) != 1 {
+		t.Fatalf("got %d bursts, want 1", len(bursts))
+	}
+	var ids []uint
+	for _, s := range bursts[0] {
+		ids = append(ids, s.file.ID)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("burst files %v, want [1 2 3]", ids)
+	}
+}
+
+func TestBetterFrame(t *testing.T) {
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	small := shot{file: domain.ImageFile{Size: 5_000_000}, takenAt: base, dims: domain.Dimensions{Width: 1920, Height: 1080}}
+	large := shot{file: domain.ImageFile{Size: 3_000_000}, takenAt: base.Add(time.Second), dims: domain.Dimensions{Width: 4000, Height: 3000}}
+	sharp := shot{file: domain.ImageFile{Size: 4_000_000}, takenAt: base.Add(2 * time.Second), dims: large.dims}
+
+	if !betterFrame(large, small) {
+		t.Error("higher resolution should win over a larger file")
+	}
+	if !betterFrame(sharp, large) {
+		t.Error("at the same resolution the larger file should win")
+	}
+	if !betterFrame(large, shot{file: large.file, takenAt: base.Add(time.Hour), dims: large.dims}) {
+		t.Error("on a full tie the earlier shot should win")
+	}
+}
+
+func TestBurstWindow(t *testing.T) {
+	if w, ok := BurstWindow(5); !ok || w != 5*time.Second {
+		t.Errorf("BurstWindow(5) = %v, %v", w, ok)
+	}
+	for _, seconds := range []int{0, -1, int(MaxBurstWindow.Seconds()) + 1} {
+		if _, ok := BurstWindow(seconds); ok {
+			t.Errorf("BurstWindow(%d) should be rejected", seconds)
+		}
+	}
+}
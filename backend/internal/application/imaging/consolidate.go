@@ -0,0 +1,150 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+
+	"gorm.io/gorm"
+)
+
+var ErrConsolidateRemote = errors.New("files in an object store cannot be moved into a library folder")
+
+// ConsolidatePath returns where a kept file goes in the library folder dest: below
+// a year/month folder of the date it was taken (its modification time if unknown)
+// when byDate is set, directly in dest otherwise
+func ConsolidatePath(dest string, file domain.ImageFile, taken *time.Time, byDate bool) string {
+	name := filepath.Base(filepath.FromSlash(file.Path))
+	if !byDate {
+		return filepath.Join(dest, name)
+	}
+	date := file.ModTime
+	if taken != nil && !taken.IsZero() {
+		date = *taken
+	}
+	return filepath.Join(dest, date.Format("2006"), date.Format("01"), name)
+}
+
+// DatesTaken returns the EXIF capture dates of the files with the given IDs that have one
+func DatesTaken(db *gorm.DB, ids []uint) (map[uint]*time.Time, error) {
+	const dbBatchSize = 500
+	dates := make(map[uint]*time.Time)
+	for i := 0; i < len(ids); i += dbBatchSize {
+		end := min(i+dbBatchSize, len(ids))
+		var rows []domain.ImageMetadata
+		if err := db.Select("image_file_id", "date_taken").
+			Where("image_file_id IN ? AND date_taken IS NOT NULL", ids[i:end]).
+			Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			dates[r.ImageFileID] = r.DateTaken
+		}
+	}
+	return dates, nil
+}
+
+// ConsolidateFile moves an indexed file to target and points its record at the new
+// path, keeping its metadata and hashes. A number is added to the name when another
+// file already exists there; a file already at target stays where it is. Moves
+// across filesystems copy the file and remove the original. Returns the new path.
+func ConsolidateFile(db *gorm.DB, file domain.ImageFile, target string) (string, error) {
+	if objectstore.IsRemote(file.Path) {
+		return "", ErrConsolidateRemote
+	}
+	source := filepath.FromSlash(file.Path)
+	if filepath.Clean(source) == filepath.Clean(target) {
+		return source, nil
+	}
+	if err := os.MkdirAll(LongPath(filepath.Dir(target)), 0755); err != nil {
+		return "", err
+	}
+	target, err := freeName(target)
+	if err != nil {
+		return "", err
+	}
+	if err := moveAcross(source, target); err != nil {
+		return "", err
+	}
+
+	info, err := os.Lstat(LongPath(target))
+	if err != nil {
+		return "", err
+	}
+	fi := fileInfo{path: target, normalizedPath: filepath.ToSlash(target), symlink: isSymlinked(info)}
+	fi.device, fi.inode = inodeOf(target, info)
+	if err := moveFile(db, &file, fi); err != nil {
+		return "", fmt.Errorf("file moved to %s, updating its record failed: %w", target, err)
+	}
+	return target, nil
+}
+
+// freeName returns path, or path with a number added to its name, whichever does not exist
+func freeName(path string) (string, error) {
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	for i := 1; ; i++ {
+		candidate := path
+		if i > 1 {
+			candidate = stem + "_" + strconv.Itoa(i) + ext
+		}
+		_, err := os.Lstat(LongPath(candidate))
+		if os.IsNotExist(err) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// moveAcross renames source to target, copying it with its modification time and
+// removing the original when a rename is not possible (e.g. across filesystems)
+func moveAcross(source, target string) error {
+	renameErr := os.Rename(LongPath(source), LongPath(target))
+	if renameErr == nil {
+		return nil
+	}
+	info, err := os.Stat(LongPath(source))
+	if err != nil {
+		return renameErr
+	}
+	if err := copyContents(source, target, info); err != nil {
+		os.Remove(LongPath(target))
+		return err
+	}
+	if err := os.Remove(LongPath(source)); err != nil {
+		os.Remove(LongPath(target))
+		return err
+	}
+	return nil
+}
+
+func copyContents(source, target string, info os.FileInfo) error {
+	in, err := os.Open(LongPath(source))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(LongPath(target), os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// The index compares modification times to tell changed files
+	return os.Chtimes(LongPath(target), info.ModTime(), info.ModTime())
+}
@@ -0,0 +1,88 @@
+package imaging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestConsolidatePath(t *testing.T) {
+	dest := "lib"
+	file := domain.ImageFile{Path: "/photos/old/a.jpg", ModTime: time.Date(2021, 3, 9, 0, 0, 0, 0, time.UTC)}
+	taken := time.Date(2019, 12, 31, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		taken  *time.Time
+		byDate bool
+		want   string
+	}{
+		{nil, false, filepath.Join("lib", "a.jpg")},
+		{&taken, false, filepath.Join("lib", "a.jpg")},
+		{&taken, true, filepath.Join("lib", "2019", "12", "a.jpg")},
+		{nil, true, filepath.Join("lib", "2021", "03", "a.jpg")},
+	}
+	for _, tt := range tests {
+		if got := ConsolidatePath(dest, file, tt.taken, tt.byDate); got != tt.want {
+			t.Errorf("ConsolidatePath(taken=%v, byDate=%v) = %s, want %s", tt.taken, tt.byDate, got, tt.want)
+		}
+	}
+}
+
+func TestConsolidateFile(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "library", "2020", "01")
+	source := filepath.Join(dir, "old", "a.jpg")
+	if err := os.MkdirAll(filepath.Dir(source), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("kept"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := domain.ImageFile{Path: filepath.ToSlash(source), Size: 4, Hash: "h", HashAlgo: "md5"}
+	if err := db.Create(&file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Another file already has the name in the library
+	if err := os.MkdirAll(lib, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(lib, "a.jpg"), []byte("other"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := ConsolidateFile(db, file, filepath.Join(lib, "a.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(lib, "a_2.jpg"); moved != want {
+		t.Fatalf("moved to %s, want %s", moved, want)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("source still exists: %v", err)
+	}
+	var record domain.ImageFile
+	if err := db.First(&record, file.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if record.Path != filepath.ToSlash(moved) || record.Hash != "h" {
+		t.Errorf("record = %s (hash %q), want %s with its hash", record.Path, record.Hash, moved)
+	}
+
+	// A file already in place stays there
+	if again, err := ConsolidateFile(db, record, moved); err != nil || again != moved {
+		t.Errorf("consolidating in place = %s, %v", again, err)
+	}
+
+	g := NewPathGuard([]string{dir})
+	if !g.ContainsTarget(filepath.Join(dir, "library", "new", "sub")) {
+		t.Error("a new folder inside the gallery should be a valid target")
+	}
+	if g.ContainsTarget(filepath.Join(dir, "..", "elsewhere")) || g.ContainsTarget("library") {
+		t.Error("folders outside the gallery should not be valid targets")
+	}
+}
@@ -0,0 +1,77 @@
+package imaging
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/zeebo/blake3"
+)
+
+// ContentHashAlgorithm identifies the cryptographic hash used for exact duplicate detection.
+// Hashes produced by different algorithms are never compared with each other.
+type ContentHashAlgorithm string
+
+const (
+	// ContentHashMD5 is the original algorithm, kept for backward compatibility
+	ContentHashMD5 ContentHashAlgorithm = "md5"
+	// ContentHashSHA256 is the collision-resistant alternative
+	ContentHashSHA256 ContentHashAlgorithm = "sha256"
+	// ContentHashXXH64 is a fast non-cryptographic 64-bit hash for large libraries
+	ContentHashXXH64 ContentHashAlgorithm = "xxh64"
+	// ContentHashBLAKE3 is a fast cryptographic hash, much quicker than MD5 on modern CPUs
+	ContentHashBLAKE3 ContentHashAlgorithm = "blake3"
+
+	// DefaultContentHashAlgorithm is used when no algorithm is configured and for
+	// rows stored before the algorithm was recorded
+	DefaultContentHashAlgorithm = ContentHashMD5
+)
+
+// ParseContentHashAlgorithm validates an algorithm name from configuration.
+// An empty name selects the default algorithm.
+func ParseContentHashAlgorithm(name string) (ContentHashAlgorithm, error) {
+	switch algo := ContentHashAlgorithm(strings.ToLower(strings.TrimSpace(name))); algo {
+	case "":
+		return DefaultContentHashAlgorithm, nil
+	case ContentHashMD5, ContentHashSHA256, ContentHashXXH64, ContentHashBLAKE3:
+		return algo, nil
+	default:
+		return "", fmt.Errorf("unknown content hash algorithm %q (expected md5, sha256, xxh64 or blake3)", name)
+	}
+}
+
+// newHasher creates a hash.Hash for the algorithm
+func (a ContentHashAlgorithm) newHasher() hash.Hash {
+	switch a {
+	case ContentHashSHA256:
+		return sha256.New()
+	case ContentHashXXH64:
+		return xxhash.New()
+	case ContentHashBLAKE3:
+		return blake3.New()
+	default:
+		return md5.New()
+	}
+}
+
+// calculateFileHash calculates the content hash of a file with the given algorithm,
+// reading it at the rate of throttle
+func calculateFileHash(path string, algo ContentHashAlgorithm, throttle *Throttle) (string, error) {
+	file, err := os.Open(longPath(path))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := algo.newHasher()
+	if err := hashReader(hasher, throttle.reader(file)); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
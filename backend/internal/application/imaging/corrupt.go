@@ -0,0 +1,50 @@
+package imaging
+
+import (
+	"errors"
+	"io/fs"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/imagecodec"
+
+	"gorm.io/gorm"
+)
+
+// isCorruptImage reports whether a decode error means the file itself is broken.
+// Files that could not be opened or read and formats this machine lacks a decoder
+// for (AVIF without ffmpeg, RAW without a preview) are not corrupt.
+func isCorruptImage(err error) bool {
+	var pathErr *fs.PathError
+	return err != nil &&
+		!errors.As(err, &pathErr) &&
+		!errors.Is(err, imagecodec.ErrFFmpegUnavailable) &&
+		!errors.Is(err, imagecodec.ErrNoPreview)
+}
+
+// checkCorrupt flags empty files and, when the scan does not decode images anyway,
+// images whose header does not decode. Videos are only checked for being empty.
+func checkCorrupt(fi fileInfo) bool {
+	if fi.size == 0 {
+		return true
+	}
+	if domain.IsVideoFile(fi.path) {
+		return false
+	}
+	_, err := imagecodec.DecodeConfig(fi.path)
+	return isCorruptImage(err)
+}
+
+// CorruptFiles returns the indexed files flagged as corrupt, ordered by path, with
+// their number and total size
+func CorruptFiles(db *gorm.DB, limit int) (files []domain.ImageFile, total, totalSize int64, err error) {
+	scope := db.Model(&domain.ImageFile{}).Where("corrupt = ?", true)
+	var sums struct {
+		Count int64
+		Size  int64
+	}
+	if err = scope.Session(&gorm.Session{}).Select("COUNT(*) AS count, COALESCE(SUM(size), 0) AS size").Scan(&sums).Error; err != nil {
+		return nil, 0, 0, err
+	}
+	err = scope.Session(&gorm.Session{}).Order("path").Limit(limit).Find(&files).Error
+	return files, sums.Count, sums.Size, err
+}
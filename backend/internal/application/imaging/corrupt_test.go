@@ -0,0 +1,76 @@
+package imaging
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestHashFileFlagsCorruptFiles(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.png")
+	f, err := os.Create(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, image.NewGray(image.Rect(0, 0, 8, 8)))
+	f.Close()
+	files := map[string][]byte{
+		"empty.jpg":   nil,
+		"garbage.jpg": []byte("not a jpeg at all"),
+		"empty.mp4":   nil,
+		"clip.mp4":    []byte("not decoded"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]bool{"good.png": false, "empty.jpg": true, "garbage.jpg": true, "empty.mp4": true, "clip.mp4": false}
+	// The header check without a decode and the decode for perceptual hashes agree
+	for _, opts := range []ScanOptions{{}, {PerceptualHash: true}} {
+		for name, corrupt := range want {
+			path := filepath.Join(dir, name)
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := hashFile(fileInfo{path: path, normalizedPath: filepath.ToSlash(path), size: info.Size()}, nil, opts)
+			if result.err != nil {
+				t.Fatalf("hashFile(%s): %v", name, result.err)
+			}
+			if result.corrupt != corrupt {
+				t.Errorf("hashFile(%s) with perceptual hash %v: corrupt = %v, want %v", name, opts.PerceptualHash, result.corrupt, corrupt)
+			}
+		}
+	}
+}
+
+func TestCorruptFiles(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	records := []domain.ImageFile{
+		{Path: "/photos/c.jpg", Size: 30, Hash: "c", Corrupt: true},
+		{Path: "/photos/a.jpg", Size: 0, Hash: "a", Corrupt: true},
+		{Path: "/photos/b.jpg", Size: 20, Hash: "b"},
+		{Path: "/photos/d.jpg", Size: 40, Hash: "d", Corrupt: true},
+	}
+	if err := db.Create(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	files, total, size, err := CorruptFiles(db, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || size != 70 {
+		t.Errorf("CorruptFiles total = %d, size = %d; want 3 and 70", total, size)
+	}
+	if len(files) != 2 || files[0].Path != "/photos/a.jpg" || files[1].Path != "/photos/c.jpg" {
+		t.Errorf("CorruptFiles = %+v, want a.jpg and c.jpg", files)
+	}
+}
@@ -0,0 +1,131 @@
+package imaging
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the per-directory file listing paths to skip, in gitignore syntax
+const IgnoreFileName = ".dedupeignore"
+
+// ignoreRule is one line of a .dedupeignore file
+type ignoreRule struct {
+	segments []string // pattern split on "/"
+	negate   bool     // "!pattern" re-includes a previously ignored path
+	dirOnly  bool     // "pattern/" matches directories only
+	anchored bool     // pattern with a slash is relative to dir; otherwise it matches any base name
+}
+
+// ignoreMatcher evaluates .dedupeignore files found between a scan root and a path.
+// Parsed files are cached per directory, so it should live for one walk.
+type ignoreMatcher struct {
+	rules map[string][]ignoreRule
+}
+
+func newIgnoreMatcher() *ignoreMatcher {
+	return &ignoreMatcher{rules: make(map[string][]ignoreRule)}
+}
+
+// ignored reports whether path, or one of its parent directories below root, is
+// excluded by the .dedupeignore files of root and the directories between root and
+// path. As in git, a file inside an ignored directory cannot be re-included.
+func (m *ignoreMatcher) ignored(root, path string, isDir bool) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for k := 1; k <= len(parts); k++ {
+		if m.ignoredEntry(root, parts[:k], k < len(parts) || isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredEntry evaluates the rules for one entry given as path parts below root.
+// Rules of deeper ignore files override those of their parents and the last
+// matching rule wins.
+func (m *ignoreMatcher) ignoredEntry(root string, parts []string, isDir bool) bool {
+	ignored := false
+	dir := root
+	for i := range parts {
+		for _, rule := range m.load(dir) {
+			if rule.matches(parts[i:], isDir) {
+				ignored = !rule.negate
+			}
+		}
+		dir = filepath.Join(dir, parts[i])
+	}
+	return ignored
+}
+
+// load returns the rules of dir's ignore file, reading it on first use
+func (m *ignoreMatcher) load(dir string) []ignoreRule {
+	if rules, ok := m.rules[dir]; ok {
+		return rules
+	}
+	rules := parseIgnoreFile(dir)
+	m.rules[dir] = rules
+	return rules
+}
+
+// matches reports whether the rule matches a path given relative to the rule's directory
+func (r ignoreRule) matches(rel []string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.anchored {
+		return matchSegments(r.segments, rel)
+	}
+	return matchSegments(r.segments, rel[len(rel)-1:])
+}
+
+func parseIgnoreFile(dir string) []ignoreRule {
+	f, err := os.Open(filepath.Join(dir, IgnoreFileName))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseIgnoreLine parses one gitignore-style line: blank lines and "#" comments are
+// skipped, "!" negates, a trailing "/" restricts the pattern to directories and a
+// leading or inner "/" anchors it to the ignore file's directory
+func parseIgnoreLine(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:] // "\#" and "\!" escape a leading special character
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	rule.segments = strings.Split(line, "/")
+	return rule, true
+}
@@ -0,0 +1,84 @@
+package imaging
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseIgnoreLine(t *testing.T) {
+	cases := []struct {
+		line string
+		ok   bool
+		want ignoreRule
+	}{
+		{"", false, ignoreRule{}},
+		{"# comment", false, ignoreRule{}},
+		{"*.tmp", true, ignoreRule{segments: []string{"*.tmp"}}},
+		{"cache/", true, ignoreRule{segments: []string{"cache"}, dirOnly: true}},
+		{"/raw", true, ignoreRule{segments: []string{"raw"}, anchored: true}},
+		{"a/**/b  ", true, ignoreRule{segments: []string{"a", "**", "b"}, anchored: true}},
+		{"!keep.jpg", true, ignoreRule{segments: []string{"keep.jpg"}, negate: true}},
+		{`\#1.jpg`, true, ignoreRule{segments: []string{"#1.jpg"}}},
+		{"/", false, ignoreRule{}},
+	}
+	for _, c := range cases {
+		got, ok := parseIgnoreLine(c.line)
+		if ok != c.ok {
+			t.Errorf("parseIgnoreLine(%q) ok = %v, want %v", c.line, ok, c.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("parseIgnoreLine(%q) = %+v, want %+v", c.line, got, c.want)
+		}
+	}
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(IgnoreFileName, "*.png\n!keep.png\ncache/\n/raw\n")
+	write("trips/"+IgnoreFileName, "# local rules\nkeep.png\nedits/*.jpg\n")
+
+	cases := []struct {
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{"a.jpg", false, false},
+		{"a.png", false, true},
+		{"keep.png", false, false},
+		{"sub/b.png", false, true},
+		{"cache", true, true},
+		{"cache", false, false},          // dir-only rule does not match files
+		{"sub/cache/c.jpg", false, true}, // inside an ignored directory
+		{"raw", true, true},
+		{"sub/raw", true, false},        // anchored to the root
+		{"trips/keep.png", false, true}, // deeper file overrides the root negation
+		{"trips/edits/d.jpg", false, true},
+		{"trips/edits/d.heic", false, false},
+		{"edits/d.jpg", false, false}, // trips rules do not apply outside trips
+	}
+	m := newIgnoreMatcher()
+	for _, c := range cases {
+		path := filepath.Join(root, filepath.FromSlash(c.rel))
+		if got := m.ignored(root, path, c.isDir); got != c.want {
+			t.Errorf("ignored(%q, dir=%v) = %v, want %v", c.rel, c.isDir, got, c.want)
+		}
+	}
+	if m.ignored(root, root, true) {
+		t.Error("scan root must never be ignored")
+	}
+}
@@ -0,0 +1,139 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+
+	"gorm.io/gorm"
+)
+
+// ErrPlanOutdated marks a planned removal skipped because the index changed since the
+// plan was made: the file or its kept copy is gone, changed or pinned
+var ErrPlanOutdated = errors.New("changed since the plan was made")
+
+// DeletionPlan lists the copies keep rules would remove from the exact duplicate
+// groups, computed ahead of an approval. Pinned and remote files are never planned
+// for removal.
+type DeletionPlan struct {
+	Groups           []PlannedGroup
+	Files            int   // files to remove
+	ReclaimableBytes int64 // total size of the files to remove
+}
+
+// PlannedGroup is the part of a deletion plan for one duplicate group
+type PlannedGroup struct {
+	Hash   string
+	Size   int64
+	Keep   string
+	Remove []string
+}
+
+// DeletionResult is the outcome of carrying out a deletion plan
+type DeletionResult struct {
+	BatchID     string // set when files were moved to the trash folder
+	Deleted     int
+	Failed      int
+	FailedFiles []string // base name and reason
+	FreedBytes  int64
+}
+
+// PlanDeletion chooses the file to keep in every exact duplicate group matching the
+// filter by the keep rules, already resolved by ScanManager.ResolveKeepRules
+func PlanDeletion(db *gorm.DB, filter DuplicateFilter, rules []KeepRule) (*DeletionPlan, error) {
+	groups, _, _, err := FindDuplicatesPaginated(db, filter, OrderDefault, 0, 100000)
+	if err != nil {
+		return nil, err
+	}
+	pixels := Resolutions(db, groups, rules)
+	plan := &DeletionPlan{}
+	for _, group := range groups {
+		keep := SelectKeeper(group.Files, rules, pixels)
+		planned := PlannedGroup{Hash: group.Hash, Size: group.Size, Keep: group.Files[keep].Path}
+		for _, file := range RemovableCopies(group.Files, keep) {
+			if objectstore.IsRemote(file.Path) {
+				continue
+			}
+			planned.Remove = append(planned.Remove, file.Path)
+			plan.ReclaimableBytes += file.Size
+		}
+		if len(planned.Remove) > 0 {
+			plan.Files += len(planned.Remove)
+			plan.Groups = append(plan.Groups, planned)
+		}
+	}
+	return plan, nil
+}
+
+// ExecuteDeletionPlan moves the planned files into trashDir, recording them for a
+// restore, and drops them from the index. Removals are checked against the index
+// first: the file must still be indexed with the planned content and unpinned, and
+// its kept copy must still be indexed, otherwise it fails with ErrPlanOutdated.
+// Files outside the gallery folders of guard are never touched.
+func ExecuteDeletionPlan(db *gorm.DB, plan *DeletionPlan, trashDir string, guard *PathGuard) (DeletionResult, error) {
+	var result DeletionResult
+	if trashDir == "" {
+		return result, fmt.Errorf("no trash folder")
+	}
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return result, err
+	}
+	fail := func(path string, err error) {
+		result.Failed++
+		result.FailedFiles = append(result.FailedFiles, filepath.Base(path)+": "+err.Error())
+	}
+
+	batchID, trashed := NewDeletionBatchID(), 0
+	for _, group := range plan.Groups {
+		var keeper int64
+		db.Model(&domain.ImageFile{}).Where("path = ? AND hash = ? AND size = ?", group.Keep, group.Hash, group.Size).Count(&keeper)
+		for _, path := range group.Remove {
+			var file domain.ImageFile
+			switch {
+			case keeper == 0:
+				fail(path, ErrPlanOutdated)
+				continue
+			case db.Where("path = ?", path).First(&file).Error != nil || file.Hash != group.Hash || file.Size != group.Size || file.Protected:
+				fail(path, ErrPlanOutdated)
+				continue
+			case !guard.Allows(path):
+				fail(path, fmt.Errorf("outside the gallery folders"))
+				continue
+			}
+
+			trashPath := TrashPath(trashDir, path)
+			if err := os.Rename(LongPath(path), LongPath(trashPath)); err != nil {
+				fail(path, err)
+				continue
+			}
+			if err := RecordDeletion(db, batchID, path, trashPath); err == nil {
+				trashed++
+			}
+			db.Where("path = ?", path).Delete(&domain.ImageFile{})
+			result.Deleted++
+			result.FreedBytes += file.Size
+		}
+	}
+	if trashed > 0 {
+		result.BatchID = batchID
+	}
+	return result, nil
+}
+
+// TrashPath returns where a file moved into trashDir goes: its base name, with a
+// timestamp added when a file of that name is already there
+func TrashPath(trashDir, path string) string {
+	baseName := filepath.Base(path)
+	trashPath := filepath.Join(trashDir, baseName)
+	if _, err := os.Stat(trashPath); err == nil {
+		ext := filepath.Ext(baseName)
+		trashPath = filepath.Join(trashDir, strings.TrimSuffix(baseName, ext)+"_"+time.Now().Format("20060102_150405.000")+ext)
+	}
+	return trashPath
+}
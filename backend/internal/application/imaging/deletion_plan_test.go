@@ -0,0 +1,88 @@
+package imaging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestDeletionPlan(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.Deletion{}, &domain.IgnoredGroup{}, &domain.GroupReview{})
+
+	dir := t.TempDir()
+	gallery := filepath.ToSlash(filepath.Join(dir, "photos"))
+	os.MkdirAll(gallery, 0755)
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	files := []domain.ImageFile{
+		{Path: gallery + "/a.jpg", Hash: "h1", Size: 10, ModTime: old},
+		{Path: gallery + "/a-copy.jpg", Hash: "h1", Size: 10, ModTime: old.Add(time.Hour)},
+		{Path: gallery + "/b.jpg", Hash: "h2", Size: 20, ModTime: old},
+		{Path: gallery + "/b-copy.jpg", Hash: "h2", Size: 20, ModTime: old.Add(time.Hour)},
+		{Path: gallery + "/b-pinned.jpg", Hash: "h2", Size: 20, ModTime: old.Add(2 * time.Hour), Protected: true},
+		{Path: gallery + "/c.jpg", Hash: "h3", Size: 30, ModTime: old},
+		{Path: gallery + "/c-copy.jpg", Hash: "h3", Size: 30, ModTime: old.Add(time.Hour)},
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.FromSlash(f.Path), []byte(f.Hash), 0644); err != nil {
+			t.Fatal(err)
+		}
+		db.Create(&f)
+	}
+
+	plan, err := PlanDeletion(db, DuplicateFilter{}, []KeepRule{{Policy: KeepOldest}})
+	if err != nil {
+		t.Fatalf("PlanDeletion: %v", err)
+	}
+	if plan.Files != 3 || plan.ReclaimableBytes != 60 || len(plan.Groups) != 3 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	for _, g := range plan.Groups {
+		for _, path := range g.Remove {
+			if !strings.HasSuffix(path, "-copy.jpg") {
+				t.Errorf("planned to remove %s", path)
+			}
+		}
+	}
+
+	// c-copy.jpg changes after the plan was made
+	db.Model(&domain.ImageFile{}).Where("path = ?", gallery+"/c-copy.jpg").Update("hash", "h4")
+
+	trash := filepath.Join(dir, "trash")
+	result, err := ExecuteDeletionPlan(db, plan, trash, NewPathGuard([]string{gallery}))
+	if err != nil {
+		t.Fatalf("ExecuteDeletionPlan: %v", err)
+	}
+	if result.Deleted != 2 || result.Failed != 1 || result.FreedBytes != 30 || result.BatchID == "" {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.FailedFiles) != 1 || !strings.Contains(result.FailedFiles[0], ErrPlanOutdated.Error()) {
+		t.Errorf("FailedFiles = %v", result.FailedFiles)
+	}
+	for _, name := range []string{"a-copy.jpg", "b-copy.jpg"} {
+		if _, err := os.Stat(filepath.Join(trash, name)); err != nil {
+			t.Errorf("%s not in the trash folder: %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(filepath.FromSlash(gallery), name)); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s still in the gallery", name)
+		}
+	}
+	var deletions int64
+	db.Model(&domain.Deletion{}).Where("batch_id = ?", result.BatchID).Count(&deletions)
+	if deletions != 2 {
+		t.Errorf("recorded deletions = %d, want 2", deletions)
+	}
+
+	// A second run finds every planned file gone
+	again, err := ExecuteDeletionPlan(db, plan, trash, NewPathGuard([]string{gallery}))
+	if err != nil || again.Deleted != 0 || again.Failed != 3 {
+		t.Errorf("second run = %+v, %v", again, err)
+	}
+	if _, err := ExecuteDeletionPlan(db, plan, "", NewPathGuard([]string{gallery})); err == nil {
+		t.Error("a plan was carried out without a trash folder")
+	}
+}
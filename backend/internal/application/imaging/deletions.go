@@ -0,0 +1,164 @@
+package imaging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDeletionBatchNotFound = errors.New("deletion batch not found")
+	errRestoreTargetExists   = errors.New("a file already exists at the original path")
+	errNotInTrash            = errors.New("file is no longer in the trash")
+)
+
+// DeletionBatch is the set of files moved to the trash by one request
+type DeletionBatch struct {
+	BatchID   string
+	DeletedAt time.Time
+	Files     []domain.Deletion
+}
+
+// NewDeletionBatchID returns an identifier for the files removed by one request
+func NewDeletionBatchID() string {
+	return newJobID()
+}
+
+// RecordDeletion records that path was moved to trashPath, copying the index fields
+// of its record (if indexed) so that a restore does not need to rehash the file.
+// Must be called before the record is deleted.
+func RecordDeletion(db *gorm.DB, batchID, path, trashPath string) error {
+	deletion := domain.Deletion{
+		BatchID:      batchID,
+		OriginalPath: filepath.ToSlash(path),
+		TrashPath:    trashPath,
+		DeletedAt:    time.Now(),
+	}
+	var file domain.ImageFile
+	if db.Where("path = ?", filepath.ToSlash(path)).First(&file).Error == nil {
+		deletion.Size = file.Size
+		deletion.Hash = file.Hash
+		deletion.PrefixHash = file.PrefixHash
+		deletion.HashAlgo = file.HashAlgo
+		deletion.PHash = file.PHash
+		deletion.PHashAlgo = file.PHashAlgo
+		deletion.PHashXform = file.PHashXform
+		deletion.PixelHash = file.PixelHash
+		deletion.ModTime = file.ModTime
+		deletion.IsVideo = file.IsVideo
+	}
+	return db.Create(&deletion).Error
+}
+
+// RecentDeletionBatches returns the most recent deletion batches, newest first
+func RecentDeletionBatches(db *gorm.DB, limit int) ([]DeletionBatch, error) {
+	var batchIDs []string
+	// Ordered by the highest ID rather than by time, which SQLite stores as text
+	err := db.Model(&domain.Deletion{}).Select("batch_id").Group("batch_id").
+		Order("MAX(id) DESC").Limit(limit).Pluck("batch_id", &batchIDs).Error
+	if err != nil || len(batchIDs) == 0 {
+		return nil, err
+	}
+
+	var rows []domain.Deletion
+	if err := db.Where("batch_id IN ?", batchIDs).Order("id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*DeletionBatch, len(batchIDs))
+	batches := make([]DeletionBatch, len(batchIDs))
+	for i, id := range batchIDs {
+		batches[i].BatchID = id
+		byID[id] = &batches[i]
+	}
+	for _, row := range rows {
+		batch := byID[row.BatchID]
+		if len(batch.Files) == 0 {
+			batch.DeletedAt = row.DeletedAt
+		}
+		batch.Files = append(batch.Files, row)
+	}
+	return batches, nil
+}
+
+// RestoreBatch moves the files of a batch that have not been restored yet back to
+// their original locations. A symlink left in place of a file is replaced; any other
+// file at the original path is not overwritten. Restored files get their index
+// record back when they are unchanged since the deletion. Returns the number of
+// restored files and a "name: reason" entry for each failure.
+func RestoreBatch(db *gorm.DB, batchID string) (int, []string, error) {
+	var rows []domain.Deletion
+	if err := db.Where("batch_id = ?", batchID).Order("id").Find(&rows).Error; err != nil {
+		return 0, nil, err
+	}
+	if len(rows) == 0 {
+		return 0, nil, ErrDeletionBatchNotFound
+	}
+
+	restored := 0
+	var failed []string
+	for _, row := range rows {
+		if row.RestoredAt != nil {
+			continue
+		}
+		if err := restoreFile(db, row); err != nil {
+			failed = append(failed, filepath.Base(row.OriginalPath)+": "+err.Error())
+			continue
+		}
+		now := time.Now()
+		db.Model(&domain.Deletion{}).Where("id = ?", row.ID).Update("restored_at", &now)
+		restored++
+	}
+	return restored, failed, nil
+}
+
+// restoreFile moves a single deleted file back and re-creates its index record
+func restoreFile(db *gorm.DB, row domain.Deletion) error {
+	path := longPath(row.OriginalPath)
+	trashPath := longPath(row.TrashPath)
+	info, err := os.Stat(trashPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errNotInTrash
+		}
+		return err
+	}
+	if existing, err := os.Lstat(path); err == nil && existing.Mode()&os.ModeSymlink == 0 {
+		return errRestoreTargetExists
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(trashPath, path); err != nil {
+		return err
+	}
+
+	// A changed or never hashed file is picked up by the next scan instead
+	if row.Hash == "" || info.Size() != row.Size || !info.ModTime().Equal(row.ModTime) {
+		return nil
+	}
+	db.Unscoped().Where("path = ?", row.OriginalPath).Delete(&domain.ImageFile{})
+	db.Create(&domain.ImageFile{
+		Path:       row.OriginalPath,
+		Size:       row.Size,
+		Hash:       row.Hash,
+		PrefixHash: row.PrefixHash,
+		HashAlgo:   row.HashAlgo,
+		PHash:      row.PHash,
+		PHashAlgo:  row.PHashAlgo,
+		PHashXform: row.PHashXform,
+		PixelHash:  row.PixelHash,
+		ModTime:    row.ModTime,
+		IsVideo:    row.IsVideo,
+	})
+	return nil
+}
+
+// ForgetDeletion drops the pending deletion records of a file removed from the trash
+func ForgetDeletion(db *gorm.DB, trashPath string) {
+	db.Where("trash_path = ? AND restored_at IS NULL", trashPath).Delete(&domain.Deletion{})
+}
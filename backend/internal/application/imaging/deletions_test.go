@@ -0,0 +1,77 @@
+package imaging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestRestoreBatch(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.Deletion{})
+
+	dir := t.TempDir()
+	trash := filepath.Join(dir, "trash")
+	if err := os.MkdirAll(trash, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	batchID := NewDeletionBatchID()
+	for _, name := range []string{"a.jpg", "b.jpg"} {
+		path := filepath.Join(dir, "photos", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		db.Create(&domain.ImageFile{Path: filepath.ToSlash(path), Size: 5, Hash: "h-" + name, HashAlgo: "md5", ModTime: modTime})
+
+		trashPath := filepath.Join(trash, name)
+		if err := os.Rename(path, trashPath); err != nil {
+			t.Fatal(err)
+		}
+		if err := RecordDeletion(db, batchID, path, trashPath); err != nil {
+			t.Fatal(err)
+		}
+		db.Where("path = ?", filepath.ToSlash(path)).Delete(&domain.ImageFile{})
+	}
+	// A new file took the place of b.jpg, which must not be overwritten
+	if err := os.WriteFile(filepath.Join(dir, "photos", "b.jpg"), []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	batches, err := RecentDeletionBatches(db, 10)
+	if err != nil || len(batches) != 1 || batches[0].BatchID != batchID || len(batches[0].Files) != 2 {
+		t.Fatalf("RecentDeletionBatches = %+v, %v", batches, err)
+	}
+
+	restored, failed, err := RestoreBatch(db, batchID)
+	if err != nil || restored != 1 || len(failed) != 1 {
+		t.Fatalf("RestoreBatch = %d, %v, %v; want 1 restored, 1 failed", restored, failed, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "photos", "a.jpg")); err != nil || string(data) != "a.jpg" {
+		t.Errorf("a.jpg not restored: %q, %v", data, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "photos", "b.jpg")); string(data) != "new" {
+		t.Errorf("b.jpg overwritten: %q", data)
+	}
+	var file domain.ImageFile
+	if err := db.Where("path = ?", filepath.ToSlash(filepath.Join(dir, "photos", "a.jpg"))).First(&file).Error; err != nil || file.Hash != "h-a.jpg" {
+		t.Errorf("index record not restored: %+v, %v", file, err)
+	}
+
+	// Restoring again only retries the failed file
+	if restored, failed, _ := RestoreBatch(db, batchID); restored != 0 || len(failed) != 1 {
+		t.Errorf("second RestoreBatch = %d, %v", restored, failed)
+	}
+	if _, _, err := RestoreBatch(db, "missing"); !errors.Is(err, ErrDeletionBatchNotFound) {
+		t.Errorf("unknown batch: got %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package imaging
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// MediaFilter restricts duplicate lookups to images, videos or both
+type MediaFilter string
+
+const (
+	MediaAll    MediaFilter = ""
+	MediaImages MediaFilter = "image"
+	MediaVideos MediaFilter = "video"
+)
+
+// DuplicateFilter selects the stored files considered by duplicate lookups
+type DuplicateFilter struct {
+	Media      MediaFilter
+	MinSize    int64    // bytes, 0 = no lower bound
+	MaxSize    int64    // bytes, 0 = no upper bound
+	Dir        string   // only files under this directory, empty = anywhere
+	Extensions []string // only files with these lowercase extensions (".png"), empty = any
+	// Count hardlinks of one file (same device and inode) as a single file, so they
+	// don't make a duplicate group on their own: removing one frees no space
+	CollapseHardlinks bool
+	// Leave out the exact duplicate groups marked as not duplicates
+	HideIgnored bool
+	// Only exact duplicate groups of this review status, empty = any
+	Review ReviewFilter
+	// Only exact duplicate groups with copies on every one of these hosts ("" is this
+	// server), empty = any
+	Hosts []string
+	// Only exact duplicate groups shot in this month, "YYYY-MM", empty = any
+	Month string
+	// Only exact duplicate groups with a copy shot near this location, nil = anywhere
+	Location *LocationFilter
+}
+
+// hardlinkKeyExpr identifies the file of an image_files row, equal for hardlinks of
+// one inode. Rows of unknown inode are told apart by their path.
+const hardlinkKeyExpr = "CASE WHEN inode = 0 THEN path ELSE CAST(device AS TEXT) || ':' || CAST(inode AS TEXT) END"
+
+// countExpr counts the files of a duplicate group in SQL
+func (f DuplicateFilter) countExpr() string {
+	if f.CollapseHardlinks {
+		return "COUNT(DISTINCT " + hardlinkKeyExpr + ")"
+	}
+	return "count(*)"
+}
+
+// orderBy returns the ORDER BY clause of a query grouping exact duplicates for the
+// order, counting files like countExpr
+func (f DuplicateFilter) orderBy(order GroupOrder, def, key string) string {
+	if f.CollapseHardlinks {
+		switch order {
+		case OrderWastedSpace:
+			return "MAX(size) * (" + f.countExpr() + " - 1) DESC, " + key
+		case OrderFileCount:
+			return f.countExpr() + " DESC, MAX(size) DESC, " + key
+		}
+	}
+	return order.orderBy(def, key)
+}
+
+// collapse keeps the first path of each hardlinked file of a group when hardlinks
+// are collapsed
+func (f DuplicateFilter) collapse(files []domain.ImageFile) []domain.ImageFile {
+	if !f.CollapseHardlinks {
+		return files
+	}
+	type inodeKey struct{ device, inode int64 }
+	seen := make(map[inodeKey]bool, len(files))
+	kept := files[:0:0]
+	for _, file := range files {
+		if file.Inode != 0 {
+			key := inodeKey{file.Device, file.Inode}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		kept = append(kept, file)
+	}
+	return kept
+}
+
+// apply adds the filter conditions to a query on image_files
+func (f DuplicateFilter) apply(db *gorm.DB) *gorm.DB {
+	switch f.Media {
+	case MediaImages:
+		db = db.Where("is_video = ?", false)
+	case MediaVideos:
+		db = db.Where("is_video = ?", true)
+	}
+	if f.MinSize > 0 {
+		db = db.Where("size >= ?", f.MinSize)
+	}
+	if f.MaxSize > 0 {
+		db = db.Where("size <= ?", f.MaxSize)
+	}
+	if f.Dir != "" {
+		dir := strings.TrimSuffix(filepath.ToSlash(f.Dir), "/")
+		db = db.Where(`path LIKE ? ESCAPE '\'`, escapeLike(dir)+"/%")
+	}
+	if len(f.Extensions) > 0 {
+		conds := make([]string, len(f.Extensions))
+		args := make([]any, len(f.Extensions))
+		for i, ext := range f.Extensions {
+			conds[i] = `LOWER(path) LIKE ? ESCAPE '\'`
+			args[i] = "%" + escapeLike(ext)
+		}
+		db = db.Where(strings.Join(conds, " OR "), args...)
+	}
+	return db
+}
+
+// applyGroups adds the filter conditions to a query grouping exact duplicates,
+// which also select the groups by their ignore mark, review status, hosts, month and
+// location
+func (f DuplicateFilter) applyGroups(db *gorm.DB) *gorm.DB {
+	db = applyMonth(applyHosts(f.Review.apply(f.apply(db)), f.Hosts), f.Month)
+	db = applyLocation(db, f.Location)
+	if f.HideIgnored {
+		db = db.Where(notIgnoredCond)
+	}
+	return db
+}
+
+// Narrow restricts the filter further to the files under dir with one of the
+// extensions and within the size limits of a request. Sizes tighten the limits
+// of the filter rather than replacing them; zero values add no restriction.
+func (f DuplicateFilter) Narrow(dir string, extensions []string, minSize, maxSize int64) DuplicateFilter {
+	if dir != "" {
+		f.Dir = filepath.Clean(dir)
+	}
+	if len(extensions) > 0 {
+		f.Extensions = extensions
+	}
+	f.MinSize = max(f.MinSize, minSize)
+	if maxSize > 0 && (f.MaxSize == 0 || maxSize < f.MaxSize) {
+		f.MaxSize = maxSize
+	}
+	return f
+}
+
+// escapeLike escapes the LIKE wildcards of s, so that it matches literally with
+// ESCAPE '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ParseExtensions parses a comma-separated list of file extensions such as
+// ".png, jpg" into lowercase extensions with a leading dot
+func ParseExtensions(s string) ([]string, error) {
+	var extensions []string
+	for _, ext := range strings.Split(s, ",") {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if len(ext) == 1 || strings.ContainsAny(ext[1:], `./\`) {
+			return nil, fmt.Errorf("invalid extension %q", ext)
+		}
+		extensions = append(extensions, ext)
+	}
+	return extensions, nil
+}
+
+// sizeUnits are the suffixes accepted by ParseSize, binary like FormatSize
+var sizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+	{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+	{"B", 1},
+}
+
+// ParseSize parses a file size such as "50KB", "1.5 MB" or "2g". Units are
+// binary (1KB = 1024 bytes); a plain number is a byte count.
+func ParseSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	factor := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(value, u.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, u.suffix))
+			factor = u.factor
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || !(n >= 0) || math.IsInf(n, 1) {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. 500KB, 10MB, 2GB)", s)
+	}
+	return int64(n * float64(factor)), nil
+}
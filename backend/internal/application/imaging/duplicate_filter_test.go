@@ -0,0 +1,141 @@
+package imaging
+
+import (
+	"reflect"
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{
+		"0":      0,
+		"512":    512,
+		"50KB":   50 << 10,
+		"50 kb":  50 << 10,
+		"1.5MB":  3 << 19,
+		"2g":     2 << 30,
+		"1TB":    1 << 40,
+		" 10B ":  10,
+		"100 MB": 100 << 20,
+	}
+	for in, want := range cases {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "KB", "-1MB", "ten", "5XB", "NaN", "Inf"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) should fail", in)
+		}
+	}
+}
+
+func TestScanOptionsInSizeRange(t *testing.T) {
+	opts := ScanOptions{MinSize: 100, MaxSize: 1000}
+	for size, want := range map[int64]bool{99: false, 100: true, 1000: true, 1001: false} {
+		if got := opts.inSizeRange(size); got != want {
+			t.Errorf("inSizeRange(%d) = %v, want %v", size, got, want)
+		}
+	}
+	if !(ScanOptions{}).inSizeRange(1 << 40) {
+		t.Error("no limits should accept any size")
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	got, err := ParseExtensions(" .PNG, jpg,,")
+	if want := []string{".png", ".jpg"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseExtensions = %v, %v; want %v", got, err, want)
+	}
+	if got, err := ParseExtensions(""); err != nil || got != nil {
+		t.Errorf("ParseExtensions(\"\") = %v, %v; want none", got, err)
+	}
+	for _, in := range []string{".", "a/b", ".tar.gz"} {
+		if _, err := ParseExtensions(in); err == nil {
+			t.Errorf("ParseExtensions(%q) should fail", in)
+		}
+	}
+}
+
+func TestDuplicateFilterNarrow(t *testing.T) {
+	f := DuplicateFilter{MinSize: 100, MaxSize: 1000}.Narrow("/photos/2020/", []string{".png"}, 50, 500)
+	want := DuplicateFilter{MinSize: 100, MaxSize: 500, Dir: "/photos/2020", Extensions: []string{".png"}}
+	if !reflect.DeepEqual(f, want) {
+		t.Errorf("Narrow = %+v, want %+v", f, want)
+	}
+	if f := (DuplicateFilter{MaxSize: 1000}).Narrow("", nil, 0, 0); f.MaxSize != 1000 || f.Dir != "" {
+		t.Errorf("empty Narrow changed the filter: %+v", f)
+	}
+}
+
+func TestDuplicateFilterApply(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	for i, path := range []string{"/photos/2020/a.png", "/photos/2020/b.JPG", "/photos/2020x/c.png", "/photos/2020/sub/d.png", "/photos/2_20/e.png"} {
+		if err := db.Create(&domain.ImageFile{Path: path, Size: int64(i+1) * 100, Hash: "h"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		filter DuplicateFilter
+		want   []string
+	}{
+		{DuplicateFilter{Dir: "/photos/2020"}, []string{"/photos/2020/a.png", "/photos/2020/b.JPG", "/photos/2020/sub/d.png"}},
+		{DuplicateFilter{Dir: "/photos/2_20"}, []string{"/photos/2_20/e.png"}},
+		{DuplicateFilter{Extensions: []string{".jpg"}}, []string{"/photos/2020/b.JPG"}},
+		{DuplicateFilter{Dir: "/photos/2020", Extensions: []string{".png", ".jpg"}, MinSize: 200}, []string{"/photos/2020/b.JPG", "/photos/2020/sub/d.png"}},
+	}
+	for _, tc := range cases {
+		var paths []string
+		if err := tc.filter.apply(db.Model(&domain.ImageFile{})).Order("path").Pluck("path", &paths).Error; err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(paths, tc.want) {
+			t.Errorf("filter %+v = %v, want %v", tc.filter, paths, tc.want)
+		}
+	}
+}
+
+func TestDuplicateFilterCollapseHardlinks(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	files := []domain.ImageFile{
+		// Two hardlinks of one file: no duplicates when collapsed
+		{Path: "/p/link1.jpg", Size: 10, Hash: "a", HashAlgo: "md5", Device: 1, Inode: 100},
+		{Path: "/p/link2.jpg", Size: 10, Hash: "a", HashAlgo: "md5", Device: 1, Inode: 100},
+		// Hardlinks plus a real copy, and a copy of unknown inode
+		{Path: "/p/b1.jpg", Size: 20, Hash: "b", HashAlgo: "md5", Device: 1, Inode: 200},
+		{Path: "/p/b2.jpg", Size: 20, Hash: "b", HashAlgo: "md5", Device: 1, Inode: 200},
+		{Path: "/p/b3.jpg", Size: 20, Hash: "b", HashAlgo: "md5", Device: 1, Inode: 201},
+		{Path: "/p/b4.jpg", Size: 20, Hash: "b", HashAlgo: "md5"},
+		// The same inode number on another device is another file
+		{Path: "/q/c1.jpg", Size: 30, Hash: "c", HashAlgo: "md5", Device: 1, Inode: 300},
+		{Path: "/r/c2.jpg", Size: 30, Hash: "c", HashAlgo: "md5", Device: 2, Inode: 300},
+	}
+	if err := db.Create(&files).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	counts := func(filter DuplicateFilter) map[string]int {
+		groups, totalGroups, _, err := FindDuplicatesPaginated(db, filter, OrderWastedSpace, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]int)
+		for _, g := range groups {
+			got[g.Hash] = len(g.Files)
+		}
+		if totalGroups != len(got) {
+			t.Fatalf("total groups %d, listed %d", totalGroups, len(got))
+		}
+		return got
+	}
+
+	if got, want := counts(DuplicateFilter{}), map[string]int{"a": 2, "b": 4, "c": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hardlinks kept: got %v, want %v", got, want)
+	}
+	if got, want := counts(DuplicateFilter{CollapseHardlinks: true}), map[string]int{"b": 3, "c": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hardlinks collapsed: got %v, want %v", got, want)
+	}
+}
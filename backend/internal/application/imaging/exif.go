@@ -1,22 +1,17 @@
 package imaging
 
 import (
+	"bytes"
 	"fmt"
-	"image"
-	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
+	"io"
 	"math"
 	"os"
 
-	_ "github.com/disintegration/imaging"
 	"github.com/rwcarlsen/goexif/exif"
 	"github.com/rwcarlsen/goexif/tiff"
-	_ "golang.org/x/image/bmp"
-	_ "golang.org/x/image/tiff"
-	_ "golang.org/x/image/webp"
 
 	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/imagecodec"
 )
 
 // extractMetadata reads EXIF metadata and image dimensions from a file.
@@ -30,7 +25,7 @@ func extractMetadata(filePath string) (*domain.ImageMetadata, error) {
 		meta.Height = h
 	}
 
-	// Attempt EXIF extraction (only JPEG and TIFF have EXIF)
+	// Attempt EXIF extraction (JPEG, TIFF and HEIF have EXIF)
 	extractExifFields(filePath, meta)
 
 	return meta, nil
@@ -38,13 +33,7 @@ func extractMetadata(filePath string) (*domain.ImageMetadata, error) {
 
 // getImageDimensions reads only the image header to get width and height.
 func getImageDimensions(filePath string) (int, int, error) {
-	f, err := os.Open(filePath)
-	if err != nil {
-		return 0, 0, err
-	}
-	defer f.Close()
-
-	cfg, _, err := image.DecodeConfig(f)
+	cfg, err := imagecodec.DecodeConfig(filePath)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -60,7 +49,21 @@ func extractExifFields(filePath string, meta *domain.ImageMetadata) {
 	}
 	defer f.Close()
 
-	x, err := exif.Decode(f)
+	var r io.Reader = f
+	if isHEIF(filePath) {
+		// HEIF keeps EXIF in a separate item rather than in a JPEG APP1 segment
+		data, err := io.ReadAll(io.LimitReader(f, heifExifScanLimit))
+		if err != nil {
+			return
+		}
+		block := findExifBlock(data)
+		if block == nil {
+			return
+		}
+		r = bytes.NewReader(block)
+	}
+
+	x, err := exif.Decode(r)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,41 @@
+package imaging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInodeOfHardlinks(t *testing.T) {
+	dir := t.TempDir()
+	original := filepath.Join(dir, "a.jpg")
+	if err := os.WriteFile(original, []byte("photo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "b.jpg")
+	if err := os.Link(original, link); err != nil {
+		t.Skipf("hardlinks not supported: %v", err)
+	}
+	copied := filepath.Join(dir, "c.jpg")
+	if err := os.WriteFile(copied, []byte("photo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	inode := func(path string) [2]int64 {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		device, ino := inodeOf(path, info)
+		if ino == 0 {
+			t.Fatalf("no inode for %s", path)
+		}
+		return [2]int64{device, ino}
+	}
+	if inode(original) != inode(link) {
+		t.Error("hardlinks should share device and inode")
+	}
+	if inode(original) == inode(copied) {
+		t.Error("a copy should have its own inode")
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package imaging
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID identifies a file independently of the path it was reached by
+type fileID struct {
+	dev, ino uint64
+}
+
+func fileIDOf(_ string, info os.FileInfo) (fileID, bool) {
+	if l, ok := info.(linkedInfo); ok {
+		info = l.FileInfo
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
+
+// inodeOf returns the device and inode of a file, both 0 if unknown. Hardlinks of one
+// file share them.
+func inodeOf(_ string, info os.FileInfo) (device, inode int64) {
+	if id, ok := fileIDOf("", info); ok {
+		return int64(id.dev), int64(id.ino)
+	}
+	return 0, 0
+}
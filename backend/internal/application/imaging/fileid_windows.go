@@ -0,0 +1,36 @@
+package imaging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// fileID identifies a directory independently of the path it was reached by.
+// Windows FileInfo carries no file index, so the fully resolved path is used.
+type fileID string
+
+func fileIDOf(path string, _ os.FileInfo) (fileID, bool) {
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+	return fileID(strings.ToLower(real)), true
+}
+
+// inodeOf returns the volume serial number and file index of a file, both 0 if
+// unknown. Hardlinks of one file share them. Windows FileInfo doesn't carry them,
+// so the file is opened.
+func inodeOf(path string, _ os.FileInfo) (device, inode int64) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+	var d syscall.ByHandleFileInformation
+	if err := syscall.GetFileInformationByHandle(syscall.Handle(f.Fd()), &d); err != nil {
+		return 0, 0
+	}
+	return int64(d.VolumeSerialNumber), int64(d.FileIndexHigh)<<32 | int64(d.FileIndexLow)
+}
@@ -0,0 +1,122 @@
+package imaging
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"image-toolkit/internal/domain"
+)
+
+// FolderPattern is a combination of folders holding the copies of exact duplicate
+// groups, the unit batch deletion rules apply to
+type FolderPattern struct {
+	Folders []string // sorted, as returned by filepath.Dir
+	Groups  int
+	Files   int
+}
+
+// FindFolderPatterns aggregates the exact duplicate groups matching the filter by the
+// folders of their files, most frequent pattern first. The distinct folders of each
+// group are computed by SQL and streamed, so the files themselves are never loaded.
+func FindFolderPatterns(db *gorm.DB, filter DuplicateFilter) ([]FolderPattern, error) {
+	groups := filter.applyGroups(db.Model(&domain.ImageFile{})).
+		Select("hash_algo AS g_algo, hash AS g_hash, size AS g_size, " + filter.countExpr() + " AS g_files").
+		Where("hash <> ''").
+		Group("hash_algo, hash, size").
+		Having(filter.countExpr() + " > 1")
+
+	members := filter.apply(db.Model(&domain.ImageFile{}).
+		Joins("JOIN (?) AS g ON image_files.hash_algo = g.g_algo AND image_files.hash = g.g_hash AND image_files.size = g.g_size", groups))
+	// rtrim drops the trailing characters other than '/', leaving the directory
+	// with a trailing slash
+	columns := "g_algo, g_hash, g_size, g_files, rtrim(path, replace(path, '/', '')) AS dir"
+	if filter.CollapseHardlinks {
+		// Like collapse, only the first path of each hardlinked file counts
+		members = members.Select(columns + ", device, inode").Order("g_algo, g_hash, g_size, image_files.id")
+	} else {
+		members = members.Distinct(columns).Order("g_algo, g_hash, g_size")
+	}
+	rows, err := members.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type groupKey struct {
+		algo, hash string
+		size       int64
+	}
+	type inodeKey struct{ device, inode int64 }
+	var (
+		current groupKey
+		files   int
+		folders = make(map[string]bool)
+		seen    = make(map[inodeKey]bool)
+	)
+	byID := make(map[string]*FolderPattern)
+	flush := func() {
+		if len(folders) == 0 {
+			return
+		}
+		sorted := make([]string, 0, len(folders))
+		for folder := range folders {
+			sorted = append(sorted, folder)
+		}
+		sort.Strings(sorted)
+		id := strings.Join(sorted, "|")
+		if p, ok := byID[id]; ok {
+			p.Groups++
+			p.Files += files
+		} else {
+			byID[id] = &FolderPattern{Folders: sorted, Groups: 1, Files: files}
+		}
+		clear(folders)
+		clear(seen)
+	}
+
+	for rows.Next() {
+		var row struct {
+			GAlgo  string
+			GHash  string
+			GSize  int64
+			GFiles int
+			Dir    string
+			Device int64
+			Inode  int64
+		}
+		if err := db.ScanRows(rows, &row); err != nil {
+			return nil, err
+		}
+		if key := (groupKey{row.GAlgo, row.GHash, row.GSize}); key != current {
+			flush()
+			current, files = key, row.GFiles
+		}
+		if row.Inode != 0 {
+			if seen[inodeKey{row.Device, row.Inode}] {
+				continue
+			}
+			seen[inodeKey{row.Device, row.Inode}] = true
+		}
+		// Clean matches filepath.Dir: no trailing slash, OS separators
+		folders[filepath.Clean(row.Dir)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	patterns := make([]FolderPattern, 0, len(byID))
+	for _, p := range byID {
+		patterns = append(patterns, *p)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Groups != patterns[j].Groups {
+			return patterns[i].Groups > patterns[j].Groups
+		}
+		return strings.Join(patterns[i].Folders, "|") < strings.Join(patterns[j].Folders, "|")
+	})
+	return patterns, nil
+}
@@ -0,0 +1,63 @@
+package imaging
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestFindFolderPatterns(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	add := func(path, hash string, inode int64) {
+		f := domain.ImageFile{Path: path, Hash: hash, HashAlgo: "sha256", Size: 10, ModTime: time.Now(), Device: 1, Inode: inode}
+		if err := db.Create(&f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("/photos/b/1.jpg", "h1", 1)
+	add("/photos/a/1.jpg", "h1", 2)
+	add("/photos/a/2.jpg", "h2", 3)
+	add("/photos/b/2.jpg", "h2", 4)
+	add("/photos/b/2-copy.jpg", "h2", 5)
+	add("/photos/a/3.jpg", "h3", 6)
+	add("/photos/c/3.jpg", "h3", 7)
+	// A hardlink pair in two folders and a copy of it
+	add("/photos/a/4.jpg", "h4", 8)
+	add("/photos/d/4.jpg", "h4", 8)
+	add("/photos/c/4.jpg", "h4", 9)
+	add("/photos/unique.jpg", "h5", 10)
+
+	describe := func(patterns []FolderPattern) string {
+		s := ""
+		for _, p := range patterns {
+			folders := make([]string, len(p.Folders))
+			for i, f := range p.Folders {
+				folders[i] = filepath.ToSlash(f)
+			}
+			s += fmt.Sprintf("%v:%d/%d ", folders, p.Groups, p.Files)
+		}
+		return s
+	}
+
+	patterns, err := FindFolderPatterns(db, DuplicateFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[/photos/a /photos/b]:2/5 [/photos/a /photos/c]:1/2 [/photos/a /photos/c /photos/d]:1/3 "
+	if got := describe(patterns); got != want {
+		t.Errorf("patterns = %s, want %s", got, want)
+	}
+
+	// Collapsed hardlinks keep the first path of the pair
+	patterns, err = FindFolderPatterns(db, DuplicateFilter{CollapseHardlinks: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "[/photos/a /photos/b]:2/5 [/photos/a /photos/c]:2/4 "
+	if got := describe(patterns); got != want {
+		t.Errorf("collapsed patterns = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package imaging
+
+import (
+	"bytes"
+	"image/jpeg"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"image-toolkit/internal/infrastructure/imagecodec"
+)
+
+// browserImageExtensions are the formats every major browser displays natively
+var browserImageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".bmp":  true,
+	".webp": true,
+	".avif": true,
+}
+
+// IsBrowserImage reports whether the file can be sent to a browser as is;
+// other formats (HEIC, TIFF, RAW) have to be converted with EncodeJPEG first
+func IsBrowserImage(path string) bool {
+	return browserImageExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// EncodeJPEG decodes an image of any supported format and writes it to w as JPEG
+func EncodeJPEG(w io.Writer, path string) error {
+	img, err := imagecodec.Decode(path)
+	if err != nil {
+		return err
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: 90})
+}
+
+// isHEIF reports whether the file is a HEIC/HEIF container
+func isHEIF(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".heic" || ext == ".heif"
+}
+
+// heifExifScanLimit bounds how much of a HEIF file is searched for the EXIF
+// block. Phones store it in front of the image data, within the first few KB.
+const heifExifScanLimit = 1 << 20
+
+// findExifBlock returns the TIFF-structured EXIF data stored in a HEIF item,
+// which starts with an "Exif\0\0" marker, or nil if there is none
+func findExifBlock(data []byte) []byte {
+	marker := []byte("Exif\x00\x00")
+	for offset := 0; ; {
+		i := bytes.Index(data[offset:], marker)
+		if i < 0 {
+			return nil
+		}
+		start := offset + i + len(marker)
+		rest := data[start:]
+		if bytes.HasPrefix(rest, []byte("II*\x00")) || bytes.HasPrefix(rest, []byte("MM\x00*")) {
+			return rest
+		}
+		offset = start
+	}
+}
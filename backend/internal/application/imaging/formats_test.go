@@ -0,0 +1,32 @@
+package imaging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFindExifBlock(t *testing.T) {
+	tiff := []byte("MM\x00*\x00\x00\x00\x08")
+	data := append([]byte("\x00\x00\x00\x1cftypheic....Exif\x00\x00junk....\x00\x00\x00\x06Exif\x00\x00"), tiff...)
+
+	block := findExifBlock(data)
+	if !bytes.Equal(block, tiff) {
+		t.Errorf("findExifBlock = %q, want %q", block, tiff)
+	}
+	if findExifBlock([]byte("ftypheic no exif here")) != nil {
+		t.Error("findExifBlock found EXIF in data without a TIFF header")
+	}
+}
+
+func TestIsBrowserImage(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/a/IMG_0001.JPG":  true,
+		"/a/b.webp":        true,
+		"/a/IMG_0001.HEIC": false,
+		"/a/scan.tif":      false,
+	} {
+		if got := IsBrowserImage(path); got != want {
+			t.Errorf("IsBrowserImage(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
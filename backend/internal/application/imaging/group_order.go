@@ -0,0 +1,134 @@
+package imaging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+// GroupOrder orders the duplicate groups of a listing
+type GroupOrder string
+
+const (
+	// OrderDefault keeps the order of the mode: largest files first, bursts newest first
+	OrderDefault GroupOrder = ""
+	// OrderWastedSpace puts first the groups freeing the most space when one file is kept
+	OrderWastedSpace GroupOrder = "wasted-space"
+	// OrderFileCount puts first the groups with the most files
+	OrderFileCount GroupOrder = "file-count"
+	// OrderNewest puts first the groups with the most recently modified file
+	OrderNewest GroupOrder = "newest"
+	// OrderOldest puts first the groups with the earliest modified file
+	OrderOldest GroupOrder = "oldest"
+	// OrderPath orders groups by the first path among their files
+	OrderPath GroupOrder = "path"
+)
+
+// ParseGroupOrder validates a group order from a request. An empty name selects
+// the default order.
+func ParseGroupOrder(name string) (GroupOrder, error) {
+	switch order := GroupOrder(strings.ToLower(strings.TrimSpace(name))); order {
+	case OrderDefault, OrderWastedSpace, OrderFileCount, OrderNewest, OrderOldest, OrderPath:
+		return order, nil
+	default:
+		return "", fmt.Errorf("unknown sort order %q (expected wasted-space, file-count, newest, oldest or path)", name)
+	}
+}
+
+// orderBy returns the ORDER BY clause of a query grouping image_files for the order,
+// or def for the default order. The key columns break ties so pages stay stable.
+func (o GroupOrder) orderBy(def, key string) string {
+	switch o {
+	case OrderWastedSpace:
+		return "SUM(size) - MAX(size) DESC, " + key
+	case OrderFileCount:
+		return "count(*) DESC, MAX(size) DESC, " + key
+	case OrderNewest:
+		return "MAX(mod_time) DESC, " + key
+	case OrderOldest:
+		return "MIN(mod_time), " + key
+	case OrderPath:
+		return "MIN(path), " + key
+	}
+	return def
+}
+
+// groupSummary holds the properties of a group that orders compare
+type groupSummary struct {
+	wasted  int64 // total size minus the largest file, which would be kept
+	files   int
+	size    int64
+	newest  time.Time
+	oldest  time.Time
+	path    string
+	hash    string
+	members domain.DuplicateGroup
+}
+
+// summarizeGroup computes the ordering properties of a group
+func summarizeGroup(g domain.DuplicateGroup) groupSummary {
+	s := groupSummary{files: len(g.Files), size: g.Size, hash: g.Hash, members: g}
+	var total, largest int64
+	for i, f := range g.Files {
+		total += f.Size
+		largest = max(largest, f.Size)
+		if i == 0 || f.ModTime.After(s.newest) {
+			s.newest = f.ModTime
+		}
+		if i == 0 || f.ModTime.Before(s.oldest) {
+			s.oldest = f.ModTime
+		}
+		if i == 0 || f.Path < s.path {
+			s.path = f.Path
+		}
+	}
+	s.wasted = total - largest
+	return s
+}
+
+// sortGroups reorders groups built in memory by the order, like orderBy does for
+// groups built by SQL. The default order leaves them as they are.
+func sortGroups(groups []domain.DuplicateGroup, order GroupOrder) {
+	if order == OrderDefault {
+		return
+	}
+	summaries := make([]groupSummary, len(groups))
+	for i, g := range groups {
+		summaries[i] = summarizeGroup(g)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		switch order {
+		case OrderWastedSpace:
+			if a.wasted != b.wasted {
+				return a.wasted > b.wasted
+			}
+		case OrderFileCount:
+			if a.files != b.files {
+				return a.files > b.files
+			}
+			if a.size != b.size {
+				return a.size > b.size
+			}
+		case OrderNewest:
+			if !a.newest.Equal(b.newest) {
+				return a.newest.After(b.newest)
+			}
+		case OrderOldest:
+			if !a.oldest.Equal(b.oldest) {
+				return a.oldest.Before(b.oldest)
+			}
+		case OrderPath:
+			if a.path != b.path {
+				return a.path < b.path
+			}
+		}
+		return a.hash < b.hash
+	})
+	for i, s := range summaries {
+		groups[i] = s.members
+	}
+}
@@ -0,0 +1,97 @@
+package imaging
+
+import (
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestParseGroupOrder(t *testing.T) {
+	for in, want := range map[string]GroupOrder{"": OrderDefault, "wasted-space": OrderWastedSpace, " Newest ": OrderNewest, "path": OrderPath} {
+		if got, err := ParseGroupOrder(in); err != nil || got != want {
+			t.Errorf("ParseGroupOrder(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseGroupOrder("size; DROP TABLE"); err == nil {
+		t.Error("unknown orders should be rejected")
+	}
+}
+
+// orderTestGroups are three groups: a has the largest files, b the most files and
+// the most wasted space, c the newest file and the first path
+func orderTestGroups() []domain.DuplicateGroup {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	file := func(path string, size int64, days int) domain.ImageFile {
+		return domain.ImageFile{Path: path, Size: size, ModTime: day.AddDate(0, 0, days)}
+	}
+	return []domain.DuplicateGroup{
+		{Hash: "a", Size: 900, Files: []domain.ImageFile{file("/p/m.jpg", 900, 1), file("/p/n.jpg", 900, 2)}},
+		{Hash: "b", Size: 500, Files: []domain.ImageFile{file("/p/x.jpg", 500, 0), file("/p/y.jpg", 500, 3), file("/p/z.jpg", 500, 4)}},
+		{Hash: "c", Size: 100, Files: []domain.ImageFile{file("/p/a.jpg", 100, 9), file("/p/b.jpg", 100, 5)}},
+	}
+}
+
+func groupHashes(groups []domain.DuplicateGroup) string {
+	var s string
+	for _, g := range groups {
+		s += g.Hash
+	}
+	return s
+}
+
+func TestSortGroups(t *testing.T) {
+	cases := map[GroupOrder]string{
+		OrderDefault:     "abc",
+		OrderWastedSpace: "bac",
+		OrderFileCount:   "bac",
+		OrderNewest:      "cba",
+		OrderOldest:      "bac",
+		OrderPath:        "cab",
+	}
+	for order, want := range cases {
+		groups := orderTestGroups()
+		sortGroups(groups, order)
+		if got := groupHashes(groups); got != want {
+			t.Errorf("sortGroups(%q) = %s, want %s", order, got, want)
+		}
+	}
+}
+
+func TestFindDuplicatesPaginatedOrder(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	for _, g := range orderTestGroups() {
+		for _, f := range g.Files {
+			f.Hash, f.HashAlgo = g.Hash, "sha256"
+			if err := db.Create(&f).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// The SQL order must agree with the in-memory one used by the other modes
+	for _, order := range []GroupOrder{OrderDefault, OrderWastedSpace, OrderFileCount, OrderNewest, OrderOldest, OrderPath} {
+		groups := orderTestGroups()
+		sortGroups(groups, order)
+		found, total, _, err := FindDuplicatesPaginated(db, DuplicateFilter{}, order, 0, 10)
+		if err != nil || total != 3 {
+			t.Fatalf("FindDuplicatesPaginated(%q) = %d groups, %v", order, total, err)
+		}
+		if got, want := groupHashes(found), groupHashes(groups); got != want {
+			t.Errorf("FindDuplicatesPaginated(%q) = %s, want %s", order, got, want)
+		}
+
+		// Pages of one group each add up to the same order and totals
+		var paged []domain.DuplicateGroup
+		for offset := 0; offset < 4; offset++ {
+			page, pageTotal, pageFiles, err := FindDuplicatesPaginated(db, DuplicateFilter{}, order, offset, 1)
+			if err != nil || pageTotal != 3 || pageFiles != len(groups[0].Files)+len(groups[1].Files)+len(groups[2].Files) {
+				t.Fatalf("page %d of %q: %d groups, %d files, %v", offset, order, pageTotal, pageFiles, err)
+			}
+			paged = append(paged, page...)
+		}
+		if got, want := groupHashes(paged), groupHashes(groups); got != want {
+			t.Errorf("paged FindDuplicatesPaginated(%q) = %s, want %s", order, got, want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewFilter restricts exact duplicate lookups to groups of one review status
+type ReviewFilter string
+
+const (
+	ReviewAll        ReviewFilter = ""
+	ReviewUnreviewed ReviewFilter = "unreviewed"
+	ReviewReviewed   ReviewFilter = domain.ReviewReviewed
+	ReviewDeferred   ReviewFilter = domain.ReviewDeferred
+)
+
+var ErrInvalidReviewStatus = errors.New("review status must be reviewed, deferred or unreviewed")
+
+// reviewExistsCond matches the image_files rows of groups with a review of the
+// status given as its argument
+const reviewExistsCond = "EXISTS (SELECT 1 FROM group_reviews gr WHERE gr.hash_algo = image_files.hash_algo AND gr.hash = image_files.hash AND gr.size = image_files.size AND gr.status = ?)"
+
+// ParseReviewFilter validates a review filter from a request. An empty name
+// selects groups of any status.
+func ParseReviewFilter(name string) (ReviewFilter, error) {
+	switch review := ReviewFilter(strings.ToLower(strings.TrimSpace(name))); review {
+	case ReviewAll, ReviewUnreviewed, ReviewReviewed, ReviewDeferred:
+		return review, nil
+	default:
+		return "", fmt.Errorf("unknown review status %q (expected unreviewed, reviewed or deferred)", name)
+	}
+}
+
+// apply adds the review condition to a query on image_files
+func (r ReviewFilter) apply(db *gorm.DB) *gorm.DB {
+	switch r {
+	case ReviewUnreviewed:
+		return db.Where("NOT EXISTS (SELECT 1 FROM group_reviews gr WHERE gr.hash_algo = image_files.hash_algo AND gr.hash = image_files.hash AND gr.size = image_files.size)")
+	case ReviewReviewed, ReviewDeferred:
+		return db.Where(reviewExistsCond, string(r))
+	}
+	return db
+}
+
+// SetGroupReview stores the review status of the exact duplicate group of the files
+// with the given content hash; "unreviewed" or an empty status removes it. It reports
+// whether there is such a group with more than one file on disk.
+func SetGroupReview(db *gorm.DB, hash, status string) (bool, error) {
+	switch status {
+	case "", string(ReviewUnreviewed), domain.ReviewReviewed, domain.ReviewDeferred:
+	default:
+		return false, ErrInvalidReviewStatus
+	}
+	group, err := FindGroup(db, hash)
+	if err != nil || group == nil || len(group.Files) < 2 {
+		return false, err
+	}
+
+	review := domain.GroupReview{HashAlgo: group.Files[0].HashAlgo, Hash: group.Hash, Size: group.Size, Status: status}
+	if status == "" || status == string(ReviewUnreviewed) {
+		err = db.Where("hash_algo = ? AND hash = ? AND size = ?", review.HashAlgo, review.Hash, review.Size).
+			Delete(&domain.GroupReview{}).Error
+		return err == nil, err
+	}
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash_algo"}, {Name: "hash"}, {Name: "size"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "updated_at"}),
+	}).Create(&review).Error
+	return err == nil, err
+}
+
+// GroupReviews returns the review status of each of the exact duplicate groups that
+// has one, by the index of the group
+func GroupReviews(db *gorm.DB, groups []domain.DuplicateGroup) (map[int]string, error) {
+	statuses := make(map[int]string)
+	if len(groups) == 0 {
+		return statuses, nil
+	}
+	hashes := make([]string, len(groups))
+	for i, g := range groups {
+		hashes[i] = g.Hash
+	}
+	var reviews []domain.GroupReview
+	if err := db.Where("hash IN ?", hashes).Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	byKey := make(map[duplicateKey]string, len(reviews))
+	for _, r := range reviews {
+		byKey[duplicateKey{r.HashAlgo, r.Hash, r.Size}] = r.Status
+	}
+	for i, g := range groups {
+		if len(g.Files) == 0 {
+			continue
+		}
+		if status, ok := byKey[duplicateKey{g.Files[0].HashAlgo, g.Hash, g.Size}]; ok {
+			statuses[i] = status
+		}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,87 @@
+package imaging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestGroupReviews(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.GroupReview{})
+	dir := filepath.ToSlash(t.TempDir())
+	var files []domain.ImageFile
+	for _, hash := range []string{"a", "b", "c"} {
+		for _, copy := range []string{"1", "2"} {
+			path := dir + "/" + hash + copy + ".jpg"
+			if err := os.WriteFile(path, []byte(hash), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			files = append(files, domain.ImageFile{Path: path, Size: 1, Hash: hash, HashAlgo: "md5"})
+		}
+	}
+	if err := db.Create(&files).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	listed := func(review ReviewFilter) []string {
+		groups, total, _, err := FindDuplicatesPaginated(db, DuplicateFilter{Review: review}, OrderDefault, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var hashes []string
+		for _, g := range groups {
+			hashes = append(hashes, g.Hash)
+		}
+		sort.Strings(hashes)
+		if total != len(hashes) {
+			t.Fatalf("total groups %d, listed %v", total, hashes)
+		}
+		return hashes
+	}
+
+	for hash, status := range map[string]string{"a": domain.ReviewReviewed, "b": domain.ReviewDeferred} {
+		if found, err := SetGroupReview(db, hash, status); !found || err != nil {
+			t.Fatalf("SetGroupReview(%s) = %v, %v", hash, found, err)
+		}
+	}
+	// A new status replaces the old one
+	if _, err := SetGroupReview(db, "b", domain.ReviewReviewed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SetGroupReview(db, "c", "done"); !errors.Is(err, ErrInvalidReviewStatus) {
+		t.Fatalf("invalid status: got %v", err)
+	}
+	if found, err := SetGroupReview(db, "z", domain.ReviewReviewed); found || err != nil {
+		t.Fatalf("unknown group: got %v, %v", found, err)
+	}
+
+	if got := listed(ReviewUnreviewed); len(got) != 1 || got[0] != "c" {
+		t.Errorf("unreviewed: got %v", got)
+	}
+	if got := listed(ReviewReviewed); len(got) != 2 {
+		t.Errorf("reviewed: got %v", got)
+	}
+	if got := listed(ReviewDeferred); len(got) != 0 {
+		t.Errorf("deferred: got %v", got)
+	}
+
+	if _, err := SetGroupReview(db, "a", string(ReviewUnreviewed)); err != nil {
+		t.Fatal(err)
+	}
+	groups, _, _, _ := FindDuplicatesPaginated(db, DuplicateFilter{}, OrderPath, 0, 100)
+	reviews, err := GroupReviews(db, groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews) != 1 || reviews[1] != domain.ReviewReviewed {
+		t.Errorf("GroupReviews = %v, want only group b reviewed", reviews)
+	}
+
+	if _, err := ParseReviewFilter("later"); err == nil {
+		t.Error("an unknown review filter should be rejected")
+	}
+}
@@ -0,0 +1,54 @@
+package imaging
+
+import (
+	"context"
+	"sync"
+)
+
+// hashInParallel runs fn over files on a pool of numWorkers goroutines and streams the
+// results. The returned channel is closed once every file has been processed, so the
+// caller can range over it from a single goroutine that owns all database writes.
+// Workers wait at a checkpoint before each file, so a paused scan stops picking up new
+// files and a cancelled one drains without processing the rest.
+func hashInParallel(ctx context.Context, files []fileInfo, numWorkers int, fn func(fileInfo) hashResult) <-chan hashResult {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	jobs := make(chan fileInfo, numWorkers*2)
+	results := make(chan hashResult, numWorkers*2)
+
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for fi := range jobs {
+				if checkpoint(ctx) != nil {
+					continue
+				}
+				results <- fn(fi)
+			}
+		}()
+	}
+
+	// Close results channel when all workers finish
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Send jobs to workers until done or cancelled
+	go func() {
+		defer close(jobs)
+		for _, fi := range files {
+			select {
+			case jobs <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
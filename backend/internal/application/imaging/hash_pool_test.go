@@ -0,0 +1,48 @@
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestHashInParallelProcessesEveryFile(t *testing.T) {
+	for _, workers := range []int{0, 1, 8} {
+		files := make([]fileInfo, 100)
+		for i := range files {
+			files[i] = fileInfo{path: fmt.Sprintf("file-%d", i)}
+		}
+
+		seen := make(map[string]bool)
+		for r := range hashInParallel(context.Background(), files, workers, func(fi fileInfo) hashResult {
+			return hashResult{fi: fi, hash: "h-" + fi.path}
+		}) {
+			if r.hash != "h-"+r.fi.path {
+				t.Fatalf("workers=%d: result mismatch for %s", workers, r.fi.path)
+			}
+			seen[r.fi.path] = true
+		}
+		if len(seen) != len(files) {
+			t.Fatalf("workers=%d: got %d results, want %d", workers, len(seen), len(files))
+		}
+	}
+}
+
+func TestHashInParallelStopsOnCancel(t *testing.T) {
+	files := make([]fileInfo, 1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	processed := 0
+	for range hashInParallel(ctx, files, 4, func(fi fileInfo) hashResult {
+		return hashResult{fi: fi}
+	}) {
+		processed++
+		if processed == 10 {
+			cancel()
+		}
+	}
+	if processed >= len(files) {
+		t.Fatalf("expected cancellation to skip remaining files, processed %d", processed)
+	}
+}
@@ -0,0 +1,55 @@
+package imaging
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// RunScan performs a full scan of all gallery folders synchronously, outside of a
+// ScanManager: every accessible folder is scanned, missing files are cleaned up and the deferred
+// hashing passes are completed. progress receives every progress message if non-nil,
+// with the completion and remaining time estimated against the previous run's file
+// count or a counting pre-pass.
+// Used by headless CLI runs.
+func RunScan(ctx context.Context, db *gorm.DB, opts ScanOptions, progress func(string)) error {
+	progressChan := make(chan string, 200)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for msg := range progressChan {
+			if progress != nil {
+				progress(msg)
+			}
+		}
+	}()
+
+	dirs, unavailable := splitRoots(db, (&ScanManager{db: db}).getGalleryDirs(), progressChan, opts)
+	ctx = withUnavailableRoots(ctx, unavailable)
+	estimate := &scanProgress{}
+	estimate.expect(len(dirs), expectedFiles(ctx, db, dirs, opts))
+	ctx = withScanProgress(ctx, estimate)
+
+	var scanErr error
+	for _, dir := range dirs {
+		if err := scanRoot(ctx, db, dir, progressChan, opts); err != nil && scanErr == nil {
+			scanErr = err
+		}
+	}
+	// After the scan, so records of moved files are moved rather than removed
+	cleanupMissingFiles(ctx, db, "", progressChan, opts)
+	if opts.SizePrefilter && ctx.Err() == nil {
+		hashSizeCollisions(ctx, db, progressChan, opts)
+	}
+	if opts.TwoStageHash && ctx.Err() == nil {
+		resolvePrefixCollisions(ctx, db, progressChan, opts)
+	}
+
+	close(progressChan)
+	<-done
+
+	if scanErr != nil {
+		return scanErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,62 @@
+package imaging
+
+import (
+	"fmt"
+	"strings"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// LocalHost names this server in host filters; its own files have an empty host,
+// while those reported by agents have the agent name
+const LocalHost = "local"
+
+// HostFiles is a machine files are indexed on and their number
+type HostFiles struct {
+	Host  string // empty for this server
+	Files int64
+}
+
+// Hosts returns the machines with indexed files, this server first, then the agents
+// by name
+func Hosts(db *gorm.DB) ([]HostFiles, error) {
+	var hosts []HostFiles
+	err := db.Model(&domain.ImageFile{}).
+		Select("host, COUNT(*) AS files").
+		Group("host").
+		Order("host").
+		Scan(&hosts).Error
+	return hosts, err
+}
+
+// ParseHosts parses a comma-separated list of host names from a request, such as
+// "laptop,nas". "local" stands for this server and maps to the empty host.
+func ParseHosts(s string) ([]string, error) {
+	var hosts []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			continue
+		case name == LocalHost:
+			name = ""
+		case !ValidAgentName(name):
+			return nil, fmt.Errorf("invalid host %q", name)
+		}
+		hosts = append(hosts, name)
+	}
+	return hosts, nil
+}
+
+// hostExistsCond matches the files of a group that also has a copy on the given host
+const hostExistsCond = "EXISTS (SELECT 1 FROM image_files hf WHERE hf.hash_algo = image_files.hash_algo AND hf.hash = image_files.hash AND hf.size = image_files.size AND hf.host = ? AND hf.deleted_at IS NULL)"
+
+// applyHosts keeps the exact duplicate groups with copies on every one of the hosts
+func applyHosts(db *gorm.DB, hosts []string) *gorm.DB {
+	for _, host := range hosts {
+		db = db.Where(hostExistsCond, host)
+	}
+	return db
+}
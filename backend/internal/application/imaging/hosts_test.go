@@ -0,0 +1,68 @@
+package imaging
+
+import (
+	"reflect"
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestHostsFilter(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	for _, f := range []domain.ImageFile{
+		// On this server and both agents
+		{Path: "/photos/a.jpg", Hash: "a", Size: 1},
+		{Path: "agent://laptop/home/a.jpg", Hash: "a", Size: 1, Host: "laptop"},
+		{Path: "agent://nas/volume1/a.jpg", Hash: "a", Size: 1, Host: "nas"},
+		// On the laptop twice
+		{Path: "agent://laptop/home/b.jpg", Hash: "b", Size: 2, Host: "laptop"},
+		{Path: "agent://laptop/home/b copy.jpg", Hash: "b", Size: 2, Host: "laptop"},
+		// On the laptop and this server
+		{Path: "/photos/c.jpg", Hash: "c", Size: 3},
+		{Path: "agent://laptop/home/c.jpg", Hash: "c", Size: 3, Host: "laptop"},
+	} {
+		if err := db.Create(&f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groupHashes := func(spec string) []string {
+		t.Helper()
+		hosts, err := ParseHosts(spec)
+		if err != nil {
+			t.Fatalf("ParseHosts(%q): %v", spec, err)
+		}
+		groups, _, _, err := FindDuplicatesPaginated(db, DuplicateFilter{Hosts: hosts}, OrderWastedSpace, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var hashes []string
+		for _, g := range groups {
+			hashes = append(hashes, g.Hash)
+		}
+		return hashes
+	}
+	for spec, want := range map[string][]string{
+		"":             {"c", "a", "b"},
+		"laptop":       {"c", "a", "b"},
+		"laptop, nas":  {"a"},
+		"local,laptop": {"c", "a"},
+		"nas,local":    {"a"},
+	} {
+		if got := groupHashes(spec); !reflect.DeepEqual(got, want) {
+			t.Errorf("groups on %q = %v, want %v", spec, got, want)
+		}
+	}
+
+	hosts, err := Hosts(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HostFiles{{"", 2}, {"laptop", 4}, {"nas", 1}}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("Hosts = %v, want %v", hosts, want)
+	}
+	if _, err := ParseHosts("laptop,bad/host"); err == nil {
+		t.Error("an invalid host should be rejected")
+	}
+}
@@ -0,0 +1,51 @@
+package imaging
+
+import (
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// notIgnoredCond excludes the image_files rows of exact duplicate groups marked as
+// not duplicates
+const notIgnoredCond = "NOT EXISTS (SELECT 1 FROM ignored_groups ig WHERE ig.hash_algo = image_files.hash_algo AND ig.hash = image_files.hash AND ig.size = image_files.size)"
+
+// IgnoreGroup marks the exact duplicate group of the files with the given content
+// hash as not duplicates, so it no longer appears among the duplicates. It returns
+// nil when there is no such group with more than one file on disk.
+func IgnoreGroup(db *gorm.DB, hash string) (*domain.IgnoredGroup, error) {
+	group, err := FindGroup(db, hash)
+	if err != nil || group == nil || len(group.Files) < 2 {
+		return nil, err
+	}
+	ignored := domain.IgnoredGroup{
+		HashAlgo: group.Files[0].HashAlgo,
+		Hash:     group.Hash,
+		Size:     group.Size,
+		Path:     group.Files[0].Path,
+		Files:    len(group.Files),
+	}
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash_algo"}, {Name: "hash"}, {Name: "size"}},
+		DoUpdates: clause.AssignmentColumns([]string{"path", "files"}),
+	}).Create(&ignored).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ignored, nil
+}
+
+// ListIgnoredGroups returns the groups marked as not duplicates, most recent first
+func ListIgnoredGroups(db *gorm.DB) ([]domain.IgnoredGroup, error) {
+	var groups []domain.IgnoredGroup
+	err := db.Order("created_at DESC, id DESC").Find(&groups).Error
+	return groups, err
+}
+
+// UnignoreGroup removes the mark of an ignored group, so it is listed among the
+// duplicates again. It reports whether the group was ignored.
+func UnignoreGroup(db *gorm.DB, id uint) (bool, error) {
+	result := db.Delete(&domain.IgnoredGroup{}, id)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,77 @@
+package imaging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestIgnoredGroups(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.Deletion{}, &domain.IgnoredGroup{})
+	dir := filepath.ToSlash(t.TempDir())
+	var files []domain.ImageFile
+	for name, hash := range map[string]string{"a1.jpg": "a", "a2.jpg": "a", "b1.jpg": "b", "b2.jpg": "b"} {
+		path := dir + "/" + name
+		if err := os.WriteFile(path, []byte(hash), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, domain.ImageFile{Path: path, Size: 1, Hash: hash, HashAlgo: "md5"})
+	}
+	if err := db.Create(&files).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	listed := func(filter DuplicateFilter) []string {
+		groups, total, _, err := FindDuplicatesPaginated(db, filter, OrderDefault, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var hashes []string
+		for _, g := range groups {
+			hashes = append(hashes, g.Hash)
+		}
+		if total != len(hashes) {
+			t.Fatalf("total groups %d, listed %v", total, hashes)
+		}
+		return hashes
+	}
+
+	ignored, err := IgnoreGroup(db, "a")
+	if err != nil || ignored == nil || ignored.Files != 2 || ignored.HashAlgo != "md5" {
+		t.Fatalf("IgnoreGroup = %+v, %v", ignored, err)
+	}
+	// Ignoring twice keeps one mark
+	if _, err := IgnoreGroup(db, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if missing, err := IgnoreGroup(db, "z"); missing != nil || err != nil {
+		t.Fatalf("unknown group: got %+v, %v", missing, err)
+	}
+
+	if got := listed(DuplicateFilter{HideIgnored: true}); len(got) != 1 || got[0] != "b" {
+		t.Errorf("ignored group hidden: got %v", got)
+	}
+	if got := listed(DuplicateFilter{}); len(got) != 2 {
+		t.Errorf("ignored groups shown without HideIgnored: got %v", got)
+	}
+	stats, err := BuildDuplicateStats(db, DuplicateFilter{HideIgnored: true})
+	if err != nil || stats.DuplicateGroups != 1 {
+		t.Errorf("stats: got %+v, %v", stats, err)
+	}
+
+	groups, err := ListIgnoredGroups(db)
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("ListIgnoredGroups = %+v, %v", groups, err)
+	}
+	if found, err := UnignoreGroup(db, groups[0].ID); !found || err != nil {
+		t.Fatalf("UnignoreGroup = %v, %v", found, err)
+	}
+	if found, _ := UnignoreGroup(db, groups[0].ID); found {
+		t.Error("a group can only be unignored once")
+	}
+	if got := listed(DuplicateFilter{HideIgnored: true}); len(got) != 2 {
+		t.Errorf("after unignoring: got %v", got)
+	}
+}
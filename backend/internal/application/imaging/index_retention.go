@@ -0,0 +1,120 @@
+package imaging
+
+import (
+	"log/slog"
+	"time"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Records removed from the index are soft-deleted: scans, the watcher and background
+// sync look them up along with the live ones and revive the record of a file that is
+// back, keeping its hashes, metadata and OCR results. The purge job removes them for
+// good once the retention period is over.
+
+// reviveRecord undeletes the soft-deleted record of a file that is back on disk
+func reviveRecord(db *gorm.DB, record *domain.ImageFile) error {
+	if !record.DeletedAt.Valid {
+		return nil
+	}
+	if err := db.Unscoped().Model(&domain.ImageFile{}).Where("id = ?", record.ID).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	record.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+// PurgeDeletedFiles permanently removes the records soft-deleted before cutoff, with
+// their metadata and OCR results, and returns how many were removed
+func PurgeDeletedFiles(db *gorm.DB, cutoff time.Time) (int64, error) {
+	var purged int64
+	// Chunked to stay below the bound parameter limit of SQLite
+	const chunkSize = 500
+	for {
+		var ids []uint
+		if err := db.Unscoped().Model(&domain.ImageFile{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Order("id").Limit(chunkSize).Pluck("id", &ids).Error; err != nil {
+			return purged, err
+		}
+		if len(ids) == 0 {
+			return purged, nil
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			classifications := tx.Model(&domain.OcrClassification{}).Select("id").Where("image_file_id IN ?", ids)
+			if err := tx.Where("classification_id IN (?)", classifications).Delete(&domain.OcrBoundingBox{}).Error; err != nil {
+				return err
+			}
+			for _, dependent := range []any{&domain.OcrClassification{}, &domain.OcrLlmRecognition{}, &domain.ImageMetadata{}} {
+				if err := tx.Where("image_file_id IN ?", ids).Delete(dependent).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Unscoped().Where("id IN ?", ids).Delete(&domain.ImageFile{}).Error
+		})
+		if err != nil {
+			return purged, err
+		}
+		purged += int64(len(ids))
+	}
+}
+
+// IndexPurgeJob periodically purges the records soft-deleted longer than the
+// retention period ago
+type IndexPurgeJob struct {
+	db        *gorm.DB
+	retention time.Duration
+	interval  time.Duration
+	stopCh    chan struct{}
+}
+
+// NewIndexPurgeJob creates a purge job keeping removed records for retention
+func NewIndexPurgeJob(db *gorm.DB, retention, interval time.Duration) *IndexPurgeJob {
+	if interval == 0 {
+		interval = 1 * time.Hour // Default: run every hour
+	}
+	return &IndexPurgeJob{
+		db:        db,
+		retention: retention,
+		interval:  interval,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the periodic purge, running the first one right away
+func (j *IndexPurgeJob) Start() {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		slog.Info("Index purge started", "retention", j.retention, "interval", j.interval)
+
+		j.runPurge()
+		for {
+			select {
+			case <-ticker.C:
+				j.runPurge()
+			case <-j.stopCh:
+				slog.Info("Index purge stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the purge job
+func (j *IndexPurgeJob) Stop() {
+	close(j.stopCh)
+}
+
+// runPurge performs a single purge
+func (j *IndexPurgeJob) runPurge() {
+	purged, err := PurgeDeletedFiles(j.db, time.Now().Add(-j.retention))
+	if err != nil {
+		slog.Error("Index purge failed", "error", err)
+	} else if purged > 0 {
+		slog.Info("Index purge completed", "records", purged)
+	}
+}
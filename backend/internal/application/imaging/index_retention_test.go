@@ -0,0 +1,94 @@
+package imaging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestRemovedRecordIsRevivedAndPurged(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.ImageMetadata{}, &domain.OcrClassification{}, &domain.OcrBoundingBox{}, &domain.OcrLlmRecognition{})
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.jpg")
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	write := func() {
+		if err := os.WriteFile(path, []byte("photo"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scan := func() []string {
+		progress := make(chan string, 100)
+		if err := scanDirectory(context.Background(), db, dir, progress, ScanOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := cleanupMissingFiles(context.Background(), db, dir, progress, ScanOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		close(progress)
+		var msgs []string
+		for msg := range progress {
+			msgs = append(msgs, msg)
+		}
+		return msgs
+	}
+
+	write()
+	scan()
+	var original domain.ImageFile
+	if err := db.First(&original).Error; err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&domain.ImageMetadata{ImageFileID: original.ID})
+
+	// The file disappears, e.g. with its NAS unmounted: the record is only hidden
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	scan()
+	var live, all int64
+	db.Model(&domain.ImageFile{}).Count(&live)
+	db.Unscoped().Model(&domain.ImageFile{}).Count(&all)
+	if live != 0 || all != 1 {
+		t.Fatalf("after removal: %d live and %d stored records, want 0 and 1", live, all)
+	}
+
+	// It comes back unchanged: the record is revived without hashing
+	write()
+	msgs := scan()
+	var revived domain.ImageFile
+	if err := db.First(&revived).Error; err != nil {
+		t.Fatalf("record was not revived: %v", err)
+	}
+	if revived.ID != original.ID || revived.Hash != original.Hash {
+		t.Errorf("revived record = %+v, want the original %+v", revived, original)
+	}
+	if joined := strings.Join(msgs, "\n"); !strings.Contains(joined, "Skipping (cached)") {
+		t.Errorf("the returning file was hashed again:\n%s", joined)
+	}
+
+	// Once removed longer than the retention, the record and its metadata are purged
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	scan()
+	if n, err := PurgeDeletedFiles(db, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("purge within the retention = %d, %v", n, err)
+	}
+	if n, err := PurgeDeletedFiles(db, time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Fatalf("purge after the retention = %d, %v", n, err)
+	}
+	var metadata int64
+	db.Unscoped().Model(&domain.ImageFile{}).Count(&all)
+	db.Model(&domain.ImageMetadata{}).Count(&metadata)
+	if all != 0 || metadata != 0 {
+		t.Errorf("after the purge: %d records and %d metadata rows left", all, metadata)
+	}
+}
@@ -0,0 +1,171 @@
+package imaging
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// An index snapshot is gzip-compressed JSON lines: a header, then one indexed file
+// per line in the JSON form of domain.ImageFile. It holds the hashes of the files, so
+// an index moved to another machine or restored from a backup is not hashed again.
+const (
+	snapshotFormat    = "image-dedup-index"
+	snapshotVersion   = 1
+	snapshotBatchSize = 500
+)
+
+// ErrInvalidSnapshot is returned for a file that is not an index snapshot
+var ErrInvalidSnapshot = errors.New("not an image-dedup index snapshot")
+
+// snapshotHeader is the first line of a snapshot
+type snapshotHeader struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Files      int64     `json:"files"`
+}
+
+// importColumns are the columns an import writes to the existing record of a path:
+// those a scan writes, and the protected flag pinned on the other machine
+var importColumns = append(append([]string(nil), scanColumns...), "protected")
+
+// ExportIndex writes a snapshot of the indexed files to w and returns their number.
+// Records removed from the index are left out.
+func ExportIndex(db *gorm.DB, w io.Writer) (int64, error) {
+	var total int64
+	if err := db.Model(&domain.ImageFile{}).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	if err := enc.Encode(snapshotHeader{Format: snapshotFormat, Version: snapshotVersion, ExportedAt: time.Now().UTC(), Files: total}); err != nil {
+		return 0, err
+	}
+
+	var written int64
+	var batch []domain.ImageFile
+	err := db.FindInBatches(&batch, snapshotBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, file := range batch {
+			if err := enc.Encode(file); err != nil {
+				return err
+			}
+		}
+		written += int64(len(batch))
+		return nil
+	}).Error
+	if err != nil {
+		return written, err
+	}
+	return written, zw.Close()
+}
+
+// ImportIndex reads a snapshot written by ExportIndex into the index and returns the
+// number of files imported. Each path is passed through remap (if non-nil) first, for
+// folders mounted elsewhere on this machine. A file already indexed under the same path
+// takes the values of the snapshot; other files are kept. The import is all or nothing.
+func ImportIndex(db *gorm.DB, r io.Reader, remap func(path string) string) (imported int64, err error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	defer zr.Close()
+	dec := json.NewDecoder(bufio.NewReader(zr))
+
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil || header.Format != snapshotFormat {
+		return 0, ErrInvalidSnapshot
+	}
+	if header.Version > snapshotVersion {
+		return 0, fmt.Errorf("index snapshot version %d is newer than this build supports (%d)", header.Version, snapshotVersion)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var txErr error
+		imported, txErr = importFiles(tx, dec, remap)
+		return txErr
+	})
+	if err != nil {
+		return 0, err
+	}
+	return imported, nil
+}
+
+// importFiles upserts the files following the header of a snapshot
+func importFiles(db *gorm.DB, dec *json.Decoder, remap func(path string) string) (int64, error) {
+	var imported int64
+	batch := make([]domain.ImageFile, 0, snapshotBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "path"}},
+			DoUpdates: clause.AssignmentColumns(importColumns),
+		}).Create(&batch).Error
+		if err != nil {
+			return err
+		}
+		imported += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		var file domain.ImageFile
+		if err := dec.Decode(&file); err == io.EOF {
+			break
+		} else if err != nil {
+			return imported, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		if file.Path == "" {
+			continue
+		}
+		// IDs are assigned by this database
+		file.ID = 0
+		file.DeletedAt = gorm.DeletedAt{}
+		if remap != nil {
+			file.Path = remap(file.Path)
+		}
+		batch = append(batch, file)
+		if len(batch) == snapshotBatchSize {
+			if err := flush(); err != nil {
+				return imported, err
+			}
+		}
+	}
+	return imported, flush()
+}
+
+// PrefixRemap returns a remap for ImportIndex replacing the path prefixes given as
+// "from=to" pairs, the first matching one winning
+func PrefixRemap(pairs []string) (func(path string) string, error) {
+	type prefix struct{ from, to string }
+	prefixes := make([]prefix, 0, len(pairs))
+	for _, pair := range pairs {
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || from == "" {
+			return nil, fmt.Errorf("invalid path remap %q (expected from=to)", pair)
+		}
+		from, to = filepath.ToSlash(from), filepath.ToSlash(to)
+		prefixes = append(prefixes, prefix{strings.TrimSuffix(from, "/"), strings.TrimSuffix(to, "/")})
+	}
+	return func(path string) string {
+		for _, p := range prefixes {
+			if path == p.from || strings.HasPrefix(path, p.from+"/") {
+				return p.to + path[len(p.from):]
+			}
+		}
+		return path
+	}, nil
+}
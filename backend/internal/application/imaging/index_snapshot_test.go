@@ -0,0 +1,63 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestIndexSnapshotRoundTrip(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	source := openTestDB(t, &domain.ImageFile{})
+	for _, f := range []domain.ImageFile{
+		{Path: "/mnt/nas/a.jpg", Hash: "ha", HashAlgo: "sha256", Size: 10, ModTime: modTime, PHash: "ff00", Protected: true},
+		{Path: "/mnt/nas/b.jpg", Hash: "hb", HashAlgo: "sha256", Size: 20, ModTime: modTime},
+		{Path: "/mnt/nas/gone.jpg", Hash: "hg", HashAlgo: "sha256", Size: 30, ModTime: modTime},
+	} {
+		if err := source.Create(&f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	source.Where("path = ?", "/mnt/nas/gone.jpg").Delete(&domain.ImageFile{})
+
+	var snapshot bytes.Buffer
+	if n, err := ExportIndex(source, &snapshot); err != nil || n != 2 {
+		t.Fatalf("ExportIndex = %d, %v", n, err)
+	}
+
+	// The target already indexed one of the files, with an outdated hash
+	target := openTestDB(t, &domain.ImageFile{})
+	target.Create(&domain.ImageFile{Path: "/volume1/b.jpg", Hash: "old", HashAlgo: "md5", Size: 20, ModTime: modTime})
+	target.Create(&domain.ImageFile{Path: "/volume1/own.jpg", Hash: "ho", HashAlgo: "md5", Size: 5, ModTime: modTime})
+	remap, err := PrefixRemap([]string{"/mnt/nas/=/volume1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := ImportIndex(target, bytes.NewReader(snapshot.Bytes()), remap); err != nil || n != 2 {
+		t.Fatalf("ImportIndex = %d, %v", n, err)
+	}
+
+	var files []domain.ImageFile
+	target.Order("path").Find(&files)
+	var got []string
+	for _, f := range files {
+		got = append(got, f.Path+":"+f.Hash+":"+f.PHash)
+		if f.Path == "/volume1/a.jpg" && (!f.Protected || !f.ModTime.Equal(modTime)) {
+			t.Errorf("imported record = %+v", f)
+		}
+	}
+	if want := "/volume1/a.jpg:ha:ff00 /volume1/b.jpg:hb: /volume1/own.jpg:ho:"; strings.Join(got, " ") != want {
+		t.Errorf("index after import = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	if _, err := ImportIndex(target, strings.NewReader("not gzip"), nil); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("importing garbage = %v, want ErrInvalidSnapshot", err)
+	}
+	if _, err := PrefixRemap([]string{"/mnt/nas"}); err == nil {
+		t.Error("a remap without = should be rejected")
+	}
+}
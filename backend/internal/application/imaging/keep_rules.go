@@ -0,0 +1,196 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// KeepPolicy decides which file of a duplicate group survives a batch deletion
+type KeepPolicy string
+
+const (
+	KeepOldest            KeepPolicy = "keep-oldest"                // earliest modification time
+	KeepNewest            KeepPolicy = "keep-newest"                // latest modification time
+	KeepShortestPath      KeepPolicy = "keep-shortest-path"         // fewest characters in the path
+	KeepLargestResolution KeepPolicy = "keep-largest-resolution"    // most pixels, from extracted metadata
+	KeepInPriorityDir     KeepPolicy = "keep-in-priority-directory" // first of Directories containing the file
+	KeepFirstScanDir      KeepPolicy = "keep-first-scan-dir"        // first gallery folder, in the order they were added
+)
+
+var (
+	ErrUnknownKeepPolicy    = errors.New("unknown keep policy")
+	ErrNoPriorityDirectory  = errors.New("keep-in-priority-directory needs at least one directory")
+	errKeepRulesUnspecified = errors.New("no keep rules")
+)
+
+// KeepRule is a keep policy with its parameters
+type KeepRule struct {
+	Policy      KeepPolicy
+	Directories []string // KeepInPriorityDir: directories from highest to lowest priority; set by ResolveKeepRules for KeepFirstScanDir
+}
+
+// ValidateKeepRules checks that every rule names a known policy with the parameters it needs
+func ValidateKeepRules(rules []KeepRule) error {
+	if len(rules) == 0 {
+		return errKeepRulesUnspecified
+	}
+	for _, rule := range rules {
+		switch rule.Policy {
+		case KeepOldest, KeepNewest, KeepShortestPath, KeepLargestResolution, KeepFirstScanDir:
+		case KeepInPriorityDir:
+			if len(rule.Directories) == 0 {
+				return ErrNoPriorityDirectory
+			}
+		default:
+			return fmt.Errorf("%w: %q", ErrUnknownKeepPolicy, rule.Policy)
+		}
+	}
+	return nil
+}
+
+// SelectKeeper returns the index of the file to keep. Rules are applied in order,
+// each one narrowing the candidates that tied under the previous rules; remaining
+// ties keep the file with the lexicographically smallest path, so the choice is
+// stable across runs. pixels maps file IDs to their resolution (see Resolutions);
+// files without an entry rank lowest for KeepLargestResolution.
+func SelectKeeper(files []domain.ImageFile, rules []KeepRule, pixels map[uint]int64) int {
+	candidates := make([]int, len(files))
+	for i := range files {
+		candidates[i] = i
+	}
+
+	for _, rule := range rules {
+		if len(candidates) <= 1 {
+			break
+		}
+		scores := make([]int64, len(candidates))
+		best := int64(0)
+		for k, i := range candidates {
+			scores[k] = rule.score(files[i], pixels)
+			if k == 0 || scores[k] < best {
+				best = scores[k]
+			}
+		}
+		narrowed := candidates[:0:0]
+		for k, i := range candidates {
+			if scores[k] == best {
+				narrowed = append(narrowed, i)
+			}
+		}
+		candidates = narrowed
+	}
+
+	keep := candidates[0]
+	for _, i := range candidates[1:] {
+		if files[i].Path < files[keep].Path {
+			keep = i
+		}
+	}
+	return keep
+}
+
+// score ranks a file under the rule; lower is better
+func (r KeepRule) score(f domain.ImageFile, pixels map[uint]int64) int64 {
+	switch r.Policy {
+	case KeepOldest:
+		return f.ModTime.UnixNano()
+	case KeepNewest:
+		return -f.ModTime.UnixNano()
+	case KeepShortestPath:
+		return int64(utf8.RuneCountInString(f.Path))
+	case KeepLargestResolution:
+		return -pixels[f.ID]
+	case KeepInPriorityDir, KeepFirstScanDir:
+		path := filepath.ToSlash(f.Path)
+		for i, dir := range r.Directories {
+			if strings.HasPrefix(path, strings.TrimSuffix(filepath.ToSlash(dir), "/")+"/") {
+				return int64(i)
+			}
+		}
+		return int64(len(r.Directories))
+	}
+	return 0
+}
+
+// ResolveKeepRules fills in the parameters that depend on the server state:
+// KeepFirstScanDir ranks files by the gallery folders in the order they were added
+func (sm *ScanManager) ResolveKeepRules(rules []KeepRule) []KeepRule {
+	resolved := make([]KeepRule, len(rules))
+	for i, rule := range rules {
+		if rule.Policy == KeepFirstScanDir {
+			var folders []domain.GalleryFolder
+			sm.db.Order("created_at").Find(&folders)
+			rule.Directories = make([]string, len(folders))
+			for j, f := range folders {
+				rule.Directories[j] = f.Path
+			}
+		}
+		resolved[i] = rule
+	}
+	return resolved
+}
+
+// needsResolution reports whether any rule ranks files by resolution
+func needsResolution(rules []KeepRule) bool {
+	for _, rule := range rules {
+		if rule.Policy == KeepLargestResolution {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolutions returns width*height of the files in groups that have extracted metadata,
+// keyed by file ID. It only queries the database when a rule needs it.
+func Resolutions(db *gorm.DB, groups []domain.DuplicateGroup, rules []KeepRule) map[uint]int64 {
+	pixels := make(map[uint]int64)
+	if !needsResolution(rules) {
+		return pixels
+	}
+
+	var ids []uint
+	for _, g := range groups {
+		for _, f := range g.Files {
+			ids = append(ids, f.ID)
+		}
+	}
+	for id, dims := range loadDimensions(db, ids) {
+		pixels[id] = int64(dims.Width) * int64(dims.Height)
+	}
+	return pixels
+}
+
+// loadDimensions returns the width and height of the files that have extracted
+// metadata, keyed by file ID
+func loadDimensions(db *gorm.DB, ids []uint) map[uint]domain.Dimensions {
+	dims := make(map[uint]domain.Dimensions, len(ids))
+	for id, m := range loadMetadata(db, ids, "width", "height") {
+		dims[id] = domain.Dimensions{Width: m.Width, Height: m.Height}
+	}
+	return dims
+}
+
+// loadMetadata returns the given columns of the extracted metadata of files, keyed
+// by file ID. Files without metadata are missing from the map.
+func loadMetadata(db *gorm.DB, ids []uint, columns ...string) map[uint]domain.ImageMetadata {
+	metadata := make(map[uint]domain.ImageMetadata, len(ids))
+	columns = append([]string{"image_file_id"}, columns...)
+	// Chunked to stay below the bound parameter limit of SQLite
+	const chunkSize = 500
+	for start := 0; start < len(ids); start += chunkSize {
+		end := min(start+chunkSize, len(ids))
+		var rows []domain.ImageMetadata
+		db.Select(columns).Where("image_file_id IN ?", ids[start:end]).Find(&rows)
+		for _, m := range rows {
+			metadata[m.ImageFileID] = m
+		}
+	}
+	return metadata
+}
@@ -0,0 +1,54 @@
+package imaging
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestSelectKeeper(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	files := []domain.ImageFile{
+		{ID: 1, Path: "/photos/inbox/2024/a.jpg", ModTime: base.Add(2 * time.Hour)},
+		{ID: 2, Path: "/photos/archive/a.jpg", ModTime: base},
+		{ID: 3, Path: "/photos/b.jpg", ModTime: base},
+		{ID: 4, Path: "/backup/photos/a.jpg", ModTime: base.Add(time.Hour)},
+	}
+	pixels := map[uint]int64{1: 12_000_000, 4: 12_000_000, 2: 3_000_000}
+
+	cases := []struct {
+		name  string
+		rules []KeepRule
+		want  uint
+	}{
+		{"oldest ties broken by path", []KeepRule{{Policy: KeepOldest}}, 2},
+		{"newest", []KeepRule{{Policy: KeepNewest}}, 1},
+		{"shortest path", []KeepRule{{Policy: KeepShortestPath}}, 3},
+		{"largest resolution then oldest", []KeepRule{{Policy: KeepLargestResolution}, {Policy: KeepOldest}}, 4},
+		{"priority directory", []KeepRule{{Policy: KeepInPriorityDir, Directories: []string{"/photos/inbox/", "/backup"}}}, 1},
+		{"priority directory falls through", []KeepRule{{Policy: KeepInPriorityDir, Directories: []string{"/nowhere"}}, {Policy: KeepNewest}}, 1},
+		{"no rules", nil, 4},
+	}
+	for _, c := range cases {
+		if got := files[SelectKeeper(files, c.rules, pixels)].ID; got != c.want {
+			t.Errorf("%s: kept file %d, want %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestValidateKeepRules(t *testing.T) {
+	if err := ValidateKeepRules([]KeepRule{{Policy: KeepOldest}, {Policy: KeepInPriorityDir, Directories: []string{"/a"}}, {Policy: KeepFirstScanDir}}); err != nil {
+		t.Errorf("valid rules rejected: %v", err)
+	}
+	if err := ValidateKeepRules([]KeepRule{{Policy: "keep-random"}}); !errors.Is(err, ErrUnknownKeepPolicy) {
+		t.Errorf("unknown policy: got %v", err)
+	}
+	if err := ValidateKeepRules([]KeepRule{{Policy: KeepInPriorityDir}}); !errors.Is(err, ErrNoPriorityDirectory) {
+		t.Errorf("priority without directories: got %v", err)
+	}
+	if err := ValidateKeepRules(nil); err == nil {
+		t.Error("empty rules accepted")
+	}
+}
@@ -0,0 +1,83 @@
+package imaging
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultLocationRadius is the radius of a location filter given without one, km
+	DefaultLocationRadius = 10
+	// MaxLocationRadius bounds the radius of a location filter, km: the flat-earth
+	// distance of locationCond is rough enough for a city or a region, not for a continent
+	MaxLocationRadius = 1000
+	// kmPerDegree is the length of a degree of latitude
+	kmPerDegree = 111.2
+)
+
+// LocationFilter selects the photos shot within a radius around a point
+type LocationFilter struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+// locationCond matches the image_files rows of exact duplicate groups with a copy
+// shot within the radius given by its arguments, see LocationFilter.args. Distances
+// are measured on a plane tangent to the point, which needs no trigonometry in SQL.
+const locationCond = "EXISTS (SELECT 1 FROM image_files lf JOIN image_metadata lm ON lm.image_file_id = lf.id" +
+	" WHERE lf.hash_algo = image_files.hash_algo AND lf.hash = image_files.hash AND lf.size = image_files.size AND lf.deleted_at IS NULL" +
+	" AND lm.gps_latitude IS NOT NULL AND lm.gps_longitude IS NOT NULL" +
+	" AND (lm.gps_latitude - ?) * (lm.gps_latitude - ?) + (lm.gps_longitude - ?) * (lm.gps_longitude - ?) * ? <= ?)"
+
+// args returns the arguments of locationCond: the point, the squared cosine of its
+// latitude, which shrinks the degrees of longitude, and the squared radius in degrees
+func (l LocationFilter) args() []any {
+	cos := math.Cos(l.Latitude * math.Pi / 180)
+	radius := l.RadiusKm / kmPerDegree
+	return []any{l.Latitude, l.Latitude, l.Longitude, l.Longitude, cos * cos, radius * radius}
+}
+
+// applyLocation keeps the exact duplicate groups with a copy shot near the location,
+// if any
+func applyLocation(db *gorm.DB, location *LocationFilter) *gorm.DB {
+	if location == nil {
+		return db
+	}
+	return db.Where(locationCond, location.args()...)
+}
+
+// ParseLocation parses a location filter from a request: near is "latitude,longitude"
+// in decimal degrees, such as "43.70,7.26", and radius is in km, DefaultLocationRadius
+// when empty. An empty near selects groups shot anywhere, or without GPS data.
+func ParseLocation(near, radius string) (*LocationFilter, error) {
+	if strings.TrimSpace(near) == "" {
+		if strings.TrimSpace(radius) != "" {
+			return nil, fmt.Errorf("radius %q given without a location", radius)
+		}
+		return nil, nil
+	}
+	lat, lon, ok := strings.Cut(near, ",")
+	if !ok {
+		return nil, fmt.Errorf("invalid location %q (expected latitude,longitude)", near)
+	}
+	location := &LocationFilter{RadiusKm: DefaultLocationRadius}
+	var err error
+	if location.Latitude, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err != nil || !(math.Abs(location.Latitude) <= 90) {
+		return nil, fmt.Errorf("invalid latitude %q", lat)
+	}
+	if location.Longitude, err = strconv.ParseFloat(strings.TrimSpace(lon), 64); err != nil || !(math.Abs(location.Longitude) <= 180) {
+		return nil, fmt.Errorf("invalid longitude %q", lon)
+	}
+	if r := strings.TrimSpace(radius); r != "" {
+		location.RadiusKm, err = strconv.ParseFloat(r, 64)
+		if err != nil || !(location.RadiusKm > 0) || location.RadiusKm > MaxLocationRadius {
+			return nil, fmt.Errorf("invalid radius %q (expected 0 to %d km)", radius, MaxLocationRadius)
+		}
+	}
+	return location, nil
+}
@@ -0,0 +1,79 @@
+package imaging
+
+import (
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestParseLocation(t *testing.T) {
+	location, err := ParseLocation("43.70, 7.26", "")
+	if err != nil || *location != (LocationFilter{Latitude: 43.70, Longitude: 7.26, RadiusKm: DefaultLocationRadius}) {
+		t.Errorf("ParseLocation(43.70, 7.26) = %+v, %v", location, err)
+	}
+	if location, err := ParseLocation("-33.86,151.21", "2.5"); err != nil || location.RadiusKm != 2.5 {
+		t.Errorf("ParseLocation with a radius = %+v, %v", location, err)
+	}
+	if location, err := ParseLocation("", ""); err != nil || location != nil {
+		t.Errorf("ParseLocation() = %+v, %v", location, err)
+	}
+	for _, tc := range [][2]string{{"43.70", ""}, {"91,0", ""}, {"0,181", ""}, {"NaN,NaN", ""}, {"0,NaN", ""}, {"Inf,0", ""}, {"0,-Inf", ""}, {"a,b", ""}, {"0,0", "0"}, {"0,0", "-5"}, {"0,0", "5000"}, {"", "10"}} {
+		if _, err := ParseLocation(tc[0], tc[1]); err == nil {
+			t.Errorf("ParseLocation(%q, %q) should fail", tc[0], tc[1])
+		}
+	}
+}
+
+func TestFindDuplicatesNearLocation(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.ImageMetadata{})
+	files := []domain.ImageFile{
+		{Path: "/albums/nice/port.jpg", Size: 100, Hash: "a", HashAlgo: "sha256"},
+		{Path: "/albums/best/port.jpg", Size: 100, Hash: "a", HashAlgo: "sha256"},
+		{Path: "/albums/monaco/casino.jpg", Size: 200, Hash: "b", HashAlgo: "sha256"},
+		{Path: "/backup/casino.jpg", Size: 200, Hash: "b", HashAlgo: "sha256"},
+		{Path: "/albums/paris/tower.jpg", Size: 300, Hash: "c", HashAlgo: "sha256"},
+		{Path: "/backup/tower.jpg", Size: 300, Hash: "c", HashAlgo: "sha256"},
+		{Path: "/scans/letter.jpg", Size: 400, Hash: "d", HashAlgo: "sha256"},
+		{Path: "/backup/letter.jpg", Size: 400, Hash: "d", HashAlgo: "sha256"},
+	}
+	if err := db.Create(&files).Error; err != nil {
+		t.Fatal(err)
+	}
+	coord := func(v float64) *float64 { return &v }
+	metadata := []domain.ImageMetadata{
+		// Nice
+		{ImageFileID: files[0].ID, GPSLatitude: coord(43.695), GPSLongitude: coord(7.285)},
+		// Monaco, about 14 km from Nice; only one copy has its metadata extracted yet
+		{ImageFileID: files[2].ID, GPSLatitude: coord(43.739), GPSLongitude: coord(7.427)},
+		// Paris
+		{ImageFileID: files[4].ID, GPSLatitude: coord(48.858), GPSLongitude: coord(2.294)},
+		{ImageFileID: files[5].ID, GPSLatitude: coord(48.858), GPSLongitude: coord(2.294)},
+		// No GPS data
+		{ImageFileID: files[6].ID},
+	}
+	if err := db.Create(&metadata).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	near := func(radius float64) []string {
+		filter := DuplicateFilter{Location: &LocationFilter{Latitude: 43.70, Longitude: 7.26, RadiusKm: radius}}
+		groups, _, _, err := FindDuplicatesPaginated(db, filter, OrderPath, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var hashes []string
+		for _, g := range groups {
+			if len(g.Files) != 2 {
+				t.Errorf("group %s has %d files, want both copies", g.Hash, len(g.Files))
+			}
+			hashes = append(hashes, g.Hash)
+		}
+		return hashes
+	}
+	if got := near(5); len(got) != 1 || got[0] != "a" {
+		t.Errorf("groups within 5 km of Nice = %v, want [a]", got)
+	}
+	if got := near(20); len(got) != 2 {
+		t.Errorf("groups within 20 km of Nice = %v, want Nice and Monaco", got)
+	}
+}
@@ -0,0 +1,27 @@
+package imaging
+
+import "strings"
+
+// maxShortPath is the length from which Windows needs the extended-length form of an
+// absolute path: MAX_PATH (260) minus room for an 8.3 file name in a directory
+const maxShortPath = 248
+
+// extendedLengthPath returns the \\?\ form of an absolute, cleaned Windows path
+// that is too long for the Win32 API, or the path itself if it is short enough or
+// already in that form. UNC paths (\\server\share\...) become \\?\UNC\server\share\...
+// so the server and share are kept instead of being read as a drive-relative path.
+func extendedLengthPath(path string) string {
+	if len(path) < maxShortPath || strings.HasPrefix(path, `\\?\`) || strings.HasPrefix(path, `\\.\`) {
+		return path
+	}
+	if strings.HasPrefix(path, `\\`) {
+		return `\\?\UNC\` + path[2:]
+	}
+	return `\\?\` + path
+}
+
+// LongPath returns the form of path that file operations accept even when it
+// exceeds MAX_PATH on Windows. Elsewhere the path is returned unchanged.
+func LongPath(path string) string {
+	return longPath(path)
+}
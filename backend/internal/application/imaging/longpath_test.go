@@ -0,0 +1,26 @@
+package imaging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtendedLengthPath(t *testing.T) {
+	long := strings.Repeat(`very long folder name\`, 12) + "photo.jpg"
+	tests := []struct {
+		path, want string
+	}{
+		{`C:\Photos\a.jpg`, `C:\Photos\a.jpg`},
+		{`\\nas\photos\a.jpg`, `\\nas\photos\a.jpg`},
+		{`C:\` + long, `\\?\C:\` + long},
+		{`\\nas\photos\` + long, `\\?\UNC\nas\photos\` + long},
+		{`\\?\C:\` + long, `\\?\C:\` + long},
+		{`\\?\UNC\nas\photos\` + long, `\\?\UNC\nas\photos\` + long},
+		{`\\.\pipe\` + long, `\\.\pipe\` + long},
+	}
+	for _, tt := range tests {
+		if got := extendedLengthPath(tt.path); got != tt.want {
+			t.Errorf("extendedLengthPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package imaging
+
+import "path/filepath"
+
+// longPath makes path absolute with backslashes and adds the extended-length prefix
+// when it exceeds MAX_PATH, since the prefix turns off the Win32 path normalization
+func longPath(path string) string {
+	abs, err := filepath.Abs(filepath.FromSlash(path))
+	if err != nil {
+		return path
+	}
+	return extendedLengthPath(abs)
+}
@@ -0,0 +1,92 @@
+package imaging
+
+import (
+	"image/color"
+	"io"
+	"sync"
+
+	"image-toolkit/internal/infrastructure/imagecodec"
+)
+
+// hashBufferSize is the size of the read buffers content hashing reuses. Large reads
+// keep the number of syscalls down on network shares.
+const hashBufferSize = 1 << 20
+
+// hashBuffers holds the read buffers of the hashing workers, so hashing a file does
+// not allocate
+var hashBuffers = sync.Pool{New: func() any {
+	buf := make([]byte, hashBufferSize)
+	return &buf
+}}
+
+// hashReader feeds r to hasher through a pooled read buffer
+func hashReader(hasher io.Writer, r io.Reader) error {
+	buf := hashBuffers.Get().(*[]byte)
+	defer hashBuffers.Put(buf)
+	// Hides the WriterTo of *os.File, which would copy through a buffer of its own
+	_, err := io.CopyBuffer(hasher, struct{ io.Reader }{r}, *buf)
+	return err
+}
+
+// MemoryLimit bounds the memory the hashing workers of a scan hold at once for decoded
+// images, so that many workers decoding huge TIFFs or panoramas do not run the
+// process out of memory. Workers wait for each other while the images being decoded
+// would exceed the limit. A nil MemoryLimit does not limit.
+type MemoryLimit struct {
+	limit int64
+	mu    sync.Mutex
+	freed *sync.Cond
+	used  int64
+}
+
+// NewMemoryLimit returns a limit of bytes for decoded images, nil for no limit
+func NewMemoryLimit(bytes int64) *MemoryLimit {
+	if bytes <= 0 {
+		return nil
+	}
+	m := &MemoryLimit{limit: bytes}
+	m.freed = sync.NewCond(&m.mu)
+	return m
+}
+
+// acquire blocks until n bytes fit in the limit and reserves them. An image larger
+// than the whole limit is decoded alone. The returned func releases the bytes.
+func (m *MemoryLimit) acquire(n int64) (release func()) {
+	if m == nil {
+		return func() {}
+	}
+	if n > m.limit {
+		n = m.limit
+	}
+	m.mu.Lock()
+	for m.used > 0 && m.used+n > m.limit {
+		m.freed.Wait()
+	}
+	m.used += n
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		m.used -= n
+		m.mu.Unlock()
+		m.freed.Broadcast()
+	}
+}
+
+// decodedSize estimates the memory of the decoded image at path from its header,
+// falling back to the file size when the header cannot be read
+func decodedSize(path string, fileSize int64) int64 {
+	cfg, err := imagecodec.DecodeConfig(path)
+	if err != nil || cfg.Width <= 0 || cfg.Height <= 0 {
+		return fileSize
+	}
+	bytesPerPixel := int64(4)
+	switch cfg.ColorModel {
+	case color.RGBA64Model, color.NRGBA64Model:
+		bytesPerPixel = 8
+	case color.GrayModel:
+		bytesPerPixel = 1
+	case color.Gray16Model:
+		bytesPerPixel = 2
+	}
+	return int64(cfg.Width) * int64(cfg.Height) * bytesPerPixel
+}
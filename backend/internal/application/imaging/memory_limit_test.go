@@ -0,0 +1,62 @@
+package imaging
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMemoryLimit(t *testing.T) {
+	if NewMemoryLimit(0) != nil {
+		t.Fatal("a zero limit should not limit")
+	}
+	// A nil limit never blocks
+	(*MemoryLimit)(nil).acquire(1 << 40)()
+
+	limit := NewMemoryLimit(100)
+	var held, peak atomic.Int64
+	var wg sync.WaitGroup
+	for _, n := range []int64{60, 60, 30, 500, 10} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := limit.acquire(n)
+			if n > 100 {
+				n = 100
+			}
+			now := held.Add(n)
+			for {
+				p := peak.Load()
+				if now <= p || peak.CompareAndSwap(p, now) {
+					break
+				}
+			}
+			held.Add(-n)
+			release()
+		}()
+	}
+	wg.Wait()
+	if peak.Load() > 100 {
+		t.Errorf("%d bytes held at once, limit 100", peak.Load())
+	}
+}
+
+func TestDecodedSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, image.NewNRGBA(image.Rect(0, 0, 30, 20)))
+	f.Close()
+	if got := decodedSize(path, 1); got != 30*20*4 {
+		t.Errorf("decodedSize = %d, want %d", got, 30*20*4)
+	}
+	if got := decodedSize(filepath.Join(t.TempDir(), "missing.png"), 123); got != 123 {
+		t.Errorf("decodedSize of a missing file = %d, want the file size", got)
+	}
+}
@@ -2,7 +2,7 @@ package imaging
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -100,10 +100,11 @@ func (mm *MetadataManager) processUnextracted() {
 	mm.db.Raw(`
 		SELECT image_files.* FROM image_files
 		LEFT JOIN image_metadata ON image_metadata.image_file_id = image_files.id
-		WHERE image_metadata.id IS NULL
-		   OR image_metadata.updated_at < image_files.updated_at
+		WHERE image_files.is_video = ? AND image_files.deleted_at IS NULL
+		  AND (image_metadata.id IS NULL
+		   OR image_metadata.updated_at < image_files.updated_at)
 		ORDER BY image_files.id
-	`).Scan(&images)
+	`, false).Scan(&images)
 
 	total := len(images)
 	if total == 0 {
@@ -117,7 +118,7 @@ func (mm *MetadataManager) processUnextracted() {
 	mm.progress = fmt.Sprintf("Extracting metadata: 0/%d", total)
 	mm.mu.Unlock()
 
-	log.Printf("Metadata extraction: %d images to process", total)
+	slog.Info("Metadata extraction started", "images", total)
 
 	type metadataResult struct {
 		imageFileID uint
@@ -136,7 +137,7 @@ func (mm *MetadataManager) processUnextracted() {
 			for img := range jobs {
 				meta, err := extractMetadata(img.Path)
 				if err != nil {
-					log.Printf("Metadata extraction failed for %s: %v", img.Path, err)
+					slog.Warn("Metadata extraction failed", "path", img.Path, "error", err)
 					continue
 				}
 				meta.ImageFileID = img.ID
@@ -190,7 +191,7 @@ func (mm *MetadataManager) processUnextracted() {
 		mm.upsertBatch(batch)
 	}
 
-	log.Printf("Metadata extraction complete: %d images processed", count)
+	slog.Info("Metadata extraction complete", "images", count)
 }
 
 // upsertBatch inserts or updates a batch of metadata records.
@@ -0,0 +1,106 @@
+package imaging
+
+import (
+	"os"
+	"time"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+
+	"gorm.io/gorm"
+)
+
+// moveKey identifies a file by the size and modification time a move or rename keeps
+type moveKey struct {
+	size    int64
+	modTime int64 // Unix nanoseconds
+}
+
+func newMoveKey(size int64, modTime time.Time) moveKey {
+	return moveKey{size: size, modTime: modTime.UnixNano()}
+}
+
+// moveSources holds indexed files whose path no longer exists on disk, by size and
+// modification time: the possible origins of new files that were moved or renamed.
+// Moving such a row to the new path keeps its ID, and with it the metadata, OCR
+// results and hashes recorded for the file. Removed records are included, so a
+// folder that comes back under another mount point is not hashed again.
+type moveSources map[moveKey][]domain.ImageFile
+
+// findMoveSources looks up the move sources of files not in the index yet
+func findMoveSources(db *gorm.DB, files []fileInfo) moveSources {
+	sources := make(moveSources)
+	if len(files) == 0 {
+		return sources
+	}
+
+	wanted := make(map[moveKey]bool, len(files))
+	seen := make(map[int64]bool)
+	var sizes []int64
+	for _, fi := range files {
+		wanted[newMoveKey(fi.size, fi.modTime)] = true
+		if !seen[fi.size] {
+			seen[fi.size] = true
+			sizes = append(sizes, fi.size)
+		}
+	}
+
+	const dbBatchSize = 500
+	for i := 0; i < len(sizes); i += dbBatchSize {
+		end := i + dbBatchSize
+		if end > len(sizes) {
+			end = len(sizes)
+		}
+		var rows []domain.ImageFile
+		db.Unscoped().Where("size IN ? AND hash <> ''", sizes[i:end]).Find(&rows)
+		for _, row := range rows {
+			key := newMoveKey(row.Size, row.ModTime)
+			if !wanted[key] {
+				continue
+			}
+			if objectstore.IsRemote(row.Path) {
+				continue
+			}
+			if _, err := os.Lstat(row.Path); !os.IsNotExist(err) {
+				continue
+			}
+			sources[key] = append(sources[key], row)
+		}
+	}
+	return sources
+}
+
+// claim returns the move source of a file, verified by hashing the file with the
+// algorithm of each candidate, and removes it so no source is moved twice. Returns
+// nil if the file was not moved from an indexed path.
+func (m moveSources) claim(fi fileInfo, throttle *Throttle) *domain.ImageFile {
+	key := newMoveKey(fi.size, fi.modTime)
+	candidates := m[key]
+	hashes := make(map[string]string)
+	for i, c := range candidates {
+		hash, ok := hashes[c.HashAlgo]
+		if !ok {
+			algo, err := ParseContentHashAlgorithm(c.HashAlgo)
+			if err == nil {
+				hash, err = calculateFileHash(fi.path, algo, throttle)
+			}
+			if err != nil {
+				hash = ""
+			}
+			hashes[c.HashAlgo] = hash
+		}
+		if hash == "" || hash != c.Hash {
+			continue
+		}
+		m[key] = append(candidates[:i:i], candidates[i+1:]...)
+		return &c
+	}
+	return nil
+}
+
+// moveFile points an index row at the new path of a moved or renamed file, reviving
+// it if it was removed
+func moveFile(db *gorm.DB, source *domain.ImageFile, fi fileInfo) error {
+	return db.Unscoped().Model(&domain.ImageFile{}).Where("id = ?", source.ID).
+		Updates(map[string]interface{}{"path": fi.normalizedPath, "is_symlink": fi.symlink, "device": fi.device, "inode": fi.inode, "deleted_at": nil}).Error
+}
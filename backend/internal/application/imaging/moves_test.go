@@ -0,0 +1,78 @@
+package imaging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestScanDirectoryKeepsRecordOfMovedFile(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	dir := t.TempDir()
+	write := func(name, content string, modTime time.Time) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	write("a.jpg", "photo", modTime)
+	write("b.jpg", "other", modTime)
+
+	scan := func() {
+		progress := make(chan string, 100)
+		ctx := context.Background()
+		if err := scanDirectory(ctx, db, dir, progress, ScanOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := cleanupMissingFiles(ctx, db, dir, progress, ScanOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	record := func(name string) domain.ImageFile {
+		var f domain.ImageFile
+		if err := db.Where("path = ?", filepath.ToSlash(filepath.Join(dir, name))).First(&f).Error; err != nil {
+			t.Fatalf("record of %s: %v", name, err)
+		}
+		return f
+	}
+
+	scan()
+	before := record("a.jpg")
+
+	// a.jpg is renamed into a subfolder; b.jpg is replaced by a different file of the
+	// same size and modification time under a new name
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "a.jpg"), filepath.Join(dir, "sub", "a.jpg")); err != nil {
+		t.Fatal(err)
+	}
+	otherID := record("b.jpg").ID
+	if err := os.Remove(filepath.Join(dir, "b.jpg")); err != nil {
+		t.Fatal(err)
+	}
+	write("c.jpg", "OTHER", modTime)
+
+	scan()
+	after := record(filepath.Join("sub", "a.jpg"))
+	if after.ID != before.ID || after.Hash != before.Hash {
+		t.Fatalf("moved file: got record %d (%s), want %d (%s)", after.ID, after.Hash, before.ID, before.Hash)
+	}
+	if c := record("c.jpg"); c.ID == otherID {
+		t.Fatal("a file with different content must not take over the record of a removed file")
+	}
+
+	var count int64
+	db.Model(&domain.ImageFile{}).Count(&count)
+	if count != 2 {
+		t.Fatalf("got %d records, want 2", count)
+	}
+}
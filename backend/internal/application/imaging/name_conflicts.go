@@ -0,0 +1,100 @@
+package imaging
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// NameConflictReport lists the file names shared by files of different content, the
+// opposite of duplicates: merging the folders of two cameras that both wrote an
+// IMG_0001.JPG would overwrite one picture with another
+type NameConflictReport struct {
+	GeneratedAt    time.Time      `json:"generatedAt"`
+	TotalConflicts int            `json:"totalConflicts"`
+	TotalFiles     int            `json:"totalFiles"`
+	Conflicts      []NameConflict `json:"conflicts"`
+}
+
+// NameConflict is a file name with the files carrying it, at least two of them
+// different in content
+type NameConflict struct {
+	Name     string             `json:"name"`
+	Versions int                `json:"versions"` // number of different contents
+	Files    []NameConflictFile `json:"files"`
+}
+
+// NameConflictFile is a file of a name conflict. Files of the same Version are
+// identical, i.e. duplicates of each other.
+type NameConflictFile struct {
+	ID      uint      `json:"id"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Version int       `json:"version"` // 1-based, in order of the first path of each content
+}
+
+// BuildNameConflictReport collects the files matching the filter whose names, compared
+// case-insensitively, are shared by files of different content, ordered by name
+func BuildNameConflictReport(db *gorm.DB, filter DuplicateFilter) (*NameConflictReport, error) {
+	byName := make(map[string][]domain.ImageFile)
+	var batch []domain.ImageFile
+	err := filter.apply(db.Model(&domain.ImageFile{})).
+		Select("id, path, size, hash, hash_algo, mod_time").
+		FindInBatches(&batch, 1000, func(*gorm.DB, int) error {
+			for _, f := range batch {
+				name := strings.ToLower(path.Base(f.Path))
+				byName[name] = append(byName[name], f)
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NameConflictReport{GeneratedAt: time.Now(), Conflicts: []NameConflict{}}
+	for _, files := range byName {
+		if len(files) < 2 {
+			continue
+		}
+		sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+		conflict := NameConflict{Name: path.Base(files[0].Path), Files: make([]NameConflictFile, len(files))}
+		versions := make(map[string]int)
+		for i, f := range files {
+			key := versionKey(f)
+			version, ok := versions[key]
+			if !ok {
+				version = len(versions) + 1
+				versions[key] = version
+			}
+			conflict.Files[i] = NameConflictFile{ID: f.ID, Path: f.Path, Size: f.Size, ModTime: f.ModTime, Version: version}
+		}
+		if len(versions) < 2 {
+			continue // all copies are identical: duplicates, not a conflict
+		}
+		conflict.Versions = len(versions)
+		sort.SliceStable(conflict.Files, func(i, j int) bool { return conflict.Files[i].Version < conflict.Files[j].Version })
+		report.Conflicts = append(report.Conflicts, conflict)
+		report.TotalFiles += len(conflict.Files)
+	}
+	report.TotalConflicts = len(report.Conflicts)
+	sort.Slice(report.Conflicts, func(i, j int) bool {
+		return strings.ToLower(report.Conflicts[i].Name) < strings.ToLower(report.Conflicts[j].Name)
+	})
+	return report, nil
+}
+
+// versionKey tells files of different content apart. Files left without a content
+// hash had a size no other file shares (or a unique prefix), so they are unique.
+func versionKey(f domain.ImageFile) string {
+	if f.Hash == "" {
+		return "id:" + strconv.FormatUint(uint64(f.ID), 10)
+	}
+	return f.HashAlgo + ":" + f.Hash + ":" + strconv.FormatInt(f.Size, 10)
+}
@@ -0,0 +1,60 @@
+package imaging
+
+import (
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestBuildNameConflictReport(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	files := []domain.ImageFile{
+		// Two cameras, one name: a conflict, with a duplicate of the first picture
+		{Path: "/photos/canon/IMG_0001.JPG", Size: 100, Hash: "a", HashAlgo: "sha256"},
+		{Path: "/photos/backup/IMG_0001.JPG", Size: 100, Hash: "a", HashAlgo: "sha256"},
+		{Path: "/photos/iphone/img_0001.jpg", Size: 200, Hash: "b", HashAlgo: "sha256"},
+		// Same name and content: duplicates only
+		{Path: "/photos/a/cat.png", Size: 10, Hash: "c", HashAlgo: "sha256"},
+		{Path: "/photos/b/cat.png", Size: 10, Hash: "c", HashAlgo: "sha256"},
+		// Never fully hashed, told apart by their sizes
+		{Path: "/photos/a/dog.png", Size: 11},
+		{Path: "/photos/b/dog.png", Size: 12},
+		{Path: "/photos/a/unique.png", Size: 13, Hash: "d", HashAlgo: "sha256"},
+	}
+	if err := db.Create(&files).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := BuildNameConflictReport(db, DuplicateFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalConflicts != 2 || report.TotalFiles != 5 {
+		t.Fatalf("report has %d conflicts of %d files, want 2 of 5: %+v", report.TotalConflicts, report.TotalFiles, report.Conflicts)
+	}
+	dog, img := report.Conflicts[0], report.Conflicts[1]
+	if dog.Name != "dog.png" || dog.Versions != 2 {
+		t.Errorf("first conflict = %+v, want dog.png with 2 versions", dog)
+	}
+	if img.Name != "IMG_0001.JPG" || img.Versions != 2 {
+		t.Errorf("second conflict = %+v, want IMG_0001.JPG with 2 versions", img)
+	}
+	want := []struct {
+		path    string
+		version int
+	}{
+		{"/photos/backup/IMG_0001.JPG", 1},
+		{"/photos/canon/IMG_0001.JPG", 1},
+		{"/photos/iphone/img_0001.jpg", 2},
+	}
+	for i, w := range want {
+		if f := img.Files[i]; f.Path != w.path || f.Version != w.version {
+			t.Errorf("file %d = %s version %d, want %s version %d", i, f.Path, f.Version, w.path, w.version)
+		}
+	}
+
+	report, err = BuildNameConflictReport(db, DuplicateFilter{Dir: "/photos/a"})
+	if err != nil || report.TotalConflicts != 0 {
+		t.Errorf("conflicts under /photos/a = %+v, %v; want none", report, err)
+	}
+}
@@ -0,0 +1,138 @@
+package imaging
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// copySuffixes match what browsers, file managers and sync clients add to the name of
+// a copy, on the lowercase file name without its extension
+var copySuffixes = []*regexp.Regexp{
+	regexp.MustCompile(`\s*\(\d+\)$`),                                  // photo (1), photo(2): downloads, Google Drive
+	regexp.MustCompile(`\s*[-–—]\s*(?:copy|копия|kopie|copie|copia)$`), // photo - Copy: Windows Explorer
+	regexp.MustCompile(`[\s_]copy(?:\s\d+)?$`),                         // photo copy, photo copy 2: macOS Finder
+	regexp.MustCompile(`\s*\([^()]*conflicted copy[^()]*\)$`),          // photo (laptop's conflicted copy 2024-01-01): Dropbox, Nextcloud
+	regexp.MustCompile(`\.sync-conflict-\d{8}-\d{6}-[0-9a-z]+$`),       // photo.sync-conflict-20240101-120000-ABCDEFG: Syncthing
+	regexp.MustCompile(`[\s_-](?:edited|edit)$`),                       // photo-edited: Google Photos, editors
+}
+
+// copyPrefix matches the prefix Google Drive gives copies: "Copy of photo"
+var copyPrefix = regexp.MustCompile(`^copy of\s+`)
+
+// extensionAliases map the extensions spelled in two ways to one of them
+var extensionAliases = map[string]string{".jpeg": ".jpg", ".tif": ".tiff"}
+
+// copyName returns the name a file would have without the copy suffixes, lowercase,
+// e.g. "img_0001.jpg" for "IMG_0001 (1).JPG" and "IMG_0001 - Copy.jpeg"
+func copyName(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if alias, ok := extensionAliases[ext]; ok {
+		ext = alias
+	}
+	stem := strings.ToLower(strings.TrimSuffix(name, path.Ext(name)))
+	stem = copyPrefix.ReplaceAllString(stem, "")
+	for changed := true; changed; {
+		changed = false
+		for _, suffix := range copySuffixes {
+			if trimmed := suffix.ReplaceAllString(stem, ""); trimmed != stem && trimmed != "" {
+				stem, changed = trimmed, true
+			}
+		}
+	}
+	return stem + ext
+}
+
+// findNameCopyGroups groups the files of each directory whose names differ only by copy
+// suffixes, such as "photo.jpg", "photo (1).jpg" and "photo - Copy.jpg", whatever their
+// content: downloads and sync conflicts often leave copies that were recompressed or
+// slightly edited. The file with the shortest name, likely the original, comes first.
+// Similarity is 1 for files identical to it, the perceptual hash score for files of
+// another content, and 0 where the content cannot be compared. Groups are ordered by
+// their largest file size, descending.
+func findNameCopyGroups(db *gorm.DB, filter DuplicateFilter) ([]domain.DuplicateGroup, error) {
+	var files []domain.ImageFile
+	if err := filter.apply(db).Order("id").Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]domain.ImageFile)
+	for _, f := range files {
+		key := path.Join(path.Dir(f.Path), copyName(path.Base(f.Path)))
+		byName[key] = append(byName[key], f)
+	}
+
+	var groups []domain.DuplicateGroup
+	for key, members := range byName {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool {
+			a, b := path.Base(members[i].Path), path.Base(members[j].Path)
+			if len(a) != len(b) {
+				return len(a) < len(b)
+			}
+			return a < b
+		})
+		group := domain.DuplicateGroup{Hash: key, Files: members, Similarity: make([]float64, len(members))}
+		for i := range members {
+			group.Similarity[i] = copySimilarity(&members[0], &members[i])
+			group.Size = max(group.Size, members[i].Size)
+		}
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Size != groups[j].Size {
+			return groups[i].Size > groups[j].Size
+		}
+		return groups[i].Hash < groups[j].Hash
+	})
+	return groups, nil
+}
+
+// copySimilarity scores a copy against the original of its group
+func copySimilarity(original, file *domain.ImageFile) float64 {
+	if original.Hash != "" && original.Hash == file.Hash && original.HashAlgo == file.HashAlgo && original.Size == file.Size {
+		return 1
+	}
+	if original.PHash == "" || file.PHash == "" || fileHashAlgorithm(original) != fileHashAlgorithm(file) {
+		return 0
+	}
+	reference, err := parsePerceptualHash(original.PHash)
+	if err != nil {
+		return 0
+	}
+	hash, err := parsePerceptualHash(file.PHash)
+	if err != nil {
+		return 0
+	}
+	return similarityScore(orientedDistance(reference, parsePerceptualHashes(original.PHashXform), hash))
+}
+
+// FindNameCopiesPaginated finds groups of likely copies by name among the files
+// matching the filter in the given order, with pagination
+func FindNameCopiesPaginated(db *gorm.DB, filter DuplicateFilter, order GroupOrder, offset, limit int) ([]domain.DuplicateGroup, int, int, error) {
+	allGroups, err := findNameCopyGroups(db, filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	sortGroups(allGroups, order)
+
+	totalGroups := len(allGroups)
+	totalFiles := 0
+	for _, g := range allGroups {
+		totalFiles += len(g.Files)
+	}
+
+	if offset >= totalGroups {
+		return []domain.DuplicateGroup{}, totalGroups, totalFiles, nil
+	}
+	end := min(offset+limit, totalGroups)
+	return allGroups[offset:end], totalGroups, totalFiles, nil
+}
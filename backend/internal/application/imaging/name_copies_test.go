@@ -0,0 +1,79 @@
+package imaging
+
+import (
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestCopyName(t *testing.T) {
+	tests := map[string]string{
+		"IMG_0001.JPG":            "img_0001.jpg",
+		"IMG_0001 (1).JPG":        "img_0001.jpg",
+		"IMG_0001(2).jpeg":        "img_0001.jpg",
+		"IMG_0001 - Copy.jpg":     "img_0001.jpg",
+		"IMG_0001 - Copy (2).jpg": "img_0001.jpg",
+		"IMG_0001 — копия.jpg":    "img_0001.jpg",
+		"IMG_0001 copy 2.jpg":     "img_0001.jpg",
+		"Copy of IMG_0001.jpg":    "img_0001.jpg",
+		"IMG_0001-edited.jpg":     "img_0001.jpg",
+		"IMG_0001 (laptop's conflicted copy 2024-01-01).jpg": "img_0001.jpg",
+		"IMG_0001.sync-conflict-20240101-120000-ABCDEFG.jpg": "img_0001.jpg",
+		// Names that only look like copies are left alone
+		"(1).jpg":      "(1).jpg",
+		"IMG_0002.jpg": "img_0002.jpg",
+		"scan.tif":     "scan.tiff",
+	}
+	for name, want := range tests {
+		if got := copyName(name); got != want {
+			t.Errorf("copyName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFindNameCopiesPaginated(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	files := []domain.ImageFile{
+		{Path: "/photos/beach (1).jpg", Size: 90, Hash: "b", HashAlgo: "sha256", PHash: formatPerceptualHash(0b1111)},
+		{Path: "/photos/beach.jpg", Size: 100, Hash: "a", HashAlgo: "sha256", PHash: formatPerceptualHash(0b1011)},
+		{Path: "/photos/beach - Copy.jpg", Size: 100, Hash: "a", HashAlgo: "sha256"},
+		{Path: "/photos/beach.png", Size: 500, Hash: "c", HashAlgo: "sha256"},
+		// Same name in another folder: not a copy
+		{Path: "/backup/beach (1).jpg", Size: 90, Hash: "b", HashAlgo: "sha256"},
+		{Path: "/photos/dune.jpg", Size: 10, Hash: "d", HashAlgo: "sha256"},
+		{Path: "/photos/dune_edit.jpg", Size: 11, Hash: "e", HashAlgo: "sha256"},
+	}
+	if err := db.Create(&files).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	groups, totalGroups, totalFiles, err := FindNameCopiesPaginated(db, DuplicateFilter{}, OrderDefault, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalGroups != 2 || totalFiles != 5 || len(groups) != 2 {
+		t.Fatalf("found %d groups of %d files, want 2 of 5: %+v", totalGroups, totalFiles, groups)
+	}
+	beach := groups[0]
+	var paths []string
+	for _, f := range beach.Files {
+		paths = append(paths, f.Path)
+	}
+	want := []string{"/photos/beach.jpg", "/photos/beach (1).jpg", "/photos/beach - Copy.jpg"}
+	for i := range want {
+		if i >= len(paths) || paths[i] != want[i] {
+			t.Fatalf("beach group = %v, want %v", paths, want)
+		}
+	}
+	if beach.Size != 100 || beach.Similarity[0] != 1 || beach.Similarity[1] != similarityScore(1) || beach.Similarity[2] != 1 {
+		t.Errorf("beach group size %d, similarity %v", beach.Size, beach.Similarity)
+	}
+	if dune := groups[1]; len(dune.Files) != 2 || dune.Similarity[1] != 0 {
+		t.Errorf("dune group = %+v, want 2 files that cannot be compared", dune)
+	}
+
+	groups, totalGroups, _, err = FindNameCopiesPaginated(db, DuplicateFilter{}, OrderDefault, 1, 10)
+	if err != nil || totalGroups != 2 || len(groups) != 1 {
+		t.Errorf("second page = %d groups of %d, %v", len(groups), totalGroups, err)
+	}
+}
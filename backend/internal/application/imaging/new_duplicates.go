@@ -0,0 +1,75 @@
+package imaging
+
+import (
+	"fmt"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// maxNewDuplicateGroups caps how many newly discovered groups are reported after a scan
+const maxNewDuplicateGroups = 100
+
+// NewDuplicateGroup is the payload of a duplicates.new event
+type NewDuplicateGroup struct {
+	Hash  string   `json:"hash"`
+	Size  int64    `json:"size"`
+	Paths []string `json:"paths"`
+}
+
+type duplicateKey struct {
+	HashAlgo string
+	Hash     string
+	Size     int64
+}
+
+func (k duplicateKey) String() string {
+	return fmt.Sprintf("%s|%s|%d", k.HashAlgo, k.Hash, k.Size)
+}
+
+// duplicateGroupKeys returns the set of exact duplicate groups currently in the
+// database, other than the ignored ones
+func duplicateGroupKeys(db *gorm.DB) (map[string]duplicateKey, error) {
+	var keys []duplicateKey
+	err := db.Model(&domain.ImageFile{}).
+		Select("hash_algo, hash, size").
+		Where("hash <> ''").
+		Where(notIgnoredCond).
+		Group("hash_algo, hash, size").
+		Having("count(*) > 1").
+		Scan(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]duplicateKey, len(keys))
+	for _, k := range keys {
+		set[k.String()] = k
+	}
+	return set, nil
+}
+
+// newDuplicateGroups returns groups present now that were not in the before snapshot,
+// at most maxNewDuplicateGroups of them
+func newDuplicateGroups(db *gorm.DB, before map[string]duplicateKey) ([]NewDuplicateGroup, error) {
+	after, err := duplicateGroupKeys(db)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []NewDuplicateGroup{}
+	for id, key := range after {
+		if _, ok := before[id]; ok {
+			continue
+		}
+		if len(groups) >= maxNewDuplicateGroups {
+			break
+		}
+		var paths []string
+		db.Model(&domain.ImageFile{}).
+			Where("hash_algo = ? AND hash = ? AND size = ?", key.HashAlgo, key.Hash, key.Size).
+			Order("path").Pluck("path", &paths)
+		groups = append(groups, NewDuplicateGroup{Hash: key.Hash, Size: key.Size, Paths: paths})
+	}
+	return groups, nil
+}
@@ -0,0 +1,201 @@
+package imaging
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrObjectNotDeletable is returned for objects of stores the server does not delete
+	// from, such as S3, whose objects are removed with a deletion plan, and for the files
+	// of remote agents
+	ErrObjectNotDeletable = errors.New("objects in this store are not deleted by the server")
+	// ErrObjectReplace is returned when an object store file would be replaced by a link
+	// or compared byte by byte, neither of which works on remote files
+	ErrObjectReplace = errors.New("object store files cannot be replaced by links or verified byte by byte")
+)
+
+// DeleteObject removes a file from an object store that supports deletion
+func DeleteObject(ctx context.Context, path string, stores objectstore.Config) error {
+	if strings.HasPrefix(path, objectstore.SchemeAgent) {
+		return ErrObjectNotDeletable
+	}
+	store, err := objectstore.Open(path, stores)
+	if err != nil {
+		return err
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
+	deleter, ok := store.(objectstore.Deleter)
+	if !ok {
+		return ErrObjectNotDeletable
+	}
+	return deleter.Delete(ctx, path)
+}
+
+// scanRoot scans a gallery root: a local directory or an object store URL
+func scanRoot(ctx context.Context, db *gorm.DB, root string, progressChan chan<- string, opts ScanOptions) error {
+	if objectstore.IsRemote(root) {
+		return scanObjectStore(ctx, db, root, progressChan, opts)
+	}
+	return scanDirectory(ctx, db, root, progressChan, opts)
+}
+
+// scanObjectStore updates the database from the objects under an object store root
+// such as s3://bucket/prefix, and removes the records of objects that are gone.
+// Objects are hashed by streaming their content, unless the store reports an MD5 of
+// it and MD5 is the content hash in use. Objects are never decoded, so they take part
+// in exact duplicate detection only.
+func scanObjectStore(ctx context.Context, db *gorm.DB, root string, progressChan chan<- string, opts ScanOptions) error {
+	store, err := objectstore.Open(root, opts.ObjectStores)
+	if err != nil {
+		return err
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
+	tally := tallyOf(ctx)
+	tally.directory(root)
+	progress := progressOf(ctx)
+
+	// Phase 1: List the objects
+	var objects []objectstore.Object
+	err = store.List(ctx, func(o objectstore.Object) error {
+		if cerr := checkpoint(ctx); cerr != nil {
+			return cerr
+		}
+		if opts.skipsObject(root, o.Path) || !opts.includes(o.Path) || !opts.inSizeRange(o.Size) {
+			return nil
+		}
+		objects = append(objects, o)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	progress.rootWalked(len(objects))
+
+	// Phase 2: Hash new and changed objects
+	files := make([]fileInfo, len(objects))
+	for i, o := range objects {
+		files[i] = fileInfo{path: o.Path, normalizedPath: o.Path, size: o.Size, modTime: o.ModTime}
+	}
+	existingMap := loadExisting(ctx, db, files, progressChan, opts)
+	seen := make(map[uint]bool, len(objects))
+	for _, o := range objects {
+		if err := checkpoint(ctx); err != nil {
+			return err
+		}
+		existing, ok := existingMap[opts.pathKey(o.Path)]
+		if ok {
+			seen[existing.ID] = true
+			if existing.Size == o.Size && existing.ModTime.Equal(o.ModTime) && !opts.needsContentRehash(&existing) {
+				reviveRecord(db, &existing)
+				progressChan <- progress.handled("Skipping (cached): " + o.Path)
+				continue
+			}
+		}
+
+		hash, prefixHash, err := hashObject(ctx, store, o, opts)
+		if err != nil {
+			progressChan <- progress.handled("Error hashing " + o.Path + ": " + err.Error())
+			continue
+		}
+		record := domain.ImageFile{
+			Path:       o.Path,
+			Size:       o.Size,
+			Hash:       hash,
+			PrefixHash: prefixHash,
+			HashAlgo:   string(opts.contentHashAlgorithm()),
+			ModTime:    o.ModTime,
+			IsVideo:    domain.IsVideoFile(o.Path),
+		}
+		if ok {
+			record.ID = existing.ID
+			record.CreatedAt = existing.CreatedAt
+			record.Protected = existing.Protected
+			err = db.Save(&record).Error
+		} else {
+			err = db.Create(&record).Error
+		}
+		if err != nil {
+			progressChan <- progress.handled("Error saving " + o.Path + ": " + err.Error())
+			continue
+		}
+		seen[record.ID] = true
+		if ok {
+			tally.count(0, 1, 0)
+		} else {
+			tally.count(1, 0, 0)
+		}
+		progressChan <- progress.handled("Processed: " + o.Path)
+	}
+
+	// Phase 3: Remove the records of objects that were deleted
+	var stored []domain.ImageFile
+	db.Where(opts.pathColumn()+` LIKE ? ESCAPE '\'`, escapeLike(opts.pathKey(strings.TrimSuffix(root, "/")+"/"))+"%").Find(&stored)
+	for _, f := range stored {
+		if !seen[f.ID] {
+			progressChan <- "Removing missing object from DB: " + f.Path
+			db.Delete(&f)
+			tally.count(0, 0, 1)
+		}
+	}
+	return nil
+}
+
+// skipsObject reports whether an object under root is left out like a file in a
+// local walk: excluded itself or in an excluded or hidden folder, or nested deeper
+// than MaxDepth allows
+func (o ScanOptions) skipsObject(root, path string) bool {
+	root = strings.TrimSuffix(root, "/")
+	segments := strings.Split(strings.TrimPrefix(path, root+"/"), "/")
+	if o.MaxDepth > 0 && len(segments) > o.MaxDepth {
+		return true
+	}
+	p := root
+	for _, segment := range segments {
+		p += "/" + segment
+		if o.isExcluded(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// hashObject returns the content hash of an object and, with two-stage hashing, the
+// hash of its first 64KB, both computed in a single read
+func hashObject(ctx context.Context, store objectstore.Store, o objectstore.Object, opts ScanOptions) (hash, prefixHash string, err error) {
+	algo := opts.contentHashAlgorithm()
+	if algo == ContentHashMD5 && o.MD5 != "" && !opts.TwoStageHash {
+		return o.MD5, "", nil
+	}
+
+	r, err := store.Open(ctx, o.Path)
+	if err != nil {
+		return "", "", err
+	}
+	defer r.Close()
+
+	hasher := algo.newHasher()
+	if opts.TwoStageHash {
+		prefix := algo.newHasher()
+		if _, err := io.CopyN(io.MultiWriter(hasher, prefix), opts.Throttle.reader(r), prefixHashSize); err != nil && err != io.EOF {
+			return "", "", err
+		}
+		prefixHash = hex.EncodeToString(prefix.Sum(nil))
+	}
+	if err := hashReader(hasher, opts.Throttle.reader(r)); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), prefixHash, nil
+}
@@ -0,0 +1,112 @@
+package imaging
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+)
+
+func TestScanObjectStore(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+
+	var mu sync.Mutex
+	objects := map[string]string{"photos/a.jpg": "photo", "photos/sub/b.jpg": "photo", "photos/@eaDir/c.jpg": "junk", "photos/notes.txt": "text"}
+	multipart := map[string]bool{"photos/sub/b.jpg": true}
+	gets := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Query().Get("list-type") == "2" {
+			fmt.Fprint(w, "<ListBucketResult>")
+			for key, content := range objects {
+				sum := md5.Sum([]byte(content))
+				etag := hex.EncodeToString(sum[:])
+				if multipart[key] {
+					etag = "0123456789abcdef0123456789abcdef-2"
+				}
+				fmt.Fprintf(w, `<Contents><Key>%s</Key><Size>%d</Size><ETag>"%s"</ETag><LastModified>2024-05-01T12:00:00.000Z</LastModified></Contents>`, key, len(content), etag)
+			}
+			fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+			return
+		}
+		content, ok := objects[strings.TrimPrefix(r.URL.Path, "/bucket/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		gets++
+		fmt.Fprint(w, content)
+	}))
+	defer srv.Close()
+
+	opts := ScanOptions{ObjectStores: objectstore.Config{S3: objectstore.S3Config{Endpoint: srv.URL}}}
+	scan := func() {
+		progress := make(chan string, 100)
+		if err := scanRoot(context.Background(), db, "s3://bucket/photos", progress, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scan()
+	var files []domain.ImageFile
+	db.Order("path").Find(&files)
+	if len(files) != 2 || files[0].Path != "s3://bucket/photos/a.jpg" || files[1].Path != "s3://bucket/photos/sub/b.jpg" {
+		t.Fatalf("got records %+v", files)
+	}
+	if files[0].Hash == "" || files[0].Hash != files[1].Hash {
+		t.Fatalf("objects with the same content should have the same hash: %q, %q", files[0].Hash, files[1].Hash)
+	}
+	if gets != 1 {
+		t.Errorf("got %d downloads, want only the multipart upload streamed", gets)
+	}
+
+	mu.Lock()
+	delete(objects, "photos/a.jpg")
+	mu.Unlock()
+	scan()
+	var count int64
+	db.Model(&domain.ImageFile{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("got %d records after the object was deleted, want 1", count)
+	}
+	if gets != 1 {
+		t.Errorf("unchanged objects should not be downloaded again, got %d downloads", gets)
+	}
+
+	// Records of objects are left to the object store scan
+	if err := cleanupMissingFiles(context.Background(), db, "", make(chan string, 100), opts); err != nil {
+		t.Fatal(err)
+	}
+	db.Model(&domain.ImageFile{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("cleanup removed object records, %d left", count)
+	}
+}
+
+func TestPathGuardObjects(t *testing.T) {
+	guard := NewPathGuard([]string{"s3://bucket/photos"})
+	for path, want := range map[string]bool{
+		"s3://bucket/photos/a.jpg":         true,
+		"s3://bucket/photos/sub/b.jpg":     true,
+		"s3://bucket/photos":               false,
+		"s3://bucket/photos2/a.jpg":        false,
+		"s3://bucket/photos/../secret.jpg": false,
+		"s3://other/photos/a.jpg":          false,
+	} {
+		if got := guard.AllowsObject(path); got != want {
+			t.Errorf("AllowsObject(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if !guard.ContainsObject("s3://bucket/photos/") || !guard.ContainsObject("s3://bucket/photos/sub") {
+		t.Error("the root and prefixes inside it should be contained")
+	}
+}
@@ -3,27 +3,21 @@ package imaging
 import (
 	"bytes"
 	"fmt"
-	"image"
 	"image/color"
 	"image/jpeg"
-	"os"
 
 	"github.com/deepteams/webp"
 	"github.com/disintegration/imaging"
+
+	"image-toolkit/internal/infrastructure/imagecodec"
 )
 
 // PrepareOcrImage opens an image, scales it by scaleFactor and rotates
 // clockwise by the given angle (in degrees). Returns WebP-encoded bytes.
 func PrepareOcrImage(imagePath string, scaleFactor float64, angle float64) ([]byte, error) {
-	file, err := os.Open(imagePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open image: %w", err)
-	}
-	defer file.Close()
-
-	img, _, err := image.Decode(file)
+	img, err := imagecodec.Decode(imagePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode image: %w", err)
+		return nil, err
 	}
 
 	// Scale by scaleFactor
@@ -3,7 +3,7 @@ package imaging
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"sync"
 	"time"
@@ -117,7 +117,8 @@ func (om *OcrManager) processUnclassified(incremental bool) {
 	// Build query based on mode
 	query := om.db.Table("image_files").
 		Select("image_files.*").
-		Joins("LEFT JOIN ocr_classifications ON ocr_classifications.image_file_id = image_files.id")
+		Joins("LEFT JOIN ocr_classifications ON ocr_classifications.image_file_id = image_files.id").
+		Where("image_files.is_video = ? AND image_files.deleted_at IS NULL", false)
 
 	if incremental {
 		// Only new files (no classification yet) or files modified after last classification
@@ -130,7 +131,7 @@ func (om *OcrManager) processUnclassified(incremental bool) {
 
 	var images []domain.ImageFile
 	if err := query.Find(&images).Error; err != nil {
-		log.Printf("OCR: failed to query images: %v", err)
+		slog.Error("OCR: failed to query images", "error", err)
 		return
 	}
 
@@ -301,7 +302,7 @@ func (om *OcrManager) processUnclassified(incremental bool) {
 		count++
 
 		if result.err != nil {
-			log.Printf("OCR: error processing %s: %v", result.image.Path, result.err)
+			slog.Warn("OCR: error processing image", "path", result.image.Path, "error", result.err)
 			om.mu.Lock()
 			om.filesProcessed = count
 			om.progress = fmt.Sprintf("Error on %s: %v", result.image.Path, result.err)
@@ -354,7 +355,7 @@ func (om *OcrManager) saveClassificationBatch(classifications *[]domain.OcrClass
 	for i := range *classifications {
 		classification := &(*classifications)[i]
 		if err := om.db.Create(classification).Error; err != nil {
-			log.Printf("OCR: failed to save classification for image %d: %v", classification.ImageFileID, err)
+			slog.Error("OCR: failed to save classification", "image_id", classification.ImageFileID, "error", err)
 			continue
 		}
 
@@ -364,7 +365,7 @@ func (om *OcrManager) saveClassificationBatch(classifications *[]domain.OcrClass
 				for j := range boxes {
 					boxes[j].ClassificationID = classification.ID
 					if err := om.db.Create(&boxes[j]).Error; err != nil {
-						log.Printf("OCR: failed to save bounding box for classification %d: %v", classification.ID, err)
+						slog.Error("OCR: failed to save bounding box", "classification_id", classification.ID, "error", err)
 					}
 				}
 				// Clean up to avoid re-processing
@@ -0,0 +1,56 @@
+package imaging
+
+import (
+	"strings"
+
+	"image-toolkit/internal/domain"
+)
+
+// pathKey returns the form paths are compared by: the path itself, or its lower case
+// form when paths are case-insensitive
+func (o ScanOptions) pathKey(path string) string {
+	if o.CaseInsensitivePaths {
+		return strings.ToLower(path)
+	}
+	return path
+}
+
+// pathColumn returns the SQL expression matching pathKey for the path column
+func (o ScanOptions) pathColumn() string {
+	if o.CaseInsensitivePaths {
+		return "LOWER(path)"
+	}
+	return "path"
+}
+
+// pickRecords maps records by path key. With case-insensitive paths one file can have
+// records under differently cased paths; of those the record of a path the file was
+// found under (walked, may be nil) is kept, then the most recently updated one, and
+// the others are returned as variants to delete.
+func (o ScanOptions) pickRecords(rows []domain.ImageFile, walked func(path string) bool) (records map[string]domain.ImageFile, variants []domain.ImageFile) {
+	if walked == nil {
+		walked = func(string) bool { return false }
+	}
+	records = make(map[string]domain.ImageFile, len(rows))
+	for _, row := range rows {
+		key := o.pathKey(row.Path)
+		prev, ok := records[key]
+		if !ok {
+			records[key] = row
+			continue
+		}
+		if walked(row.Path) && !walked(prev.Path) || walked(row.Path) == walked(prev.Path) && newerRecord(row, prev) {
+			records[key], row = row, prev
+		}
+		variants = append(variants, row)
+	}
+	return records, variants
+}
+
+// newerRecord reports whether a was updated after b, the higher ID winning a tie
+func newerRecord(a, b domain.ImageFile) bool {
+	if !a.UpdatedAt.Equal(b.UpdatedAt) {
+		return a.UpdatedAt.After(b.UpdatedAt)
+	}
+	return a.ID > b.ID
+}
@@ -0,0 +1,86 @@
+package imaging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestPickRecords(t *testing.T) {
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []domain.ImageFile{
+		{ID: 1, Path: "C:/Photos/a.jpg", UpdatedAt: old},
+		{ID: 2, Path: "c:/photos/A.JPG", UpdatedAt: old.Add(time.Hour)},
+		{ID: 3, Path: "C:/Photos/b.jpg", UpdatedAt: old},
+	}
+
+	records, variants := ScanOptions{}.pickRecords(rows, nil)
+	if len(records) != 3 || len(variants) != 0 {
+		t.Fatalf("case-sensitive: got %d records, %d variants", len(records), len(variants))
+	}
+
+	opts := ScanOptions{CaseInsensitivePaths: true}
+	records, variants = opts.pickRecords(rows, nil)
+	if len(records) != 2 || records["c:/photos/a.jpg"].ID != 2 {
+		t.Fatalf("most recently updated record should be kept, got %+v", records)
+	}
+	if len(variants) != 1 || variants[0].ID != 1 {
+		t.Fatalf("variants: got %+v", variants)
+	}
+
+	records, _ = opts.pickRecords(rows, func(path string) bool { return path == "C:/Photos/a.jpg" })
+	if records["c:/photos/a.jpg"].ID != 1 {
+		t.Fatalf("record of the walked path should be kept, got %+v", records["c:/photos/a.jpg"])
+	}
+}
+
+func TestScanDirectoryCaseInsensitivePaths(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Photo.jpg")
+	if err := os.WriteFile(path, []byte("photo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file was recorded twice under paths differing only in case
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"photo.jpg", "PHOTO.JPG"} {
+		db.Create(&domain.ImageFile{
+			Path:      filepath.ToSlash(filepath.Join(dir, name)),
+			Size:      info.Size(),
+			Hash:      "h",
+			HashAlgo:  string(DefaultContentHashAlgorithm),
+			ModTime:   info.ModTime(),
+			UpdatedAt: old.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	var newest domain.ImageFile
+	db.Where("path = ?", filepath.ToSlash(filepath.Join(dir, "PHOTO.JPG"))).First(&newest)
+
+	opts := ScanOptions{CaseInsensitivePaths: true}
+	progress := make(chan string, 100)
+	ctx := context.Background()
+	if err := scanDirectory(ctx, db, dir, progress, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanupMissingFiles(ctx, db, dir, progress, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var files []domain.ImageFile
+	db.Find(&files)
+	if len(files) != 1 {
+		t.Fatalf("got %d records, want 1", len(files))
+	}
+	if files[0].ID != newest.ID || files[0].Path != filepath.ToSlash(path) || files[0].Hash != "h" {
+		t.Fatalf("got %+v, want record %d under the path as found on disk", files[0], newest.ID)
+	}
+}
@@ -0,0 +1,115 @@
+package imaging
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"image-toolkit/internal/infrastructure/objectstore"
+)
+
+// PathGuard restricts destructive file operations to the gallery folders.
+// Roots and checked paths are compared after symlink resolution, so a link
+// inside a gallery folder cannot be used to reach files outside of it.
+type PathGuard struct {
+	roots  []string
+	remote []string // object store roots, e.g. s3://bucket/photos
+}
+
+// NewPathGuard creates a guard for the given gallery folders. Folders that cannot
+// be resolved (e.g. no longer exist) are ignored.
+func NewPathGuard(dirs []string) *PathGuard {
+	g := &PathGuard{}
+	for _, dir := range dirs {
+		if objectstore.IsRemote(dir) {
+			g.remote = append(g.remote, strings.TrimSuffix(dir, "/")+"/")
+			continue
+		}
+		if resolved, err := resolvePath(dir); err == nil {
+			g.roots = append(g.roots, resolved)
+		}
+	}
+	return g
+}
+
+// Allows reports whether path is an existing file or directory strictly inside one of the roots
+func (g *PathGuard) Allows(path string) bool {
+	return g.within(path, false)
+}
+
+// AllowsObject reports whether path is the URL of an object strictly inside one of
+// the object store roots
+func (g *PathGuard) AllowsObject(path string) bool {
+	return g.withinObject(path, false)
+}
+
+// ContainsObject reports whether path is one of the object store roots or a URL
+// inside one
+func (g *PathGuard) ContainsObject(path string) bool {
+	return g.withinObject(path, true)
+}
+
+func (g *PathGuard) withinObject(path string, allowRoot bool) bool {
+	path = strings.TrimSuffix(path, "/") + "/"
+	for _, root := range g.remote {
+		if rel, ok := strings.CutPrefix(path, root); ok && (rel != "" || allowRoot) && !slices.Contains(strings.Split(rel, "/"), "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether path is one of the roots or an existing file or directory inside one
+func (g *PathGuard) Contains(path string) bool {
+	return g.within(path, true)
+}
+
+// ContainsTarget reports whether a directory that may not exist yet would be one of
+// the roots or inside one, judged by its closest existing ancestor
+func (g *PathGuard) ContainsTarget(path string) bool {
+	if !filepath.IsAbs(filepath.FromSlash(path)) || slices.Contains(strings.Split(filepath.ToSlash(path), "/"), "..") {
+		return false
+	}
+	dir := filepath.Clean(filepath.FromSlash(path))
+	for {
+		if _, err := os.Lstat(dir); err == nil {
+			return g.Contains(dir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+func (g *PathGuard) within(path string, allowRoot bool) bool {
+	if !filepath.IsAbs(filepath.FromSlash(path)) {
+		return false
+	}
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return false
+	}
+	for _, root := range g.roots {
+		rel, err := filepath.Rel(root, resolved)
+		if err == nil && (rel != "." || allowRoot) && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(filepath.FromSlash(path))
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+// GalleryPathGuard creates a PathGuard for the gallery folders stored in the database
+func (sm *ScanManager) GalleryPathGuard() *PathGuard {
+	return NewPathGuard(sm.getGalleryDirs())
+}
@@ -0,0 +1,62 @@
+package imaging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathGuard(t *testing.T) {
+	base := t.TempDir()
+	gallery := filepath.Join(base, "gallery")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{filepath.Join(gallery, "sub"), outside} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inside := filepath.Join(gallery, "sub", "a.jpg")
+	secret := filepath.Join(outside, "secret.jpg")
+	for _, f := range []string{inside, secret} {
+		if err := os.WriteFile(f, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := filepath.Join(gallery, "link.jpg")
+	if err := os.Symlink(secret, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	linkDir := filepath.Join(gallery, "escape")
+	if err := os.Symlink(outside, linkDir); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewPathGuard([]string{gallery, filepath.Join(base, "missing")})
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{inside, true},
+		{filepath.ToSlash(inside), true},
+		{secret, false},
+		{filepath.Join(gallery, "..", "outside", "secret.jpg"), false},
+		{link, false},
+		{filepath.Join(linkDir, "secret.jpg"), false},
+		{filepath.Join(gallery, "sub", "missing.jpg"), false},
+		{"sub/a.jpg", false},
+		{gallery + "-other", false},
+		{gallery, false},
+	}
+	for _, tt := range tests {
+		if got := g.Allows(tt.path); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	for path, want := range map[string]bool{gallery: true, filepath.Join(gallery, "sub"): true, linkDir: false, outside: false} {
+		if got := g.Contains(path); got != want {
+			t.Errorf("Contains(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
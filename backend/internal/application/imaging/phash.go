@@ -0,0 +1,215 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// PerceptualHashAlgorithm identifies the algorithm used to compute a perceptual hash.
+// Hashes produced by different algorithms are not comparable with each other.
+type PerceptualHashAlgorithm string
+
+const (
+	// HashAlgoAverage is the average hash (aHash): pixels of an 8x8 thumbnail compared to their mean
+	HashAlgoAverage PerceptualHashAlgorithm = "ahash"
+	// HashAlgoDifference is the difference hash (dHash): gradients between horizontally adjacent pixels
+	HashAlgoDifference PerceptualHashAlgorithm = "dhash"
+	// HashAlgoDCT is the DCT-based perceptual hash (pHash)
+	HashAlgoDCT PerceptualHashAlgorithm = "phash"
+
+	// DefaultPerceptualHashAlgorithm is used when no algorithm is configured
+	DefaultPerceptualHashAlgorithm = HashAlgoDCT
+)
+
+// ParsePerceptualHashAlgorithm validates an algorithm name from configuration.
+// An empty name selects the default algorithm.
+func ParsePerceptualHashAlgorithm(name string) (PerceptualHashAlgorithm, error) {
+	switch algo := PerceptualHashAlgorithm(strings.ToLower(strings.TrimSpace(name))); algo {
+	case "":
+		return DefaultPerceptualHashAlgorithm, nil
+	case HashAlgoAverage, HashAlgoDifference, HashAlgoDCT:
+		return algo, nil
+	default:
+		return "", fmt.Errorf("unknown perceptual hash algorithm %q (expected ahash, dhash or phash)", name)
+	}
+}
+
+const (
+	// pHashSampleSize is the side of the grayscale square the image is reduced to before the DCT
+	pHashSampleSize = 32
+	// pHashBlockSize is the side of the low-frequency DCT block used to build the hash (8x8 = 64 bits)
+	pHashBlockSize = 8
+)
+
+// hashImage calculates a perceptual hash of a decoded image with the given algorithm.
+// Visually identical images produce hashes with a small Hamming distance even when
+// they differ in compression, resolution or minor edits.
+func hashImage(img image.Image, algo PerceptualHashAlgorithm) uint64 {
+	switch algo {
+	case HashAlgoAverage:
+		return averageHash(img)
+	case HashAlgoDifference:
+		return differenceHash(img)
+	default:
+		return perceptualHash(img)
+	}
+}
+
+// averageHash computes the aHash of a decoded image
+func averageHash(img image.Image) uint64 {
+	small := imaging.Grayscale(imaging.Resize(img, pHashBlockSize, pHashBlockSize, imaging.Lanczos))
+
+	var sum int
+	for y := 0; y < pHashBlockSize; y++ {
+		for x := 0; x < pHashBlockSize; x++ {
+			sum += int(small.Pix[y*small.Stride+x*4])
+		}
+	}
+	mean := sum / (pHashBlockSize * pHashBlockSize)
+
+	var hash uint64
+	for y := 0; y < pHashBlockSize; y++ {
+		for x := 0; x < pHashBlockSize; x++ {
+			if int(small.Pix[y*small.Stride+x*4]) > mean {
+				hash |= 1 << uint(y*pHashBlockSize+x)
+			}
+		}
+	}
+	return hash
+}
+
+// differenceHash computes the dHash of a decoded image.
+// The image is reduced to 9x8 so that each row yields 8 left-to-right comparisons.
+func differenceHash(img image.Image) uint64 {
+	small := imaging.Grayscale(imaging.Resize(img, pHashBlockSize+1, pHashBlockSize, imaging.Lanczos))
+
+	var hash uint64
+	for y := 0; y < pHashBlockSize; y++ {
+		row := small.Pix[y*small.Stride:]
+		for x := 0; x < pHashBlockSize; x++ {
+			if row[x*4] < row[(x+1)*4] {
+				hash |= 1 << uint(y*pHashBlockSize+x)
+			}
+		}
+	}
+	return hash
+}
+
+// perceptualHash computes the pHash of a decoded image
+func perceptualHash(img image.Image) uint64 {
+	small := imaging.Grayscale(imaging.Resize(img, pHashSampleSize, pHashSampleSize, imaging.Lanczos))
+
+	var pixels [pHashSampleSize][pHashSampleSize]float64
+	for y := 0; y < pHashSampleSize; y++ {
+		for x := 0; x < pHashSampleSize; x++ {
+			// Grayscale output has R == G == B
+			pixels[y][x] = float64(small.Pix[y*small.Stride+x*4])
+		}
+	}
+
+	// 2D DCT-II, only the low-frequency top-left block is needed
+	var coeffs [pHashBlockSize * pHashBlockSize]float64
+	for v := 0; v < pHashBlockSize; v++ {
+		for u := 0; u < pHashBlockSize; u++ {
+			var sum float64
+			for y := 0; y < pHashSampleSize; y++ {
+				cy := math.Cos(float64(2*y+1) * float64(v) * math.Pi / (2 * pHashSampleSize))
+				for x := 0; x < pHashSampleSize; x++ {
+					cx := math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * pHashSampleSize))
+					sum += pixels[y][x] * cx * cy
+				}
+			}
+			coeffs[v*pHashBlockSize+u] = sum
+		}
+	}
+
+	// Median of the block excluding the DC term, which only reflects average brightness
+	sorted := make([]float64, len(coeffs)-1)
+	copy(sorted, coeffs[1:])
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, c := range coeffs {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// transformSampleSize is the side of the thumbnail rotated and mirrored by
+// transformHashes, well above the sample size of every hash algorithm
+const transformSampleSize = 64
+
+// imageTransforms are the rotations and mirror images of the dihedral group except
+// the identity, which is the stored perceptual hash itself
+var imageTransforms = []func(image.Image) *image.NRGBA{
+	imaging.Rotate90,
+	imaging.Rotate180,
+	imaging.Rotate270,
+	imaging.FlipH,
+	imaging.FlipV,
+	imaging.Transpose,
+	imaging.Transverse,
+}
+
+// transformHashes calculates the perceptual hashes of the image rotated by 90, 180
+// and 270 degrees and mirrored along each axis and diagonal. A copy rotated or
+// mirrored that way has a perceptual hash close to one of them. Every algorithm
+// resizes to a square ignoring the aspect ratio, so the transforms are applied to a
+// square thumbnail instead of the full image.
+func transformHashes(img image.Image, algo PerceptualHashAlgorithm) []uint64 {
+	small := imaging.Resize(img, transformSampleSize, transformSampleSize, imaging.Lanczos)
+	hashes := make([]uint64, len(imageTransforms))
+	for i, transform := range imageTransforms {
+		hashes[i] = hashImage(transform(small), algo)
+	}
+	return hashes
+}
+
+// hammingDistance returns the number of differing bits between two perceptual hashes
+func hammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// formatPerceptualHash encodes a perceptual hash as a fixed-width hex string for storage
+func formatPerceptualHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// parsePerceptualHash decodes a perceptual hash stored by formatPerceptualHash
+func parsePerceptualHash(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
+
+// formatPerceptualHashes encodes a list of perceptual hashes as comma-separated hex
+func formatPerceptualHashes(hashes []uint64) string {
+	encoded := make([]string, len(hashes))
+	for i, h := range hashes {
+		encoded[i] = formatPerceptualHash(h)
+	}
+	return strings.Join(encoded, ",")
+}
+
+// parsePerceptualHashes decodes hashes stored by formatPerceptualHashes, skipping
+// malformed entries
+func parsePerceptualHashes(s string) []uint64 {
+	if s == "" {
+		return nil
+	}
+	var hashes []uint64
+	for _, part := range strings.Split(s, ",") {
+		if h, err := parsePerceptualHash(part); err == nil {
+			hashes = append(hashes, h)
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,150 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+// gradientImage builds a deterministic test image with some structure
+func gradientImage(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8((x*255/w + y*128/h) % 256)
+			if (x/(w/4)+y/(h/4))%2 == 0 {
+				v = 255 - v
+			}
+			img.Set(x, y, color.NRGBA{R: v, G: v / 2, B: 255 - v, A: 255})
+		}
+	}
+	return img
+}
+
+func TestPerceptualHashStableAcrossResize(t *testing.T) {
+	original := gradientImage(640, 480)
+	resized := imaging.Resize(original, 200, 150, imaging.Lanczos)
+
+	d := hammingDistance(perceptualHash(original), perceptualHash(resized))
+	if d > DefaultSimilarityThreshold {
+		t.Fatalf("resized copy distance %d exceeds threshold %d", d, DefaultSimilarityThreshold)
+	}
+}
+
+func TestPerceptualHashDiffersForDifferentImages(t *testing.T) {
+	a := gradientImage(640, 480)
+	b := imaging.FlipH(imaging.Rotate90(a))
+
+	d := hammingDistance(perceptualHash(a), perceptualHash(b))
+	if d <= DefaultSimilarityThreshold {
+		t.Fatalf("unrelated images distance %d is within threshold %d", d, DefaultSimilarityThreshold)
+	}
+}
+
+func TestPerceptualHashFormatRoundTrip(t *testing.T) {
+	const h uint64 = 0xfedcba9876543210
+	parsed, err := parsePerceptualHash(formatPerceptualHash(h))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if parsed != h {
+		t.Fatalf("round trip mismatch: got %x, want %x", parsed, h)
+	}
+}
+
+func TestBKTreeSearchMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	hashes := make([]uint64, 500)
+	tree := &bkTree{}
+	for i := range hashes {
+		hashes[i] = rng.Uint64()
+		if i%5 == 0 && i > 0 {
+			// Plant near neighbours of an earlier hash
+			hashes[i] = hashes[i-1] ^ (1 << uint(rng.Intn(64)))
+		}
+		tree.insert(hashes[i], i)
+	}
+
+	for _, radius := range []int{0, 3, 10, 24} {
+		for q := 0; q < len(hashes); q += 37 {
+			got := tree.search(hashes[q], radius)
+			var want []int
+			for i, h := range hashes {
+				if hammingDistance(hashes[q], h) <= radius {
+					want = append(want, i)
+				}
+			}
+			sort.Ints(got)
+			if len(got) != len(want) {
+				t.Fatalf("radius %d query %d: got %d results, want %d", radius, q, len(got), len(want))
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf("radius %d query %d: result mismatch %v vs %v", radius, q, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestAlternativeHashesStableAcrossResize(t *testing.T) {
+	original := gradientImage(640, 480)
+	resized := imaging.Resize(original, 200, 150, imaging.Lanczos)
+
+	for name, fn := range map[string]func(image.Image) uint64{
+		"ahash": averageHash,
+		"dhash": differenceHash,
+	} {
+		d := hammingDistance(fn(original), fn(resized))
+		if d > DefaultSimilarityThreshold {
+			t.Errorf("%s: resized copy distance %d exceeds threshold %d", name, d, DefaultSimilarityThreshold)
+		}
+	}
+}
+
+func TestParsePerceptualHashAlgorithm(t *testing.T) {
+	cases := map[string]PerceptualHashAlgorithm{
+		"":       DefaultPerceptualHashAlgorithm,
+		"ahash":  HashAlgoAverage,
+		" DHash": HashAlgoDifference,
+		"phash":  HashAlgoDCT,
+	}
+	for in, want := range cases {
+		got, err := ParsePerceptualHashAlgorithm(in)
+		if err != nil || got != want {
+			t.Errorf("ParsePerceptualHashAlgorithm(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParsePerceptualHashAlgorithm("md5"); err == nil {
+		t.Error("expected error for unknown algorithm")
+	}
+}
+
+func TestTransformHashesMatchRotatedCopies(t *testing.T) {
+	original := gradientImage(640, 480)
+	copies := map[string]image.Image{
+		"rotate90":  imaging.Rotate90(original),
+		"rotate180": imaging.Rotate180(original),
+		"flipH":     imaging.FlipH(original),
+		"transpose": imaging.Transpose(original),
+	}
+	for _, algo := range []PerceptualHashAlgorithm{HashAlgoAverage, HashAlgoDifference, HashAlgoDCT} {
+		xforms := transformHashes(original, algo)
+		reference := hashImage(original, algo)
+		for name, img := range copies {
+			h := hashImage(img, algo)
+			if d := orientedDistance(reference, xforms, h); d > DefaultSimilarityThreshold {
+				t.Errorf("%s/%s: distance %d exceeds threshold %d", algo, name, d, DefaultSimilarityThreshold)
+			}
+		}
+	}
+
+	hashes := transformHashes(original, HashAlgoDCT)
+	if got := parsePerceptualHashes(formatPerceptualHashes(hashes)); len(got) != len(hashes) || got[0] != hashes[0] {
+		t.Errorf("round trip of %v gave %v", hashes, got)
+	}
+}
@@ -0,0 +1,87 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"image"
+
+	"image-toolkit/internal/domain"
+
+	"github.com/disintegration/imaging"
+	"github.com/zeebo/blake3"
+	"gorm.io/gorm"
+)
+
+// pixelHash hashes the decoded pixels of an image together with its dimensions.
+// Pixels are normalized to 8-bit non-premultiplied RGBA, and fully transparent
+// pixels to zero, so the same picture stored as PNG and lossless WebP, or re-saved
+// with different metadata, gets the same hash although the file bytes differ.
+func pixelHash(img image.Image) string {
+	nrgba := imaging.Clone(img)
+	width, height := nrgba.Rect.Dx(), nrgba.Rect.Dy()
+
+	hasher := blake3.New()
+	var dims [8]byte
+	binary.BigEndian.PutUint32(dims[:4], uint32(width))
+	binary.BigEndian.PutUint32(dims[4:], uint32(height))
+	hasher.Write(dims[:])
+
+	row := make([]byte, width*4)
+	for y := 0; y < height; y++ {
+		copy(row, nrgba.Pix[y*nrgba.Stride:])
+		for i := 0; i < len(row); i += 4 {
+			if row[i+3] == 0 {
+				row[i], row[i+1], row[i+2] = 0, 0, 0
+			}
+		}
+		hasher.Write(row)
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// FindPixelDuplicatesPaginated groups images whose decoded pixels are identical,
+// regardless of file format and metadata. Groups are ordered by their largest
+// file unless another order is given; the group Size is that of the largest file.
+func FindPixelDuplicatesPaginated(db *gorm.DB, filter DuplicateFilter, order GroupOrder, offset, limit int) ([]domain.DuplicateGroup, int, int, error) {
+	type PixelHashCount struct {
+		PixelHash string
+		MaxSize   int64
+		Count     int64
+	}
+
+	var allPixelHashes []PixelHashCount
+	result := filter.apply(db.Model(&domain.ImageFile{})).
+		Select("pixel_hash, MAX(size) as max_size, count(*) as count").
+		Where("pixel_hash <> ''").
+		Group("pixel_hash").
+		Having("count(*) > 1").
+		Order(order.orderBy("max_size DESC, pixel_hash", "pixel_hash")).
+		Scan(&allPixelHashes)
+	if result.Error != nil {
+		return nil, 0, 0, result.Error
+	}
+
+	totalGroups := len(allPixelHashes)
+	totalFiles := 0
+	for _, ph := range allPixelHashes {
+		totalFiles += int(ph.Count)
+	}
+	if offset >= totalGroups {
+		return []domain.DuplicateGroup{}, totalGroups, totalFiles, nil
+	}
+	end := min(offset+limit, totalGroups)
+
+	var groups []domain.DuplicateGroup
+	for _, ph := range allPixelHashes[offset:end] {
+		var files []domain.ImageFile
+		filter.apply(db.Where("pixel_hash = ?", ph.PixelHash)).Order("size DESC, path").Find(&files)
+		if len(files) > 1 {
+			groups = append(groups, domain.DuplicateGroup{
+				Hash:  ph.PixelHash,
+				Size:  ph.MaxSize,
+				Files: files,
+			})
+		}
+	}
+	return groups, totalGroups, totalFiles, nil
+}
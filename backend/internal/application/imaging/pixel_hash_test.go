@@ -0,0 +1,47 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestPixelHashIgnoresColorModel(t *testing.T) {
+	original := gradientImage(64, 48)
+	rgba := image.NewRGBA(original.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), original, image.Point{}, draw.Src)
+
+	if pixelHash(original) != pixelHash(rgba) {
+		t.Error("same pixels in NRGBA and RGBA should hash equal")
+	}
+}
+
+func TestPixelHashIgnoresTransparentColor(t *testing.T) {
+	a := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	b := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	a.Set(1, 1, color.NRGBA{R: 255, A: 0})
+	b.Set(1, 1, color.NRGBA{B: 255, A: 0})
+
+	if pixelHash(a) != pixelHash(b) {
+		t.Error("fully transparent pixels should hash equal regardless of color")
+	}
+}
+
+func TestPixelHashDetectsChanges(t *testing.T) {
+	original := gradientImage(64, 48)
+	changed := image.NewNRGBA(original.Bounds())
+	draw.Draw(changed, changed.Bounds(), original, image.Point{}, draw.Src)
+	changed.Set(10, 10, color.NRGBA{R: 1, G: 2, B: 3, A: 255})
+
+	if pixelHash(original) == pixelHash(changed) {
+		t.Error("a changed pixel should change the hash")
+	}
+
+	// Same pixel data laid out with different dimensions
+	wide := image.NewNRGBA(image.Rect(0, 0, 4, 1))
+	tall := image.NewNRGBA(image.Rect(0, 0, 1, 4))
+	if pixelHash(wide) == pixelHash(tall) {
+		t.Error("dimensions should be part of the hash")
+	}
+}
@@ -0,0 +1,81 @@
+package imaging
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// prefixHashSize is the number of leading bytes hashed in the first stage of two-stage hashing
+const prefixHashSize = 64 * 1024
+
+// calculatePrefixHash hashes the first prefixHashSize bytes of a file.
+// For files no larger than the prefix the result equals the full content hash.
+func calculatePrefixHash(path string, algo ContentHashAlgorithm, throttle *Throttle) (string, error) {
+	file, err := os.Open(longPath(path))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := algo.newHasher()
+	if err := hashReader(hasher, io.LimitReader(throttle.reader(file), prefixHashSize)); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// resolvePrefixCollisions completes the second stage of two-stage hashing: files that share
+// algorithm, size and prefix hash with at least one other file get their full content hash
+// computed. Files with a unique size+prefix combination cannot be exact duplicates and keep
+// an empty full hash. Returns the number of files that were fully hashed.
+func resolvePrefixCollisions(ctx context.Context, db *gorm.DB, progressChan chan<- string, opts ScanOptions) int {
+	type prefixKey struct {
+		HashAlgo   string
+		Size       int64
+		PrefixHash string
+	}
+
+	var collisions []prefixKey
+	err := db.Model(&domain.ImageFile{}).
+		Select("hash_algo, size, prefix_hash").
+		Where("prefix_hash <> ''").
+		Group("hash_algo, size, prefix_hash").
+		Having("count(*) > 1 AND sum(case when hash = '' then 1 else 0 end) > 0").
+		Scan(&collisions).Error
+	if err != nil {
+		progressChan <- "Error finding prefix hash collisions: " + err.Error()
+		return 0
+	}
+
+	resolved := 0
+	for _, key := range collisions {
+		var files []domain.ImageFile
+		db.Where("hash_algo = ? AND size = ? AND prefix_hash = ? AND hash = ''", key.HashAlgo, key.Size, key.PrefixHash).Find(&files)
+
+		for _, f := range files {
+			if checkpoint(ctx) != nil {
+				return resolved
+			}
+			hash, err := calculateFileHash(f.Path, ContentHashAlgorithm(key.HashAlgo), opts.Throttle)
+			if err != nil {
+				progressChan <- "Error hashing " + f.Path + ": " + err.Error()
+				continue
+			}
+			if err := db.Model(&domain.ImageFile{}).Where("id = ?", f.ID).Update("hash", hash).Error; err != nil {
+				progressChan <- "Error updating " + f.Path + ": " + err.Error()
+				continue
+			}
+			resolved++
+			progressChan <- "Full hash (prefix collision): " + f.Path
+		}
+	}
+
+	return resolved
+}
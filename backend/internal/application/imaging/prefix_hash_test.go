@@ -0,0 +1,50 @@
+package imaging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrefixHash(t *testing.T) {
+	dir := t.TempDir()
+	small := filepath.Join(dir, "small.bin")
+	large := filepath.Join(dir, "large.bin")
+	largeOther := filepath.Join(dir, "large_other.bin")
+
+	if err := os.WriteFile(small, []byte("tiny file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte{0xAB}, prefixHashSize*2)
+	if err := os.WriteFile(large, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Same prefix, different tail
+	data[len(data)-1] = 0xCD
+	if err := os.WriteFile(largeOther, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, algo := range []ContentHashAlgorithm{ContentHashMD5, ContentHashSHA256, ContentHashXXH64, ContentHashBLAKE3} {
+		prefix, err := calculatePrefixHash(small, algo, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", algo, err)
+		}
+		full, _ := calculateFileHash(small, algo, nil)
+		if prefix != full {
+			t.Errorf("%s: prefix hash of small file %s differs from full hash %s", algo, prefix, full)
+		}
+
+		p1, _ := calculatePrefixHash(large, algo, nil)
+		p2, _ := calculatePrefixHash(largeOther, algo, nil)
+		if p1 != p2 {
+			t.Errorf("%s: files with identical first 64KB have different prefix hashes", algo)
+		}
+		f1, _ := calculateFileHash(large, algo, nil)
+		f2, _ := calculateFileHash(largeOther, algo, nil)
+		if f1 == f2 {
+			t.Errorf("%s: files with different tails have equal full hashes", algo)
+		}
+	}
+}
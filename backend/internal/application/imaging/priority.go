@@ -0,0 +1,12 @@
+package imaging
+
+import "errors"
+
+var ErrIdlePriorityUnsupported = errors.New("idle priority is not supported on this platform")
+
+// SetIdlePriority lowers the CPU and disk priority of the whole process to idle, so
+// scans yield to every other program on the machine (e.g. a media server on a NAS).
+// The priority is lowered for good: API requests are served at idle priority too.
+func SetIdlePriority() error {
+	return setIdlePriority()
+}
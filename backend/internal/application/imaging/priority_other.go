@@ -0,0 +1,7 @@
+//go:build !unix && !windows
+
+package imaging
+
+func setIdlePriority() error {
+	return ErrIdlePriorityUnsupported
+}
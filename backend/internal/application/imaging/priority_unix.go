@@ -0,0 +1,11 @@
+//go:build unix && !linux
+
+package imaging
+
+import "golang.org/x/sys/unix"
+
+// setIdlePriority sets nice 19 for the process; disk priority has no portable
+// interface on these systems and is left alone
+func setIdlePriority() error {
+	return unix.Setpriority(unix.PRIO_PROCESS, 0, 19)
+}
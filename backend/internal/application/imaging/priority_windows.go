@@ -0,0 +1,13 @@
+package imaging
+
+import "golang.org/x/sys/windows"
+
+// setIdlePriority moves the process to the idle priority class and to background
+// processing mode, which also lowers its I/O and memory priority
+func setIdlePriority() error {
+	process := windows.CurrentProcess()
+	if err := windows.SetPriorityClass(process, windows.IDLE_PRIORITY_CLASS); err != nil {
+		return err
+	}
+	return windows.SetPriorityClass(process, windows.PROCESS_MODE_BACKGROUND_BEGIN)
+}
@@ -0,0 +1,74 @@
+package imaging
+
+import (
+	"path/filepath"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// SetProtected pins the indexed files with the given paths, so delete requests leave
+// them alone, or unpins them. It returns how many indexed files were changed.
+func SetProtected(db *gorm.DB, paths []string, protected bool) (int64, error) {
+	const dbBatchSize = 500
+	var changed int64
+	for i := 0; i < len(paths); i += dbBatchSize {
+		end := min(i+dbBatchSize, len(paths))
+		result := db.Model(&domain.ImageFile{}).
+			Where("path IN ? AND protected <> ?", slashPaths(paths[i:end]), protected).
+			Update("protected", protected)
+		if result.Error != nil {
+			return changed, result.Error
+		}
+		changed += result.RowsAffected
+	}
+	return changed, nil
+}
+
+// ProtectedPaths returns the given paths whose files are pinned against deletion
+func ProtectedPaths(db *gorm.DB, paths []string) (map[string]bool, error) {
+	const dbBatchSize = 500
+	protected := make(map[string]bool)
+	for i := 0; i < len(paths); i += dbBatchSize {
+		end := min(i+dbBatchSize, len(paths))
+		batch := paths[i:end]
+		var found []string
+		if err := db.Model(&domain.ImageFile{}).
+			Where("path IN ? AND protected = ?", slashPaths(batch), true).
+			Pluck("path", &found).Error; err != nil {
+			return nil, err
+		}
+		pinned := make(map[string]bool, len(found))
+		for _, p := range found {
+			pinned[p] = true
+		}
+		for _, p := range batch {
+			if pinned[filepath.ToSlash(p)] {
+				protected[p] = true
+			}
+		}
+	}
+	return protected, nil
+}
+
+// RemovableCopies returns the files of a duplicate group to suggest for deletion:
+// all but the one kept at index keep and the pinned ones
+func RemovableCopies(files []domain.ImageFile, keep int) []domain.ImageFile {
+	var removable []domain.ImageFile
+	for i, file := range files {
+		if i != keep && !file.Protected {
+			removable = append(removable, file)
+		}
+	}
+	return removable
+}
+
+// slashPaths returns paths in the form stored in the index
+func slashPaths(paths []string) []string {
+	slashed := make([]string, len(paths))
+	for i, p := range paths {
+		slashed[i] = filepath.ToSlash(p)
+	}
+	return slashed
+}
@@ -0,0 +1,73 @@
+package imaging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestProtectedFiles(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.jpg"), filepath.Join(dir, "b.jpg")
+	for _, path := range []string{a, b} {
+		if err := os.WriteFile(path, []byte("same"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scan := func() {
+		progress := make(chan string, 100)
+		if err := scanDirectory(context.Background(), db, dir, progress, ScanOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scan()
+
+	if n, err := SetProtected(db, []string{a, filepath.Join(dir, "unknown.jpg")}, true); err != nil || n != 1 {
+		t.Fatalf("SetProtected = %d, %v; want 1 file", n, err)
+	}
+	// Pinning a pinned file changes nothing
+	if n, _ := SetProtected(db, []string{a}, true); n != 0 {
+		t.Errorf("pinning again changed %d files", n)
+	}
+
+	// A rescan of the modified file keeps the pin
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(a, later, later); err != nil {
+		t.Fatal(err)
+	}
+	scan()
+	protected, err := ProtectedPaths(db, []string{a, b})
+	if err != nil || len(protected) != 1 || !protected[a] {
+		t.Fatalf("ProtectedPaths after rescan = %v, %v; want only %s", protected, err, a)
+	}
+
+	if n, err := SetProtected(db, []string{a}, false); err != nil || n != 1 {
+		t.Fatalf("unpin = %d, %v", n, err)
+	}
+	if protected, _ := ProtectedPaths(db, []string{a, b}); len(protected) != 0 {
+		t.Errorf("after unpinning: got %v", protected)
+	}
+}
+
+func TestRemovableCopiesSkipsPinnedFiles(t *testing.T) {
+	files := []domain.ImageFile{
+		{Path: "/photos/a.jpg"},
+		{Path: "/backup/a.jpg", Protected: true},
+		{Path: "/downloads/a.jpg"},
+	}
+	removable := RemovableCopies(files, 0)
+	if len(removable) != 1 || removable[0].Path != "/downloads/a.jpg" {
+		t.Errorf("RemovableCopies = %+v, want only /downloads/a.jpg", removable)
+	}
+	// A pinned keeper stays, as any keeper does
+	files[1].Protected = false
+	files[0].Protected = true
+	if removable := RemovableCopies(files, 0); len(removable) != 2 {
+		t.Errorf("RemovableCopies with a pinned keeper = %+v, want both copies", removable)
+	}
+}
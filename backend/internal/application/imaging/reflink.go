@@ -0,0 +1,44 @@
+package imaging
+
+import (
+	"errors"
+	"os"
+)
+
+var ErrReflinkUnsupported = errors.New("copy-on-write clones are not supported on this platform")
+
+// ReplaceWithClone replaces the file at path with a copy-on-write clone of keep, so
+// both share their data extents on disk while path stays a regular file. The clone
+// inherits the permissions and modification time of the original. The content is
+// compared byte by byte first, as the file may have changed since it was hashed, and
+// the clone is created under a temporary name and renamed into place, so on failure
+// path is left as it was. Requires a filesystem with reflink support (Btrfs, XFS,
+// APFS) and both files on the same filesystem.
+func ReplaceWithClone(path, keep string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := VerifyIdentical(path, keep); err != nil {
+		return err
+	}
+
+	tmp := path + ".dedup-clone"
+	if err := cloneFile(keep, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, info.Mode().Perm()); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chtimes(tmp, info.ModTime(), info.ModTime()); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
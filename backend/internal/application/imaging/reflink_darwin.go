@@ -0,0 +1,17 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile creates dst sharing the data extents of src (clonefile(2))
+func cloneFile(src, dst string) error {
+	err := unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW)
+	if errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EXDEV) {
+		return fmt.Errorf("%w: %v", ErrReflinkUnsupported, err)
+	}
+	return err
+}
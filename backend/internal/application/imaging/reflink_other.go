@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package imaging
+
+// cloneFile is unavailable: only Linux (FICLONE) and macOS (clonefile) are supported
+func cloneFile(src, dst string) error {
+	return ErrReflinkUnsupported
+}
@@ -0,0 +1,79 @@
+package imaging
+
+import (
+	"context"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// rehashStaleFiles recomputes content hashes of all rows whose hash was computed with an
+// algorithm other than the configured one. Rows are processed in id order in batches so
+// large libraries can be migrated without loading every record at once.
+// Returns the number of rows updated.
+func rehashStaleFiles(ctx context.Context, db *gorm.DB, progressChan chan<- string, opts ScanOptions) int {
+	algo := opts.contentHashAlgorithm()
+	updated := 0
+
+	const batchSize = 500
+	var lastID uint
+	for {
+		if ctx.Err() != nil {
+			return updated
+		}
+		var batch []domain.ImageFile
+		if err := db.Where("hash_algo <> ? AND id > ?", string(algo), lastID).
+			Order("id").Limit(batchSize).Find(&batch).Error; err != nil {
+			progressChan <- "Error loading files to rehash: " + err.Error()
+			return updated
+		}
+		if len(batch) == 0 {
+			if opts.TwoStageHash {
+				resolvePrefixCollisions(ctx, db, progressChan, opts)
+			}
+			return updated
+		}
+		lastID = batch[len(batch)-1].ID
+
+		n := rehashRows(ctx, db, batch, progressChan, opts)
+		tallyOf(ctx).count(0, n, 0)
+		updated += n
+	}
+}
+
+// rehashRows computes content hashes for existing rows in parallel and stores them.
+// Only the content hash columns are updated, perceptual hashes are left untouched.
+// Returns the number of rows updated.
+func rehashRows(ctx context.Context, db *gorm.DB, rows []domain.ImageFile, progressChan chan<- string, opts ScanOptions) int {
+	contentOpts := opts
+	contentOpts.PerceptualHash = false
+
+	files := make([]fileInfo, len(rows))
+	existing := make(map[string]*domain.ImageFile, len(rows))
+	for i := range rows {
+		files[i] = fileInfo{path: rows[i].Path, normalizedPath: rows[i].Path, size: rows[i].Size}
+		existing[rows[i].Path] = &rows[i]
+	}
+
+	results := hashInParallel(ctx, files, opts.workerCount(), func(fi fileInfo) hashResult {
+		return hashFile(fi, existing[fi.normalizedPath], contentOpts)
+	})
+
+	updated := 0
+	for result := range results {
+		if result.err != nil {
+			progressChan <- "Error hashing " + result.fi.path + ": " + result.err.Error()
+			continue
+		}
+		err := db.Model(&domain.ImageFile{}).Where("id = ?", result.existing.ID).
+			Updates(map[string]interface{}{"hash": result.hash, "prefix_hash": result.prefixHash, "hash_algo": result.hashAlgo}).Error
+		if err != nil {
+			progressChan <- "Error updating " + result.fi.path + ": " + err.Error()
+			continue
+		}
+		updated++
+		progressChan <- "Hashed: " + result.fi.path
+	}
+	return updated
+}
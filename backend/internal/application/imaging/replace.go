@@ -0,0 +1,106 @@
+package imaging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ReplaceMode selects what is left in place of a removed duplicate
+type ReplaceMode string
+
+const (
+	ReplaceNone            ReplaceMode = ""                 // nothing: the file is deleted or moved to the trash
+	ReplaceRelativeSymlink ReplaceMode = "relative-symlink" // symlink relative to the link's directory, survives moving the whole tree
+	ReplaceAbsoluteSymlink ReplaceMode = "absolute-symlink" // symlink with the absolute path of the kept file
+	ReplaceReflink         ReplaceMode = "reflink"          // copy-on-write clone of the kept file; the path stays a regular file
+)
+
+var ErrNoKeptCopy = errors.New("no remaining copy to link to")
+
+// Valid reports whether m is a known replace mode
+func (m ReplaceMode) Valid() bool {
+	switch m {
+	case ReplaceNone, ReplaceRelativeSymlink, ReplaceAbsoluteSymlink, ReplaceReflink:
+		return true
+	}
+	return false
+}
+
+// KeepsFile reports whether the path stays an indexed regular file after replacement
+func (m ReplaceMode) KeepsFile() bool {
+	return m == ReplaceReflink
+}
+
+// linkTarget returns the target of a symlink at path pointing to keep
+func (m ReplaceMode) linkTarget(path, keep string) (string, error) {
+	keep, err := filepath.Abs(filepath.FromSlash(keep))
+	if err != nil {
+		return "", err
+	}
+	if m == ReplaceAbsoluteSymlink {
+		return keep, nil
+	}
+	path, err = filepath.Abs(filepath.FromSlash(path))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(filepath.Dir(path), keep)
+}
+
+// ReplaceWithSymlink replaces the file at path with a symlink to keep. When trashPath
+// is set the file is moved there first, otherwise it is deleted. The link is created
+// under a temporary name and renamed into place, so on failure path is left as it was.
+func ReplaceWithSymlink(path, keep, trashPath string, mode ReplaceMode) error {
+	target, err := mode.linkTarget(path, keep)
+	if err != nil {
+		return err
+	}
+	path = longPath(path)
+	if trashPath != "" {
+		trashPath = longPath(trashPath)
+	}
+	tmp := path + ".dedup-link"
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if trashPath != "" {
+		if err := os.Rename(path, trashPath); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		if trashPath != "" {
+			os.Rename(trashPath, path)
+		}
+		return err
+	}
+	return nil
+}
+
+// KeptCopy returns the path of an indexed, existing file with the same content as
+// path that is not about to be removed, for use as a symlink target or clone source
+func KeptCopy(db *gorm.DB, path string, removing map[string]bool) (string, error) {
+	var file domain.ImageFile
+	if err := db.Where("path = ?", filepath.ToSlash(path)).First(&file).Error; err != nil || file.Hash == "" {
+		return "", ErrNoKeptCopy // not indexed, or content not hashed yet
+	}
+	var copies []domain.ImageFile
+	db.Where("hash = ? AND size = ? AND hash_algo = ? AND id <> ?", file.Hash, file.Size, file.HashAlgo, file.ID).
+		Order("path").Find(&copies)
+	for _, c := range copies {
+		if removing[c.Path] || removing[filepath.FromSlash(c.Path)] || isSymlinkPath(c.Path) {
+			continue
+		}
+		if _, err := os.Stat(c.Path); err == nil {
+			return c.Path, nil
+		}
+	}
+	return "", ErrNoKeptCopy
+}
@@ -0,0 +1,101 @@
+package imaging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplaceWithSymlink(t *testing.T) {
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "keep", "a.jpg")
+	trash := filepath.Join(dir, "trash")
+	for _, d := range []string{filepath.Dir(keep), filepath.Join(dir, "dup"), trash} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(keep, []byte("content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		mode       ReplaceMode
+		trash      bool
+		wantTarget string
+	}{
+		{ReplaceRelativeSymlink, false, filepath.Join("..", "keep", "a.jpg")},
+		{ReplaceAbsoluteSymlink, true, keep},
+	}
+	for _, c := range cases {
+		dup := filepath.Join(dir, "dup", "a.jpg")
+		if err := os.WriteFile(dup, []byte("content"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		trashPath := ""
+		if c.trash {
+			trashPath = filepath.Join(trash, "a.jpg")
+		}
+
+		if err := ReplaceWithSymlink(dup, keep, trashPath, c.mode); err != nil {
+			if os.IsPermission(err) {
+				t.Skipf("symlinks not permitted: %v", err)
+			}
+			t.Fatalf("%s: %v", c.mode, err)
+		}
+		target, err := os.Readlink(dup)
+		if err != nil {
+			t.Fatalf("%s: not a symlink: %v", c.mode, err)
+		}
+		if target != c.wantTarget {
+			t.Errorf("%s: link target = %q, want %q", c.mode, target, c.wantTarget)
+		}
+		if data, err := os.ReadFile(dup); err != nil || string(data) != "content" {
+			t.Errorf("%s: link does not resolve to the kept file: %q, %v", c.mode, data, err)
+		}
+		if c.trash {
+			if _, err := os.Stat(trashPath); err != nil {
+				t.Errorf("%s: original not moved to trash: %v", c.mode, err)
+			}
+		}
+		os.Remove(dup)
+	}
+
+	if ReplaceMode("hardlink").Valid() || !ReplaceNone.Valid() {
+		t.Error("Valid accepts unknown modes or rejects the empty mode")
+	}
+}
+
+func TestReplaceWithClone(t *testing.T) {
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "keep.jpg")
+	dup := filepath.Join(dir, "dup.jpg")
+	other := filepath.Join(dir, "other.jpg")
+	for path, content := range map[string]string{keep: "content", dup: "content", other: "contenT"} {
+		if err := os.WriteFile(path, []byte(content), 0o640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ReplaceWithClone(other, keep); !errors.Is(err, ErrContentMismatch) {
+		t.Errorf("differing content: got %v", err)
+	}
+	before, _ := os.Stat(dup)
+	if err := ReplaceWithClone(dup, keep); err != nil {
+		if errors.Is(err, ErrReflinkUnsupported) {
+			t.Skipf("no reflink support: %v", err)
+		}
+		t.Fatal(err)
+	}
+	after, err := os.Stat(dup)
+	if err != nil || !after.Mode().IsRegular() {
+		t.Fatalf("clone is not a regular file: %v", err)
+	}
+	if after.Mode() != before.Mode() || !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("metadata not preserved: %v %v, want %v %v", after.Mode(), after.ModTime(), before.Mode(), before.ModTime())
+	}
+	if data, _ := os.ReadFile(dup); string(data) != "content" {
+		t.Errorf("clone content = %q", data)
+	}
+}
@@ -0,0 +1,81 @@
+package imaging
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DuplicateReport summarises the exact duplicate groups for exports and headless runs
+type DuplicateReport struct {
+	GeneratedAt time.Time     `json:"generatedAt"`
+	TotalGroups int           `json:"totalGroups"`
+	TotalFiles  int           `json:"totalFiles"`
+	WastedBytes int64         `json:"wastedBytes"` // bytes freed by keeping one file per group
+	Groups      []ReportGroup `json:"groups"`
+}
+
+// ReportGroup is one set of identical files
+type ReportGroup struct {
+	Hash        string       `json:"hash"`
+	HashAlgo    string       `json:"hashAlgo"`
+	Size        int64        `json:"size"`
+	WastedBytes int64        `json:"wastedBytes"`
+	Files       []ReportFile `json:"files"`
+}
+
+// ReportFile is a file of a duplicate group
+type ReportFile struct {
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// BuildDuplicateReport collects all exact duplicate groups matching the filter,
+// largest waste first. Records of files that no longer exist on disk are removed on the way.
+func BuildDuplicateReport(db *gorm.DB, filter DuplicateFilter) (*DuplicateReport, error) {
+	groups, err := findDuplicates(db, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DuplicateReport{GeneratedAt: time.Now(), Groups: make([]ReportGroup, 0, len(groups))}
+	for _, g := range groups {
+		rg := ReportGroup{
+			Hash:        g.Hash,
+			HashAlgo:    g.Files[0].HashAlgo,
+			Size:        g.Size,
+			WastedBytes: g.Size * int64(len(g.Files)-1),
+			Files:       make([]ReportFile, len(g.Files)),
+		}
+		for i, f := range g.Files {
+			rg.Files[i] = ReportFile{Path: f.Path, ModTime: f.ModTime}
+		}
+		sort.Slice(rg.Files, func(i, j int) bool { return rg.Files[i].Path < rg.Files[j].Path })
+
+		report.Groups = append(report.Groups, rg)
+		report.TotalFiles += len(rg.Files)
+		report.WastedBytes += rg.WastedBytes
+	}
+	report.TotalGroups = len(report.Groups)
+
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		return report.Groups[i].WastedBytes > report.Groups[j].WastedBytes
+	})
+	return report, nil
+}
+
+// FormatSize formats a byte count in human readable form, e.g. "1.5 MB"
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,33 @@
+package imaging
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteCSVReport writes the duplicate report as CSV, one row per file. If w can be
+// flushed (an HTTP response), it is flushed after every group so that large exports
+// start downloading immediately.
+func WriteCSVReport(w io.Writer, report *DuplicateReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"group", "hash", "size", "path", "mod_time"})
+	flusher, _ := w.(interface{ Flush() })
+	for i, g := range report.Groups {
+		group := strconv.Itoa(i + 1)
+		size := strconv.FormatInt(g.Size, 10)
+		for _, f := range g.Files {
+			cw.Write([]string{group, g.Hash, size, f.Path, f.ModTime.Format(time.RFC3339)})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
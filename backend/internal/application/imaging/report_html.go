@@ -0,0 +1,92 @@
+package imaging
+
+import (
+	"html/template"
+	"io"
+	"sync"
+)
+
+// reportThumbnailWorkers bounds parallel thumbnail generation for HTML reports
+const reportThumbnailWorkers = 8
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"formatSize": FormatSize,
+	"inc":        func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Duplicate report {{.Report.GeneratedAt.Format "2006-01-02 15:04"}}</title>
+<style>
+body{font-family:system-ui,-apple-system,"Segoe UI",sans-serif;margin:0;padding:24px;background:#f6f7f9;color:#1f2328}
+h1{font-size:22px;margin:0 0 4px}
+.muted{color:#656d76;font-size:13px}
+.stats{display:flex;flex-wrap:wrap;gap:12px;margin:16px 0 24px}
+.stat{background:#fff;border:1px solid #d0d7de;border-radius:8px;padding:12px 16px;min-width:140px}
+.stat b{display:block;font-size:20px}
+.group{display:flex;gap:16px;background:#fff;border:1px solid #d0d7de;border-radius:8px;padding:12px;margin-bottom:12px}
+.thumb{flex:0 0 160px;height:160px;display:flex;align-items:center;justify-content:center;background:#eaeef2;border-radius:6px;overflow:hidden}
+.thumb img{max-width:100%;max-height:100%}
+.info{flex:1;min-width:0}
+.info h2{font-size:15px;margin:0 0 6px}
+.hash{font-family:ui-monospace,monospace;font-size:12px;word-break:break-all}
+table{border-collapse:collapse;width:100%;margin-top:8px;font-size:13px}
+td{padding:3px 8px 3px 0;border-top:1px solid #eaeef2;vertical-align:top}
+td.path{font-family:ui-monospace,monospace;word-break:break-all}
+td.date{white-space:nowrap;color:#656d76}
+</style>
+</head>
+<body>
+<h1>Duplicate report</h1>
+<div class="muted">Generated {{.Report.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</div>
+<div class="stats">
+<div class="stat"><b>{{.Report.TotalGroups}}</b>duplicate groups</div>
+<div class="stat"><b>{{.Report.TotalFiles}}</b>files</div>
+<div class="stat"><b>{{formatSize .Report.WastedBytes}}</b>reclaimable</div>
+</div>
+{{if not .Report.Groups}}<p>No duplicates found.</p>{{end}}
+{{range $i, $g := .Report.Groups}}
+<div class="group">
+<div class="thumb">{{with index $.Thumbnails $i}}<img src="{{.}}" alt="">{{end}}</div>
+<div class="info">
+<h2>#{{inc $i}} &middot; {{len $g.Files}} files &times; {{formatSize $g.Size}} &middot; {{formatSize $g.WastedBytes}} reclaimable</h2>
+<div class="hash">{{$g.HashAlgo}}: {{$g.Hash}}</div>
+<table>
+{{range $g.Files}}<tr><td class="path">{{.Path}}</td><td class="date">{{.ModTime.Format "2006-01-02 15:04"}}</td></tr>
+{{end}}</table>
+</div>
+</div>
+{{end}}
+</body>
+</html>
+`))
+
+// WriteHTMLReport renders the report as a standalone HTML page. thumbnail returns a
+// data URL for a file path; the first file of every group is embedded as its preview.
+// Groups whose thumbnail fails are rendered without a preview.
+func WriteHTMLReport(w io.Writer, report *DuplicateReport, thumbnail func(path string) (string, error)) error {
+	thumbs := make([]template.URL, len(report.Groups))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, reportThumbnailWorkers)
+	for i, g := range report.Groups {
+		wg.Add(1)
+		go func(idx int, path string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if thumb, err := thumbnail(path); err == nil {
+				// Data URLs come from our own encoder, so they are safe to embed as-is
+				thumbs[idx] = template.URL(thumb)
+			}
+		}(i, g.Files[0].Path)
+	}
+	wg.Wait()
+
+	return htmlReportTemplate.Execute(w, struct {
+		Report     *DuplicateReport
+		Thumbnails []template.URL
+	}{report, thumbs})
+}
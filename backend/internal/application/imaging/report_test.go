@@ -0,0 +1,65 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024 * 1024, "5.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.size); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	report := &DuplicateReport{
+		GeneratedAt: time.Now(),
+		TotalGroups: 2,
+		TotalFiles:  4,
+		WastedBytes: 3072,
+		Groups: []ReportGroup{
+			{Hash: "aaa", Size: 2048, WastedBytes: 2048, Files: []ReportFile{{Path: "/p/<a>.jpg"}, {Path: "/q/a.jpg"}}},
+			{Hash: "bbb", Size: 1024, WastedBytes: 1024, Files: []ReportFile{{Path: "/p/b.jpg"}, {Path: "/q/b.jpg"}}},
+		},
+	}
+	thumbnail := func(path string) (string, error) {
+		if path == "/p/b.jpg" {
+			return "", errors.New("decode failed")
+		}
+		return "data:image/webp;base64,AAAA", nil
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(&buf, report, thumbnail); err != nil {
+		t.Fatalf("WriteHTMLReport: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, `src="data:image/webp;base64,AAAA"`) {
+		t.Error("thumbnail data URL not embedded")
+	}
+	if strings.Count(out, "<img") != 1 {
+		t.Errorf("expected 1 embedded thumbnail, got %d", strings.Count(out, "<img"))
+	}
+	if strings.Contains(out, "<a>.jpg") || !strings.Contains(out, "&lt;a&gt;.jpg") {
+		t.Error("file paths must be HTML-escaped")
+	}
+	if !strings.Contains(out, "3.0 KB") {
+		t.Error("total reclaimable size missing")
+	}
+}
@@ -0,0 +1,98 @@
+package imaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+
+	"gorm.io/gorm"
+)
+
+// ErrRootUnavailable is returned for a gallery root that is missing or looks
+// unmounted. Such a root is neither scanned nor cleaned up, so its records survive
+// until it is back instead of being removed and hashed all over again.
+var ErrRootUnavailable = errors.New("gallery folder is not accessible")
+
+// checkRoot reports whether a local gallery root can be scanned. A root is
+// unavailable when it is missing, is not a directory, or is empty while the index
+// holds files under it - the mount point of an unmounted network share.
+func checkRoot(db *gorm.DB, root string, opts ScanOptions) error {
+	if objectstore.IsRemote(root) {
+		return nil // object store errors surface from the listing
+	}
+	absPath, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrRootUnavailable, root, err)
+	}
+	dir, err := os.Open(longPath(absPath))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrRootUnavailable, root, err)
+	}
+	defer dir.Close()
+	info, err := dir.Stat()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrRootUnavailable, root, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrRootUnavailable, root)
+	}
+	if names, _ := dir.Readdirnames(1); len(names) > 0 {
+		return nil
+	}
+	var indexed []domain.ImageFile
+	if err := db.Select("id").Where(opts.pathColumn()+` LIKE ? ESCAPE '\'`, rootPrefix(absPath, opts)).Limit(1).Find(&indexed).Error; err != nil {
+		return err
+	}
+	if len(indexed) > 0 {
+		return fmt.Errorf("%w: %s is empty but has indexed files, is it mounted?", ErrRootUnavailable, root)
+	}
+	return nil
+}
+
+// splitRoots separates the gallery roots that can be scanned from the unavailable
+// ones, warning about the latter
+func splitRoots(db *gorm.DB, roots []string, progressChan chan<- string, opts ScanOptions) (available, unavailable []string) {
+	for _, root := range roots {
+		if err := checkRoot(db, root, opts); err != nil {
+			slog.Warn("Skipping gallery folder", "folder", root, "error", err)
+			progressChan <- fmt.Sprintf("Skipping %v", err)
+			unavailable = append(unavailable, root)
+			continue
+		}
+		available = append(available, root)
+	}
+	return available, unavailable
+}
+
+type unavailableRootsKey struct{}
+
+// withUnavailableRoots attaches the unavailable gallery roots to a scan context,
+// keeping the missing file cleanup away from their records
+func withUnavailableRoots(ctx context.Context, roots []string) context.Context {
+	if len(roots) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, unavailableRootsKey{}, roots)
+}
+
+// unavailableRootsOf returns the unavailable gallery roots of a scan context
+func unavailableRootsOf(ctx context.Context) []string {
+	roots, _ := ctx.Value(unavailableRootsKey{}).([]string)
+	return roots
+}
+
+// rootPrefix returns the LIKE pattern of the records under a local root, to be
+// matched with ESCAPE '\'
+func rootPrefix(root string, opts ScanOptions) string {
+	if absPath, err := filepath.Abs(root); err == nil {
+		root = absPath
+	}
+	return escapeLike(opts.pathKey(strings.TrimSuffix(filepath.ToSlash(root), "/"))) + "/%"
+}
@@ -0,0 +1,93 @@
+package imaging
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestUnavailableRootsAreSkipped(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	base := t.TempDir()
+	mounted := filepath.Join(base, "mounted")
+	unmounted := filepath.Join(base, "unmounted")
+	fresh := filepath.Join(base, "fresh")
+	for _, dir := range []string{mounted, unmounted, fresh} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(mounted, "a.jpg"), []byte("photo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(mounted, "gone.jpg"), filepath.Join(unmounted, "b.jpg")} {
+		f := domain.ImageFile{Path: filepath.ToSlash(path), Hash: "h", HashAlgo: "sha256", Size: 5, ModTime: time.Now()}
+		if err := db.Create(&f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string]bool{
+		mounted:                        true,
+		fresh:                          true, // empty, but nothing indexed under it yet
+		unmounted:                      false,
+		filepath.Join(base, "missing"): false,
+	}
+	for root, want := range cases {
+		err := checkRoot(db, root, ScanOptions{})
+		if got := err == nil; got != want {
+			t.Errorf("checkRoot(%s) = %v, want available %v", root, err, want)
+		}
+		if err != nil && !errors.Is(err, ErrRootUnavailable) {
+			t.Errorf("checkRoot(%s) = %v, want ErrRootUnavailable", root, err)
+		}
+	}
+
+	// The cleanup removes missing files of available roots only
+	progress := make(chan string, 100)
+	available, unavailable := splitRoots(db, []string{mounted, unmounted}, progress, ScanOptions{})
+	if len(available) != 1 || len(unavailable) != 1 || unavailable[0] != unmounted {
+		t.Fatalf("splitRoots = %v, %v", available, unavailable)
+	}
+	ctx := withUnavailableRoots(context.Background(), unavailable)
+	if err := cleanupMissingFiles(ctx, db, "", progress, ScanOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	db.Model(&domain.ImageFile{}).Order("path").Pluck("path", &left)
+	if len(left) != 1 || left[0] != filepath.ToSlash(filepath.Join(unmounted, "b.jpg")) {
+		t.Errorf("records left after cleanup = %v, want only the unmounted one", left)
+	}
+}
+
+func TestRootPrefixMatchesLiterally(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	base := t.TempDir()
+	root := filepath.Join(base, "a_b")
+	if err := os.Mkdir(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A missing file of a sibling folder whose name differs only at the "_"
+	sibling := domain.ImageFile{Path: filepath.ToSlash(filepath.Join(base, "aXb", "gone.jpg")), Hash: "h", HashAlgo: "sha256", Size: 5, ModTime: time.Now()}
+	if err := db.Create(&sibling).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkRoot(db, root, ScanOptions{}); err != nil {
+		t.Errorf("checkRoot(%s) = %v, want available: nothing is indexed under it", root, err)
+	}
+	ctx := withUnavailableRoots(context.Background(), []string{root})
+	if err := cleanupMissingFiles(ctx, db, "", make(chan string, 100), ScanOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var left int64
+	db.Model(&domain.ImageFile{}).Count(&left)
+	if left != 0 {
+		t.Errorf("%d records left, want the missing file of the sibling folder removed", left)
+	}
+}
@@ -0,0 +1,137 @@
+package imaging
+
+import (
+	"sort"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// maxAspectRatioDeviation is how far (relative) the aspect ratio of a downscaled copy
+// may differ from the original, allowing for rounding of odd dimensions
+const maxAspectRatioDeviation = 0.02
+
+// sameAspectRatio reports whether b has the aspect ratio of a within tolerance
+func sameAspectRatio(a, b domain.Dimensions) bool {
+	// Cross-multiplied to compare a.W/a.H with b.W/b.H without division
+	lhs := int64(a.Width) * int64(b.Height)
+	rhs := int64(b.Width) * int64(a.Height)
+	diff := lhs - rhs
+	if diff < 0 {
+		diff = -diff
+	}
+	return float64(diff) <= maxAspectRatioDeviation*float64(lhs)
+}
+
+// scaledGroup narrows a group of similar files to the file with the highest resolution
+// and the copies of it with the same aspect ratio. Files without dimensions are
+// dropped. ok is false when fewer than two resolutions remain, as then nothing in the
+// group is a resized copy.
+func scaledGroup(group domain.DuplicateGroup, dims map[uint]domain.Dimensions) (domain.DuplicateGroup, bool) {
+	type member struct {
+		file domain.ImageFile
+		dims domain.Dimensions
+	}
+	var members []member
+	for _, f := range group.Files {
+		if d, ok := dims[f.ID]; ok && d.Width > 0 && d.Height > 0 {
+			members = append(members, member{f, d})
+		}
+	}
+	if len(members) < 2 {
+		return domain.DuplicateGroup{}, false
+	}
+	// Highest resolution first, then the larger file (less compressed) and path for stability
+	sort.Slice(members, func(i, j int) bool {
+		pi := int64(members[i].dims.Width) * int64(members[i].dims.Height)
+		pj := int64(members[j].dims.Width) * int64(members[j].dims.Height)
+		if pi != pj {
+			return pi > pj
+		}
+		if members[i].file.Size != members[j].file.Size {
+			return members[i].file.Size > members[j].file.Size
+		}
+		return members[i].file.Path < members[j].file.Path
+	})
+
+	original := members[0]
+	reference, _ := parsePerceptualHash(original.file.PHash)
+	scaled := domain.DuplicateGroup{Hash: original.file.PHash, Size: original.file.Size}
+	resized := false
+	for _, m := range members {
+		if !sameAspectRatio(original.dims, m.dims) {
+			continue
+		}
+		if m.dims != original.dims {
+			resized = true
+		}
+		h, _ := parsePerceptualHash(m.file.PHash)
+		distance := orientedDistance(reference, parsePerceptualHashes(original.file.PHashXform), h)
+		scaled.Files = append(scaled.Files, m.file)
+		scaled.Similarity = append(scaled.Similarity, similarityScore(distance))
+		scaled.Dimensions = append(scaled.Dimensions, m.dims)
+	}
+	return scaled, resized
+}
+
+// findScaledGroups finds images that are similar by perceptual hash and differ in
+// resolution but not in aspect ratio, such as an original and its thumbnails or
+// exports. Dimensions come from extracted metadata. Groups are ordered by the size of
+// their original, descending.
+func findScaledGroups(db *gorm.DB, filter DuplicateFilter, threshold int) ([]domain.DuplicateGroup, error) {
+	similar, err := findSimilarGroups(db, filter, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	for _, g := range similar {
+		for _, f := range g.Files {
+			ids = append(ids, f.ID)
+		}
+	}
+	dims := loadDimensions(db, ids)
+
+	var groups []domain.DuplicateGroup
+	for _, g := range similar {
+		if scaled, ok := scaledGroup(g, dims); ok {
+			groups = append(groups, scaled)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Size != groups[j].Size {
+			return groups[i].Size > groups[j].Size
+		}
+		return groups[i].Hash < groups[j].Hash
+	})
+	return groups, nil
+}
+
+// FindScaledPaginated finds groups of resized copies among the files matching the
+// filter in the given order, with pagination
+func FindScaledPaginated(db *gorm.DB, filter DuplicateFilter, order GroupOrder, offset, limit, threshold int) ([]domain.DuplicateGroup, int, int, error) {
+	allGroups, err := findScaledGroups(db, filter, threshold)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	sortGroups(allGroups, order)
+
+	totalGroups := len(allGroups)
+	totalFiles := 0
+	for _, g := range allGroups {
+		totalFiles += len(g.Files)
+	}
+
+	if offset >= totalGroups {
+		return []domain.DuplicateGroup{}, totalGroups, totalFiles, nil
+	}
+
+	end := offset + limit
+	if end > totalGroups {
+		end = totalGroups
+	}
+
+	return allGroups[offset:end], totalGroups, totalFiles, nil
+}
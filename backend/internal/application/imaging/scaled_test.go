@@ -0,0 +1,46 @@
+package imaging
+
+import (
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestScaledGroup(t *testing.T) {
+	group := domain.DuplicateGroup{Files: []domain.ImageFile{
+		{ID: 1, Path: "/export/thumb.jpg", Size: 20_000},
+		{ID: 2, Path: "/photos/original.jpg", Size: 4_000_000},
+		{ID: 3, Path: "/export/web.jpg", Size: 300_000},
+		{ID: 4, Path: "/export/crop.jpg", Size: 250_000},
+		{ID: 5, Path: "/unknown.jpg", Size: 100_000},
+	}}
+	dims := map[uint]domain.Dimensions{
+		1: {Width: 201, Height: 150},
+		2: {Width: 4000, Height: 3000},
+		3: {Width: 1200, Height: 900},
+		4: {Width: 1000, Height: 1000},
+	}
+
+	scaled, ok := scaledGroup(group, dims)
+	if !ok {
+		t.Fatal("expected a scaled group")
+	}
+	want := []uint{2, 3, 1}
+	if len(scaled.Files) != len(want) {
+		t.Fatalf("got %d files, want %d", len(scaled.Files), len(want))
+	}
+	for i, id := range want {
+		if scaled.Files[i].ID != id {
+			t.Errorf("file %d: got ID %d, want %d", i, scaled.Files[i].ID, id)
+		}
+	}
+	if scaled.Size != 4_000_000 || scaled.Dimensions[0] != dims[2] {
+		t.Errorf("group should be described by the original, got size %d and %v", scaled.Size, scaled.Dimensions[0])
+	}
+
+	// Copies at the same resolution are not resized copies
+	same := map[uint]domain.Dimensions{1: {Width: 800, Height: 600}, 2: {Width: 800, Height: 600}}
+	if _, ok := scaledGroup(group, same); ok {
+		t.Error("a group with a single resolution should be dropped")
+	}
+}
@@ -0,0 +1,79 @@
+package imaging
+
+import (
+	"context"
+	"sync"
+)
+
+// pauseGate lets a running scan be suspended between files.
+// Workers call wait at every checkpoint and block while the gate is closed.
+type pauseGate struct {
+	mu     sync.Mutex
+	paused bool
+	resume chan struct{} // closed when the scan is resumed
+}
+
+func newPauseGate() *pauseGate {
+	return &pauseGate{}
+}
+
+// pause closes the gate. Returns false if it was already paused.
+func (g *pauseGate) pause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.paused {
+		return false
+	}
+	g.paused = true
+	g.resume = make(chan struct{})
+	return true
+}
+
+// unpause opens the gate and releases waiting workers. Returns false if it was not paused.
+func (g *pauseGate) unpause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.paused {
+		return false
+	}
+	g.paused = false
+	close(g.resume)
+	return true
+}
+
+// isPaused reports whether the gate is closed
+func (g *pauseGate) isPaused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.paused
+}
+
+// wait blocks while the gate is paused or until ctx is cancelled
+func (g *pauseGate) wait(ctx context.Context) error {
+	g.mu.Lock()
+	paused, resume := g.paused, g.resume
+	g.mu.Unlock()
+	if paused {
+		select {
+		case <-resume:
+		case <-ctx.Done():
+		}
+	}
+	return ctx.Err()
+}
+
+type pauseGateKey struct{}
+
+// withPauseGate attaches a pause gate to a scan context
+func withPauseGate(ctx context.Context, g *pauseGate) context.Context {
+	return context.WithValue(ctx, pauseGateKey{}, g)
+}
+
+// checkpoint is called by scan loops between units of work. It blocks while the scan
+// is paused and returns a non-nil error once the scan has been cancelled.
+func checkpoint(ctx context.Context) error {
+	if g, ok := ctx.Value(pauseGateKey{}).(*pauseGate); ok {
+		return g.wait(ctx)
+	}
+	return ctx.Err()
+}
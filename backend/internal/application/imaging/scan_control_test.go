@@ -0,0 +1,41 @@
+package imaging
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckpointPauseResumeCancel(t *testing.T) {
+	gate := newPauseGate()
+	ctx, cancel := context.WithCancel(withPauseGate(context.Background(), gate))
+	defer cancel()
+
+	if err := checkpoint(ctx); err != nil {
+		t.Fatalf("unexpected error before pause: %v", err)
+	}
+
+	if !gate.pause() || gate.pause() {
+		t.Fatal("pause should succeed exactly once")
+	}
+	done := make(chan error, 1)
+	go func() { done <- checkpoint(ctx) }()
+
+	select {
+	case <-done:
+		t.Fatal("checkpoint returned while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	gate.unpause()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error after resume: %v", err)
+	}
+
+	gate.pause()
+	go func() { done <- checkpoint(ctx) }()
+	cancel()
+	if err := <-done; err == nil {
+		t.Fatal("checkpoint should report cancellation while paused")
+	}
+}
@@ -0,0 +1,62 @@
+package imaging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// ScanJobState is the lifecycle state of a scan job
+type ScanJobState string
+
+const (
+	JobQueued    ScanJobState = "queued"
+	JobRunning   ScanJobState = "running"
+	JobDone      ScanJobState = "done"
+	JobFailed    ScanJobState = "failed"
+	JobCancelled ScanJobState = "cancelled"
+)
+
+// Scan job kinds
+const (
+	JobKindScan        = "scan"
+	JobKindScanDir     = "scan-dir"
+	JobKindFastScan    = "fast-scan"
+	JobKindFastScanDir = "fast-scan-dir"
+	JobKindRehash      = "rehash"
+)
+
+// maxRetainedJobs bounds how many finished jobs are kept in memory for status lookups
+const maxRetainedJobs = 100
+
+// ScanJob describes one asynchronous scan started through the ScanManager
+type ScanJob struct {
+	ID             string
+	Kind           string
+	State          ScanJobState
+	Progress       string
+	FilesProcessed int
+	Errors         int    // progress messages reporting a per-file error
+	Error          string // reason the job failed
+	CreatedAt      time.Time
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+}
+
+// Elapsed returns how long the job has been running, or ran if it has finished
+func (j ScanJob) Elapsed(now time.Time) time.Duration {
+	if j.StartedAt == nil {
+		return 0
+	}
+	if j.FinishedAt != nil {
+		return j.FinishedAt.Sub(*j.StartedAt)
+	}
+	return now.Sub(*j.StartedAt)
+}
+
+// newJobID returns a random 16-character hex job identifier
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
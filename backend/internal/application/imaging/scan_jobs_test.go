@@ -0,0 +1,39 @@
+package imaging
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestScanJobElapsed(t *testing.T) {
+	now := time.Now()
+	started := now.Add(-5 * time.Second)
+	finished := started.Add(2 * time.Second)
+
+	if d := (ScanJob{}).Elapsed(now); d != 0 {
+		t.Errorf("queued job elapsed = %v, want 0", d)
+	}
+	if d := (ScanJob{StartedAt: &started}).Elapsed(now); d != 5*time.Second {
+		t.Errorf("running job elapsed = %v, want 5s", d)
+	}
+	if d := (ScanJob{StartedAt: &started, FinishedAt: &finished}).Elapsed(now); d != 2*time.Second {
+		t.Errorf("finished job elapsed = %v, want 2s", d)
+	}
+}
+
+func TestScanManagerRetainsRecentJobs(t *testing.T) {
+	sm := NewScanManager(nil, ScanOptions{})
+	for i := 0; i < maxRetainedJobs+10; i++ {
+		sm.addJobLocked(&ScanJob{ID: fmt.Sprintf("job-%d", i)})
+	}
+	if _, ok := sm.GetJob("job-0"); ok {
+		t.Error("oldest job should have been evicted")
+	}
+	if _, ok := sm.GetJob(fmt.Sprintf("job-%d", maxRetainedJobs+9)); !ok {
+		t.Error("newest job should be retained")
+	}
+	if len(sm.jobs) != maxRetainedJobs {
+		t.Errorf("retained %d jobs, want %d", len(sm.jobs), maxRetainedJobs)
+	}
+}
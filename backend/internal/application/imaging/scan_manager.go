@@ -1,10 +1,18 @@
 package imaging
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"strings"
 	"sync"
+	"time"
 
+	"image-toolkit/internal/application/events"
 	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
 
 	"gorm.io/gorm"
 )
@@ -12,8 +20,18 @@ import (
 // ScanStatusResponse is the JSON response for GET /api/status
 type ScanStatusResponse struct {
 	Scanning       bool   `json:"scanning"`
+	Paused         bool   `json:"paused"`
+	JobID          string `json:"jobId,omitempty"`
 	Progress       string `json:"progress"`
 	FilesProcessed int    `json:"filesProcessed"`
+	// Candidate files handled and expected, with the completion and remaining time
+	// estimated from them; omitted while the total is unknown
+	FilesDone  int     `json:"filesDone,omitempty"`
+	FilesTotal int     `json:"filesTotal,omitempty"`
+	Percent    float64 `json:"percent,omitempty"`
+	ETASeconds int64   `json:"etaSeconds,omitempty"`
+	// Gallery folders skipped by the last scan as missing or unmounted
+	UnavailableRoots []string `json:"unavailableRoots,omitempty"`
 }
 
 // FastScanResult holds the result of a fast scan operation
@@ -22,6 +40,7 @@ type FastScanResult struct {
 	Modified     int `json:"modified"`     // Files that were modified (size changed)
 	Created      int `json:"created"`      // New files added
 	Deleted      int `json:"deleted"`      // Records removed from DB (files no longer exist)
+	Moved        int `json:"moved"`        // Records pointed at the new path of a moved or renamed file
 	TotalChecked int `json:"totalChecked"` // Total files checked (modified + created)
 }
 
@@ -35,19 +54,37 @@ type ScanManager struct {
 	isScanning     bool
 	progress       string
 	filesProcessed int
+	job            *ScanJob      // job of the running scan
+	estimate       *scanProgress // progress estimate of the running scan
+	unavailable    []string      // gallery roots skipped by the last scan as unavailable
+	jobs           map[string]*ScanJob
+	jobOrder       []string           // job IDs, oldest first
+	cancel         context.CancelFunc // cancels the running scan
+	gate           *pauseGate         // pauses the running scan
+	lastStatusSent time.Time
 	db             *gorm.DB
-	scanWorkers    int
-	OnScanComplete func() // called after each scan finishes (if non-nil)
+	options        ScanOptions
+	Events         *events.Bus // receives scan status, finish and new duplicate events (if non-nil)
+	OnScanComplete func()      // called after each scan finishes (if non-nil)
 }
 
+// statusEventInterval throttles scan.status events while a scan is running
+const statusEventInterval = 250 * time.Millisecond
+
 // NewScanManager creates a new ScanManager
-func NewScanManager(db *gorm.DB, scanWorkers int) *ScanManager {
+func NewScanManager(db *gorm.DB, options ScanOptions) *ScanManager {
 	return &ScanManager{
-		db:          db,
-		scanWorkers: scanWorkers,
+		db:      db,
+		options: options,
+		jobs:    make(map[string]*ScanJob),
 	}
 }
 
+// Options returns the scan options the manager was created with
+func (sm *ScanManager) Options() ScanOptions {
+	return sm.options
+}
+
 // getGalleryDirs reads current gallery folder paths from the database
 func (sm *ScanManager) getGalleryDirs() []string {
 	var folders []domain.GalleryFolder
@@ -59,170 +96,100 @@ func (sm *ScanManager) getGalleryDirs() []string {
 	return dirs
 }
 
-// StartScan launches an asynchronous scan of all gallery directories
-func (sm *ScanManager) StartScan() error {
-	sm.mu.Lock()
-	if sm.isScanning {
-		sm.mu.Unlock()
-		return fmt.Errorf("scan already in progress")
-	}
-	sm.isScanning = true
-	sm.progress = "Starting scan..."
-	sm.filesProcessed = 0
-	sm.mu.Unlock()
-
-	go func() {
-		progressChan := make(chan string, 200)
-
-		go func() {
-			count := 0
-			for msg := range progressChan {
-				count++
-				sm.mu.Lock()
-				sm.progress = msg
-				sm.filesProcessed = count
-				sm.mu.Unlock()
-			}
-		}()
-
-		// Cleanup missing files first
-		sm.mu.Lock()
-		sm.progress = "Cleaning up missing files..."
-		sm.mu.Unlock()
-		cleanupMissingFiles(sm.db, progressChan)
+// StartScan launches an asynchronous scan of all gallery directories and returns its job ID
+func (sm *ScanManager) StartScan() (string, error) {
+	return sm.StartScanWithTrigger(domain.ScanTriggerManual)
+}
 
-		// Read gallery dirs from DB at scan time
-		scanDirs := sm.getGalleryDirs()
+// StartScanWithTrigger launches an asynchronous scan of all gallery directories,
+// recorded in the scan_runs table with the given trigger. Returns the job ID.
+func (sm *ScanManager) StartScanWithTrigger(trigger string) (string, error) {
+	return sm.runScan(JobKindScan, trigger, "Starting scan...", func(ctx context.Context, progressChan chan<- string) (string, error) {
+		// Read gallery dirs from DB at scan time, skipping the unavailable ones
+		ctx, scanDirs := sm.checkGalleryRoots(ctx, progressChan)
+		sm.expectFullScan(ctx, scanDirs)
 
-		// Scan all directories
+		// Scan all directories; a failing directory doesn't stop the others
+		var scanErr error
 		for _, dir := range scanDirs {
-			sm.mu.Lock()
-			sm.progress = fmt.Sprintf("Scanning: %s", dir)
-			sm.mu.Unlock()
-			scanDirectory(sm.db, dir, progressChan, sm.scanWorkers)
+			if ctx.Err() != nil {
+				break
+			}
+			sm.setProgress(fmt.Sprintf("Scanning: %s", dir))
+			if err := scanRoot(ctx, sm.db, dir, progressChan, sm.options); err != nil && scanErr == nil && ctx.Err() == nil {
+				scanErr = fmt.Errorf("%s: %w", dir, err)
+			}
 		}
 
-		close(progressChan)
-
-		sm.mu.Lock()
-		sm.isScanning = false
-		sm.progress = "Scan complete"
-		sm.mu.Unlock()
-
-		if sm.OnScanComplete != nil {
-			sm.OnScanComplete()
-		}
-	}()
+		// Cleanup missing files after the scan, so records of moved files are moved
+		// to their new path rather than removed
+		sm.setProgress("Cleaning up missing files...")
+		cleanupMissingFiles(ctx, sm.db, "", progressChan, sm.options)
 
-	return nil
+		sm.completeDeferredHashing(ctx, progressChan)
+		return "Scan complete", scanErr
+	})
 }
 
-// ScanSingleDir launches an asynchronous scan of a single directory
-func (sm *ScanManager) ScanSingleDir(dirPath string) error {
-	sm.mu.Lock()
-	if sm.isScanning {
-		sm.mu.Unlock()
-		return fmt.Errorf("scan already in progress")
-	}
-	sm.isScanning = true
-	sm.progress = fmt.Sprintf("Scanning: %s", dirPath)
-	sm.filesProcessed = 0
-	sm.mu.Unlock()
-
-	go func() {
-		progressChan := make(chan string, 200)
-
-		go func() {
-			count := 0
-			for msg := range progressChan {
-				count++
-				sm.mu.Lock()
-				sm.progress = msg
-				sm.filesProcessed = count
-				sm.mu.Unlock()
-			}
-		}()
-
-		scanDirectory(sm.db, dirPath, progressChan, sm.scanWorkers)
-
-		close(progressChan)
-
-		sm.mu.Lock()
-		sm.isScanning = false
-		sm.progress = "Scan complete"
-		sm.mu.Unlock()
-
-		if sm.OnScanComplete != nil {
-			sm.OnScanComplete()
+// ScanSingleDir launches an asynchronous scan of a single directory, removing records
+// of files that were deleted from it. Returns the job ID.
+func (sm *ScanManager) ScanSingleDir(dirPath string) (string, error) {
+	return sm.runScan(JobKindScanDir, domain.ScanTriggerManual, fmt.Sprintf("Scanning: %s", dirPath), func(ctx context.Context, progressChan chan<- string) (string, error) {
+		if err := sm.checkSingleRoot(dirPath, progressChan); err != nil {
+			return "Scan skipped", err
 		}
-	}()
-
-	return nil
+		progressOf(ctx).expect(1, 0)
+		err := scanRoot(ctx, sm.db, dirPath, progressChan, sm.options)
+		if ctx.Err() != nil {
+			err = nil
+		}
+		cleanupMissingFiles(ctx, sm.db, dirPath, progressChan, sm.options)
+		sm.completeDeferredHashing(ctx, progressChan)
+		return "Scan complete", err
+	})
 }
 
 // FastScanGallery launches an asynchronous fast scan of all gallery directories
 // Only hashes files when record doesn't exist or size differs
 // Returns result with scan statistics
 func (sm *ScanManager) FastScanGallery() FastScanResult {
-	sm.mu.Lock()
-	if sm.isScanning {
-		sm.mu.Unlock()
-		return FastScanResult{}
-	}
-	sm.isScanning = true
-	sm.progress = "Starting fast scan..."
-	sm.filesProcessed = 0
-	sm.mu.Unlock()
-
 	totalStats := FastScanResult{}
 
-	go func() {
-		progressChan := make(chan string, 200)
-
-		go func() {
-			count := 0
-			for msg := range progressChan {
-				count++
-				sm.mu.Lock()
-				sm.progress = msg
-				sm.filesProcessed = count
-				sm.mu.Unlock()
-			}
-		}()
-
-		// Cleanup missing files first
-		sm.mu.Lock()
-		sm.progress = "Cleaning up missing files..."
-		sm.mu.Unlock()
-		cleanupMissingFiles(sm.db, progressChan)
-
-		// Read gallery dirs from DB at scan time
-		scanDirs := sm.getGalleryDirs()
+	sm.runScan(JobKindFastScan, domain.ScanTriggerManual, "Starting fast scan...", func(ctx context.Context, progressChan chan<- string) (string, error) {
+		// Read gallery dirs from DB at scan time, skipping the unavailable ones
+		ctx, scanDirs := sm.checkGalleryRoots(ctx, progressChan)
+		sm.expectFullScan(ctx, scanDirs)
 
 		// Fast scan all directories
 		for _, dir := range scanDirs {
-			sm.mu.Lock()
-			sm.progress = fmt.Sprintf("Fast scanning: %s", dir)
-			sm.mu.Unlock()
-			stats := fastScanGalleryDirectory(sm.db, dir, progressChan, sm.scanWorkers)
+			if ctx.Err() != nil {
+				break
+			}
+			sm.setProgress(fmt.Sprintf("Fast scanning: %s", dir))
+			if objectstore.IsRemote(dir) {
+				// Listing an object store is as cheap as a fast scan
+				if err := scanObjectStore(ctx, sm.db, dir, progressChan, sm.options); err != nil {
+					progressChan <- "Error scanning " + dir + ": " + err.Error()
+				}
+				continue
+			}
+			stats := fastScanGalleryDirectory(ctx, sm.db, dir, progressChan, sm.options)
 			totalStats.Unchanged += stats.Unchanged
 			totalStats.Modified += stats.Modified
 			totalStats.Created += stats.Created
 			totalStats.Deleted += stats.Deleted
+			totalStats.Moved += stats.Moved
 			totalStats.TotalChecked += stats.TotalChecked
 		}
 
-		close(progressChan)
+		// Cleanup missing files after the scan, so records of moved files are moved
+		// to their new path rather than removed
+		sm.setProgress("Cleaning up missing files...")
+		cleanupMissingFiles(ctx, sm.db, "", progressChan, sm.options)
 
-		sm.mu.Lock()
-		sm.isScanning = false
-		sm.progress = "Fast scan complete"
-		sm.mu.Unlock()
-
-		if sm.OnScanComplete != nil {
-			sm.OnScanComplete()
-		}
-	}()
+		sm.completeDeferredHashing(ctx, progressChan)
+		return "Fast scan complete", nil
+	})
 
 	return totalStats
 }
@@ -231,59 +198,373 @@ func (sm *ScanManager) FastScanGallery() FastScanResult {
 // Only hashes files when record doesn't exist or size differs
 // Returns result with scan statistics
 func (sm *ScanManager) FastScanSingleDir(dirPath string) FastScanResult {
+	stats := FastScanResult{}
+
+	sm.runScan(JobKindFastScanDir, domain.ScanTriggerManual, fmt.Sprintf("Fast scanning: %s", dirPath), func(ctx context.Context, progressChan chan<- string) (string, error) {
+		if err := sm.checkSingleRoot(dirPath, progressChan); err != nil {
+			return "Fast scan skipped", err
+		}
+		progressOf(ctx).expect(1, 0)
+		stats = fastScanGalleryDirectory(ctx, sm.db, dirPath, progressChan, sm.options)
+		sm.completeDeferredHashing(ctx, progressChan)
+		return "Fast scan complete", nil
+	})
+
+	return stats
+}
+
+// StartRehash launches an asynchronous migration that re-hashes every file whose content
+// hash was computed with an algorithm other than the configured one. Returns the job ID.
+func (sm *ScanManager) StartRehash() (string, error) {
+	startMsg := fmt.Sprintf("Rehashing files with %s...", sm.options.contentHashAlgorithm())
+	return sm.runScan(JobKindRehash, domain.ScanTriggerManual, startMsg, func(ctx context.Context, progressChan chan<- string) (string, error) {
+		updated := rehashStaleFiles(ctx, sm.db, progressChan, sm.options)
+		return fmt.Sprintf("Rehash complete: %d files updated", updated), nil
+	})
+}
+
+// runScan is the common lifecycle of every scan kind: it registers a job, marks the manager
+// busy, runs body in the background with a cancellable, pausable context and a progress
+// channel, then publishes the outcome. Every run is recorded in the scan_runs table with
+// the given trigger, together with what the scan functions tallied through the context.
+// body returns the final progress message used when the scan was not cancelled, and an
+// error that marks the job as failed. Returns the job ID.
+func (sm *ScanManager) runScan(kind, trigger, startMsg string, body func(ctx context.Context, progressChan chan<- string) (string, error)) (string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	gate := newPauseGate()
+
 	sm.mu.Lock()
 	if sm.isScanning {
 		sm.mu.Unlock()
-		return FastScanResult{}
+		cancel()
+		return "", fmt.Errorf("scan already in progress")
 	}
+	job := &ScanJob{ID: newJobID(), Kind: kind, State: JobQueued, Progress: startMsg, CreatedAt: time.Now()}
+	sm.addJobLocked(job)
 	sm.isScanning = true
-	sm.progress = fmt.Sprintf("Fast scanning: %s", dirPath)
+	sm.progress = startMsg
 	sm.filesProcessed = 0
+	sm.job = job
+	sm.estimate = &scanProgress{}
+	sm.cancel = cancel
+	sm.gate = gate
 	sm.mu.Unlock()
-
-	stats := FastScanResult{}
+	sm.publishStatus(true)
 
 	go func() {
+		defer cancel()
+
+		sm.mu.Lock()
+		startedAt := time.Now()
+		job.State = JobRunning
+		job.StartedAt = &startedAt
+		sm.mu.Unlock()
+
+		run := domain.ScanRun{Kind: kind, Trigger: trigger, Status: domain.ScanRunRunning, StartedAt: startedAt}
+		if err := sm.db.Create(&run).Error; err != nil {
+			slog.Error("Failed to record scan run", "job", job.ID, "error", err)
+		}
+		tally := &scanTally{}
+		estimate := sm.estimate
+
+		// Snapshot existing duplicate groups only when someone will receive the diff
+		var duplicatesBefore map[string]duplicateKey
+		if sm.Events.HasSubscribers() {
+			var err error
+			if duplicatesBefore, err = duplicateGroupKeys(sm.db); err != nil {
+				slog.Error("Failed to snapshot duplicate groups", "error", err)
+			}
+		}
+
 		progressChan := make(chan string, 200)
+		consumerDone := make(chan struct{})
 
 		go func() {
+			defer close(consumerDone)
 			count := 0
 			for msg := range progressChan {
 				count++
 				sm.mu.Lock()
 				sm.progress = msg
 				sm.filesProcessed = count
+				job.Progress = msg
+				job.FilesProcessed = count
+				if strings.HasPrefix(msg, "Error") {
+					job.Errors++
+				}
 				sm.mu.Unlock()
+				sm.publishStatus(false)
 			}
 		}()
 
-		result := fastScanGalleryDirectory(sm.db, dirPath, progressChan, sm.scanWorkers)
-		stats = result
+		finalMsg, scanErr := body(withScanProgress(withScanTally(withPauseGate(ctx, gate), tally), estimate), progressChan)
 
 		close(progressChan)
+		<-consumerDone
+
+		cancelled := ctx.Err() != nil
+		if cancelled {
+			finalMsg = "Scan cancelled"
+		}
 
 		sm.mu.Lock()
 		sm.isScanning = false
-		sm.progress = "Fast scan complete"
+		sm.progress = finalMsg
+		sm.job = nil
+		sm.estimate = nil
+		sm.cancel = nil
+		sm.gate = nil
+		filesProcessed := sm.filesProcessed
+		jobErrors := job.Errors
+		finishedAt := time.Now()
+		job.Progress = finalMsg
+		job.FinishedAt = &finishedAt
+		switch {
+		case cancelled:
+			job.State = JobCancelled
+		case scanErr != nil:
+			job.State = JobFailed
+			job.Error = scanErr.Error()
+		default:
+			job.State = JobDone
+		}
 		sm.mu.Unlock()
 
+		if scanErr != nil {
+			slog.Error("Scan job failed", "job", job.ID, "error", scanErr)
+		}
+
+		if run.ID != 0 {
+			sm.finishRun(&run, tally.snapshot(), estimate, cancelled, scanErr, filesProcessed, jobErrors, finishedAt)
+		}
+
+		sm.Events.Publish(events.TypeScanFinished, sm.GetStatus())
+
+		var newGroups []NewDuplicateGroup
+		if duplicatesBefore != nil {
+			groups, err := newDuplicateGroups(sm.db, duplicatesBefore)
+			if err != nil {
+				slog.Error("Failed to collect new duplicate groups", "error", err)
+			} else if len(groups) > 0 {
+				newGroups = groups
+				sm.Events.Publish(events.TypeDuplicatesNew, groups)
+			}
+		}
+
+		if sm.Events.HasSubscribers() {
+			sm.Events.Publish(events.TypeScanSummary, sm.scanSummary(job.ID, run, newGroups))
+		}
+
 		if sm.OnScanComplete != nil {
 			sm.OnScanComplete()
 		}
 	}()
 
-	return stats
+	return job.ID, nil
+}
+
+// finishRun stores the outcome and the counts of a scan run
+func (sm *ScanManager) finishRun(run *domain.ScanRun, counts tallyCounts, estimate *scanProgress, cancelled bool, scanErr error, filesProcessed, errorCount int, finishedAt time.Time) {
+	update := map[string]interface{}{
+		"status":          domain.ScanRunCompleted,
+		"directories":     strings.Join(counts.Directories, "\n"),
+		"files_processed": filesProcessed,
+		"files_added":     counts.Added,
+		"files_updated":   counts.Updated,
+		"files_removed":   counts.Removed,
+		"errors":          errorCount,
+		"finished_at":     finishedAt,
+	}
+	if total, ok := estimate.walkedFiles(); ok {
+		update["files_total"] = total
+	}
+	switch {
+	case cancelled:
+		update["status"] = domain.ScanRunCancelled
+	case scanErr != nil:
+		update["status"] = domain.ScanRunFailed
+		update["error"] = scanErr.Error()
+	}
+	if err := sm.db.Model(run).Updates(update).Error; err != nil {
+		slog.Error("Failed to record scan run outcome", "run", run.ID, "error", err)
+	}
+}
+
+// addJobLocked registers a job, dropping the oldest finished jobs beyond maxRetainedJobs.
+// Must be called with sm.mu held.
+func (sm *ScanManager) addJobLocked(job *ScanJob) {
+	sm.jobs[job.ID] = job
+	sm.jobOrder = append(sm.jobOrder, job.ID)
+	for len(sm.jobOrder) > maxRetainedJobs {
+		delete(sm.jobs, sm.jobOrder[0])
+		sm.jobOrder = sm.jobOrder[1:]
+	}
+}
+
+// GetJob returns a snapshot of a scan job by ID
+func (sm *ScanManager) GetJob(id string) (ScanJob, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	job, ok := sm.jobs[id]
+	if !ok {
+		return ScanJob{}, false
+	}
+	return *job, true
+}
+
+// setProgress updates the progress message of the running scan
+func (sm *ScanManager) setProgress(msg string) {
+	sm.mu.Lock()
+	sm.progress = withEstimate(msg, sm.estimate)
+	sm.mu.Unlock()
+	sm.publishStatus(true)
+}
+
+// publishStatus sends a scan.status event. Unless force is set, events are throttled
+// to one per statusEventInterval so per-file progress doesn't flood subscribers.
+func (sm *ScanManager) publishStatus(force bool) {
+	if !sm.Events.HasSubscribers() {
+		return
+	}
+	sm.mu.Lock()
+	now := time.Now()
+	if !force && now.Sub(sm.lastStatusSent) < statusEventInterval {
+		sm.mu.Unlock()
+		return
+	}
+	sm.lastStatusSent = now
+	sm.mu.Unlock()
+
+	sm.Events.Publish(events.TypeScanStatus, sm.GetStatus())
+}
+
+// Errors returned by the scan control methods
+var (
+	ErrNoScanInProgress  = errors.New("no scan in progress")
+	ErrScanAlreadyPaused = errors.New("scan is already paused")
+	ErrScanNotPaused     = errors.New("scan is not paused")
+)
+
+// Cancel stops the running scan. Files already processed stay in the database.
+func (sm *ScanManager) Cancel() error {
+	sm.mu.Lock()
+	cancel := sm.cancel
+	sm.mu.Unlock()
+	if cancel == nil {
+		return ErrNoScanInProgress
+	}
+	cancel()
+	sm.setProgress("Cancelling scan...")
+	return nil
+}
+
+// Pause suspends the running scan after the files currently being hashed
+func (sm *ScanManager) Pause() error {
+	sm.mu.Lock()
+	gate := sm.gate
+	sm.mu.Unlock()
+	if gate == nil {
+		return ErrNoScanInProgress
+	}
+	if !gate.pause() {
+		return ErrScanAlreadyPaused
+	}
+	sm.publishStatus(true)
+	return nil
+}
+
+// Resume continues a paused scan
+func (sm *ScanManager) Resume() error {
+	sm.mu.Lock()
+	gate := sm.gate
+	sm.mu.Unlock()
+	if gate == nil {
+		return ErrNoScanInProgress
+	}
+	if !gate.unpause() {
+		return ErrScanNotPaused
+	}
+	sm.publishStatus(true)
+	return nil
+}
+
+// completeDeferredHashing runs the hashing passes postponed during a scan: content hashes
+// for size collisions (size prefilter), then full hashes for prefix hash collisions
+// (two-stage hashing). Both steps are no-ops when the corresponding mode is disabled.
+func (sm *ScanManager) completeDeferredHashing(ctx context.Context, progressChan chan<- string) {
+	if sm.options.SizePrefilter && ctx.Err() == nil {
+		sm.setProgress("Hashing files with matching sizes...")
+		hashSizeCollisions(ctx, sm.db, progressChan, sm.options)
+	}
+	if sm.options.TwoStageHash && ctx.Err() == nil {
+		sm.setProgress("Resolving prefix hash collisions...")
+		resolvePrefixCollisions(ctx, sm.db, progressChan, sm.options)
+	}
 }
 
 // GetStatus returns the current scan status
 func (sm *ScanManager) GetStatus() ScanStatusResponse {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
-	return ScanStatusResponse{
+	status := ScanStatusResponse{
 		Scanning:       sm.isScanning,
+		Paused:         sm.gate != nil && sm.gate.isPaused(),
+		JobID:          sm.jobID(),
 		Progress:       sm.progress,
 		FilesProcessed: sm.filesProcessed,
+		// Replaced rather than modified, so sharing the slice is safe
+		UnavailableRoots: sm.unavailable,
+	}
+	if e, ok := sm.estimate.estimate(time.Now()); ok {
+		status.FilesDone = e.Done
+		status.FilesTotal = e.Total
+		status.Percent = math.Round(e.Percent*10) / 10
+		status.ETASeconds = int64(e.ETA.Seconds())
+	}
+	return status
+}
+
+// expectFullScan starts the progress estimate of a scan of the gallery folders dirs
+func (sm *ScanManager) expectFullScan(ctx context.Context, dirs []string) {
+	sm.setProgress("Counting files...")
+	progressOf(ctx).expect(len(dirs), expectedFiles(ctx, sm.db, dirs, sm.options))
+}
+
+// checkGalleryRoots reads the gallery folders and returns the ones that can be
+// scanned, with a context keeping the cleanup away from the others
+func (sm *ScanManager) checkGalleryRoots(ctx context.Context, progressChan chan<- string) (context.Context, []string) {
+	available, unavailable := splitRoots(sm.db, sm.getGalleryDirs(), progressChan, sm.options)
+	sm.mu.Lock()
+	sm.unavailable = unavailable
+	sm.mu.Unlock()
+	return withUnavailableRoots(ctx, unavailable), available
+}
+
+// checkSingleRoot checks a gallery folder before a scan of it alone, updating the
+// unavailable roots reported in the status
+func (sm *ScanManager) checkSingleRoot(dir string, progressChan chan<- string) error {
+	_, unavailable := splitRoots(sm.db, []string{dir}, progressChan, sm.options)
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	roots := make([]string, 0, len(sm.unavailable)+1)
+	for _, root := range sm.unavailable {
+		if root != dir {
+			roots = append(roots, root)
+		}
+	}
+	sm.unavailable = append(roots, unavailable...)
+	if len(unavailable) > 0 {
+		return fmt.Errorf("%w: %s", ErrRootUnavailable, dir)
+	}
+	return nil
+}
+
+// jobID returns the ID of the running job, empty when idle. Must be called with sm.mu held.
+func (sm *ScanManager) jobID() string {
+	if sm.job == nil {
+		return ""
 	}
+	return sm.job.ID
 }
 
 // IsScanning returns whether a scan is currently running
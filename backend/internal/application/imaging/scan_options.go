@@ -0,0 +1,209 @@
+package imaging
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"image-toolkit/internal/application/thumbnail"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+)
+
+// ScanOptions controls how gallery directories are scanned and hashed
+type ScanOptions struct {
+	Workers        int                     // Number of parallel goroutines used for file hashing
+	PerceptualHash bool                    // Compute perceptual hashes for near-duplicate detection
+	PixelHash      bool                    // Hash decoded pixels to group the same picture stored in different files
+	HashAlgorithm  PerceptualHashAlgorithm // Perceptual hash algorithm, DefaultPerceptualHashAlgorithm if empty
+	Rotations      bool                    // Also hash the rotated and mirrored image so such copies match as similar
+	ContentHash    ContentHashAlgorithm    // Content hash algorithm, DefaultContentHashAlgorithm if empty
+	TwoStageHash   bool                    // Hash only the first 64KB, full hash only on size+prefix collision
+	SizePrefilter  bool                    // Record new files without hashing, hash only sizes seen more than once
+	Videos         bool                    // Also scan video files (exact duplicates by size and content hash)
+	MinSize        int64                   // Skip files smaller than this many bytes, 0 = no limit
+	MaxSize        int64                   // Skip files larger than this many bytes, 0 = no limit
+	MaxDepth       int                     // Levels of subdirectories scanned below each root, 0 = unlimited
+	FollowSymlinks bool                    // Follow symlinked files and directories (with loop protection)
+	IncludeHidden  bool                    // Also scan dot files, dot directories and NAS/OS junk directories
+	Thumbnails     *thumbnail.Service      // Cache thumbnails of scanned images made from their hashing decode, nil = only on demand
+	// Glob patterns of files and directories to skip, matched against the base name,
+	// or against the full slash-separated path when the pattern contains a slash;
+	// a "**" segment matches any number of directories
+	ExcludePatterns []string
+	// Treat hardlinks of one file (same device and inode) as a single file in exact
+	// duplicate lookups
+	CollapseHardlinks bool
+	// Compare paths regardless of case, as on Windows and macOS filesystems, so one file
+	// reached under differently cased paths gets a single record
+	CaseInsensitivePaths bool
+	// Endpoints and credentials of gallery roots in object stores (s3://bucket/prefix)
+	ObjectStores objectstore.Config
+	// Limits the rate file content is read at while hashing, shared by all workers;
+	// nil reads at full speed
+	Throttle *Throttle
+	// Bounds the memory of the images decoded at once, shared by all workers; nil
+	// decodes on every worker regardless of image size
+	MemoryLimit *MemoryLimit
+}
+
+// workerCount returns the configured number of hashing workers (at least 1)
+func (o ScanOptions) workerCount() int {
+	if o.Workers <= 0 {
+		return 1
+	}
+	return o.Workers
+}
+
+// hashAlgorithm returns the configured perceptual hash algorithm
+func (o ScanOptions) hashAlgorithm() PerceptualHashAlgorithm {
+	if o.HashAlgorithm == "" {
+		return DefaultPerceptualHashAlgorithm
+	}
+	return o.HashAlgorithm
+}
+
+// needsPerceptualHash reports whether a stored file lacks a perceptual hash
+// computed with the configured algorithm, or the hashes of its rotations when
+// those are enabled. Videos never get one.
+func (o ScanOptions) needsPerceptualHash(f *domain.ImageFile) bool {
+	return o.PerceptualHash && !f.IsVideo &&
+		(f.PHash == "" || f.PHashAlgo != string(o.hashAlgorithm()) || (o.Rotations && f.PHashXform == ""))
+}
+
+// needsPixelHash reports whether a stored image lacks a pixel hash while pixel
+// hashing is enabled
+func (o ScanOptions) needsPixelHash(f *domain.ImageFile) bool {
+	return o.PixelHash && !f.IsVideo && f.PixelHash == ""
+}
+
+// needsThumbnail reports whether a thumbnail of a scanned image should be cached:
+// a thumbnail cache preloading on scan is attached and has no fresh thumbnail of it
+func (o ScanOptions) needsThumbnail(fi fileInfo) bool {
+	return o.Thumbnails != nil && !domain.IsVideoFile(fi.path) && o.Thumbnails.NeedsThumbnail(fi.normalizedPath)
+}
+
+// includes reports whether a file is scanned: every supported image, and videos
+// when video duplicate detection is enabled
+func (o ScanOptions) includes(path string) bool {
+	return domain.IsImageFile(path) || o.Videos && domain.IsVideoFile(path)
+}
+
+// beyondMaxDepth reports whether a path lies deeper below root than MaxDepth allows.
+// Files directly in root are at depth 1; a directory is beyond the limit when its
+// contents would be, so walks can skip it as a whole.
+func (o ScanOptions) beyondMaxDepth(root, p string, isDir bool) bool {
+	if o.MaxDepth <= 0 {
+		return false
+	}
+	rel, err := filepath.Rel(root, p)
+	if err != nil || rel == "." {
+		return false
+	}
+	depth := strings.Count(filepath.ToSlash(rel), "/") + 1
+	if isDir {
+		depth++
+	}
+	return depth > o.MaxDepth
+}
+
+// inSizeRange reports whether a file of the given size passes the size limits
+func (o ScanOptions) inSizeRange(size int64) bool {
+	return size >= o.MinSize && (o.MaxSize == 0 || size <= o.MaxSize)
+}
+
+// DuplicateFilter returns the duplicate lookup filter for the given media type,
+// so that stored files outside the size limits and the groups marked as not
+// duplicates are ignored too
+func (o ScanOptions) DuplicateFilter(media MediaFilter) DuplicateFilter {
+	return DuplicateFilter{Media: media, MinSize: o.MinSize, MaxSize: o.MaxSize, CollapseHardlinks: o.CollapseHardlinks, HideIgnored: true}
+}
+
+// contentHashAlgorithm returns the configured content hash algorithm
+func (o ScanOptions) contentHashAlgorithm() ContentHashAlgorithm {
+	if o.ContentHash == "" {
+		return DefaultContentHashAlgorithm
+	}
+	return o.ContentHash
+}
+
+// needsContentRehash reports whether a stored file's content hash was computed
+// with an algorithm other than the configured one, lacks the prefix hash required
+// by two-stage hashing, or was deferred by a size prefilter that is now disabled
+func (o ScanOptions) needsContentRehash(f *domain.ImageFile) bool {
+	if f.HashAlgo != string(o.contentHashAlgorithm()) {
+		return true
+	}
+	if isDeferred(f) {
+		return !o.SizePrefilter
+	}
+	return o.TwoStageHash && f.PrefixHash == ""
+}
+
+// junkDirs are directories created by NAS indexers, operating systems and photo
+// tools; they hold thumbnails, previews and deleted files rather than photos
+var junkDirs = map[string]bool{
+	"@eadir":                    true, // Synology thumbnails
+	"@recycle":                  true, // QNAP recycle bin
+	"#recycle":                  true, // Synology recycle bin
+	"#snapshot":                 true, // Synology snapshots
+	"$recycle.bin":              true,
+	"system volume information": true,
+	"__macosx":                  true, // resource forks extracted from zip archives
+}
+
+// isHiddenOrJunk reports whether a file or directory name is hidden (dot-prefixed,
+// e.g. .git, .thumbnails or ._IMG_0001.JPG resource forks) or a known junk directory
+func isHiddenOrJunk(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".." || junkDirs[strings.ToLower(name)]
+}
+
+// isExcluded reports whether a file or directory matches one of the exclude patterns,
+// or is hidden or junk unless IncludeHidden is set
+func (o ScanOptions) isExcluded(p string) bool {
+	base := filepath.Base(p)
+	if !o.IncludeHidden && isHiddenOrJunk(base) {
+		return true
+	}
+	if len(o.ExcludePatterns) == 0 {
+		return false
+	}
+	slashPath := filepath.ToSlash(p)
+	for _, pattern := range o.ExcludePatterns {
+		if strings.Contains(pattern, "/") {
+			if matchGlob(pattern, slashPath) {
+				return true
+			}
+		} else if matched, _ := filepath.Match(pattern, base); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash-separated path against a pattern whose segments are
+// path.Match patterns, or "**" for zero or more whole segments
+func matchGlob(pattern, p string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
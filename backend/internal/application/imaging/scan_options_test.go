@@ -0,0 +1,105 @@
+package imaging
+
+import "testing"
+
+func TestScanOptionsIsExcluded(t *testing.T) {
+	opts := ScanOptions{ExcludePatterns: []string{"@eaDir", "*.tmp", "/photos/private/*"}}
+	cases := map[string]bool{
+		"/photos/@eaDir":            true,
+		"/photos/2024/img.tmp":      true,
+		"/photos/private/a.jpg":     true,
+		"/photos/private/sub/a.jpg": false, // * does not cross directories
+		"/photos/2024/img.jpg":      false,
+	}
+	for path, want := range cases {
+		if got := opts.isExcluded(path); got != want {
+			t.Errorf("isExcluded(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if (ScanOptions{}).isExcluded("/photos/a.tmp") {
+		t.Error("no patterns should exclude nothing")
+	}
+}
+
+func TestScanOptionsIsExcludedDoubleStar(t *testing.T) {
+	opts := ScanOptions{ExcludePatterns: []string{"**/node_modules/**", "*.thumb.jpg", "/photos/**/backup"}}
+	cases := map[string]bool{
+		"/photos/app/node_modules":         true,
+		"/photos/app/node_modules/x/a.jpg": true,
+		"/photos/2024/a.thumb.jpg":         true,
+		"/photos/backup":                   true,
+		"/photos/2024/06/backup":           true,
+		"/photos/2024/backup.jpg":          false,
+		"/photos/2024/a.jpg":               false,
+	}
+	for path, want := range cases {
+		if got := opts.isExcluded(path); got != want {
+			t.Errorf("isExcluded(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestScanOptionsIncludes(t *testing.T) {
+	if (ScanOptions{}).includes("/videos/clip.mp4") {
+		t.Error("videos should be skipped unless enabled")
+	}
+	opts := ScanOptions{Videos: true}
+	for path, want := range map[string]bool{
+		"/photos/a.JPG":    true,
+		"/videos/clip.MOV": true,
+		"/videos/clip.mkv": true,
+		"/docs/notes.txt":  false,
+	} {
+		if got := opts.includes(path); got != want {
+			t.Errorf("includes(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestScanOptionsBeyondMaxDepth(t *testing.T) {
+	opts := ScanOptions{MaxDepth: 2}
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"/photos", true, false},
+		{"/photos/a.jpg", false, false},
+		{"/photos/2024", true, false},
+		{"/photos/2024/a.jpg", false, false},
+		{"/photos/2024/06", true, true},
+		{"/photos/2024/06/a.jpg", false, true},
+	}
+	for _, c := range cases {
+		if got := opts.beyondMaxDepth("/photos", c.path, c.isDir); got != c.want {
+			t.Errorf("beyondMaxDepth(%q, dir=%v) = %v, want %v", c.path, c.isDir, got, c.want)
+		}
+	}
+	if (ScanOptions{}).beyondMaxDepth("/photos", "/photos/a/b/c/d.jpg", false) {
+		t.Error("zero MaxDepth should be unlimited")
+	}
+}
+
+func TestScanOptionsSkipsHiddenAndJunk(t *testing.T) {
+	var opts ScanOptions
+	for path, want := range map[string]bool{
+		"/photos/.git":                true,
+		"/photos/.thumbnails":         true,
+		"/photos/2024/._IMG_0001.JPG": true,
+		"/photos/@eaDir":              true,
+		"/photos/#recycle":            true,
+		"/photos/@Recycle":            true,
+		"/photos/$RECYCLE.BIN":        true,
+		"/photos/__MACOSX":            true,
+		"/photos/2024":                false,
+		"/photos/2024/IMG_0001.JPG":   false,
+	} {
+		if got := opts.isExcluded(path); got != want {
+			t.Errorf("isExcluded(%q) = %v, want %v", path, got, want)
+		}
+	}
+	opts.IncludeHidden = true
+	if opts.isExcluded("/photos/.git") || opts.isExcluded("/photos/@eaDir") {
+		t.Error("IncludeHidden should scan hidden and junk directories")
+	}
+}
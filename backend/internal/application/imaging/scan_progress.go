@@ -0,0 +1,204 @@
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+
+	"gorm.io/gorm"
+)
+
+// scanProgress estimates how far a scan is. It travels in the scan context like the
+// tally: the scan functions report the candidate files each walked root holds and
+// every file they have handled, and a nil progress ignores everything. Until all roots
+// are walked the total is the expected count from the previous run or a pre-pass.
+type scanProgress struct {
+	mu       sync.Mutex
+	roots    int // roots to walk
+	walked   int // roots walked so far
+	expected int // candidate files expected in all roots
+	found    int // candidate files in the walked roots
+	done     int // candidate files handled so far
+	started  time.Time
+}
+
+// ProgressEstimate is a snapshot of a scan progress
+type ProgressEstimate struct {
+	Done    int
+	Total   int
+	Percent float64
+	ETA     time.Duration // 0 while unknown
+}
+
+type scanProgressKey struct{}
+
+// withScanProgress attaches a progress estimate to a scan context
+func withScanProgress(ctx context.Context, p *scanProgress) context.Context {
+	return context.WithValue(ctx, scanProgressKey{}, p)
+}
+
+// progressOf returns the progress estimate of a scan context, nil if there is none
+func progressOf(ctx context.Context) *scanProgress {
+	p, _ := ctx.Value(scanProgressKey{}).(*scanProgress)
+	return p
+}
+
+// expect starts estimating a scan of roots expected to hold files candidate files
+func (p *scanProgress) expect(roots, files int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roots, p.expected, p.started = roots, files, time.Now()
+}
+
+// rootWalked records the number of candidate files found in a walked root
+func (p *scanProgress) rootWalked(files int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.walked++
+	p.found += files
+}
+
+// handled records a candidate file that was skipped, moved, hashed or failed, and
+// returns its progress message with the estimate appended
+func (p *scanProgress) handled(msg string) string {
+	if p == nil {
+		return msg
+	}
+	p.mu.Lock()
+	p.done++
+	p.mu.Unlock()
+	return withEstimate(msg, p)
+}
+
+// walkedFiles returns the candidate files found once every root has been walked
+func (p *scanProgress) walkedFiles() (int, bool) {
+	if p == nil {
+		return 0, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.found, p.roots > 0 && p.walked >= p.roots
+}
+
+// estimate returns the current estimate; ok is false while the total is unknown
+func (p *scanProgress) estimate(now time.Time) (e ProgressEstimate, ok bool) {
+	if p == nil {
+		return e, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.roots == 0 {
+		return e, false
+	}
+	e.Done, e.Total = p.done, p.found
+	if p.walked < p.roots {
+		e.Total = max(p.expected, p.found)
+	}
+	e.Total = max(e.Total, e.Done)
+	if e.Total == 0 {
+		return e, false
+	}
+	e.Percent = float64(e.Done) * 100 / float64(e.Total)
+	if elapsed := now.Sub(p.started); e.Done > 0 && e.Done < e.Total {
+		e.ETA = time.Duration(float64(elapsed) / float64(e.Done) * float64(e.Total-e.Done))
+	}
+	return e, true
+}
+
+// String formats the estimate as 1234/56789 (2.2%), ETA 14m
+func (e ProgressEstimate) String() string {
+	s := fmt.Sprintf("%d/%d (%.1f%%)", e.Done, e.Total, e.Percent)
+	if e.ETA >= time.Second {
+		s += ", ETA " + formatETA(e.ETA)
+	}
+	return s
+}
+
+// formatETA rounds a remaining time to seconds under a minute, minutes under an hour,
+// and hours and minutes beyond
+func formatETA(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()+0.5))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()+0.5))
+	default:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// withEstimate appends the progress estimate to a progress message
+func withEstimate(msg string, p *scanProgress) string {
+	if e, ok := p.estimate(time.Now()); ok {
+		return msg + " [" + e.String() + "]"
+	}
+	return msg
+}
+
+// expectedFiles returns how many candidate files a full scan of roots is expected to
+// handle: the count the previous full scan walked, or else the count of a pre-pass
+// over the local roots
+func expectedFiles(ctx context.Context, db *gorm.DB, roots []string, opts ScanOptions) int {
+	var previous []domain.ScanRun
+	db.Where("kind IN ? AND status = ? AND files_total > 0", []string{JobKindScan, JobKindFastScan}, domain.ScanRunCompleted).
+		Order("started_at DESC").Limit(1).Find(&previous)
+	if len(previous) > 0 {
+		return previous[0].FilesTotal
+	}
+	total := 0
+	for _, root := range roots {
+		if objectstore.IsRemote(root) || ctx.Err() != nil {
+			continue
+		}
+		total += countCandidates(ctx, root, opts)
+	}
+	return total
+}
+
+// countCandidates counts the files a walk of root would scan
+func countCandidates(ctx context.Context, root string, opts ScanOptions) int {
+	absPath, err := filepath.Abs(root)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	ignore := newIgnoreMatcher()
+	walkTree(absPath, opts.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if cerr := checkpoint(ctx); cerr != nil {
+			return cerr
+		}
+		if err != nil {
+			return nil
+		}
+		if path != absPath && (opts.isExcluded(path) || ignore.ignored(absPath, path, info.IsDir())) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if opts.beyondMaxDepth(absPath, path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if opts.includes(path) && opts.inSizeRange(info.Size()) {
+			count++
+		}
+		return nil
+	})
+	return count
+}
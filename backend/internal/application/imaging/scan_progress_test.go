@@ -0,0 +1,99 @@
+package imaging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestScanProgressEstimate(t *testing.T) {
+	var none *scanProgress
+	if got := none.handled("Processed: a.jpg"); got != "Processed: a.jpg" {
+		t.Errorf("nil progress message = %q", got)
+	}
+	if _, ok := none.estimate(time.Now()); ok {
+		t.Fatal("a nil progress should have no estimate")
+	}
+
+	p := &scanProgress{}
+	p.expect(2, 100)
+	start := p.started
+	p.rootWalked(30)
+	for i := 0; i < 25; i++ {
+		p.handled("")
+	}
+	e, ok := p.estimate(start.Add(5 * time.Minute))
+	if !ok || e.Done != 25 || e.Total != 100 || e.ETA != 15*time.Minute {
+		t.Fatalf("before all roots are walked: got %+v, %v", e, ok)
+	}
+	if got := e.String(); got != "25/100 (25.0%), ETA 15m" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Once every root is walked the found count replaces the expected one
+	p.rootWalked(20)
+	e, _ = p.estimate(start.Add(5 * time.Minute))
+	if e.Total != 50 || e.Percent != 50 {
+		t.Fatalf("after all roots are walked: got %+v", e)
+	}
+	if total, ok := p.walkedFiles(); !ok || total != 50 {
+		t.Errorf("walkedFiles() = %d, %v", total, ok)
+	}
+}
+
+func TestFormatETA(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{42 * time.Second, "42s"},
+		{14*time.Minute + 20*time.Second, "14m"},
+		{2*time.Hour + 5*time.Minute + 40*time.Second, "2h06m"},
+	}
+	for _, tt := range tests {
+		if got := formatETA(tt.d); got != tt.want {
+			t.Errorf("formatETA(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestExpectedFiles(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.ScanRun{})
+	dir := t.TempDir()
+	for _, name := range []string{"a.jpg", "sub/b.png", "notes.txt", ".hidden/c.jpg"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Without a previous run the pre-pass counts what a scan walks
+	progress := &scanProgress{}
+	ctx := withScanProgress(context.Background(), progress)
+	progress.expect(1, expectedFiles(ctx, db, []string{dir}, ScanOptions{}))
+	if err := scanDirectory(ctx, db, dir, make(chan string, 100), ScanOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	found, _ := progress.walkedFiles()
+	if progress.expected != found || found == 0 {
+		t.Fatalf("pre-pass counted %d files, the scan walked %d", progress.expected, found)
+	}
+	if e, _ := progress.estimate(time.Now()); e.Done != found || e.Percent != 100 {
+		t.Errorf("after the scan: got %+v", e)
+	}
+
+	run := domain.ScanRun{Kind: JobKindScan, Trigger: domain.ScanTriggerManual, Status: domain.ScanRunCompleted, FilesTotal: 1234, StartedAt: time.Now()}
+	if err := db.Create(&run).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := expectedFiles(context.Background(), db, []string{dir}, ScanOptions{}); got != 1234 {
+		t.Errorf("expected files after a recorded run = %d, want 1234", got)
+	}
+}
@@ -0,0 +1,47 @@
+package imaging
+
+import (
+	"log/slog"
+
+	"image-toolkit/internal/domain"
+)
+
+// ScanSummary is the outcome of a finished scan with the duplicates it left behind,
+// published as events.TypeScanSummary for notifications
+type ScanSummary struct {
+	JobID string         `json:"jobId"`
+	Run   domain.ScanRun `json:"run"` // as recorded in scan_runs
+	// Duplicate groups that appeared during the scan
+	NewGroups []NewDuplicateGroup `json:"newGroups"`
+	// Exact duplicates of the whole index after the scan
+	Duplicates DuplicateTotals `json:"duplicates"`
+}
+
+// NewGroupsReclaimableBytes returns the space freed by keeping one file of every
+// new duplicate group
+func (s ScanSummary) NewGroupsReclaimableBytes() int64 {
+	var total int64
+	for _, g := range s.NewGroups {
+		total += g.Size * int64(len(g.Paths)-1)
+	}
+	return total
+}
+
+// scanSummary builds the summary of a finished scan run
+func (sm *ScanManager) scanSummary(jobID string, run domain.ScanRun, newGroups []NewDuplicateGroup) ScanSummary {
+	if run.ID != 0 {
+		if err := sm.db.First(&run, run.ID).Error; err != nil {
+			slog.Error("Failed to load scan run", "run", run.ID, "error", err)
+		}
+	}
+	summary := ScanSummary{JobID: jobID, Run: run, NewGroups: newGroups}
+	if summary.NewGroups == nil {
+		summary.NewGroups = []NewDuplicateGroup{}
+	}
+	totals, err := CountDuplicates(sm.db, sm.Options().DuplicateFilter(MediaAll))
+	if err != nil {
+		slog.Error("Failed to count duplicates", "error", err)
+	}
+	summary.Duplicates = totals
+	return summary
+}
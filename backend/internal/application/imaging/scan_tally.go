@@ -0,0 +1,77 @@
+package imaging
+
+import (
+	"context"
+	"sync"
+)
+
+// scanTally counts what a scan run changed in the index. It travels in the scan
+// context like the pause gate; the scan functions record into it and a nil tally,
+// e.g. in background sync, ignores everything.
+type scanTally struct {
+	mu          sync.Mutex
+	directories []string
+	added       int
+	updated     int
+	removed     int
+}
+
+// tallyCounts is a snapshot of a scan tally
+type tallyCounts struct {
+	Directories []string
+	Added       int
+	Updated     int
+	Removed     int
+}
+
+type scanTallyKey struct{}
+
+// withScanTally attaches a tally to a scan context
+func withScanTally(ctx context.Context, t *scanTally) context.Context {
+	return context.WithValue(ctx, scanTallyKey{}, t)
+}
+
+// tallyOf returns the tally of a scan context, nil if there is none
+func tallyOf(ctx context.Context) *scanTally {
+	t, _ := ctx.Value(scanTallyKey{}).(*scanTally)
+	return t
+}
+
+// directory records a scanned directory once
+func (t *scanTally) directory(dir string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, d := range t.directories {
+		if d == dir {
+			return
+		}
+	}
+	t.directories = append(t.directories, dir)
+}
+
+// count adds to the numbers of created, updated and deleted index rows
+func (t *scanTally) count(added, updated, removed int) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.added += added
+	t.updated += updated
+	t.removed += removed
+}
+
+// snapshot returns the current counts
+func (t *scanTally) snapshot() tallyCounts {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return tallyCounts{
+		Directories: append([]string(nil), t.directories...),
+		Added:       t.added,
+		Updated:     t.updated,
+		Removed:     t.removed,
+	}
+}
@@ -0,0 +1,63 @@
+package imaging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestScanTallyNil(t *testing.T) {
+	var tally *scanTally
+	tally.directory("/photos")
+	tally.count(1, 1, 1)
+	if tallyOf(context.Background()) != nil {
+		t.Fatal("a context without a tally should have none")
+	}
+}
+
+func TestScanDirectoryTally(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.jpg", "first")
+	write("b.jpg", "second")
+
+	scan := func() tallyCounts {
+		tally := &scanTally{}
+		ctx := withScanTally(context.Background(), tally)
+		progress := make(chan string, 100)
+		if err := cleanupMissingFiles(ctx, db, dir, progress, ScanOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := scanDirectory(ctx, db, dir, progress, ScanOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		return tally.snapshot()
+	}
+
+	counts := scan()
+	if counts.Added != 2 || counts.Updated != 0 || counts.Removed != 0 {
+		t.Fatalf("first scan: got %+v", counts)
+	}
+	if len(counts.Directories) != 1 || counts.Directories[0] != filepath.ToSlash(dir) {
+		t.Fatalf("directories: got %v", counts.Directories)
+	}
+
+	write("a.jpg", "first, edited")
+	if err := os.Remove(filepath.Join(dir, "b.jpg")); err != nil {
+		t.Fatal(err)
+	}
+	write("c.jpg", "third")
+
+	counts = scan()
+	if counts.Added != 1 || counts.Updated != 1 || counts.Removed != 1 {
+		t.Fatalf("second scan: got %+v", counts)
+	}
+}
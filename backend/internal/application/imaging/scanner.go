@@ -1,121 +1,215 @@
 package imaging
 
 import (
-	"crypto/md5"
-	"encoding/hex"
+	"context"
 	"fmt"
-	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
 	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/imagecodec"
+	"image-toolkit/internal/infrastructure/objectstore"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// calculateFileHash calculates MD5 hash of a file
-func calculateFileHash(path string) (string, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	hash := md5.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", err
-	}
-
-	return hex.EncodeToString(hash.Sum(nil)), nil
-}
-
 // fileInfo holds file information collected during directory walk
 type fileInfo struct {
 	path           string
 	normalizedPath string
 	size           int64
 	modTime        time.Time
+	symlink        bool  // symlink, or below a followed directory symlink
+	device, inode  int64 // shared by hardlinks of one file, 0 if unknown
+	pHashOnly      bool  // content hash is current, only the perceptual or pixel hash or the thumbnail is missing or stale
+	deferHash      bool  // size prefilter: record the file now, content hash in the second pass
+	thumbnail      bool  // cache a thumbnail in ScanOptions.Thumbnails from the decoded image
 }
 
 // hashResult holds the result of a file hash computation
 type hashResult struct {
-	fi       fileInfo
-	hash     string
-	err      error
-	existing *domain.ImageFile
+	fi         fileInfo
+	hash       string
+	hashAlgo   string
+	prefixHash string
+	pHash      string
+	pHashAlgo  string
+	pHashXform string
+	pixelHash  string
+	corrupt    bool
+	err        error
+	existing   *domain.ImageFile
+}
+
+// hashFile computes the content hash and, if enabled, the perceptual hash of a file.
+// In two-stage mode only the prefix hash is computed here; the full hash is left empty
+// unless the whole file fits in the prefix, and is filled in by resolvePrefixCollisions.
+// Deferred files (size prefilter) get no content hash at all until hashSizeCollisions.
+// A perceptual hash failure (e.g. undecodable image) is not an error: the file still
+// takes part in exact duplicate detection. Thumbnails marked for caching are made from
+// the same decode. Empty files and images failing to decode are flagged as corrupt.
+func hashFile(fi fileInfo, existing *domain.ImageFile, opts ScanOptions) hashResult {
+	result := hashResult{fi: fi, existing: existing}
+
+	if fi.deferHash {
+		result.hashAlgo = string(opts.contentHashAlgorithm())
+	} else if fi.pHashOnly && existing != nil {
+		result.hash = existing.Hash
+		result.prefixHash = existing.PrefixHash
+		result.hashAlgo = existing.HashAlgo
+		result.pHash = existing.PHash
+		result.pHashAlgo = existing.PHashAlgo
+		result.pHashXform = existing.PHashXform
+		result.pixelHash = existing.PixelHash
+	} else if opts.TwoStageHash {
+		algo := opts.contentHashAlgorithm()
+		result.prefixHash, result.err = calculatePrefixHash(fi.path, algo, opts.Throttle)
+		if result.err != nil {
+			return result
+		}
+		if fi.size <= prefixHashSize {
+			result.hash = result.prefixHash
+		}
+		result.hashAlgo = string(algo)
+	} else {
+		algo := opts.contentHashAlgorithm()
+		result.hash, result.err = calculateFileHash(fi.path, algo, opts.Throttle)
+		if result.err != nil {
+			return result
+		}
+		result.hashAlgo = string(algo)
+	}
+
+	// Both hashes and the thumbnail of the decoded image share a single decode
+	if (opts.PerceptualHash || opts.PixelHash || fi.thumbnail) && !domain.IsVideoFile(fi.path) {
+		opts.Throttle.wait(fi.size) // the decoder reads about the whole file
+		if opts.MemoryLimit != nil {
+			release := opts.MemoryLimit.acquire(decodedSize(fi.path, fi.size))
+			defer release()
+		}
+		img, err := imagecodec.Decode(fi.path)
+		if err != nil {
+			result.corrupt = fi.size == 0 || isCorruptImage(err)
+		} else {
+			if opts.PerceptualHash {
+				algo := opts.hashAlgorithm()
+				result.pHash = formatPerceptualHash(hashImage(img, algo))
+				result.pHashAlgo = string(algo)
+				if opts.Rotations {
+					result.pHashXform = formatPerceptualHashes(transformHashes(img, algo))
+				}
+			}
+			if opts.PixelHash {
+				result.pixelHash = pixelHash(img)
+			}
+			if fi.thumbnail {
+				if err := opts.Thumbnails.StoreImage(fi.normalizedPath, img); err != nil {
+					slog.Warn("Failed to cache thumbnail while scanning", "path", fi.path, "error", err)
+				}
+			}
+		}
+	} else {
+		result.corrupt = checkCorrupt(fi)
+	}
+
+	return result
 }
 
 // scanDirectory scans a directory for image files and updates the database.
-// numWorkers controls the number of parallel goroutines used for file hashing.
-func scanDirectory(db *gorm.DB, dirPath string, progressChan chan<- string, numWorkers int) error {
+// opts.Workers controls the number of parallel goroutines used for file hashing.
+func scanDirectory(ctx context.Context, db *gorm.DB, dirPath string, progressChan chan<- string, opts ScanOptions) error {
 	absPath, err := filepath.Abs(dirPath)
 	if err != nil {
 		return fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	if numWorkers <= 0 {
-		numWorkers = 1
-	}
+	numWorkers := opts.workerCount()
+	tally := tallyOf(ctx)
+	tally.directory(filepath.ToSlash(absPath))
+	progress := progressOf(ctx)
 
 	// Phase 1: Collect all image files from the directory tree
 	var allFiles []fileInfo
-	err = filepath.Walk(absPath, func(path string, info os.FileInfo, err error) error {
+	ignore := newIgnoreMatcher()
+	err = walkTree(absPath, opts.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if cerr := checkpoint(ctx); cerr != nil {
+			return cerr
+		}
 		if err != nil {
 			progressChan <- "Error accessing " + path + ": " + err.Error()
 			return nil
 		}
+		if path != absPath && (opts.isExcluded(path) || ignore.ignored(absPath, path, info.IsDir())) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 		if info.IsDir() {
+			if opts.beyondMaxDepth(absPath, path, true) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
-		if !domain.IsImageFile(path) {
+		if !opts.includes(path) || !opts.inSizeRange(info.Size()) {
 			return nil
 		}
-		allFiles = append(allFiles, fileInfo{
+		fi := fileInfo{
 			path:           path,
 			normalizedPath: filepath.ToSlash(path),
 			size:           info.Size(),
 			modTime:        info.ModTime(),
-		})
+			symlink:        isSymlinked(info),
+		}
+		fi.device, fi.inode = inodeOf(path, info)
+		allFiles = append(allFiles, fi)
 		return nil
 	})
 	if err != nil {
 		return err
 	}
+	progress.rootWalked(len(allFiles))
 
 	if len(allFiles) == 0 {
 		return nil
 	}
 
 	// Phase 2: Batch query existing files from DB to build a cache map
-	existingMap := make(map[string]domain.ImageFile)
-	const dbBatchSize = 500
-	for i := 0; i < len(allFiles); i += dbBatchSize {
-		end := i + dbBatchSize
-		if end > len(allFiles) {
-			end = len(allFiles)
-		}
-		paths := make([]string, end-i)
-		for j, fi := range allFiles[i:end] {
-			paths[j] = fi.normalizedPath
-		}
-		var existingFiles []domain.ImageFile
-		db.Where("path IN ?", paths).Find(&existingFiles)
-		for _, ef := range existingFiles {
-			existingMap[ef.Path] = ef
-		}
-	}
+	existingMap := loadExisting(ctx, db, allFiles, progressChan, opts)
 
-	// Phase 3: Separate cached (unchanged) files from files that need hashing
+	// Phase 3: Separate cached (unchanged) files from files that need hashing, and move
+	// the records of files that were moved or renamed to their new path
+	moves := findMoveSources(db, newFiles(allFiles, existingMap, opts))
 	var filesToHash []fileInfo
 	for _, fi := range allFiles {
-		if existing, ok := existingMap[fi.normalizedPath]; ok {
-			if existing.ModTime.Equal(fi.modTime) && existing.Size == fi.size {
-				progressChan <- "Skipping (cached): " + fi.path
+		if existing, ok := existingMap[opts.pathKey(fi.normalizedPath)]; ok {
+			if existing.ModTime.Equal(fi.modTime) && existing.Size == fi.size && !opts.needsContentRehash(&existing) {
+				fi.thumbnail = opts.needsThumbnail(fi)
+				if opts.needsPerceptualHash(&existing) || opts.needsPixelHash(&existing) || fi.thumbnail {
+					fi.pHashOnly = true
+					filesToHash = append(filesToHash, fi)
+					continue
+				}
+				refreshUnchanged(db, &existing, fi)
+				progressChan <- progress.handled("Skipping (cached): " + fi.path)
 				continue
 			}
+		} else if source := moves.claim(fi, opts.Throttle); source != nil {
+			if err := moveFile(db, source, fi); err != nil {
+				progressChan <- progress.handled("Error moving " + source.Path + ": " + err.Error())
+				continue
+			}
+			progressChan <- progress.handled("Moved: " + source.Path + " -> " + fi.path)
+			tally.count(0, 1, 0)
+			continue
 		}
+		fi.deferHash = opts.SizePrefilter
+		fi.thumbnail = opts.needsThumbnail(fi)
 		filesToHash = append(filesToHash, fi)
 	}
 
@@ -124,69 +218,53 @@ func scanDirectory(db *gorm.DB, dirPath string, progressChan chan<- string, numW
 	}
 
 	// Phase 4: Hash files in parallel using a worker pool
-	jobs := make(chan fileInfo, numWorkers*2)
-	results := make(chan hashResult, numWorkers*2)
-
-	var wg sync.WaitGroup
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for fi := range jobs {
-				hash, err := calculateFileHash(fi.path)
-				var existing *domain.ImageFile
-				if ef, ok := existingMap[fi.normalizedPath]; ok {
-					existing = &ef
-				}
-				results <- hashResult{
-					fi:       fi,
-					hash:     hash,
-					err:      err,
-					existing: existing,
-				}
-			}
-		}()
-	}
-
-	// Close results channel when all workers finish
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Send jobs to workers
-	go func() {
-		for _, fi := range filesToHash {
-			jobs <- fi
+	results := hashInParallel(ctx, filesToHash, numWorkers, func(fi fileInfo) hashResult {
+		var existing *domain.ImageFile
+		if ef, ok := existingMap[opts.pathKey(fi.normalizedPath)]; ok {
+			existing = &ef
 		}
-		close(jobs)
-	}()
+		return hashFile(fi, existing, opts)
+	})
 
-	// Phase 5: Collect results and batch write to DB
+	// Phase 5: Collect results and write to DB from this goroutine only
 	const writeBatchSize = 50
 	var toCreate []domain.ImageFile
 	var toUpdate []domain.ImageFile
 
 	for result := range results {
 		if result.err != nil {
-			progressChan <- "Error hashing " + result.fi.path + ": " + result.err.Error()
+			progressChan <- progress.handled("Error hashing " + result.fi.path + ": " + result.err.Error())
 			continue
 		}
 
-		progressChan <- "Processed: " + result.fi.path
+		progressChan <- progress.handled("Processed: " + result.fi.path)
 
 		imageFile := domain.ImageFile{
-			Path:    result.fi.normalizedPath,
-			Size:    result.fi.size,
-			Hash:    result.hash,
-			ModTime: result.fi.modTime,
+			Path:       result.fi.normalizedPath,
+			Size:       result.fi.size,
+			Hash:       result.hash,
+			HashAlgo:   result.hashAlgo,
+			PrefixHash: result.prefixHash,
+			PHash:      result.pHash,
+			PHashAlgo:  result.pHashAlgo,
+			PHashXform: result.pHashXform,
+			PixelHash:  result.pixelHash,
+			Corrupt:    result.corrupt,
+			ModTime:    result.fi.modTime,
+			IsVideo:    domain.IsVideoFile(result.fi.path),
+			IsSymlink:  result.fi.symlink,
+			Device:     result.fi.device,
+			Inode:      result.fi.inode,
 		}
 
 		if result.existing != nil {
 			imageFile.ID = result.existing.ID
+			imageFile.Protected = result.existing.Protected
 			toUpdate = append(toUpdate, imageFile)
+			tally.count(0, 1, 0)
 		} else {
 			toCreate = append(toCreate, imageFile)
+			tally.count(1, 0, 0)
 		}
 
 		if len(toCreate)+len(toUpdate) >= writeBatchSize {
@@ -200,24 +278,124 @@ func scanDirectory(db *gorm.DB, dirPath string, progressChan chan<- string, numW
 	return nil
 }
 
-// flushDBBatch writes accumulated create/update records to the database and resets the slices
+// updateInode records the device and inode of an unchanged file when they differ from
+// its record, e.g. for records made before they were tracked or a file replaced by a
+// hardlink
+func updateInode(db *gorm.DB, existing *domain.ImageFile, device, inode int64) {
+	if existing.Device == device && existing.Inode == inode {
+		return
+	}
+	db.Model(&domain.ImageFile{}).Where("id = ?", existing.ID).
+		Updates(map[string]interface{}{"device": device, "inode": inode})
+}
+
+// refreshUnchanged brings the record of an unchanged file up to date: revived when it
+// was removed from the index, its path when the file was reached under a differently
+// cased path, its device and inode
+func refreshUnchanged(db *gorm.DB, existing *domain.ImageFile, fi fileInfo) {
+	reviveRecord(db, existing)
+	if existing.Path != fi.normalizedPath {
+		moveFile(db, existing, fi)
+		return
+	}
+	updateInode(db, existing, fi.device, fi.inode)
+}
+
+// loadExisting returns the records of walked files by path key, including removed
+// records that can be revived. Records of one file under differently cased paths are
+// reduced to one, see pickRecords.
+func loadExisting(ctx context.Context, db *gorm.DB, files []fileInfo, progressChan chan<- string, opts ScanOptions) map[string]domain.ImageFile {
+	walked := make(map[string]bool, len(files))
+	var rows []domain.ImageFile
+	const dbBatchSize = 500
+	for i := 0; i < len(files); i += dbBatchSize {
+		end := i + dbBatchSize
+		if end > len(files) {
+			end = len(files)
+		}
+		keys := make([]string, end-i)
+		for j, fi := range files[i:end] {
+			keys[j] = opts.pathKey(fi.normalizedPath)
+			walked[fi.normalizedPath] = true
+		}
+		var batch []domain.ImageFile
+		db.Unscoped().Where(opts.pathColumn()+" IN ?", keys).Find(&batch)
+		rows = append(rows, batch...)
+	}
+
+	existing, variants := opts.pickRecords(rows, func(path string) bool { return walked[path] })
+	for _, v := range variants {
+		if v.DeletedAt.Valid {
+			continue
+		}
+		progressChan <- "Removing differently cased duplicate entry from DB: " + v.Path
+		db.Delete(&v)
+		tallyOf(ctx).count(0, 0, 1)
+	}
+	return existing
+}
+
+// newFiles returns the walked files that have no record yet
+func newFiles(files []fileInfo, existing map[string]domain.ImageFile, opts ScanOptions) []fileInfo {
+	var unknown []fileInfo
+	for _, fi := range files {
+		if _, ok := existing[opts.pathKey(fi.normalizedPath)]; !ok {
+			unknown = append(unknown, fi)
+		}
+	}
+	return unknown
+}
+
+// scanColumns are the columns a scan writes to an existing record, reviving it if it
+// was removed; created_at and the protected flag are kept
+var scanColumns = []string{
+	"path", "size", "hash", "prefix_hash", "hash_algo", "p_hash", "p_hash_algo", "p_hash_xform",
+	"pixel_hash", "corrupt", "mod_time", "is_video", "is_symlink", "device", "inode", "updated_at", "deleted_at",
+}
+
+// flushDBBatch writes accumulated create/update records to the database in one
+// transaction and resets the slices. New records are upserted on their path, so a
+// file recorded by the watcher in the meantime is updated instead of failing the
+// batch; changed records are upserted on their ID, which also renames a record whose
+// path changed case.
 func flushDBBatch(db *gorm.DB, toCreate *[]domain.ImageFile, toUpdate *[]domain.ImageFile) {
-	if len(*toCreate) > 0 {
-		db.Create(toCreate)
-		*toCreate = (*toCreate)[:0]
+	if len(*toCreate) == 0 && len(*toUpdate) == 0 {
+		return
 	}
-	for _, f := range *toUpdate {
-		db.Save(&f)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if len(*toCreate) > 0 {
+			if err := tx.Clauses(upsertOn("path")).Create(toCreate).Error; err != nil {
+				return err
+			}
+		}
+		if len(*toUpdate) > 0 {
+			if err := tx.Clauses(upsertOn("id")).Create(toUpdate).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Failed to write scan results", "files", len(*toCreate)+len(*toUpdate), "error", err)
 	}
+	*toCreate = (*toCreate)[:0]
 	*toUpdate = (*toUpdate)[:0]
 }
 
+// upsertOn updates the scanned columns of the record that conflicts on column
+func upsertOn(column string) clause.OnConflict {
+	return clause.OnConflict{
+		Columns:   []clause.Column{{Name: column}},
+		DoUpdates: clause.AssignmentColumns(scanColumns),
+	}
+}
+
 // fastScanGalleryDirectory performs a fast gallery scan that only computes hash
 // when file record doesn't exist in DB or size differs.
 // It also cleans up records for files that no longer exist on disk.
 // Returns statistics about the scan operation.
-// numWorkers controls the number of parallel goroutines used for file hashing.
-func fastScanGalleryDirectory(db *gorm.DB, dirPath string, progressChan chan<- string, numWorkers int) FastScanResult {
+// opts.Workers controls the number of parallel goroutines used for file hashing.
+func fastScanGalleryDirectory(ctx context.Context, db *gorm.DB, dirPath string, progressChan chan<- string, opts ScanOptions) FastScanResult {
 	stats := FastScanResult{}
 
 	absPath, err := filepath.Abs(dirPath)
@@ -226,34 +404,52 @@ func fastScanGalleryDirectory(db *gorm.DB, dirPath string, progressChan chan<- s
 		return stats
 	}
 
-	if numWorkers <= 0 {
-		numWorkers = 1
-	}
+	numWorkers := opts.workerCount()
+	tally := tallyOf(ctx)
+	tally.directory(filepath.ToSlash(absPath))
+	progress := progressOf(ctx)
 
 	// Phase 1: Collect all image files from the directory tree
 	var allFiles []fileInfo
-	err = filepath.Walk(absPath, func(path string, info os.FileInfo, err error) error {
+	ignore := newIgnoreMatcher()
+	err = walkTree(absPath, opts.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if cerr := checkpoint(ctx); cerr != nil {
+			return cerr
+		}
 		if err != nil {
 			progressChan <- "Error accessing " + path + ": " + err.Error()
 			return nil
 		}
+		if path != absPath && (opts.isExcluded(path) || ignore.ignored(absPath, path, info.IsDir())) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 		if info.IsDir() {
+			if opts.beyondMaxDepth(absPath, path, true) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
-		if !domain.IsImageFile(path) {
+		if !opts.includes(path) || !opts.inSizeRange(info.Size()) {
 			return nil
 		}
-		allFiles = append(allFiles, fileInfo{
+		fi := fileInfo{
 			path:           path,
 			normalizedPath: filepath.ToSlash(path),
 			size:           info.Size(),
 			modTime:        info.ModTime(),
-		})
+			symlink:        isSymlinked(info),
+		}
+		fi.device, fi.inode = inodeOf(path, info)
+		allFiles = append(allFiles, fi)
 		return nil
 	})
 	if err != nil {
 		return stats
 	}
+	progress.rootWalked(len(allFiles))
 
 	if len(allFiles) == 0 {
 		return stats
@@ -261,42 +457,49 @@ func fastScanGalleryDirectory(db *gorm.DB, dirPath string, progressChan chan<- s
 
 	// Phase 2: Batch query existing files from DB by path to build a cache map
 	// Also track all DB record IDs for later cleanup
-	existingMap := make(map[string]domain.ImageFile)
+	existingMap := loadExisting(ctx, db, allFiles, progressChan, opts)
 	checkedIDs := make(map[uint]bool) // IDs of files that were checked
-	const dbBatchSize = 500
-	for i := 0; i < len(allFiles); i += dbBatchSize {
-		end := i + dbBatchSize
-		if end > len(allFiles) {
-			end = len(allFiles)
-		}
-		paths := make([]string, end-i)
-		for j, fi := range allFiles[i:end] {
-			paths[j] = fi.normalizedPath
-		}
-		var existingFiles []domain.ImageFile
-		db.Where("path IN ?", paths).Find(&existingFiles)
-		for _, ef := range existingFiles {
-			existingMap[ef.Path] = ef
-			checkedIDs[ef.ID] = true // Mark this ID as checked (exists on disk)
-		}
+	for _, ef := range existingMap {
+		checkedIDs[ef.ID] = true // Mark this ID as checked (exists on disk)
 	}
 
 	// Phase 3: Check files - if record exists with matching size, skip hashing
+	// If it was moved or renamed, point its record at the new path
 	// Otherwise, compute hash and update/create record
+	moves := findMoveSources(db, newFiles(allFiles, existingMap, opts))
 	var filesToProcess []fileInfo
 	for _, fi := range allFiles {
-		if existing, ok := existingMap[fi.normalizedPath]; ok {
+		existing, ok := existingMap[opts.pathKey(fi.normalizedPath)]
+		if !ok {
+			if source := moves.claim(fi, opts.Throttle); source != nil {
+				if err := moveFile(db, source, fi); err != nil {
+					progressChan <- progress.handled("Error moving " + source.Path + ": " + err.Error())
+					continue
+				}
+				progressChan <- progress.handled("Moved: " + source.Path + " -> " + fi.path)
+				checkedIDs[source.ID] = true
+				stats.Moved++
+				tally.count(0, 1, 0)
+				continue
+			}
+		}
+		if ok {
 			if existing.Size == fi.size {
 				// File exists and size matches - no change needed
+				refreshUnchanged(db, &existing, fi)
 				stats.Unchanged++
-				progressChan <- "Skipped (unchanged): " + fi.path
+				progressChan <- progress.handled("Skipped (unchanged): " + fi.path)
 				continue
 			}
 			// Size differs - need to update
+			fi.deferHash = opts.SizePrefilter
+			fi.thumbnail = opts.needsThumbnail(fi)
 			filesToProcess = append(filesToProcess, fi)
 			stats.TotalChecked++ // Count modified as checked
 		} else {
 			// New file - need to create
+			fi.deferHash = opts.SizePrefilter
+			fi.thumbnail = opts.needsThumbnail(fi)
 			filesToProcess = append(filesToProcess, fi)
 			stats.TotalChecked++ // Count created as checked
 		}
@@ -305,8 +508,8 @@ func fastScanGalleryDirectory(db *gorm.DB, dirPath string, progressChan chan<- s
 	// Phase 3.5: Delete records for files that don't exist on disk anymore
 	// Get all IDs in this directory that were NOT checked
 	var existingFilesInDir []domain.ImageFile
-	prefix := filepath.ToSlash(absPath) + "/"
-	db.Where("path LIKE ?", prefix+"%").Find(&existingFilesInDir)
+	prefix := opts.pathKey(filepath.ToSlash(absPath) + "/")
+	db.Where(opts.pathColumn()+` LIKE ? ESCAPE '\'`, escapeLike(prefix)+"%").Find(&existingFilesInDir)
 
 	for _, ef := range existingFilesInDir {
 		if !checkedIDs[ef.ID] {
@@ -314,6 +517,7 @@ func fastScanGalleryDirectory(db *gorm.DB, dirPath string, progressChan chan<- s
 			progressChan <- "Removing missing file from DB: " + ef.Path
 			db.Delete(&ef)
 			stats.Deleted++
+			tally.count(0, 0, 1)
 		}
 	}
 
@@ -322,71 +526,55 @@ func fastScanGalleryDirectory(db *gorm.DB, dirPath string, progressChan chan<- s
 	}
 
 	// Phase 4: Hash files in parallel using a worker pool
-	jobs := make(chan fileInfo, numWorkers*2)
-	results := make(chan hashResult, numWorkers*2)
-
-	var wg sync.WaitGroup
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for fi := range jobs {
-				hash, err := calculateFileHash(fi.path)
-				var existing *domain.ImageFile
-				if ef, ok := existingMap[fi.normalizedPath]; ok {
-					existing = &ef
-				}
-				results <- hashResult{
-					fi:       fi,
-					hash:     hash,
-					err:      err,
-					existing: existing,
-				}
-			}
-		}()
-	}
-
-	// Close results channel when all workers finish
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Send jobs to workers
-	go func() {
-		for _, fi := range filesToProcess {
-			jobs <- fi
+	results := hashInParallel(ctx, filesToProcess, numWorkers, func(fi fileInfo) hashResult {
+		var existing *domain.ImageFile
+		if ef, ok := existingMap[opts.pathKey(fi.normalizedPath)]; ok {
+			existing = &ef
 		}
-		close(jobs)
-	}()
+		return hashFile(fi, existing, opts)
+	})
 
-	// Phase 5: Collect results and batch write to DB
+	// Phase 5: Collect results and write to DB from this goroutine only
 	const writeBatchSize = 50
 	var toCreate []domain.ImageFile
 	var toUpdate []domain.ImageFile
 
 	for result := range results {
 		if result.err != nil {
-			progressChan <- "Error hashing " + result.fi.path + ": " + result.err.Error()
+			progressChan <- progress.handled("Error hashing " + result.fi.path + ": " + result.err.Error())
 			continue
 		}
 
-		progressChan <- "Processed: " + result.fi.path
+		progressChan <- progress.handled("Processed: " + result.fi.path)
 
 		imageFile := domain.ImageFile{
-			Path:    result.fi.normalizedPath,
-			Size:    result.fi.size,
-			Hash:    result.hash,
-			ModTime: result.fi.modTime,
+			Path:       result.fi.normalizedPath,
+			Size:       result.fi.size,
+			Hash:       result.hash,
+			HashAlgo:   result.hashAlgo,
+			PrefixHash: result.prefixHash,
+			PHash:      result.pHash,
+			PHashAlgo:  result.pHashAlgo,
+			PHashXform: result.pHashXform,
+			PixelHash:  result.pixelHash,
+			Corrupt:    result.corrupt,
+			ModTime:    result.fi.modTime,
+			IsVideo:    domain.IsVideoFile(result.fi.path),
+			IsSymlink:  result.fi.symlink,
+			Device:     result.fi.device,
+			Inode:      result.fi.inode,
 		}
 
 		if result.existing != nil {
 			imageFile.ID = result.existing.ID
+			imageFile.Protected = result.existing.Protected
 			toUpdate = append(toUpdate, imageFile)
 			stats.Modified++
+			tally.count(0, 1, 0)
 		} else {
 			toCreate = append(toCreate, imageFile)
 			stats.Created++
+			tally.count(1, 0, 0)
 		}
 
 		if len(toCreate)+len(toUpdate) >= writeBatchSize {
@@ -403,19 +591,21 @@ func fastScanGalleryDirectory(db *gorm.DB, dirPath string, progressChan chan<- s
 	return stats
 }
 
-// findDuplicates finds all duplicate groups from the database
-func findDuplicates(db *gorm.DB) ([]domain.DuplicateGroup, error) {
+// findDuplicates finds all duplicate groups matching the filter from the database
+func findDuplicates(db *gorm.DB, filter DuplicateFilter) ([]domain.DuplicateGroup, error) {
 	type HashSizeCount struct {
-		Hash  string
-		Size  int64
-		Count int64
+		HashAlgo string
+		Hash     string
+		Size     int64
+		Count    int64
 	}
 
 	var duplicateHashSizes []HashSizeCount
-	result := db.Model(&domain.ImageFile{}).
-		Select("hash, size, count(*) as count").
-		Group("hash, size").
-		Having("count(*) > 1").
+	result := filter.applyGroups(db.Model(&domain.ImageFile{})).
+		Select("hash_algo, hash, size, " + filter.countExpr() + " as count").
+		Where("hash <> ''").
+		Group("hash_algo, hash, size").
+		Having(filter.countExpr() + " > 1").
 		Scan(&duplicateHashSizes)
 
 	if result.Error != nil {
@@ -425,11 +615,14 @@ func findDuplicates(db *gorm.DB) ([]domain.DuplicateGroup, error) {
 	var groups []domain.DuplicateGroup
 	for _, hs := range duplicateHashSizes {
 		var files []domain.ImageFile
-		db.Where("hash = ? AND size = ?", hs.Hash, hs.Size).Find(&files)
+		filter.apply(db.Where("hash_algo = ? AND hash = ? AND size = ?", hs.HashAlgo, hs.Hash, hs.Size)).Find(&files)
 
 		var existingFiles []domain.ImageFile
-		for _, f := range files {
-			if _, err := os.Stat(f.Path); err == nil {
+		for _, f := range filter.collapse(files) {
+			// Remote files are checked by their scans
+			if objectstore.IsRemote(f.Path) {
+				existingFiles = append(existingFiles, f)
+			} else if _, err := os.Stat(f.Path); err == nil {
 				existingFiles = append(existingFiles, f)
 			} else {
 				db.Delete(&f)
@@ -448,49 +641,72 @@ func findDuplicates(db *gorm.DB) ([]domain.DuplicateGroup, error) {
 	return groups, nil
 }
 
-// FindDuplicatesPaginated finds duplicate groups with pagination
-func FindDuplicatesPaginated(db *gorm.DB, offset, limit int) ([]domain.DuplicateGroup, int, int, error) {
+// FindDuplicatesPaginated finds duplicate groups matching the filter in the given order,
+// with pagination. The page of groups and the totals are computed in SQL, and the files
+// of the page are loaded with IN queries rather than one query per group.
+func FindDuplicatesPaginated(db *gorm.DB, filter DuplicateFilter, order GroupOrder, offset, limit int) ([]domain.DuplicateGroup, int, int, error) {
 	type HashSizeCount struct {
-		Hash  string
-		Size  int64
-		Count int64
+		HashAlgo string
+		Hash     string
+		Size     int64
+		Count    int64
 	}
 
-	var allDuplicateHashSizes []HashSizeCount
-	result := db.Model(&domain.ImageFile{}).
-		Select("hash, size, count(*) as count").
-		Group("hash, size").
-		Having("count(*) > 1").
-		Order("size DESC").
-		Scan(&allDuplicateHashSizes)
-
-	if result.Error != nil {
-		return nil, 0, 0, result.Error
+	groupQuery := func() *gorm.DB {
+		return filter.applyGroups(db.Model(&domain.ImageFile{})).
+			Select("hash_algo, hash, size, " + filter.countExpr() + " as count").
+			Where("hash <> ''").
+			Group("hash_algo, hash, size").
+			Having(filter.countExpr() + " > 1")
 	}
 
-	totalGroups := len(allDuplicateHashSizes)
-
-	totalFiles := 0
-	for _, hs := range allDuplicateHashSizes {
-		totalFiles += int(hs.Count)
+	var totals struct {
+		GroupCount int
+		FileCount  int
 	}
-
-	if offset >= len(allDuplicateHashSizes) {
-		return []domain.DuplicateGroup{}, totalGroups, totalFiles, nil
+	if err := db.Table("(?) AS duplicate_groups", groupQuery()).
+		Select("COUNT(*) AS group_count, COALESCE(SUM(count), 0) AS file_count").
+		Scan(&totals).Error; err != nil {
+		return nil, 0, 0, err
 	}
-
-	end := offset + limit
-	if end > len(allDuplicateHashSizes) {
-		end = len(allDuplicateHashSizes)
+	if offset >= totals.GroupCount {
+		return []domain.DuplicateGroup{}, totals.GroupCount, totals.FileCount, nil
 	}
 
-	paginatedHashSizes := allDuplicateHashSizes[offset:end]
+	var page []HashSizeCount
+	if err := groupQuery().
+		Order(filter.orderBy(order, "size DESC", "hash, size")).
+		Offset(offset).Limit(limit).
+		Scan(&page).Error; err != nil {
+		return nil, 0, 0, err
+	}
 
-	var groups []domain.DuplicateGroup
-	for _, hs := range paginatedHashSizes {
+	type groupKey struct {
+		algo, hash string
+		size       int64
+	}
+	hashes := make([]string, len(page))
+	for i, hs := range page {
+		hashes[i] = hs.Hash
+	}
+	byGroup := make(map[groupKey][]domain.ImageFile, len(page))
+	// Chunked to stay below the bound parameter limit of SQLite
+	const chunkSize = 500
+	for start := 0; start < len(hashes); start += chunkSize {
+		end := min(start+chunkSize, len(hashes))
 		var files []domain.ImageFile
-		db.Where("hash = ? AND size = ?", hs.Hash, hs.Size).Find(&files)
+		if err := filter.apply(db.Where("hash IN ?", hashes[start:end])).Order("id").Find(&files).Error; err != nil {
+			return nil, 0, 0, err
+		}
+		for _, f := range files {
+			key := groupKey{f.HashAlgo, f.Hash, f.Size}
+			byGroup[key] = append(byGroup[key], f)
+		}
+	}
 
+	var groups []domain.DuplicateGroup
+	for _, hs := range page {
+		files := filter.collapse(byGroup[groupKey{hs.HashAlgo, hs.Hash, hs.Size}])
 		if len(files) > 1 {
 			groups = append(groups, domain.DuplicateGroup{
 				Hash:  hs.Hash,
@@ -500,20 +716,174 @@ func FindDuplicatesPaginated(db *gorm.DB, offset, limit int) ([]domain.Duplicate
 		}
 	}
 
-	return groups, totalGroups, totalFiles, nil
+	return groups, totals.GroupCount, totals.FileCount, nil
 }
 
-// cleanupMissingFiles removes database entries for files that no longer exist
-func cleanupMissingFiles(db *gorm.DB, progressChan chan<- string) error {
+// FindGroup returns the exact duplicate group of the files with the given content hash
+// that are still on disk, or remote, ordered by path, or nil when there are none. A
+// group whose other copies were removed holds a single file.
+func FindGroup(db *gorm.DB, hash string) (*domain.DuplicateGroup, error) {
 	var files []domain.ImageFile
-	db.Find(&files)
+	if err := db.Where("hash = ?", hash).Order("size DESC, path").Find(&files).Error; err != nil {
+		return nil, err
+	}
 
+	var group *domain.DuplicateGroup
 	for _, f := range files {
-		if _, err := os.Stat(f.Path); os.IsNotExist(err) {
-			progressChan <- fmt.Sprintf("Removing missing file from DB: %s", f.Path)
-			db.Delete(&f)
+		if !objectstore.IsRemote(f.Path) {
+			if _, err := os.Stat(f.Path); err != nil {
+				continue
+			}
+		}
+		if group == nil {
+			group = &domain.DuplicateGroup{Hash: f.Hash, Size: f.Size}
+		} else if f.Size != group.Size || f.HashAlgo != group.Files[0].HashAlgo {
+			// An equal hash of another size or algorithm is not the same content
+			continue
 		}
+		group.Files = append(group.Files, f)
+	}
+	return group, nil
+}
+
+// cleanupPageSize is the number of records cleanupMissingFiles loads, stats and
+// deletes at a time
+const cleanupPageSize = 500
+
+// cleanupMissingFiles removes database entries for files that no longer exist, and
+// with case-insensitive paths the extra entries of files recorded under differently
+// cased paths. A non-empty dir limits the cleanup to files under that directory.
+// Records are paged by ID and the files of a page are checked on a pool of workers,
+// so the whole table is never loaded and slow network storage is queried in parallel.
+func cleanupMissingFiles(ctx context.Context, db *gorm.DB, dir string, progressChan chan<- string, opts ScanOptions) error {
+	scope := func() *gorm.DB {
+		query := db.Model(&domain.ImageFile{})
+		if dir != "" {
+			query = query.Where(opts.pathColumn()+` LIKE ? ESCAPE '\'`, escapeLike(opts.pathKey(filepath.ToSlash(dir)))+"/%")
+		}
+		// The records of unavailable roots are kept until they are back
+		for _, root := range unavailableRootsOf(ctx) {
+			query = query.Where(opts.pathColumn()+` NOT LIKE ? ESCAPE '\'`, rootPrefix(root, opts))
+		}
+		return query
+	}
+
+	if opts.CaseInsensitivePaths {
+		if err := removeCaseVariants(ctx, db, scope, progressChan, opts); err != nil {
+			return err
+		}
+	}
+
+	var total int64
+	scope().Count(&total)
+	var lastID uint
+	checked, removed := 0, 0
+	for {
+		if err := checkpoint(ctx); err != nil {
+			return err
+		}
+		var page []domain.ImageFile
+		if err := scope().Select("id, path").Where("id > ?", lastID).Order("id").Limit(cleanupPageSize).Find(&page).Error; err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			break
+		}
+		lastID = page[len(page)-1].ID
+
+		missing, err := statMissing(ctx, page, opts.workerCount())
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			ids := make([]uint, len(missing))
+			for i, f := range missing {
+				progressChan <- fmt.Sprintf("Removing missing file from DB: %s", f.Path)
+				ids[i] = f.ID
+			}
+			if err := db.Where("id IN ?", ids).Delete(&domain.ImageFile{}).Error; err != nil {
+				return err
+			}
+			tallyOf(ctx).count(0, 0, len(missing))
+			removed += len(missing)
+		}
+		checked += len(page)
+		progressChan <- fmt.Sprintf("Checked %d/%d indexed files, %d missing", checked, total, removed)
 	}
 
 	return nil
 }
+
+// removeCaseVariants deletes the extra records of files recorded under differently
+// cased paths, keeping the one pickRecords picks
+func removeCaseVariants(ctx context.Context, db *gorm.DB, scope func() *gorm.DB, progressChan chan<- string, opts ScanOptions) error {
+	var keys []string
+	if err := scope().Select(opts.pathColumn()).Group(opts.pathColumn()).Having("COUNT(*) > 1").Pluck(opts.pathColumn(), &keys).Error; err != nil {
+		return err
+	}
+	for start := 0; start < len(keys); start += cleanupPageSize {
+		if err := checkpoint(ctx); err != nil {
+			return err
+		}
+		end := min(start+cleanupPageSize, len(keys))
+		var rows []domain.ImageFile
+		if err := db.Where(opts.pathColumn()+" IN ?", keys[start:end]).Find(&rows).Error; err != nil {
+			return err
+		}
+		_, variants := opts.pickRecords(rows, nil)
+		if len(variants) == 0 {
+			continue
+		}
+		ids := make([]uint, len(variants))
+		for i, f := range variants {
+			progressChan <- "Removing differently cased duplicate entry from DB: " + f.Path
+			ids[i] = f.ID
+		}
+		if err := db.Where("id IN ?", ids).Delete(&domain.ImageFile{}).Error; err != nil {
+			return err
+		}
+		tallyOf(ctx).count(0, 0, len(variants))
+	}
+	return nil
+}
+
+// statMissing returns the records of files that no longer exist, checked on a pool of
+// numWorkers goroutines. Objects are skipped: scanObjectStore checks them.
+func statMissing(ctx context.Context, files []domain.ImageFile, numWorkers int) ([]domain.ImageFile, error) {
+	jobs := make(chan domain.ImageFile)
+	var (
+		mu      sync.Mutex
+		missing []domain.ImageFile
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range jobs {
+				if checkpoint(ctx) != nil {
+					continue
+				}
+				if _, err := os.Stat(f.Path); os.IsNotExist(err) {
+					mu.Lock()
+					missing = append(missing, f)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, f := range files {
+		if objectstore.IsRemote(f.Path) {
+			continue
+		}
+		jobs <- f
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Report in ID order regardless of which worker finished first
+	sort.Slice(missing, func(i, j int) bool { return missing[i].ID < missing[j].ID })
+	return missing, nil
+}
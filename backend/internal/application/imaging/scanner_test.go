@@ -0,0 +1,150 @@
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestFindGroup(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	dir := t.TempDir()
+	for _, name := range []string{"b.jpg", "a.jpg", "other-algo.jpg"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := []domain.ImageFile{
+		{Path: filepath.Join(dir, "b.jpg"), Size: 100, Hash: "h", HashAlgo: "sha256"},
+		{Path: filepath.Join(dir, "a.jpg"), Size: 100, Hash: "h", HashAlgo: "sha256"},
+		{Path: filepath.Join(dir, "missing.jpg"), Size: 100, Hash: "h", HashAlgo: "sha256"},
+		{Path: filepath.Join(dir, "other-algo.jpg"), Size: 100, Hash: "h", HashAlgo: "md5"},
+	}
+	for i := range files {
+		if err := db.Create(&files[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	group, err := FindGroup(db, "h")
+	if err != nil || group == nil {
+		t.Fatalf("FindGroup = %v, %v", group, err)
+	}
+	// Files missing on disk and equal hashes of another algorithm are left out
+	if len(group.Files) != 2 || group.Files[0].Path != files[1].Path || group.Files[1].Path != files[0].Path {
+		t.Errorf("FindGroup files = %+v, want a.jpg and b.jpg", group.Files)
+	}
+
+	if group, err := FindGroup(db, "unknown"); err != nil || group != nil {
+		t.Errorf("FindGroup(unknown) = %v, %v; want nil", group, err)
+	}
+}
+
+func TestFlushDBBatchUpserts(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing := []domain.ImageFile{
+		{Path: "/photos/a.jpg", Size: 1, Hash: "old-a", ModTime: created, Protected: true, CreatedAt: created},
+		{Path: "/photos/B.jpg", Size: 1, Hash: "old-b", ModTime: created, CreatedAt: created},
+	}
+	if err := db.Create(&existing).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// a.jpg was recorded after the scan loaded its records, b.jpg changed case
+	toCreate := []domain.ImageFile{
+		{Path: "/photos/a.jpg", Size: 2, Hash: "new-a", ModTime: created},
+		{Path: "/photos/c.jpg", Size: 3, Hash: "new-c", ModTime: created},
+	}
+	toUpdate := []domain.ImageFile{
+		{ID: existing[1].ID, Path: "/photos/b.jpg", Size: 4, Hash: "new-b", ModTime: created},
+	}
+	flushDBBatch(db, &toCreate, &toUpdate)
+	if len(toCreate) != 0 || len(toUpdate) != 0 {
+		t.Fatal("the batch slices should be reset")
+	}
+
+	var files []domain.ImageFile
+	db.Order("path").Find(&files)
+	if len(files) != 3 {
+		t.Fatalf("got %d records, want 3", len(files))
+	}
+	a, b := files[0], files[1]
+	if a.ID != existing[0].ID || a.Hash != "new-a" || !a.Protected || !a.CreatedAt.Equal(created) {
+		t.Errorf("a.jpg was not updated in place: %+v", a)
+	}
+	if b.ID != existing[1].ID || b.Path != "/photos/b.jpg" || b.Hash != "new-b" || !b.CreatedAt.Equal(created) {
+		t.Errorf("b.jpg was not updated in place: %+v", b)
+	}
+	if files[2].Path != "/photos/c.jpg" || files[2].Hash != "new-c" {
+		t.Errorf("c.jpg was not created: %+v", files[2])
+	}
+}
+
+func TestCleanupMissingFilesPages(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	dir := t.TempDir()
+	const records = 2*cleanupPageSize + 10
+	kept := map[string]bool{}
+	rows := make([]domain.ImageFile, records)
+	for i := range rows {
+		path := filepath.ToSlash(filepath.Join(dir, fmt.Sprintf("%04d.jpg", i)))
+		if i%cleanupPageSize == 7 {
+			if err := os.WriteFile(path, []byte("photo"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			kept[path] = true
+		}
+		rows[i] = domain.ImageFile{Path: path, Hash: "h", ModTime: time.Now()}
+	}
+	if err := db.CreateInBatches(&rows, 100).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	progress := make(chan string, 2*records)
+	if err := cleanupMissingFiles(context.Background(), db, dir, progress, ScanOptions{Workers: 4}); err != nil {
+		t.Fatal(err)
+	}
+	close(progress)
+	var last string
+	for msg := range progress {
+		last = msg
+	}
+	if want := fmt.Sprintf("Checked %d/%d indexed files, %d missing", records, records, records-len(kept)); last != want {
+		t.Errorf("last progress message = %q, want %q", last, want)
+	}
+
+	var left []domain.ImageFile
+	db.Find(&left)
+	if len(left) != len(kept) {
+		t.Fatalf("%d records left, want %d", len(left), len(kept))
+	}
+	for _, f := range left {
+		if !kept[f.Path] {
+			t.Errorf("record of missing file %s was kept", f.Path)
+		}
+	}
+}
+
+func TestCleanupMissingFilesInDirMatchesLiterally(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	base := t.TempDir()
+	// A missing file of a sibling folder whose name differs only at the "_"
+	sibling := domain.ImageFile{Path: filepath.ToSlash(filepath.Join(base, "aXb", "gone.jpg")), Hash: "h", ModTime: time.Now()}
+	if err := db.Create(&sibling).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanupMissingFiles(context.Background(), db, filepath.Join(base, "a_b"), make(chan string, 100), ScanOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var left int64
+	db.Model(&domain.ImageFile{}).Count(&left)
+	if left != 1 {
+		t.Errorf("%d records left, want the sibling folder outside the cleanup left alone", left)
+	}
+}
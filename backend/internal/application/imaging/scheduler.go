@@ -0,0 +1,138 @@
+package imaging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"image-toolkit/internal/domain"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+// ScanScheduler triggers full gallery scans on a cron schedule.
+// The schedule is stored in AppSettings so changes made through the API survive restarts.
+type ScanScheduler struct {
+	mu          sync.Mutex
+	db          *gorm.DB
+	scanManager *ScanManager
+	cron        *cron.Cron
+	entryID     cron.EntryID
+	spec        string
+}
+
+// NewScanScheduler creates a new scan scheduler
+func NewScanScheduler(db *gorm.DB, scanManager *ScanManager) *ScanScheduler {
+	return &ScanScheduler{
+		db:          db,
+		scanManager: scanManager,
+		cron:        cron.New(),
+	}
+}
+
+// ValidateSchedule checks a standard 5-field cron expression (or descriptor such as @daily).
+// An empty expression is valid and disables scheduled scans.
+func ValidateSchedule(spec string) error {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil
+	}
+	if _, err := cron.ParseStandard(spec); err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", spec, err)
+	}
+	return nil
+}
+
+// Start loads the schedule and starts the cron runner. A schedule saved in the database
+// takes precedence over defaultSpec (from configuration).
+func (ss *ScanScheduler) Start(defaultSpec string) error {
+	spec := defaultSpec
+	var settings domain.AppSettings
+	if err := ss.db.First(&settings, 1).Error; err == nil && settings.ScanSchedule != "" {
+		spec = settings.ScanSchedule
+	}
+
+	ss.mu.Lock()
+	err := ss.applyLocked(spec)
+	ss.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	ss.cron.Start()
+	return nil
+}
+
+// Stop stops the cron runner. A scan that was already started keeps running in the ScanManager.
+func (ss *ScanScheduler) Stop() {
+	ss.cron.Stop()
+}
+
+// SetSchedule validates, applies and persists a new schedule. An empty spec disables it.
+func (ss *ScanScheduler) SetSchedule(spec string) error {
+	spec = strings.TrimSpace(spec)
+	if err := ValidateSchedule(spec); err != nil {
+		return err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if err := ss.applyLocked(spec); err != nil {
+		return err
+	}
+
+	return ss.db.Model(&domain.AppSettings{}).Where("id = ?", 1).Update("scan_schedule", spec).Error
+}
+
+// Schedule returns the current cron expression and the next run time (zero if disabled)
+func (ss *ScanScheduler) Schedule() (string, time.Time) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.entryID == 0 {
+		return ss.spec, time.Time{}
+	}
+	return ss.spec, ss.cron.Entry(ss.entryID).Next
+}
+
+// applyLocked replaces the scheduled job
+func (ss *ScanScheduler) applyLocked(spec string) error {
+	if err := ValidateSchedule(spec); err != nil {
+		return err
+	}
+	if ss.entryID != 0 {
+		ss.cron.Remove(ss.entryID)
+		ss.entryID = 0
+	}
+	ss.spec = spec
+	if spec == "" {
+		return nil
+	}
+
+	id, err := ss.cron.AddFunc(spec, ss.run)
+	if err != nil {
+		return err
+	}
+	ss.entryID = id
+	slog.Info("Scan scheduler: scheduled full scan", "schedule", spec)
+	return nil
+}
+
+// run is the cron job: cleanup of missing files plus a scan of every gallery folder
+func (ss *ScanScheduler) run() {
+	jobID, err := ss.scanManager.StartScanWithTrigger(domain.ScanTriggerScheduled)
+	if err != nil {
+		slog.Warn("Scan scheduler: scheduled scan skipped", "reason", err)
+		return
+	}
+	slog.Info("Scan scheduler: scheduled scan started", "job", jobID)
+}
+
+// RecentScanRuns returns the latest scan runs, newest first
+func RecentScanRuns(db *gorm.DB, limit int) ([]domain.ScanRun, error) {
+	var runs []domain.ScanRun
+	err := db.Order("started_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
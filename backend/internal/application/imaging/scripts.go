@@ -0,0 +1,293 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// ScriptType is the shell a generated removal script is written for
+type ScriptType string
+
+const (
+	ScriptBash       ScriptType = "bash"
+	ScriptSh         ScriptType = "sh" // strict POSIX sh, for systems without bash
+	ScriptPowerShell ScriptType = "powershell"
+	ScriptBat        ScriptType = "bat" // cmd.exe, for machines whose execution policy blocks PowerShell scripts
+)
+
+var (
+	ErrUnknownScriptType  = errors.New("script type must be bash, sh, powershell or bat")
+	ErrRestoreNeedsTrash  = errors.New("a restore script needs a trash directory to restore from")
+	ErrScriptNotEncodable = errors.New("a path is not representable in the script encoding")
+)
+
+// ParseScriptType validates a script type from a request; empty selects bash
+func ParseScriptType(name string) (ScriptType, error) {
+	switch t := ScriptType(strings.ToLower(strings.TrimSpace(name))); t {
+	case "":
+		return ScriptBash, nil
+	case ScriptBash, ScriptSh, ScriptPowerShell, ScriptBat:
+		return t, nil
+	default:
+		return "", ErrUnknownScriptType
+	}
+}
+
+// ScriptName returns the file name of a removal script, or of its restore script
+func (t ScriptType) ScriptName(restore bool) string {
+	name := "remove_duplicates"
+	if restore {
+		name = "restore_duplicates"
+	}
+	switch t {
+	case ScriptPowerShell:
+		return name + ".ps1"
+	case ScriptBat:
+		return name + ".bat"
+	default:
+		return name + ".sh"
+	}
+}
+
+// GenerateScript returns a script that moves the given files into trashDir, or
+// deletes them when trashDir is empty. The source folders are recreated below
+// trashDir, so files of the same name from different folders do not overwrite each
+// other. The script is written in the encoding and with the line endings of format.
+func GenerateScript(t ScriptType, files []string, trashDir string, format ScriptFormat) ([]byte, error) {
+	enc, err := t.resolveFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	// Shell functions get the target folder as an argument, since $(dirname) would
+	// drop trailing newlines of its name
+	var b strings.Builder
+	switch t {
+	case ScriptBash:
+		b.WriteString(shellHeader("/bin/bash", "Removes duplicate files"))
+		if trashDir != "" {
+			b.WriteString(`trash() {
+  if [ -e "$2" ]; then
+    echo "already in the trash: $2" >&2
+  else
+    mkdir -p -- "$3" && mv -n -- "$1" "$2"
+  fi
+}
+
+`)
+		}
+		for _, f := range files {
+			if trashDir == "" {
+				fmt.Fprintf(&b, "rm -f -- %s\n", shellQuote(f))
+			} else {
+				to := trashPathOf(trashDir, f)
+				fmt.Fprintf(&b, "trash %s %s %s\n", shellQuote(f), shellQuote(to), shellQuote(path.Dir(to)))
+			}
+		}
+	case ScriptSh:
+		b.WriteString(shellHeader("/bin/sh", "Removes duplicate files") + "set -u\n\n")
+		if trashDir != "" {
+			b.WriteString(`trash() {
+  if [ -e "$2" ] || [ -L "$2" ]; then
+    printf 'already in the trash: %s\n' "$2" >&2
+  else
+    mkdir -p -- "$3" && mv -- "$1" "$2"
+  fi
+}
+
+`)
+		}
+		for _, f := range files {
+			if trashDir == "" {
+				fmt.Fprintf(&b, "rm -f -- %s\n", shellQuote(f))
+			} else {
+				to := trashPathOf(trashDir, f)
+				fmt.Fprintf(&b, "trash %s %s %s\n", shellQuote(f), shellQuote(to), shellQuote(path.Dir(to)))
+			}
+		}
+	case ScriptBat:
+		b.WriteString(batHeader("Removes duplicate files", enc.codePage))
+		for _, f := range files {
+			if trashDir == "" {
+				fmt.Fprintf(&b, "del /f /q %s\n", batQuote(f))
+			} else {
+				from, to := batQuote(f), batQuote(trashPathOf(trashDir, f))
+				fmt.Fprintf(&b, "if exist %s (echo already in the trash: %s 1>&2) else (mkdir %s 2>nul & move %s %s >nul)\n",
+					to, to, batQuote(path.Dir(trashPathOf(trashDir, f))), from, to)
+			}
+		}
+	case ScriptPowerShell:
+		b.WriteString(powerShellHeader("Removes duplicate files"))
+		if trashDir != "" {
+			b.WriteString(`function Move-ToTrash([string]$From, [string]$To) {
+    if (Test-Path -LiteralPath $To) {
+        Write-Warning "already in the trash: $To"
+    } else {
+        New-Item -ItemType Directory -Force -Path (Split-Path -LiteralPath $To) | Out-Null
+        Move-Item -LiteralPath $From -Destination $To
+    }
+}
+
+`)
+		}
+		for _, f := range files {
+			if trashDir == "" {
+				fmt.Fprintf(&b, "Remove-Item -LiteralPath %s -Force\n", powerShellQuote(f))
+			} else {
+				fmt.Fprintf(&b, "Move-ToTrash %s %s\n", powerShellQuote(f), powerShellQuote(trashPathOf(trashDir, f)))
+			}
+		}
+	default:
+		return nil, ErrUnknownScriptType
+	}
+	return enc.encode(b.String())
+}
+
+// GenerateRestoreScript returns a script that undoes GenerateScript with the same
+// arguments: every file is moved back from trashDir to its original location. Files
+// missing from the trash, or whose original path is taken again, are left alone.
+func GenerateRestoreScript(t ScriptType, files []string, trashDir string, format ScriptFormat) ([]byte, error) {
+	if trashDir == "" {
+		return nil, ErrRestoreNeedsTrash
+	}
+	enc, err := t.resolveFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	switch t {
+	case ScriptBash:
+		b.WriteString(shellHeader("/bin/bash", "Moves the files removed by remove_duplicates.sh back from the trash"))
+		b.WriteString(`restore() {
+  if [ ! -e "$1" ]; then
+    echo "not in the trash: $1" >&2
+  elif [ -e "$2" ]; then
+    echo "already exists: $2" >&2
+  else
+    mkdir -p -- "$3" && mv -n -- "$1" "$2"
+  fi
+}
+
+`)
+		for _, f := range files {
+			fmt.Fprintf(&b, "restore %s %s %s\n", shellQuote(trashPathOf(trashDir, f)), shellQuote(f), shellQuote(path.Dir(f)))
+		}
+	case ScriptSh:
+		b.WriteString(shellHeader("/bin/sh", "Moves the files removed by remove_duplicates.sh back from the trash") + "set -u\n\n")
+		b.WriteString(`restore() {
+  if [ ! -e "$1" ] && [ ! -L "$1" ]; then
+    printf 'not in the trash: %s\n' "$1" >&2
+  elif [ -e "$2" ] || [ -L "$2" ]; then
+    printf 'already exists: %s\n' "$2" >&2
+  else
+    mkdir -p -- "$3" && mv -- "$1" "$2"
+  fi
+}
+
+`)
+		for _, f := range files {
+			fmt.Fprintf(&b, "restore %s %s %s\n", shellQuote(trashPathOf(trashDir, f)), shellQuote(f), shellQuote(path.Dir(f)))
+		}
+	case ScriptBat:
+		b.WriteString(batHeader("Moves the files removed by remove_duplicates.bat back from the trash", enc.codePage))
+		for _, f := range files {
+			from, to := batQuote(trashPathOf(trashDir, f)), batQuote(f)
+			fmt.Fprintf(&b, "if not exist %s (echo not in the trash: %s 1>&2) else if exist %s (echo already exists: %s 1>&2) else (mkdir %s 2>nul & move %s %s >nul)\n",
+				from, from, to, to, batQuote(path.Dir(f)), from, to)
+		}
+	case ScriptPowerShell:
+		b.WriteString(powerShellHeader("Moves the files removed by remove_duplicates.ps1 back from the trash"))
+		b.WriteString(`function Restore-File([string]$From, [string]$To) {
+    if (-not (Test-Path -LiteralPath $From)) {
+        Write-Warning "not in the trash: $From"
+    } elseif (Test-Path -LiteralPath $To) {
+        Write-Warning "already exists: $To"
+    } else {
+        New-Item -ItemType Directory -Force -Path (Split-Path -LiteralPath $To) | Out-Null
+        Move-Item -LiteralPath $From -Destination $To
+    }
+}
+
+`)
+		for _, f := range files {
+			fmt.Fprintf(&b, "Restore-File %s %s\n", powerShellQuote(trashPathOf(trashDir, f)), powerShellQuote(f))
+		}
+	default:
+		return nil, ErrUnknownScriptType
+	}
+	return enc.encode(b.String())
+}
+
+// trashPathOf returns where a script moves file in trashDir: its path below the
+// root, with a drive letter or UNC server and share as the first folders
+// (C:/Photos/a.jpg goes to trashDir/C/Photos/a.jpg)
+func trashPathOf(trashDir, file string) string {
+	rel := strings.TrimLeft(strings.ReplaceAll(file, `\`, "/"), "/")
+	if len(rel) >= 2 && rel[1] == ':' {
+		rel = rel[:1] + rel[2:]
+	}
+	return strings.TrimSuffix(trashDir, "/") + "/" + strings.TrimLeft(path.Clean("/"+rel), "/")
+}
+
+func shellHeader(shell, purpose string) string {
+	return fmt.Sprintf("#!%s\n# %s\n# Generated by image-toolkit on %s\n\n", shell, purpose, time.Now().Format(time.RFC3339))
+}
+
+// shellQuote single-quotes s for bash and POSIX sh, so no character in it is
+// special, newlines included
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// batHeader starts a cmd script; cmd reads the lines after chcp in the code page of
+// the script encoding
+func batHeader(purpose string, codePage int) string {
+	return fmt.Sprintf("@echo off\nrem %s\nrem Generated by image-toolkit on %s\nsetlocal DisableDelayedExpansion\nchcp %d >nul\n\n", purpose, time.Now().Format(time.RFC3339), codePage)
+}
+
+// batQuote double-quotes the Windows form of a path for cmd. Windows paths cannot
+// contain quotes, and only % keeps a meaning inside them. Extended-length paths are
+// not used, since cmd commands do not accept them.
+func batQuote(p string) string {
+	return `"` + strings.ReplaceAll(windowsPath(p), "%", "%%") + `"`
+}
+
+func powerShellHeader(purpose string) string {
+	return fmt.Sprintf("# %s\n# Generated by image-toolkit on %s\n\n", purpose, time.Now().Format(time.RFC3339))
+}
+
+// powerShellQuote single-quotes the Windows form of a path for PowerShell: slashes
+// become backslashes, a UNC prefix (//server/share) is kept as \\server\share, and
+// paths too long for the Win32 API get the extended-length prefix
+func powerShellQuote(p string) string {
+	return "'" + strings.ReplaceAll(extendedLengthPath(windowsPath(p)), "'", "''") + "'"
+}
+
+// windowsPath converts a stored slash path to backslashes, collapsing repeated
+// separators everywhere except in the leading \\ of a UNC path
+func windowsPath(p string) string {
+	unc := strings.HasPrefix(p, "//") || strings.HasPrefix(p, `\\`)
+	var b strings.Builder
+	prevSep := false
+	for _, r := range strings.TrimLeft(p, `/\`) {
+		if r == '/' || r == '\\' {
+			if !prevSep {
+				b.WriteByte('\\')
+			}
+			prevSep = true
+			continue
+		}
+		prevSep = false
+		b.WriteRune(r)
+	}
+	switch {
+	case unc:
+		return `\\` + b.String()
+	case strings.HasPrefix(p, "/") || strings.HasPrefix(p, `\`):
+		return `\` + b.String()
+	default:
+		return b.String()
+	}
+}
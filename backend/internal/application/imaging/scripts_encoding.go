@@ -0,0 +1,134 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var ErrScriptFormat = errors.New("unsupported script encoding or line endings for this script type")
+
+// ScriptFormat selects how a generated script is written. Empty fields select the
+// defaults of the script type: Windows-1251 with CRLF for PowerShell, UTF-8 with
+// CRLF for cmd and UTF-8 with LF for the shells.
+type ScriptFormat struct {
+	Encoding    string // utf-8, utf-8-bom, utf-16le or a code page such as windows-1252 or cp866
+	LineEndings string // lf or crlf
+}
+
+// scriptEncoding is a resolved ScriptFormat
+type scriptEncoding struct {
+	charset  string
+	encoding encoding.Encoding // nil for UTF-8
+	bom      bool              // UTF-8 with a byte order mark
+	codePage int               // Windows code page, for chcp in cmd scripts; 0 if unknown
+	crlf     bool
+}
+
+// codePageName matches code page names that carry the Windows code page number
+var codePageName = regexp.MustCompile(`^(?:cp|windows-|ibm)(\d+)$`)
+
+// resolveFormat applies the defaults of t to f and checks that t can run a script
+// in the result: shells and cmd cannot read UTF-16 or a byte order mark, and cmd
+// needs a code page number for chcp
+func (t ScriptType) resolveFormat(f ScriptFormat) (scriptEncoding, error) {
+	name := strings.ToLower(strings.TrimSpace(f.Encoding))
+	if name == "" {
+		name = "utf-8"
+		if t == ScriptPowerShell {
+			name = "windows-1251"
+		}
+	}
+
+	var enc scriptEncoding
+	switch name {
+	case "utf-8", "utf8":
+		enc = scriptEncoding{charset: "utf-8", codePage: 65001}
+	case "utf-8-bom", "utf-8-sig":
+		enc = scriptEncoding{charset: "utf-8", bom: true, codePage: 65001}
+	case "utf-16le", "utf-16":
+		enc = scriptEncoding{charset: "utf-16le", encoding: unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), codePage: 1200}
+	default:
+		e, err := ianaindex.IANA.Encoding(name)
+		if err != nil || e == nil {
+			return enc, fmt.Errorf("%w: unknown encoding %q", ErrScriptFormat, f.Encoding)
+		}
+		enc = scriptEncoding{charset: name, encoding: e}
+		if canonical, err := ianaindex.IANA.Name(e); err == nil {
+			enc.charset = strings.ToLower(canonical)
+		}
+		if m := codePageName.FindStringSubmatch(name); m != nil {
+			enc.codePage, _ = strconv.Atoi(m[1])
+		} else if e == charmap.KOI8R {
+			enc.codePage = 20866
+		} else if e == charmap.KOI8U {
+			enc.codePage = 21866
+		}
+	}
+	if t != ScriptPowerShell && (enc.bom || enc.codePage == 1200) {
+		return enc, fmt.Errorf("%w: %s cannot read %s", ErrScriptFormat, t, f.Encoding)
+	}
+	if t == ScriptBat && enc.codePage == 0 {
+		return enc, fmt.Errorf("%w: no Windows code page for %s", ErrScriptFormat, f.Encoding)
+	}
+
+	switch strings.ToLower(f.LineEndings) {
+	case "":
+		enc.crlf = t == ScriptPowerShell || t == ScriptBat
+	case "crlf":
+		if t == ScriptBash || t == ScriptSh {
+			// The shebang would name "bash\r" and every line would end in a stray \r
+			return enc, fmt.Errorf("%w: %s cannot read crlf line endings", ErrScriptFormat, t)
+		}
+		enc.crlf = true
+	case "lf":
+	default:
+		return enc, fmt.Errorf("%w: line endings must be lf or crlf", ErrScriptFormat)
+	}
+	return enc, nil
+}
+
+// ContentType returns the MIME type of a script of type t written in format f
+func (t ScriptType) ContentType(f ScriptFormat) string {
+	charset := "utf-8"
+	if enc, err := t.resolveFormat(f); err == nil {
+		charset = enc.charset
+	}
+	if t == ScriptBash || t == ScriptSh {
+		return "text/x-shellscript; charset=" + charset
+	}
+	return "text/plain; charset=" + charset
+}
+
+// encode converts a script written with LF line endings to the resolved format
+func (e scriptEncoding) encode(script string) ([]byte, error) {
+	if e.crlf {
+		// This also rewrites the newlines of quoted paths, changing the path. Shell
+		// scripts always keep LF, so only PowerShell and cmd scripts are affected.
+		script = strings.ReplaceAll(script, "\n", "\r\n")
+	}
+	if e.encoding == nil {
+		if e.bom {
+			return append([]byte("\xef\xbb\xbf"), script...), nil
+		}
+		return []byte(script), nil
+	}
+	encoded, err := e.encoding.NewEncoder().String(script)
+	if err != nil {
+		// Name the first line that cannot be encoded
+		for i, line := range strings.Split(script, "\n") {
+			if _, lineErr := e.encoding.NewEncoder().String(line); lineErr != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, ErrScriptNotEncodable)
+			}
+		}
+		return nil, ErrScriptNotEncodable
+	}
+	return []byte(encoded), nil
+}
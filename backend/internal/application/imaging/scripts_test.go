@@ -0,0 +1,192 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestShellScriptsRoundTrip(t *testing.T) {
+	for _, st := range []ScriptType{ScriptBash, ScriptSh} {
+		t.Run(string(st), func(t *testing.T) {
+			shell, err := exec.LookPath(string(st))
+			if err != nil {
+				t.Skipf("%s not available", st)
+			}
+			testShellScriptRoundTrip(t, st, shell)
+		})
+	}
+}
+
+func testShellScriptRoundTrip(t *testing.T, st ScriptType, shell string) {
+	dir := t.TempDir()
+	trash := filepath.ToSlash(filepath.Join(dir, "trash"))
+	names := []string{"sub dir/a.jpg", "sub dir/it's here.jpg", "sub dir/-dash.png", "other/a.jpg"}
+	if runtime.GOOS != "windows" {
+		names = append(names, "new\nline\n/b.jpg")
+	}
+	var files []string
+	// Files of the same name from different folders must not overwrite each other
+	for _, name := range names {
+		p := filepath.Join(dir, "photos", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, filepath.ToSlash(p))
+	}
+
+	run := func(script []byte) {
+		t.Helper()
+		cmd := exec.Command(shell, "-s")
+		cmd.Stdin = bytes.NewReader(script)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("script failed: %v\n%s", err, out)
+		}
+	}
+
+	remove, err := GenerateScript(st, files, trash, ScriptFormat{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run(remove)
+	for _, f := range files {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("%s not moved to the trash", f)
+		}
+		if _, err := os.Stat(trashPathOf(trash, f)); err != nil {
+			t.Errorf("%s missing from the trash: %v", f, err)
+		}
+	}
+
+	restore, err := GenerateRestoreScript(st, files, trash, ScriptFormat{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The restore script recreates removed folders
+	for _, f := range files {
+		os.Remove(filepath.Dir(f))
+	}
+	run(restore)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil || !strings.HasSuffix(f, string(data)) {
+			t.Errorf("%s not restored: %q, %v", f, data, err)
+		}
+	}
+
+	if _, err := GenerateRestoreScript(st, files, "", ScriptFormat{}); !errors.Is(err, ErrRestoreNeedsTrash) {
+		t.Errorf("restore without a trash directory: got %v", err)
+	}
+}
+
+func TestBatScripts(t *testing.T) {
+	script, err := GenerateScript(ScriptBat, []string{"C:/Photos/100% (1).jpg"}, "D:/Trash", ScriptFormat{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `if exist "D:\Trash\C\Photos\100%% (1).jpg" (echo already in the trash: "D:\Trash\C\Photos\100%% (1).jpg" 1>&2) else (mkdir "D:\Trash\C\Photos" 2>nul & move "C:\Photos\100%% (1).jpg" "D:\Trash\C\Photos\100%% (1).jpg" >nul)` + "\r\n"
+	if !strings.HasSuffix(string(script), want) || !strings.Contains(string(script), "chcp 65001") {
+		t.Errorf("unexpected cmd script:\n%s", script)
+	}
+}
+
+func TestTrashPathOf(t *testing.T) {
+	tests := []struct{ file, want string }{
+		{"/photos/2020/a.jpg", "/trash/photos/2020/a.jpg"},
+		{"C:/Photos/a.jpg", "/trash/C/Photos/a.jpg"},
+		{"//server/share/a.jpg", "/trash/server/share/a.jpg"},
+		{"/photos/../../etc/a.jpg", "/trash/etc/a.jpg"},
+	}
+	for _, tt := range tests {
+		if got := trashPathOf("/trash/", tt.file); got != tt.want {
+			t.Errorf("trashPathOf(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestPowerShellScripts(t *testing.T) {
+	tests := []struct{ path, want string }{
+		{"C:/Photos//2020/a.jpg", `C:\Photos\2020\a.jpg`},
+		{"//server/share/photos/a.jpg", `\\server\share\photos\a.jpg`},
+		{`\\server\share\a.jpg`, `\\server\share\a.jpg`},
+		{"/photos/a.jpg", `\photos\a.jpg`},
+	}
+	for _, tt := range tests {
+		if got := windowsPath(tt.path); got != tt.want {
+			t.Errorf("windowsPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	long := "//server/share/" + strings.Repeat("d/", 130) + "a.jpg"
+	if got := powerShellQuote(long); !strings.HasPrefix(got, `'\\?\UNC\server\share\`) {
+		t.Errorf("long UNC path quoted as %s", got)
+	}
+	if got := powerShellQuote("C:/it's.jpg"); got != `'C:\it''s.jpg'` {
+		t.Errorf("quote = %s", got)
+	}
+
+	script, err := GenerateRestoreScript(ScriptPowerShell, []string{"C:/Фото/a.jpg"}, "D:/Trash", ScriptFormat{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(script, []byte("Restore-File 'D:\\Trash\\C\\\xd4\xee\xf2\xee\\a.jpg' 'C:\\\xd4\xee\xf2\xee\\a.jpg'\r\n")) {
+		t.Errorf("restore script is not in Windows-1251 with CRLF:\n%q", script)
+	}
+	if _, err := GenerateScript(ScriptPowerShell, []string{"C:/photo 📷.jpg"}, "", ScriptFormat{}); !errors.Is(err, ErrScriptNotEncodable) {
+		t.Errorf("unencodable path: got %v", err)
+	}
+}
+
+func TestScriptFormats(t *testing.T) {
+	files := []string{"C:/Фото/a.jpg"}
+	tests := []struct {
+		t      ScriptType
+		format ScriptFormat
+		check  func([]byte) bool
+	}{
+		{ScriptPowerShell, ScriptFormat{Encoding: "utf-8-bom"}, func(b []byte) bool {
+			return bytes.HasPrefix(b, []byte("\xef\xbb\xbf# ")) && bytes.Contains(b, []byte("Фото"))
+		}},
+		{ScriptPowerShell, ScriptFormat{Encoding: "utf-16le", LineEndings: "lf"}, func(b []byte) bool {
+			return bytes.HasPrefix(b, []byte("\xff\xfe#\x00")) && !bytes.Contains(b, []byte("\r\x00\n\x00"))
+		}},
+		{ScriptBat, ScriptFormat{Encoding: "CP866", LineEndings: "lf"}, func(b []byte) bool {
+			return bytes.Contains(b, []byte("chcp 866 >nul\n")) && bytes.Contains(b, []byte("C:\\\x94\xae\xe2\xae\\a.jpg"))
+		}},
+	}
+	for _, tt := range tests {
+		script, err := GenerateScript(tt.t, files, "D:/Trash", tt.format)
+		if err != nil {
+			t.Errorf("%s %+v: %v", tt.t, tt.format, err)
+			continue
+		}
+		if !tt.check(script) {
+			t.Errorf("%s %+v: unexpected script:\n%q", tt.t, tt.format, script)
+		}
+	}
+
+	for _, tt := range []struct {
+		t      ScriptType
+		format ScriptFormat
+	}{
+		{ScriptBash, ScriptFormat{Encoding: "utf-16le"}},
+		{ScriptSh, ScriptFormat{Encoding: "utf-8-bom"}},
+		{ScriptBash, ScriptFormat{LineEndings: "crlf"}},
+		{ScriptSh, ScriptFormat{LineEndings: "CRLF"}},
+		{ScriptBat, ScriptFormat{Encoding: "euc-jp"}},
+		{ScriptPowerShell, ScriptFormat{Encoding: "no-such-charset"}},
+		{ScriptPowerShell, ScriptFormat{LineEndings: "cr"}},
+	} {
+		if _, err := GenerateScript(tt.t, files, "", tt.format); !errors.Is(err, ErrScriptFormat) {
+			t.Errorf("%s %+v: got %v, want ErrScriptFormat", tt.t, tt.format, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package imaging
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"image-toolkit/internal/domain"
+)
+
+// FileSearchResult is an indexed file matching a search, with the other files of its
+// exact duplicate group
+type FileSearchResult struct {
+	File       domain.ImageFile
+	Duplicates []domain.ImageFile
+}
+
+// contentKey identifies an exact duplicate group
+type contentKey struct {
+	algo string
+	hash string
+	size int64
+}
+
+// SearchFiles finds indexed files whose path contains query, ignoring case. It returns
+// at most limit files ordered by path, each with the files sharing its content, and the
+// total number of matches. On PostgreSQL the trigram index on lower(path) serves the lookup.
+func SearchFiles(db *gorm.DB, query string, limit int) ([]FileSearchResult, int, error) {
+	match := db.Model(&domain.ImageFile{}).
+		Where(`LOWER(path) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(query))+"%")
+
+	var total int64
+	if err := match.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var files []domain.ImageFile
+	if err := match.Order("path").Limit(limit).Find(&files).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Load the copies of all matches at once rather than one query per file
+	var hashes []string
+	for _, f := range files {
+		if f.Hash != "" {
+			hashes = append(hashes, f.Hash)
+		}
+	}
+	copies := make(map[contentKey][]domain.ImageFile)
+	if len(hashes) > 0 {
+		var candidates []domain.ImageFile
+		if err := db.Where("hash IN ?", hashes).Order("path").Find(&candidates).Error; err != nil {
+			return nil, 0, err
+		}
+		for _, c := range candidates {
+			key := contentKey{c.HashAlgo, c.Hash, c.Size}
+			copies[key] = append(copies[key], c)
+		}
+	}
+
+	results := make([]FileSearchResult, len(files))
+	for i, f := range files {
+		results[i] = FileSearchResult{File: f}
+		if f.Hash == "" {
+			continue
+		}
+		for _, c := range copies[contentKey{f.HashAlgo, f.Hash, f.Size}] {
+			if c.ID != f.ID {
+				results[i].Duplicates = append(results[i].Duplicates, c)
+			}
+		}
+	}
+	return results, int(total), nil
+}
@@ -0,0 +1,43 @@
+package imaging
+
+import (
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestSearchFiles(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	files := []domain.ImageFile{
+		{Path: "/photos/2020/IMG_2034.jpg", Size: 100, Hash: "a", HashAlgo: "sha256"},
+		{Path: "/backup/img_2034.jpg", Size: 100, Hash: "a", HashAlgo: "sha256"},
+		{Path: "/backup/other.jpg", Size: 100, Hash: "a", HashAlgo: "sha256"},
+		{Path: "/photos/2020/IMG_2035.jpg", Size: 200, Hash: "b", HashAlgo: "sha256"},
+		{Path: "/photos/IMGX2034.jpg", Size: 300},
+	}
+	for i := range files {
+		if err := db.Create(&files[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, total, err := SearchFiles(db, "img_2034", 10)
+	if err != nil || total != 2 || len(results) != 2 {
+		t.Fatalf("SearchFiles = %d results of %d, %v; want 2", len(results), total, err)
+	}
+	// Ordered by path; each match lists the other files with its content
+	if r := results[0]; r.File.Path != "/backup/img_2034.jpg" || len(r.Duplicates) != 2 ||
+		r.Duplicates[0].Path != "/backup/other.jpg" || r.Duplicates[1].Path != "/photos/2020/IMG_2034.jpg" {
+		t.Errorf("first result = %+v", r)
+	}
+
+	results, total, err = SearchFiles(db, "2035", 10)
+	if err != nil || total != 1 || len(results[0].Duplicates) != 0 {
+		t.Errorf("SearchFiles(2035) = %+v, %d, %v; want one file without copies", results, total, err)
+	}
+
+	results, total, err = SearchFiles(db, ".jpg", 1)
+	if err != nil || total != 5 || len(results) != 1 {
+		t.Errorf("SearchFiles with limit = %d results of %d, %v; want 1 of 5", len(results), total, err)
+	}
+}
@@ -0,0 +1,110 @@
+package imaging
+
+import (
+	"errors"
+	"path/filepath"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidSelectionAction = errors.New("selection action must be keep, delete or empty")
+
+// LoadSelection returns the files marked in a session, ordered by path
+func LoadSelection(db *gorm.DB, key string) ([]domain.Selection, error) {
+	var rows []domain.Selection
+	err := db.Where("session_key = ?", key).Order("path").Find(&rows).Error
+	return rows, err
+}
+
+// SelectionMarks returns the action of every file marked in a session, by path
+func SelectionMarks(db *gorm.DB, key string) (map[string]string, error) {
+	rows, err := LoadSelection(db, key)
+	if err != nil {
+		return nil, err
+	}
+	marks := make(map[string]string, len(rows))
+	for _, row := range rows {
+		marks[row.Path] = row.Action
+	}
+	return marks, nil
+}
+
+// UpdateSelection stores marks of a session, replacing earlier marks of the same
+// paths. An empty action removes the mark of a path. Nothing is stored if an action
+// is invalid.
+func UpdateSelection(db *gorm.DB, key string, marks []domain.Selection) error {
+	for _, m := range marks {
+		if m.Action != "" && m.Action != domain.SelectionKeep && m.Action != domain.SelectionDelete {
+			return ErrInvalidSelectionAction
+		}
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, m := range marks {
+			path := filepath.ToSlash(m.Path)
+			if m.Action == "" {
+				if err := tx.Where("session_key = ? AND path = ?", key, path).Delete(&domain.Selection{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			row := domain.Selection{SessionKey: key, Path: path, Action: m.Action}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "session_key"}, {Name: "path"}},
+				DoUpdates: clause.AssignmentColumns([]string{"action", "updated_at"}),
+			}).Create(&row).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClearSelection removes the marks of the given paths in a session, or all of its
+// marks if no path is given
+func ClearSelection(db *gorm.DB, key string, paths []string) error {
+	if len(paths) == 0 {
+		return db.Where("session_key = ?", key).Delete(&domain.Selection{}).Error
+	}
+	const dbBatchSize = 500
+	for i := 0; i < len(paths); i += dbBatchSize {
+		end := min(i+dbBatchSize, len(paths))
+		batch := make([]string, 0, end-i)
+		for _, p := range paths[i:end] {
+			batch = append(batch, filepath.ToSlash(p))
+		}
+		if err := db.Where("session_key = ? AND path IN ?", key, batch).Delete(&domain.Selection{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SelectionKeeps returns the files of a duplicate group kept by the stored marks:
+// the files marked keep or, if there are none, all files not marked delete. marked
+// is false when no file of the group is marked; keep is empty when every file is
+// marked delete, which must not remove the whole group.
+func SelectionKeeps(files []domain.ImageFile, marks map[string]string) (keep map[int]bool, marked bool) {
+	keep = make(map[int]bool)
+	for i, f := range files {
+		switch marks[f.Path] {
+		case domain.SelectionKeep:
+			keep[i] = true
+			marked = true
+		case domain.SelectionDelete:
+			marked = true
+		}
+	}
+	if !marked || len(keep) > 0 {
+		return keep, marked
+	}
+	for i, f := range files {
+		if marks[f.Path] != domain.SelectionDelete {
+			keep[i] = true
+		}
+	}
+	return keep, marked
+}
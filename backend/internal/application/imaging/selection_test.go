@@ -0,0 +1,83 @@
+package imaging
+
+import (
+	"errors"
+	"testing"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestSelection(t *testing.T) {
+	db := openTestDB(t, &domain.Selection{})
+
+	if err := UpdateSelection(db, "s1", []domain.Selection{
+		{Path: "/p/a.jpg", Action: domain.SelectionDelete},
+		{Path: "/p/b.jpg", Action: domain.SelectionKeep},
+		{Path: "/p/c.jpg", Action: domain.SelectionDelete},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateSelection(db, "s2", []domain.Selection{{Path: "/p/a.jpg", Action: domain.SelectionKeep}}); err != nil {
+		t.Fatal(err)
+	}
+	// Marks are replaced and removed per path
+	if err := UpdateSelection(db, "s1", []domain.Selection{
+		{Path: "/p/a.jpg", Action: domain.SelectionKeep},
+		{Path: "/p/b.jpg", Action: ""},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateSelection(db, "s1", []domain.Selection{{Path: "/p/d.jpg", Action: "maybe"}}); !errors.Is(err, ErrInvalidSelectionAction) {
+		t.Fatalf("invalid action: got %v", err)
+	}
+
+	marks, err := SelectionMarks(db, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(marks) != 2 || marks["/p/a.jpg"] != domain.SelectionKeep || marks["/p/c.jpg"] != domain.SelectionDelete {
+		t.Fatalf("session s1: got %v", marks)
+	}
+
+	if err := ClearSelection(db, "s1", []string{"/p/c.jpg"}); err != nil {
+		t.Fatal(err)
+	}
+	if marks, _ := SelectionMarks(db, "s1"); len(marks) != 1 {
+		t.Fatalf("after clearing one path: got %v", marks)
+	}
+	if err := ClearSelection(db, "s1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if marks, _ := SelectionMarks(db, "s1"); len(marks) != 0 {
+		t.Fatalf("after clearing the session: got %v", marks)
+	}
+	if marks, _ := SelectionMarks(db, "s2"); marks["/p/a.jpg"] != domain.SelectionKeep {
+		t.Fatalf("other sessions must keep their marks: got %v", marks)
+	}
+}
+
+func TestSelectionKeeps(t *testing.T) {
+	files := []domain.ImageFile{{Path: "/a.jpg"}, {Path: "/b.jpg"}, {Path: "/c.jpg"}}
+	for _, tc := range []struct {
+		name   string
+		marks  map[string]string
+		keep   []int
+		marked bool
+	}{
+		{"unmarked", map[string]string{"/other.jpg": domain.SelectionDelete}, nil, false},
+		{"keep mark wins", map[string]string{"/b.jpg": domain.SelectionKeep, "/c.jpg": domain.SelectionDelete}, []int{1}, true},
+		{"unmarked files are kept", map[string]string{"/a.jpg": domain.SelectionDelete}, []int{1, 2}, true},
+		{"all marked delete", map[string]string{"/a.jpg": "delete", "/b.jpg": "delete", "/c.jpg": "delete"}, nil, true},
+	} {
+		keep, marked := SelectionKeeps(files, tc.marks)
+		if marked != tc.marked || len(keep) != len(tc.keep) {
+			t.Errorf("%s: got keep %v, marked %v", tc.name, keep, marked)
+			continue
+		}
+		for _, i := range tc.keep {
+			if !keep[i] {
+				t.Errorf("%s: file %d not kept: %v", tc.name, i, keep)
+			}
+		}
+	}
+}
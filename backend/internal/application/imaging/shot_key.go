@@ -0,0 +1,106 @@
+package imaging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// shotKey identifies a shot by its capture time to the second, camera model and
+// resolution. Re-encoded copies (e.g. recompressed by a messenger or an export) keep
+// the EXIF of the original, so they share its key while their content hashes differ.
+// Width and height are ordered, so that a copy rotated by its orientation tag matches.
+func shotKey(takenAt time.Time, camera string, width, height int) string {
+	if width > height {
+		width, height = height, width
+	}
+	return fmt.Sprintf("%s|%s|%dx%d", takenAt.UTC().Format("2006-01-02T15:04:05"), strings.ToLower(strings.TrimSpace(camera)), width, height)
+}
+
+// findShotGroups groups the files that carry the same capture time, camera model and
+// resolution in their extracted metadata. Files without a capture time or dimensions
+// are left out. The largest file of each group, likely the least recompressed, comes
+// first and is recommended for keeping; similarity is measured against it as for copies
+// by name. Groups are ordered by their largest file size, descending.
+func findShotGroups(db *gorm.DB, filter DuplicateFilter) ([]domain.DuplicateGroup, error) {
+	var files []domain.ImageFile
+	if err := filter.apply(db).Order("id").Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(files))
+	for i, f := range files {
+		ids[i] = f.ID
+	}
+	metadata := loadMetadata(db, ids, "width", "height", "camera_model", "date_taken")
+
+	type shotFile struct {
+		file domain.ImageFile
+		meta domain.ImageMetadata
+	}
+	byKey := make(map[string][]shotFile)
+	for _, f := range files {
+		m, ok := metadata[f.ID]
+		if !ok || m.DateTaken == nil || m.Width <= 0 || m.Height <= 0 {
+			continue
+		}
+		key := shotKey(*m.DateTaken, m.CameraModel, m.Width, m.Height)
+		byKey[key] = append(byKey[key], shotFile{file: f, meta: m})
+	}
+
+	var groups []domain.DuplicateGroup
+	for key, members := range byKey {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].file.Size != members[j].file.Size {
+				return members[i].file.Size > members[j].file.Size
+			}
+			return members[i].file.Path < members[j].file.Path
+		})
+		group := domain.DuplicateGroup{Hash: key, Size: members[0].file.Size}
+		for _, s := range members {
+			group.Files = append(group.Files, s.file)
+			group.Similarity = append(group.Similarity, copySimilarity(&members[0].file, &s.file))
+			group.Dimensions = append(group.Dimensions, domain.Dimensions{Width: s.meta.Width, Height: s.meta.Height})
+			group.TakenAt = append(group.TakenAt, *s.meta.DateTaken)
+		}
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Size != groups[j].Size {
+			return groups[i].Size > groups[j].Size
+		}
+		return groups[i].Hash < groups[j].Hash
+	})
+	return groups, nil
+}
+
+// FindShotsPaginated finds groups of copies of one shot by EXIF among the files
+// matching the filter in the given order, with pagination
+func FindShotsPaginated(db *gorm.DB, filter DuplicateFilter, order GroupOrder, offset, limit int) ([]domain.DuplicateGroup, int, int, error) {
+	allGroups, err := findShotGroups(db, filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	sortGroups(allGroups, order)
+
+	totalGroups := len(allGroups)
+	totalFiles := 0
+	for _, g := range allGroups {
+		totalFiles += len(g.Files)
+	}
+
+	if offset >= totalGroups {
+		return []domain.DuplicateGroup{}, totalGroups, totalFiles, nil
+	}
+	end := min(offset+limit, totalGroups)
+	return allGroups[offset:end], totalGroups, totalFiles, nil
+}
@@ -0,0 +1,70 @@
+package imaging
+
+import (
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestShotKey(t *testing.T) {
+	taken := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	key := shotKey(taken, "Pixel 8", 4000, 3000)
+	if got := shotKey(taken.Add(300*time.Millisecond), " pixel 8", 3000, 4000); got != key {
+		t.Errorf("rotated copy key %q, want %q", got, key)
+	}
+	if got := shotKey(taken.In(time.FixedZone("UTC+3", 3*3600)), "Pixel 8", 4000, 3000); got != key {
+		t.Errorf("key in another zone %q, want %q", got, key)
+	}
+	if shotKey(taken.Add(time.Second), "Pixel 8", 4000, 3000) == key {
+		t.Error("shots a second apart should differ")
+	}
+	if shotKey(taken, "Pixel 8", 1600, 1200) == key {
+		t.Error("downscaled copies should differ")
+	}
+}
+
+func TestFindShotsPaginated(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.ImageMetadata{})
+	files := []domain.ImageFile{
+		{Path: "/photos/IMG_0001.jpg", Size: 4_000_000, Hash: "a", HashAlgo: "sha256"},
+		{Path: "/whatsapp/IMG-20240601-WA0001.jpg", Size: 900_000, Hash: "b", HashAlgo: "sha256"},
+		{Path: "/photos/IMG_0002.jpg", Size: 3_000_000, Hash: "c", HashAlgo: "sha256"},
+		// Same moment, another camera
+		{Path: "/friend/DSC_0001.jpg", Size: 5_000_000, Hash: "d", HashAlgo: "sha256"},
+		// No capture time
+		{Path: "/scans/scan.jpg", Size: 1_000_000, Hash: "e", HashAlgo: "sha256"},
+		{Path: "/scans/scan (1).jpg", Size: 1_000_000, Hash: "f", HashAlgo: "sha256"},
+	}
+	if err := db.Create(&files).Error; err != nil {
+		t.Fatal(err)
+	}
+	taken := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := taken.Add(time.Minute)
+	metadata := []domain.ImageMetadata{
+		{ImageFileID: files[0].ID, Width: 4000, Height: 3000, CameraModel: "Pixel 8", DateTaken: &taken},
+		{ImageFileID: files[1].ID, Width: 4000, Height: 3000, CameraModel: "Pixel 8", DateTaken: &taken},
+		{ImageFileID: files[2].ID, Width: 4000, Height: 3000, CameraModel: "Pixel 8", DateTaken: &later},
+		{ImageFileID: files[3].ID, Width: 4000, Height: 3000, CameraModel: "NIKON D750", DateTaken: &taken},
+		{ImageFileID: files[4].ID, Width: 2000, Height: 1000},
+		{ImageFileID: files[5].ID, Width: 2000, Height: 1000},
+	}
+	if err := db.Create(&metadata).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	groups, totalGroups, totalFiles, err := FindShotsPaginated(db, DuplicateFilter{}, OrderDefault, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalGroups != 1 || totalFiles != 2 || len(groups) != 1 {
+		t.Fatalf("found %d groups of %d files, want 1 of 2: %+v", totalGroups, totalFiles, groups)
+	}
+	group := groups[0]
+	if group.Files[0].Path != "/photos/IMG_0001.jpg" || group.Files[1].Path != "/whatsapp/IMG-20240601-WA0001.jpg" {
+		t.Errorf("group files %s, %s: the largest file should come first", group.Files[0].Path, group.Files[1].Path)
+	}
+	if group.Recommended != 0 || group.Size != 4_000_000 || len(group.Dimensions) != 2 || !group.TakenAt[1].Equal(taken) {
+		t.Errorf("group recommended %d, size %d, dimensions %v, taken at %v", group.Recommended, group.Size, group.Dimensions, group.TakenAt)
+	}
+}
@@ -0,0 +1,241 @@
+package imaging
+
+import (
+	"sort"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultSimilarityThreshold is the maximum Hamming distance between two perceptual
+	// hashes (out of 64 bits) for the images to be considered near-duplicates
+	DefaultSimilarityThreshold = 10
+	// MaxSimilarityThreshold is the largest meaningful threshold (hash length in bits)
+	MaxSimilarityThreshold = 64
+)
+
+// similarityScore converts a Hamming distance into a score where 1 means identical hashes
+func similarityScore(distance int) float64 {
+	return 1 - float64(distance)/MaxSimilarityThreshold
+}
+
+// bkNode is a node of a BK-tree indexing perceptual hashes by Hamming distance
+type bkNode struct {
+	hash     uint64
+	ids      []int // indexes of files sharing this exact hash
+	children map[int]*bkNode
+}
+
+// bkTree allows querying all hashes within a Hamming radius without comparing every pair
+type bkTree struct {
+	root *bkNode
+}
+
+// insert adds a hash with its file index to the tree
+func (t *bkTree) insert(hash uint64, id int) {
+	if t.root == nil {
+		t.root = &bkNode{hash: hash, ids: []int{id}}
+		return
+	}
+	node := t.root
+	for {
+		d := hammingDistance(hash, node.hash)
+		if d == 0 {
+			node.ids = append(node.ids, id)
+			return
+		}
+		child, ok := node.children[d]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[d] = &bkNode{hash: hash, ids: []int{id}}
+			return
+		}
+		node = child
+	}
+}
+
+// search returns indexes of all files whose hash is within maxDistance of hash
+func (t *bkTree) search(hash uint64, maxDistance int) []int {
+	if t.root == nil {
+		return nil
+	}
+	var found []int
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := hammingDistance(hash, node.hash)
+		if d <= maxDistance {
+			found = append(found, node.ids...)
+		}
+		for cd, child := range node.children {
+			if cd >= d-maxDistance && cd <= d+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return found
+}
+
+// unionFind groups file indexes transitively
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+	}
+	return uf
+}
+
+func (uf *unionFind) find(x int) int {
+	for uf.parent[x] != x {
+		uf.parent[x] = uf.parent[uf.parent[x]]
+		x = uf.parent[x]
+	}
+	return x
+}
+
+func (uf *unionFind) union(a, b int) {
+	ra, rb := uf.find(a), uf.find(b)
+	if ra != rb {
+		uf.parent[rb] = ra
+	}
+}
+
+// orientedDistance returns the Hamming distance between hash and the closest of a
+// reference hash and the hashes of its rotations and mirror images
+func orientedDistance(reference uint64, xforms []uint64, hash uint64) int {
+	distance := hammingDistance(reference, hash)
+	for _, h := range xforms {
+		distance = min(distance, hammingDistance(h, hash))
+	}
+	return distance
+}
+
+// fileHashAlgorithm returns the algorithm a stored perceptual hash was computed with.
+// Rows hashed before the algorithm was recorded always used the DCT pHash.
+func fileHashAlgorithm(f *domain.ImageFile) PerceptualHashAlgorithm {
+	if f.PHashAlgo == "" {
+		return HashAlgoDCT
+	}
+	return PerceptualHashAlgorithm(f.PHashAlgo)
+}
+
+// findSimilarGroups clusters all files with a perceptual hash into near-duplicate groups.
+// Two files end up in the same group when a chain of files connects them where every
+// step is within threshold bits and both hashes come from the same algorithm. A file
+// with hashes of its rotations and mirror images also matches files within threshold
+// of any of them. Groups are ordered by their largest file size, descending.
+func findSimilarGroups(db *gorm.DB, filter DuplicateFilter, threshold int) ([]domain.DuplicateGroup, error) {
+	var files []domain.ImageFile
+	if err := filter.apply(db).Where("p_hash <> ''").Order("id").Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	// Hashes from different algorithms are not comparable, so each algorithm gets its own tree
+	hashes := make([]uint64, len(files))
+	xforms := make([][]uint64, len(files))
+	valid := make([]bool, len(files))
+	trees := make(map[PerceptualHashAlgorithm]*bkTree)
+	for i, f := range files {
+		h, err := parsePerceptualHash(f.PHash)
+		if err != nil {
+			continue
+		}
+		hashes[i] = h
+		xforms[i] = parsePerceptualHashes(f.PHashXform)
+		valid[i] = true
+		algo := fileHashAlgorithm(&f)
+		if trees[algo] == nil {
+			trees[algo] = &bkTree{}
+		}
+		trees[algo].insert(h, i)
+	}
+
+	uf := newUnionFind(len(files))
+	for i := range files {
+		if !valid[i] {
+			continue
+		}
+		tree := trees[fileHashAlgorithm(&files[i])]
+		for _, j := range tree.search(hashes[i], threshold) {
+			uf.union(i, j)
+		}
+		for _, h := range xforms[i] {
+			for _, j := range tree.search(h, threshold) {
+				uf.union(i, j)
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range files {
+		if !valid[i] {
+			continue
+		}
+		root := uf.find(i)
+		members[root] = append(members[root], i)
+	}
+
+	var groups []domain.DuplicateGroup
+	for root, idxs := range members {
+		if len(idxs) < 2 {
+			continue
+		}
+		group := domain.DuplicateGroup{Hash: files[root].PHash}
+		reference := idxs[0]
+		for _, idx := range idxs {
+			group.Files = append(group.Files, files[idx])
+			distance := orientedDistance(hashes[reference], xforms[reference], hashes[idx])
+			group.Similarity = append(group.Similarity, similarityScore(distance))
+			if files[idx].Size > group.Size {
+				group.Size = files[idx].Size
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Size != groups[j].Size {
+			return groups[i].Size > groups[j].Size
+		}
+		return groups[i].Hash < groups[j].Hash
+	})
+
+	return groups, nil
+}
+
+// FindSimilarPaginated finds near-duplicate groups by perceptual hash among the files
+// matching the filter in the given order, with pagination
+func FindSimilarPaginated(db *gorm.DB, filter DuplicateFilter, order GroupOrder, offset, limit, threshold int) ([]domain.DuplicateGroup, int, int, error) {
+	allGroups, err := findSimilarGroups(db, filter, threshold)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	sortGroups(allGroups, order)
+
+	totalGroups := len(allGroups)
+	totalFiles := 0
+	for _, g := range allGroups {
+		totalFiles += len(g.Files)
+	}
+
+	if offset >= totalGroups {
+		return []domain.DuplicateGroup{}, totalGroups, totalFiles, nil
+	}
+
+	end := offset + limit
+	if end > totalGroups {
+		end = totalGroups
+	}
+
+	return allGroups[offset:end], totalGroups, totalFiles, nil
+}
@@ -0,0 +1,47 @@
+package imaging
+
+import (
+	"context"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// isDeferred reports whether a row was recorded by a size-prefiltered scan and has
+// not been content-hashed yet
+func isDeferred(f *domain.ImageFile) bool {
+	return f.Hash == "" && f.PrefixHash == ""
+}
+
+// hashSizeCollisions completes the second pass of size-prefiltered scanning: rows recorded
+// without a content hash are hashed only when another file of the same size exists.
+// Files with a unique size cannot have exact duplicates and are never read.
+// Returns the number of rows hashed.
+func hashSizeCollisions(ctx context.Context, db *gorm.DB, progressChan chan<- string, opts ScanOptions) int {
+	collidingSizes := db.Model(&domain.ImageFile{}).
+		Select("size").
+		Group("size").
+		Having("count(*) > 1")
+
+	hashed := 0
+	const batchSize = 500
+	var lastID uint
+	for {
+		if ctx.Err() != nil {
+			return hashed
+		}
+		var batch []domain.ImageFile
+		if err := db.Where("hash = '' AND prefix_hash = '' AND size IN (?) AND id > ?", collidingSizes, lastID).
+			Order("id").Limit(batchSize).Find(&batch).Error; err != nil {
+			progressChan <- "Error loading files with colliding sizes: " + err.Error()
+			return hashed
+		}
+		if len(batch) == 0 {
+			return hashed
+		}
+		lastID = batch[len(batch)-1].ID
+
+		hashed += rehashRows(ctx, db, batch, progressChan, opts)
+	}
+}
@@ -0,0 +1,271 @@
+package imaging
+
+import (
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/database"
+)
+
+const (
+	// statsTopCount bounds the directory, extension and group lists of DuplicateStats
+	statsTopCount = 20
+	// statsMonthCount is the number of most recent months in DuplicateStats
+	statsMonthCount = 24
+)
+
+// DuplicateStats summarises where exact duplicates waste space. Everything is computed
+// by SQL aggregation, without loading the indexed files.
+type DuplicateStats struct {
+	IndexedFiles     int64
+	IndexedBytes     int64
+	DuplicateGroups  int64
+	DuplicateFiles   int64 // files in duplicate groups, including the copy to keep
+	DuplicateBytes   int64
+	ReclaimableBytes int64        // freed by keeping one file per group
+	Directories      []ShareStats // directories holding the most duplicate bytes
+	Extensions       []ShareStats // extensions of the most duplicate bytes, "" for none
+	LargestGroups    []GroupStats // groups freeing the most space
+	Months           []MonthStats // oldest first
+}
+
+// ShareStats is the part of the duplicate files under one key
+type ShareStats struct {
+	Key   string
+	Files int64
+	Bytes int64
+}
+
+// GroupStats describes one exact duplicate group
+type GroupStats struct {
+	Hash             string
+	Size             int64
+	Files            int64
+	ReclaimableBytes int64
+	Path             string // first path of the group
+}
+
+// MonthStats counts the duplicate files indexed and the files moved to the trash
+// folder (and not restored) in one month, "YYYY-MM"
+type MonthStats struct {
+	Month          string
+	DuplicateFiles int64
+	DuplicateBytes int64
+	DeletedFiles   int64
+	DeletedBytes   int64
+}
+
+// BuildDuplicateStats aggregates the exact duplicate groups among the files matching
+// the filter
+func BuildDuplicateStats(db *gorm.DB, filter DuplicateFilter) (*DuplicateStats, error) {
+	stats := &DuplicateStats{}
+
+	var indexed struct {
+		Files int64
+		Bytes int64
+	}
+	if err := filter.apply(db.Model(&domain.ImageFile{})).
+		Select("COUNT(*) AS files, COALESCE(SUM(size), 0) AS bytes").
+		Scan(&indexed).Error; err != nil {
+		return nil, err
+	}
+	stats.IndexedFiles, stats.IndexedBytes = indexed.Files, indexed.Bytes
+
+	groups := duplicateGroupsQuery(db, filter)
+	totals, err := duplicateTotals(db, groups)
+	if err != nil {
+		return nil, err
+	}
+	stats.DuplicateGroups, stats.DuplicateFiles = totals.Groups, totals.Files
+	stats.DuplicateBytes, stats.ReclaimableBytes = totals.Bytes, totals.ReclaimableBytes
+
+	var largest []struct {
+		GHash  string
+		GSize  int64
+		GFiles int64
+		GPath  string
+	}
+	if err := db.Table("(?) AS g", groups).
+		Select("g_hash, g_size, g_files, g_path").
+		Order("g_size * (g_files - 1) DESC, g_hash").
+		Limit(statsTopCount).
+		Scan(&largest).Error; err != nil {
+		return nil, err
+	}
+	for _, g := range largest {
+		stats.LargestGroups = append(stats.LargestGroups, GroupStats{
+			Hash: g.GHash, Size: g.GSize, Files: g.GFiles, ReclaimableBytes: g.GSize * (g.GFiles - 1), Path: g.GPath,
+		})
+	}
+
+	// Files of the duplicate groups
+	members := func() *gorm.DB {
+		return filter.apply(db.Model(&domain.ImageFile{}).
+			Joins("JOIN (?) AS g ON image_files.hash_algo = g.g_algo AND image_files.hash = g.g_hash AND image_files.size = g.g_size", groups))
+	}
+
+	// rtrim drops the trailing characters other than '/', leaving the directory
+	// with a trailing slash; the same trick on '.' finds the extension
+	if stats.Directories, err = shareStats(members(), "rtrim(path, replace(path, '/', ''))", normalizeStatsDir); err != nil {
+		return nil, err
+	}
+	if stats.Extensions, err = shareStats(members(), "LOWER(replace(path, rtrim(path, replace(path, '.', '')), ''))", normalizeStatsExtension); err != nil {
+		return nil, err
+	}
+
+	if stats.Months, err = monthStats(db, members()); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// DuplicateTotals counts the exact duplicate groups among the files matching a filter
+type DuplicateTotals struct {
+	Groups           int64 `gorm:"column:group_count" json:"groups"`
+	Files            int64 `json:"files"` // including the copy to keep
+	Bytes            int64 `json:"bytes"`
+	ReclaimableBytes int64 `json:"reclaimableBytes"` // freed by keeping one file per group
+}
+
+// CountDuplicates returns the totals of BuildDuplicateStats alone
+func CountDuplicates(db *gorm.DB, filter DuplicateFilter) (DuplicateTotals, error) {
+	return duplicateTotals(db, duplicateGroupsQuery(db, filter))
+}
+
+// duplicateGroupsQuery selects the exact duplicate groups among the files matching
+// the filter. The columns of the groups are prefixed so that the filter conditions
+// on image_files stay unambiguous in joins.
+func duplicateGroupsQuery(db *gorm.DB, filter DuplicateFilter) *gorm.DB {
+	return filter.applyGroups(db.Model(&domain.ImageFile{})).
+		Select("hash_algo AS g_algo, hash AS g_hash, size AS g_size, " + filter.countExpr() + " AS g_files, MIN(path) AS g_path").
+		Where("hash <> ''").
+		Group("hash_algo, hash, size").
+		Having(filter.countExpr() + " > 1")
+}
+
+// duplicateTotals sums the groups of duplicateGroupsQuery
+func duplicateTotals(db, groups *gorm.DB) (DuplicateTotals, error) {
+	var totals DuplicateTotals
+	err := db.Table("(?) AS g", groups).
+		Select("COUNT(*) AS group_count, COALESCE(SUM(g_files), 0) AS files, COALESCE(SUM(g_size * g_files), 0) AS bytes, COALESCE(SUM(g_size * (g_files - 1)), 0) AS reclaimable_bytes").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// shareStats groups duplicate files by a key expression. Keys are normalized in Go,
+// which may merge groups, before the largest ones are kept.
+func shareStats(members *gorm.DB, keyExpr string, normalize func(string) string) ([]ShareStats, error) {
+	var rows []struct {
+		ShareKey string
+		Files    int64
+		Bytes    int64
+	}
+	if err := members.
+		Select("COALESCE(" + keyExpr + ", '') AS share_key, COUNT(*) AS files, COALESCE(SUM(size), 0) AS bytes").
+		Group(keyExpr).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]*ShareStats)
+	var shares []ShareStats
+	for _, r := range rows {
+		key := normalize(r.ShareKey)
+		if s, ok := merged[key]; ok {
+			s.Files += r.Files
+			s.Bytes += r.Bytes
+			continue
+		}
+		merged[key] = &ShareStats{Key: key, Files: r.Files, Bytes: r.Bytes}
+	}
+	for _, s := range merged {
+		shares = append(shares, *s)
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Bytes != shares[j].Bytes {
+			return shares[i].Bytes > shares[j].Bytes
+		}
+		return shares[i].Key < shares[j].Key
+	})
+	if len(shares) > statsTopCount {
+		shares = shares[:statsTopCount]
+	}
+	return shares, nil
+}
+
+// normalizeStatsDir drops the trailing slash left by the directory expression
+func normalizeStatsDir(dir string) string {
+	if dir == "/" {
+		return dir
+	}
+	return strings.TrimSuffix(dir, "/")
+}
+
+// normalizeStatsExtension turns the text after the last dot of a path into an
+// extension. Text with a slash means the file name itself has no dot.
+func normalizeStatsExtension(ext string) string {
+	if ext == "" || strings.Contains(ext, "/") {
+		return ""
+	}
+	return "." + ext
+}
+
+// monthStats counts the duplicate files by the month they were indexed, and the
+// files still in the trash folder by the month they were deleted
+func monthStats(db, members *gorm.DB) ([]MonthStats, error) {
+	type monthRow struct {
+		Month string
+		Files int64
+		Bytes int64
+	}
+
+	indexedMonth := database.MonthExpr(db, "image_files.created_at")
+	var indexed []monthRow
+	if err := members.
+		Select("COALESCE(" + indexedMonth + ", '') AS month, COUNT(*) AS files, COALESCE(SUM(size), 0) AS bytes").
+		Group(indexedMonth).
+		Scan(&indexed).Error; err != nil {
+		return nil, err
+	}
+
+	deletedMonth := database.MonthExpr(db, "deleted_at")
+	var deleted []monthRow
+	if err := db.Model(&domain.Deletion{}).
+		Select("COALESCE(" + deletedMonth + ", '') AS month, COUNT(*) AS files, COALESCE(SUM(size), 0) AS bytes").
+		Where("restored_at IS NULL").
+		Group(deletedMonth).
+		Scan(&deleted).Error; err != nil {
+		return nil, err
+	}
+
+	byMonth := make(map[string]*MonthStats)
+	month := func(name string) *MonthStats {
+		if m, ok := byMonth[name]; ok {
+			return m
+		}
+		m := &MonthStats{Month: name}
+		byMonth[name] = m
+		return m
+	}
+	for _, r := range indexed {
+		m := month(r.Month)
+		m.DuplicateFiles, m.DuplicateBytes = r.Files, r.Bytes
+	}
+	for _, r := range deleted {
+		m := month(r.Month)
+		m.DeletedFiles, m.DeletedBytes = r.Files, r.Bytes
+	}
+
+	months := make([]MonthStats, 0, len(byMonth))
+	for _, m := range byMonth {
+		months = append(months, *m)
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Month < months[j].Month })
+	if len(months) > statsMonthCount {
+		months = months[len(months)-statsMonthCount:]
+	}
+	return months, nil
+}
@@ -0,0 +1,90 @@
+package imaging
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestNormalizeStatsKeys(t *testing.T) {
+	for in, want := range map[string]string{"/photos/2020/": "/photos/2020", "/": "/"} {
+		if got := normalizeStatsDir(in); got != want {
+			t.Errorf("normalizeStatsDir(%q) = %q, want %q", in, got, want)
+		}
+	}
+	for in, want := range map[string]string{"jpg": ".jpg", "": "", "d/readme": "", "/photos/readme": ""} {
+		if got := normalizeStatsExtension(in); got != want {
+			t.Errorf("normalizeStatsExtension(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuildDuplicateStats(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.Deletion{})
+	march := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 2, 12, 0, 0, 0, time.UTC)
+	files := []domain.ImageFile{
+		{Path: "/photos/a/1.jpg", Size: 100, Hash: "x", HashAlgo: "sha256", CreatedAt: march},
+		{Path: "/photos/b/1.JPG", Size: 100, Hash: "x", HashAlgo: "sha256", CreatedAt: march},
+		{Path: "/photos/b/2.jpg", Size: 100, Hash: "x", HashAlgo: "sha256", CreatedAt: april},
+		{Path: "/photos/a/big.png", Size: 1000, Hash: "y", HashAlgo: "sha256", CreatedAt: april},
+		{Path: "/photos.old/big", Size: 1000, Hash: "y", HashAlgo: "sha256", CreatedAt: april},
+		{Path: "/photos/a/single.jpg", Size: 5000, Hash: "z", HashAlgo: "sha256", CreatedAt: april},
+	}
+	for i := range files {
+		if err := db.Create(&files[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	restored := april
+	for _, d := range []domain.Deletion{
+		{BatchID: "1", OriginalPath: "/photos/c.jpg", TrashPath: "/trash/c.jpg", Size: 300, DeletedAt: april},
+		{BatchID: "1", OriginalPath: "/photos/d.jpg", TrashPath: "/trash/d.jpg", Size: 300, DeletedAt: april, RestoredAt: &restored},
+	} {
+		if err := db.Create(&d).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := BuildDuplicateStats(db, DuplicateFilter{})
+	if err != nil {
+		t.Fatalf("BuildDuplicateStats: %v", err)
+	}
+	if stats.IndexedFiles != 6 || stats.IndexedBytes != 7300 || stats.DuplicateGroups != 2 ||
+		stats.DuplicateFiles != 5 || stats.DuplicateBytes != 2300 || stats.ReclaimableBytes != 1200 {
+		t.Errorf("totals = %+v", stats)
+	}
+
+	wantDirs := []ShareStats{{"/photos/a", 2, 1100}, {"/photos.old", 1, 1000}, {"/photos/b", 2, 200}}
+	if !reflect.DeepEqual(stats.Directories, wantDirs) {
+		t.Errorf("Directories = %+v, want %+v", stats.Directories, wantDirs)
+	}
+	// Extensions ignore case; a dotted directory does not make an extension
+	wantExts := []ShareStats{{"", 1, 1000}, {".png", 1, 1000}, {".jpg", 3, 300}}
+	if !reflect.DeepEqual(stats.Extensions, wantExts) {
+		t.Errorf("Extensions = %+v, want %+v", stats.Extensions, wantExts)
+	}
+	if len(stats.LargestGroups) != 2 || stats.LargestGroups[0].Hash != "y" || stats.LargestGroups[0].ReclaimableBytes != 1000 ||
+		stats.LargestGroups[0].Path != "/photos.old/big" {
+		t.Errorf("LargestGroups = %+v", stats.LargestGroups)
+	}
+	wantMonths := []MonthStats{
+		{Month: "2026-03", DuplicateFiles: 2, DuplicateBytes: 200},
+		{Month: "2026-04", DuplicateFiles: 3, DuplicateBytes: 2100, DeletedFiles: 1, DeletedBytes: 300},
+	}
+	if !reflect.DeepEqual(stats.Months, wantMonths) {
+		t.Errorf("Months = %+v, want %+v", stats.Months, wantMonths)
+	}
+
+	filtered, err := BuildDuplicateStats(db, DuplicateFilter{Dir: "/photos"})
+	if err != nil || filtered.DuplicateGroups != 1 || filtered.ReclaimableBytes != 200 {
+		t.Errorf("filtered stats = %+v, %v", filtered, err)
+	}
+
+	totals, err := CountDuplicates(db, DuplicateFilter{})
+	if want := (DuplicateTotals{Groups: 2, Files: 5, Bytes: 2300, ReclaimableBytes: 1200}); err != nil || totals != want {
+		t.Errorf("CountDuplicates = %+v, %v, want %+v", totals, err, want)
+	}
+}
@@ -0,0 +1,13 @@
+package imaging
+
+import "errors"
+
+var ErrSystemTrashUnsupported = errors.New("system trash is not supported on this platform")
+
+// MoveToSystemTrash moves the file at path to the trash of the operating system
+// (freedesktop.org Trash on Linux, ~/.Trash on macOS, the Recycle Bin on Windows),
+// so it can be restored with the usual desktop tools. The trash belongs to the user
+// the server runs as.
+func MoveToSystemTrash(path string) error {
+	return moveToSystemTrash(path)
+}
@@ -0,0 +1,28 @@
+package imaging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// moveToSystemTrash moves the file to ~/.Trash, adding a timestamp on name clashes
+// like Finder does. Files on other volumes cannot be renamed there and fail.
+func moveToSystemTrash(path string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	trash := filepath.Join(home, ".Trash")
+	base := filepath.Base(path)
+	dest := filepath.Join(trash, base)
+	if _, err := os.Lstat(dest); err == nil {
+		ext := filepath.Ext(base)
+		dest = filepath.Join(trash, strings.TrimSuffix(base, ext)+" "+time.Now().Format("15.04.05.000")+ext)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.Rename(path, dest)
+}
@@ -0,0 +1,147 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// moveToSystemTrash implements the freedesktop.org Trash specification: the file
+// goes to the home trash when it is on the same filesystem, otherwise to the
+// trash at the top of its mount ($topdir/.Trash/$uid or $topdir/.Trash-$uid).
+// A .trashinfo file records the original path and deletion date for restoring.
+func moveToSystemTrash(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	dev := deviceOf(info)
+
+	trash, topDir, err := trashDirFor(path, dev)
+	if err != nil {
+		return err
+	}
+	filesDir := filepath.Join(trash, "files")
+	infoDir := filepath.Join(trash, "info")
+	for _, dir := range []string{filesDir, infoDir} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+	}
+
+	// Paths in a top directory trash are stored relative to the mount point
+	recorded := path
+	if topDir != "" {
+		if rel, err := filepath.Rel(topDir, path); err == nil {
+			recorded = rel
+		}
+	}
+
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = stem + "." + strconv.Itoa(i) + ext
+		}
+		// The info file is created exclusively, which reserves the name in files/
+		infoPath := filepath.Join(infoDir, name+".trashinfo")
+		f, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(f, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+			trashInfoEscape(recorded), time.Now().Format("2006-01-02T15:04:05"))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = os.Rename(path, filepath.Join(filesDir, name))
+		}
+		if err != nil {
+			os.Remove(infoPath)
+		}
+		return err
+	}
+}
+
+// trashDirFor returns the trash directory for a file on device dev, and the mount
+// point when it is a top directory trash
+func trashDirFor(path string, dev uint64) (trash, topDir string, err error) {
+	home := os.Getenv("XDG_DATA_HOME")
+	if home == "" {
+		userHome, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", err
+		}
+		home = filepath.Join(userHome, ".local", "share")
+	}
+	homeTrash := filepath.Join(home, "Trash")
+	if err := os.MkdirAll(homeTrash, 0o700); err == nil {
+		if info, err := os.Stat(homeTrash); err == nil && deviceOf(info) == dev {
+			return homeTrash, "", nil
+		}
+	}
+
+	topDir = mountPoint(path, dev)
+	uid := strconv.Itoa(os.Getuid())
+	// An administrator-provided .Trash must be a sticky directory, not a symlink
+	if info, err := os.Lstat(filepath.Join(topDir, ".Trash")); err == nil &&
+		info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		trash = filepath.Join(topDir, ".Trash", uid)
+		if err := os.MkdirAll(trash, 0o700); err == nil {
+			return trash, topDir, nil
+		}
+	}
+	trash = filepath.Join(topDir, ".Trash-"+uid)
+	if info, err := os.Lstat(trash); err == nil && (!info.IsDir() || info.Mode()&os.ModeSymlink != 0) {
+		return "", "", fmt.Errorf("%w: %s is not a directory", ErrSystemTrashUnsupported, trash)
+	}
+	return trash, topDir, nil
+}
+
+// mountPoint returns the topmost ancestor of path on device dev
+func mountPoint(path string, dev uint64) string {
+	dir := filepath.Dir(path)
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		info, err := os.Stat(parent)
+		if err != nil || deviceOf(info) != dev {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+func deviceOf(info os.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Dev)
+	}
+	return 0
+}
+
+// trashInfoEscape percent-encodes a path for the Path key of a .trashinfo file
+func trashInfoEscape(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
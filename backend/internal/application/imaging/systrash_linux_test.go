@@ -0,0 +1,41 @@
+package imaging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMoveToSystemTrash(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	trash := filepath.Join(dir, "data", "Trash")
+
+	for i, want := range []string{"a b.jpg", "a b.2.jpg"} {
+		path := filepath.Join(dir, "a b.jpg")
+		if err := os.WriteFile(path, []byte{byte(i)}, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := MoveToSystemTrash(path); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("file still in place: %v", err)
+		}
+		if data, err := os.ReadFile(filepath.Join(trash, "files", want)); err != nil || len(data) != 1 || data[0] != byte(i) {
+			t.Errorf("trashed file %s: %v, %v", want, data, err)
+		}
+		info, err := os.ReadFile(filepath.Join(trash, "info", want+".trashinfo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(info), "[Trash Info]\nPath="+trashInfoEscape(path)+"\nDeletionDate=") {
+			t.Errorf("unexpected trashinfo:\n%s", info)
+		}
+	}
+
+	if got := trashInfoEscape("/photos/a b/%.jpg"); got != "/photos/a%20b/%25.jpg" {
+		t.Errorf("trashInfoEscape = %q", got)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !(windows && (amd64 || arm64))
+
+package imaging
+
+func moveToSystemTrash(path string) error {
+	return ErrSystemTrashUnsupported
+}
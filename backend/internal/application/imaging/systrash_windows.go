@@ -0,0 +1,66 @@
+//go:build windows && (amd64 || arm64)
+
+package imaging
+
+import (
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var procSHFileOperationW = syscall.NewLazyDLL("shell32.dll").NewProc("SHFileOperationW")
+
+const (
+	foDelete          = 0x0003
+	fofSilent         = 0x0004
+	fofNoConfirmation = 0x0010
+	fofAllowUndo      = 0x0040
+	fofNoErrorUI      = 0x0400
+
+	maxPath = 260
+)
+
+// shFileOpStruct is SHFILEOPSTRUCTW, which is naturally aligned on 64-bit Windows
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted int32
+	hNameMappings         uintptr
+	lpszProgressTitle     *uint16
+}
+
+// moveToSystemTrash sends the file to the Recycle Bin through SHFileOperationW
+func moveToSystemTrash(path string) error {
+	path, err := filepath.Abs(filepath.FromSlash(path))
+	if err != nil {
+		return err
+	}
+	// SHFileOperationW takes neither \\?\ paths nor paths exceeding MAX_PATH
+	if len(path) >= maxPath {
+		return fmt.Errorf("%w: %s exceeds MAX_PATH", ErrSystemTrashUnsupported, path)
+	}
+	// pFrom is a list of names terminated by an extra NUL
+	from, err := syscall.UTF16FromString(path)
+	if err != nil {
+		return err
+	}
+	from = append(from, 0)
+
+	op := shFileOpStruct{
+		wFunc:  foDelete,
+		pFrom:  &from[0],
+		fFlags: fofAllowUndo | fofNoConfirmation | fofSilent | fofNoErrorUI,
+	}
+	ret, _, _ := procSHFileOperationW.Call(uintptr(unsafe.Pointer(&op)))
+	if ret != 0 {
+		return fmt.Errorf("SHFileOperationW failed with code %#x", ret)
+	}
+	if op.fAnyOperationsAborted != 0 {
+		return fmt.Errorf("moving %s to the Recycle Bin was aborted", path)
+	}
+	return nil
+}
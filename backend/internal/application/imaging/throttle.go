@@ -0,0 +1,86 @@
+package imaging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// throttleBurst is how far reads may run ahead of the rate, so short pauses do not
+// have to be made up for byte by byte
+const throttleBurst = 250 * time.Millisecond
+
+// Throttle limits how fast scans read file content, across all hashing workers, so a
+// rescan does not saturate the disk or network of a NAS. A nil Throttle does not limit.
+type Throttle struct {
+	bytesPerSecond float64
+	mu             sync.Mutex
+	next           time.Time // when the bytes read so far are paid for
+}
+
+// NewThrottle returns a throttle reading at most bytesPerSecond, nil for no limit
+func NewThrottle(bytesPerSecond int64) *Throttle {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &Throttle{bytesPerSecond: float64(bytesPerSecond)}
+}
+
+// ParseRate parses a read rate such as "50MB/s" or "1.5 GB"; the unit is that of
+// ParseSize and the "/s" suffix is optional
+func ParseRate(s string) (int64, error) {
+	value := strings.TrimSpace(s)
+	if lower := strings.ToLower(value); strings.HasSuffix(lower, "/s") {
+		value = value[:len(value)-2]
+	}
+	n, err := ParseSize(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q (expected e.g. 50MB/s)", s)
+	}
+	return n, nil
+}
+
+// wait blocks until n more bytes may be read
+func (t *Throttle) wait(n int64) {
+	if t == nil || n <= 0 {
+		return
+	}
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now.Add(-throttleBurst)) {
+		t.next = now.Add(-throttleBurst)
+	}
+	t.next = t.next.Add(time.Duration(float64(n) / t.bytesPerSecond * float64(time.Second)))
+	delay := t.next.Sub(now)
+	t.mu.Unlock()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// reader returns r reading at the rate of t
+func (t *Throttle) reader(r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+	return &throttledReader{r: r, t: t}
+}
+
+type throttledReader struct {
+	r io.Reader
+	t *Throttle
+}
+
+// throttleChunk bounds a single read, so one large read does not wait for seconds
+const throttleChunk = 256 * 1024
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleChunk {
+		p = p[:throttleChunk]
+	}
+	n, err := r.r.Read(p)
+	r.t.wait(int64(n))
+	return n, err
+}
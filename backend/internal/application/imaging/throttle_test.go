@@ -0,0 +1,43 @@
+package imaging
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"50MB/s", 50 << 20},
+		{"1.5 GB/S", 3 << 29},
+		{"512k", 512 << 10},
+	}
+	for _, tt := range tests {
+		if got, err := ParseRate(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseRate(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseRate("fast"); err == nil {
+		t.Error("ParseRate accepted an invalid rate")
+	}
+}
+
+func TestThrottleReader(t *testing.T) {
+	if NewThrottle(0) != nil {
+		t.Fatal("a zero rate should not throttle")
+	}
+	throttle := NewThrottle(4 << 20)
+	start := time.Now()
+	n, err := io.Copy(io.Discard, throttle.reader(bytes.NewReader(make([]byte, 2<<20))))
+	if err != nil || n != 2<<20 {
+		t.Fatalf("copied %d bytes: %v", n, err)
+	}
+	// 2MB at 4MB/s take half a second, less the burst allowance
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond-throttleBurst-50*time.Millisecond {
+		t.Errorf("read 2MB at 4MB/s in %v", elapsed)
+	}
+}
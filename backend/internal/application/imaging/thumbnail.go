@@ -2,49 +2,120 @@ package imaging
 
 import (
 	"bytes"
+	"container/list"
 	"encoding/base64"
 	"fmt"
-	"image"
 	"image/jpeg"
-	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 
 	"github.com/deepteams/webp"
 	"github.com/disintegration/imaging"
+
+	"image-toolkit/internal/infrastructure/imagecodec"
 )
 
 const (
 	maxThumbnailSize = 320
 )
 
-// ThumbnailCache stores generated thumbnails in memory
+// ThumbnailCache stores generated thumbnails in memory, evicting the least recently
+// used ones once it holds more than maxEntries thumbnails or maxBytes of data URLs
 type ThumbnailCache struct {
-	cache map[string]string
-	mu    sync.RWMutex
+	maxEntries int
+	maxBytes   int64
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // front is the most recently used
+	bytes      int64
+	hits       int64
+	misses     int64
+	evictions  int64
+}
+
+// thumbnailEntry is an element of the LRU list of ThumbnailCache
+type thumbnailEntry struct {
+	path      string
+	thumbnail string
+}
+
+// ThumbnailCacheStats describes the contents and effectiveness of a ThumbnailCache
+type ThumbnailCacheStats struct {
+	Entries    int
+	Bytes      int64
+	MaxEntries int
+	MaxBytes   int64
+	Hits       int64
+	Misses     int64
+	Evictions  int64
 }
 
-// NewThumbnailCache creates a new thumbnail cache
-func NewThumbnailCache() *ThumbnailCache {
+// NewThumbnailCache creates a thumbnail cache holding at most maxEntries thumbnails
+// and maxBytes of data; a limit of 0 or less means unlimited
+func NewThumbnailCache(maxEntries int, maxBytes int64) *ThumbnailCache {
 	return &ThumbnailCache{
-		cache: make(map[string]string),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
 	}
 }
 
 // Get returns a cached thumbnail if available
 func (tc *ThumbnailCache) Get(path string) (string, bool) {
-	tc.mu.RLock()
-	defer tc.mu.RUnlock()
-	thumb, ok := tc.cache[path]
-	return thumb, ok
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	elem, ok := tc.entries[path]
+	if !ok {
+		tc.misses++
+		return "", false
+	}
+	tc.hits++
+	tc.lru.MoveToFront(elem)
+	return elem.Value.(*thumbnailEntry).thumbnail, true
 }
 
-// Set stores a thumbnail in the cache
+// Set stores a thumbnail in the cache, evicting the least recently used ones when
+// it exceeds its limits. A thumbnail larger than the byte limit is not cached.
 func (tc *ThumbnailCache) Set(path, thumbnail string) {
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
-	tc.cache[path] = thumbnail
+	if tc.maxBytes > 0 && int64(len(thumbnail)) > tc.maxBytes {
+		return
+	}
+	if elem, ok := tc.entries[path]; ok {
+		entry := elem.Value.(*thumbnailEntry)
+		tc.bytes += int64(len(thumbnail) - len(entry.thumbnail))
+		entry.thumbnail = thumbnail
+		tc.lru.MoveToFront(elem)
+	} else {
+		tc.entries[path] = tc.lru.PushFront(&thumbnailEntry{path: path, thumbnail: thumbnail})
+		tc.bytes += int64(len(thumbnail))
+	}
+	for (tc.maxEntries > 0 && tc.lru.Len() > tc.maxEntries) || (tc.maxBytes > 0 && tc.bytes > tc.maxBytes) {
+		oldest := tc.lru.Back()
+		entry := oldest.Value.(*thumbnailEntry)
+		tc.lru.Remove(oldest)
+		delete(tc.entries, entry.path)
+		tc.bytes -= int64(len(entry.thumbnail))
+		tc.evictions++
+	}
+}
+
+// Stats returns the current size and hit counters of the cache
+func (tc *ThumbnailCache) Stats() ThumbnailCacheStats {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return ThumbnailCacheStats{
+		Entries:    tc.lru.Len(),
+		Bytes:      tc.bytes,
+		MaxEntries: tc.maxEntries,
+		MaxBytes:   tc.maxBytes,
+		Hits:       tc.hits,
+		Misses:     tc.misses,
+		Evictions:  tc.evictions,
+	}
 }
 
 // GenerateThumbnail creates a thumbnail for an image file
@@ -53,15 +124,9 @@ func GenerateThumbnail(imagePath string, cache *ThumbnailCache) (string, error)
 		return cached, nil
 	}
 
-	file, err := os.Open(imagePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to open image: %w", err)
-	}
-	defer file.Close()
-
-	img, _, err := image.Decode(file)
+	img, err := imagecodec.DecodeOriented(imagePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode image: %w", err)
+		return "", err
 	}
 
 	bounds := img.Bounds()
@@ -117,6 +182,22 @@ func GetImageMimeType(path string) string {
 		return "image/webp"
 	case ".tiff", ".tif":
 		return "image/tiff"
+	case ".heic":
+		return "image/heic"
+	case ".heif":
+		return "image/heif"
+	case ".avif":
+		return "image/avif"
+	case ".cr2":
+		return "image/x-canon-cr2"
+	case ".nef":
+		return "image/x-nikon-nef"
+	case ".arw":
+		return "image/x-sony-arw"
+	case ".dng":
+		return "image/x-adobe-dng"
+	case ".orf":
+		return "image/x-olympus-orf"
 	default:
 		return "image/jpeg"
 	}
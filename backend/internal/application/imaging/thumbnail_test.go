@@ -0,0 +1,57 @@
+package imaging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThumbnailCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewThumbnailCache(2, 0)
+	cache.Set("a", "thumb-a")
+	cache.Set("b", "thumb-b")
+	cache.Get("a") // b is now the least recently used
+	cache.Set("c", "thumb-c")
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("least recently used entry should have been evicted")
+	}
+	for _, path := range []string{"a", "c"} {
+		if _, ok := cache.Get(path); !ok {
+			t.Errorf("%s should still be cached", path)
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 || stats.Hits != 3 || stats.Misses != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestThumbnailCacheByteLimit(t *testing.T) {
+	cache := NewThumbnailCache(0, 10)
+	cache.Set("a", "12345")
+	cache.Set("b", "12345")
+	cache.Set("c", "123")
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("oldest entry should have been evicted to fit the byte limit")
+	}
+	if stats := cache.Stats(); stats.Bytes != 8 || stats.Entries != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// Replacing an entry accounts for the size difference
+	cache.Set("c", "1")
+	if stats := cache.Stats(); stats.Bytes != 6 {
+		t.Errorf("bytes after replace = %d, want 6", stats.Bytes)
+	}
+
+	// A thumbnail that can never fit is not cached and does not flush the cache
+	cache.Set("huge", strings.Repeat("x", 11))
+	if _, ok := cache.Get("huge"); ok {
+		t.Error("thumbnail over the byte limit should not be cached")
+	}
+	if stats := cache.Stats(); stats.Entries != 2 {
+		t.Errorf("entries = %d, want 2", stats.Entries)
+	}
+}
@@ -0,0 +1,67 @@
+package imaging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"image-toolkit/internal/infrastructure/database"
+)
+
+// TimelineMonth counts the exact duplicate groups of one month, "YYYY-MM"
+type TimelineMonth struct {
+	Month            string
+	Groups           int64 `gorm:"column:group_count"`
+	Files            int64 // including the copy to keep
+	ReclaimableBytes int64 // freed by keeping one file per group
+}
+
+// groupMonthExpr is the month a group of exact duplicates was shot in: the earliest
+// EXIF capture time of its copies, or modification time for those without one. It is
+// computed over all copies still indexed, so a group belongs to the same month
+// whatever the filter. algo, hash and size name the columns of the group in the
+// enclosing query.
+func groupMonthExpr(db *gorm.DB, algo, hash, size string) string {
+	return fmt.Sprintf("(SELECT MIN(%s) FROM image_files tf LEFT JOIN image_metadata tm ON tm.image_file_id = tf.id"+
+		" WHERE tf.hash_algo = %s AND tf.hash = %s AND tf.size = %s AND tf.deleted_at IS NULL)",
+		database.MonthExpr(db, "COALESCE(tm.date_taken, tf.mod_time)"), algo, hash, size)
+}
+
+// applyMonth keeps the exact duplicate groups shot in the month, if any
+func applyMonth(db *gorm.DB, month string) *gorm.DB {
+	if month == "" {
+		return db
+	}
+	return db.Where(groupMonthExpr(db, "image_files.hash_algo", "image_files.hash", "image_files.size")+" = ?", month)
+}
+
+// ParseMonth validates a month from a request, "YYYY-MM". An empty month selects
+// groups of any date.
+func ParseMonth(s string) (string, error) {
+	month := strings.TrimSpace(s)
+	if month == "" {
+		return "", nil
+	}
+	if _, err := time.Parse("2006-01", month); err != nil {
+		return "", fmt.Errorf("invalid month %q (expected YYYY-MM)", s)
+	}
+	return month, nil
+}
+
+// BuildTimeline aggregates the exact duplicate groups among the files matching the
+// filter by the month they were shot in, oldest first, so that they can be cleaned
+// up one trip or event at a time
+func BuildTimeline(db *gorm.DB, filter DuplicateFilter) ([]TimelineMonth, error) {
+	dated := db.Table("(?) AS g", duplicateGroupsQuery(db, filter)).
+		Select("g_size, g_files, " + groupMonthExpr(db, "g.g_algo", "g.g_hash", "g.g_size") + " AS month")
+
+	var months []TimelineMonth
+	err := db.Table("(?) AS d", dated).
+		Select("COALESCE(month, '') AS month, COUNT(*) AS group_count, SUM(g_files) AS files, SUM(g_size * (g_files - 1)) AS reclaimable_bytes").
+		Group("month").
+		Order("month").
+		Scan(&months).Error
+	return months, err
+}
@@ -0,0 +1,79 @@
+package imaging
+
+import (
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestParseMonth(t *testing.T) {
+	if month, err := ParseMonth(" 2024-06 "); err != nil || month != "2024-06" {
+		t.Errorf("ParseMonth(2024-06) = %q, %v", month, err)
+	}
+	if month, err := ParseMonth(""); err != nil || month != "" {
+		t.Errorf("ParseMonth() = %q, %v", month, err)
+	}
+	for _, s := range []string{"2024-13", "2024-6", "06-2024", "2024-06-01"} {
+		if _, err := ParseMonth(s); err == nil {
+			t.Errorf("ParseMonth(%q) should fail", s)
+		}
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{}, &domain.ImageMetadata{})
+	june := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	august := time.Date(2024, 8, 3, 9, 0, 0, 0, time.UTC)
+	copied := time.Date(2025, 1, 20, 18, 0, 0, 0, time.UTC)
+	files := []domain.ImageFile{
+		// Shot in June, copied to a backup in January: dated by the capture time
+		{Path: "/photos/beach.jpg", Size: 100, Hash: "a", HashAlgo: "sha256", ModTime: june},
+		{Path: "/backup/beach.jpg", Size: 100, Hash: "a", HashAlgo: "sha256", ModTime: copied},
+		{Path: "/photos/dune.jpg", Size: 50, Hash: "b", HashAlgo: "sha256", ModTime: copied},
+		{Path: "/backup/dune.jpg", Size: 50, Hash: "b", HashAlgo: "sha256", ModTime: copied},
+		{Path: "/old/dune.jpg", Size: 50, Hash: "b", HashAlgo: "sha256", ModTime: copied},
+		// No capture time: dated by the earliest modification time
+		{Path: "/photos/sea.png", Size: 200, Hash: "c", HashAlgo: "sha256", ModTime: august},
+		{Path: "/backup/sea.png", Size: 200, Hash: "c", HashAlgo: "sha256", ModTime: copied},
+		{Path: "/backup/sea (1).png", Size: 200, Hash: "c", HashAlgo: "sha256", ModTime: copied},
+		{Path: "/photos/unique.jpg", Size: 300, Hash: "d", HashAlgo: "sha256", ModTime: june},
+	}
+	if err := db.Create(&files).Error; err != nil {
+		t.Fatal(err)
+	}
+	metadata := []domain.ImageMetadata{
+		{ImageFileID: files[0].ID, DateTaken: &june},
+		{ImageFileID: files[1].ID, DateTaken: &june},
+		{ImageFileID: files[2].ID, DateTaken: &june},
+	}
+	if err := db.Create(&metadata).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	months, err := BuildTimeline(db, DuplicateFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TimelineMonth{
+		{Month: "2024-06", Groups: 2, Files: 5, ReclaimableBytes: 100 + 2*50},
+		{Month: "2024-08", Groups: 1, Files: 3, ReclaimableBytes: 2 * 200},
+	}
+	if len(months) != len(want) {
+		t.Fatalf("timeline = %+v, want %+v", months, want)
+	}
+	for i := range want {
+		if months[i] != want[i] {
+			t.Errorf("month %d = %+v, want %+v", i, months[i], want[i])
+		}
+	}
+
+	// The month of a group does not depend on the copies the filter leaves out
+	groups, totalGroups, _, err := FindDuplicatesPaginated(db, DuplicateFilter{Dir: "/backup", Month: "2024-08"}, OrderDefault, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalGroups != 1 || len(groups) != 1 || groups[0].Hash != "c" {
+		t.Errorf("August groups under /backup = %+v, want the sea group", groups)
+	}
+}
@@ -0,0 +1,74 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+
+	"image-toolkit/internal/infrastructure/objectstore"
+)
+
+var ErrContentMismatch = errors.New("file content differs from the kept copy")
+
+// VerifyIdentical compares path with keep byte by byte and returns ErrContentMismatch
+// when they differ. It guards destructive operations against hash collisions and
+// against files modified since they were hashed.
+func VerifyIdentical(path, keep string) error {
+	if objectstore.IsRemote(path) || objectstore.IsRemote(keep) {
+		return ErrObjectReplace
+	}
+	same, err := sameContent(path, keep)
+	if err != nil {
+		return err
+	}
+	if !same {
+		return ErrContentMismatch
+	}
+	return nil
+}
+
+// sameContent reports whether the files at a and b have identical content
+func sameContent(a, b string) (bool, error) {
+	fa, err := os.Open(longPath(a))
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := os.Open(longPath(b))
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	ia, err := fa.Stat()
+	if err != nil {
+		return false, err
+	}
+	ib, err := fb.Stat()
+	if err != nil {
+		return false, err
+	}
+	if ia.Size() != ib.Size() {
+		return false, nil
+	}
+
+	bufA := make([]byte, 64*1024)
+	bufB := make([]byte, 64*1024)
+	for {
+		na, errA := io.ReadFull(fa, bufA)
+		nb, errB := io.ReadFull(fb, bufB)
+		if !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		if errA == io.EOF || errA == io.ErrUnexpectedEOF {
+			return errB == io.EOF || errB == io.ErrUnexpectedEOF, nil
+		}
+		if errA != nil {
+			return false, errA
+		}
+		if errB != nil {
+			return false, errB
+		}
+	}
+}
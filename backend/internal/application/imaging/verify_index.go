@@ -0,0 +1,110 @@
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+
+	"gorm.io/gorm"
+)
+
+// Problems VerifyIndex reports for an indexed file
+const (
+	IndexFileMissing    = "missing"    // not on disk any more
+	IndexFileChanged    = "changed"    // size or modification time differ from the index
+	IndexFileCorrupt    = "corrupt"    // same size and time, but the content hash differs
+	IndexFileUnreadable = "unreadable" // could not be read
+)
+
+// IndexProblem is an indexed file that does not match the disk
+type IndexProblem struct {
+	Path    string `json:"path"`
+	Problem string `json:"problem"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// IndexVerification is the outcome of VerifyIndex
+type IndexVerification struct {
+	Checked  int            `json:"checked"`
+	Hashed   int            `json:"hashed"`
+	Problems []IndexProblem `json:"problems"`
+}
+
+// VerifyIndex checks the indexed files of this machine against the disk without
+// changing the index. With rehash the content of every file that looks unchanged is
+// hashed again and compared with the indexed hash, which finds silent corruption.
+// Remote files and the files reported by agents are skipped.
+func VerifyIndex(ctx context.Context, db *gorm.DB, rehash bool, throttle *Throttle, progress func(string)) (*IndexVerification, error) {
+	result := &IndexVerification{Problems: []IndexProblem{}}
+
+	const batchSize = 500
+	var lastID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		var batch []domain.ImageFile
+		if err := db.Where("host = ? AND id > ?", "", lastID).Order("id").Limit(batchSize).Find(&batch).Error; err != nil {
+			return result, err
+		}
+		if len(batch) == 0 {
+			return result, nil
+		}
+		lastID = batch[len(batch)-1].ID
+
+		for _, file := range batch {
+			if ctx.Err() != nil {
+				break
+			}
+			if objectstore.IsRemote(file.Path) {
+				continue
+			}
+			result.Checked++
+			problem, hashed := verifyIndexedFile(file, rehash, throttle)
+			if hashed {
+				result.Hashed++
+			}
+			if problem != nil {
+				result.Problems = append(result.Problems, *problem)
+			}
+		}
+		if progress != nil {
+			progress(fmt.Sprintf("Verified %d files, %d problems", result.Checked, len(result.Problems)))
+		}
+	}
+}
+
+// verifyIndexedFile compares one index record with the file on disk and reports
+// whether its content was hashed
+func verifyIndexedFile(file domain.ImageFile, rehash bool, throttle *Throttle) (*IndexProblem, bool) {
+	info, err := os.Stat(longPath(file.Path))
+	if os.IsNotExist(err) {
+		return &IndexProblem{Path: file.Path, Problem: IndexFileMissing}, false
+	}
+	if err != nil {
+		return &IndexProblem{Path: file.Path, Problem: IndexFileUnreadable, Detail: err.Error()}, false
+	}
+	if info.Size() != file.Size || !info.ModTime().Equal(file.ModTime) {
+		return &IndexProblem{Path: file.Path, Problem: IndexFileChanged,
+			Detail: fmt.Sprintf("indexed %d bytes, %s; on disk %d bytes, %s", file.Size, file.ModTime.Format("2006-01-02 15:04:05"), info.Size(), info.ModTime().Format("2006-01-02 15:04:05"))}, false
+	}
+	// Files of unique size are not hashed when the size prefilter is on
+	if !rehash || file.Hash == "" {
+		return nil, false
+	}
+	algo, err := ParseContentHashAlgorithm(file.HashAlgo)
+	if err != nil {
+		return &IndexProblem{Path: file.Path, Problem: IndexFileUnreadable, Detail: err.Error()}, false
+	}
+	hash, err := calculateFileHash(file.Path, algo, throttle)
+	if err != nil {
+		return &IndexProblem{Path: file.Path, Problem: IndexFileUnreadable, Detail: err.Error()}, true
+	}
+	if hash != file.Hash {
+		return &IndexProblem{Path: file.Path, Problem: IndexFileCorrupt, Detail: fmt.Sprintf("%s %s, indexed %s", algo, hash, file.Hash)}, true
+	}
+	return nil, true
+}
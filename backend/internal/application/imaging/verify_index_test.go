@@ -0,0 +1,71 @@
+package imaging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+func TestVerifyIndex(t *testing.T) {
+	db := openTestDB(t, &domain.ImageFile{})
+	dir := t.TempDir()
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	index := func(name, content string) string {
+		path := filepath.ToSlash(filepath.Join(dir, name))
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := calculateFileHash(path, ContentHashMD5, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.Create(&domain.ImageFile{Path: path, Hash: hash, HashAlgo: "md5", Size: int64(len(content)), ModTime: modTime})
+		return path
+	}
+	index("ok.jpg", "photo")
+	missing := index("missing.jpg", "photo")
+	changed := index("changed.jpg", "photo")
+	corrupt := index("corrupt.jpg", "photo")
+	db.Create(&domain.ImageFile{Path: "/elsewhere/agent.jpg", Hash: "h", Size: 1, ModTime: modTime, Host: "nas"})
+
+	os.Remove(missing)
+	os.WriteFile(changed, []byte("edited photo"), 0o644)
+	// Same size and time, different content
+	os.WriteFile(corrupt, []byte("phot0"), 0o644)
+	os.Chtimes(corrupt, modTime, modTime)
+
+	quick, err := VerifyIndex(context.Background(), db, false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quick.Checked != 4 || quick.Hashed != 0 || len(quick.Problems) != 2 {
+		t.Fatalf("quick verification = %+v", quick)
+	}
+
+	full, err := VerifyIndex(context.Background(), db, true, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{missing: IndexFileMissing, changed: IndexFileChanged, corrupt: IndexFileCorrupt}
+	if full.Hashed != 2 || len(full.Problems) != len(want) {
+		t.Fatalf("full verification = %+v", full)
+	}
+	for _, p := range full.Problems {
+		if want[p.Path] != p.Problem {
+			t.Errorf("%s: problem = %s, want %s", p.Path, p.Problem, want[p.Path])
+		}
+	}
+
+	var files int64
+	db.Model(&domain.ImageFile{}).Count(&files)
+	if files != 5 {
+		t.Errorf("verification changed the index: %d files", files)
+	}
+}
@@ -0,0 +1,41 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyIdentical(t *testing.T) {
+	dir := t.TempDir()
+	big := bytes.Repeat([]byte("0123456789abcdef"), 10000) // spans several read buffers
+	changed := bytes.Clone(big)
+	changed[len(changed)-1] = 'x'
+
+	files := map[string][]byte{
+		"keep":    big,
+		"same":    big,
+		"changed": changed,
+		"short":   big[:len(big)-1],
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keep := filepath.Join(dir, "keep")
+
+	if err := VerifyIdentical(filepath.Join(dir, "same"), keep); err != nil {
+		t.Errorf("identical files: %v", err)
+	}
+	for _, name := range []string{"changed", "short"} {
+		if err := VerifyIdentical(filepath.Join(dir, name), keep); !errors.Is(err, ErrContentMismatch) {
+			t.Errorf("%s: got %v, want ErrContentMismatch", name, err)
+		}
+	}
+	if err := VerifyIdentical(filepath.Join(dir, "missing"), keep); err == nil || errors.Is(err, ErrContentMismatch) {
+		t.Errorf("missing file: got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package imaging
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// walkTree walks the file tree rooted at root like filepath.Walk, except that
+// symlinks below root are not reported unless follow is set. With follow set,
+// symlinks to files and directories are followed: entries get the FileInfo of the
+// link target and keep their path below the link. Directories are identified by
+// device and inode (the resolved path on Windows), so a directory reachable through
+// several links, or a link pointing at one of its own parents, is walked only once.
+func walkTree(root string, follow bool, fn filepath.WalkFunc) error {
+	if !follow {
+		// Links are skipped altogether: a link to a file would otherwise be indexed
+		// with the size of the link and the content of its target
+		return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err == nil && path != root && info.Mode()&os.ModeSymlink != 0 {
+				return nil
+			}
+			return fn(path, info, err)
+		})
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		w := &linkWalker{fn: fn, visited: make(map[fileID]bool)}
+		err = w.walk(root, info)
+	}
+	if errors.Is(err, filepath.SkipDir) || errors.Is(err, filepath.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+// isSymlinked reports whether a walked entry is a symlink or lies below a followed
+// directory symlink
+func isSymlinked(info os.FileInfo) bool {
+	if _, ok := info.(linkedInfo); ok {
+		return true
+	}
+	return info.Mode()&os.ModeSymlink != 0
+}
+
+// isSymlinkPath reports whether the file at path is itself a symlink
+func isSymlinkPath(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+// linkedInfo marks the FileInfo of an entry reached through a symlink
+type linkedInfo struct {
+	os.FileInfo
+}
+
+type linkWalker struct {
+	fn      filepath.WalkFunc
+	visited map[fileID]bool
+}
+
+func (w *linkWalker) walk(path string, info os.FileInfo) error {
+	if !info.IsDir() {
+		return w.fn(path, info, nil)
+	}
+
+	if id, ok := fileIDOf(path, info); ok {
+		if w.visited[id] {
+			return nil // symlink loop or a directory already walked through another link
+		}
+		w.visited[id] = true
+	}
+
+	if err := w.fn(path, info, nil); err != nil {
+		if errors.Is(err, filepath.SkipDir) {
+			return nil
+		}
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if err := w.fn(path, info, err); err != nil && !errors.Is(err, filepath.SkipDir) {
+			return err
+		}
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	_, parentLinked := info.(linkedInfo)
+	for _, entry := range entries {
+		child := filepath.Join(path, entry.Name())
+		childInfo, err := w.stat(child, entry, parentLinked)
+		if err != nil {
+			if err := w.fn(child, childInfo, err); err != nil && !errors.Is(err, filepath.SkipDir) {
+				return err
+			}
+			continue
+		}
+		if err := w.walk(child, childInfo); err != nil {
+			if errors.Is(err, filepath.SkipDir) && !childInfo.IsDir() {
+				return nil // like filepath.Walk: SkipDir on a file skips the rest of the directory
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// stat returns the FileInfo of a directory entry, resolving symlinks. A broken
+// link is reported with its own (Lstat) info and the resolution error.
+func (w *linkWalker) stat(path string, entry fs.DirEntry, parentLinked bool) (os.FileInfo, error) {
+	if entry.Type()&os.ModeSymlink == 0 {
+		info, err := entry.Info()
+		if err == nil && parentLinked {
+			info = linkedInfo{info}
+		}
+		return info, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		linkInfo, lerr := entry.Info()
+		if lerr != nil {
+			return nil, err
+		}
+		return linkInfo, err
+	}
+	return linkedInfo{info}, nil
+}
@@ -0,0 +1,72 @@
+package imaging
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestWalkTreeFollowsSymlinksWithoutLooping(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	mustWrite := func(path string) {
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "a"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(filepath.Join(root, "a", "1.jpg"))
+	mustWrite(filepath.Join(outside, "2.jpg"))
+	links := map[string]string{
+		filepath.Join(root, "a", "loop"): root,    // points at its own ancestor
+		filepath.Join(root, "ext"):       outside, // directory outside the root
+		filepath.Join(root, "3.jpg"):     filepath.Join(outside, "2.jpg"),
+		filepath.Join(root, "broken"):    filepath.Join(outside, "missing"),
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	collect := func(follow bool) (files []string, linked map[string]bool) {
+		linked = make(map[string]bool)
+		err := walkTree(root, follow, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			rel, _ := filepath.Rel(root, path)
+			files = append(files, filepath.ToSlash(rel))
+			linked[filepath.ToSlash(rel)] = isSymlinked(info)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(files)
+		return files, linked
+	}
+
+	files, linked := collect(true)
+	want := []string{"3.jpg", "a/1.jpg", "ext/2.jpg"}
+	if len(files) != len(want) {
+		t.Fatalf("followed walk = %v, want %v", files, want)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Fatalf("followed walk = %v, want %v", files, want)
+		}
+	}
+	if linked["a/1.jpg"] || !linked["3.jpg"] || !linked["ext/2.jpg"] {
+		t.Errorf("symlink flags = %v", linked)
+	}
+
+	// Without following, links are neither descended into nor reported
+	files, _ = collect(false)
+	if len(files) != 1 || files[0] != "a/1.jpg" {
+		t.Errorf("unfollowed walk = %v, want [a/1.jpg]", files)
+	}
+}
@@ -0,0 +1,418 @@
+package imaging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"image-toolkit/internal/application/thumbnail"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+
+	"github.com/fsnotify/fsnotify"
+	"gorm.io/gorm"
+)
+
+const (
+	// watchDebounce is how long a path must stay quiet before its change is applied,
+	// so files that are still being copied are hashed only once
+	watchDebounce = 2 * time.Second
+	// watchRefreshInterval is how often the set of gallery folders is re-read from the DB
+	watchRefreshInterval = time.Minute
+)
+
+// FolderWatcher keeps ImageFile rows current by watching gallery folders with fsnotify.
+// Added, modified, renamed and removed files are applied incrementally, so duplicates
+// stay up to date between full scans.
+type FolderWatcher struct {
+	mu               sync.Mutex
+	running          bool
+	stopCh           chan struct{}
+	db               *gorm.DB
+	thumbnailService *thumbnail.Service
+	options          ScanOptions
+	watcher          *fsnotify.Watcher
+	roots            map[string]bool      // watched gallery folders
+	pending          map[string]time.Time // path -> time of the last event
+	OnChange         func()               // called after a batch of changes was applied (if non-nil)
+}
+
+// NewFolderWatcher creates a new folder watcher
+func NewFolderWatcher(db *gorm.DB, thumbnailService *thumbnail.Service, options ScanOptions) *FolderWatcher {
+	return &FolderWatcher{
+		db:               db,
+		thumbnailService: thumbnailService,
+		options:          options,
+		roots:            make(map[string]bool),
+		pending:          make(map[string]time.Time),
+	}
+}
+
+// Start begins watching all gallery folders
+func (fw *FolderWatcher) Start() error {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if fw.running {
+		return nil
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	fw.watcher = w
+	fw.running = true
+	fw.stopCh = make(chan struct{})
+	fw.roots = make(map[string]bool)
+	fw.pending = make(map[string]time.Time)
+
+	fw.refreshRootsLocked()
+	go fw.loop()
+
+	slog.Info("Folder watcher started", "folders", len(fw.roots))
+	return nil
+}
+
+// Stop stops watching
+func (fw *FolderWatcher) Stop() {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if !fw.running {
+		return
+	}
+	fw.running = false
+	close(fw.stopCh)
+	fw.watcher.Close()
+	slog.Info("Folder watcher stopped")
+}
+
+// IsRunning returns whether the watcher is active
+func (fw *FolderWatcher) IsRunning() bool {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	return fw.running
+}
+
+// loop dispatches fsnotify events and applies debounced changes
+func (fw *FolderWatcher) loop() {
+	flushTicker := time.NewTicker(watchDebounce / 2)
+	defer flushTicker.Stop()
+	refreshTicker := time.NewTicker(watchRefreshInterval)
+	defer refreshTicker.Stop()
+
+	for {
+		select {
+		case event, ok := <-fw.watcher.Events:
+			if !ok {
+				return
+			}
+			fw.handleEvent(event)
+		case err, ok := <-fw.watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("Folder watcher error", "error", err)
+		case <-flushTicker.C:
+			fw.flush()
+		case <-refreshTicker.C:
+			fw.mu.Lock()
+			fw.refreshRootsLocked()
+			fw.mu.Unlock()
+		case <-fw.stopCh:
+			return
+		}
+	}
+}
+
+// refreshRootsLocked starts watching newly added gallery folders and stops watching removed ones
+func (fw *FolderWatcher) refreshRootsLocked() {
+	var folders []domain.GalleryFolder
+	if err := fw.db.Find(&folders).Error; err != nil {
+		slog.Error("Folder watcher: failed to get gallery folders", "error", err)
+		return
+	}
+
+	current := make(map[string]bool, len(folders))
+	for _, f := range folders {
+		if objectstore.IsRemote(f.Path) {
+			continue
+		}
+		absPath, err := filepath.Abs(f.Path)
+		if err != nil {
+			continue
+		}
+		current[absPath] = true
+		if !fw.roots[absPath] {
+			fw.addTreeLocked(absPath, absPath)
+		}
+	}
+
+	for root := range fw.roots {
+		if !current[root] {
+			fw.removeTreeLocked(root)
+		}
+	}
+	fw.roots = current
+}
+
+// addTreeLocked watches a directory and all its subdirectories (fsnotify is not recursive)
+// down to the maximum depth below the gallery root
+func (fw *FolderWatcher) addTreeLocked(root, dir string) {
+	ignore := newIgnoreMatcher()
+	walkTree(dir, fw.options.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if path != root && (fw.options.isExcluded(path) || ignore.ignored(root, path, true)) {
+			return filepath.SkipDir
+		}
+		if fw.options.beyondMaxDepth(root, path, true) {
+			return filepath.SkipDir
+		}
+		if err := fw.watcher.Add(path); err != nil {
+			slog.Warn("Folder watcher: failed to watch directory", "path", path, "error", err)
+		}
+		return nil
+	})
+}
+
+// rootOfLocked returns the watched gallery root containing path, or path itself
+// if it is outside all roots
+func (fw *FolderWatcher) rootOfLocked(path string) string {
+	for root := range fw.roots {
+		if path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return root
+		}
+	}
+	return path
+}
+
+// removeTreeLocked stops watching a directory and its subdirectories
+func (fw *FolderWatcher) removeTreeLocked(root string) {
+	prefix := root + string(filepath.Separator)
+	for _, path := range fw.watcher.WatchList() {
+		if path == root || len(path) > len(prefix) && path[:len(prefix)] == prefix {
+			fw.watcher.Remove(path)
+		}
+	}
+}
+
+// handleEvent records a change for debounced processing. New directories are watched
+// immediately and their existing images queued, since files may land before the watch does.
+func (fw *FolderWatcher) handleEvent(event fsnotify.Event) {
+	if fw.options.isExcluded(event.Name) {
+		return
+	}
+
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	// Paths that no longer exist are never ignored, so records of removed files get cleaned up
+	root := fw.rootOfLocked(event.Name)
+	ignore := newIgnoreMatcher()
+	info, statErr := os.Stat(event.Name)
+	if statErr == nil && ignore.ignored(root, event.Name, info.IsDir()) {
+		return
+	}
+
+	if event.Has(fsnotify.Create) {
+		if statErr == nil && info.IsDir() {
+			fw.addTreeLocked(root, event.Name)
+			walkTree(event.Name, fw.options.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+				if err == nil && (fw.options.isExcluded(path) || ignore.ignored(root, path, info.IsDir())) {
+					if info.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if err == nil && info.IsDir() && fw.options.beyondMaxDepth(root, path, true) {
+					return filepath.SkipDir
+				}
+				if err == nil && !info.IsDir() && fw.options.includes(path) && fw.options.inSizeRange(info.Size()) {
+					fw.pending[path] = time.Now()
+				}
+				return nil
+			})
+			return
+		}
+	}
+
+	// Removed or renamed directories are not stat-able anymore, so queue the path
+	// regardless of extension and let applyChange decide
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) || fw.options.includes(event.Name) {
+		fw.pending[event.Name] = time.Now()
+	}
+}
+
+// flush applies changes for paths that have been quiet for watchDebounce
+func (fw *FolderWatcher) flush() {
+	fw.mu.Lock()
+	var ready []string
+	now := time.Now()
+	for path, at := range fw.pending {
+		if now.Sub(at) >= watchDebounce {
+			ready = append(ready, path)
+			delete(fw.pending, path)
+		}
+	}
+	fw.mu.Unlock()
+
+	if len(ready) == 0 {
+		return
+	}
+
+	// Paths that exist go first: a moved or renamed file then takes over the record of
+	// its old path before the removal of the old path is applied
+	sort.SliceStable(ready, func(i, j int) bool {
+		_, errI := os.Lstat(ready[i])
+		_, errJ := os.Lstat(ready[j])
+		return errI == nil && errJ != nil
+	})
+
+	changed := 0
+	for _, path := range ready {
+		if fw.applyChange(path) {
+			changed++
+		}
+	}
+	if changed == 0 {
+		return
+	}
+
+	completeDeferredHashingLogged(fw.db, fw.options, "Folder watcher")
+	slog.Info("Folder watcher: applied changes", "changes", changed)
+
+	if fw.OnChange != nil {
+		fw.OnChange()
+	}
+}
+
+// applyChange brings the DB record(s) for a path in line with the disk.
+// Returns true if anything was written.
+func (fw *FolderWatcher) applyChange(path string) bool {
+	normalizedPath := filepath.ToSlash(path)
+	info, err := os.Stat(path)
+
+	if os.IsNotExist(err) {
+		// The files of an unmounted gallery folder are gone only for now
+		fw.mu.Lock()
+		root := fw.rootOfLocked(path)
+		watched := fw.roots[root]
+		fw.mu.Unlock()
+		if watched {
+			if err := checkRoot(fw.db, root, fw.options); err != nil {
+				slog.Warn("Folder watcher: keeping records of unavailable gallery folder", "path", path, "error", err)
+				return false
+			}
+		}
+
+		// File or whole directory removed (or renamed away)
+		key, column := fw.options.pathKey(normalizedPath), fw.options.pathColumn()
+		result := fw.db.Where(column+" = ? OR "+column+` LIKE ? ESCAPE '\'`, key, escapeLike(key)+"/%").Delete(&domain.ImageFile{})
+		if result.Error != nil {
+			slog.Error("Folder watcher: failed to delete records", "path", path, "error", result.Error)
+			return false
+		}
+		if fw.thumbnailService != nil {
+			fw.thumbnailService.Invalidate(path)
+		}
+		if result.RowsAffected > 0 {
+			slog.Debug("Folder watcher: removed records", "path", path, "records", result.RowsAffected)
+		}
+		return result.RowsAffected > 0
+	}
+	if err == nil && !fw.options.FollowSymlinks && isSymlinkPath(path) {
+		// Not indexed without following, e.g. a duplicate just replaced by a link
+		return fw.db.Where(fw.options.pathColumn()+" = ?", fw.options.pathKey(normalizedPath)).Delete(&domain.ImageFile{}).RowsAffected > 0
+	}
+	if err != nil || info.IsDir() || !fw.options.includes(path) || !fw.options.inSizeRange(info.Size()) {
+		return false
+	}
+
+	fi := fileInfo{path: path, normalizedPath: normalizedPath, size: info.Size(), modTime: info.ModTime(), symlink: isSymlinkPath(path), deferHash: fw.options.SizePrefilter}
+	fi.device, fi.inode = inodeOf(path, info)
+
+	var rows []domain.ImageFile
+	fw.db.Unscoped().Where(fw.options.pathColumn()+" = ?", fw.options.pathKey(normalizedPath)).Find(&rows)
+	records, variants := fw.options.pickRecords(rows, func(p string) bool { return p == normalizedPath })
+	for _, v := range variants {
+		fw.db.Delete(&v)
+	}
+	existing, found := records[fw.options.pathKey(normalizedPath)]
+	if found {
+		if err := reviveRecord(fw.db, &existing); err != nil {
+			slog.Error("Folder watcher: failed to revive record", "path", path, "error", err)
+			return false
+		}
+	}
+	if found && existing.Size == info.Size() && existing.ModTime.Equal(info.ModTime()) && !fw.options.needsContentRehash(&existing) {
+		// e.g. replaced by a hardlink of another copy, or reached under a differently
+		// cased path
+		refreshUnchanged(fw.db, &existing, fi)
+		return len(variants) > 0 || existing.Path != normalizedPath
+	}
+
+	if !found {
+		if source := findMoveSources(fw.db, []fileInfo{fi}).claim(fi, fw.options.Throttle); source != nil {
+			if err := moveFile(fw.db, source, fi); err != nil {
+				slog.Error("Folder watcher: failed to move record", "from", source.Path, "to", path, "error", err)
+				return false
+			}
+			if fw.thumbnailService != nil {
+				fw.thumbnailService.Invalidate(source.Path)
+			}
+			slog.Debug("Folder watcher: moved file", "from", source.Path, "to", path)
+			return true
+		}
+	}
+	var existingPtr *domain.ImageFile
+	if found {
+		existingPtr = &existing
+	}
+	hashed := hashFile(fi, existingPtr, fw.options)
+	if hashed.err != nil {
+		slog.Warn("Folder watcher: failed to hash file", "path", path, "error", hashed.err)
+		return false
+	}
+
+	record := domain.ImageFile{
+		Path:       normalizedPath,
+		Size:       info.Size(),
+		Hash:       hashed.hash,
+		HashAlgo:   hashed.hashAlgo,
+		PrefixHash: hashed.prefixHash,
+		PHash:      hashed.pHash,
+		PHashAlgo:  hashed.pHashAlgo,
+		PHashXform: hashed.pHashXform,
+		PixelHash:  hashed.pixelHash,
+		Corrupt:    hashed.corrupt,
+		ModTime:    info.ModTime(),
+		IsVideo:    domain.IsVideoFile(path),
+		IsSymlink:  isSymlinkPath(path),
+		Device:     fi.device,
+		Inode:      fi.inode,
+	}
+	if found {
+		record.ID = existing.ID
+		record.CreatedAt = existing.CreatedAt
+		record.Protected = existing.Protected
+		if fw.thumbnailService != nil {
+			fw.thumbnailService.Invalidate(path)
+		}
+	}
+	if err := fw.db.Save(&record).Error; err != nil {
+		slog.Error("Folder watcher: failed to save record", "path", path, "error", err)
+		return false
+	}
+
+	if found {
+		slog.Debug("Folder watcher: updated file", "path", path)
+	} else {
+		slog.Debug("Folder watcher: added new file", "path", path)
+	}
+	return true
+}
@@ -7,7 +7,7 @@ import (
 	"image"
 	"image/jpeg"
 	"image/png"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
@@ -15,6 +15,8 @@ import (
 
 	"github.com/deepteams/webp"
 	"github.com/disintegration/imaging"
+
+	"image-toolkit/internal/infrastructure/imagecodec"
 )
 
 // Config конфигурация ThumbnailService
@@ -213,17 +215,16 @@ func (s *Service) GenerateThumbnail(filePath string) ([]byte, error) {
 
 // generateThumbnail внутренняя функция генерации миниатюры
 func (s *Service) generateThumbnail(filePath string) ([]byte, error) {
-	file, err := os.Open(filePath)
+	img, err := imagecodec.DecodeOriented(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open image: %w", err)
-	}
-	defer file.Close()
-
-	img, _, err := image.Decode(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode image: %w", err)
+		return nil, err
 	}
+	return s.encodeThumbnail(img)
+}
 
+// encodeThumbnail уменьшает изображение до MaxSize и кодирует его в формат кэша.
+// Использует только неизменяемые поля конфигурации, поэтому не требует блокировки.
+func (s *Service) encodeThumbnail(img image.Image) ([]byte, error) {
 	bounds := img.Bounds()
 	width := bounds.Dx()
 	height := bounds.Dy()
@@ -273,16 +274,37 @@ func (s *Service) Invalidate(filePath string) error {
 	return s.storage.Delete(filePath)
 }
 
-// saveThumbnailToCache внутренний метод для сохранения миниатюры (для интеграции со сканером)
-func (s *Service) saveThumbnailToCache(filePath string, thumbnailData []byte) error {
+// NeedsThumbnail сообщает, нужно ли сохранить миниатюру файла при сканировании:
+// кэш включен, предзагрузка при сканировании разрешена и свежей миниатюры нет
+func (s *Service) NeedsThumbnail(filePath string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.cfg.Enabled && s.initialized && s.cfg.PreloadOnScan && !s.storage.Exists(filePath)
+}
+
+// StoreImage создает миниатюру из уже декодированного файла (без учета EXIF-ориентации,
+// как его возвращает imagecodec.Decode) и сохраняет ее в кэш. Сканер передает сюда
+// изображение, декодированное для хешей, чтобы не декодировать файл второй раз.
+func (s *Service) StoreImage(filePath string, img image.Image) error {
+	data, err := s.encodeThumbnail(imagecodec.Orient(img, filePath))
+	if err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if !s.cfg.Enabled || !s.initialized {
 		return ErrThumbnailCacheDisabled
 	}
+	if err := s.storage.Set(filePath, data); err != nil {
+		return err
+	}
 
-	return s.storage.Set(filePath, thumbnailData)
+	s.stats.TotalFiles++
+	s.stats.TotalSize += int64(len(data))
+	return nil
 }
 
 // InvalidateAll invalидирует все миниатюры
@@ -369,14 +391,14 @@ func (s *Service) Stats() ThumbnailStats {
 	defer s.mu.Unlock()
 
 	if !s.initialized {
-		log.Printf("Stats: service not initialized")
+		slog.Debug("Thumbnail stats: service not initialized")
 		return ThumbnailStats{}
 	}
 
 	// Always refresh from disk to ensure accuracy
 	s.updateStats()
 
-	log.Printf("Stats: returning %+v", s.stats)
+	slog.Debug("Thumbnail stats", "stats", s.stats)
 	return s.stats
 }
 
@@ -390,26 +412,26 @@ func (s *Service) UpdateCachePath(newPath string) error {
 	defer s.mu.Unlock()
 
 	oldPath := s.cfg.CacheDir
-	log.Printf("UpdateCachePath: old=%s, new=%s", oldPath, newPath)
+	slog.Info("Updating thumbnail cache path", "old", oldPath, "new", newPath)
 
 	// Если путь не изменился, просто обновляем статистику
 	if oldPath == newPath {
-		log.Printf("UpdateCachePath: paths are the same, updating stats only")
+		slog.Debug("Thumbnail cache path unchanged, updating stats only")
 		s.updateStats()
-		log.Printf("UpdateCachePath: stats after update: %+v", s.stats)
+		slog.Debug("Thumbnail stats updated", "stats", s.stats)
 		return nil
 	}
 
 	// Перемещаем файлы из старого хранилища в новое
 	if err := s.moveCacheTo(newPath); err != nil {
-		log.Printf("UpdateCachePath: moveCacheTo failed: %v", err)
+		slog.Error("Failed to move thumbnail cache", "error", err)
 		return err
 	}
 
 	// Создаем новое хранилище и заменяем старое
 	newStorage, err := NewThumbnailCacheStorage(newPath)
 	if err != nil {
-		log.Printf("UpdateCachePath: NewThumbnailCacheStorage failed: %v", err)
+		slog.Error("Failed to open thumbnail cache storage", "error", err)
 		return &ErrCacheInitFailed{Path: newPath, Err: err}
 	}
 
@@ -419,7 +441,7 @@ func (s *Service) UpdateCachePath(newPath string) error {
 	s.initialized = true
 
 	s.updateStats()
-	log.Printf("UpdateCachePath: success, stats: %+v", s.stats)
+	slog.Info("Thumbnail cache path updated", "stats", s.stats)
 	return nil
 }
 
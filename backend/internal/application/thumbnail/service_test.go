@@ -0,0 +1,53 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreImageFromScan(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "photo.png")
+	if err := os.WriteFile(source, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(source, past, past)
+
+	cfg := &Config{CacheDir: filepath.Join(dir, "cache"), MaxSize: 32, Quality: 80, Enabled: true, Format: "jpeg", PreloadOnScan: true}
+	service, err := NewService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !service.NeedsThumbnail(source) {
+		t.Fatal("a file without a cached thumbnail needs one")
+	}
+
+	if err := service.StoreImage(source, image.NewGray(image.Rect(0, 0, 100, 50))); err != nil {
+		t.Fatal(err)
+	}
+	if service.NeedsThumbnail(source) {
+		t.Error("a stored thumbnail should satisfy the scan")
+	}
+	data, err := os.ReadFile(service.GetThumbnailPath(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumb, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumb.Width != 32 || thumb.Height != 16 {
+		t.Errorf("thumbnail is %dx%d, want 32x16", thumb.Width, thumb.Height)
+	}
+
+	cfg.PreloadOnScan = false
+	if service.NeedsThumbnail(filepath.Join(dir, "other.png")) {
+		t.Error("scans should not cache thumbnails unless preloading is enabled")
+	}
+}
@@ -154,7 +154,7 @@ func (tcs *ThumbnailCacheStorage) GetPathRelative(filePath string) string {
 	return CachePathRelative(filePath)
 }
 
-// Exists проверяет наличие миниатюры в кэше
+// Exists проверяет наличие актуальной миниатюры в кэше
 func (tcs *ThumbnailCacheStorage) Exists(filePath string) bool {
 	if !tcs.enabled {
 		return false
@@ -164,8 +164,22 @@ func (tcs *ThumbnailCacheStorage) Exists(filePath string) bool {
 	tcs.mu.RLock()
 	defer tcs.mu.RUnlock()
 
-	_, err := os.Stat(path)
-	return err == nil
+	return isFresh(path, filePath)
+}
+
+// isFresh проверяет, что миниатюра существует и создана не раньше последнего изменения
+// исходного файла. Кэш переживает перезапуск, поэтому файл, измененный, пока сервер был
+// остановлен, получает новую миниатюру. Если исходный файл недоступен, миниатюра
+// считается актуальной.
+func isFresh(thumbPath, filePath string) bool {
+	thumbInfo, err := os.Stat(thumbPath)
+	if err != nil {
+		return false
+	}
+	if info, err := os.Stat(filePath); err == nil && info.ModTime().After(thumbInfo.ModTime()) {
+		return false
+	}
+	return true
 }
 
 // Get возвращает путь к миниатюре, если она существует, пустую строку иначе
@@ -178,7 +192,7 @@ func (tcs *ThumbnailCacheStorage) Get(filePath string) string {
 	tcs.mu.RLock()
 	defer tcs.mu.RUnlock()
 
-	if _, err := os.Stat(path); err != nil {
+	if !isFresh(path, filePath) {
 		return ""
 	}
 	return path
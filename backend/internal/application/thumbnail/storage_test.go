@@ -0,0 +1,42 @@
+package thumbnail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStorageSkipsStaleThumbnails(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewThumbnailCacheStorage(filepath.Join(dir, "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := filepath.Join(dir, "photo.jpg")
+	if err := os.WriteFile(source, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(source, past, past)
+
+	if err := storage.Set(source, []byte("thumb")); err != nil {
+		t.Fatal(err)
+	}
+	if !storage.Exists(source) || storage.Get(source) == "" {
+		t.Fatal("thumbnail written after the last change should be served")
+	}
+
+	// The source changes after the thumbnail was cached, e.g. while the server was down
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(source, future, future)
+	if storage.Exists(source) || storage.Get(source) != "" {
+		t.Error("thumbnail older than its source should be treated as missing")
+	}
+
+	// Thumbnails of files that are gone are still served
+	os.Remove(source)
+	if !storage.Exists(source) {
+		t.Error("thumbnail of an unreadable source should be kept")
+	}
+}
@@ -4,17 +4,35 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // ImageFile represents an image file in the database
 type ImageFile struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Path      string    `gorm:"uniqueIndex;not null" json:"path"`
-	Size      int64     `gorm:"not null;index:idx_size_hash" json:"size"`
-	Hash      string    `gorm:"not null;index:idx_size_hash" json:"hash"`
-	ModTime   time.Time `gorm:"not null" json:"modTime"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Path       string    `gorm:"uniqueIndex;not null" json:"path"`
+	Size       int64     `gorm:"not null;index:idx_size_hash" json:"size"`
+	Hash       string    `gorm:"not null;index:idx_size_hash" json:"hash"`
+	PrefixHash string    `gorm:"default:'';index" json:"prefixHash"`     // Hash of the first 64KB (two-stage hashing)
+	HashAlgo   string    `gorm:"not null;default:'md5'" json:"hashAlgo"` // Algorithm of Hash: md5, sha256, xxh64 or blake3
+	PHash      string    `gorm:"default:'';index" json:"pHash"`          // Perceptual hash (hex), empty if not computed
+	PHashAlgo  string    `gorm:"default:''" json:"pHashAlgo"`            // Algorithm of PHash: ahash, dhash or phash
+	PHashXform string    `gorm:"default:''" json:"pHashXform"`           // Perceptual hashes of the rotated and mirrored image (comma-separated hex), empty if not computed
+	PixelHash  string    `gorm:"default:'';index" json:"pixelHash"`      // Hash of the decoded, normalized pixels, empty if not computed
+	ModTime    time.Time `gorm:"not null" json:"modTime"`
+	IsVideo    bool      `gorm:"not null;default:false;index" json:"isVideo"` // Video file: exact duplicates only, no metadata or OCR
+	IsSymlink  bool      `gorm:"not null;default:false" json:"isSymlink"`     // Symlink, or reached through a followed directory symlink
+	Device     int64     `gorm:"not null;default:0" json:"device"`            // Device (volume serial number on Windows), 0 if unknown
+	Inode      int64     `gorm:"not null;default:0;index" json:"inode"`       // Inode (file index on Windows), 0 if unknown; hardlinks share device and inode
+	Protected  bool      `gorm:"not null;default:false" json:"protected"`     // Pinned by the user: delete requests never remove the file
+	Host       string    `gorm:"not null;default:'';index" json:"host"`       // Agent that reported the file, empty for the files of this server
+	Corrupt    bool      `gorm:"not null;default:false;index" json:"corrupt"` // Empty, or an image that fails to decode
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	// Set when the file was removed from the index; the row is kept for the retention
+	// period so that a file coming back, e.g. on a remounted NAS, is not hashed again
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // DuplicateGroup represents a group of duplicate images
@@ -22,6 +40,23 @@ type DuplicateGroup struct {
 	Hash  string
 	Size  int64
 	Files []ImageFile
+	// Similarity holds a score in [0, 1] for each file (parallel to Files), measured
+	// against the first file of the group. Nil for exact duplicates, which are all 1.
+	Similarity []float64
+	// Dimensions holds the width and height of each file (parallel to Files) for
+	// scaled duplicates and bursts, zero where unknown. Nil otherwise.
+	Dimensions []Dimensions
+	// Recommended is the index of the file worth keeping in groups with Dimensions:
+	// the original of scaled duplicates, the best frame of a burst
+	Recommended int
+	// TakenAt holds the capture time of each file (parallel to Files) for bursts
+	TakenAt []time.Time
+}
+
+// Dimensions is the resolution of an image in pixels
+type Dimensions struct {
+	Width  int
+	Height int
 }
 
 // SupportedExtensions contains all supported image file extensions
@@ -34,6 +69,15 @@ var SupportedExtensions = map[string]bool{
 	".tiff": true,
 	".tif":  true,
 	".webp": true,
+	".heic": true,
+	".heif": true,
+	".avif": true,
+	// Camera RAW, read through the embedded JPEG preview
+	".cr2": true,
+	".nef": true,
+	".arw": true,
+	".dng": true,
+	".orf": true,
 }
 
 // IsImageFile checks if a file is a supported image based on extension
@@ -42,6 +86,21 @@ func IsImageFile(path string) bool {
 	return SupportedExtensions[ext]
 }
 
+// VideoExtensions contains the video file extensions scanned when video
+// duplicate detection is enabled
+var VideoExtensions = map[string]bool{
+	".mp4": true,
+	".mov": true,
+	".avi": true,
+	".mkv": true,
+}
+
+// IsVideoFile checks if a file is a supported video based on extension
+func IsVideoFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return VideoExtensions[ext]
+}
+
 // ImageMetadata stores extracted EXIF metadata and geolocation for an image
 type ImageMetadata struct {
 	ID           uint       `gorm:"primaryKey" json:"id"`
@@ -81,9 +140,61 @@ type AppSettings struct {
 	TrashDir           string    `gorm:"default:''" json:"trashDir"`
 	ThumbnailCachePath string    `gorm:"default:''" json:"thumbnailCachePath"`
 	ThumbnailCacheSize int       `gorm:"default:0" json:"thumbnailCacheSize"`
+	ScanSchedule       string    `gorm:"default:''" json:"scanSchedule"` // Cron expression, empty = disabled
 	UpdatedAt          time.Time `json:"updatedAt"`
 }
 
+// Scan run triggers and statuses
+const (
+	ScanTriggerManual    = "manual"
+	ScanTriggerScheduled = "scheduled"
+
+	ScanRunRunning   = "running"
+	ScanRunCompleted = "completed"
+	ScanRunCancelled = "cancelled"
+	ScanRunFailed    = "failed"
+)
+
+// ScanRun records a single scan started through the scan manager
+type ScanRun struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Kind           string     `gorm:"not null;default:scan" json:"kind"` // scan job kind, e.g. scan or fast-scan
+	Trigger        string     `gorm:"not null" json:"trigger"`           // manual or scheduled
+	Status         string     `gorm:"not null;index" json:"status"`
+	Directories    string     `gorm:"type:text" json:"directories"` // scanned directories, one per line
+	FilesProcessed int        `json:"filesProcessed"`
+	FilesTotal     int        `json:"filesTotal"` // candidate files the walk found, the estimate for the next run
+	FilesAdded     int        `json:"filesAdded"`
+	FilesUpdated   int        `json:"filesUpdated"`
+	FilesRemoved   int        `json:"filesRemoved"`
+	Errors         int        `json:"errors"`                 // files that could not be read or hashed
+	Error          string     `gorm:"type:text" json:"error"` // reason the run failed
+	StartedAt      time.Time  `gorm:"not null;index" json:"startedAt"`
+	FinishedAt     *time.Time `json:"finishedAt"`
+}
+
+// Deletion records a file moved to the trash folder, so it can be restored to its
+// original location. Files removed in one request share a BatchID. The index fields
+// are kept so a restored file is back in the index without rehashing.
+type Deletion struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	BatchID      string     `gorm:"not null;index" json:"batchId"`
+	OriginalPath string     `gorm:"not null" json:"originalPath"`
+	TrashPath    string     `gorm:"not null;index" json:"trashPath"`
+	Size         int64      `json:"size"`
+	Hash         string     `json:"hash"`
+	PrefixHash   string     `json:"prefixHash"`
+	HashAlgo     string     `json:"hashAlgo"`
+	PHash        string     `json:"pHash"`
+	PHashAlgo    string     `json:"pHashAlgo"`
+	PHashXform   string     `json:"pHashXform"`
+	PixelHash    string     `json:"pixelHash"`
+	ModTime      time.Time  `json:"modTime"`
+	IsVideo      bool       `json:"isVideo"`
+	DeletedAt    time.Time  `gorm:"not null;index" json:"deletedAt"`
+	RestoredAt   *time.Time `json:"restoredAt"`
+}
+
 // OcrClassification stores OCR classification results for an image
 type OcrClassification struct {
 	ID                 uint      `gorm:"primaryKey" json:"id"`
@@ -139,3 +250,58 @@ type OcrLlmRecognition struct {
 	CreatedAt           time.Time `json:"createdAt"`
 	UpdatedAt           time.Time `json:"updatedAt"`
 }
+
+// Selection actions
+const (
+	SelectionKeep   = "keep"
+	SelectionDelete = "delete"
+)
+
+// UserSelectionKeyPrefix starts the SessionKey of selections made by clients that
+// authenticate with a header (API token or basic auth) rather than a session; the
+// user ID follows it
+const UserSelectionKeyPrefix = "user:"
+
+// Selection is a file marked to keep or delete in the duplicate view. Marks are
+// stored per session, so they survive paging and restarts; SessionKey is the
+// stored form of the session token.
+type Selection struct {
+	ID         uint      `gorm:"primaryKey" json:"-"`
+	SessionKey string    `gorm:"size:255;not null;uniqueIndex:idx_selection_session_path" json:"-"`
+	Path       string    `gorm:"not null;uniqueIndex:idx_selection_session_path" json:"path"`
+	Action     string    `gorm:"size:16;not null" json:"action"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// IgnoredGroup is an exact duplicate group marked as "not duplicates", such as
+// intentional copies. The group is identified by the content of its files, so it
+// stays hidden from the duplicate list whichever copies are added or removed later.
+// Path and Files describe the group when it was ignored.
+type IgnoredGroup struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	HashAlgo  string    `gorm:"size:16;not null;uniqueIndex:idx_ignored_group" json:"hashAlgo"`
+	Hash      string    `gorm:"size:128;not null;uniqueIndex:idx_ignored_group" json:"hash"`
+	Size      int64     `gorm:"not null;uniqueIndex:idx_ignored_group" json:"size"`
+	Path      string    `gorm:"not null" json:"path"`
+	Files     int       `json:"files"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Review statuses of exact duplicate groups; a group without a GroupReview is
+// unreviewed
+const (
+	ReviewReviewed = "reviewed"
+	ReviewDeferred = "deferred"
+)
+
+// GroupReview records that an exact duplicate group was handled or put off in a
+// cleanup session, so later sessions can skip it. Like IgnoredGroup, the group is
+// identified by the content of its files.
+type GroupReview struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	HashAlgo  string    `gorm:"size:16;not null;uniqueIndex:idx_group_review" json:"hashAlgo"`
+	Hash      string    `gorm:"size:128;not null;uniqueIndex:idx_group_review" json:"hash"`
+	Size      int64     `gorm:"not null;uniqueIndex:idx_group_review" json:"size"`
+	Status    string    `gorm:"size:16;not null;index" json:"status"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
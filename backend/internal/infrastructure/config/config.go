@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -9,19 +11,62 @@ import (
 
 // AppConfig holds all application configuration
 type AppConfig struct {
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
-	DBName     string
+	DatabaseURL string // postgres:// URL or sqlite:<path>, overrides the DB_* settings below
+	DBBackend   string // "postgres" or "sqlite:<path>"
+	DBHost      string
+	DBPort      string
+	DBUser      string
+	DBPassword  string
+	DBName      string
+
+	// Connection pool of the database; zero values keep the driver defaults and pool
+	// parameters of DatabaseURL take precedence
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime string // e.g. "30m"
+	DBConnMaxIdleTime string // e.g. "5m"
+	DBConnectTimeout  string // How long to retry connecting to PostgreSQL at startup, e.g. "30s"
 
 	ServerHost  string
 	ServerPort  string
+	GRPCPort    string // port of the gRPC API, empty = disabled
 	CORSOrigins []string
 
-	ScanWorkers         int
-	MetadataWorkers     int
-	MetadataIntervalMin int
+	// HTTPS: certificate and key files; with TLSSelfSigned a certificate is
+	// generated (at these paths, or a default location if empty)
+	TLSCertFile   string
+	TLSKeyFile    string
+	TLSSelfSigned bool
+
+	LogLevel  string // debug, info, warn or error
+	LogFormat string // text or json
+
+	ScanWorkers          int
+	ContentHashAlgo      string // md5, sha256, xxh64 or blake3
+	TwoStageHashEnabled  bool   // Hash the first 64KB first, full hash only on collision
+	SizePrefilter        bool   // Defer hashing of new files until their size is seen twice
+	VideoScanEnabled     bool   // Include video files (exact duplicates only)
+	MinFileSize          string // Skip smaller files, e.g. "50KB" (empty = no limit)
+	MaxFileSize          string // Skip larger files, e.g. "2GB" (empty = no limit)
+	MaxScanDepth         int    // Subdirectory levels scanned below each gallery folder (0 = unlimited)
+	FollowSymlinks       bool   // Follow symlinked files and directories during scans
+	IncludeHidden        bool   // Scan dot files/directories and NAS junk directories (@eaDir, #recycle, ...)
+	CollapseHardlinks    bool   // Count hardlinks of one file as a single file in duplicate lookups
+	CaseInsensitivePaths bool   // Compare paths regardless of case (Windows and macOS filesystems)
+	ScanThrottle         string // Max rate file content is read at while hashing, e.g. "50MB/s" (empty = no limit)
+	ScanIdlePriority     bool   // Run at idle CPU and disk priority
+	ScanMemoryLimit      string // Max memory of the images decoded at once while hashing, e.g. "2GB" (empty = no limit)
+	IndexRetentionDays   int    // Days records of removed files are kept, so a file that comes back is not hashed again
+	MetadataWorkers      int
+	MetadataIntervalMin  int
+
+	// Perceptual hashing for near-duplicate detection
+	PerceptualHashEnabled bool
+	PerceptualHashAlgo    string // ahash, dhash or phash
+	PerceptualRotations   bool   // Match rotated and mirrored copies as similar
+	SimilarityThreshold   int    // Max Hamming distance for near-duplicates, overridable per request
+	BurstWindowSeconds    int    // Max gap between consecutive shots of a burst, overridable per request
+	PixelHashEnabled      bool   // Hash decoded pixels to group identical pictures in different files
 
 	// OCR classifier configuration
 	OCREnabled            bool
@@ -35,6 +80,10 @@ type AppConfig struct {
 	BootstrapPassword   string
 	SessionIdleHours    int
 	SessionAbsoluteDays int
+	// Non-browser clients: static bearer token and HTTP basic auth with user credentials
+	APIToken         string // empty = disabled
+	APITokenUser     string // login the token acts as; empty = first administrator
+	BasicAuthEnabled bool
 
 	// Thumbnail cache configuration
 	ThumbnailCacheEnabled       bool
@@ -42,10 +91,98 @@ type AppConfig struct {
 	ThumbnailCacheMaxSize       int
 	ThumbnailCacheQuality       int
 	ThumbnailCachePreloadOnScan bool
+	ThumbnailMemoryEntries      int // Thumbnails kept in memory when the disk cache is unavailable, 0 = unlimited
+	ThumbnailMemoryMB           int // Memory for those thumbnails in MB, 0 = unlimited
 
 	// Background sync configuration
 	BackgroundSyncEnabled     bool
 	BackgroundSyncIntervalMin int
+
+	// Filesystem watch configuration
+	WatchEnabled bool
+
+	// Scheduled scans (cron expression, empty = disabled; a schedule saved via the API wins)
+	ScanSchedule string
+
+	// Gallery folders added on startup (existing folders are kept)
+	ScanDirectories []string
+	// Glob patterns of files and directories skipped by scans, matched against the base
+	// name, or against the full path when the pattern contains a slash
+	ExcludePatterns []string
+	// Trash directory applied to the settings on startup (empty = keep the saved one)
+	TrashDir string
+
+	// S3 and S3-compatible stores of gallery folders given as s3://bucket/prefix
+	S3Endpoint        string // Custom endpoint (MinIO etc.), addressed path-style; empty = AWS
+	S3Region          string
+	S3AccessKeyID     string // empty = anonymous access
+	S3SecretAccessKey string
+	S3SessionToken    string
+
+	// Basic auth credentials of WebDAV gallery folders (webdav:// or webdavs://), e.g. a Nextcloud app password
+	WebDAVUsername string
+	WebDAVPassword string
+
+	// SSH credentials of SFTP gallery folders (sftp://user@host/path)
+	SFTPUser           string // Login for URLs without one
+	SFTPPassword       string
+	SFTPKeyFile        string
+	SFTPKnownHostsFile string // empty = ~/.ssh/known_hosts
+
+	// URLs receiving a JSON POST after each scan and batch delete, signed with the
+	// secret (HMAC-SHA256) when one is set
+	WebhookURLs   []string
+	WebhookSecret string
+
+	// SMTP server of the reports emailed after scheduled scans to ReportEmailTo
+	// (empty = no reports); port 465 is implicit TLS, others use STARTTLS when offered
+	SMTPHost      string
+	SMTPPort      int
+	SMTPUsername  string
+	SMTPPassword  string
+	SMTPFrom      string
+	ReportEmailTo []string
+
+	// Telegram bot sending scan summaries and deletion plans to approve (empty token =
+	// disabled); only the listed chats receive messages and may command the bot
+	TelegramBotToken     string
+	TelegramChatIDs      []string
+	TelegramKeepPolicies []string // keep policies of the deletion plans, applied in order
+
+	// ffmpeg binary for formats without a Go decoder (AVIF, video frames); a bare name is looked up in PATH
+	FFmpegPath string
+}
+
+// fileValues holds options read from the configuration file, keyed by environment variable name
+var fileValues map[string]string
+
+// Load reads the optional configuration file at path, then the environment, which
+// overrides file values. An empty path reads the environment only.
+func Load(path string) (*AppConfig, error) {
+	fileValues = nil
+	if path != "" {
+		fc, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fileValues = fc.values()
+	}
+
+	cfg := LoadConfig()
+	if err := ValidateExcludePatterns(cfg.ExcludePatterns); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ValidateExcludePatterns checks the syntax of scan exclude patterns
+func ValidateExcludePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
 }
 
 // LoadConfig reads configuration from environment variables
@@ -81,17 +218,51 @@ func LoadConfig() *AppConfig {
 	}
 
 	return &AppConfig{
+		DatabaseURL:                 getEnv("DATABASE_URL", ""),
+		DBBackend:                   getEnv("DB_BACKEND", "postgres"),
 		DBHost:                      getEnv("DB_HOST", "localhost"),
 		DBPort:                      getEnv("DB_PORT", "5432"),
 		DBUser:                      getEnv("DB_USER", "postgres"),
 		DBPassword:                  getEnv("DB_PASSWORD", "postgres"),
 		DBName:                      getEnv("DB_NAME", "image_dedup"),
+		DBMaxOpenConns:              getEnvInt("DB_MAX_OPEN_CONNS", 0),
+		DBMaxIdleConns:              getEnvInt("DB_MAX_IDLE_CONNS", 0),
+		DBConnMaxLifetime:           getEnv("DB_CONN_MAX_LIFETIME", ""),
+		DBConnMaxIdleTime:           getEnv("DB_CONN_MAX_IDLE_TIME", ""),
+		DBConnectTimeout:            getEnv("DB_CONNECT_TIMEOUT", "30s"),
 		ServerHost:                  getEnv("SERVER_HOST", "0.0.0.0"),
 		ServerPort:                  getEnv("SERVER_PORT", "5170"),
+		GRPCPort:                    getEnv("GRPC_PORT", ""),
 		CORSOrigins:                 origins,
+		TLSCertFile:                 getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:                  getEnv("TLS_KEY_FILE", ""),
+		TLSSelfSigned:               getEnv("TLS_SELF_SIGNED", "false") == "true",
+		LogLevel:                    getEnv("LOG_LEVEL", "info"),
+		LogFormat:                   getEnv("LOG_FORMAT", "text"),
 		ScanWorkers:                 scanWorkers,
+		ContentHashAlgo:             getEnv("CONTENT_HASH_ALGO", "md5"),
+		TwoStageHashEnabled:         getEnv("TWO_STAGE_HASH_ENABLED", "true") == "true",
+		SizePrefilter:               getEnv("SIZE_PREFILTER_ENABLED", "false") == "true",
+		VideoScanEnabled:            getEnv("VIDEO_SCAN_ENABLED", "false") == "true",
+		MinFileSize:                 getEnv("MIN_FILE_SIZE", ""),
+		MaxFileSize:                 getEnv("MAX_FILE_SIZE", ""),
+		MaxScanDepth:                getEnvInt("MAX_SCAN_DEPTH", 0),
+		FollowSymlinks:              getEnv("FOLLOW_SYMLINKS", "false") == "true",
+		IncludeHidden:               getEnv("INCLUDE_HIDDEN", "false") == "true",
+		CollapseHardlinks:           getEnv("COLLAPSE_HARDLINKS", "false") == "true",
+		CaseInsensitivePaths:        getEnv("CASE_INSENSITIVE_PATHS", "false") == "true",
+		ScanThrottle:                getEnv("SCAN_THROTTLE", ""),
+		ScanIdlePriority:            getEnv("SCAN_IDLE_PRIORITY", "false") == "true",
+		ScanMemoryLimit:             getEnv("SCAN_MEMORY_LIMIT", ""),
+		IndexRetentionDays:          getEnvInt("INDEX_RETENTION_DAYS", 30),
 		MetadataWorkers:             metadataWorkers,
 		MetadataIntervalMin:         metadataInterval,
+		PerceptualHashEnabled:       getEnv("PERCEPTUAL_HASH_ENABLED", "true") == "true",
+		PerceptualHashAlgo:          getEnv("PERCEPTUAL_HASH_ALGO", "phash"),
+		PerceptualRotations:         getEnv("PERCEPTUAL_HASH_ROTATIONS", "false") == "true",
+		SimilarityThreshold:         getEnvInt("SIMILARITY_THRESHOLD", 10),
+		BurstWindowSeconds:          getEnvInt("BURST_WINDOW_SECONDS", 5),
+		PixelHashEnabled:            getEnv("PIXEL_HASH_ENABLED", "false") == "true",
 		OCREnabled:                  getEnv("OCR_ENABLED", "true") == "true",
 		OCRHost:                     getEnv("OCR_HOST", "localhost"),
 		OCRPort:                     getEnv("OCR_PORT", "8080"),
@@ -101,19 +272,63 @@ func LoadConfig() *AppConfig {
 		BootstrapPassword:           getEnv("BOOTSTRAP_PASSWORD", "admin"),
 		SessionIdleHours:            getEnvInt("SESSION_IDLE_HOURS", 720),   // 30 days
 		SessionAbsoluteDays:         getEnvInt("SESSION_ABSOLUTE_DAYS", 90), // 90 days
+		APIToken:                    getEnv("API_TOKEN", ""),
+		APITokenUser:                getEnv("API_TOKEN_USER", ""),
+		BasicAuthEnabled:            getEnv("BASIC_AUTH_ENABLED", "false") == "true",
 		ThumbnailCacheEnabled:       getEnv("THUMBNAIL_CACHE_ENABLED", "true") == "true",
 		ThumbnailCachePath:          getEnv("THUMBNAIL_CACHE_PATH", ""),
 		ThumbnailCacheMaxSize:       getEnvInt("THUMBNAIL_CACHE_MAX_SIZE", 320),
 		ThumbnailCacheQuality:       getEnvInt("THUMBNAIL_CACHE_QUALITY", 80),
-		ThumbnailCachePreloadOnScan: getEnv("THUMBNAIL_CACHE_PRELOAD_ON_SCAN", "true") == "true",
+		ThumbnailCachePreloadOnScan: getEnv("THUMBNAIL_CACHE_PRELOAD_ON_SCAN", "false") == "true",
+		ThumbnailMemoryEntries:      getEnvInt("THUMBNAIL_MEMORY_CACHE_ENTRIES", 10000),
+		ThumbnailMemoryMB:           getEnvInt("THUMBNAIL_MEMORY_CACHE_MB", 128),
 		BackgroundSyncEnabled:       getEnv("BACKGROUND_SYNC_ENABLED", "true") == "true",
 		BackgroundSyncIntervalMin:   getEnvInt("BACKGROUND_SYNC_INTERVAL_MIN", 60*12), // 12 hours
+		WatchEnabled:                getEnv("WATCH_ENABLED", "false") == "true",
+		ScanSchedule:                getEnv("SCAN_SCHEDULE", ""),
+		ScanDirectories:             getEnvList("SCAN_DIRECTORIES"),
+		ExcludePatterns:             getEnvList("SCAN_EXCLUDE"),
+		TrashDir:                    getEnv("TRASH_DIR", ""),
+		S3Endpoint:                  getEnv("S3_ENDPOINT", ""),
+		S3Region:                    getEnv("S3_REGION", getEnv("AWS_REGION", "us-east-1")),
+		S3AccessKeyID:               getEnv("S3_ACCESS_KEY_ID", getEnv("AWS_ACCESS_KEY_ID", "")),
+		S3SecretAccessKey:           getEnv("S3_SECRET_ACCESS_KEY", getEnv("AWS_SECRET_ACCESS_KEY", "")),
+		S3SessionToken:              getEnv("S3_SESSION_TOKEN", getEnv("AWS_SESSION_TOKEN", "")),
+		WebDAVUsername:              getEnv("WEBDAV_USERNAME", ""),
+		WebDAVPassword:              getEnv("WEBDAV_PASSWORD", ""),
+		SFTPUser:                    getEnv("SFTP_USER", ""),
+		SFTPPassword:                getEnv("SFTP_PASSWORD", ""),
+		SFTPKeyFile:                 getEnv("SFTP_KEY_FILE", ""),
+		SFTPKnownHostsFile:          getEnv("SFTP_KNOWN_HOSTS", ""),
+		WebhookURLs:                 getEnvList("WEBHOOK_URLS"),
+		WebhookSecret:               getEnv("WEBHOOK_SECRET", ""),
+		SMTPHost:                    getEnv("SMTP_HOST", ""),
+		SMTPPort:                    getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:                getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:                getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:                    getEnv("SMTP_FROM", ""),
+		ReportEmailTo:               getEnvList("REPORT_EMAIL_TO"),
+		TelegramBotToken:            getEnv("TELEGRAM_BOT_TOKEN", ""),
+		TelegramChatIDs:             getEnvList("TELEGRAM_CHAT_IDS"),
+		TelegramKeepPolicies:        getEnvList("TELEGRAM_KEEP_POLICIES"),
+		FFmpegPath:                  getEnv("FFMPEG_PATH", "ffmpeg"),
 	}
 }
 
+// getEnvList gets a comma-separated environment variable as a list, skipping empty items
+func getEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(getEnv(key, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // getEnvInt gets environment variable as int with a default value
 func getEnvInt(key string, defaultValue int) int {
-	if value, exists := os.LookupEnv(key); exists {
+	if value, exists := lookup(key); exists {
 		if n, err := strconv.Atoi(value); err == nil {
 			return n
 		}
@@ -123,8 +338,17 @@ func getEnvInt(key string, defaultValue int) int {
 
 // getEnv gets environment variable with a default value
 func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
+	if value, exists := lookup(key); exists {
 		return value
 	}
 	return defaultValue
 }
+
+// lookup returns the environment variable, falling back to the configuration file
+func lookup(key string) (string, bool) {
+	if value, exists := os.LookupEnv(key); exists {
+		return value, true
+	}
+	value, exists := fileValues[key]
+	return value, exists
+}
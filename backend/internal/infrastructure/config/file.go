@@ -0,0 +1,262 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig is the optional configuration file (YAML, or TOML for *.toml files).
+// Every value is equivalent to an environment variable; environment variables take
+// precedence over the file, and command line flags over both.
+type FileConfig struct {
+	Database struct {
+		URL      string `yaml:"url" toml:"url"`         // DATABASE_URL
+		Backend  string `yaml:"backend" toml:"backend"` // DB_BACKEND
+		Host     string `yaml:"host" toml:"host"`       // DB_HOST
+		Port     int    `yaml:"port" toml:"port"`       // DB_PORT
+		User     string `yaml:"user" toml:"user"`       // DB_USER
+		Password string `yaml:"password" toml:"password"`
+		Name     string `yaml:"name" toml:"name"`
+
+		MaxOpenConns    int    `yaml:"max_open_conns" toml:"max_open_conns"`         // DB_MAX_OPEN_CONNS
+		MaxIdleConns    int    `yaml:"max_idle_conns" toml:"max_idle_conns"`         // DB_MAX_IDLE_CONNS
+		ConnMaxLifetime string `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`   // DB_CONN_MAX_LIFETIME
+		ConnMaxIdleTime string `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"` // DB_CONN_MAX_IDLE_TIME
+		ConnectTimeout  string `yaml:"connect_timeout" toml:"connect_timeout"`       // DB_CONNECT_TIMEOUT
+	} `yaml:"database" toml:"database"`
+
+	Server struct {
+		Host        string   `yaml:"host" toml:"host"`           // SERVER_HOST
+		Port        int      `yaml:"port" toml:"port"`           // SERVER_PORT
+		GRPCPort    int      `yaml:"grpc_port" toml:"grpc_port"` // GRPC_PORT
+		CORSOrigins []string `yaml:"cors_origins" toml:"cors_origins"`
+		TLSCert     string   `yaml:"tls_cert" toml:"tls_cert"`               // TLS_CERT_FILE
+		TLSKey      string   `yaml:"tls_key" toml:"tls_key"`                 // TLS_KEY_FILE
+		SelfSigned  bool     `yaml:"tls_self_signed" toml:"tls_self_signed"` // TLS_SELF_SIGNED
+	} `yaml:"server" toml:"server"`
+
+	Log struct {
+		Level  string `yaml:"level" toml:"level"`   // LOG_LEVEL
+		Format string `yaml:"format" toml:"format"` // LOG_FORMAT
+	} `yaml:"log" toml:"log"`
+
+	Auth struct {
+		APIToken     string `yaml:"api_token" toml:"api_token"`           // API_TOKEN
+		APITokenUser string `yaml:"api_token_user" toml:"api_token_user"` // API_TOKEN_USER
+		BasicAuth    bool   `yaml:"basic_auth" toml:"basic_auth"`         // BASIC_AUTH_ENABLED
+	} `yaml:"auth" toml:"auth"`
+
+	Scan struct {
+		Directories []string `yaml:"directories" toml:"directories"` // SCAN_DIRECTORIES
+		Exclude     []string `yaml:"exclude" toml:"exclude"`         // SCAN_EXCLUDE
+		Workers     int      `yaml:"workers" toml:"workers"`         // SCAN_WORKERS
+		ContentHash string   `yaml:"content_hash_algo" toml:"content_hash_algo"`
+		Schedule    string   `yaml:"schedule" toml:"schedule"`
+		Videos      bool     `yaml:"videos" toml:"videos"`                                 // VIDEO_SCAN_ENABLED
+		MinSize     string   `yaml:"min_size" toml:"min_size"`                             // MIN_FILE_SIZE
+		MaxSize     string   `yaml:"max_size" toml:"max_size"`                             // MAX_FILE_SIZE
+		MaxDepth    int      `yaml:"max_depth" toml:"max_depth"`                           // MAX_SCAN_DEPTH
+		Symlinks    bool     `yaml:"follow_symlinks" toml:"follow_symlinks"`               // FOLLOW_SYMLINKS
+		Hidden      bool     `yaml:"include_hidden" toml:"include_hidden"`                 // INCLUDE_HIDDEN
+		Hardlinks   bool     `yaml:"collapse_hardlinks" toml:"collapse_hardlinks"`         // COLLAPSE_HARDLINKS
+		IgnoreCase  bool     `yaml:"case_insensitive_paths" toml:"case_insensitive_paths"` // CASE_INSENSITIVE_PATHS
+		Throttle    string   `yaml:"throttle" toml:"throttle"`                             // SCAN_THROTTLE
+		IdlePrio    bool     `yaml:"idle_priority" toml:"idle_priority"`                   // SCAN_IDLE_PRIORITY
+		MemoryLimit string   `yaml:"memory_limit" toml:"memory_limit"`                     // SCAN_MEMORY_LIMIT
+		Retention   int      `yaml:"retention_days" toml:"retention_days"`                 // INDEX_RETENTION_DAYS
+	} `yaml:"scan" toml:"scan"`
+
+	Metadata struct {
+		Workers int `yaml:"workers" toml:"workers"` // METADATA_WORKERS
+	} `yaml:"metadata" toml:"metadata"`
+
+	OCR struct {
+		ConcurrentRequests int `yaml:"concurrent_requests" toml:"concurrent_requests"`
+	} `yaml:"ocr" toml:"ocr"`
+
+	S3 struct {
+		Endpoint        string `yaml:"endpoint" toml:"endpoint"`                   // S3_ENDPOINT
+		Region          string `yaml:"region" toml:"region"`                       // S3_REGION
+		AccessKeyID     string `yaml:"access_key_id" toml:"access_key_id"`         // S3_ACCESS_KEY_ID
+		SecretAccessKey string `yaml:"secret_access_key" toml:"secret_access_key"` // S3_SECRET_ACCESS_KEY
+	} `yaml:"s3" toml:"s3"`
+
+	WebDAV struct {
+		Username string `yaml:"username" toml:"username"` // WEBDAV_USERNAME
+		Password string `yaml:"password" toml:"password"` // WEBDAV_PASSWORD
+	} `yaml:"webdav" toml:"webdav"`
+
+	SFTP struct {
+		User       string `yaml:"user" toml:"user"`               // SFTP_USER
+		Password   string `yaml:"password" toml:"password"`       // SFTP_PASSWORD
+		KeyFile    string `yaml:"key_file" toml:"key_file"`       // SFTP_KEY_FILE
+		KnownHosts string `yaml:"known_hosts" toml:"known_hosts"` // SFTP_KNOWN_HOSTS
+	} `yaml:"sftp" toml:"sftp"`
+
+	Webhooks struct {
+		URLs   []string `yaml:"urls" toml:"urls"`     // WEBHOOK_URLS
+		Secret string   `yaml:"secret" toml:"secret"` // WEBHOOK_SECRET
+	} `yaml:"webhooks" toml:"webhooks"`
+
+	SMTP struct {
+		Host     string   `yaml:"host" toml:"host"`           // SMTP_HOST
+		Port     int      `yaml:"port" toml:"port"`           // SMTP_PORT
+		Username string   `yaml:"username" toml:"username"`   // SMTP_USERNAME
+		Password string   `yaml:"password" toml:"password"`   // SMTP_PASSWORD
+		From     string   `yaml:"from" toml:"from"`           // SMTP_FROM
+		ReportTo []string `yaml:"report_to" toml:"report_to"` // REPORT_EMAIL_TO
+	} `yaml:"smtp" toml:"smtp"`
+
+	Telegram struct {
+		BotToken     string   `yaml:"bot_token" toml:"bot_token"`         // TELEGRAM_BOT_TOKEN
+		ChatIDs      []int64  `yaml:"chat_ids" toml:"chat_ids"`           // TELEGRAM_CHAT_IDS
+		KeepPolicies []string `yaml:"keep_policies" toml:"keep_policies"` // TELEGRAM_KEEP_POLICIES
+	} `yaml:"telegram" toml:"telegram"`
+
+	TrashDir   string `yaml:"trash_dir" toml:"trash_dir"`     // TRASH_DIR
+	FFmpegPath string `yaml:"ffmpeg_path" toml:"ffmpeg_path"` // FFMPEG_PATH
+}
+
+// LoadFile parses a configuration file. The format is chosen by extension:
+// .toml is TOML, anything else is YAML. Unknown keys are rejected to catch typos.
+func LoadFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var fc FileConfig
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		md, err := toml.Decode(string(data), &fc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("failed to parse %s: unknown key %q", path, undecoded[0].String())
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&fc); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	return &fc, nil
+}
+
+// values maps the options set in the file to their environment variable names
+func (fc *FileConfig) values() map[string]string {
+	v := make(map[string]string)
+	setString := func(key, value string) {
+		if value != "" {
+			v[key] = value
+		}
+	}
+	setInt := func(key string, value int) {
+		if value != 0 {
+			v[key] = strconv.Itoa(value)
+		}
+	}
+	setList := func(key string, values []string) {
+		if len(values) > 0 {
+			v[key] = strings.Join(values, ",")
+		}
+	}
+
+	setString("DATABASE_URL", fc.Database.URL)
+	setString("DB_BACKEND", fc.Database.Backend)
+	setString("DB_HOST", fc.Database.Host)
+	setInt("DB_PORT", fc.Database.Port)
+	setString("DB_USER", fc.Database.User)
+	setString("DB_PASSWORD", fc.Database.Password)
+	setString("DB_NAME", fc.Database.Name)
+	setInt("DB_MAX_OPEN_CONNS", fc.Database.MaxOpenConns)
+	setInt("DB_MAX_IDLE_CONNS", fc.Database.MaxIdleConns)
+	setString("DB_CONN_MAX_LIFETIME", fc.Database.ConnMaxLifetime)
+	setString("DB_CONN_MAX_IDLE_TIME", fc.Database.ConnMaxIdleTime)
+	setString("DB_CONNECT_TIMEOUT", fc.Database.ConnectTimeout)
+	setString("SERVER_HOST", fc.Server.Host)
+	setInt("SERVER_PORT", fc.Server.Port)
+	setInt("GRPC_PORT", fc.Server.GRPCPort)
+	setList("CORS_ORIGINS", fc.Server.CORSOrigins)
+	setString("TLS_CERT_FILE", fc.Server.TLSCert)
+	setString("TLS_KEY_FILE", fc.Server.TLSKey)
+	if fc.Server.SelfSigned {
+		v["TLS_SELF_SIGNED"] = "true"
+	}
+	setString("LOG_LEVEL", fc.Log.Level)
+	setString("LOG_FORMAT", fc.Log.Format)
+	setString("API_TOKEN", fc.Auth.APIToken)
+	setString("API_TOKEN_USER", fc.Auth.APITokenUser)
+	if fc.Auth.BasicAuth {
+		v["BASIC_AUTH_ENABLED"] = "true"
+	}
+	setList("SCAN_DIRECTORIES", fc.Scan.Directories)
+	setList("SCAN_EXCLUDE", fc.Scan.Exclude)
+	setInt("SCAN_WORKERS", fc.Scan.Workers)
+	setString("CONTENT_HASH_ALGO", fc.Scan.ContentHash)
+	setString("SCAN_SCHEDULE", fc.Scan.Schedule)
+	if fc.Scan.Videos {
+		v["VIDEO_SCAN_ENABLED"] = "true"
+	}
+	setString("MIN_FILE_SIZE", fc.Scan.MinSize)
+	setString("MAX_FILE_SIZE", fc.Scan.MaxSize)
+	setInt("MAX_SCAN_DEPTH", fc.Scan.MaxDepth)
+	if fc.Scan.Symlinks {
+		v["FOLLOW_SYMLINKS"] = "true"
+	}
+	if fc.Scan.Hidden {
+		v["INCLUDE_HIDDEN"] = "true"
+	}
+	if fc.Scan.Hardlinks {
+		v["COLLAPSE_HARDLINKS"] = "true"
+	}
+	if fc.Scan.IgnoreCase {
+		v["CASE_INSENSITIVE_PATHS"] = "true"
+	}
+	setString("SCAN_THROTTLE", fc.Scan.Throttle)
+	if fc.Scan.IdlePrio {
+		v["SCAN_IDLE_PRIORITY"] = "true"
+	}
+	setString("SCAN_MEMORY_LIMIT", fc.Scan.MemoryLimit)
+	setInt("INDEX_RETENTION_DAYS", fc.Scan.Retention)
+	setInt("METADATA_WORKERS", fc.Metadata.Workers)
+	setInt("OCR_CONCURRENT_REQUESTS", fc.OCR.ConcurrentRequests)
+	setString("S3_ENDPOINT", fc.S3.Endpoint)
+	setString("S3_REGION", fc.S3.Region)
+	setString("S3_ACCESS_KEY_ID", fc.S3.AccessKeyID)
+	setString("S3_SECRET_ACCESS_KEY", fc.S3.SecretAccessKey)
+	setString("WEBDAV_USERNAME", fc.WebDAV.Username)
+	setString("WEBDAV_PASSWORD", fc.WebDAV.Password)
+	setString("SFTP_USER", fc.SFTP.User)
+	setString("SFTP_PASSWORD", fc.SFTP.Password)
+	setString("SFTP_KEY_FILE", fc.SFTP.KeyFile)
+	setString("SFTP_KNOWN_HOSTS", fc.SFTP.KnownHosts)
+	setList("WEBHOOK_URLS", fc.Webhooks.URLs)
+	setString("WEBHOOK_SECRET", fc.Webhooks.Secret)
+	setString("SMTP_HOST", fc.SMTP.Host)
+	setInt("SMTP_PORT", fc.SMTP.Port)
+	setString("SMTP_USERNAME", fc.SMTP.Username)
+	setString("SMTP_PASSWORD", fc.SMTP.Password)
+	setString("SMTP_FROM", fc.SMTP.From)
+	setList("REPORT_EMAIL_TO", fc.SMTP.ReportTo)
+	setString("TELEGRAM_BOT_TOKEN", fc.Telegram.BotToken)
+	chatIDs := make([]string, len(fc.Telegram.ChatIDs))
+	for i, id := range fc.Telegram.ChatIDs {
+		chatIDs[i] = strconv.FormatInt(id, 10)
+	}
+	setList("TELEGRAM_CHAT_IDS", chatIDs)
+	setList("TELEGRAM_KEEP_POLICIES", fc.Telegram.KeepPolicies)
+	setString("TRASH_DIR", fc.TrashDir)
+	setString("FFMPEG_PATH", fc.FFmpegPath)
+	return v
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadYAMLWithEnvOverride(t *testing.T) {
+	path := writeConfigFile(t, "dedup.yaml", `
+database:
+  backend: sqlite:/data/dedup.db
+server:
+  port: 8080
+scan:
+  directories: [/photos, /archive]
+  exclude: ["@eaDir", "*.tmp"]
+  workers: 3
+telegram:
+  chat_ids: [123, -100456]
+trash_dir: /data/trash
+`)
+	t.Setenv("SCAN_WORKERS", "5")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DBBackend != "sqlite:/data/dedup.db" || cfg.ServerPort != "8080" || cfg.TrashDir != "/data/trash" {
+		t.Errorf("file values not applied: %+v", cfg)
+	}
+	if cfg.ScanWorkers != 5 {
+		t.Errorf("ScanWorkers = %d, want env value 5", cfg.ScanWorkers)
+	}
+	if !reflect.DeepEqual(cfg.ScanDirectories, []string{"/photos", "/archive"}) {
+		t.Errorf("ScanDirectories = %v", cfg.ScanDirectories)
+	}
+	if !reflect.DeepEqual(cfg.ExcludePatterns, []string{"@eaDir", "*.tmp"}) {
+		t.Errorf("ExcludePatterns = %v", cfg.ExcludePatterns)
+	}
+	if !reflect.DeepEqual(cfg.TelegramChatIDs, []string{"123", "-100456"}) {
+		t.Errorf("TelegramChatIDs = %v", cfg.TelegramChatIDs)
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	path := writeConfigFile(t, "dedup.toml", `
+trash_dir = "/trash"
+
+[database]
+host = "db.local"
+port = 6543
+max_open_conns = 20
+conn_max_lifetime = "30m"
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DBHost != "db.local" || cfg.DBPort != "6543" || cfg.TrashDir != "/trash" {
+		t.Errorf("file values not applied: host=%q port=%q trash=%q", cfg.DBHost, cfg.DBPort, cfg.TrashDir)
+	}
+	if cfg.DBMaxOpenConns != 20 || cfg.DBConnMaxLifetime != "30m" || cfg.DBConnectTimeout != "30s" {
+		t.Errorf("pool settings = %d, %q, connect timeout %q", cfg.DBMaxOpenConns, cfg.DBConnMaxLifetime, cfg.DBConnectTimeout)
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	cases := map[string]string{
+		"typo.yaml":    "server:\n  prot: 8080\n",
+		"typo.toml":    "[server]\nprot = 8080\n",
+		"pattern.yaml": "scan:\n  exclude: [\"[\"]\n",
+	}
+	for name, content := range cases {
+		if _, err := Load(writeConfigFile(t, name, content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestExampleConfigFileParses(t *testing.T) {
+	if _, err := Load(filepath.Join("..", "..", "..", "config.example.yaml")); err != nil {
+		t.Fatalf("config.example.yaml: %v", err)
+	}
+}
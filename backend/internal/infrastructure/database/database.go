@@ -2,46 +2,37 @@ package database
 
 import (
 	"fmt"
+	"log/slog"
+	"strings"
 
 	"image-toolkit/internal/domain"
 	"image-toolkit/internal/infrastructure/config"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-// InitDatabase initializes the database connection and runs migrations
-func InitDatabase(cfg *config.AppConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName,
-	)
+// sqlitePrefix selects the embedded SQLite backend in DB_BACKEND, e.g. "sqlite:/data/dedup.db"
+const sqlitePrefix = "sqlite:"
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
-	})
+// InitDatabase initializes the database connection, applies the pending migrations and
+// seeds the default settings
+func InitDatabase(cfg *config.AppConfig) (*gorm.DB, error) {
+	db, err := Open(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, err
 	}
 
-	if err := db.AutoMigrate(
-		&domain.ImageFile{},
-		&domain.GalleryFolder{},
-		&domain.AppSettings{},
-		&domain.ImageMetadata{},
-		&domain.User{},
-		&domain.UserSettings{},
-		&domain.Session{},
-		&domain.AuditLog{},
-		&domain.OcrClassification{},
-		&domain.OcrBoundingBox{},
-		&domain.LlmSettings{},
-		&domain.OcrLlmRecognition{},
-	); err != nil {
+	if err := Migrate(db); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if !IsSQLite(db) {
+		createPathSearchIndex(db)
+	}
+
 	// Seed default settings row if not exists
 	var count int64
 	db.Model(&domain.AppSettings{}).Count(&count)
@@ -64,3 +55,107 @@ func InitDatabase(cfg *config.AppConfig) (*gorm.DB, error) {
 
 	return db, nil
 }
+
+// Open connects to the configured database without migrating it. A PostgreSQL server
+// that is not ready yet is retried for DB_CONNECT_TIMEOUT.
+func Open(cfg *config.AppConfig) (*gorm.DB, error) {
+	dialector, pool, err := openDialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := parseDuration("DB_CONNECT_TIMEOUT", cfg.DBConnectTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if dialector.Name() == "sqlite" {
+		timeout = 0 // a local file is there or not, waiting doesn't help
+	}
+
+	db, err := connect(func() (*gorm.DB, error) {
+		return gorm.Open(dialector, &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+	}, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure database: %w", err)
+	}
+	if pool.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	if pool.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	}
+	if pool.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	}
+	return db, nil
+}
+
+// createPathSearchIndex adds the trigram index serving filename search on PostgreSQL.
+// The pg_trgm extension may be unavailable to the database user; search then falls
+// back to a sequential scan.
+func createPathSearchIndex(db *gorm.DB) {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		slog.Warn("Filename search index unavailable: cannot enable pg_trgm", "error", err)
+		return
+	}
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_image_files_path_trgm ON image_files USING gin (lower(path) gin_trgm_ops)").Error; err != nil {
+		slog.Warn("Failed to create filename search index", "error", err)
+	}
+}
+
+// openDialector picks the database driver and pool settings. DATABASE_URL wins when set
+// (postgres:// URL or sqlite:<path>); otherwise DB_BACKEND selects "postgres" (default),
+// built from the DB_* connection settings, or "sqlite:<path>" for a local database file.
+// The pool is configured by the DB_* pool settings, overridden by the pool parameters
+// of a DATABASE_URL.
+func openDialector(cfg *config.AppConfig) (gorm.Dialector, poolSettings, error) {
+	pool, err := configuredPool(cfg)
+	if err != nil {
+		return nil, poolSettings{}, err
+	}
+	if databaseURL := strings.TrimSpace(cfg.DatabaseURL); databaseURL != "" {
+		if path, ok := strings.CutPrefix(databaseURL, sqlitePrefix); ok {
+			return openSQLite(path, "DATABASE_URL", pool)
+		}
+		dsn, urlPool, err := parsePostgresURL(databaseURL)
+		if err != nil {
+			return nil, poolSettings{}, err
+		}
+		return postgres.Open(dsn), pool.override(urlPool), nil
+	}
+
+	backend := strings.TrimSpace(cfg.DBBackend)
+	if path, ok := strings.CutPrefix(backend, sqlitePrefix); ok {
+		return openSQLite(path, "DB_BACKEND", pool)
+	}
+	if backend != "" && backend != "postgres" {
+		return nil, poolSettings{}, fmt.Errorf("DB_BACKEND: unsupported value %q (expected postgres or sqlite:<path>)", backend)
+	}
+
+	dsn := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName,
+	)
+	return postgres.Open(dsn), pool, nil
+}
+
+// openSQLite opens a local database file. SQLite allows a single writer, so the pool is
+// limited to one connection to avoid "database is locked" errors from the concurrent
+// scan, sync and metadata workers, whatever the pool settings.
+func openSQLite(path, source string, pool poolSettings) (gorm.Dialector, poolSettings, error) {
+	if path == "" {
+		return nil, poolSettings{}, fmt.Errorf("%s: sqlite database path is empty", source)
+	}
+	// Case-sensitive LIKE keeps path prefix matching consistent with PostgreSQL
+	dsn := path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_pragma=case_sensitive_like(1)"
+	return sqlite.Open(dsn), pool.override(poolSettings{MaxOpenConns: 1}), nil
+}
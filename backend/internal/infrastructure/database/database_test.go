@@ -0,0 +1,136 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/config"
+
+	"gorm.io/gorm"
+)
+
+func TestInitDatabaseSQLite(t *testing.T) {
+	cfg := &config.AppConfig{DBBackend: "sqlite:" + filepath.Join(t.TempDir(), "dedup.db")}
+	db, err := InitDatabase(cfg)
+	if err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	if !IsSQLite(db) {
+		t.Fatal("expected sqlite dialector")
+	}
+
+	file := domain.ImageFile{Path: "/photos/a.jpg", Size: 10, Hash: "h", ModTime: time.Now()}
+	if err := db.Create(&file).Error; err != nil {
+		t.Fatalf("create image file: %v", err)
+	}
+	taken := time.Date(2024, 5, 17, 10, 30, 0, 0, time.UTC)
+	if err := db.Create(&domain.ImageMetadata{ImageFileID: file.ID, DateTaken: &taken}).Error; err != nil {
+		t.Fatalf("create metadata: %v", err)
+	}
+
+	var days []int
+	err = db.Raw("SELECT DISTINCT "+DayOfMonthExpr(db, "date_taken")+" AS day FROM image_metadata WHERE date_taken >= ? AND date_taken < ?",
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)).Pluck("day", &days).Error
+	if err != nil {
+		t.Fatalf("day query: %v", err)
+	}
+	if len(days) != 1 || days[0] != 17 {
+		t.Fatalf("days = %v, want [17]", days)
+	}
+
+	var settings domain.AppSettings
+	if err := db.First(&settings, 1).Error; err != nil {
+		t.Fatalf("default settings row not seeded: %v", err)
+	}
+}
+
+func TestOpenDialectorRejectsUnknownBackend(t *testing.T) {
+	for _, backend := range []string{"mysql", "sqlite:"} {
+		if _, _, err := openDialector(&config.AppConfig{DBBackend: backend}); err == nil {
+			t.Errorf("expected error for DB_BACKEND=%q", backend)
+		}
+	}
+}
+
+func TestParsePostgresURL(t *testing.T) {
+	dsn, pool, err := parsePostgresURL("postgres://user:p%40ss@db:5432/dedup?sslmode=require&connect_timeout=5&pool_max_conns=20&pool_max_conn_lifetime=30m")
+	if err != nil {
+		t.Fatalf("parsePostgresURL: %v", err)
+	}
+	if dsn != "postgres://user:p%40ss@db:5432/dedup?connect_timeout=5&sslmode=require" {
+		t.Errorf("dsn = %q", dsn)
+	}
+	if pool.MaxOpenConns != 20 || pool.ConnMaxLifetime != 30*time.Minute {
+		t.Errorf("pool = %+v", pool)
+	}
+
+	for _, raw := range []string{
+		"mysql://db/dedup",
+		"postgres://db",
+		"postgres://db/dedup?pool_max_conns=many",
+		"postgres://db/dedup?pool_max_conn_idle_time=5",
+	} {
+		if _, _, err := parsePostgresURL(raw); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestPoolSettings(t *testing.T) {
+	cfg := &config.AppConfig{
+		DatabaseURL:       "postgres://db/dedup?pool_max_conns=20",
+		DBMaxOpenConns:    5,
+		DBMaxIdleConns:    2,
+		DBConnMaxLifetime: "1h",
+	}
+	_, pool, err := openDialector(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// URL parameters win over the DB_* settings, which fill in the rest
+	if want := (poolSettings{MaxOpenConns: 20, MaxIdleConns: 2, ConnMaxLifetime: time.Hour}); pool != want {
+		t.Errorf("pool = %+v, want %+v", pool, want)
+	}
+
+	cfg = &config.AppConfig{DBBackend: "sqlite:/tmp/dedup.db", DBMaxOpenConns: 5, DBConnMaxIdleTime: "5m"}
+	if _, pool, err = openDialector(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if want := (poolSettings{MaxOpenConns: 1, ConnMaxIdleTime: 5 * time.Minute}); pool != want {
+		t.Errorf("sqlite pool = %+v, want %+v", pool, want)
+	}
+
+	for _, bad := range []*config.AppConfig{{DBConnMaxLifetime: "soon"}, {DBMaxIdleConns: -1}} {
+		if _, _, err := openDialector(bad); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestConnectRetries(t *testing.T) {
+	defer func(delay time.Duration) { connectRetryDelay = delay }(connectRetryDelay)
+	connectRetryDelay = time.Millisecond
+
+	notReady := errors.New("connection refused")
+	attempts := 0
+	db, err := connect(func() (*gorm.DB, error) {
+		if attempts++; attempts < 3 {
+			return nil, notReady
+		}
+		return &gorm.DB{}, nil
+	}, time.Second)
+	if err != nil || db == nil || attempts != 3 {
+		t.Fatalf("connect = %v, %v after %d attempts", db, err, attempts)
+	}
+
+	attempts = 0
+	if _, err := connect(func() (*gorm.DB, error) {
+		attempts++
+		return nil, notReady
+	}, 0); !errors.Is(err, notReady) || attempts != 1 {
+		t.Errorf("connect without timeout = %v after %d attempts", err, attempts)
+	}
+}
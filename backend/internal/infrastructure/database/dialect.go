@@ -0,0 +1,30 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// IsSQLite reports whether db is backed by the embedded SQLite driver
+func IsSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == "sqlite"
+}
+
+// DayOfMonthExpr returns a SQL expression yielding the day of month (1-31) of a
+// timestamp column as an integer, in the dialect of db
+func DayOfMonthExpr(db *gorm.DB, column string) string {
+	if IsSQLite(db) {
+		return fmt.Sprintf("CAST(strftime('%%d', %s) AS INTEGER)", column)
+	}
+	return fmt.Sprintf("CAST(EXTRACT(DAY FROM %s) AS INTEGER)", column)
+}
+
+// MonthExpr returns a SQL expression yielding the month of a timestamp column as
+// "YYYY-MM" text, in the dialect of db
+func MonthExpr(db *gorm.DB, column string) string {
+	if IsSQLite(db) {
+		return fmt.Sprintf("strftime('%%Y-%%m', %s)", column)
+	}
+	return fmt.Sprintf("to_char(%s, 'YYYY-MM')", column)
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// poolSettings configures the database/sql connection pool. Zero values keep the defaults.
+type poolSettings struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// Pool parameters accepted in DATABASE_URL. They are consumed here and removed from the
+// URL, since the driver would otherwise send them to the server as runtime parameters.
+const (
+	paramPoolMaxConns        = "pool_max_conns"
+	paramPoolMaxIdleConns    = "pool_max_idle_conns"
+	paramPoolMaxConnLifetime = "pool_max_conn_lifetime"
+	paramPoolMaxConnIdleTime = "pool_max_conn_idle_time"
+)
+
+// parsePostgresURL validates a postgres:// DATABASE_URL and splits off the pool parameters.
+// Everything else (sslmode, connect_timeout, ...) is passed to the driver unchanged.
+func parsePostgresURL(raw string) (string, poolSettings, error) {
+	var pool poolSettings
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", pool, fmt.Errorf("DATABASE_URL: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return "", pool, fmt.Errorf("DATABASE_URL: unsupported scheme %q (expected postgres:// or sqlite:<path>)", u.Scheme)
+	}
+	if u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return "", pool, fmt.Errorf("DATABASE_URL: host and database name are required")
+	}
+
+	query := u.Query()
+	intParam := func(name string, dst *int) error {
+		if v := query.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("DATABASE_URL: invalid %s %q", name, v)
+			}
+			*dst = n
+		}
+		query.Del(name)
+		return nil
+	}
+	durationParam := func(name string, dst *time.Duration) error {
+		if v := query.Get(name); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				return fmt.Errorf("DATABASE_URL: invalid %s %q (expected a duration like 30m)", name, v)
+			}
+			*dst = d
+		}
+		query.Del(name)
+		return nil
+	}
+
+	if err := intParam(paramPoolMaxConns, &pool.MaxOpenConns); err != nil {
+		return "", pool, err
+	}
+	if err := intParam(paramPoolMaxIdleConns, &pool.MaxIdleConns); err != nil {
+		return "", pool, err
+	}
+	if err := durationParam(paramPoolMaxConnLifetime, &pool.ConnMaxLifetime); err != nil {
+		return "", pool, err
+	}
+	if err := durationParam(paramPoolMaxConnIdleTime, &pool.ConnMaxIdleTime); err != nil {
+		return "", pool, err
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String(), pool, nil
+}
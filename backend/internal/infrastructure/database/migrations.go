@@ -0,0 +1,225 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Migration is a versioned change of the database schema. Migrations are applied in
+// order of Version, each in a transaction with its row in schema_migrations, and rolled
+// back in reverse order. Down reverts Up; it is nil for a migration that cannot be
+// rolled back.
+//
+// New columns and tables are added by appending a migration, e.g. one calling
+// tx.Migrator().AddColumn(&domain.ImageFile{}, "Field") unless HasColumn reports it:
+// on a new database the baseline already creates the current models.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// migrations is the schema history, oldest first
+var migrations = []Migration{
+	{Version: 1, Name: "baseline", Up: migrateBaseline},
+	{Version: 2, Name: "image file host", Up: addImageFileHost, Down: dropImageFileHost},
+	{Version: 3, Name: "image file corrupt flag", Up: addImageFileCorrupt, Down: dropImageFileCorrupt},
+}
+
+// schemaMigration records an applied migration
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// migrateBaseline creates the schema as it was before versioned migrations. Databases
+// created back then were kept up to date by AutoMigrate, so it also brings those in
+// line, whatever release they were last opened by.
+func migrateBaseline(tx *gorm.DB) error {
+	return tx.AutoMigrate(
+		&domain.ImageFile{},
+		&domain.GalleryFolder{},
+		&domain.AppSettings{},
+		&domain.ImageMetadata{},
+		&domain.User{},
+		&domain.UserSettings{},
+		&domain.Session{},
+		&domain.AuditLog{},
+		&domain.OcrClassification{},
+		&domain.OcrBoundingBox{},
+		&domain.LlmSettings{},
+		&domain.OcrLlmRecognition{},
+		&domain.ScanRun{},
+		&domain.Deletion{},
+		&domain.Selection{},
+		&domain.IgnoredGroup{},
+		&domain.GroupReview{},
+	)
+}
+
+// addImageFileHost adds the host column of image_files and fills it in for the files
+// reported by agents, whose paths start with agent://<name>/
+func addImageFileHost(tx *gorm.DB) error {
+	m := tx.Migrator()
+	if !m.HasColumn(&domain.ImageFile{}, "Host") {
+		if err := m.AddColumn(&domain.ImageFile{}, "Host"); err != nil {
+			return err
+		}
+	}
+	if !m.HasIndex(&domain.ImageFile{}, "Host") {
+		if err := m.CreateIndex(&domain.ImageFile{}, "Host"); err != nil {
+			return err
+		}
+	}
+	var files []domain.ImageFile
+	return tx.Unscoped().Select("id, path").Where("path LIKE ? AND host = ''", "agent://%").
+		FindInBatches(&files, 500, func(batch *gorm.DB, _ int) error {
+			for _, f := range files {
+				host, _, _ := strings.Cut(strings.TrimPrefix(f.Path, "agent://"), "/")
+				if err := tx.Unscoped().Model(&domain.ImageFile{}).Where("id = ?", f.ID).Update("host", host).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// dropImageFileHost removes the host column of image_files
+func dropImageFileHost(tx *gorm.DB) error {
+	m := tx.Migrator()
+	if m.HasIndex(&domain.ImageFile{}, "Host") {
+		if err := m.DropIndex(&domain.ImageFile{}, "Host"); err != nil {
+			return err
+		}
+	}
+	return m.DropColumn(&domain.ImageFile{}, "Host")
+}
+
+// addImageFileCorrupt adds the corrupt column of image_files. Files indexed before
+// are flagged when they are hashed again.
+func addImageFileCorrupt(tx *gorm.DB) error {
+	m := tx.Migrator()
+	if !m.HasColumn(&domain.ImageFile{}, "Corrupt") {
+		if err := m.AddColumn(&domain.ImageFile{}, "Corrupt"); err != nil {
+			return err
+		}
+	}
+	if !m.HasIndex(&domain.ImageFile{}, "Corrupt") {
+		return m.CreateIndex(&domain.ImageFile{}, "Corrupt")
+	}
+	return nil
+}
+
+// dropImageFileCorrupt removes the corrupt column of image_files
+func dropImageFileCorrupt(tx *gorm.DB) error {
+	m := tx.Migrator()
+	if m.HasIndex(&domain.ImageFile{}, "Corrupt") {
+		if err := m.DropIndex(&domain.ImageFile{}, "Corrupt"); err != nil {
+			return err
+		}
+	}
+	return m.DropColumn(&domain.ImageFile{}, "Corrupt")
+}
+
+// LatestSchemaVersion returns the schema version this build migrates to
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the latest migration applied to the database, 0 if none
+func SchemaVersion(db *gorm.DB) (int, error) {
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return 0, nil
+	}
+	var version int
+	err := db.Model(&schemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// Migrate applies the pending migrations
+func Migrate(db *gorm.DB) error {
+	return migrateTo(db, migrations, LatestSchemaVersion())
+}
+
+// MigrateTo migrates the schema up or down to the given version
+func MigrateTo(db *gorm.DB, version int) error {
+	if version < 1 || version > LatestSchemaVersion() {
+		return fmt.Errorf("unknown schema version %d (this build knows 1 to %d)", version, LatestSchemaVersion())
+	}
+	return migrateTo(db, migrations, version)
+}
+
+// migrateTo applies the migrations of list up to target and rolls back the applied
+// ones above it. A database migrated by a newer build is refused, as this one does
+// not know how to roll its migrations back.
+func migrateTo(db *gorm.DB, list []Migration, target int) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	var applied []schemaMigration
+	if err := db.Find(&applied).Error; err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	known := make(map[int]bool, len(list))
+	for _, m := range list {
+		known[m.Version] = true
+	}
+	done := make(map[int]bool, len(applied))
+	for _, a := range applied {
+		if !known[a.Version] {
+			return fmt.Errorf("database has migration %d (%s) unknown to this build: it was migrated by a newer release", a.Version, a.Name)
+		}
+		done[a.Version] = true
+	}
+
+	sorted := append([]Migration(nil), list...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		m := sorted[i]
+		if m.Version <= target || !done[m.Version] {
+			continue
+		}
+		if m.Down == nil {
+			return fmt.Errorf("migration %d (%s) cannot be rolled back", m.Version, m.Name)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to roll back migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		slog.Info("Rolled back database migration", "version", m.Version, "name", m.Name)
+	}
+
+	for _, m := range sorted {
+		if m.Version > target || done[m.Version] {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		slog.Info("Applied database migration", "version", m.Version, "name", m.Name)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/config"
+
+	"gorm.io/gorm"
+)
+
+func TestMigrateUpAndDown(t *testing.T) {
+	db, err := Open(&config.AppConfig{DBBackend: "sqlite:" + filepath.Join(t.TempDir(), "dedup.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	type note struct {
+		ID   uint
+		Text string
+	}
+	list := append(append([]Migration(nil), migrations...),
+		Migration{
+			Version: 100,
+			Name:    "notes",
+			Up:      func(tx *gorm.DB) error { return tx.Migrator().CreateTable(&note{}) },
+			Down:    func(tx *gorm.DB) error { return tx.Migrator().DropTable(&note{}) },
+		},
+		Migration{
+			Version: 101,
+			Name:    "broken",
+			Up: func(tx *gorm.DB) error {
+				if err := tx.Exec("CREATE TABLE half_done (id INTEGER)").Error; err != nil {
+					return err
+				}
+				return tx.Exec("SELECT no_such_column FROM notes").Error
+			},
+		},
+	)
+
+	version := func() int {
+		v, err := SchemaVersion(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	if err := migrateTo(db, list, 100); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if v := version(); v != 100 || !db.Migrator().HasTable(&note{}) {
+		t.Fatalf("after migrating up: version %d, notes table %v", v, db.Migrator().HasTable(&note{}))
+	}
+
+	// A failing migration leaves neither its changes nor its version behind
+	if err := migrateTo(db, list, 101); err == nil {
+		t.Fatal("the broken migration should fail")
+	}
+	if v := version(); v != 100 || db.Migrator().HasTable("half_done") {
+		t.Errorf("after the failed migration: version %d, half_done table %v", v, db.Migrator().HasTable("half_done"))
+	}
+
+	if err := migrateTo(db, list, 1); err != nil {
+		t.Fatalf("roll back: %v", err)
+	}
+	if v := version(); v != 1 || db.Migrator().HasTable(&note{}) {
+		t.Errorf("after rolling back: version %d, notes table %v", v, db.Migrator().HasTable(&note{}))
+	}
+	if err := migrateTo(db, list, 0); err == nil || !strings.Contains(err.Error(), "cannot be rolled back") {
+		t.Errorf("rolling back the baseline = %v", err)
+	}
+
+	// A build that doesn't know an applied migration refuses the database
+	if err := migrateTo(db, list, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := Migrate(db); err == nil || !strings.Contains(err.Error(), "newer release") {
+		t.Errorf("migrating a newer database = %v", err)
+	}
+}
+
+func TestImageFileHostMigration(t *testing.T) {
+	db, err := Open(&config.AppConfig{DBBackend: "sqlite:" + filepath.Join(t.TempDir(), "dedup.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	// A database of the release before the host column
+	if err := MigrateTo(db, 1); err != nil {
+		t.Fatalf("roll back: %v", err)
+	}
+	if db.Migrator().HasColumn(&domain.ImageFile{}, "Host") {
+		t.Fatal("the host column is left after rolling back")
+	}
+	for _, path := range []string{"/photos/a.jpg", "agent://laptop/home/me/a.jpg"} {
+		if err := db.Exec("INSERT INTO image_files (path, size, hash, mod_time) VALUES (?, 1, 'h', CURRENT_TIMESTAMP)", path).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	var hosts []string
+	db.Model(&domain.ImageFile{}).Order("path").Pluck("host", &hosts)
+	if strings.Join(hosts, ",") != ",laptop" {
+		t.Errorf("hosts after migrating = %q", hosts)
+	}
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"image-toolkit/internal/infrastructure/config"
+
+	"gorm.io/gorm"
+)
+
+// Delays between connection attempts: doubled after each failure up to the maximum
+var (
+	connectRetryDelay    = 500 * time.Millisecond
+	maxConnectRetryDelay = 10 * time.Second
+)
+
+// configuredPool reads the DB_* pool settings
+func configuredPool(cfg *config.AppConfig) (poolSettings, error) {
+	pool := poolSettings{MaxOpenConns: cfg.DBMaxOpenConns, MaxIdleConns: cfg.DBMaxIdleConns}
+	if pool.MaxOpenConns < 0 || pool.MaxIdleConns < 0 {
+		return pool, fmt.Errorf("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS must not be negative")
+	}
+	var err error
+	if pool.ConnMaxLifetime, err = parseDuration("DB_CONN_MAX_LIFETIME", cfg.DBConnMaxLifetime); err != nil {
+		return pool, err
+	}
+	if pool.ConnMaxIdleTime, err = parseDuration("DB_CONN_MAX_IDLE_TIME", cfg.DBConnMaxIdleTime); err != nil {
+		return pool, err
+	}
+	return pool, nil
+}
+
+// override returns the settings with the non-zero ones of o in place
+func (p poolSettings) override(o poolSettings) poolSettings {
+	if o.MaxOpenConns > 0 {
+		p.MaxOpenConns = o.MaxOpenConns
+	}
+	if o.MaxIdleConns > 0 {
+		p.MaxIdleConns = o.MaxIdleConns
+	}
+	if o.ConnMaxLifetime > 0 {
+		p.ConnMaxLifetime = o.ConnMaxLifetime
+	}
+	if o.ConnMaxIdleTime > 0 {
+		p.ConnMaxIdleTime = o.ConnMaxIdleTime
+	}
+	return p
+}
+
+// parseDuration parses an optional duration setting, empty meaning zero
+func parseDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s: invalid duration %q (expected e.g. 30m)", name, value)
+	}
+	return d, nil
+}
+
+// connect opens the database, retrying with exponential backoff for up to timeout
+// while the server is not accepting connections yet, e.g. PostgreSQL starting in the
+// next container of a docker-compose setup
+func connect(open func() (*gorm.DB, error), timeout time.Duration) (*gorm.DB, error) {
+	deadline := time.Now().Add(timeout)
+	delay := connectRetryDelay
+	for attempt := 1; ; attempt++ {
+		db, err := open()
+		if err == nil {
+			return db, nil
+		}
+		if db != nil {
+			// The failed ping leaves an open pool behind
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				sqlDB.Close()
+			}
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return nil, err
+		}
+		slog.Warn("Database not ready, retrying", "attempt", attempt, "retry_in", delay, "error", err)
+		time.Sleep(delay)
+		delay = min(delay*2, maxConnectRetryDelay)
+	}
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/config"
+
+	"gorm.io/gorm"
+)
+
+// ApplyStartupConfig adds the configured scan directories to the gallery and applies the
+// configured trash directory. Folders added through the UI are kept; directories that
+// don't exist are skipped with a warning.
+func ApplyStartupConfig(db *gorm.DB, cfg *config.AppConfig) error {
+	for _, dir := range cfg.ScanDirectories {
+		absPath, err := filepath.Abs(dir)
+		if err != nil {
+			slog.Warn("Skipping scan directory", "path", dir, "error", err)
+			continue
+		}
+		if info, err := os.Stat(absPath); err != nil || !info.IsDir() {
+			slog.Warn("Skipping scan directory: not an accessible directory", "path", dir)
+			continue
+		}
+		folder := domain.GalleryFolder{Path: filepath.ToSlash(absPath)}
+		if err := db.Where("path = ?", folder.Path).FirstOrCreate(&folder).Error; err != nil {
+			return fmt.Errorf("failed to add scan directory %s: %w", dir, err)
+		}
+	}
+
+	if cfg.TrashDir != "" {
+		absPath, err := filepath.Abs(cfg.TrashDir)
+		if err != nil {
+			return fmt.Errorf("invalid trash directory %s: %w", cfg.TrashDir, err)
+		}
+		if err := db.Model(&domain.AppSettings{}).Where("id = ?", 1).Update("trash_dir", filepath.ToSlash(absPath)).Error; err != nil {
+			return fmt.Errorf("failed to apply trash directory: %w", err)
+		}
+	}
+	return nil
+}
@@ -1,7 +1,7 @@
 package geocoder
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/sams96/rgeo"
 	"github.com/twpayne/go-geom"
@@ -17,7 +17,7 @@ type Geocoder struct {
 func NewGeocoder() *Geocoder {
 	r, err := rgeo.New(rgeo.Provinces10, rgeo.Cities10)
 	if err != nil {
-		log.Printf("WARNING: Failed to initialize geocoder: %v (geolocation will be disabled)", err)
+		slog.Warn("Failed to initialize geocoder, geolocation will be disabled", "error", err)
 		return nil
 	}
 	return &Geocoder{r: r}
@@ -0,0 +1,135 @@
+package imagecodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// There is no Go AV1 decoder, so AVIF pixels are decoded by ffmpeg (built with
+// libdav1d or libaom). Dimensions are read from the file header without it.
+
+var (
+	ffmpegMu   sync.RWMutex
+	ffmpegPath = "ffmpeg"
+)
+
+// SetFFmpegPath sets the ffmpeg binary used for formats without a Go decoder.
+// A bare name is looked up in PATH; empty restores the default.
+func SetFFmpegPath(path string) {
+	if path == "" {
+		path = "ffmpeg"
+	}
+	ffmpegMu.Lock()
+	ffmpegPath = path
+	ffmpegMu.Unlock()
+}
+
+// FFmpegPath returns the configured ffmpeg binary
+func FFmpegPath() string {
+	ffmpegMu.RLock()
+	defer ffmpegMu.RUnlock()
+	return ffmpegPath
+}
+
+// ErrFFmpegUnavailable is returned when decoding needs ffmpeg and it cannot be run
+var ErrFFmpegUnavailable = errors.New("ffmpeg not found (required to decode AVIF and video)")
+
+// FFmpegAvailable reports whether the configured ffmpeg binary can be found
+func FFmpegAvailable() bool {
+	_, err := exec.LookPath(FFmpegPath())
+	return err == nil
+}
+
+// IsAVIF reports whether the file is an AVIF image, based on its extension
+func IsAVIF(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".avif"
+}
+
+// decodeAVIF converts the primary image to PNG with ffmpeg and decodes that
+func decodeAVIF(path string) (image.Image, error) {
+	return ffmpegFrame(path)
+}
+
+// ffmpegFrame decodes the first frame of a file with ffmpeg. inputArgs go before
+// the input, e.g. to restrict decoding to keyframes.
+func ffmpegFrame(path string, inputArgs ...string) (image.Image, error) {
+	bin, err := exec.LookPath(FFmpegPath())
+	if err != nil {
+		return nil, ErrFFmpegUnavailable
+	}
+
+	args := append([]string{"-nostdin", "-v", "error"}, inputArgs...)
+	args = append(args, "-i", path, "-frames:v", "1", "-f", "image2pipe", "-c:v", "png", "-")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(bin, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to decode image: ffmpeg: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	img, err := png.Decode(&stdout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// avifHeaderLimit bounds the search for the image size property; the meta box
+// precedes the image data and is small
+const avifHeaderLimit = 64 << 10
+
+// decodeAVIFConfig reads the dimensions of an AVIF image from its header
+func decodeAVIFConfig(path string) (image.Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer file.Close()
+
+	header, err := io.ReadAll(io.LimitReader(file, avifHeaderLimit))
+	if err != nil {
+		return image.Config{}, fmt.Errorf("failed to open image: %w", err)
+	}
+	width, height, ok := avifSize(header)
+	if !ok {
+		return image.Config{}, errors.New("failed to decode image: no AVIF size property found")
+	}
+	return image.Config{ColorModel: color.RGBAModel, Width: width, Height: height}, nil
+}
+
+// avifSize finds the largest image spatial extents ("ispe") property in an AVIF
+// (ISO BMFF) header. Thumbnail and alpha items carry their own, smaller ones.
+func avifSize(header []byte) (int, int, bool) {
+	if len(header) < 12 || string(header[4:8]) != "ftyp" {
+		return 0, 0, false
+	}
+
+	var width, height int
+	for rest := header; ; {
+		i := bytes.Index(rest, []byte("ispe"))
+		// box type, 4 bytes version and flags, width, height
+		if i < 0 || i+16 > len(rest) {
+			break
+		}
+		w := int(binary.BigEndian.Uint32(rest[i+8:]))
+		h := int(binary.BigEndian.Uint32(rest[i+12:]))
+		if w*h > width*height {
+			width, height = w, h
+		}
+		rest = rest[i+4:]
+	}
+	return width, height, width > 0 && height > 0
+}
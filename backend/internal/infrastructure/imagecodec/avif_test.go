@@ -0,0 +1,43 @@
+package imagecodec
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func ispe(w, h uint32) []byte {
+	b := []byte("\x00\x00\x00\x14ispe\x00\x00\x00\x00")
+	b = binary.BigEndian.AppendUint32(b, w)
+	return binary.BigEndian.AppendUint32(b, h)
+}
+
+func TestAVIFSize(t *testing.T) {
+	header := []byte("\x00\x00\x00\x1cftypavif\x00\x00\x00\x00avifmif1miaf")
+	header = append(header, ispe(160, 120)...)
+	header = append(header, ispe(4032, 3024)...)
+
+	w, h, ok := avifSize(header)
+	if !ok || w != 4032 || h != 3024 {
+		t.Errorf("avifSize = %d, %d, %v; want 4032, 3024, true", w, h, ok)
+	}
+	if _, _, ok := avifSize([]byte("\x00\x00\x00\x1cftypavif")); ok {
+		t.Error("avifSize succeeded without an ispe property")
+	}
+}
+
+func TestDecodeAVIFWithoutFFmpeg(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.avif")
+	if err := os.WriteFile(path, []byte("\x00\x00\x00\x1cftypavif"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	SetFFmpegPath(filepath.Join(t.TempDir(), "no-ffmpeg"))
+	defer SetFFmpegPath("")
+
+	if _, err := Decode(path); !errors.Is(err, ErrFFmpegUnavailable) {
+		t.Errorf("Decode err = %v, want ErrFFmpegUnavailable", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Package imagecodec decodes image files of every supported format: those
+// registered with the standard image package, HEIC/HEIF, AVIF (through ffmpeg)
+// and camera RAW files through their embedded JPEG previews. Videos decode to
+// their first keyframe (through ffmpeg).
+package imagecodec
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"os"
+
+	"image-toolkit/internal/domain"
+
+	"github.com/gen2brain/heic"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+func init() {
+	// The heic package registers only the "heic" brand. HEIF stills, bursts and
+	// sequences written by phones and cameras use these brands as well.
+	for _, brand := range []string{"heix", "heim", "heis", "hevc", "hevx", "mif1", "msf1"} {
+		image.RegisterFormat("heic", "????ftyp"+brand, heic.Decode, heic.DecodeConfig)
+	}
+}
+
+// Decode reads and decodes an image file
+func Decode(path string) (image.Image, error) {
+	if domain.IsVideoFile(path) {
+		return decodeVideoFrame(path)
+	}
+	if IsAVIF(path) {
+		return decodeAVIF(path)
+	}
+	if IsRaw(path) {
+		preview, err := readPreview(path)
+		if err != nil {
+			return nil, err
+		}
+		img, err := jpeg.Decode(bytes.NewReader(preview))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+		return img, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// DecodeConfig reads only the header of an image file. For RAW files these are
+// the dimensions of the embedded preview.
+func DecodeConfig(path string) (image.Config, error) {
+	if domain.IsVideoFile(path) {
+		img, err := decodeVideoFrame(path)
+		if err != nil {
+			return image.Config{}, err
+		}
+		return image.Config{ColorModel: img.ColorModel(), Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}, nil
+	}
+	if IsAVIF(path) {
+		return decodeAVIFConfig(path)
+	}
+	if IsRaw(path) {
+		preview, err := readPreview(path)
+		if err != nil {
+			return image.Config{}, err
+		}
+		return jpeg.DecodeConfig(bytes.NewReader(preview))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer file.Close()
+
+	cfg, _, err := image.DecodeConfig(file)
+	return cfg, err
+}
+
+// readPreview opens a RAW file and extracts its embedded JPEG preview
+func readPreview(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	return ExtractPreview(file, info.Size())
+}
@@ -0,0 +1,87 @@
+package imagecodec
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// orientedExtensions are the formats whose EXIF orientation is applied for display.
+// HEIF decoders already apply the rotation stored in the container, and its EXIF
+// orientation must then be ignored.
+var orientedExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".tif":  true,
+	".tiff": true,
+}
+
+// Orientation returns the EXIF orientation (1-8) of a JPEG, TIFF or camera RAW file,
+// or 1 (as stored) when the file has none or is of another format
+func Orientation(path string) int {
+	if !orientedExtensions[strings.ToLower(filepath.Ext(path))] && !IsRaw(path) {
+		return 1
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 1
+	}
+	defer f.Close()
+
+	x, err := exif.Decode(f)
+	if err != nil {
+		return 1
+	}
+	tag, err := x.Get(exif.Orientation)
+	if err != nil {
+		return 1
+	}
+	orientation, err := tag.Int(0)
+	if err != nil || orientation < 1 || orientation > 8 {
+		return 1
+	}
+	return orientation
+}
+
+// applyOrientation rotates and flips an image stored with the given EXIF orientation
+// so that it appears upright
+func applyOrientation(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return imaging.FlipH(img)
+	case 3:
+		return imaging.Rotate180(img)
+	case 4:
+		return imaging.FlipV(img)
+	case 5:
+		return imaging.Transpose(img)
+	case 6:
+		return imaging.Rotate270(img)
+	case 7:
+		return imaging.Transverse(img)
+	case 8:
+		return imaging.Rotate90(img)
+	}
+	return img
+}
+
+// Orient turns an image decoded from path with Decode upright according to the
+// EXIF orientation of the file
+func Orient(img image.Image, path string) image.Image {
+	return applyOrientation(img, Orientation(path))
+}
+
+// DecodeOriented decodes an image file for display: like Decode, but turned upright
+// according to its EXIF orientation. Hashes use Decode, so that they keep matching
+// the stored pixels.
+func DecodeOriented(path string) (image.Image, error) {
+	img, err := Decode(path)
+	if err != nil {
+		return nil, err
+	}
+	return Orient(img, path), nil
+}
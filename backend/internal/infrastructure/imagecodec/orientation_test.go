@@ -0,0 +1,50 @@
+package imagecodec
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestApplyOrientation(t *testing.T) {
+	// A 3x2 image stored with each orientation; the marked pixel is the stored
+	// top-left corner, and after correction it must land where the camera saw it
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	src.Set(0, 0, color.White)
+
+	cases := []struct {
+		orientation int
+		w, h        int
+		x, y        int
+	}{
+		{1, 3, 2, 0, 0},
+		{2, 3, 2, 2, 0},
+		{3, 3, 2, 2, 1},
+		{4, 3, 2, 0, 1},
+		{5, 2, 3, 0, 0},
+		{6, 2, 3, 1, 0},
+		{7, 2, 3, 1, 2},
+		{8, 2, 3, 0, 2},
+		{0, 3, 2, 0, 0},
+	}
+	for _, c := range cases {
+		img := applyOrientation(src, c.orientation)
+		b := img.Bounds()
+		if b.Dx() != c.w || b.Dy() != c.h {
+			t.Errorf("orientation %d: size %dx%d, want %dx%d", c.orientation, b.Dx(), b.Dy(), c.w, c.h)
+			continue
+		}
+		if r, _, _, _ := img.At(b.Min.X+c.x, b.Min.Y+c.y).RGBA(); r != 0xffff {
+			t.Errorf("orientation %d: corner not at (%d,%d)", c.orientation, c.x, c.y)
+		}
+	}
+}
+
+func TestOrientationOfOtherFormats(t *testing.T) {
+	if o := Orientation("/nonexistent/photo.png"); o != 1 {
+		t.Errorf("Orientation = %d, want 1", o)
+	}
+	if o := Orientation("/nonexistent/photo.jpg"); o != 1 {
+		t.Errorf("Orientation of a missing file = %d, want 1", o)
+	}
+}
@@ -0,0 +1,207 @@
+package imagecodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/jpeg"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// rawExtensions are the camera RAW formats read through their embedded previews.
+// All of them are TIFF containers.
+var rawExtensions = map[string]bool{
+	".cr2": true, // Canon
+	".nef": true, // Nikon
+	".arw": true, // Sony
+	".dng": true, // Adobe Digital Negative
+	".orf": true, // Olympus
+}
+
+// IsRaw reports whether the file is a camera RAW image, based on its extension
+func IsRaw(path string) bool {
+	return rawExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// ErrNoPreview is returned for RAW files without a decodable JPEG preview
+var ErrNoPreview = errors.New("no embedded JPEG preview found")
+
+// TIFF tags locating embedded JPEG data
+const (
+	tagCompression     = 0x0103
+	tagStripOffsets    = 0x0111
+	tagStripByteCounts = 0x0117
+	tagSubIFDs         = 0x014a
+	tagJPEGOffset      = 0x0201
+	tagJPEGLength      = 0x0202
+	tagExifIFD         = 0x8769
+)
+
+// Limits guarding against corrupt or hostile files
+const (
+	maxIFDs        = 64
+	maxIFDEntries  = 1024
+	maxPreviewSize = 64 << 20
+)
+
+type jpegBlock struct {
+	offset, length int64
+}
+
+// ExtractPreview returns the largest baseline JPEG embedded in a TIFF-based RAW
+// file: previews referenced by JPEGInterchangeFormat tags and JPEG-compressed
+// single-strip images in IFD0, its chain, SubIFDs and the EXIF IFD. Lossless JPEG
+// sensor data (DNG, CR2) is skipped because it does not decode as a picture.
+func ExtractPreview(r io.ReaderAt, size int64) ([]byte, error) {
+	var header [8]byte
+	if _, err := r.ReadAt(header[:], 0); err != nil {
+		return nil, ErrNoPreview
+	}
+	var order binary.ByteOrder
+	switch string(header[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, ErrNoPreview
+	}
+	// 42 is TIFF; Olympus ORF uses "RO" and "RS"
+	switch order.Uint16(header[2:]) {
+	case 42, 0x4f52, 0x5352:
+	default:
+		return nil, ErrNoPreview
+	}
+
+	p := &tiffParser{r: r, size: size, order: order, visited: make(map[int64]bool)}
+	p.walk(int64(order.Uint32(header[4:])), true)
+
+	sort.Slice(p.blocks, func(i, j int) bool { return p.blocks[i].length > p.blocks[j].length })
+	for _, b := range p.blocks {
+		data := make([]byte, b.length)
+		if _, err := r.ReadAt(data, b.offset); err != nil {
+			continue
+		}
+		if !bytes.HasPrefix(data, []byte{0xff, 0xd8}) {
+			continue
+		}
+		if _, err := jpeg.DecodeConfig(bytes.NewReader(data)); err != nil {
+			continue
+		}
+		return data, nil
+	}
+	return nil, ErrNoPreview
+}
+
+type tiffParser struct {
+	r       io.ReaderAt
+	size    int64
+	order   binary.ByteOrder
+	visited map[int64]bool
+	blocks  []jpegBlock
+}
+
+// walk reads the IFD at offset and the IFDs it links to, collecting JPEG blocks
+func (p *tiffParser) walk(offset int64, followNext bool) {
+	for offset > 0 && offset < p.size && !p.visited[offset] && len(p.visited) < maxIFDs {
+		p.visited[offset] = true
+
+		var countBuf [2]byte
+		if _, err := p.r.ReadAt(countBuf[:], offset); err != nil {
+			return
+		}
+		count := int(p.order.Uint16(countBuf[:]))
+		if count == 0 || count > maxIFDEntries {
+			return
+		}
+		entries := make([]byte, count*12+4)
+		if _, err := p.r.ReadAt(entries, offset+2); err != nil {
+			return
+		}
+
+		var compression uint32
+		var jpegOffset, jpegLength, stripOffsets, stripCounts, children []uint32
+		for i := 0; i < count; i++ {
+			e := entries[i*12 : i*12+12]
+			switch p.order.Uint16(e) {
+			case tagCompression:
+				if v := p.values(e); len(v) > 0 {
+					compression = v[0]
+				}
+			case tagStripOffsets:
+				stripOffsets = p.values(e)
+			case tagStripByteCounts:
+				stripCounts = p.values(e)
+			case tagJPEGOffset:
+				jpegOffset = p.values(e)
+			case tagJPEGLength:
+				jpegLength = p.values(e)
+			case tagSubIFDs, tagExifIFD:
+				children = append(children, p.values(e)...)
+			}
+		}
+
+		if len(jpegOffset) == 1 && len(jpegLength) == 1 {
+			p.add(jpegOffset[0], jpegLength[0])
+		}
+		// 6 is old-style and 7 new-style JPEG compression
+		if (compression == 6 || compression == 7) && len(stripOffsets) == 1 && len(stripCounts) == 1 {
+			p.add(stripOffsets[0], stripCounts[0])
+		}
+		for _, child := range children {
+			p.walk(int64(child), false)
+		}
+
+		if !followNext {
+			return
+		}
+		offset = int64(p.order.Uint32(entries[count*12:]))
+	}
+}
+
+func (p *tiffParser) add(offset, length uint32) {
+	if length == 0 || length > maxPreviewSize || int64(offset)+int64(length) > p.size {
+		return
+	}
+	p.blocks = append(p.blocks, jpegBlock{offset: int64(offset), length: int64(length)})
+}
+
+// values returns the SHORT, LONG or IFD values of an IFD entry
+func (p *tiffParser) values(entry []byte) []uint32 {
+	typ := p.order.Uint16(entry[2:])
+	count := p.order.Uint32(entry[4:])
+
+	var width uint32
+	switch typ {
+	case 3: // SHORT
+		width = 2
+	case 4, 13: // LONG, IFD
+		width = 4
+	default:
+		return nil
+	}
+	if count == 0 || count > maxIFDEntries {
+		return nil
+	}
+
+	data := entry[8:12]
+	if count*width > 4 {
+		data = make([]byte, count*width)
+		if _, err := p.r.ReadAt(data, int64(p.order.Uint32(entry[8:]))); err != nil {
+			return nil
+		}
+	}
+
+	values := make([]uint32, count)
+	for i := range values {
+		if width == 2 {
+			values[i] = uint32(p.order.Uint16(data[i*2:]))
+		} else {
+			values[i] = p.order.Uint32(data[i*4:])
+		}
+	}
+	return values
+}
@@ -0,0 +1,105 @@
+package imagecodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+func testJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+type ifdEntry struct {
+	tag, typ uint16
+	value    uint32
+}
+
+// buildTIFF lays out a little-endian TIFF: header, IFD0 at 8, a SubIFD, then the payloads
+func buildTIFF(magic uint16, ifd0, sub []ifdEntry, payload []byte) []byte {
+	le := binary.LittleEndian
+	var b []byte
+	b = append(b, 'I', 'I')
+	b = le.AppendUint16(b, magic)
+	b = le.AppendUint32(b, 8)
+	writeIFD := func(entries []ifdEntry) {
+		b = le.AppendUint16(b, uint16(len(entries)))
+		for _, e := range entries {
+			b = le.AppendUint16(b, e.tag)
+			b = le.AppendUint16(b, e.typ)
+			b = le.AppendUint32(b, 1)
+			if e.typ == 3 {
+				b = le.AppendUint16(b, uint16(e.value))
+				b = le.AppendUint16(b, 0)
+			} else {
+				b = le.AppendUint32(b, e.value)
+			}
+		}
+		b = le.AppendUint32(b, 0)
+	}
+	writeIFD(ifd0)
+	writeIFD(sub)
+	return append(b, payload...)
+}
+
+func TestExtractPreview(t *testing.T) {
+	thumb := testJPEG(t, 16, 8)
+	preview := testJPEG(t, 320, 160)
+
+	ifd0Size := 2 + 3*12 + 4
+	subOffset := uint32(8 + ifd0Size)
+	dataOffset := subOffset + uint32(2+3*12+4)
+
+	ifd0 := []ifdEntry{
+		{tagSubIFDs, 4, subOffset},
+		{tagJPEGOffset, 4, dataOffset},
+		{tagJPEGLength, 4, uint32(len(thumb))},
+	}
+	sub := []ifdEntry{
+		{tagCompression, 3, 6},
+		{tagStripOffsets, 4, dataOffset + uint32(len(thumb))},
+		{tagStripByteCounts, 4, uint32(len(preview))},
+	}
+	payload := append(append([]byte{}, thumb...), preview...)
+
+	for _, magic := range []uint16{42, 0x4f52} {
+		file := buildTIFF(magic, ifd0, sub, payload)
+		got, err := ExtractPreview(bytes.NewReader(file), int64(len(file)))
+		if err != nil {
+			t.Fatalf("magic %#x: %v", magic, err)
+		}
+		if !bytes.Equal(got, preview) {
+			t.Errorf("magic %#x: got %d bytes, want the %d byte preview", magic, len(got), len(preview))
+		}
+	}
+
+	// A dangling offset past the end of the file is ignored
+	broken := buildTIFF(42, []ifdEntry{{tagJPEGOffset, 4, 1 << 20}, {tagJPEGLength, 4, 100}}, nil, nil)
+	if _, err := ExtractPreview(bytes.NewReader(broken), int64(len(broken))); err != ErrNoPreview {
+		t.Errorf("broken file: err = %v, want ErrNoPreview", err)
+	}
+	if _, err := ExtractPreview(bytes.NewReader(preview), int64(len(preview))); err != ErrNoPreview {
+		t.Errorf("plain JPEG: err = %v, want ErrNoPreview", err)
+	}
+}
+
+func TestIsRaw(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/p/IMG_0001.CR2": true,
+		"/p/DSC_0001.nef": true,
+		"/p/a.dng":        true,
+		"/p/a.tif":        false,
+		"/p/a.jpg":        false,
+	} {
+		if got := IsRaw(path); got != want {
+			t.Errorf("IsRaw(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package imagecodec
+
+import "image"
+
+// decodeVideoFrame returns the first keyframe of a video. Skipping non-key
+// frames avoids decoding a whole GOP and the grey frames some encoders start with.
+func decodeVideoFrame(path string) (image.Image, error) {
+	return ffmpegFrame(path, "-skip_frame", "nokey")
+}
@@ -0,0 +1,329 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemeS3 is the URL scheme of S3 scan roots: s3://bucket/prefix
+const SchemeS3 = "s3://"
+
+// emptyPayloadHash is the SHA-256 of an empty request body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Config configures access to S3 and S3-compatible stores (MinIO, Ceph, Backblaze B2 etc.)
+type S3Config struct {
+	Endpoint        string // e.g. http://minio:9000, addressed path-style; empty = AWS, virtual-hosted
+	Region          string // Region requests are signed for, us-east-1 if empty
+	AccessKeyID     string // Empty = anonymous access to public buckets
+	SecretAccessKey string
+	SessionToken    string // Temporary credentials only
+}
+
+// S3Store lists and reads the objects under an S3 bucket prefix. Requests are
+// signed with AWS Signature Version 4.
+type S3Store struct {
+	cfg    S3Config
+	bucket string
+	prefix string
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store creates the store of an s3://bucket/prefix URL
+func NewS3Store(root string, cfg S3Config) (*S3Store, error) {
+	bucket, prefix, err := ParseS3URL(root)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	return &S3Store{cfg: cfg, bucket: bucket, prefix: prefix, client: &http.Client{Timeout: 30 * time.Minute}, now: time.Now}, nil
+}
+
+// ParseS3URL splits an s3://bucket/key URL into bucket and key
+func ParseS3URL(u string) (bucket, key string, err error) {
+	rest, ok := strings.CutPrefix(u, SchemeS3)
+	if !ok {
+		return "", "", fmt.Errorf("not an S3 URL: %s", u)
+	}
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("S3 URL without a bucket: %s", u)
+	}
+	return bucket, key, nil
+}
+
+// listBucketResult is the response of ListObjectsV2
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		ETag         string    `xml:"ETag"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List calls fn for every object under the prefix, skipping folder markers
+func (s *S3Store) List(ctx context.Context, fn func(Object) error) error {
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := s.do(ctx, "", query)
+		if err != nil {
+			return err
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse object list of s3://%s/%s: %w", s.bucket, s.prefix, err)
+		}
+
+		for _, c := range page.Contents {
+			if strings.HasSuffix(c.Key, "/") {
+				continue
+			}
+			obj := Object{Path: SchemeS3 + s.bucket + "/" + c.Key, Size: c.Size, ModTime: c.LastModified}
+			// The ETag of a single-part upload without SSE-KMS is the MD5 of the content;
+			// multipart ETags carry a "-<parts>" suffix
+			if etag := strings.Trim(c.ETag, `"`); len(etag) == 32 && !strings.Contains(etag, "-") {
+				obj.MD5 = strings.ToLower(etag)
+			}
+			if err := fn(obj); err != nil {
+				return err
+			}
+		}
+
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// Open streams the content of an object in the store's bucket
+func (s *S3Store) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	bucket, key, err := ParseS3URL(path)
+	if err != nil {
+		return nil, err
+	}
+	if bucket != s.bucket {
+		return nil, fmt.Errorf("%s is not in bucket %s", path, s.bucket)
+	}
+	resp, err := s.do(ctx, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends a signed GET request for a key, or for the bucket if key is empty, and
+// fails on error responses
+func (s *S3Store) do(ctx context.Context, key string, query url.Values) (*http.Response, error) {
+	u := s.url(key)
+	u.RawQuery = canonicalQuery(query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		var e struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+			return nil, fmt.Errorf("S3 %s: %s (%s)", e.Code, e.Message, resp.Status)
+		}
+		return nil, fmt.Errorf("S3 request failed: %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// url returns the URL of a key: path-style on a custom endpoint, virtual-hosted on AWS
+func (s *S3Store) url(key string) *url.URL {
+	if s.cfg.Endpoint != "" {
+		u, err := url.Parse(s.cfg.Endpoint)
+		if err == nil {
+			path := strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+			return &url.URL{Scheme: u.Scheme, Host: u.Host, Path: path, RawPath: encodePath(path)}
+		}
+	}
+	path := "/" + key
+	return &url.URL{Scheme: "https", Host: s.bucket + ".s3." + s.cfg.Region + ".amazonaws.com", Path: path, RawPath: encodePath(path)}
+}
+
+// sign adds the AWS Signature Version 4 headers to a request without a body.
+// Anonymous stores send unsigned requests.
+func (s *S3Store) sign(req *http.Request, now time.Time) {
+	if s.cfg.AccessKeyID == "" {
+		return
+	}
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.cfg.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalQuery encodes a query sorted by key with RFC 3986 escaping, as Signature
+// Version 4 requires
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, escape(k, true)+"="+escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// encodePath escapes a path for a URL and the canonical request, keeping slashes
+func encodePath(path string) string {
+	return escape(path, false)
+}
+
+// escape percent-encodes everything but RFC 3986 unreserved characters, and slashes
+// unless encodeSlash is set
+func escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' && !encodeSlash {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// S3DeleteBatch is one DeleteObjects request of an S3 deletion plan. Delete is the
+// request body in the JSON form of `aws s3api delete-objects --bucket <bucket>
+// --delete file://batch.json`.
+type S3DeleteBatch struct {
+	Bucket string          `json:"bucket"`
+	Delete S3DeleteObjects `json:"delete"`
+}
+
+// S3DeleteObjects lists the keys of a DeleteObjects request
+type S3DeleteObjects struct {
+	Objects []S3ObjectID `json:"Objects"`
+	Quiet   bool         `json:"Quiet"`
+}
+
+// S3ObjectID identifies an object to delete
+type S3ObjectID struct {
+	Key string `json:"Key"`
+}
+
+// maxDeleteKeys is the number of keys a DeleteObjects request takes at most
+const maxDeleteKeys = 1000
+
+// S3DeletionPlan groups the objects at the given s3:// URLs into DeleteObjects
+// batches, per bucket and at most 1000 keys each, in the order given
+func S3DeletionPlan(paths []string) ([]S3DeleteBatch, error) {
+	var batches []S3DeleteBatch
+	open := make(map[string]int) // bucket -> index of its batch being filled
+	for _, p := range paths {
+		bucket, key, err := ParseS3URL(p)
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			return nil, fmt.Errorf("S3 URL without an object key: %s", p)
+		}
+		i, ok := open[bucket]
+		if !ok || len(batches[i].Delete.Objects) == maxDeleteKeys {
+			batches = append(batches, S3DeleteBatch{Bucket: bucket, Delete: S3DeleteObjects{Quiet: true}})
+			i = len(batches) - 1
+			open[bucket] = i
+		}
+		batches[i].Delete.Objects = append(batches[i].Delete.Objects, S3ObjectID{Key: key})
+	}
+	return batches, nil
+}
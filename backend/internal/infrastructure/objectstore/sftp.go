@@ -0,0 +1,228 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SchemeSFTP is the URL scheme of SFTP scan roots: sftp://user@host:port/absolute/path
+const SchemeSFTP = "sftp://"
+
+// SFTPConfig configures SSH connections to SFTP servers. Keys of a running ssh-agent
+// (SSH_AUTH_SOCK) are offered first, then KeyFile, then Password.
+type SFTPConfig struct {
+	User           string // Login used when the URL has none
+	Password       string
+	KeyFile        string // Unencrypted private key, e.g. ~/.ssh/id_ed25519
+	KnownHostsFile string // Host keys servers are verified against, ~/.ssh/known_hosts if empty
+}
+
+// SFTPStore lists, reads and deletes the files under a directory of an SFTP server.
+// The SSH connection is opened on first use and kept until Close.
+type SFTPStore struct {
+	cfg       SFTPConfig
+	authority string // user@host:port as written in the URL
+	user      string
+	addr      string
+	root      string
+
+	mu     sync.Mutex
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+// NewSFTPStore creates the store of an sftp:// URL
+func NewSFTPStore(root string, cfg SFTPConfig) (*SFTPStore, error) {
+	authority, path, err := parseSFTPURL(root)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(SchemeSFTP + authority)
+	if err != nil || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid SFTP URL: %s", root)
+	}
+	user := u.User.Username()
+	if user == "" {
+		user = cfg.User
+	}
+	if user == "" {
+		return nil, fmt.Errorf("SFTP URL without a user: %s", root)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "22"
+	}
+	return &SFTPStore{cfg: cfg, authority: authority, user: user, addr: net.JoinHostPort(u.Hostname(), port), root: strings.TrimSuffix(path, "/")}, nil
+}
+
+// parseSFTPURL splits an sftp:// URL into the authority (user@host:port) and the path
+func parseSFTPURL(u string) (authority, path string, err error) {
+	rest, ok := strings.CutPrefix(u, SchemeSFTP)
+	if !ok {
+		return "", "", fmt.Errorf("not an SFTP URL: %s", u)
+	}
+	authority, path, _ = strings.Cut(rest, "/")
+	if authority == "" {
+		return "", "", fmt.Errorf("SFTP URL without a host: %s", u)
+	}
+	return authority, "/" + path, nil
+}
+
+// connect returns the SFTP client, dialing the server on first use
+func (s *SFTPStore) connect() (*sftp.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	knownHostsFile := s.cfg.KnownHostsFile
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	hostKeys, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SFTP known hosts: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if agentConn, err := net.Dial("unix", sock); err == nil {
+			defer agentConn.Close()
+			auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+		}
+	}
+	if s.cfg.KeyFile != "" {
+		key, err := os.ReadFile(s.cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SFTP key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SFTP key %s: %w", s.cfg.KeyFile, err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if s.cfg.Password != "" {
+		auth = append(auth, ssh.Password(s.cfg.Password))
+	}
+
+	conn, err := ssh.Dial("tcp", s.addr, &ssh.ClientConfig{
+		User:            s.user,
+		Auth:            auth,
+		HostKeyCallback: hostKeys,
+		Timeout:         30 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SSH connection to %s failed: %w", s.addr, err)
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SFTP session on %s failed: %w", s.addr, err)
+	}
+	s.conn, s.client = conn, client
+	return client, nil
+}
+
+// List calls fn for every regular file under the root directory. Symlinks are not
+// followed.
+func (s *SFTPStore) List(ctx context.Context, fn func(Object) error) error {
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	root := s.root
+	if root == "" {
+		root = "/"
+	}
+	walker := client.Walk(root)
+	for walker.Step() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := walker.Err(); err != nil {
+			if walker.Path() == root {
+				return err
+			}
+			continue // unreadable subdirectory
+		}
+		info := walker.Stat()
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		obj := Object{Path: SchemeSFTP + s.authority + walker.Path(), Size: info.Size(), ModTime: info.ModTime().UTC()}
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Open streams the content of a file on the store's server
+func (s *SFTPStore) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	p, err := s.pathOf(path)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.Open(p)
+}
+
+// Delete removes a file from the server
+func (s *SFTPStore) Delete(ctx context.Context, path string) error {
+	p, err := s.pathOf(path)
+	if err != nil {
+		return err
+	}
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	return client.Remove(p)
+}
+
+// Close ends the SSH connection, if one was opened
+func (s *SFTPStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == nil {
+		return nil
+	}
+	s.client.Close()
+	err := s.conn.Close()
+	s.conn, s.client = nil, nil
+	return err
+}
+
+// pathOf returns the remote path of a file URL on the store's server
+func (s *SFTPStore) pathOf(u string) (string, error) {
+	authority, path, err := parseSFTPURL(u)
+	if err != nil {
+		return "", err
+	}
+	if authority != s.authority {
+		return "", fmt.Errorf("%s is not on %s%s", u, SchemeSFTP, s.authority)
+	}
+	return path, nil
+}
@@ -0,0 +1,150 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// serveSFTP runs an SSH server with the SFTP subsystem on a local port, accepting
+// the password "secret", and returns its address and a known_hosts file listing it
+func serveSFTP(t *testing.T) (addr, knownHostsFile string) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+		if c.User() == "backup" && string(pass) == "secret" {
+			return nil, nil
+		}
+		return nil, os.ErrPermission
+	}}
+	config.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			nc, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(nc, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for nch := range chans {
+					ch, requests, err := nch.Accept()
+					if err != nil {
+						continue
+					}
+					go func() {
+						for req := range requests {
+							ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+							req.Reply(ok, nil)
+							if ok {
+								if server, err := sftp.NewServer(ch); err == nil {
+									server.Serve()
+								}
+								ch.Close()
+							}
+						}
+					}()
+				}
+			}()
+		}
+	}()
+
+	knownHostsFile = filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(l.Addr().String())}, signer.PublicKey())
+	if err := os.WriteFile(knownHostsFile, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return l.Addr().String(), knownHostsFile
+}
+
+func TestSFTPStore(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	addr, knownHosts := serveSFTP(t)
+	dir := filepath.ToSlash(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(dir, "2024"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"a.jpg": "hello", "2024/b.jpg": "photo!"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dir, "a.jpg"), filepath.Join(dir, "link.jpg")); err != nil {
+		t.Fatal(err)
+	}
+
+	root := SchemeSFTP + "backup@" + addr + dir
+	store, err := Open(root, Config{SFTP: SFTPConfig{Password: "secret", KnownHostsFile: knownHosts}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.(io.Closer).Close()
+
+	var objects []Object
+	if err := store.List(context.Background(), func(o Object) error {
+		objects = append(objects, o)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Path < objects[j].Path })
+	if len(objects) != 2 || objects[0].Path != root+"/2024/b.jpg" || objects[1].Path != root+"/a.jpg" || objects[1].Size != 5 {
+		t.Fatalf("got files %+v", objects)
+	}
+
+	r, err := store.Open(context.Background(), objects[1].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "hello" {
+		t.Errorf("content: got %q", data)
+	}
+
+	if err := store.(Deleter).Delete(context.Background(), objects[0].Path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2024", "b.jpg")); !os.IsNotExist(err) {
+		t.Errorf("file was not deleted: %v", err)
+	}
+
+	// A server whose host key is not known is refused
+	emptyKnownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(emptyKnownHosts, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	unknown, err := Open(root, Config{SFTP: SFTPConfig{Password: "secret", KnownHostsFile: emptyKnownHosts}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unknown.List(context.Background(), func(Object) error { return nil }); err == nil {
+		t.Error("an unknown host key should be rejected")
+	}
+}
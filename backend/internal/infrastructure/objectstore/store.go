@@ -0,0 +1,71 @@
+// Package objectstore reads scan roots that are not local directories but object
+// store URLs such as s3://bucket/prefix, webdavs://host/path or sftp://user@host/path
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Object is a file in an object store
+type Object struct {
+	Path    string // URL of the object, e.g. s3://bucket/photos/a.jpg
+	Size    int64
+	ModTime time.Time
+	MD5     string // Hex MD5 of the content if the store reports it, e.g. the ETag of a single-part S3 upload
+}
+
+// Store lists and reads the objects under a scan root. Stores holding a connection
+// also implement io.Closer.
+type Store interface {
+	// List calls fn for every object under the root
+	List(ctx context.Context, fn func(Object) error) error
+
+	// Open streams the content of an object, given by its URL
+	Open(ctx context.Context, path string) (io.ReadCloser, error)
+}
+
+// Deleter is implemented by stores whose objects the server may delete itself
+type Deleter interface {
+	// Delete removes an object, given by its URL
+	Delete(ctx context.Context, path string) error
+}
+
+// Config holds the endpoints and credentials of the supported object stores
+type Config struct {
+	S3     S3Config
+	WebDAV WebDAVConfig
+	SFTP   SFTPConfig
+}
+
+// SchemeAgent prefixes the files indexed by remote agents, agent://<name>/<path>.
+// They are remote like object store files, but no store reads them: they are
+// listed and hashed by the agent on its machine.
+const SchemeAgent = "agent://"
+
+// IsRemote reports whether path is an object store URL or an agent file rather than
+// a local path
+func IsRemote(path string) bool {
+	for _, scheme := range []string{SchemeS3, SchemeWebDAV, SchemeWebDAVS, SchemeSFTP, SchemeAgent} {
+		if strings.HasPrefix(path, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Open returns the store of a scan root URL
+func Open(root string, cfg Config) (Store, error) {
+	switch {
+	case strings.HasPrefix(root, SchemeS3):
+		return NewS3Store(root, cfg.S3)
+	case strings.HasPrefix(root, SchemeWebDAV), strings.HasPrefix(root, SchemeWebDAVS):
+		return NewWebDAVStore(root, cfg.WebDAV)
+	case strings.HasPrefix(root, SchemeSFTP):
+		return NewSFTPStore(root, cfg.SFTP)
+	}
+	return nil, fmt.Errorf("unsupported object store URL: %s", root)
+}
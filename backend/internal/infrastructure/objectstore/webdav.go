@@ -0,0 +1,241 @@
+package objectstore
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// URL schemes of WebDAV scan roots, e.g. webdavs://cloud.example.com/remote.php/dav/files/alice/Photos
+// for a Nextcloud folder. webdav:// is served over plain HTTP, webdavs:// over HTTPS.
+const (
+	SchemeWebDAV  = "webdav://"
+	SchemeWebDAVS = "webdavs://"
+)
+
+// WebDAVConfig holds the credentials of WebDAV servers, sent with HTTP basic auth.
+// For Nextcloud, use an app password.
+type WebDAVConfig struct {
+	Username string // Empty = anonymous access
+	Password string
+}
+
+// WebDAVStore lists, reads and deletes the files under a WebDAV collection
+type WebDAVStore struct {
+	cfg    WebDAVConfig
+	scheme string // SchemeWebDAV or SchemeWebDAVS
+	host   string
+	root   string // Unescaped path of the root collection, without a trailing slash
+	client *http.Client
+}
+
+// NewWebDAVStore creates the store of a webdav:// or webdavs:// URL
+func NewWebDAVStore(root string, cfg WebDAVConfig) (*WebDAVStore, error) {
+	scheme, host, path, err := parseWebDAVURL(root)
+	if err != nil {
+		return nil, err
+	}
+	return &WebDAVStore{cfg: cfg, scheme: scheme, host: host, root: strings.TrimSuffix(path, "/"), client: &http.Client{Timeout: 30 * time.Minute}}, nil
+}
+
+// parseWebDAVURL splits a WebDAV URL into its scheme, host and unescaped path
+func parseWebDAVURL(u string) (scheme, host, path string, err error) {
+	var rest string
+	var ok bool
+	for _, scheme = range []string{SchemeWebDAVS, SchemeWebDAV} {
+		if rest, ok = strings.CutPrefix(u, scheme); ok {
+			break
+		}
+	}
+	if !ok {
+		return "", "", "", fmt.Errorf("not a WebDAV URL: %s", u)
+	}
+	host, path, _ = strings.Cut(rest, "/")
+	if host == "" {
+		return "", "", "", fmt.Errorf("WebDAV URL without a host: %s", u)
+	}
+	return scheme, host, "/" + path, nil
+}
+
+// multistatus is the response of PROPFIND
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Status string `xml:"status"`
+			Prop   struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				ContentLength int64    `xml:"getcontentlength"`
+				LastModified  string   `xml:"getlastmodified"`
+				Checksums     []string `xml:"checksums>checksum"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// propfindBody asks for the properties List needs. oc:checksums is reported by
+// Nextcloud and ownCloud for files uploaded by their clients.
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:" xmlns:oc="http://owncloud.org/ns">
+  <d:prop><d:resourcetype/><d:getcontentlength/><d:getlastmodified/><oc:checksums/></d:prop>
+</d:propfind>`
+
+// List calls fn for every file under the root collection. Collections are walked one
+// level at a time, as many servers (Nextcloud among them) refuse "Depth: infinity".
+func (s *WebDAVStore) List(ctx context.Context, fn func(Object) error) error {
+	pending := []string{s.root + "/"}
+	visited := make(map[string]bool)
+	for len(pending) > 0 {
+		dir := pending[0]
+		pending = pending[1:]
+		if visited[dir] {
+			continue
+		}
+		visited[dir] = true
+
+		req, err := s.request(ctx, "PROPFIND", dir, strings.NewReader(propfindBody))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Depth", "1")
+		req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+		resp, err := s.do(req, http.StatusMultiStatus)
+		if err != nil {
+			return err
+		}
+		var ms multistatus
+		err = xml.NewDecoder(resp.Body).Decode(&ms)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse listing of %s%s%s: %w", s.scheme, s.host, dir, err)
+		}
+
+		for _, r := range ms.Responses {
+			href, err := url.Parse(r.Href)
+			if err != nil {
+				continue
+			}
+			path := href.Path
+			if strings.TrimSuffix(path, "/") == strings.TrimSuffix(dir, "/") {
+				continue // the collection itself
+			}
+			for _, ps := range r.Propstat {
+				if !strings.Contains(ps.Status, " 200 ") {
+					continue
+				}
+				if ps.Prop.ResourceType.Collection != nil {
+					pending = append(pending, strings.TrimSuffix(path, "/")+"/")
+					break
+				}
+				obj := Object{Path: s.scheme + s.host + path, Size: ps.Prop.ContentLength, MD5: checksumMD5(ps.Prop.Checksums)}
+				if t, err := http.ParseTime(ps.Prop.LastModified); err == nil {
+					obj.ModTime = t.UTC()
+				}
+				if err := fn(obj); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// checksumMD5 returns the MD5 among oc:checksum values such as
+// "SHA1:0a4d… MD5:5d41… ADLER32:062c…"
+func checksumMD5(checksums []string) string {
+	for _, c := range checksums {
+		for _, field := range strings.Fields(c) {
+			if sum, ok := strings.CutPrefix(field, "MD5:"); ok && len(sum) == 32 {
+				return strings.ToLower(sum)
+			}
+		}
+	}
+	return ""
+}
+
+// Open streams the content of a file on the store's server
+func (s *WebDAVStore) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	p, err := s.pathOf(path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.request(ctx, http.MethodGet, p, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes a file from the server. Nextcloud and ownCloud keep deleted files in
+// their trash bin.
+func (s *WebDAVStore) Delete(ctx context.Context, path string) error {
+	p, err := s.pathOf(path)
+	if err != nil {
+		return err
+	}
+	req, err := s.request(ctx, http.MethodDelete, p, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, http.StatusNoContent, http.StatusOK, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// pathOf returns the unescaped path of a file URL on the store's server
+func (s *WebDAVStore) pathOf(u string) (string, error) {
+	scheme, host, path, err := parseWebDAVURL(u)
+	if err != nil {
+		return "", err
+	}
+	if scheme != s.scheme || host != s.host {
+		return "", fmt.Errorf("%s is not on %s%s", u, s.scheme, s.host)
+	}
+	return path, nil
+}
+
+// request creates a request for an unescaped path on the server
+func (s *WebDAVStore) request(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	u := url.URL{Scheme: "http", Host: s.host, Path: path}
+	if s.scheme == SchemeWebDAVS {
+		u.Scheme = "https"
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.Username != "" {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+	}
+	return req, nil
+}
+
+// do sends a request and fails unless the response has one of the expected statuses
+func (s *WebDAVStore) do(req *http.Request, expected ...int) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+	return nil, fmt.Errorf("WebDAV %s %s failed: %s", req.Method, req.URL.Path, resp.Status)
+}
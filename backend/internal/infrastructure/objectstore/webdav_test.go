@@ -0,0 +1,105 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebDAVStore(t *testing.T) {
+	listings := map[string]string{
+		"/dav/Photos/": `<d:multistatus xmlns:d="DAV:" xmlns:oc="http://owncloud.org/ns">
+			<d:response><d:href>/dav/Photos/</d:href><d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+			<d:response><d:href>/dav/Photos/a%20b.jpg</d:href><d:propstat><d:prop><d:resourcetype/><d:getcontentlength>5</d:getcontentlength>
+				<d:getlastmodified>Wed, 01 May 2024 12:00:00 GMT</d:getlastmodified>
+				<oc:checksums><oc:checksum>SHA1:aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d MD5:5D41402ABC4B2A76B9719D911017C592</oc:checksum></oc:checksums></d:prop>
+				<d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+				<d:propstat><d:prop><oc:missing/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat></d:response>
+			<d:response><d:href>/dav/Photos/2024/</d:href><d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+		</d:multistatus>`,
+		"/dav/Photos/2024/": `<d:multistatus xmlns:d="DAV:">
+			<d:response><d:href>/dav/Photos/2024/</d:href><d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+			<d:response><d:href>/dav/Photos/2024/c.jpg</d:href><d:propstat><d:prop><d:resourcetype/><d:getcontentlength>7</d:getcontentlength></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+		</d:multistatus>`,
+	}
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "app-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case "PROPFIND":
+			if r.Header.Get("Depth") != "1" {
+				t.Errorf("Depth: got %q", r.Header.Get("Depth"))
+			}
+			listing, ok := listings[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, listing)
+		case http.MethodGet:
+			if r.URL.EscapedPath() != "/dav/Photos/a%20b.jpg" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, "hello")
+		case http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	root := SchemeWebDAV + strings.TrimPrefix(srv.URL, "http://") + "/dav/Photos"
+	store, err := Open(root, Config{WebDAV: WebDAVConfig{Username: "alice", Password: "app-password"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var objects []Object
+	if err := store.List(context.Background(), func(o Object) error {
+		objects = append(objects, o)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Path < objects[j].Path })
+	if len(objects) != 2 {
+		t.Fatalf("got %d files, want 2: %+v", len(objects), objects)
+	}
+	if objects[0].Path != root+"/2024/c.jpg" || objects[0].Size != 7 || objects[0].MD5 != "" {
+		t.Errorf("nested file: got %+v", objects[0])
+	}
+	if objects[1].Path != root+"/a b.jpg" || objects[1].Size != 5 || objects[1].MD5 != "5d41402abc4b2a76b9719d911017c592" ||
+		!objects[1].ModTime.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("file with checksum: got %+v", objects[1])
+	}
+
+	r, err := store.Open(context.Background(), objects[1].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "hello" {
+		t.Errorf("content: got %q", data)
+	}
+
+	if err := store.(Deleter).Delete(context.Background(), objects[1].Path); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0] != "/dav/Photos/a b.jpg" {
+		t.Errorf("deleted: got %v", deleted)
+	}
+	if err := store.(Deleter).Delete(context.Background(), "webdavs://elsewhere/a.jpg"); err == nil {
+		t.Error("a file on another server should be rejected")
+	}
+}
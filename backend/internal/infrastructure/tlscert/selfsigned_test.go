@@ -0,0 +1,37 @@
+package tlscert
+
+import (
+	"crypto/tls"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnsureSelfSigned(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "sub", "key.pem")
+	hosts := []string{"localhost", "127.0.0.1", "nas.local"}
+
+	created, err := EnsureSelfSigned(certFile, keyFile, hosts)
+	if err != nil || !created {
+		t.Fatalf("EnsureSelfSigned = %v, %v; want new certificate", created, err)
+	}
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		t.Fatalf("generated pair does not load: %v", err)
+	}
+
+	if created, err := EnsureSelfSigned(certFile, keyFile, hosts); err != nil || created {
+		t.Errorf("second call = %v, %v; want existing certificate reused", created, err)
+	}
+
+	// A new LAN address is not covered by the old certificate
+	if created, err := EnsureSelfSigned(certFile, keyFile, append(hosts, "192.168.1.20")); err != nil || !created {
+		t.Errorf("call with new host = %v, %v; want regenerated certificate", created, err)
+	}
+	cert, err := readCertificate(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cert.VerifyHostname("192.168.1.20"); err != nil {
+		t.Errorf("certificate does not cover new host: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"image-toolkit/internal/domain"
+)
+
+// --- Agent API ---
+
+// AgentSyncStartResponse is the JSON response for POST /api/agent/sync/start: the
+// time on the central server the agent passes back when finishing the sync
+type AgentSyncStartResponse struct {
+	Since time.Time `json:"since"`
+}
+
+// AgentFilesRequest reports a batch of files indexed by an agent, with their paths on
+// the agent
+type AgentFilesRequest struct {
+	Agent string             `json:"agent" binding:"required"`
+	Files []domain.ImageFile `json:"files"`
+}
+
+// AgentFilesResponse is the JSON response for POST /api/agent/files
+type AgentFilesResponse struct {
+	Stored int `json:"stored"`
+}
+
+// AgentSyncFinishRequest ends the sync of an agent: records under its scanned roots
+// not reported since the sync started are removed
+type AgentSyncFinishRequest struct {
+	Agent string    `json:"agent" binding:"required"`
+	Roots []string  `json:"roots"`
+	Since time.Time `json:"since" binding:"required"`
+}
+
+// AgentSyncFinishResponse is the JSON response for POST /api/agent/sync/finish
+type AgentSyncFinishResponse struct {
+	Removed int64 `json:"removed"`
+}
+
+// HostDTO is a machine files are indexed on
+type HostDTO struct {
+	Name  string `json:"name"`  // agent name, "local" for this server
+	Local bool   `json:"local"` // this server
+	Files int64  `json:"files"`
+}
+
+// HostsResponse is the JSON response for GET /api/hosts
+type HostsResponse struct {
+	Hosts []HostDTO `json:"hosts"`
+}
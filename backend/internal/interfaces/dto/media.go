@@ -4,6 +4,11 @@ package dto
 
 // DuplicatesResponse is the JSON response for GET /api/duplicates
 type DuplicatesResponse struct {
+	Mode        string              `json:"mode"`                // "exact", "similar", "pixel", "scaled", "burst", "name" or "exif"
+	Sort        string              `json:"sort,omitempty"`      // "wasted-space", "file-count", "newest", "oldest" or "path"; empty for the mode's order
+	Media       string              `json:"media"`               // "image" or "video"
+	Threshold   *int                `json:"threshold,omitempty"` // Hamming distance threshold in "similar", "scaled" and "burst" modes
+	Window      int                 `json:"window,omitempty"`    // Longest gap between shots in seconds in "burst" mode
 	Groups      []DuplicateGroupDTO `json:"groups"`
 	TotalFiles  int                 `json:"totalFiles"`
 	PageFiles   int                 `json:"pageFiles"`
@@ -24,7 +29,17 @@ type DuplicateGroupDTO struct {
 	Size      int64     `json:"size"`
 	SizeHuman string    `json:"sizeHuman"`
 	Files     []FileDTO `json:"files"`
-	Thumbnail string    `json:"thumbnail"`
+	Thumbnail string    `json:"thumbnail"` // URL of the thumbnail of the first file
+	// Review status of an exact duplicate group: reviewed, deferred or empty
+	Review string `json:"review,omitempty"`
+}
+
+// GroupResponse is the JSON response for GET /api/groups/:hash. The group index is 0,
+// as the group is shown outside of a listing.
+type GroupResponse struct {
+	Group            DuplicateGroupDTO `json:"group"`
+	ReclaimableBytes int64             `json:"reclaimableBytes"` // freed by keeping a single file
+	ReclaimableHuman string            `json:"reclaimableHuman"`
 }
 
 // FileDTO represents a file in JSON responses
@@ -33,7 +48,79 @@ type FileDTO struct {
 	Path     string `json:"path"`
 	FileName string `json:"fileName"`
 	DirPath  string `json:"dirPath"`
+	Size     int64  `json:"size"`
 	ModTime  string `json:"modTime"`
+	// Similarity to the first file of the group in [0, 1], 1 for exact duplicates
+	Similarity float64 `json:"similarity"`
+	// Width, Height and Recommended are only set for scaled duplicates, bursts and
+	// copies by EXIF, where the original or the best frame is recommended for keeping
+	Width       int  `json:"width,omitempty"`
+	Height      int  `json:"height,omitempty"`
+	Recommended bool `json:"recommended,omitempty"`
+	// Capture time from EXIF, only set for bursts and copies by EXIF
+	TakenAt string `json:"takenAt,omitempty"`
+	// Pinned against deletion
+	Protected bool `json:"protected,omitempty"`
+	// Agent that reported the file, empty for the files of this server
+	Host string `json:"host,omitempty"`
+	// Empty, or an image that fails to decode
+	Corrupt bool `json:"corrupt,omitempty"`
+}
+
+// SearchResponse is the JSON response for GET /api/search
+type SearchResponse struct {
+	Query string            `json:"query"`
+	Files []SearchResultDTO `json:"files"`
+	Total int               `json:"total"` // all matching files, possibly more than returned
+	Limit int               `json:"limit"`
+}
+
+// SearchResultDTO is a file matching a search with the other copies of its content
+type SearchResultDTO struct {
+	FileDTO
+	Hash       string    `json:"hash,omitempty"` // content hash, empty until the file is hashed
+	Duplicates []FileDTO `json:"duplicates"`     // files with the same content, empty if none
+}
+
+// StatsResponse is the JSON response for GET /api/stats. Sizes are in bytes.
+type StatsResponse struct {
+	IndexedFiles     int64           `json:"indexedFiles"`
+	IndexedBytes     int64           `json:"indexedBytes"`
+	DuplicateGroups  int64           `json:"duplicateGroups"`
+	DuplicateFiles   int64           `json:"duplicateFiles"` // including the copy to keep
+	DuplicateBytes   int64           `json:"duplicateBytes"`
+	ReclaimableBytes int64           `json:"reclaimableBytes"` // freed by keeping one file per group
+	Directories      []StatsShareDTO `json:"directories"`      // most duplicate bytes first
+	Extensions       []StatsShareDTO `json:"extensions"`       // most duplicate bytes first
+	LargestGroups    []StatsGroupDTO `json:"largestGroups"`    // most reclaimable bytes first
+	Months           []StatsMonthDTO `json:"months"`           // oldest first
+}
+
+// StatsShareDTO is the part of the duplicate files in one directory or with one
+// extension ("" for files without one)
+type StatsShareDTO struct {
+	Key   string `json:"key"`
+	Files int64  `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+// StatsGroupDTO is one of the duplicate groups freeing the most space
+type StatsGroupDTO struct {
+	Hash             string `json:"hash"`
+	Size             int64  `json:"size"`
+	Files            int64  `json:"files"`
+	ReclaimableBytes int64  `json:"reclaimableBytes"`
+	Path             string `json:"path"` // first path of the group
+}
+
+// StatsMonthDTO counts the duplicate files indexed and the files moved to the trash
+// folder, and not restored, in one month
+type StatsMonthDTO struct {
+	Month          string `json:"month"` // YYYY-MM
+	DuplicateFiles int64  `json:"duplicateFiles"`
+	DuplicateBytes int64  `json:"duplicateBytes"`
+	DeletedFiles   int64  `json:"deletedFiles"`
+	DeletedBytes   int64  `json:"deletedBytes"`
 }
 
 // --- Scan API ---
@@ -42,6 +129,56 @@ type FileDTO struct {
 // Message is a i18n key string (e.g., "scan.started")
 type ScanResponse struct {
 	Message string `json:"message"`
+	JobID   string `json:"jobId,omitempty"` // ID for GET /api/scan/jobs/:id
+}
+
+// ScanJobDTO is the JSON response for GET /api/scan/jobs/:id
+type ScanJobDTO struct {
+	ID             string  `json:"id"`
+	Kind           string  `json:"kind"`
+	State          string  `json:"state"` // queued, running, done, failed or cancelled
+	Progress       string  `json:"progress"`
+	FilesProcessed int     `json:"filesProcessed"`
+	Errors         int     `json:"errors"`
+	Error          string  `json:"error,omitempty"`
+	CreatedAt      string  `json:"createdAt"`
+	StartedAt      *string `json:"startedAt,omitempty"`
+	FinishedAt     *string `json:"finishedAt,omitempty"`
+	ElapsedMs      int64   `json:"elapsedMs"`
+}
+
+// ScheduleDTO is the JSON response for GET /api/schedule
+type ScheduleDTO struct {
+	Schedule string  `json:"schedule"`          // Cron expression, empty if disabled
+	NextRun  *string `json:"nextRun,omitempty"` // RFC 3339 time of the next scheduled scan
+}
+
+// UpdateScheduleRequest is the JSON request for PUT /api/schedule
+type UpdateScheduleRequest struct {
+	Schedule string `json:"schedule"`
+}
+
+// ScanRunDTO represents a recorded scan run
+type ScanRunDTO struct {
+	ID             uint     `json:"id"`
+	Kind           string   `json:"kind"`    // scan, scan-dir, fast-scan, fast-scan-dir or rehash
+	Trigger        string   `json:"trigger"` // manual or scheduled
+	Status         string   `json:"status"`  // running, completed, cancelled or failed
+	Directories    []string `json:"directories"`
+	FilesProcessed int      `json:"filesProcessed"`
+	FilesAdded     int      `json:"filesAdded"`
+	FilesUpdated   int      `json:"filesUpdated"`
+	FilesRemoved   int      `json:"filesRemoved"`
+	Errors         int      `json:"errors"`
+	Error          string   `json:"error,omitempty"`
+	StartedAt      string   `json:"startedAt"`
+	FinishedAt     *string  `json:"finishedAt,omitempty"`
+	ElapsedMs      int64    `json:"elapsedMs,omitempty"` // set once the run has finished
+}
+
+// ScanRunsResponse is the JSON response for GET /api/scan-runs
+type ScanRunsResponse struct {
+	Runs []ScanRunDTO `json:"runs"`
 }
 
 // FastScanResponse is the JSON response for POST /api/fast-scan
@@ -51,6 +188,7 @@ type FastScanResponse struct {
 	Modified  int    `json:"modified"`  // Files that were modified (size changed)
 	Created   int    `json:"created"`   // New files added
 	Deleted   int    `json:"deleted"`   // Records removed from DB (files no longer exist)
+	Moved     int    `json:"moved"`     // Records moved to the new path of a moved or renamed file
 	Total     int    `json:"total"`     // Total checked (modified + created)
 }
 
@@ -60,6 +198,18 @@ type FastScanResponse struct {
 type DeleteFilesRequest struct {
 	FilePaths []string `json:"filePaths"`
 	TrashDir  string   `json:"trashDir"`
+	// SystemTrash moves files to the trash of the server's OS user instead of TrashDir
+	SystemTrash bool `json:"systemTrash,omitempty"`
+	// Verify compares each file byte by byte with a remaining copy first and skips
+	// it on mismatch (hash collision, or modified since the last scan)
+	Verify bool `json:"verify,omitempty"`
+	// Replace leaves a "relative-symlink" or "absolute-symlink" to a remaining copy
+	// in place of each removed file, or with "reflink" turns the file into a
+	// copy-on-write clone of it; empty removes the file only
+	Replace string `json:"replace,omitempty"`
+	// UseSelection deletes the files marked delete in the stored selection of the
+	// session when FilePaths is empty
+	UseSelection bool `json:"useSelection,omitempty"`
 }
 
 // DeleteFilesResponse represents the response from file deletion
@@ -67,6 +217,68 @@ type DeleteFilesResponse struct {
 	Success     int      `json:"success"`
 	Failed      int      `json:"failed"`
 	FailedFiles []string `json:"failedFiles,omitempty"`
+	BatchID     string   `json:"batchId,omitempty"` // set when files were moved to the trash folder; see POST /restore/:batchId
+	// ProtectedFiles are the requested files left in place because they are pinned
+	ProtectedFiles []string `json:"protectedFiles,omitempty"`
+}
+
+// GenerateScriptRequest is the request body for POST /api/generate-script
+type GenerateScriptRequest struct {
+	FilePaths  []string `json:"filePaths"`
+	TrashDir   string   `json:"trashDir"`             // the script moves files here; empty deletes them
+	OutputDir  string   `json:"outputDir"`            // server folder the scripts are written to, unless Download is set
+	ScriptType string   `json:"scriptType,omitempty"` // bash (default), sh, powershell or bat
+	// Encoding is utf-8, utf-8-bom, utf-16le or a code page such as windows-1252;
+	// empty selects windows-1251 for powershell and utf-8 otherwise
+	Encoding string `json:"encoding,omitempty"`
+	// LineEndings is lf or crlf (powershell and bat only); empty selects crlf for
+	// powershell and bat
+	LineEndings string `json:"lineEndings,omitempty"`
+	// Restore also writes restore_duplicates.sh/.ps1, which moves every file back
+	// from TrashDir to its original location
+	Restore bool `json:"restore,omitempty"`
+	// Download returns the script as an attachment instead of writing it on the
+	// server, for clients on another machine; with Restore it returns the restore
+	// script only
+	Download bool `json:"download,omitempty"`
+}
+
+// GenerateScriptResponse is the JSON response for POST /api/generate-script
+type GenerateScriptResponse struct {
+	ScriptPath        string `json:"scriptPath"`
+	RestoreScriptPath string `json:"restoreScriptPath,omitempty"`
+	Files             int    `json:"files"`
+	// RemoteFiles are objects in an object store, left out of the script; S3
+	// objects can be removed with a plan from POST /api/deletion-plan/s3
+	RemoteFiles []string `json:"remoteFiles,omitempty"`
+}
+
+// S3DeletionPlanRequest is the request body for POST /api/deletion-plan/s3
+type S3DeletionPlanRequest struct {
+	FilePaths []string `json:"filePaths"` // s3:// URLs of objects in the gallery folders
+}
+
+// S3DeletionPlanResponse is the JSON response for POST /api/deletion-plan/s3
+type S3DeletionPlanResponse struct {
+	Batches []S3DeleteBatchDTO `json:"batches"`
+}
+
+// S3DeleteBatchDTO is one DeleteObjects request of at most 1000 keys. Delete is the
+// body for `aws s3api delete-objects --bucket <bucket> --delete file://batch.json`.
+type S3DeleteBatchDTO struct {
+	Bucket string             `json:"bucket"`
+	Delete S3DeleteObjectsDTO `json:"delete"`
+}
+
+// S3DeleteObjectsDTO lists the keys of a DeleteObjects request
+type S3DeleteObjectsDTO struct {
+	Objects []S3ObjectIDDTO `json:"Objects"`
+	Quiet   bool            `json:"Quiet"`
+}
+
+// S3ObjectIDDTO identifies an object to delete
+type S3ObjectIDDTO struct {
+	Key string `json:"Key"`
 }
 
 // --- Folder Patterns API ---
@@ -86,10 +298,27 @@ type FolderPatternsResponse struct {
 
 // --- Batch Delete API ---
 
-// BatchDeleteRequest represents a request for batch deletion
+// BatchDeleteRequest represents a request for batch deletion. With UseSelection,
+// groups with files marked in the stored selection of the session are decided by
+// the marks. Groups whose folder pattern has a rule keep the files in its folder;
+// all other groups are decided by KeepRules when given, and left untouched otherwise.
 type BatchDeleteRequest struct {
-	Rules    []BatchDeleteRule `json:"rules"`
-	TrashDir string            `json:"trashDir"`
+	Rules        []BatchDeleteRule `json:"rules"`
+	KeepRules    []KeepRuleDTO     `json:"keepRules,omitempty"`
+	UseSelection bool              `json:"useSelection,omitempty"`
+	TrashDir     string            `json:"trashDir"`
+	SystemTrash  bool              `json:"systemTrash,omitempty"` // as in DeleteFilesRequest
+	Verify       bool              `json:"verify,omitempty"`      // as in DeleteFilesRequest, skipping the whole group on mismatch
+	Replace      string            `json:"replace,omitempty"`     // as in DeleteFilesRequest, linking to the kept file
+}
+
+// KeepRuleDTO is a server-side keep policy, applied in order as tie-breakers:
+// keep-oldest, keep-newest, keep-shortest-path, keep-largest-resolution,
+// keep-first-scan-dir or keep-in-priority-directory (with directories from
+// highest to lowest priority)
+type KeepRuleDTO struct {
+	Policy      string   `json:"policy"`
+	Directories []string `json:"directories,omitempty"`
 }
 
 // BatchDeleteRule specifies which folder to keep for a pattern
@@ -98,20 +327,155 @@ type BatchDeleteRule struct {
 	KeepFolder string `json:"keepFolder"`
 }
 
+// AutoSelectRequest is the JSON body for POST /api/auto-select
+type AutoSelectRequest struct {
+	KeepRules []KeepRuleDTO `json:"keepRules"`
+	Mode      string        `json:"mode,omitempty"`      // exact (default), similar, pixel, scaled, burst, name or exif
+	Threshold *int          `json:"threshold,omitempty"` // similar, scaled and burst modes, defaults to SIMILARITY_THRESHOLD
+	Window    *int          `json:"window,omitempty"`    // burst mode, seconds, defaults to BURST_WINDOW_SECONDS
+	Media     string        `json:"media,omitempty"`     // image (default) or video
+	Dir       string        `json:"dir,omitempty"`       // only files under this absolute directory
+	Ext       string        `json:"ext,omitempty"`       // only files with these comma-separated extensions
+	MinSize   string        `json:"minSize,omitempty"`   // only files at least this large, e.g. "1MB"
+	MaxSize   string        `json:"maxSize,omitempty"`   // only files at most this large
+	Review    string        `json:"review,omitempty"`    // exact mode, only groups of this review status
+	Hosts     string        `json:"hosts,omitempty"`     // exact mode, only groups with copies on all these comma-separated hosts
+	Month     string        `json:"month,omitempty"`     // exact mode, only groups shot in this month, YYYY-MM
+	Near      string        `json:"near,omitempty"`      // exact mode, only groups with a copy shot near "latitude,longitude"
+	Radius    string        `json:"radius,omitempty"`    // around near, km, 10 by default
+}
+
+// AutoSelectGroupDTO is the suggestion for one duplicate group
+type AutoSelectGroupDTO struct {
+	Hash   string   `json:"hash"`
+	Keep   string   `json:"keep"`
+	Remove []string `json:"remove"`
+}
+
+// AutoSelectResponse is the JSON response for POST /api/auto-select. Nothing is
+// deleted; Remove lists are meant to pre-select files for review.
+type AutoSelectResponse struct {
+	Mode             string               `json:"mode"`
+	Groups           []AutoSelectGroupDTO `json:"groups"`
+	TotalRemove      int                  `json:"totalRemove"`
+	ReclaimableBytes int64                `json:"reclaimableBytes"`
+	ReclaimableHuman string               `json:"reclaimableHuman"`
+}
+
 // BatchDeleteResponse represents the response from batch deletion
 type BatchDeleteResponse struct {
 	Success     int      `json:"success"`
 	Failed      int      `json:"failed"`
 	FailedFiles []string `json:"failedFiles,omitempty"`
+	BatchID     string   `json:"batchId,omitempty"` // as in DeleteFilesResponse
+	// ProtectedFiles are the duplicates left in place because they are pinned
+	ProtectedFiles []string `json:"protectedFiles,omitempty"`
+	FreedBytes     int64    `json:"freedBytes"` // total size of the removed files
+}
+
+// ConsolidateRequest moves the kept file of each exact duplicate group into the
+// library folder Destination and removes the other copies. The keeper is chosen by
+// KeepRules, or with UseSelection by the first file marked keep in the stored
+// selection (other files marked keep stay in place).
+type ConsolidateRequest struct {
+	Destination  string        `json:"destination" binding:"required"`
+	ByDate       bool          `json:"byDate,omitempty"` // file into year/month folders by the EXIF date taken, else the modification time
+	KeepRules    []KeepRuleDTO `json:"keepRules,omitempty"`
+	UseSelection bool          `json:"useSelection,omitempty"`
+	TrashDir     string        `json:"trashDir"`
+	SystemTrash  bool          `json:"systemTrash,omitempty"` // as in DeleteFilesRequest
+	Verify       bool          `json:"verify,omitempty"`      // as in BatchDeleteRequest
+}
+
+// ConsolidateResponse represents the response from a consolidation: Moved kept
+// files and the removal of the other copies as in BatchDeleteResponse
+type ConsolidateResponse struct {
+	Moved          int      `json:"moved"`
+	Success        int      `json:"success"`
+	Failed         int      `json:"failed"`
+	FailedFiles    []string `json:"failedFiles,omitempty"`
+	BatchID        string   `json:"batchId,omitempty"`
+	ProtectedFiles []string `json:"protectedFiles,omitempty"`
+}
+
+// --- Deletions API ---
+
+// DeletedFileDTO is a file moved to the trash folder
+type DeletedFileDTO struct {
+	OriginalPath string  `json:"originalPath"`
+	TrashPath    string  `json:"trashPath"`
+	Size         int64   `json:"size"`
+	RestoredAt   *string `json:"restoredAt,omitempty"`
+}
+
+// DeletionBatchDTO is the set of files moved to the trash by one request
+type DeletionBatchDTO struct {
+	BatchID       string           `json:"batchId"`
+	DeletedAt     string           `json:"deletedAt"`
+	FileCount     int              `json:"fileCount"`
+	RestoredCount int              `json:"restoredCount"`
+	TotalSize     int64            `json:"totalSize"`
+	Files         []DeletedFileDTO `json:"files"`
+}
+
+// CorruptFilesResponse is the JSON response for GET /api/corrupt-files
+type CorruptFilesResponse struct {
+	Files     []FileDTO `json:"files"`
+	Total     int64     `json:"total"`
+	TotalSize int64     `json:"totalSize"`
 }
 
-// --- Thumbnail API ---
+// TimelineResponse is the JSON response for GET /api/timeline
+type TimelineResponse struct {
+	Months []TimelineMonthDTO `json:"months"` // oldest first
+}
+
+// TimelineMonthDTO counts the exact duplicate groups shot in one month
+type TimelineMonthDTO struct {
+	Month            string `json:"month"` // YYYY-MM
+	Groups           int64  `json:"groups"`
+	Files            int64  `json:"files"`            // including the copy to keep
+	ReclaimableBytes int64  `json:"reclaimableBytes"` // freed by keeping one file per group
+}
+
+// NameConflictsResponse is the JSON response for GET /api/name-conflicts
+type NameConflictsResponse struct {
+	TotalConflicts int               `json:"totalConflicts"`
+	TotalFiles     int               `json:"totalFiles"`
+	Conflicts      []NameConflictDTO `json:"conflicts"`
+}
+
+// NameConflictDTO is a file name shared by files of different content
+type NameConflictDTO struct {
+	Name     string                `json:"name"`
+	Versions int                   `json:"versions"`
+	Files    []NameConflictFileDTO `json:"files"`
+}
+
+// NameConflictFileDTO is a file of a name conflict; files of one version are identical
+type NameConflictFileDTO struct {
+	ID      uint   `json:"id"`
+	Path    string `json:"path"`
+	DirPath string `json:"dirPath"`
+	Size    int64  `json:"size"`
+	ModTime string `json:"modTime"`
+	Version int    `json:"version"`
+}
 
-// ThumbnailResponse is the JSON response for GET /api/thumbnail
-type ThumbnailResponse struct {
-	Thumbnail string `json:"thumbnail"`
+// DeletionsResponse is the JSON response for GET /api/deletions
+type DeletionsResponse struct {
+	Batches []DeletionBatchDTO `json:"batches"`
 }
 
+// RestoreResponse is the JSON response for POST /api/restore/:batchId
+type RestoreResponse struct {
+	Restored    int      `json:"restored"`
+	Failed      int      `json:"failed"`
+	FailedFiles []string `json:"failedFiles,omitempty"`
+}
+
+// --- Thumbnail API ---
+
 // ThumbnailCacheStatsResponse статистика кэша миниатюр
 type ThumbnailCacheStatsResponse struct {
 	TotalSize   int64  `json:"totalSize"`
@@ -119,6 +483,19 @@ type ThumbnailCacheStatsResponse struct {
 	CacheDir    string `json:"cacheDir"`
 	Enabled     bool   `json:"enabled"`
 	Initialized bool   `json:"initialized"`
+	// Memory describes the in-memory cache used when the disk cache is unavailable
+	Memory MemoryThumbnailCacheDTO `json:"memory"`
+}
+
+// MemoryThumbnailCacheDTO статистика кэша миниатюр в памяти (LRU)
+type MemoryThumbnailCacheDTO struct {
+	Entries    int   `json:"entries"`
+	Bytes      int64 `json:"bytes"`
+	MaxEntries int   `json:"maxEntries"` // 0 = unlimited
+	MaxBytes   int64 `json:"maxBytes"`   // 0 = unlimited
+	Hits       int64 `json:"hits"`
+	Misses     int64 `json:"misses"`
+	Evictions  int64 `json:"evictions"`
 }
 
 // InvalidateThumbnailRequest запрос на удаление миниатюры
@@ -207,6 +584,8 @@ type AppSettingsDTO struct {
 	TrashDir           string `json:"trashDir"`
 	ThumbnailCachePath string `json:"thumbnailCachePath,omitempty"`
 	ThumbnailCacheSize int    `json:"thumbnailCacheSize,omitempty"`
+	VideoScanEnabled   bool   `json:"videoScanEnabled"` // read-only, set by VIDEO_SCAN_ENABLED
+	PixelHashEnabled   bool   `json:"pixelHashEnabled"` // read-only, set by PIXEL_HASH_ENABLED
 }
 
 // UserSettingsDTO is the JSON response for user settings
@@ -461,3 +840,61 @@ type LlmRecognizeStatusResponse struct {
 	ProcessingTimeMs int    `json:"processingTimeMs,omitempty"`
 	Error            string `json:"error,omitempty"`
 }
+
+// SelectionItemDTO is a file marked in the duplicate view with action "keep" or
+// "delete"; in an update, an empty action removes the mark
+type SelectionItemDTO struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+}
+
+// UpdateSelectionRequest stores marks in the selection of the session
+type UpdateSelectionRequest struct {
+	Items []SelectionItemDTO `json:"items"`
+}
+
+// SelectionResponse is the stored selection of the session
+type SelectionResponse struct {
+	Items []SelectionItemDTO `json:"items"`
+}
+
+// IgnoredGroupDTO is an exact duplicate group marked as not duplicates; Path and
+// Files describe the group when it was ignored
+type IgnoredGroupDTO struct {
+	ID        uint   `json:"id"`
+	Hash      string `json:"hash"`
+	HashAlgo  string `json:"hashAlgo"`
+	Size      int64  `json:"size"`
+	SizeHuman string `json:"sizeHuman"`
+	Path      string `json:"path"`
+	Files     int    `json:"files"`
+	IgnoredAt string `json:"ignoredAt"`
+}
+
+// IgnoredGroupsResponse is the JSON response for GET /api/ignored-groups
+type IgnoredGroupsResponse struct {
+	Groups []IgnoredGroupDTO `json:"groups"`
+}
+
+// SetProtectedRequest pins indexed files against deletion, or unpins them
+type SetProtectedRequest struct {
+	FilePaths []string `json:"filePaths" binding:"required"`
+	Protected bool     `json:"protected"`
+}
+
+// SetProtectedResponse is the JSON response for PUT /api/files/protected
+type SetProtectedResponse struct {
+	Updated int64 `json:"updated"` // indexed files whose flag changed
+}
+
+// ReviewGroupRequest sets the review status of an exact duplicate group: reviewed,
+// deferred, or unreviewed to clear it
+type ReviewGroupRequest struct {
+	Status string `json:"status"`
+}
+
+// ReviewGroupResponse is the JSON response for PUT /api/groups/:hash/review
+type ReviewGroupResponse struct {
+	Hash   string `json:"hash"`
+	Status string `json:"status"` // empty when unreviewed
+}
@@ -0,0 +1,281 @@
+// Package email sends HTML summaries of scheduled scans over SMTP, for servers that
+// run unattended
+package email
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+)
+
+const (
+	// reportGroupLimit bounds the new duplicate groups listed in a report
+	reportGroupLimit = 20
+	// dialTimeout bounds connecting to the SMTP server
+	dialTimeout = 30 * time.Second
+)
+
+// Config is the SMTP server and the recipients of the reports. Port 465 uses
+// implicit TLS; other ports upgrade with STARTTLS when the server offers it.
+type Config struct {
+	Host     string
+	Port     int
+	Username string // empty = no authentication
+	Password string
+	From     string
+	To       []string
+}
+
+// Reporter emails a summary after each scheduled scan
+type Reporter struct {
+	config      Config
+	scanManager *imaging.ScanManager
+}
+
+// NewReporter validates the configuration and creates a reporter
+func NewReporter(config Config, scanManager *imaging.ScanManager) (*Reporter, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("SMTP host is not set")
+	}
+	if config.Port <= 0 || config.Port > 65535 {
+		return nil, fmt.Errorf("invalid SMTP port %d", config.Port)
+	}
+	if _, err := mail.ParseAddress(config.From); err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", config.From, err)
+	}
+	if len(config.To) == 0 {
+		return nil, fmt.Errorf("no report recipients")
+	}
+	for _, to := range config.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return nil, fmt.Errorf("invalid recipient address %q: %w", to, err)
+		}
+	}
+	return &Reporter{config: config, scanManager: scanManager}, nil
+}
+
+// Start emails the summaries of scheduled scans in the background until stop is called
+func (r *Reporter) Start() (stop func()) {
+	ch, unsubscribe := r.scanManager.Events.Subscribe()
+	go func() {
+		for event := range ch {
+			summary, ok := event.Data.(imaging.ScanSummary)
+			if !ok || summary.Run.Trigger != domain.ScanTriggerScheduled {
+				continue
+			}
+			go func() {
+				if err := r.Send(summary); err != nil {
+					slog.Error("Failed to email scan report", "job", summary.JobID, "error", err)
+				} else {
+					slog.Info("Scan report emailed", "job", summary.JobID, "recipients", len(r.config.To))
+				}
+			}()
+		}
+	}()
+	return unsubscribe
+}
+
+// Send emails the report of a scan
+func (r *Reporter) Send(summary imaging.ScanSummary) error {
+	msg, err := buildMessage(r.config.From, r.config.To, summary, time.Now())
+	if err != nil {
+		return err
+	}
+	return r.deliver(msg)
+}
+
+// deliver hands a message to the SMTP server
+func (r *Reporter) deliver(msg []byte) error {
+	addr := net.JoinHostPort(r.config.Host, strconv.Itoa(r.config.Port))
+	tlsConfig := &tls.Config{ServerName: r.config.Host}
+
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return err
+	}
+	if r.config.Port == 465 {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, r.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if r.config.Username != "" {
+		// PlainAuth refuses to send the password unencrypted, except to localhost
+		if err := client.Auth(smtp.PlainAuth("", r.config.Username, r.config.Password, r.config.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(envelopeAddress(r.config.From)); err != nil {
+		return err
+	}
+	for _, to := range r.config.To {
+		if err := client.Rcpt(envelopeAddress(to)); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// envelopeAddress returns the bare address of "Name <address>"
+func envelopeAddress(s string) string {
+	if addr, err := mail.ParseAddress(s); err == nil {
+		return addr.Address
+	}
+	return s
+}
+
+// Subject returns the subject line of the report of a scan
+func Subject(summary imaging.ScanSummary) string {
+	if summary.Run.Status != domain.ScanRunCompleted {
+		return fmt.Sprintf("Image dedup: scheduled scan %s", summary.Run.Status)
+	}
+	return fmt.Sprintf("Image dedup: %d new duplicate groups, %s reclaimable",
+		len(summary.NewGroups), imaging.FormatSize(summary.Duplicates.ReclaimableBytes))
+}
+
+// buildMessage renders the report of a scan as an HTML email
+func buildMessage(from string, to []string, summary imaging.ScanSummary, now time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	if err := reportTemplate.Execute(&body, reportData(summary)); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", Subject(summary)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&msg)
+	if _, err := qp.Write(body.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// report is the data of reportTemplate
+type report struct {
+	Run              domain.ScanRun
+	Directories      []string
+	Duration         string
+	NewGroups        []reportGroup
+	MoreGroups       int // new groups beyond reportGroupLimit
+	NewGroupCount    int
+	NewReclaimable   string
+	TotalGroups      int64
+	TotalFiles       int64
+	TotalReclaimable string
+}
+
+type reportGroup struct {
+	Size        string
+	Reclaimable string
+	Paths       []string
+}
+
+// reportData prepares a summary for the template
+func reportData(summary imaging.ScanSummary) report {
+	run := summary.Run
+	data := report{
+		Run:              run,
+		NewGroupCount:    len(summary.NewGroups),
+		NewReclaimable:   imaging.FormatSize(summary.NewGroupsReclaimableBytes()),
+		TotalGroups:      summary.Duplicates.Groups,
+		TotalFiles:       summary.Duplicates.Files,
+		TotalReclaimable: imaging.FormatSize(summary.Duplicates.ReclaimableBytes),
+	}
+	if run.Directories != "" {
+		data.Directories = strings.Split(run.Directories, "\n")
+	}
+	if run.FinishedAt != nil {
+		data.Duration = run.FinishedAt.Sub(run.StartedAt).Round(time.Second).String()
+	}
+	for i, g := range summary.NewGroups {
+		if i == reportGroupLimit {
+			data.MoreGroups = len(summary.NewGroups) - reportGroupLimit
+			break
+		}
+		data.NewGroups = append(data.NewGroups, reportGroup{
+			Size:        imaging.FormatSize(g.Size),
+			Reclaimable: imaging.FormatSize(g.Size * int64(len(g.Paths)-1)),
+			Paths:       g.Paths,
+		})
+	}
+	return data
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; font-size: 14px; color: #222;">
+<h2>Scheduled scan {{.Run.Status}}</h2>
+<p>Started {{.Run.StartedAt.Format "2006-01-02 15:04"}}{{if .Duration}}, took {{.Duration}}{{end}}.</p>
+{{- if .Run.Error}}
+<p style="color: #b91c1c;"><b>Error:</b> {{.Run.Error}}</p>
+{{- end}}
+<table cellpadding="4" style="border-collapse: collapse;">
+<tr><td>Files processed</td><td align="right">{{.Run.FilesProcessed}}</td></tr>
+<tr><td>Added to the index</td><td align="right">{{.Run.FilesAdded}}</td></tr>
+<tr><td>Updated</td><td align="right">{{.Run.FilesUpdated}}</td></tr>
+<tr><td>Removed</td><td align="right">{{.Run.FilesRemoved}}</td></tr>
+<tr><td>Files that could not be read</td><td align="right"{{if .Run.Errors}} style="color: #b91c1c;"{{end}}>{{.Run.Errors}}</td></tr>
+</table>
+<h3>New duplicates</h3>
+{{- if .NewGroups}}
+<p>{{.NewGroupCount}} new duplicate groups, {{.NewReclaimable}} reclaimable.</p>
+<table cellpadding="4" style="border-collapse: collapse;">
+<tr style="text-align: left;"><th>Size</th><th>Reclaimable</th><th>Files</th></tr>
+{{- range .NewGroups}}
+<tr style="vertical-align: top; border-top: 1px solid #ddd;"><td>{{.Size}}</td><td>{{.Reclaimable}}</td><td>{{range .Paths}}{{.}}<br>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- if .MoreGroups}}
+<p>and {{.MoreGroups}} more groups.</p>
+{{- end}}
+{{- else}}
+<p>No new duplicate groups.</p>
+{{- end}}
+<h3>All duplicates</h3>
+<p>{{.TotalGroups}} groups of {{.TotalFiles}} files, {{.TotalReclaimable}} reclaimable by keeping one copy of each.</p>
+{{- if .Directories}}
+<p style="color: #666;">Scanned: {{range $i, $d := .Directories}}{{if $i}}, {{end}}{{$d}}{{end}}</p>
+{{- end}}
+</body>
+</html>
+`))
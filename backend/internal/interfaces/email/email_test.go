@@ -0,0 +1,146 @@
+package email
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+)
+
+func testSummary() imaging.ScanSummary {
+	started := time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)
+	finished := started.Add(95 * time.Second)
+	return imaging.ScanSummary{
+		JobID: "job-1",
+		Run: domain.ScanRun{
+			Kind: "scan", Trigger: domain.ScanTriggerScheduled, Status: domain.ScanRunCompleted,
+			Directories: "/photos\n/backup", FilesProcessed: 10, FilesAdded: 4, Errors: 1,
+			StartedAt: started, FinishedAt: &finished,
+		},
+		NewGroups: []imaging.NewDuplicateGroup{
+			{Hash: "h", Size: 2048, Paths: []string{"/photos/a.jpg", "/backup/<a>.jpg"}},
+		},
+		Duplicates: imaging.DuplicateTotals{Groups: 3, Files: 7, ReclaimableBytes: 3 << 20},
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	raw, err := buildMessage("Dedup <nas@example.com>", []string{"me@example.com"}, testSummary(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "Image dedup: 1 new duplicate groups, 3.0 MB reclaimable" {
+		t.Errorf("subject = %q", subject)
+	}
+	if msg.Header.Get("To") != "me@example.com" || !strings.HasPrefix(msg.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("headers = %v", msg.Header)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Scheduled scan completed", "took 1m35s", "/photos/a.jpg", "/backup/&lt;a&gt;.jpg",
+		"1 new duplicate groups, 2.0 KB reclaimable", "3 groups of 7 files, 3.0 MB reclaimable", "Scanned: /photos, /backup",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("body lacks %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestNewReporter(t *testing.T) {
+	valid := Config{Host: "smtp.example.com", Port: 587, From: "nas@example.com", To: []string{"me@example.com"}}
+	if _, err := NewReporter(valid, nil); err != nil {
+		t.Errorf("valid config: %v", err)
+	}
+	for name, mutate := range map[string]func(*Config){
+		"no host":       func(c *Config) { c.Host = "" },
+		"bad port":      func(c *Config) { c.Port = 0 },
+		"bad sender":    func(c *Config) { c.From = "nas" },
+		"no recipients": func(c *Config) { c.To = nil },
+		"bad recipient": func(c *Config) { c.To = []string{"me@example.com", "you"} },
+	} {
+		c := valid
+		mutate(&c)
+		if _, err := NewReporter(c, nil); err == nil {
+			t.Errorf("%s: NewReporter succeeded", name)
+		}
+	}
+}
+
+// TestSend delivers a report to a minimal SMTP server without TLS or authentication
+func TestSend(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+		var commands []string
+		reply("220 test")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			commands = append(commands, line)
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 test")
+			case line == "DATA":
+				reply("354 go ahead")
+				for {
+					if l, err := r.ReadString('\n'); err != nil || l == ".\r\n" {
+						break
+					}
+				}
+				reply("250 queued")
+			case line == "QUIT":
+				reply("221 bye")
+				received <- commands
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	r, err := NewReporter(Config{Host: "127.0.0.1", Port: port, From: "Dedup <nas@example.com>", To: []string{"a@example.com", "b@example.com"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Send(testSummary()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	got := strings.Join(<-received, "|")
+	for _, want := range []string{"MAIL FROM:<nas@example.com>", "RCPT TO:<a@example.com>", "RCPT TO:<b@example.com>", "DATA"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("commands %s lack %s", got, want)
+		}
+	}
+}
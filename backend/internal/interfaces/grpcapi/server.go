@@ -0,0 +1,63 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"image-toolkit/api/dedupv1"
+	"image-toolkit/internal/application/auth"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer creates a gRPC server of the service. Every call must carry the API
+// token as "authorization: Bearer <token>" metadata; opts add e.g. TLS credentials.
+func NewServer(service *Service, authService *auth.AuthService, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.UnaryInterceptor(authenticate(authService)))
+	server := grpc.NewServer(opts...)
+	dedupv1.RegisterDedupServiceServer(server, service)
+	return server
+}
+
+// authenticate checks the bearer token of calls, limiting failed attempts per client
+// address like the REST API
+func authenticate(authService *auth.AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var token string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for _, value := range md.Get("authorization") {
+				if t, ok := strings.CutPrefix(value, "Bearer "); ok {
+					token = strings.TrimSpace(t)
+				}
+			}
+		}
+		if _, err := authService.AuthenticateToken(token, clientIP(ctx)); err != nil {
+			if errors.Is(err, domain.ErrRateLimited) {
+				return nil, status.Error(codes.ResourceExhausted, string(i18n.MsgAuthRateLimited))
+			}
+			return nil, status.Error(codes.Unauthenticated, string(i18n.MsgAuthInvalidCredentials))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// clientIP returns the address of the caller without its port
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
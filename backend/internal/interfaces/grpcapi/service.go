@@ -0,0 +1,202 @@
+// Package grpcapi serves the gRPC API defined in api/dedupv1: scans, duplicate
+// listing and deletion planning, for programs rather than the web UI
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"image-toolkit/api/dedupv1"
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// Page sizes of ListDuplicates
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// Service implements dedupv1.DedupServiceServer on the scan manager of the server.
+// Errors carry the message keys of the REST API.
+type Service struct {
+	dedupv1.UnimplementedDedupServiceServer
+	db          *gorm.DB
+	scanManager *imaging.ScanManager
+}
+
+// NewService creates the gRPC service
+func NewService(db *gorm.DB, scanManager *imaging.ScanManager) *Service {
+	return &Service{db: db, scanManager: scanManager}
+}
+
+// StartScan starts a scan of all gallery folders or of one directory inside them
+func (s *Service) StartScan(ctx context.Context, req *dedupv1.StartScanRequest) (*dedupv1.StartScanResponse, error) {
+	var (
+		jobID string
+		err   error
+	)
+	guard := s.scanManager.GalleryPathGuard()
+	switch dir := req.GetDirectory(); {
+	case dir != "" && objectstore.IsRemote(dir):
+		if !guard.ContainsObject(dir) {
+			return nil, status.Error(codes.InvalidArgument, string(i18n.MsgScanPathNotAllowed))
+		}
+		jobID, err = s.scanManager.ScanSingleDir(strings.TrimSuffix(dir, "/"))
+	case dir != "":
+		if !guard.Contains(dir) {
+			return nil, status.Error(codes.InvalidArgument, string(i18n.MsgScanPathNotAllowed))
+		}
+		if info, statErr := os.Stat(filepath.FromSlash(dir)); statErr != nil || !info.IsDir() {
+			return nil, status.Error(codes.InvalidArgument, string(i18n.MsgFolderNotDirectory))
+		}
+		jobID, err = s.scanManager.ScanSingleDir(filepath.ToSlash(filepath.Clean(filepath.FromSlash(dir))))
+	default:
+		jobID, err = s.scanManager.StartScan()
+	}
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, string(i18n.MsgScanFailed))
+	}
+	return &dedupv1.StartScanResponse{JobId: jobID}, nil
+}
+
+// GetScanJob returns the state of a scan job
+func (s *Service) GetScanJob(ctx context.Context, req *dedupv1.GetScanJobRequest) (*dedupv1.ScanJob, error) {
+	job, ok := s.scanManager.GetJob(req.GetJobId())
+	if !ok {
+		return nil, status.Error(codes.NotFound, string(i18n.MsgScanJobNotFound))
+	}
+	resp := &dedupv1.ScanJob{
+		Id:             job.ID,
+		Kind:           job.Kind,
+		State:          string(job.State),
+		Progress:       job.Progress,
+		FilesProcessed: int64(job.FilesProcessed),
+		Errors:         int64(job.Errors),
+		Error:          job.Error,
+		CreatedAt:      timestamppb.New(job.CreatedAt),
+	}
+	if job.StartedAt != nil {
+		resp.StartedAt = timestamppb.New(*job.StartedAt)
+	}
+	if job.FinishedAt != nil {
+		resp.FinishedAt = timestamppb.New(*job.FinishedAt)
+	}
+	return resp, nil
+}
+
+// ListDuplicates returns a page of exact duplicate groups
+func (s *Service) ListDuplicates(ctx context.Context, req *dedupv1.ListDuplicatesRequest) (*dedupv1.ListDuplicatesResponse, error) {
+	filter, err := s.duplicateFilter(req.GetFilter())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	order, err := imaging.ParseGroupOrder(req.GetSort())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	limit = min(limit, maxPageSize)
+	offset := max(int(req.GetOffset()), 0)
+
+	groups, totalGroups, totalFiles, err := imaging.FindDuplicatesPaginated(s.db, filter, order, offset, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, string(i18n.MsgScanDuplicateFailed))
+	}
+	resp := &dedupv1.ListDuplicatesResponse{
+		Groups:      make([]*dedupv1.DuplicateGroup, len(groups)),
+		TotalGroups: int64(totalGroups),
+		TotalFiles:  int64(totalFiles),
+	}
+	for i, g := range groups {
+		group := &dedupv1.DuplicateGroup{Hash: g.Hash, Size: g.Size, Files: make([]*dedupv1.File, len(g.Files))}
+		for j, f := range g.Files {
+			group.Files[j] = fileMessage(f)
+		}
+		resp.Groups[i] = group
+	}
+	return resp, nil
+}
+
+// PlanDeletion suggests the files to remove in every exact duplicate group under the
+// keep rules, like POST /api/v1/auto-select. Pinned and remote files are left out.
+func (s *Service) PlanDeletion(ctx context.Context, req *dedupv1.PlanDeletionRequest) (*dedupv1.PlanDeletionResponse, error) {
+	keepRules := make([]imaging.KeepRule, len(req.GetKeepRules()))
+	for i, r := range req.GetKeepRules() {
+		keepRules[i] = imaging.KeepRule{Policy: imaging.KeepPolicy(r.GetPolicy()), Directories: r.GetDirectories()}
+	}
+	if err := imaging.ValidateKeepRules(keepRules); err != nil {
+		return nil, status.Error(codes.InvalidArgument, string(i18n.MsgScanKeepRuleInvalid))
+	}
+	keepRules = s.scanManager.ResolveKeepRules(keepRules)
+	filter, err := s.duplicateFilter(req.GetFilter())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	plan, err := imaging.PlanDeletion(s.db, filter, keepRules)
+	if err != nil {
+		return nil, status.Error(codes.Internal, string(i18n.MsgScanDuplicateFailed))
+	}
+	resp := &dedupv1.PlanDeletionResponse{
+		Groups:           make([]*dedupv1.GroupPlan, len(plan.Groups)),
+		TotalRemove:      int64(plan.Files),
+		ReclaimableBytes: plan.ReclaimableBytes,
+	}
+	for i, group := range plan.Groups {
+		resp.Groups[i] = &dedupv1.GroupPlan{Hash: group.Hash, Keep: group.Keep, Remove: group.Remove}
+	}
+	return resp, nil
+}
+
+// duplicateFilter builds the duplicate lookup filter of a request from the configured
+// size limits, like the filter query parameters of GET /api/v1/duplicates
+func (s *Service) duplicateFilter(f *dedupv1.DuplicateFilter) (imaging.DuplicateFilter, error) {
+	media := imaging.MediaImages
+	if f.GetMedia() == string(imaging.MediaVideos) {
+		media = imaging.MediaVideos
+	}
+	filter := s.scanManager.Options().DuplicateFilter(media)
+	if f.GetDir() != "" && !filepath.IsAbs(f.GetDir()) {
+		return filter, fmt.Errorf("directory %q is not absolute", f.GetDir())
+	}
+	extensions, err := imaging.ParseExtensions(strings.Join(f.GetExtensions(), ","))
+	if err != nil {
+		return filter, err
+	}
+	if f.GetMinSize() < 0 || f.GetMaxSize() < 0 || (f.GetMaxSize() > 0 && f.GetMinSize() > f.GetMaxSize()) {
+		return filter, fmt.Errorf("invalid size range %d to %d", f.GetMinSize(), f.GetMaxSize())
+	}
+	filter = filter.Narrow(f.GetDir(), extensions, f.GetMinSize(), f.GetMaxSize())
+	if filter.Review, err = imaging.ParseReviewFilter(f.GetReview()); err != nil {
+		return filter, err
+	}
+	if filter.Hosts, err = imaging.ParseHosts(strings.Join(f.GetHosts(), ",")); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// fileMessage converts an indexed file for responses
+func fileMessage(f domain.ImageFile) *dedupv1.File {
+	return &dedupv1.File{
+		Path:      f.Path,
+		Size:      f.Size,
+		ModTime:   timestamppb.New(f.ModTime),
+		Protected: f.Protected,
+		Host:      f.Host,
+	}
+}
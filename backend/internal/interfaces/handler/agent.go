@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleAgentSyncStart starts the sync of an agent, returning the time records it
+// reports from now on are newer than
+func (s *Server) handleAgentSyncStart(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.AgentSyncStartResponse{Since: time.Now()})
+}
+
+// handleAgentFiles stores a batch of files indexed by an agent
+func (s *Server) handleAgentFiles(c *gin.Context) {
+	var req dto.AgentFilesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	stored, err := imaging.StoreAgentFiles(s.db, req.Agent, req.Files)
+	if errors.Is(err, imaging.ErrInvalidAgent) {
+		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgScanAgentInvalid))
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to store agent files", "agent", req.Agent, "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanAgentFailed))
+		return
+	}
+	c.JSON(http.StatusOK, dto.AgentFilesResponse{Stored: stored})
+}
+
+// handleHosts lists the machines with indexed files: this server and the agents
+func (s *Server) handleHosts(c *gin.Context) {
+	hosts, err := imaging.Hosts(s.db)
+	if err != nil {
+		slog.Error("Failed to list hosts", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
+		return
+	}
+	resp := dto.HostsResponse{Hosts: make([]dto.HostDTO, len(hosts))}
+	for i, h := range hosts {
+		resp.Hosts[i] = dto.HostDTO{Name: h.Host, Local: h.Host == "", Files: h.Files}
+		if h.Host == "" {
+			resp.Hosts[i].Name = imaging.LocalHost
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleAgentSyncFinish ends the sync of an agent, removing the records of its files
+// that are gone
+func (s *Server) handleAgentSyncFinish(c *gin.Context) {
+	var req dto.AgentSyncFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	removed, err := imaging.FinishAgentSync(s.db, req.Agent, req.Roots, req.Since)
+	if errors.Is(err, imaging.ErrInvalidAgent) {
+		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgScanAgentInvalid))
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to finish agent sync", "agent", req.Agent, "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanAgentFailed))
+		return
+	}
+	slog.Info("Agent sync finished", "agent", req.Agent, "roots", len(req.Roots), "removed", removed)
+	c.JSON(http.StatusOK, dto.AgentSyncFinishResponse{Removed: removed})
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/openapi"
+)
+
+// Query parameters shared by several endpoints
+var (
+	pathQuery     = openapi.Param{Name: "path", Description: "Absolute file path", Required: true}
+	pageQuery     = openapi.Param{Name: "page", Type: "integer"}
+	pageSizeQuery = openapi.Param{Name: "pageSize", Type: "integer"}
+	nearQuery     = openapi.Param{Name: "near", Description: "latitude,longitude, e.g. 43.70,7.26: only exact duplicate groups with a copy shot within radius by its GPS data"}
+	radiusQuery   = openapi.Param{Name: "radius", Type: "number", Description: "Radius around near in km, 10 by default, at most 1000"}
+)
+
+// apiOperations documents the JSON API for the OpenAPI document, keyed by
+// "METHOD /path" relative to APIPrefix. Routes missing here are still listed.
+var apiOperations = map[string]openapi.Operation{
+	// Auth
+	"GET /auth/status":           {Tag: "auth", Summary: "Authentication status", Response: dto.AuthStatusResponse{}, Public: true},
+	"POST /auth/login":           {Tag: "auth", Summary: "Log in and start a session", Request: dto.LoginRequest{}, Public: true},
+	"POST /auth/bootstrap/setup": {Tag: "auth", Summary: "Create the first administrator", Request: dto.BootstrapSetupRequest{}, Public: true},
+	"POST /auth/logout":          {Tag: "auth", Summary: "End the current session"},
+	"GET /auth/me":               {Tag: "auth", Summary: "Current user"},
+	"POST /auth/change-password": {Tag: "auth", Summary: "Change own password", Request: dto.ChangePasswordRequest{}},
+	"PATCH /users/me":            {Tag: "auth", Summary: "Update own profile", Request: dto.UpdateProfileRequest{}},
+
+	// Duplicates and scanning
+	"GET /duplicates": {Tag: "duplicates", Summary: "Duplicate groups, paginated", Response: dto.DuplicatesResponse{}, Query: []openapi.Param{
+		pageQuery, pageSizeQuery,
+		{Name: "mode", Description: "exact, similar, pixel (identical decoded pixels), scaled (resized copies, highest resolution first), burst (similar shots taken in quick succession), name (files of one folder whose names differ only by copy suffixes such as \" (1)\", \" - Copy\" or \"-edited\") or exif (re-encoded copies of one shot with the same EXIF capture time, camera model and resolution, largest file first)"},
+		{Name: "sort", Description: "wasted-space, file-count, newest, oldest or path; by default groups with the largest files come first (bursts: newest first)"},
+		{Name: "media", Description: "image (default) or video; videos are only grouped in exact mode"},
+		{Name: "threshold", Type: "integer", Description: "Maximum perceptual hash distance for mode=similar, scaled and burst"},
+		{Name: "window", Type: "integer", Description: "Longest gap between consecutive shots in seconds for mode=burst"},
+		{Name: "dir", Description: "Only files under this absolute directory"},
+		{Name: "ext", Description: "Only files with these comma-separated extensions, e.g. .png,.jpg"},
+		{Name: "minSize", Description: "Only files at least this large, e.g. 500KB or 1MB; narrows MIN_FILE_SIZE"},
+		{Name: "maxSize", Description: "Only files at most this large; narrows MAX_FILE_SIZE"},
+		{Name: "review", Description: "unreviewed, reviewed or deferred: only exact duplicate groups of this review status"},
+		{Name: "hosts", Description: "Only exact duplicate groups with copies on all these comma-separated hosts, local for this server, e.g. laptop,nas"},
+		{Name: "month", Description: "YYYY-MM: only exact duplicate groups shot in this month, see /timeline"},
+		nearQuery, radiusQuery,
+	}},
+	"GET /timeline": {Tag: "duplicates", Summary: "Exact duplicate groups per month of the earliest capture time (or modification time) of their copies", Response: dto.TimelineResponse{}, Query: []openapi.Param{
+		{Name: "media", Description: "image (default) or video"},
+		{Name: "dir", Description: "Only files under this absolute directory"},
+		nearQuery, radiusQuery,
+	}},
+	"GET /name-conflicts": {Tag: "duplicates", Summary: "File names shared by files of different content, e.g. two different IMG_0001.JPG", Response: dto.NameConflictsResponse{}, Query: []openapi.Param{
+		{Name: "dir", Description: "Only files under this absolute directory"},
+		{Name: "limit", Type: "integer", Description: "Most conflicts returned, 500 by default; the totals count all of them"},
+	}},
+	"GET /groups/:hash":           {Tag: "duplicates", Summary: "All files of one exact duplicate group by content hash", Response: dto.GroupResponse{}},
+	"POST /groups/:hash/ignore":   {Tag: "duplicates", Summary: "Mark an exact duplicate group as not duplicates, hiding it from the listing", Response: dto.IgnoredGroupDTO{}},
+	"PUT /groups/:hash/review":    {Tag: "duplicates", Summary: "Mark an exact duplicate group reviewed, deferred or unreviewed", Request: dto.ReviewGroupRequest{}, Response: dto.ReviewGroupResponse{}},
+	"GET /ignored-groups":         {Tag: "duplicates", Summary: "Groups marked as not duplicates", Response: dto.IgnoredGroupsResponse{}},
+	"DELETE /ignored-groups/:id":  {Tag: "duplicates", Summary: "List an ignored group among the duplicates again", Response: dto.IgnoredGroupsResponse{}},
+	"GET /corrupt-files":          {Tag: "duplicates", Summary: "Indexed files that are empty or fail to decode", Response: dto.CorruptFilesResponse{}, Query: []openapi.Param{{Name: "limit", Type: "integer"}}},
+	"GET /export/csv":             {Tag: "duplicates", Summary: "All duplicate groups as CSV", ContentType: "text/csv"},
+	"GET /export/html":            {Tag: "duplicates", Summary: "Standalone HTML duplicate report", ContentType: "text/html"},
+	"GET /stats":                  {Tag: "duplicates", Summary: "Reclaimable space by directory, extension, group and month", Response: dto.StatsResponse{}},
+	"GET /search":                 {Tag: "duplicates", Summary: "Indexed files whose path contains q, with the copies of each", Response: dto.SearchResponse{}, Query: []openapi.Param{{Name: "q", Description: "Case-insensitive part of the path, e.g. IMG_2034", Required: true}, {Name: "limit", Type: "integer"}}},
+	"GET /folder-patterns":        {Tag: "duplicates", Summary: "Folder patterns for batch deduplication", Response: dto.FolderPatternsResponse{}},
+	"POST /delete-files":          {Tag: "duplicates", Summary: "Delete files", Request: dto.DeleteFilesRequest{}, Response: dto.DeleteFilesResponse{}},
+	"POST /generate-script":       {Tag: "duplicates", Summary: "Write a shell, PowerShell or cmd script removing files, and optionally one restoring them from the trash, in a chosen encoding; with download the script is returned as an attachment", Request: dto.GenerateScriptRequest{}, Response: dto.GenerateScriptResponse{}},
+	"POST /deletion-plan/s3":      {Tag: "duplicates", Summary: "DeleteObjects batches removing objects from S3, for the AWS CLI or an S3 batch job", Request: dto.S3DeletionPlanRequest{}, Response: dto.S3DeletionPlanResponse{}},
+	"POST /batch-delete":          {Tag: "duplicates", Summary: "Delete duplicates by folder rules or keep policies", Request: dto.BatchDeleteRequest{}, Response: dto.BatchDeleteResponse{}},
+	"POST /consolidate":           {Tag: "duplicates", Summary: "Move the kept file of each group into a library folder and remove the other copies", Request: dto.ConsolidateRequest{}, Response: dto.ConsolidateResponse{}},
+	"PUT /files/protected":        {Tag: "duplicates", Summary: "Pin files against deletion, or unpin them", Request: dto.SetProtectedRequest{}, Response: dto.SetProtectedResponse{}},
+	"GET /selection":              {Tag: "duplicates", Summary: "Files marked keep or delete in this session", Response: dto.SelectionResponse{}},
+	"PUT /selection":              {Tag: "duplicates", Summary: "Mark files keep or delete, or unmark them with an empty action", Request: dto.UpdateSelectionRequest{}, Response: dto.SelectionResponse{}},
+	"DELETE /selection":           {Tag: "duplicates", Summary: "Unmark the given files, or all files without a path", Response: dto.SelectionResponse{}, Query: []openapi.Param{{Name: "path", Description: "Marked file path, repeatable"}}},
+	"POST /auto-select":           {Tag: "duplicates", Summary: "Suggest files to remove from each group by keep policies", Request: dto.AutoSelectRequest{}, Response: dto.AutoSelectResponse{}},
+	"POST /scan":                  {Tag: "scan", Summary: "Start a full scan, or a scan of one directory", Response: dto.ScanResponse{}, Query: []openapi.Param{{Name: "directory", Description: "Gallery folder or a directory inside it to rescan alone"}}},
+	"GET /scan/jobs/:id":          {Tag: "scan", Summary: "Scan job status", Response: dto.ScanJobDTO{}},
+	"POST /scan/cancel":           {Tag: "scan", Summary: "Cancel the running scan", Response: dto.ScanResponse{}},
+	"POST /scan/pause":            {Tag: "scan", Summary: "Pause the running scan", Response: dto.ScanResponse{}},
+	"POST /scan/resume":           {Tag: "scan", Summary: "Resume a paused scan", Response: dto.ScanResponse{}},
+	"POST /fast-scan":             {Tag: "scan", Summary: "Scan only new and changed files", Response: dto.FastScanResponse{}},
+	"POST /rehash":                {Tag: "scan", Summary: "Rehash files hashed with another algorithm", Response: dto.ScanResponse{}},
+	"GET /status":                 {Tag: "scan", Summary: "Current scan status", Response: imaging.ScanStatusResponse{}},
+	"GET /schedule":               {Tag: "scan", Summary: "Scan schedule", Response: dto.ScheduleDTO{}},
+	"PUT /schedule":               {Tag: "scan", Summary: "Update the scan schedule", Request: dto.UpdateScheduleRequest{}, Response: dto.ScheduleDTO{}},
+	"GET /scan-runs":              {Tag: "scan", Summary: "Scan run history", Response: dto.ScanRunsResponse{}, Query: []openapi.Param{{Name: "limit", Type: "integer"}}},
+	"GET /ws":                     {Tag: "scan", Summary: "WebSocket with scan progress, new duplicates and deletions"},
+	"GET /folders":                {Tag: "folders", Summary: "Gallery folders", Response: dto.GalleryFoldersResponse{}},
+	"POST /folders":               {Tag: "folders", Summary: "Add a gallery folder", Request: dto.AddFolderRequest{}, Response: dto.AddFolderResponse{}},
+	"DELETE /folders/:id":         {Tag: "folders", Summary: "Remove a gallery folder", Response: dto.RemoveFolderResponse{}},
+	"GET /trash-info":             {Tag: "folders", Summary: "Trash folder size", Response: dto.TrashInfoResponse{}},
+	"POST /trash-clean":           {Tag: "folders", Summary: "Empty the trash folder", Response: dto.CleanTrashResponse{}},
+	"GET /deletions":              {Tag: "folders", Summary: "Recent batches of files moved to the trash folder", Response: dto.DeletionsResponse{}, Query: []openapi.Param{{Name: "limit", Type: "integer"}}},
+	"POST /restore/:batchId":      {Tag: "folders", Summary: "Restore a deletion batch to the original locations", Response: dto.RestoreResponse{}},
+	"GET /metadata-status":        {Tag: "scan", Summary: "Metadata extraction status", Response: imaging.MetadataStatusResponse{}},
+	"GET /thumbnail":              {Tag: "images", Summary: "Thumbnail of a file, cacheable by ETag", ContentType: "image/*", Query: []openapi.Param{pathQuery, {Name: "v", Description: "File modification time, distinguishing thumbnails of changed files"}}},
+	"GET /image":                  {Tag: "images", Summary: "Original image", ContentType: "image/*", Query: []openapi.Param{pathQuery}},
+	"GET /image-metadata":         {Tag: "images", Summary: "EXIF metadata of a file", Response: dto.ImageMetadataResponse{}, Query: []openapi.Param{pathQuery}},
+	"GET /gallery":                {Tag: "gallery", Summary: "Gallery images, paginated", Response: dto.GalleryImagesResponse{}, Query: []openapi.Param{pageQuery, pageSizeQuery, {Name: "view"}}},
+	"GET /gallery/calendar":       {Tag: "gallery", Summary: "Gallery images grouped by date", Response: dto.GalleryCalendarResponse{}, Query: []openapi.Param{pageQuery, pageSizeQuery, {Name: "monthYear"}, {Name: "startDate"}, {Name: "endDate"}}},
+	"GET /gallery/calendar/month": {Tag: "gallery", Summary: "Days with images in a month", Query: []openapi.Param{{Name: "monthYear", Required: true}}},
+
+	// Settings
+	"GET /settings":      {Tag: "settings", Summary: "Application settings", Response: dto.AppSettingsDTO{}},
+	"PUT /settings":      {Tag: "settings", Summary: "Update application settings", Request: dto.UpdateSettingsRequest{}, Response: dto.AppSettingsDTO{}},
+	"GET /user-settings": {Tag: "settings", Summary: "Current user settings", Response: dto.UserSettingsDTO{}},
+	"PUT /user-settings": {Tag: "settings", Summary: "Update current user settings", Request: dto.UpdateUserSettingsRequest{}, Response: dto.UserSettingsDTO{}},
+
+	// Thumbnail cache
+	"GET /thumbnail/cache/stats":             {Tag: "thumbnails", Summary: "Thumbnail cache statistics, on disk and in memory", Response: dto.ThumbnailCacheStatsResponse{}},
+	"DELETE /thumbnail/cache/invalidate":     {Tag: "thumbnails", Summary: "Invalidate cached thumbnails", Request: dto.InvalidateThumbnailRequest{}},
+	"DELETE /thumbnail/cache/invalidate-all": {Tag: "thumbnails", Summary: "Invalidate all cached thumbnails"},
+	"POST /thumbnail/cache/warmup":           {Tag: "thumbnails", Summary: "Pre-generate thumbnails", Request: dto.WarmupThumbnailsRequest{}},
+	"POST /thumbnail/cache/enable":           {Tag: "thumbnails", Summary: "Enable the thumbnail cache"},
+	"POST /thumbnail/cache/disable":          {Tag: "thumbnails", Summary: "Disable the thumbnail cache"},
+
+	// OCR
+	"GET /ocr-status":            {Tag: "ocr", Summary: "OCR classifier availability", Response: dto.OCRStatusResponse{}},
+	"POST /ocr/classify":         {Tag: "ocr", Summary: "Classify all images", Response: dto.ScanResponse{}},
+	"POST /ocr/classify-changes": {Tag: "ocr", Summary: "Classify new and changed images", Response: dto.ScanResponse{}},
+	"POST /ocr/stop":             {Tag: "ocr", Summary: "Stop classification", Response: dto.ScanResponse{}},
+	"GET /ocr/classify-status":   {Tag: "ocr", Summary: "Classification progress", Response: imaging.OcrStatusResponse{}},
+	"GET /ocr/documents":         {Tag: "ocr", Summary: "Images classified as documents", Response: dto.OcrDocumentsResponse{}, Query: []openapi.Param{pageQuery, pageSizeQuery}},
+	"GET /ocr/data":              {Tag: "ocr", Summary: "OCR bounding boxes of a file", Response: dto.OcrDataResponse{}, Query: []openapi.Param{pathQuery}},
+	"GET /ocr-image":             {Tag: "ocr", Summary: "Rotated image for OCR display", ContentType: "image/*", Query: []openapi.Param{pathQuery, {Name: "angle", Type: "integer"}, {Name: "scaleFactor"}}},
+	"GET /llm/settings":          {Tag: "ocr", Summary: "LLM recognition settings", Response: dto.LlmSettingsDTO{}},
+	"PUT /llm/settings":          {Tag: "ocr", Summary: "Update LLM recognition settings", Request: dto.UpdateLlmSettingsRequest{}},
+	"POST /llm/recognize":        {Tag: "ocr", Summary: "Start LLM text recognition", Request: dto.LlmOcrRequest{}, Response: dto.LlmRecognizeStatusResponse{}},
+	"GET /llm/recognize-status":  {Tag: "ocr", Summary: "LLM recognition progress", Response: dto.LlmRecognizeStatusResponse{}, Query: []openapi.Param{pathQuery}},
+	"GET /llm/recognition":       {Tag: "ocr", Summary: "LLM recognition result", Response: dto.LlmOcrDataResponse{}, Query: []openapi.Param{pathQuery}},
+	"GET /llm/models":            {Tag: "ocr", Summary: "Models offered by the LLM provider", Response: dto.LlmModelsResponse{}},
+
+	// Agents
+	"POST /agent/sync/start":  {Tag: "agent", Summary: "Start reporting the files of an agent", Response: dto.AgentSyncStartResponse{}},
+	"POST /agent/files":       {Tag: "agent", Summary: "Report a batch of files indexed by an agent", Request: dto.AgentFilesRequest{}, Response: dto.AgentFilesResponse{}},
+	"POST /agent/sync/finish": {Tag: "agent", Summary: "Finish reporting, removing the agent files that are gone", Request: dto.AgentSyncFinishRequest{}, Response: dto.AgentSyncFinishResponse{}},
+	"GET /hosts":              {Tag: "agent", Summary: "Machines with indexed files, this server as local", Response: dto.HostsResponse{}},
+
+	// Administration
+	"GET /admin/users":                     {Tag: "admin", Summary: "List users", Response: dto.UsersListResponse{}},
+	"POST /admin/users":                    {Tag: "admin", Summary: "Create a user", Request: dto.CreateUserRequest{}},
+	"PATCH /admin/users/:id":               {Tag: "admin", Summary: "Update a user", Request: dto.UpdateUserRequest{}},
+	"DELETE /admin/users/:id":              {Tag: "admin", Summary: "Delete a user"},
+	"POST /admin/users/:id/reset-password": {Tag: "admin", Summary: "Reset a user's password", Request: dto.ResetPasswordRequest{}},
+	"GET /admin/audit":                     {Tag: "admin", Summary: "Audit log", Response: dto.AuditLogsResponse{}, Query: []openapi.Param{pageQuery}},
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"image-toolkit/internal/application/events"
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/objectstore"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+	"image-toolkit/internal/interfaces/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleConsolidate turns scattered copies into one library: the kept file of each
+// exact duplicate group is moved into the destination folder and the other copies
+// are removed as in a batch delete
+func (s *Server) handleConsolidate(c *gin.Context) {
+	var req dto.ConsolidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if len(req.KeepRules) == 0 && !req.UseSelection {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	var keepRules []imaging.KeepRule
+	if len(req.KeepRules) > 0 {
+		var err error
+		if keepRules, err = s.keepRules(req.KeepRules); err != nil {
+			c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.MsgScanKeepRuleInvalid))
+			return
+		}
+	}
+
+	// The library must stay indexed, so it lives in a gallery folder
+	guard := s.scanManager.GalleryPathGuard()
+	dest := filepath.Clean(filepath.FromSlash(req.Destination))
+	if objectstore.IsRemote(req.Destination) || !guard.ContainsTarget(dest) {
+		c.JSON(http.StatusForbidden, i18n.ErrorResponse(i18n.MsgScanPathNotAllowed))
+		return
+	}
+	if err := os.MkdirAll(imaging.LongPath(dest), 0755); err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanLibraryFailed))
+		return
+	}
+
+	var marks map[string]string
+	if req.UseSelection {
+		var err error
+		if marks, err = imaging.SelectionMarks(s.db, middleware.GetSessionKey(c)); err != nil {
+			c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanSelectionFailed))
+			return
+		}
+	}
+
+	groups, _, _, err := imaging.FindDuplicatesPaginated(s.db, s.scanManager.Options().DuplicateFilter(imaging.MediaAll), imaging.OrderDefault, 0, 100000)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
+		return
+	}
+
+	if req.SystemTrash {
+		req.TrashDir = ""
+	}
+	if req.TrashDir != "" {
+		if err := os.MkdirAll(req.TrashDir, 0755); err != nil {
+			c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanTrashDirFailed))
+			return
+		}
+	}
+
+	// Choose the keeper of each group first, so dates are looked up in one go
+	type consolidation struct {
+		group  domain.DuplicateGroup
+		keep   map[int]bool
+		keeper int
+	}
+	var plan []consolidation
+	var keeperIDs []uint
+	var resp dto.ConsolidateResponse
+	pixels := imaging.Resolutions(s.db, groups, keepRules)
+	for _, group := range groups {
+		keeper := -1
+		keep := make(map[int]bool)
+		if selected, marked := imaging.SelectionKeeps(group.Files, marks); marked {
+			if len(selected) == 0 {
+				for _, file := range group.Files {
+					resp.Failed++
+					resp.FailedFiles = append(resp.FailedFiles, filepath.Base(file.Path)+": every copy is selected for deletion")
+				}
+				continue
+			}
+			keep = selected
+			for i := range group.Files {
+				if keep[i] {
+					keeper = i
+					break
+				}
+			}
+		} else if len(keepRules) > 0 {
+			keeper = imaging.SelectKeeper(group.Files, keepRules, pixels)
+			keep[keeper] = true
+		} else {
+			continue
+		}
+		plan = append(plan, consolidation{group: group, keep: keep, keeper: keeper})
+		keeperIDs = append(keeperIDs, group.Files[keeper].ID)
+	}
+
+	dates := make(map[uint]*time.Time)
+	if req.ByDate {
+		if dates, err = imaging.DatesTaken(s.db, keeperIDs); err != nil {
+			c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
+			return
+		}
+	}
+
+	batchID, trashed := imaging.NewDeletionBatchID(), 0
+	var cleared []string
+	for _, p := range plan {
+		files := p.group.Files
+		kept := files[p.keeper]
+
+		// A group is only taken apart once its keeper is safely in the library
+		failGroup := func(reason string) {
+			for i, file := range files {
+				if !p.keep[i] || i == p.keeper {
+					resp.Failed++
+					resp.FailedFiles = append(resp.FailedFiles, filepath.Base(file.Path)+": "+reason)
+				}
+			}
+		}
+		if req.Verify {
+			if err := verifyGroup(files, p.keep, kept.Path); err != nil {
+				slog.Warn("Consolidate: group skipped after failed verification", "hash", p.group.Hash, "error", err)
+				failGroup("group not verified: " + err.Error())
+				continue
+			}
+		}
+		if !guard.Allows(kept.Path) {
+			failGroup("outside the gallery folders")
+			continue
+		}
+		target := imaging.ConsolidatePath(dest, kept, dates[kept.ID], req.ByDate)
+		keepPath, err := imaging.ConsolidateFile(s.db, kept, target)
+		if err != nil {
+			slog.Warn("Consolidate: moving the kept file failed", "path", kept.Path, "error", err)
+			failGroup("kept file not moved: " + err.Error())
+			continue
+		}
+		if keepPath != filepath.FromSlash(kept.Path) {
+			resp.Moved++
+			cleared = append(cleared, kept.Path)
+		}
+
+		for i, file := range files {
+			if p.keep[i] {
+				continue
+			}
+			if file.Protected {
+				resp.ProtectedFiles = append(resp.ProtectedFiles, file.Path)
+				continue
+			}
+			if !guard.Allows(file.Path) && !guard.AllowsObject(file.Path) {
+				resp.Failed++
+				resp.FailedFiles = append(resp.FailedFiles, filepath.Base(file.Path)+": outside the gallery folders")
+				continue
+			}
+
+			trashPath, err := s.removeFile(c.Request.Context(), file.Path, keepPath, req.TrashDir, req.SystemTrash, imaging.ReplaceNone)
+			if err != nil {
+				resp.Failed++
+				resp.FailedFiles = append(resp.FailedFiles, filepath.Base(file.Path)+": "+err.Error())
+				continue
+			}
+			if trashPath != "" {
+				s.recordDeletion(batchID, file.Path, trashPath)
+				trashed++
+			}
+			s.db.Where("path = ?", filepath.ToSlash(file.Path)).Delete(&domain.ImageFile{})
+			cleared = append(cleared, file.Path)
+			resp.Success++
+		}
+	}
+	s.clearDeletedFromSelection(c, cleared)
+
+	if trashed > 0 {
+		resp.BatchID = batchID
+	}
+	s.scanManager.Events.Publish(events.TypeFilesDeleted, resp)
+	c.JSON(http.StatusOK, resp)
+}
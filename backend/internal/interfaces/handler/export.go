@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleExportCSV streams all exact duplicate groups as a CSV download,
+// one row per file, largest reclaimable size first
+func (s *Server) handleExportCSV(c *gin.Context) {
+	report, err := imaging.BuildDuplicateReport(s.db, s.scanManager.Options().DuplicateFilter(imaging.MediaAll))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
+		return
+	}
+
+	filename := fmt.Sprintf("duplicates-%s.csv", report.GeneratedAt.Format("20060102-150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := imaging.WriteCSVReport(c.Writer, report); err != nil {
+		slog.Error("CSV export failed", "error", err)
+	}
+}
+
+// handleExportHTML renders all exact duplicate groups as a standalone HTML report
+// with embedded thumbnails, served as a download
+func (s *Server) handleExportHTML(c *gin.Context) {
+	report, err := imaging.BuildDuplicateReport(s.db, s.scanManager.Options().DuplicateFilter(imaging.MediaAll))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
+		return
+	}
+
+	filename := fmt.Sprintf("duplicates-%s.html", report.GeneratedAt.Format("20060102-150405"))
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := imaging.WriteHTMLReport(c.Writer, report, s.thumbnail); err != nil {
+		slog.Error("HTML export failed", "error", err)
+	}
+}
+
+// thumbnail returns a thumbnail data URL, using the thumbnail cache service if available
+func (s *Server) thumbnail(path string) (string, error) {
+	if s.thumbnailService != nil {
+		return s.thumbnailService.GetOrGenerate(path)
+	}
+	return imaging.GenerateThumbnail(path, s.thumbnailCache)
+}
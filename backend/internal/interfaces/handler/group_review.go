@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleReviewGroup marks an exact duplicate group reviewed or deferred, so later
+// cleanup sessions can list only the groups still to handle, or clears the mark
+func (s *Server) handleReviewGroup(c *gin.Context) {
+	var req dto.ReviewGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	hash := c.Param("hash")
+	found, err := imaging.SetGroupReview(s.db, hash, req.Status)
+	if err != nil {
+		if errors.Is(err, imaging.ErrInvalidReviewStatus) {
+			c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+			return
+		}
+		slog.Error("Failed to store group review", "hash", hash, "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanReviewFailed))
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, i18n.ErrorResponse(i18n.MsgScanGroupNotFound))
+		return
+	}
+
+	resp := dto.ReviewGroupResponse{Hash: hash, Status: req.Status}
+	if req.Status == string(imaging.ReviewUnreviewed) {
+		resp.Status = ""
+	}
+	c.JSON(http.StatusOK, resp)
+}
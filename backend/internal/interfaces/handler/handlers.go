@@ -1,20 +1,28 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"log"
+	"hash/fnv"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
+	"image-toolkit/internal/application/events"
 	"image-toolkit/internal/application/imaging"
-	"image-toolkit/internal/application/thumbnail"
 	"image-toolkit/internal/domain"
+	"image-toolkit/internal/infrastructure/database"
 	"image-toolkit/internal/infrastructure/llm"
+	"image-toolkit/internal/infrastructure/objectstore"
 	"image-toolkit/internal/interfaces/dto"
 	"image-toolkit/internal/interfaces/i18n"
 	"image-toolkit/internal/interfaces/middleware"
@@ -44,8 +52,90 @@ func (s *Server) handleGetDuplicates(c *gin.Context) {
 		page = 1
 	}
 
+	// "exact" groups by content hash, "similar" groups by perceptual hash distance,
+	// "pixel" groups images with identical decoded pixels, "scaled" groups similar
+	// images that differ in resolution, "burst" groups similar shots taken in quick
+	// succession, "name" groups files whose names differ only by copy suffixes, "exif"
+	// groups re-encoded copies of one shot by capture time, camera and resolution
+	mode := c.DefaultQuery("mode", "exact")
+
+	// Videos have no perceptual hash, so they are only grouped by content
+	media := imaging.MediaImages
+	if c.Query("media") == string(imaging.MediaVideos) {
+		media = imaging.MediaVideos
+		mode = "exact"
+	}
+
+	order, err := imaging.ParseGroupOrder(c.Query("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	filter, err := s.duplicateFilter(media, c.Query("dir"), c.Query("ext"), c.Query("minSize"), c.Query("maxSize"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if filter.Review, err = imaging.ParseReviewFilter(c.Query("review")); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if filter.Hosts, err = imaging.ParseHosts(c.Query("hosts")); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if filter.Month, err = imaging.ParseMonth(c.Query("month")); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if filter.Location, err = imaging.ParseLocation(c.Query("near"), c.Query("radius")); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
 	offset := (page - 1) * pageSize
-	groups, totalGroups, totalFiles, err := imaging.FindDuplicatesPaginated(s.db, offset, pageSize)
+	var groups []domain.DuplicateGroup
+	var totalGroups, totalFiles, threshold, window int
+	// The threshold of the modes that use one, reported even when it is 0
+	var usedThreshold *int
+	switch mode {
+	case "similar", "scaled", "burst":
+		threshold = s.config.SimilarityThreshold
+		if t := c.Query("threshold"); t != "" {
+			threshold, err = strconv.Atoi(t)
+			if err != nil || threshold < 0 || threshold > imaging.MaxSimilarityThreshold {
+				c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+				return
+			}
+		}
+		usedThreshold = &threshold
+		switch mode {
+		case "scaled":
+			groups, totalGroups, totalFiles, err = imaging.FindScaledPaginated(s.db, filter, order, offset, pageSize, threshold)
+		case "burst":
+			window = s.config.BurstWindowSeconds
+			if w := c.Query("window"); w != "" {
+				window, err = strconv.Atoi(w)
+			}
+			burstWindow, ok := imaging.BurstWindow(window)
+			if err != nil || !ok {
+				c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+				return
+			}
+			groups, totalGroups, totalFiles, err = imaging.FindBurstsPaginated(s.db, filter, order, offset, pageSize, threshold, burstWindow)
+		default:
+			groups, totalGroups, totalFiles, err = imaging.FindSimilarPaginated(s.db, filter, order, offset, pageSize, threshold)
+		}
+	case "pixel":
+		groups, totalGroups, totalFiles, err = imaging.FindPixelDuplicatesPaginated(s.db, filter, order, offset, pageSize)
+	case "name":
+		groups, totalGroups, totalFiles, err = imaging.FindNameCopiesPaginated(s.db, filter, order, offset, pageSize)
+	case "exif":
+		groups, totalGroups, totalFiles, err = imaging.FindShotsPaginated(s.db, filter, order, offset, pageSize)
+	default:
+		mode = "exact"
+		groups, totalGroups, totalFiles, err = imaging.FindDuplicatesPaginated(s.db, filter, order, offset, pageSize)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
 		return
@@ -59,7 +149,7 @@ func (s *Server) handleGetDuplicates(c *gin.Context) {
 		page = totalPages
 	}
 
-	// Prepare group DTOs with parallel thumbnail generation
+	// Prepare group DTOs; the browser loads thumbnails separately and caches them
 	groupDTOs := make([]dto.DuplicateGroupDTO, len(groups))
 	pageFiles := 0
 
@@ -67,56 +157,19 @@ func (s *Server) handleGetDuplicates(c *gin.Context) {
 		pageFiles += len(g.Files)
 	}
 
-	const maxWorkers = 16
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, maxWorkers)
-
 	for i, g := range groups {
-		fileDTOs := make([]dto.FileDTO, len(g.Files))
-		for j, f := range g.Files {
-			fileDTOs[j] = dto.FileDTO{
-				ID:       f.ID,
-				Path:     f.Path,
-				FileName: filepath.Base(f.Path),
-				DirPath:  filepath.Dir(f.Path),
-				ModTime:  f.ModTime.Format("2006-01-02 15:04:05"),
-			}
+		groupDTOs[i] = duplicateGroupDTO(offset+i+1, g)
+	}
+	if mode == "exact" {
+		reviews, err := imaging.GroupReviews(s.db, groups)
+		if err != nil {
+			slog.Warn("Failed to load group reviews", "error", err)
 		}
-
-		groupDTOs[i] = dto.DuplicateGroupDTO{
-			Index:     offset + i + 1,
-			Hash:      g.Hash,
-			Size:      g.Size,
-			SizeHuman: formatSize(g.Size),
-			Files:     fileDTOs,
-		}
-
-		if len(g.Files) > 0 {
-			wg.Add(1)
-			go func(idx int, filePath string) {
-				defer wg.Done()
-				semaphore <- struct{}{}
-				defer func() { <-semaphore }()
-
-				var thumb string
-				var err error
-
-				// Use thumbnail service if available
-				if s.thumbnailService != nil {
-					thumb, err = s.thumbnailService.GetOrGenerate(filePath)
-				} else {
-					thumb, err = imaging.GenerateThumbnail(filePath, s.thumbnailCache)
-				}
-
-				if err == nil {
-					groupDTOs[idx].Thumbnail = thumb
-				}
-			}(i, g.Files[0].Path)
+		for i, status := range reviews {
+			groupDTOs[i].Review = status
 		}
 	}
 
-	wg.Wait()
-
 	// Get scanned dirs from gallery folders
 	var galleryFolders []domain.GalleryFolder
 	s.db.Order("created_at").Find(&galleryFolders)
@@ -126,6 +179,11 @@ func (s *Server) handleGetDuplicates(c *gin.Context) {
 	}
 
 	response := dto.DuplicatesResponse{
+		Mode:        mode,
+		Sort:        string(order),
+		Media:       string(media),
+		Threshold:   usedThreshold,
+		Window:      window,
 		Groups:      groupDTOs,
 		TotalFiles:  totalFiles,
 		PageFiles:   pageFiles,
@@ -142,13 +200,111 @@ func (s *Server) handleGetDuplicates(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// handleGetGroup returns all files of one exact duplicate group by content hash, for
+// reviewing it on its own rather than within a page of the listing
+func (s *Server) handleGetGroup(c *gin.Context) {
+	group, err := imaging.FindGroup(s.db, c.Param("hash"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, i18n.ErrorResponse(i18n.MsgScanGroupNotFound))
+		return
+	}
+
+	reclaimable := group.Size * int64(len(group.Files)-1)
+	resp := dto.GroupResponse{
+		Group:            duplicateGroupDTO(0, *group),
+		ReclaimableBytes: reclaimable,
+		ReclaimableHuman: formatSize(reclaimable),
+	}
+	if reviews, err := imaging.GroupReviews(s.db, []domain.DuplicateGroup{*group}); err == nil {
+		resp.Group.Review = reviews[0]
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// duplicateGroupDTO converts a duplicate group for JSON responses; index numbers the
+// group within the whole listing
+func duplicateGroupDTO(index int, g domain.DuplicateGroup) dto.DuplicateGroupDTO {
+	fileDTOs := make([]dto.FileDTO, len(g.Files))
+	for j, f := range g.Files {
+		fileDTOs[j] = fileDTO(f)
+		if g.Similarity != nil {
+			fileDTOs[j].Similarity = g.Similarity[j]
+		}
+		if g.Dimensions != nil {
+			fileDTOs[j].Width = g.Dimensions[j].Width
+			fileDTOs[j].Height = g.Dimensions[j].Height
+			fileDTOs[j].Recommended = j == g.Recommended
+		}
+		if g.TakenAt != nil {
+			fileDTOs[j].TakenAt = g.TakenAt[j].Format("2006-01-02 15:04:05")
+		}
+	}
+
+	groupDTO := dto.DuplicateGroupDTO{
+		Index:     index,
+		Hash:      g.Hash,
+		Size:      g.Size,
+		SizeHuman: formatSize(g.Size),
+		Files:     fileDTOs,
+	}
+	if len(g.Files) > 0 {
+		groupDTO.Thumbnail = thumbnailURL(g.Files[0].Path, g.Files[0].ModTime)
+	}
+	return groupDTO
+}
+
+// fileDTO converts an indexed file for JSON responses, as an exact duplicate
+func fileDTO(f domain.ImageFile) dto.FileDTO {
+	return dto.FileDTO{
+		ID:         f.ID,
+		Path:       f.Path,
+		FileName:   filepath.Base(f.Path),
+		DirPath:    filepath.Dir(f.Path),
+		Size:       f.Size,
+		ModTime:    f.ModTime.Format("2006-01-02 15:04:05"),
+		Similarity: 1,
+		Protected:  f.Protected,
+		Host:       f.Host,
+		Corrupt:    f.Corrupt,
+	}
+}
+
 // handleScan triggers an async scan of directories
 func (s *Server) handleScan(c *gin.Context) {
-	if err := s.scanManager.StartScan(); err != nil {
+	var (
+		jobID string
+		err   error
+	)
+	if dir := c.Query("directory"); dir != "" && objectstore.IsRemote(dir) {
+		// Only an object store gallery folder or a prefix inside one can be rescanned
+		if !s.scanManager.GalleryPathGuard().ContainsObject(dir) {
+			c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgScanPathNotAllowed))
+			return
+		}
+		jobID, err = s.scanManager.ScanSingleDir(strings.TrimSuffix(dir, "/"))
+	} else if dir != "" {
+		// Only a gallery folder or a directory inside one can be rescanned
+		if !s.scanManager.GalleryPathGuard().Contains(dir) {
+			c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgScanPathNotAllowed))
+			return
+		}
+		if info, statErr := os.Stat(filepath.FromSlash(dir)); statErr != nil || !info.IsDir() {
+			c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgFolderNotDirectory))
+			return
+		}
+		jobID, err = s.scanManager.ScanSingleDir(filepath.ToSlash(filepath.Clean(filepath.FromSlash(dir))))
+	} else {
+		jobID, err = s.scanManager.StartScan()
+	}
+	if err != nil {
 		c.JSON(http.StatusConflict, i18n.ErrorResponse(i18n.MsgScanFailed))
 		return
 	}
-	c.JSON(http.StatusAccepted, dto.ScanResponse{Message: string(i18n.MsgScanStarted)})
+	c.JSON(http.StatusAccepted, dto.ScanResponse{Message: string(i18n.MsgScanStarted), JobID: jobID})
 }
 
 // handleFastScan triggers an async fast scan of directories
@@ -161,16 +317,192 @@ func (s *Server) handleFastScan(c *gin.Context) {
 		Modified:  result.Modified,
 		Created:   result.Created,
 		Deleted:   result.Deleted,
+		Moved:     result.Moved,
 		Total:     result.TotalChecked,
 	})
 }
 
+// handleRehash triggers an async migration of content hashes to the configured algorithm.
+// Progress is reported through the regular scan status endpoint.
+func (s *Server) handleRehash(c *gin.Context) {
+	jobID, err := s.scanManager.StartRehash()
+	if err != nil {
+		c.JSON(http.StatusConflict, i18n.ErrorResponse(i18n.MsgScanFailed))
+		return
+	}
+	c.JSON(http.StatusAccepted, dto.ScanResponse{Message: string(i18n.MsgScanStarted), JobID: jobID})
+}
+
+// handleGetSchedule returns the scan schedule and the next run time
+func (s *Server) handleGetSchedule(c *gin.Context) {
+	spec, next := s.scanScheduler.Schedule()
+	resp := dto.ScheduleDTO{Schedule: spec}
+	if !next.IsZero() {
+		formatted := next.Format("2006-01-02 15:04:05")
+		resp.NextRun = &formatted
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleUpdateSchedule sets the cron expression for scheduled scans (empty disables them)
+func (s *Server) handleUpdateSchedule(c *gin.Context) {
+	var req dto.UpdateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	if err := imaging.ValidateSchedule(req.Schedule); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.MsgScheduleInvalid))
+		return
+	}
+	if err := s.scanScheduler.SetSchedule(req.Schedule); err != nil {
+		slog.Error("Failed to save scan schedule", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScheduleSaveFailed))
+		return
+	}
+
+	s.handleGetSchedule(c)
+}
+
+// handleGetScanRuns returns the most recent scan runs
+func (s *Server) handleGetScanRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 500 {
+		limit = 20
+	}
+
+	runs, err := imaging.RecentScanRuns(s.db, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanRunsFailed))
+		return
+	}
+
+	runDTOs := make([]dto.ScanRunDTO, len(runs))
+	for i, r := range runs {
+		runDTOs[i] = dto.ScanRunDTO{
+			ID:             r.ID,
+			Kind:           r.Kind,
+			Trigger:        r.Trigger,
+			Status:         r.Status,
+			Directories:    []string{},
+			FilesProcessed: r.FilesProcessed,
+			FilesAdded:     r.FilesAdded,
+			FilesUpdated:   r.FilesUpdated,
+			FilesRemoved:   r.FilesRemoved,
+			Errors:         r.Errors,
+			Error:          r.Error,
+			StartedAt:      r.StartedAt.Format("2006-01-02 15:04:05"),
+		}
+		if r.Directories != "" {
+			runDTOs[i].Directories = strings.Split(r.Directories, "\n")
+		}
+		if r.FinishedAt != nil {
+			finished := r.FinishedAt.Format("2006-01-02 15:04:05")
+			runDTOs[i].FinishedAt = &finished
+			runDTOs[i].ElapsedMs = r.FinishedAt.Sub(r.StartedAt).Milliseconds()
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.ScanRunsResponse{Runs: runDTOs})
+}
+
+// handleCancelScan stops the running scan; files processed so far are kept
+func (s *Server) handleCancelScan(c *gin.Context) {
+	s.respondScanControl(c, s.scanManager.Cancel(), i18n.MsgScanCancelled)
+}
+
+// handlePauseScan suspends the running scan until it is resumed or cancelled
+func (s *Server) handlePauseScan(c *gin.Context) {
+	s.respondScanControl(c, s.scanManager.Pause(), i18n.MsgScanPaused)
+}
+
+// handleResumeScan continues a paused scan
+func (s *Server) handleResumeScan(c *gin.Context) {
+	s.respondScanControl(c, s.scanManager.Resume(), i18n.MsgScanResumed)
+}
+
+// respondScanControl maps the result of a scan control call to an HTTP response
+func (s *Server) respondScanControl(c *gin.Context, err error, success i18n.MessageKey) {
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, dto.ScanResponse{Message: string(success), JobID: s.scanManager.GetStatus().JobID})
+	case errors.Is(err, imaging.ErrNoScanInProgress):
+		c.JSON(http.StatusConflict, i18n.ErrorResponse(i18n.MsgScanNotRunning))
+	case errors.Is(err, imaging.ErrScanAlreadyPaused):
+		c.JSON(http.StatusConflict, i18n.ErrorResponse(i18n.MsgScanAlreadyPaused))
+	case errors.Is(err, imaging.ErrScanNotPaused):
+		c.JSON(http.StatusConflict, i18n.ErrorResponse(i18n.MsgScanNotPaused))
+	default:
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanFailed))
+	}
+}
+
+// handleGetScanJob returns the state of a scan job started by POST /api/scan or /api/rehash
+func (s *Server) handleGetScanJob(c *gin.Context) {
+	job, ok := s.scanManager.GetJob(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, i18n.ErrorResponse(i18n.MsgScanJobNotFound))
+		return
+	}
+
+	jobDTO := dto.ScanJobDTO{
+		ID:             job.ID,
+		Kind:           job.Kind,
+		State:          string(job.State),
+		Progress:       job.Progress,
+		FilesProcessed: job.FilesProcessed,
+		Errors:         job.Errors,
+		Error:          job.Error,
+		CreatedAt:      job.CreatedAt.Format(time.RFC3339),
+		ElapsedMs:      job.Elapsed(time.Now()).Milliseconds(),
+	}
+	if job.StartedAt != nil {
+		started := job.StartedAt.Format(time.RFC3339)
+		jobDTO.StartedAt = &started
+	}
+	if job.FinishedAt != nil {
+		finished := job.FinishedAt.Format(time.RFC3339)
+		jobDTO.FinishedAt = &finished
+	}
+
+	c.JSON(http.StatusOK, jobDTO)
+}
+
 // handleGetStatus returns the current scan status
 func (s *Server) handleGetStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, s.scanManager.GetStatus())
 }
 
-// handleThumbnail serves a thumbnail for a specific file
+// thumbnailMaxAge is how long browsers may reuse a thumbnail without revalidating it
+const thumbnailMaxAge = 24 * time.Hour
+
+// thumbnailURL returns the URL of the thumbnail of a file for JSON responses. The
+// modification time is part of the URL, so a changed file bypasses the browser cache.
+func thumbnailURL(path string, modTime time.Time) string {
+	return APIPrefix + "/thumbnail?path=" + url.QueryEscape(path) + "&v=" + strconv.FormatInt(modTime.Unix(), 10)
+}
+
+// thumbnailETag identifies the thumbnail of a file by its path, modification time and size
+func thumbnailETag(path string, info os.FileInfo) string {
+	h := fnv.New64a()
+	h.Write([]byte(path))
+	return fmt.Sprintf(`"%x-%x-%x"`, h.Sum64(), info.ModTime().UnixNano(), info.Size())
+}
+
+// decodeDataURL splits a base64 data URL into its MIME type and content
+func decodeDataURL(dataURL string) (string, []byte, error) {
+	header, payload, ok := strings.Cut(strings.TrimPrefix(dataURL, "data:"), ",")
+	mimeType, isBase64 := strings.CutSuffix(header, ";base64")
+	if !ok || !isBase64 {
+		return "", nil, fmt.Errorf("not a base64 data URL")
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	return mimeType, data, err
+}
+
+// handleThumbnail serves the thumbnail of a file as an image, with an ETag and
+// Cache-Control headers so that browsers keep it between page loads
 func (s *Server) handleThumbnail(c *gin.Context) {
 	path := c.Query("path")
 	if path == "" {
@@ -178,22 +510,36 @@ func (s *Server) handleThumbnail(c *gin.Context) {
 		return
 	}
 
-	var thumbnail string
-	var err error
-
-	// Use thumbnail service if available
-	if s.thumbnailService != nil {
-		thumbnail, err = s.thumbnailService.GetOrGenerate(path)
-	} else {
-		thumbnail, err = imaging.GenerateThumbnail(path, s.thumbnailCache)
+	info, err := os.Stat(filepath.FromSlash(path))
+	if err != nil {
+		c.JSON(http.StatusNotFound, i18n.ErrorResponse(i18n.MsgImageNotFound))
+		return
+	}
+	etag := thumbnailETag(path, info)
+	cacheHeaders := func() {
+		c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(thumbnailMaxAge.Seconds())))
+		c.Header("Pragma", "")
+		c.Header("ETag", etag)
+	}
+	if c.GetHeader("If-None-Match") == etag {
+		cacheHeaders()
+		c.Status(http.StatusNotModified)
+		return
 	}
 
+	thumbnail, err := s.thumbnail(path)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgImageThumbnailFailed))
+		return
+	}
+	mimeType, data, err := decodeDataURL(thumbnail)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgImageThumbnailFailed))
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.ThumbnailResponse{Thumbnail: thumbnail})
+	cacheHeaders()
+	c.Data(http.StatusOK, mimeType, data)
 }
 
 // handleDeleteFiles deletes selected files directly (moves to trash)
@@ -204,106 +550,231 @@ func (s *Server) handleDeleteFiles(c *gin.Context) {
 		return
 	}
 
+	if len(req.FilePaths) == 0 && req.UseSelection {
+		marks, err := imaging.SelectionMarks(s.db, middleware.GetSessionKey(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanSelectionFailed))
+			return
+		}
+		for path, action := range marks {
+			if action == domain.SelectionDelete {
+				req.FilePaths = append(req.FilePaths, path)
+			}
+		}
+		sort.Strings(req.FilePaths)
+	}
 	if len(req.FilePaths) == 0 {
 		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgScanNoFilesSelected))
 		return
 	}
+	replace := imaging.ReplaceMode(req.Replace)
+	if !replace.Valid() {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	// Only files inside the gallery folders may be deleted; reject the whole request otherwise
+	guard := s.scanManager.GalleryPathGuard()
+	for _, filePath := range req.FilePaths {
+		if !guard.Allows(filePath) && !guard.AllowsObject(filePath) {
+			slog.Warn("Delete rejected: file is outside the gallery folders", "path", filePath)
+			c.JSON(http.StatusForbidden, i18n.ErrorResponse(i18n.MsgScanPathNotAllowed))
+			return
+		}
+	}
+
+	// Pinned files are reported apart and not removed
+	protected, err := imaging.ProtectedPaths(s.db, req.FilePaths)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanProtectFailed))
+		return
+	}
+	var protectedFiles []string
+	if len(protected) > 0 {
+		paths := req.FilePaths[:0:0]
+		for _, filePath := range req.FilePaths {
+			if protected[filePath] {
+				protectedFiles = append(protectedFiles, filePath)
+			} else {
+				paths = append(paths, filePath)
+			}
+		}
+		req.FilePaths = paths
+	}
 
 	var successCount, failedCount int
-	var failedFiles []string
+	var failedFiles, removed []string
 
+	if req.SystemTrash {
+		req.TrashDir = ""
+	}
 	if req.TrashDir != "" {
 		if err := os.MkdirAll(req.TrashDir, 0755); err != nil {
 			c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanTrashDirFailed))
 			return
 		}
+	}
 
-		for _, filePath := range req.FilePaths {
-			baseName := filepath.Base(filePath)
-			destPath := filepath.Join(req.TrashDir, baseName)
+	batchID, trashed := imaging.NewDeletionBatchID(), 0
+	removing := make(map[string]bool, len(req.FilePaths))
+	for _, filePath := range req.FilePaths {
+		removing[filePath] = true
+	}
 
-			if _, err := os.Stat(destPath); err == nil {
-				ext := filepath.Ext(baseName)
-				nameWithoutExt := strings.TrimSuffix(baseName, ext)
-				destPath = filepath.Join(req.TrashDir, nameWithoutExt+"_"+time.Now().Format("20060102_150405")+ext)
-			}
+	for _, filePath := range req.FilePaths {
+		baseName := filepath.Base(filePath)
 
-			if err := os.Rename(filePath, destPath); err != nil {
+		var keep string
+		if replace != imaging.ReplaceNone || req.Verify {
+			var err error
+			if keep, err = imaging.KeptCopy(s.db, filePath, removing); err != nil {
 				failedCount++
 				failedFiles = append(failedFiles, baseName+": "+err.Error())
 				continue
 			}
-
-			s.db.Where("path = ?", filepath.ToSlash(filePath)).Delete(&domain.ImageFile{})
-			successCount++
 		}
-	} else {
-		for _, filePath := range req.FilePaths {
-			baseName := filepath.Base(filePath)
-
-			if err := os.Remove(filePath); err != nil {
+		if req.Verify {
+			if err := imaging.VerifyIdentical(filePath, keep); err != nil {
+				slog.Warn("Delete skipped: verification against the kept copy failed", "path", filePath, "keep", keep, "error", err)
 				failedCount++
 				failedFiles = append(failedFiles, baseName+": "+err.Error())
 				continue
 			}
+		}
 
+		trashPath, err := s.removeFile(c.Request.Context(), filePath, keep, req.TrashDir, req.SystemTrash, replace)
+		if err != nil {
+			failedCount++
+			failedFiles = append(failedFiles, baseName+": "+err.Error())
+			continue
+		}
+		if trashPath != "" {
+			s.recordDeletion(batchID, filePath, trashPath)
+			trashed++
+		}
+
+		if !replace.KeepsFile() {
 			s.db.Where("path = ?", filepath.ToSlash(filePath)).Delete(&domain.ImageFile{})
-			successCount++
 		}
+		removed = append(removed, filePath)
+		successCount++
 	}
+	s.clearDeletedFromSelection(c, removed)
 
-	c.JSON(http.StatusOK, dto.DeleteFilesResponse{
-		Success:     successCount,
-		Failed:      failedCount,
-		FailedFiles: failedFiles,
-	})
+	resp := dto.DeleteFilesResponse{
+		Success:        successCount,
+		Failed:         failedCount,
+		FailedFiles:    failedFiles,
+		ProtectedFiles: protectedFiles,
+	}
+	if trashed > 0 {
+		resp.BatchID = batchID
+	}
+	s.scanManager.Events.Publish(events.TypeFilesDeleted, resp)
+	c.JSON(http.StatusOK, resp)
 }
 
-// handleGetFolderPatterns returns all unique folder patterns from duplicates
-func (s *Server) handleGetFolderPatterns(c *gin.Context) {
-	groups, _, _, err := imaging.FindDuplicatesPaginated(s.db, 0, 100000)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
+// handleS3DeletionPlan returns the DeleteObjects batches that remove the given objects
+// from S3. Objects are not deleted by the server: the plan is run with the AWS CLI
+// or an S3 batch job, and the next scan drops the records of the deleted objects.
+func (s *Server) handleS3DeletionPlan(c *gin.Context) {
+	var req dto.S3DeletionPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if len(req.FilePaths) == 0 {
+		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgScanNoFilesSelected))
 		return
 	}
 
-	patternMap := make(map[string]*dto.FolderPattern)
-
-	for _, group := range groups {
-		folderSet := make(map[string]bool)
-		for _, file := range group.Files {
-			dir := filepath.Dir(file.Path)
-			folderSet[dir] = true
+	guard := s.scanManager.GalleryPathGuard()
+	for _, p := range req.FilePaths {
+		if !strings.HasPrefix(p, objectstore.SchemeS3) || !guard.AllowsObject(p) {
+			slog.Warn("Deletion plan rejected: object is outside the gallery folders", "path", p)
+			c.JSON(http.StatusForbidden, i18n.ErrorResponse(i18n.MsgScanPathNotAllowed))
+			return
 		}
+	}
 
-		folders := make([]string, 0, len(folderSet))
-		for folder := range folderSet {
-			folders = append(folders, folder)
+	batches, err := objectstore.S3DeletionPlan(req.FilePaths)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	resp := dto.S3DeletionPlanResponse{Batches: make([]dto.S3DeleteBatchDTO, len(batches))}
+	for i, b := range batches {
+		objects := make([]dto.S3ObjectIDDTO, len(b.Delete.Objects))
+		for j, o := range b.Delete.Objects {
+			objects[j] = dto.S3ObjectIDDTO{Key: o.Key}
 		}
+		resp.Batches[i] = dto.S3DeleteBatchDTO{Bucket: b.Bucket, Delete: dto.S3DeleteObjectsDTO{Objects: objects, Quiet: b.Delete.Quiet}}
+	}
+	c.JSON(http.StatusOK, resp)
+}
 
-		sortStrings(folders)
+// removeFile deletes path, moves it to the system trash, or moves it to trashDir when
+// set (adding a timestamp on name clashes) and returns where it was moved. With a
+// replace mode a symlink to keep is left in its place; a reflink replaces the file
+// with a clone of keep and never touches the trash. Files in an object store are
+// deleted there, bypassing the trash.
+func (s *Server) removeFile(ctx context.Context, path, keep, trashDir string, systemTrash bool, replace imaging.ReplaceMode) (string, error) {
+	if replace != imaging.ReplaceNone && (objectstore.IsRemote(path) || objectstore.IsRemote(keep)) {
+		return "", imaging.ErrObjectReplace
+	}
+	if objectstore.IsRemote(path) {
+		return "", imaging.DeleteObject(ctx, path, s.scanManager.Options().ObjectStores)
+	}
+	if replace == imaging.ReplaceReflink {
+		return "", imaging.ReplaceWithClone(path, keep)
+	}
+	if systemTrash {
+		if err := imaging.MoveToSystemTrash(path); err != nil {
+			return "", err
+		}
+		if replace != imaging.ReplaceNone {
+			return "", imaging.ReplaceWithSymlink(path, keep, "", replace)
+		}
+		return "", nil
+	}
 
-		patternID := createPatternID(folders)
+	trashPath := ""
+	if trashDir != "" {
+		trashPath = imaging.TrashPath(trashDir, path)
+	}
 
-		if existing, ok := patternMap[patternID]; ok {
-			existing.DuplicateCount++
-			existing.TotalFiles += len(group.Files)
-		} else {
-			patternMap[patternID] = &dto.FolderPattern{
-				ID:             patternID,
-				Folders:        folders,
-				DuplicateCount: 1,
-				TotalFiles:     len(group.Files),
-			}
-		}
+	var err error
+	switch {
+	case replace != imaging.ReplaceNone:
+		err = imaging.ReplaceWithSymlink(path, keep, trashPath, replace)
+	case trashPath != "":
+		err = os.Rename(imaging.LongPath(path), imaging.LongPath(trashPath))
+	default:
+		err = os.Remove(imaging.LongPath(path))
 	}
+	if err != nil {
+		return "", err
+	}
+	return trashPath, nil
+}
 
-	patterns := make([]dto.FolderPattern, 0, len(patternMap))
-	for _, p := range patternMap {
-		patterns = append(patterns, *p)
+// handleGetFolderPatterns returns all unique folder patterns from duplicates
+func (s *Server) handleGetFolderPatterns(c *gin.Context) {
+	found, err := imaging.FindFolderPatterns(s.db, s.scanManager.Options().DuplicateFilter(imaging.MediaAll))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
+		return
 	}
 
-	sortPatternsByCount(patterns)
+	patterns := make([]dto.FolderPattern, len(found))
+	for i, p := range found {
+		patterns[i] = dto.FolderPattern{
+			ID:             createPatternID(p.Folders),
+			Folders:        p.Folders,
+			DuplicateCount: p.Groups,
+			TotalFiles:     p.Files,
+		}
+	}
 
 	c.JSON(http.StatusOK, dto.FolderPatternsResponse{Patterns: patterns})
 }
@@ -316,25 +787,56 @@ func (s *Server) handleBatchDelete(c *gin.Context) {
 		return
 	}
 
-	if len(req.Rules) == 0 {
+	if len(req.Rules) == 0 && len(req.KeepRules) == 0 && !req.UseSelection {
 		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
 		return
 	}
 
+	replace := imaging.ReplaceMode(req.Replace)
+	if !replace.Valid() {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	var keepRules []imaging.KeepRule
+	if len(req.KeepRules) > 0 {
+		var err error
+		if keepRules, err = s.keepRules(req.KeepRules); err != nil {
+			c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.MsgScanKeepRuleInvalid))
+			return
+		}
+	}
+
 	ruleMap := make(map[string]string)
 	for _, rule := range req.Rules {
 		ruleMap[rule.PatternID] = rule.KeepFolder
 	}
 
-	groups, _, _, err := imaging.FindDuplicatesPaginated(s.db, 0, 100000)
+	var marks map[string]string
+	if req.UseSelection {
+		var err error
+		if marks, err = imaging.SelectionMarks(s.db, middleware.GetSessionKey(c)); err != nil {
+			c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanSelectionFailed))
+			return
+		}
+	}
+
+	groups, _, _, err := imaging.FindDuplicatesPaginated(s.db, s.scanManager.Options().DuplicateFilter(imaging.MediaAll), imaging.OrderDefault, 0, 100000)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
 		return
 	}
 
 	var successCount, failedCount int
-	var failedFiles []string
+	var freedBytes int64
+	var failedFiles, protectedFiles, removed []string
+	guard := s.scanManager.GalleryPathGuard()
+	pixels := imaging.Resolutions(s.db, groups, keepRules)
+	batchID, trashed := imaging.NewDeletionBatchID(), 0
 
+	if req.SystemTrash {
+		req.TrashDir = ""
+	}
 	if req.TrashDir != "" {
 		if err := os.MkdirAll(req.TrashDir, 0755); err != nil {
 			c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanTrashDirFailed))
@@ -353,54 +855,275 @@ func (s *Server) handleBatchDelete(c *gin.Context) {
 		for folder := range folderSet {
 			folders = append(folders, folder)
 		}
-		sortStrings(folders)
+		sort.Strings(folders)
 
 		patternID := createPatternID(folders)
 
-		keepFolder, hasRule := ruleMap[patternID]
-		if !hasRule {
+		keep := make(map[int]bool)
+		if selected, marked := imaging.SelectionKeeps(group.Files, marks); marked {
+			if len(selected) == 0 {
+				// Every copy is marked delete; removing all of them would lose the content
+				for _, file := range group.Files {
+					failedCount++
+					failedFiles = append(failedFiles, filepath.Base(file.Path)+": every copy is selected for deletion")
+				}
+				continue
+			}
+			keep = selected
+		} else if keepFolder, hasRule := ruleMap[patternID]; hasRule {
+			for i, file := range group.Files {
+				if filepath.Dir(file.Path) == keepFolder {
+					keep[i] = true
+				}
+			}
+		} else if len(keepRules) > 0 {
+			keep[imaging.SelectKeeper(group.Files, keepRules, pixels)] = true
+		} else {
 			continue
 		}
 
-		for _, file := range group.Files {
-			fileDir := filepath.Dir(file.Path)
-			if fileDir == keepFolder {
+		// Links point to the first kept file
+		keepPath := ""
+		for i, file := range group.Files {
+			if keep[i] {
+				keepPath = file.Path
+				break
+			}
+		}
+
+		if req.Verify {
+			if err := verifyGroup(group.Files, keep, keepPath); err != nil {
+				slog.Warn("Batch delete: group skipped after failed verification", "hash", group.Hash, "error", err)
+				for i, file := range group.Files {
+					if !keep[i] {
+						failedCount++
+						failedFiles = append(failedFiles, filepath.Base(file.Path)+": group not verified: "+err.Error())
+					}
+				}
 				continue
 			}
+		}
 
-			if req.TrashDir != "" {
-				baseName := filepath.Base(file.Path)
-				destPath := filepath.Join(req.TrashDir, baseName)
+		for i, file := range group.Files {
+			if keep[i] {
+				continue
+			}
+			if file.Protected {
+				protectedFiles = append(protectedFiles, file.Path)
+				continue
+			}
 
-				if _, err := os.Stat(destPath); err == nil {
-					ext := filepath.Ext(baseName)
-					nameWithoutExt := strings.TrimSuffix(baseName, ext)
-					destPath = filepath.Join(req.TrashDir, nameWithoutExt+"_"+time.Now().Format("20060102_150405_000")+ext)
-				}
+			// Records may outlive their gallery folder; never touch files outside of them
+			if !guard.Allows(file.Path) && !guard.AllowsObject(file.Path) {
+				failedCount++
+				failedFiles = append(failedFiles, filepath.Base(file.Path)+": outside the gallery folders")
+				continue
+			}
 
-				if err := os.Rename(file.Path, destPath); err != nil {
-					failedCount++
-					failedFiles = append(failedFiles, filepath.Base(file.Path)+": "+err.Error())
-					continue
-				}
-			} else {
-				if err := os.Remove(file.Path); err != nil {
-					failedCount++
-					failedFiles = append(failedFiles, filepath.Base(file.Path)+": "+err.Error())
-					continue
-				}
+			if replace != imaging.ReplaceNone && keepPath == "" {
+				failedCount++
+				failedFiles = append(failedFiles, filepath.Base(file.Path)+": "+imaging.ErrNoKeptCopy.Error())
+				continue
+			}
+
+			trashPath, err := s.removeFile(c.Request.Context(), file.Path, keepPath, req.TrashDir, req.SystemTrash, replace)
+			if err != nil {
+				failedCount++
+				failedFiles = append(failedFiles, filepath.Base(file.Path)+": "+err.Error())
+				continue
+			}
+			if trashPath != "" {
+				s.recordDeletion(batchID, file.Path, trashPath)
+				trashed++
 			}
 
-			s.db.Where("path = ?", filepath.ToSlash(file.Path)).Delete(&domain.ImageFile{})
+			if !replace.KeepsFile() {
+				s.db.Where("path = ?", filepath.ToSlash(file.Path)).Delete(&domain.ImageFile{})
+			}
+			removed = append(removed, file.Path)
 			successCount++
+			freedBytes += file.Size
 		}
 	}
+	s.clearDeletedFromSelection(c, removed)
 
-	c.JSON(http.StatusOK, dto.BatchDeleteResponse{
-		Success:     successCount,
-		Failed:      failedCount,
-		FailedFiles: failedFiles,
-	})
+	resp := dto.BatchDeleteResponse{
+		Success:        successCount,
+		Failed:         failedCount,
+		FailedFiles:    failedFiles,
+		ProtectedFiles: protectedFiles,
+		FreedBytes:     freedBytes,
+	}
+	if trashed > 0 {
+		resp.BatchID = batchID
+	}
+	s.scanManager.Events.Publish(events.TypeFilesDeleted, resp)
+	c.JSON(http.StatusOK, resp)
+}
+
+// verifyGroup compares every file of a group that is about to be removed with the
+// kept file byte by byte
+func verifyGroup(files []domain.ImageFile, keep map[int]bool, keepPath string) error {
+	if keepPath == "" {
+		return imaging.ErrNoKeptCopy
+	}
+	for i, file := range files {
+		if keep[i] {
+			continue
+		}
+		if err := imaging.VerifyIdentical(file.Path, keepPath); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file.Path), err)
+		}
+	}
+	return nil
+}
+
+// recordDeletion stores a move to the trash folder so it can be undone later
+func (s *Server) recordDeletion(batchID, path, trashPath string) {
+	if err := imaging.RecordDeletion(s.db, batchID, path, trashPath); err != nil {
+		slog.Warn("Failed to record deletion", "path", path, "error", err)
+	}
+}
+
+// keepRules converts and validates keep rules from a request
+func (s *Server) keepRules(rules []dto.KeepRuleDTO) ([]imaging.KeepRule, error) {
+	keepRules := make([]imaging.KeepRule, len(rules))
+	for i, r := range rules {
+		keepRules[i] = imaging.KeepRule{Policy: imaging.KeepPolicy(r.Policy), Directories: r.Directories}
+	}
+	if err := imaging.ValidateKeepRules(keepRules); err != nil {
+		return nil, err
+	}
+	return s.scanManager.ResolveKeepRules(keepRules), nil
+}
+
+// duplicateFilter builds the duplicate lookup filter of a request from the configured
+// size limits and the directory, extensions and size range asked for
+func (s *Server) duplicateFilter(media imaging.MediaFilter, dir, ext, minSize, maxSize string) (imaging.DuplicateFilter, error) {
+	filter := s.scanManager.Options().DuplicateFilter(media)
+	if dir != "" && !filepath.IsAbs(dir) {
+		return filter, fmt.Errorf("directory %q is not absolute", dir)
+	}
+	extensions, err := imaging.ParseExtensions(ext)
+	if err != nil {
+		return filter, err
+	}
+	var minBytes, maxBytes int64
+	if minSize != "" {
+		if minBytes, err = imaging.ParseSize(minSize); err != nil {
+			return filter, err
+		}
+	}
+	if maxSize != "" {
+		if maxBytes, err = imaging.ParseSize(maxSize); err != nil {
+			return filter, err
+		}
+	}
+	if maxBytes > 0 && minBytes > maxBytes {
+		return filter, fmt.Errorf("minimum size %s exceeds maximum size %s", minSize, maxSize)
+	}
+	return filter.Narrow(dir, extensions, minBytes, maxBytes), nil
+}
+
+// handleAutoSelect suggests, for every duplicate group, which files to remove under the
+// given keep rules. Nothing is deleted; the client pre-selects the files for review.
+func (s *Server) handleAutoSelect(c *gin.Context) {
+	var req dto.AutoSelectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	keepRules, err := s.keepRules(req.KeepRules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.MsgScanKeepRuleInvalid))
+		return
+	}
+
+	media := imaging.MediaImages
+	if req.Media == string(imaging.MediaVideos) {
+		media = imaging.MediaVideos
+		req.Mode = "exact"
+	}
+	filter, err := s.duplicateFilter(media, req.Dir, req.Ext, req.MinSize, req.MaxSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if filter.Review, err = imaging.ParseReviewFilter(req.Review); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if filter.Hosts, err = imaging.ParseHosts(req.Hosts); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if filter.Month, err = imaging.ParseMonth(req.Month); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if filter.Location, err = imaging.ParseLocation(req.Near, req.Radius); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	var groups []domain.DuplicateGroup
+	switch req.Mode {
+	case "similar", "scaled", "burst":
+		threshold := s.config.SimilarityThreshold
+		if req.Threshold != nil {
+			threshold = *req.Threshold
+		}
+		if threshold < 0 || threshold > imaging.MaxSimilarityThreshold {
+			c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+			return
+		}
+		switch req.Mode {
+		case "scaled":
+			groups, _, _, err = imaging.FindScaledPaginated(s.db, filter, imaging.OrderDefault, 0, 100000, threshold)
+		case "burst":
+			window := s.config.BurstWindowSeconds
+			if req.Window != nil {
+				window = *req.Window
+			}
+			burstWindow, ok := imaging.BurstWindow(window)
+			if !ok {
+				c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+				return
+			}
+			groups, _, _, err = imaging.FindBurstsPaginated(s.db, filter, imaging.OrderDefault, 0, 100000, threshold, burstWindow)
+		default:
+			groups, _, _, err = imaging.FindSimilarPaginated(s.db, filter, imaging.OrderDefault, 0, 100000, threshold)
+		}
+	case "pixel":
+		groups, _, _, err = imaging.FindPixelDuplicatesPaginated(s.db, filter, imaging.OrderDefault, 0, 100000)
+	case "name":
+		groups, _, _, err = imaging.FindNameCopiesPaginated(s.db, filter, imaging.OrderDefault, 0, 100000)
+	case "exif":
+		groups, _, _, err = imaging.FindShotsPaginated(s.db, filter, imaging.OrderDefault, 0, 100000)
+	default:
+		req.Mode = "exact"
+		groups, _, _, err = imaging.FindDuplicatesPaginated(s.db, filter, imaging.OrderDefault, 0, 100000)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanDuplicateFailed))
+		return
+	}
+
+	pixels := imaging.Resolutions(s.db, groups, keepRules)
+	resp := dto.AutoSelectResponse{Mode: req.Mode, Groups: make([]dto.AutoSelectGroupDTO, len(groups))}
+	for i, group := range groups {
+		keep := imaging.SelectKeeper(group.Files, keepRules, pixels)
+		suggestion := dto.AutoSelectGroupDTO{Hash: group.Hash, Keep: group.Files[keep].Path, Remove: []string{}}
+		for _, file := range imaging.RemovableCopies(group.Files, keep) {
+			suggestion.Remove = append(suggestion.Remove, file.Path)
+			resp.ReclaimableBytes += file.Size
+		}
+		resp.TotalRemove += len(suggestion.Remove)
+		resp.Groups[i] = suggestion
+	}
+	resp.ReclaimableHuman = formatSize(resp.ReclaimableBytes)
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // --- Gallery Folder Handlers ---
@@ -430,29 +1153,34 @@ func (s *Server) handleGetFolders(c *gin.Context) {
 	})
 }
 
-// handleAddFolder adds a new gallery folder and triggers a scan
-func (s *Server) handleAddFolder(c *gin.Context) {
-	var req dto.AddFolderRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgFolderPathRequired))
-		return
+// validateFolderPath checks a new gallery folder: an existing local directory that
+// doesn't overlap the trash folder, or an object store URL whose store is known.
+// Returns the path as stored, or writes the error response.
+func (s *Server) validateFolderPath(c *gin.Context, path string) (string, bool) {
+	if objectstore.IsRemote(path) {
+		// Access is checked by the scan started for the folder
+		if _, err := objectstore.Open(path, s.scanManager.Options().ObjectStores); err != nil {
+			c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgFolderInvalidPath))
+			return "", false
+		}
+		return strings.TrimSuffix(path, "/"), true
 	}
 
 	// Validate directory exists
-	absPath, err := filepath.Abs(req.Path)
+	absPath, err := filepath.Abs(path)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgFolderInvalidPath))
-		return
+		return "", false
 	}
 
 	info, err := os.Stat(absPath)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgFolderCannotAccessPath))
-		return
+		return "", false
 	}
 	if !info.IsDir() {
 		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgFolderNotDirectory))
-		return
+		return "", false
 	}
 
 	normalizedPath := filepath.ToSlash(absPath)
@@ -462,9 +1190,24 @@ func (s *Server) handleAddFolder(c *gin.Context) {
 	if result := s.db.First(&settings, 1); result.Error == nil && settings.TrashDir != "" {
 		if reason := pathsConflict(normalizedPath, settings.TrashDir); reason != "" {
 			c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgFolderConflictTrash))
-			return
+			return "", false
 		}
 	}
+	return normalizedPath, true
+}
+
+// handleAddFolder adds a new gallery folder and triggers a scan
+func (s *Server) handleAddFolder(c *gin.Context) {
+	var req dto.AddFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgFolderPathRequired))
+		return
+	}
+
+	normalizedPath, ok := s.validateFolderPath(c, req.Path)
+	if !ok {
+		return
+	}
 
 	folder := domain.GalleryFolder{Path: normalizedPath}
 	if result := s.db.Create(&folder); result.Error != nil {
@@ -478,7 +1221,7 @@ func (s *Server) handleAddFolder(c *gin.Context) {
 
 	// Trigger background scan for this folder
 	scanStarted := true
-	if err := s.scanManager.ScanSingleDir(normalizedPath); err != nil {
+	if _, err := s.scanManager.ScanSingleDir(normalizedPath); err != nil {
 		scanStarted = false
 	}
 
@@ -531,8 +1274,9 @@ func (s *Server) handleGetGalleryImages(c *gin.Context) {
 		pageSize = 50
 	}
 
+	// Videos are only listed in the duplicates view
 	var totalImages int64
-	s.db.Model(&domain.ImageFile{}).Count(&totalImages)
+	s.db.Model(&domain.ImageFile{}).Where("is_video = ?", false).Count(&totalImages)
 
 	totalPages := (int(totalImages) + pageSize - 1) / pageSize
 	if totalPages < 1 {
@@ -545,7 +1289,7 @@ func (s *Server) handleGetGalleryImages(c *gin.Context) {
 	offset := (page - 1) * pageSize
 
 	var files []domain.ImageFile
-	s.db.Order("path").Offset(offset).Limit(pageSize).Find(&files)
+	s.db.Where("is_video = ?", false).Order("path").Offset(offset).Limit(pageSize).Find(&files)
 
 	imageDTOs := make([]dto.GalleryImageDTO, len(files))
 	for i, f := range files {
@@ -560,35 +1304,11 @@ func (s *Server) handleGetGalleryImages(c *gin.Context) {
 		}
 	}
 
-	// Generate thumbnails in parallel if thumbnail or folders view
-	if (view == "thumbnails" || view == "folders") && len(files) > 0 {
-		const maxWorkers = 16
-		var wg sync.WaitGroup
-		semaphore := make(chan struct{}, maxWorkers)
-
+	// Thumbnails are only shown in the thumbnail and folders views
+	if view == "thumbnails" || view == "folders" {
 		for i, f := range files {
-			wg.Add(1)
-			go func(idx int, filePath string) {
-				defer wg.Done()
-				semaphore <- struct{}{}
-				defer func() { <-semaphore }()
-
-				var thumb string
-				var err error
-
-				// Use thumbnail service if available
-				if s.thumbnailService != nil {
-					thumb, err = s.thumbnailService.GetOrGenerate(filePath)
-				} else {
-					thumb, err = imaging.GenerateThumbnail(filePath, s.thumbnailCache)
-				}
-
-				if err == nil {
-					imageDTOs[idx].Thumbnail = thumb
-				}
-			}(i, f.Path)
+			imageDTOs[i].Thumbnail = thumbnailURL(f.Path, f.ModTime)
 		}
-		wg.Wait()
 	}
 
 	c.JSON(http.StatusOK, dto.GalleryImagesResponse{
@@ -633,6 +1353,24 @@ func (s *Server) handleServeImage(c *gin.Context) {
 		return
 	}
 
+	// Videos are served as-is; c.File handles range requests for seeking
+	if domain.IsVideoFile(osPath) {
+		c.File(osPath)
+		return
+	}
+
+	// Formats browsers cannot display (HEIC, TIFF) are converted to JPEG
+	if !imaging.IsBrowserImage(osPath) {
+		var buf bytes.Buffer
+		if err := imaging.EncodeJPEG(&buf, osPath); err != nil {
+			slog.Warn("Failed to convert image for display", "path", osPath, "error", err)
+			c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgImageConvertFailed))
+			return
+		}
+		c.Data(http.StatusOK, "image/jpeg", buf.Bytes())
+		return
+	}
+
 	c.File(osPath)
 }
 
@@ -696,13 +1434,15 @@ func (s *Server) handleServeOcrImage(c *gin.Context) {
 func (s *Server) handleGetSettings(c *gin.Context) {
 	var settings domain.AppSettings
 	if result := s.db.First(&settings, 1); result.Error != nil {
-		c.JSON(http.StatusOK, dto.AppSettingsDTO{TrashDir: ""})
+		c.JSON(http.StatusOK, dto.AppSettingsDTO{VideoScanEnabled: s.config.VideoScanEnabled, PixelHashEnabled: s.config.PixelHashEnabled})
 		return
 	}
 	c.JSON(http.StatusOK, dto.AppSettingsDTO{
 		TrashDir:           settings.TrashDir,
 		ThumbnailCachePath: settings.ThumbnailCachePath,
 		ThumbnailCacheSize: settings.ThumbnailCacheSize,
+		VideoScanEnabled:   s.config.VideoScanEnabled,
+		PixelHashEnabled:   s.config.PixelHashEnabled,
 	})
 }
 
@@ -758,14 +1498,14 @@ func (s *Server) handleUpdateSettings(c *gin.Context) {
 
 			// Update thumbnail service if available
 			if s.thumbnailService != nil {
-				log.Printf("Updating thumbnail cache path from %s to %s", s.thumbnailService.Stats().CacheDir, normalizedCache)
+				slog.Info("Updating thumbnail cache path", "old", s.thumbnailService.Stats().CacheDir, "new", normalizedCache)
 				if err := s.thumbnailService.UpdateCachePath(normalizedCache); err != nil {
-					log.Printf("Failed to update thumbnail cache path: %v", err)
+					slog.Error("Failed to update thumbnail cache path", "error", err)
 				} else {
-					log.Printf("Thumbnail cache path updated successfully. New stats: %+v", s.thumbnailService.Stats())
+					slog.Info("Thumbnail cache path updated", "stats", s.thumbnailService.Stats())
 				}
 			} else {
-				log.Printf("Thumbnail service is nil, cannot update cache path")
+				slog.Warn("Thumbnail service unavailable, cannot update cache path")
 			}
 		} else {
 			settings.ThumbnailCachePath = ""
@@ -778,6 +1518,8 @@ func (s *Server) handleUpdateSettings(c *gin.Context) {
 		TrashDir:           settings.TrashDir,
 		ThumbnailCachePath: settings.ThumbnailCachePath,
 		ThumbnailCacheSize: settings.ThumbnailCacheSize,
+		VideoScanEnabled:   s.config.VideoScanEnabled,
+		PixelHashEnabled:   s.config.PixelHashEnabled,
 	})
 }
 
@@ -926,9 +1668,10 @@ func (s *Server) handleCleanTrash(c *gin.Context) {
 			continue
 		}
 		filePath := filepath.Join(settings.TrashDir, entry.Name())
-		if err := os.Remove(filePath); err != nil {
+		if err := os.Remove(imaging.LongPath(filePath)); err != nil {
 			failed++
 		} else {
+			imaging.ForgetDeletion(s.db, filePath)
 			deleted++
 		}
 	}
@@ -939,6 +1682,86 @@ func (s *Server) handleCleanTrash(c *gin.Context) {
 	})
 }
 
+// handleGetDeletions returns the most recent batches of files moved to the trash folder
+func (s *Server) handleGetDeletions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 500 {
+		limit = 20
+	}
+
+	batches, err := imaging.RecentDeletionBatches(s.db, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgDeletionsFailed))
+		return
+	}
+
+	batchDTOs := make([]dto.DeletionBatchDTO, len(batches))
+	for i, b := range batches {
+		batch := dto.DeletionBatchDTO{
+			BatchID:   b.BatchID,
+			DeletedAt: b.DeletedAt.Format("2006-01-02 15:04:05"),
+			FileCount: len(b.Files),
+			Files:     make([]dto.DeletedFileDTO, len(b.Files)),
+		}
+		for j, f := range b.Files {
+			batch.Files[j] = dto.DeletedFileDTO{
+				OriginalPath: f.OriginalPath,
+				TrashPath:    f.TrashPath,
+				Size:         f.Size,
+			}
+			batch.TotalSize += f.Size
+			if f.RestoredAt != nil {
+				restored := f.RestoredAt.Format("2006-01-02 15:04:05")
+				batch.Files[j].RestoredAt = &restored
+				batch.RestoredCount++
+			}
+		}
+		batchDTOs[i] = batch
+	}
+
+	c.JSON(http.StatusOK, dto.DeletionsResponse{Batches: batchDTOs})
+}
+
+// handleGetCorruptFiles lists the indexed files that are empty or fail to decode
+func (s *Server) handleGetCorruptFiles(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if limit < 1 || limit > 5000 {
+		limit = 500
+	}
+
+	files, total, totalSize, err := imaging.CorruptFiles(s.db, limit)
+	if err != nil {
+		slog.Error("Failed to list corrupt files", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanCorruptFailed))
+		return
+	}
+	fileDTOs := make([]dto.FileDTO, len(files))
+	for i, f := range files {
+		fileDTOs[i] = fileDTO(f)
+	}
+	c.JSON(http.StatusOK, dto.CorruptFilesResponse{Files: fileDTOs, Total: total, TotalSize: totalSize})
+}
+
+// handleRestore moves the files of a deletion batch back to their original locations
+func (s *Server) handleRestore(c *gin.Context) {
+	restored, failedFiles, err := imaging.RestoreBatch(s.db, c.Param("batchId"))
+	if errors.Is(err, imaging.ErrDeletionBatchNotFound) {
+		c.JSON(http.StatusNotFound, i18n.ErrorResponse(i18n.MsgDeletionNotFound))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgRestoreFailed))
+		return
+	}
+
+	slog.Info("Restored deleted files", "batch", c.Param("batchId"), "restored", restored, "failed", len(failedFiles))
+	c.JSON(http.StatusOK, dto.RestoreResponse{
+		Restored:    restored,
+		Failed:      len(failedFiles),
+		FailedFiles: failedFiles,
+	})
+}
+
 // handleGetImageMetadata returns EXIF metadata for a single image
 func (s *Server) handleGetImageMetadata(c *gin.Context) {
 	path := c.Query("path")
@@ -995,6 +1818,9 @@ func (s *Server) handleGetMetadataStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, s.metadataManager.GetStatus())
 }
 
+// liveMetadataCond keeps the image_metadata rows of files still in the index
+const liveMetadataCond = "image_file_id IN (SELECT id FROM image_files WHERE deleted_at IS NULL)"
+
 // handleGetGalleryCalendar returns paginated gallery images grouped by date taken
 func (s *Server) handleGetGalleryCalendar(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -1020,7 +1846,7 @@ func (s *Server) handleGetGalleryCalendar(c *gin.Context) {
 	query := s.db.Table("image_files").
 		Select("image_files.*, image_metadata.date_taken").
 		Joins("INNER JOIN image_metadata ON image_metadata.image_file_id = image_files.id").
-		Where("image_metadata.date_taken IS NOT NULL")
+		Where("image_metadata.date_taken IS NOT NULL AND image_files.deleted_at IS NULL")
 
 	// Apply date range filter
 	if startDate != "" {
@@ -1076,31 +1902,10 @@ func (s *Server) handleGetGalleryCalendar(c *gin.Context) {
 				Size:      f.Size,
 				SizeHuman: formatSize(f.Size),
 				ModTime:   f.ModTime.Format("2006-01-02 15:04:05"),
+				Thumbnail: thumbnailURL(f.Path, f.ModTime),
 			}
 		}
 
-		// Generate thumbnails in parallel
-		if len(g.images) > 0 {
-			const maxWorkers = 16
-			var wg sync.WaitGroup
-			semaphore := make(chan struct{}, maxWorkers)
-
-			for i, f := range g.images {
-				wg.Add(1)
-				go func(idx int, filePath string) {
-					defer wg.Done()
-					semaphore <- struct{}{}
-					defer func() { <-semaphore }()
-
-					thumb, err := imaging.GenerateThumbnail(filePath, s.thumbnailCache)
-					if err == nil {
-						imageDTOs[idx].Thumbnail = thumb
-					}
-				}(i, f.Path)
-			}
-			wg.Wait()
-		}
-
 		// Human-readable label
 		label := g.date.Format("Monday, January 2, 2006")
 
@@ -1114,13 +1919,15 @@ func (s *Server) handleGetGalleryCalendar(c *gin.Context) {
 
 	// Get date range
 	var dateRange dto.CalendarDateRange
-	var minDate, maxDate *time.Time
-	s.db.Raw("SELECT MIN(date_taken), MAX(date_taken) FROM image_metadata WHERE date_taken IS NOT NULL").Row().Scan(&minDate, &maxDate)
-	if minDate != nil {
-		dateRange.MinDate = minDate.Format("2006-01-02")
+	// Ordered lookups instead of MIN/MAX: SQLite returns aggregates of timestamps as text
+	var minDate, maxDate []time.Time
+	s.db.Model(&domain.ImageMetadata{}).Where("date_taken IS NOT NULL AND "+liveMetadataCond).Order("date_taken ASC").Limit(1).Pluck("date_taken", &minDate)
+	s.db.Model(&domain.ImageMetadata{}).Where("date_taken IS NOT NULL AND "+liveMetadataCond).Order("date_taken DESC").Limit(1).Pluck("date_taken", &maxDate)
+	if len(minDate) > 0 {
+		dateRange.MinDate = minDate[0].Format("2006-01-02")
 	}
-	if maxDate != nil {
-		dateRange.MaxDate = maxDate.Format("2006-01-02")
+	if len(maxDate) > 0 {
+		dateRange.MaxDate = maxDate[0].Format("2006-01-02")
 	}
 	dateRange.TotalWithDate = int(totalImages)
 
@@ -1132,12 +1939,12 @@ func (s *Server) handleGetGalleryCalendar(c *gin.Context) {
 			month := int(t.Month())
 			nextMonth := t.AddDate(0, 1, 0)
 
-			// Get distinct days that have images in this month (PostgreSQL)
+			// Get distinct days that have images in this month
 			var days []int
 			s.db.Raw(`
-				SELECT DISTINCT CAST(EXTRACT(DAY FROM date_taken) AS INTEGER) as day
+				SELECT DISTINCT `+database.DayOfMonthExpr(s.db, "date_taken")+` as day
 				FROM image_metadata
-				WHERE date_taken >= $1 AND date_taken < $2 AND date_taken IS NOT NULL
+				WHERE date_taken >= ? AND date_taken < ? AND date_taken IS NOT NULL AND `+liveMetadataCond+`
 				ORDER BY day
 			`, t, nextMonth).Pluck("day", &days)
 
@@ -1182,20 +1989,21 @@ func (s *Server) handleGetCalendarMonthInfo(c *gin.Context) {
 	month := int(t.Month())
 	nextMonth := t.AddDate(0, 1, 0)
 
-	// Get day-level counts: how many images per day in this month (PostgreSQL)
+	// Get day-level counts: how many images per day in this month
 	type dayCount struct {
 		Day   int `json:"day"`
 		Count int `json:"count"`
 	}
 
 	var dayCounts []dayCount
+	dayExpr := database.DayOfMonthExpr(s.db, "date_taken")
 	s.db.Raw(`
 		SELECT 
-			CAST(EXTRACT(DAY FROM date_taken) AS INTEGER) as day,
+			`+dayExpr+` as day,
 			COUNT(*) as count
 		FROM image_metadata
-		WHERE date_taken >= $1 AND date_taken < $2 AND date_taken IS NOT NULL
-		GROUP BY EXTRACT(DAY FROM date_taken)
+		WHERE date_taken >= ? AND date_taken < ? AND date_taken IS NOT NULL AND `+liveMetadataCond+`
+		GROUP BY `+dayExpr+`
 		ORDER BY day
 	`, t, nextMonth).Scan(&dayCounts)
 
@@ -1209,7 +2017,7 @@ func (s *Server) handleGetCalendarMonthInfo(c *gin.Context) {
 	var totalInMonth int
 	s.db.Raw(`
 		SELECT COUNT(*) FROM image_metadata
-		WHERE date_taken >= $1 AND date_taken < $2 AND date_taken IS NOT NULL
+		WHERE date_taken >= ? AND date_taken < ? AND date_taken IS NOT NULL AND `+liveMetadataCond+`
 	`, t, nextMonth).Scan(&totalInMonth)
 
 	c.JSON(http.StatusOK, gin.H{
@@ -1339,7 +2147,7 @@ func (s *Server) handleGetOcrDocuments(c *gin.Context) {
 
 	var total int64
 	s.db.Table("ocr_classifications").
-		Joins("JOIN image_files ON image_files.id = ocr_classifications.image_file_id").
+		Joins("JOIN image_files ON image_files.id = ocr_classifications.image_file_id AND image_files.deleted_at IS NULL").
 		Where("ocr_classifications.is_text_document = true").
 		Count(&total)
 
@@ -1359,7 +2167,7 @@ func (s *Server) handleGetOcrDocuments(c *gin.Context) {
 
 	if err := s.db.Table("ocr_classifications").
 		Select("image_files.id, image_files.path, image_files.size, image_files.hash, image_files.mod_time, ocr_classifications.image_file_id, ocr_classifications.mean_confidence, ocr_classifications.weighted_confidence, ocr_classifications.token_count, ocr_classifications.angle, ocr_classifications.scale_factor").
-		Joins("JOIN image_files ON image_files.id = ocr_classifications.image_file_id").
+		Joins("JOIN image_files ON image_files.id = ocr_classifications.image_file_id AND image_files.deleted_at IS NULL").
 		Where("ocr_classifications.is_text_document = true").
 		Order("image_files.id").
 		Offset(offset).
@@ -1377,7 +2185,7 @@ func (s *Server) handleGetOcrDocuments(c *gin.Context) {
 		page = totalPages
 	}
 
-	// Build DTOs with thumbnails
+	// Build DTOs with thumbnail URLs
 	docs := make([]dto.OcrDocumentDTO, len(results))
 	for i, r := range results {
 		docs[i] = dto.OcrDocumentDTO{
@@ -1395,39 +2203,10 @@ func (s *Server) handleGetOcrDocuments(c *gin.Context) {
 			Angle:              r.Angle,
 			ScaleFactor:        r.ScaleFactor,
 		}
-	}
-
-	// Generate thumbnails in parallel
-	const maxWorkers = 16
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, maxWorkers)
-
-	for i, doc := range docs {
-		if doc.Path == "" {
-			continue
+		if r.Path != "" {
+			docs[i].Thumbnail = thumbnailURL(r.Path, r.ModTime)
 		}
-		wg.Add(1)
-		go func(idx int, path string) {
-			defer wg.Done()
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
-			var thumb string
-			var err error
-
-			// Use thumbnail service if available
-			if s.thumbnailService != nil {
-				thumb, err = s.thumbnailService.GetOrGenerate(path)
-			} else {
-				thumb, err = imaging.GenerateThumbnail(path, s.thumbnailCache)
-			}
-
-			if err == nil {
-				docs[idx].Thumbnail = thumb
-			}
-		}(i, doc.Path)
 	}
-	wg.Wait()
 
 	c.JSON(http.StatusOK, dto.OcrDocumentsResponse{
 		Documents:   docs,
@@ -1450,7 +2229,7 @@ func (s *Server) handleGetOcrData(c *gin.Context) {
 	// Find classification
 	var classification domain.OcrClassification
 	if err := s.db.Table("ocr_classifications").
-		Joins("JOIN image_files ON image_files.id = ocr_classifications.image_file_id").
+		Joins("JOIN image_files ON image_files.id = ocr_classifications.image_file_id AND image_files.deleted_at IS NULL").
 		Where("image_files.path = ?", imagePath).
 		First(&classification).Error; err != nil {
 		c.JSON(http.StatusNotFound, i18n.ErrorResponse(i18n.MsgOcrDataNotFound))
@@ -1791,17 +2570,34 @@ func (s *Server) handleGetLlmModels(c *gin.Context) {
 	})
 }
 
-// handleThumbnailCacheStats возвращает статистику кэша миниатюр
+// handleThumbnailCacheStats возвращает статистику дискового кэша миниатюр и кэша в памяти
 func (s *Server) handleThumbnailCacheStats(c *gin.Context) {
+	var resp dto.ThumbnailCacheStatsResponse
 	if s.thumbnailService == nil {
-		log.Printf("Thumbnail stats requested: service is nil")
-		c.JSON(http.StatusOK, thumbnail.ThumbnailStats{})
-		return
+		slog.Debug("Thumbnail stats requested: service unavailable")
+	} else {
+		stats := s.thumbnailService.Stats()
+		slog.Debug("Thumbnail stats", "stats", stats)
+		resp = dto.ThumbnailCacheStatsResponse{
+			TotalSize:   stats.TotalSize,
+			TotalFiles:  stats.TotalFiles,
+			CacheDir:    stats.CacheDir,
+			Enabled:     stats.Enabled,
+			Initialized: stats.Initialized,
+		}
 	}
 
-	stats := s.thumbnailService.Stats()
-	log.Printf("Thumbnail stats: %+v", stats)
-	c.JSON(http.StatusOK, stats)
+	memory := s.thumbnailCache.Stats()
+	resp.Memory = dto.MemoryThumbnailCacheDTO{
+		Entries:    memory.Entries,
+		Bytes:      memory.Bytes,
+		MaxEntries: memory.MaxEntries,
+		MaxBytes:   memory.MaxBytes,
+		Hits:       memory.Hits,
+		Misses:     memory.Misses,
+		Evictions:  memory.Evictions,
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 // handleThumbnailCacheInvalidate удаляет миниатюру из кэша
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleIgnoreGroup marks an exact duplicate group as not duplicates, so it is no
+// longer listed, reported or removed by batch deletion
+func (s *Server) handleIgnoreGroup(c *gin.Context) {
+	group, err := imaging.IgnoreGroup(s.db, c.Param("hash"))
+	if err != nil {
+		slog.Error("Failed to ignore duplicate group", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanIgnoreFailed))
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, i18n.ErrorResponse(i18n.MsgScanGroupNotFound))
+		return
+	}
+	c.JSON(http.StatusOK, ignoredGroupDTO(*group))
+}
+
+// handleGetIgnoredGroups returns the groups marked as not duplicates
+func (s *Server) handleGetIgnoredGroups(c *gin.Context) {
+	s.respondIgnoredGroups(c)
+}
+
+// handleUnignoreGroup lists an ignored group among the duplicates again
+func (s *Server) handleUnignoreGroup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	found, err := imaging.UnignoreGroup(s.db, uint(id))
+	if err != nil {
+		slog.Error("Failed to unignore duplicate group", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanIgnoreFailed))
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, i18n.ErrorResponse(i18n.MsgScanGroupNotFound))
+		return
+	}
+	s.respondIgnoredGroups(c)
+}
+
+func (s *Server) respondIgnoredGroups(c *gin.Context) {
+	groups, err := imaging.ListIgnoredGroups(s.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanIgnoreFailed))
+		return
+	}
+	resp := dto.IgnoredGroupsResponse{Groups: make([]dto.IgnoredGroupDTO, len(groups))}
+	for i, g := range groups {
+		resp.Groups[i] = ignoredGroupDTO(g)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func ignoredGroupDTO(g domain.IgnoredGroup) dto.IgnoredGroupDTO {
+	return dto.IgnoredGroupDTO{
+		ID:        g.ID,
+		Hash:      g.Hash,
+		HashAlgo:  g.HashAlgo,
+		Size:      g.Size,
+		SizeHuman: formatSize(g.Size),
+		Path:      g.Path,
+		Files:     g.Files,
+		IgnoredAt: g.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetNameConflicts lists the file names shared by files of different content,
+// which would overwrite each other when their folders are merged
+func (s *Server) handleGetNameConflicts(c *gin.Context) {
+	filter, err := s.duplicateFilter(imaging.MediaAll, c.Query("dir"), "", "", "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if limit < 1 || limit > 5000 {
+		limit = 500
+	}
+
+	report, err := imaging.BuildNameConflictReport(s.db, filter)
+	if err != nil {
+		slog.Error("Failed to find name conflicts", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanConflictsFailed))
+		return
+	}
+
+	conflicts := report.Conflicts[:min(limit, len(report.Conflicts))]
+	resp := dto.NameConflictsResponse{
+		TotalConflicts: report.TotalConflicts,
+		TotalFiles:     report.TotalFiles,
+		Conflicts:      make([]dto.NameConflictDTO, len(conflicts)),
+	}
+	for i, conflict := range conflicts {
+		files := make([]dto.NameConflictFileDTO, len(conflict.Files))
+		for j, f := range conflict.Files {
+			files[j] = dto.NameConflictFileDTO{
+				ID:      f.ID,
+				Path:    f.Path,
+				DirPath: filepath.Dir(f.Path),
+				Size:    f.Size,
+				ModTime: f.ModTime.Format("2006-01-02 15:04:05"),
+				Version: f.Version,
+			}
+		}
+		resp.Conflicts[i] = dto.NameConflictDTO{Name: conflict.Name, Versions: conflict.Versions, Files: files}
+	}
+	c.JSON(http.StatusOK, resp)
+}
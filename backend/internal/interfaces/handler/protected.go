@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleSetProtected pins indexed files so that delete requests leave them in place,
+// or unpins them
+func (s *Server) handleSetProtected(c *gin.Context) {
+	var req dto.SetProtectedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	updated, err := imaging.SetProtected(s.db, req.FilePaths, req.Protected)
+	if err != nil {
+		slog.Error("Failed to change file protection", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanProtectFailed))
+		return
+	}
+	c.JSON(http.StatusOK, dto.SetProtectedResponse{Updated: updated})
+}
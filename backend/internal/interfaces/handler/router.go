@@ -1,15 +1,27 @@
 package handler
 
 import (
+	"log/slog"
+	"net/http"
+
 	"image-toolkit/internal/interfaces/middleware"
+	"image-toolkit/internal/interfaces/openapi"
 
 	"github.com/gin-gonic/gin"
 )
 
+// APIPrefix is the route prefix of the current JSON API version
+const APIPrefix = "/api/v1"
+
+// APIVersion is reported in the OpenAPI document
+const APIVersion = "1.0.0"
+
 // SetupRouter sets up the Gin router with all API routes
-func (s *Server) SetupRouter(authMiddleware *middleware.AuthMiddleware, csrfProtection *middleware.CSRFProtection, authHandlers *AuthHandlers) *gin.Engine {
+func (s *Server) SetupRouter(logger *slog.Logger, authMiddleware *middleware.AuthMiddleware, csrfProtection *middleware.CSRFProtection, authHandlers *AuthHandlers) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
-	r := gin.Default()
+	r := gin.New()
+	// Request logging goes first so it also records the 500s produced by Recovery
+	r.Use(middleware.RequestLogger(logger), gin.Recovery())
 
 	// Security headers middleware
 	r.Use(func(c *gin.Context) {
@@ -26,88 +38,135 @@ func (s *Server) SetupRouter(authMiddleware *middleware.AuthMiddleware, csrfProt
 	// CSRF protection
 	r.Use(csrfProtection.Middleware())
 
-	// Public auth routes
-	api := r.Group("/api")
+	// JSON API, versioned. The unversioned /api prefix is kept as an alias for
+	// existing clients and may be removed in a future major version.
+	v1 := r.Group(APIPrefix)
+	s.registerAPIRoutes(v1, authMiddleware, authHandlers)
+	s.registerAPIRoutes(r.Group("/api"), authMiddleware, authHandlers)
+
+	spec := openapi.Build("Image Dedup API", APIVersion, APIPrefix, r.Routes(), apiOperations)
+	v1.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec)
+	})
+
+	return r
+}
+
+// registerAPIRoutes registers all JSON endpoints on the given route group
+func (s *Server) registerAPIRoutes(api *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware, authHandlers *AuthHandlers) {
+	// Auth endpoints (public)
+	auth := api.Group("/auth")
 	{
-		// Auth endpoints (public)
-		auth := api.Group("/auth")
-		{
-			auth.GET("/status", authHandlers.handleAuthStatus)
-			auth.POST("/login", authHandlers.handleLogin)
-			auth.POST("/bootstrap/setup", authHandlers.handleBootstrapSetup)
-		}
+		auth.GET("/status", authHandlers.handleAuthStatus)
+		auth.POST("/login", authHandlers.handleLogin)
+		auth.POST("/bootstrap/setup", authHandlers.handleBootstrapSetup)
+	}
+
+	// Protected routes (require auth)
+	protected := api.Group("")
+	protected.Use(authMiddleware.RequireAuth())
+	{
+		protected.POST("/auth/logout", authHandlers.handleLogout)
+		protected.GET("/auth/me", authHandlers.handleMe)
+		protected.POST("/auth/change-password", authHandlers.handleChangePassword)
+		protected.PATCH("/users/me", authHandlers.handleUpdateProfile)
+
+		// Thumbnail cache endpoints
+		protected.GET("/thumbnail/cache/stats", s.handleThumbnailCacheStats)
+		protected.DELETE("/thumbnail/cache/invalidate", s.handleThumbnailCacheInvalidate)
+		protected.DELETE("/thumbnail/cache/invalidate-all", s.handleThumbnailCacheInvalidateAll)
+		protected.POST("/thumbnail/cache/warmup", s.handleThumbnailCacheWarmup)
+		protected.POST("/thumbnail/cache/enable", s.handleThumbnailCacheEnable)
+		protected.POST("/thumbnail/cache/disable", s.handleThumbnailCacheDisable)
 
-		// Protected routes (require auth)
-		protected := api.Group("")
-		protected.Use(authMiddleware.RequireAuth())
+		// Existing endpoints (now protected)
+		protected.GET("/duplicates", s.handleGetDuplicates)
+		protected.GET("/groups/:hash", s.handleGetGroup)
+		protected.POST("/groups/:hash/ignore", s.handleIgnoreGroup)
+		protected.PUT("/groups/:hash/review", s.handleReviewGroup)
+		protected.GET("/ignored-groups", s.handleGetIgnoredGroups)
+		protected.GET("/corrupt-files", s.handleGetCorruptFiles)
+		protected.GET("/name-conflicts", s.handleGetNameConflicts)
+		protected.GET("/timeline", s.handleGetTimeline)
+		protected.DELETE("/ignored-groups/:id", s.handleUnignoreGroup)
+		protected.GET("/export/csv", s.handleExportCSV)
+		protected.GET("/export/html", s.handleExportHTML)
+		protected.GET("/search", s.handleSearch)
+		protected.GET("/stats", s.handleGetStats)
+		protected.POST("/scan", s.handleScan)
+		protected.GET("/scan/jobs/:id", s.handleGetScanJob)
+		protected.POST("/scan/cancel", s.handleCancelScan)
+		protected.POST("/scan/pause", s.handlePauseScan)
+		protected.POST("/scan/resume", s.handleResumeScan)
+		protected.POST("/fast-scan", s.handleFastScan)
+		protected.POST("/rehash", s.handleRehash)
+		protected.GET("/schedule", s.handleGetSchedule)
+		protected.PUT("/schedule", s.handleUpdateSchedule)
+		protected.GET("/scan-runs", s.handleGetScanRuns)
+		protected.GET("/status", s.handleGetStatus)
+		protected.GET("/ws", s.handleWebSocket)
+		protected.POST("/delete-files", s.handleDeleteFiles)
+		protected.POST("/generate-script", s.handleGenerateScript)
+		protected.POST("/deletion-plan/s3", s.handleS3DeletionPlan)
+		protected.GET("/thumbnail", s.handleThumbnail)
+		protected.GET("/folder-patterns", s.handleGetFolderPatterns)
+		protected.POST("/batch-delete", s.handleBatchDelete)
+		protected.POST("/consolidate", s.handleConsolidate)
+		protected.PUT("/files/protected", s.handleSetProtected)
+		protected.GET("/selection", s.handleGetSelection)
+		protected.PUT("/selection", s.handleUpdateSelection)
+		protected.DELETE("/selection", s.handleClearSelection)
+		protected.POST("/auto-select", s.handleAutoSelect)
+		protected.GET("/folders", s.handleGetFolders)
+		protected.POST("/folders", s.handleAddFolder)
+		protected.DELETE("/folders/:id", s.handleRemoveFolder)
+		protected.GET("/gallery", s.handleGetGalleryImages)
+		protected.GET("/gallery/calendar", s.handleGetGalleryCalendar)
+		protected.GET("/gallery/calendar/month", s.handleGetCalendarMonthInfo)
+		protected.GET("/image", s.handleServeImage)
+		protected.GET("/ocr-image", s.handleServeOcrImage)
+		protected.GET("/settings", s.handleGetSettings)
+		protected.PUT("/settings", s.handleUpdateSettings)
+		protected.GET("/user-settings", s.handleGetUserSettings)
+		protected.PUT("/user-settings", s.handleUpdateUserSettings)
+		protected.GET("/trash-info", s.handleGetTrashInfo)
+		protected.POST("/trash-clean", s.handleCleanTrash)
+		protected.GET("/deletions", s.handleGetDeletions)
+		protected.POST("/restore/:batchId", s.handleRestore)
+		protected.GET("/image-metadata", s.handleGetImageMetadata)
+		protected.GET("/metadata-status", s.handleGetMetadataStatus)
+		protected.GET("/ocr-status", s.handleGetOCRStatus)
+		protected.POST("/ocr/classify", s.handleStartOcrClassification)
+		protected.POST("/ocr/classify-changes", s.handleStartOcrClassificationIncremental)
+		protected.POST("/ocr/stop", s.handleStopOcrClassification)
+		protected.GET("/ocr/classify-status", s.handleGetOcrClassificationStatus)
+		protected.GET("/ocr/documents", s.handleGetOcrDocuments)
+		protected.GET("/ocr/data", s.handleGetOcrData)
+
+		// LLM OCR endpoints
+		protected.GET("/llm/settings", s.handleGetLlmSettings)
+		protected.PUT("/llm/settings", s.handleUpdateLlmSettings)
+		protected.POST("/llm/recognize", s.handleLlmRecognize)
+		protected.GET("/llm/recognize-status", s.handleLlmRecognizeStatus)
+		protected.GET("/llm/recognition", s.handleGetLlmRecognition)
+		protected.GET("/llm/models", s.handleGetLlmModels)
+
+		// Agents reporting the files of other computers
+		protected.POST("/agent/sync/start", s.handleAgentSyncStart)
+		protected.POST("/agent/files", s.handleAgentFiles)
+		protected.POST("/agent/sync/finish", s.handleAgentSyncFinish)
+		protected.GET("/hosts", s.handleHosts)
+
+		// Admin routes
+		admin := protected.Group("/admin")
+		admin.Use(middleware.RequireAdmin())
 		{
-			protected.POST("/auth/logout", authHandlers.handleLogout)
-			protected.GET("/auth/me", authHandlers.handleMe)
-			protected.POST("/auth/change-password", authHandlers.handleChangePassword)
-			protected.PATCH("/users/me", authHandlers.handleUpdateProfile)
-
-			// Thumbnail cache endpoints
-			protected.GET("/thumbnail/cache/stats", s.handleThumbnailCacheStats)
-			protected.DELETE("/thumbnail/cache/invalidate", s.handleThumbnailCacheInvalidate)
-			protected.DELETE("/thumbnail/cache/invalidate-all", s.handleThumbnailCacheInvalidateAll)
-			protected.POST("/thumbnail/cache/warmup", s.handleThumbnailCacheWarmup)
-			protected.POST("/thumbnail/cache/enable", s.handleThumbnailCacheEnable)
-			protected.POST("/thumbnail/cache/disable", s.handleThumbnailCacheDisable)
-
-			// Existing endpoints (now protected)
-			protected.GET("/duplicates", s.handleGetDuplicates)
-			protected.POST("/scan", s.handleScan)
-			protected.POST("/fast-scan", s.handleFastScan)
-			protected.GET("/status", s.handleGetStatus)
-			protected.POST("/delete-files", s.handleDeleteFiles)
-			protected.GET("/thumbnail", s.handleThumbnail)
-			protected.GET("/folder-patterns", s.handleGetFolderPatterns)
-			protected.POST("/batch-delete", s.handleBatchDelete)
-			protected.GET("/folders", s.handleGetFolders)
-			protected.POST("/folders", s.handleAddFolder)
-			protected.DELETE("/folders/:id", s.handleRemoveFolder)
-			protected.GET("/gallery", s.handleGetGalleryImages)
-			protected.GET("/gallery/calendar", s.handleGetGalleryCalendar)
-			protected.GET("/gallery/calendar/month", s.handleGetCalendarMonthInfo)
-			protected.GET("/image", s.handleServeImage)
-			protected.GET("/ocr-image", s.handleServeOcrImage)
-			protected.GET("/settings", s.handleGetSettings)
-			protected.PUT("/settings", s.handleUpdateSettings)
-			protected.GET("/user-settings", s.handleGetUserSettings)
-			protected.PUT("/user-settings", s.handleUpdateUserSettings)
-			protected.GET("/trash-info", s.handleGetTrashInfo)
-			protected.POST("/trash-clean", s.handleCleanTrash)
-			protected.GET("/image-metadata", s.handleGetImageMetadata)
-			protected.GET("/metadata-status", s.handleGetMetadataStatus)
-			protected.GET("/ocr-status", s.handleGetOCRStatus)
-			protected.POST("/ocr/classify", s.handleStartOcrClassification)
-			protected.POST("/ocr/classify-changes", s.handleStartOcrClassificationIncremental)
-			protected.POST("/ocr/stop", s.handleStopOcrClassification)
-			protected.GET("/ocr/classify-status", s.handleGetOcrClassificationStatus)
-			protected.GET("/ocr/documents", s.handleGetOcrDocuments)
-			protected.GET("/ocr/data", s.handleGetOcrData)
-
-			// LLM OCR endpoints
-			protected.GET("/llm/settings", s.handleGetLlmSettings)
-			protected.PUT("/llm/settings", s.handleUpdateLlmSettings)
-			protected.POST("/llm/recognize", s.handleLlmRecognize)
-			protected.GET("/llm/recognize-status", s.handleLlmRecognizeStatus)
-			protected.GET("/llm/recognition", s.handleGetLlmRecognition)
-			protected.GET("/llm/models", s.handleGetLlmModels)
-
-			// Admin routes
-			admin := protected.Group("/admin")
-			admin.Use(middleware.RequireAdmin())
-			{
-				admin.GET("/users", authHandlers.handleListUsers)
-				admin.POST("/users", authHandlers.handleCreateUser)
-				admin.PATCH("/users/:id", authHandlers.handleUpdateUser)
-				admin.DELETE("/users/:id", authHandlers.handleDeleteUser)
-				admin.POST("/users/:id/reset-password", authHandlers.handleResetPassword)
-				admin.GET("/audit", authHandlers.handleAuditLogs)
-			}
+			admin.GET("/users", authHandlers.handleListUsers)
+			admin.POST("/users", authHandlers.handleCreateUser)
+			admin.PATCH("/users/:id", authHandlers.handleUpdateUser)
+			admin.DELETE("/users/:id", authHandlers.handleDeleteUser)
+			admin.POST("/users/:id/reset-password", authHandlers.handleResetPassword)
+			admin.GET("/audit", authHandlers.handleAuditLogs)
 		}
 	}
-
-	return r
 }
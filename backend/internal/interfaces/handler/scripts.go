@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/infrastructure/objectstore"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGenerateScript writes a shell, PowerShell or cmd script that moves the given files
+// to the trash folder (or deletes them) into a server folder, for running on the
+// machine that holds the files. With restore, a matching script that moves them
+// back is written next to it. With download, the script is returned as an
+// attachment instead.
+func (s *Server) handleGenerateScript(c *gin.Context) {
+	var req dto.GenerateScriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if len(req.FilePaths) == 0 {
+		c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgScanNoFilesSelected))
+		return
+	}
+	scriptType, err := imaging.ParseScriptType(req.ScriptType)
+	if err != nil || (!req.Download && !filepath.IsAbs(req.OutputDir)) {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if req.Restore && req.TrashDir == "" {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.MsgScriptRestoreNoTrash))
+		return
+	}
+
+	// Objects in a store cannot be moved by a shell script
+	guard := s.scanManager.GalleryPathGuard()
+	var files, remote []string
+	for _, p := range req.FilePaths {
+		if objectstore.IsRemote(p) {
+			remote = append(remote, p)
+			continue
+		}
+		if !guard.Allows(p) {
+			slog.Warn("Script rejected: file is outside the gallery folders", "path", p)
+			c.JSON(http.StatusForbidden, i18n.ErrorResponse(i18n.MsgScanPathNotAllowed))
+			return
+		}
+		files = append(files, filepath.ToSlash(p))
+	}
+
+	trashDir := filepath.ToSlash(req.TrashDir)
+	format := imaging.ScriptFormat{Encoding: req.Encoding, LineEndings: req.LineEndings}
+	var script, restore []byte
+	if !req.Download || !req.Restore {
+		script, err = imaging.GenerateScript(scriptType, files, trashDir, format)
+	}
+	if err == nil && req.Restore {
+		restore, err = imaging.GenerateRestoreScript(scriptType, files, trashDir, format)
+	}
+	if err != nil {
+		if errors.Is(err, imaging.ErrScriptNotEncodable) {
+			c.JSON(http.StatusBadRequest, i18n.ErrorResponse(i18n.MsgScriptNotEncodable))
+			return
+		}
+		if errors.Is(err, imaging.ErrScriptFormat) {
+			c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.MsgScriptFormatInvalid))
+			return
+		}
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	if req.Download {
+		name, data := scriptType.ScriptName(false), script
+		if req.Restore {
+			name, data = scriptType.ScriptName(true), restore
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+		if len(remote) > 0 {
+			c.Header("X-Remote-Files", strconv.Itoa(len(remote)))
+		}
+		c.Data(http.StatusOK, scriptType.ContentType(format), data)
+		return
+	}
+
+	resp := dto.GenerateScriptResponse{
+		ScriptPath:  filepath.Join(req.OutputDir, scriptType.ScriptName(false)),
+		Files:       len(files),
+		RemoteFiles: remote,
+	}
+	err = os.MkdirAll(req.OutputDir, 0755)
+	if err == nil {
+		err = os.WriteFile(resp.ScriptPath, script, 0755)
+	}
+	if err == nil && restore != nil {
+		resp.RestoreScriptPath = filepath.Join(req.OutputDir, scriptType.ScriptName(true))
+		err = os.WriteFile(resp.RestoreScriptPath, restore, 0755)
+	}
+	if err != nil {
+		slog.Error("Failed to write script", "dir", req.OutputDir, "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScriptWriteFailed))
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
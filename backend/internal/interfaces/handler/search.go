@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleSearch finds indexed files by a part of their path, e.g. IMG_2034, and lists
+// the copies of each, so one can check whether a specific photo is duplicated
+func (s *Server) handleSearch(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+
+	results, total, err := imaging.SearchFiles(s.db, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanSearchFailed))
+		return
+	}
+
+	resultDTOs := make([]dto.SearchResultDTO, len(results))
+	for i, r := range results {
+		duplicates := make([]dto.FileDTO, len(r.Duplicates))
+		for j, d := range r.Duplicates {
+			duplicates[j] = fileDTO(d)
+		}
+		resultDTOs[i] = dto.SearchResultDTO{FileDTO: fileDTO(r.File), Hash: r.File.Hash, Duplicates: duplicates}
+	}
+
+	c.JSON(http.StatusOK, dto.SearchResponse{Query: query, Files: resultDTOs, Total: total, Limit: limit})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+	"image-toolkit/internal/interfaces/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetSelection returns the files marked keep or delete in the current session
+func (s *Server) handleGetSelection(c *gin.Context) {
+	s.respondSelection(c)
+}
+
+// handleUpdateSelection stores marks in the selection of the current session
+func (s *Server) handleUpdateSelection(c *gin.Context) {
+	var req dto.UpdateSelectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	marks := make([]domain.Selection, len(req.Items))
+	for i, item := range req.Items {
+		marks[i] = domain.Selection{Path: item.Path, Action: item.Action}
+	}
+	if err := imaging.UpdateSelection(s.db, middleware.GetSessionKey(c), marks); err != nil {
+		if errors.Is(err, imaging.ErrInvalidSelectionAction) {
+			c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+			return
+		}
+		slog.Error("Failed to store selection", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanSelectionFailed))
+		return
+	}
+	s.respondSelection(c)
+}
+
+// handleClearSelection removes the marks of the paths given as `path` query
+// parameters, or the whole selection of the current session without any
+func (s *Server) handleClearSelection(c *gin.Context) {
+	if err := imaging.ClearSelection(s.db, middleware.GetSessionKey(c), c.QueryArray("path")); err != nil {
+		slog.Error("Failed to clear selection", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanSelectionFailed))
+		return
+	}
+	s.respondSelection(c)
+}
+
+func (s *Server) respondSelection(c *gin.Context) {
+	rows, err := imaging.LoadSelection(s.db, middleware.GetSessionKey(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanSelectionFailed))
+		return
+	}
+	resp := dto.SelectionResponse{Items: make([]dto.SelectionItemDTO, len(rows))}
+	for i, row := range rows {
+		resp.Items[i] = dto.SelectionItemDTO{Path: row.Path, Action: row.Action}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// clearDeletedFromSelection drops the marks of files a delete request removed
+func (s *Server) clearDeletedFromSelection(c *gin.Context, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	if err := imaging.ClearSelection(s.db, middleware.GetSessionKey(c), paths); err != nil {
+		slog.Warn("Failed to clear deleted files from the selection", "error", err)
+	}
+}
@@ -1,14 +1,12 @@
 package handler
 
 import (
-	"fmt"
 	"strings"
 
 	"image-toolkit/internal/application/imaging"
 	"image-toolkit/internal/application/thumbnail"
 	"image-toolkit/internal/infrastructure/config"
 	"image-toolkit/internal/infrastructure/ocr"
-	"image-toolkit/internal/interfaces/dto"
 
 	"gorm.io/gorm"
 )
@@ -19,6 +17,7 @@ type Server struct {
 	thumbnailCache  *imaging.ThumbnailCache
 	thumbnailService *thumbnail.Service
 	scanManager     *imaging.ScanManager
+	scanScheduler   *imaging.ScanScheduler
 	metadataManager *imaging.MetadataManager
 	ocrManager      *imaging.OcrManager
 	llmOcrService   *imaging.LlmOcrService
@@ -27,16 +26,17 @@ type Server struct {
 }
 
 // NewServer creates a new server instance
-func NewServer(db *gorm.DB, scanManager *imaging.ScanManager, metadataManager *imaging.MetadataManager, ocrManager *imaging.OcrManager, llmOcrService *imaging.LlmOcrService, thumbnailService *thumbnail.Service, cfg *config.AppConfig) *Server {
+func NewServer(db *gorm.DB, scanManager *imaging.ScanManager, scanScheduler *imaging.ScanScheduler, metadataManager *imaging.MetadataManager, ocrManager *imaging.OcrManager, llmOcrService *imaging.LlmOcrService, thumbnailService *thumbnail.Service, cfg *config.AppConfig) *Server {
 	var ocrClient ocr.Client
 	if cfg.OCREnabled {
 		ocrClient = ocr.NewClient(cfg.OCRHost, cfg.OCRPort)
 	}
 	return &Server{
 		db:               db,
-		thumbnailCache:   imaging.NewThumbnailCache(),
+		thumbnailCache:   imaging.NewThumbnailCache(cfg.ThumbnailMemoryEntries, int64(cfg.ThumbnailMemoryMB)<<20),
 		thumbnailService: thumbnailService,
 		scanManager:      scanManager,
+		scanScheduler:    scanScheduler,
 		metadataManager:  metadataManager,
 		ocrManager:       ocrManager,
 		llmOcrService:    llmOcrService,
@@ -61,16 +61,7 @@ func (s *Server) StopOCRHealthCheck() {
 
 // formatSize formats file size in human readable format
 func formatSize(size int64) string {
-	const unit = 1024
-	if size < unit {
-		return fmt.Sprintf("%d B", size)
-	}
-	div, exp := int64(unit), 0
-	for n := size / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+	return imaging.FormatSize(size)
 }
 
 // pathsConflict checks if two normalized (forward-slash) paths are the same,
@@ -93,28 +84,6 @@ func pathsConflict(a, b string) string {
 	return ""
 }
 
-// sortStrings sorts a slice of strings in place
-func sortStrings(s []string) {
-	for i := 0; i < len(s)-1; i++ {
-		for j := i + 1; j < len(s); j++ {
-			if s[i] > s[j] {
-				s[i], s[j] = s[j], s[i]
-			}
-		}
-	}
-}
-
-// sortPatternsByCount sorts patterns by duplicate count descending
-func sortPatternsByCount(patterns []dto.FolderPattern) {
-	for i := 0; i < len(patterns)-1; i++ {
-		for j := i + 1; j < len(patterns); j++ {
-			if patterns[i].DuplicateCount < patterns[j].DuplicateCount {
-				patterns[i], patterns[j] = patterns[j], patterns[i]
-			}
-		}
-	}
-}
-
 // createPatternID creates a unique ID from sorted folder paths
 func createPatternID(folders []string) string {
 	return strings.Join(folders, "|")
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetStats reports where exact duplicates waste space: totals, the directories,
+// extensions and groups with the most duplicate bytes, and counts per month
+func (s *Server) handleGetStats(c *gin.Context) {
+	stats, err := imaging.BuildDuplicateStats(s.db, s.scanManager.Options().DuplicateFilter(imaging.MediaAll))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanStatsFailed))
+		return
+	}
+
+	resp := dto.StatsResponse{
+		IndexedFiles:     stats.IndexedFiles,
+		IndexedBytes:     stats.IndexedBytes,
+		DuplicateGroups:  stats.DuplicateGroups,
+		DuplicateFiles:   stats.DuplicateFiles,
+		DuplicateBytes:   stats.DuplicateBytes,
+		ReclaimableBytes: stats.ReclaimableBytes,
+		Directories:      statsShareDTOs(stats.Directories),
+		Extensions:       statsShareDTOs(stats.Extensions),
+		LargestGroups:    make([]dto.StatsGroupDTO, len(stats.LargestGroups)),
+		Months:           make([]dto.StatsMonthDTO, len(stats.Months)),
+	}
+	for i, g := range stats.LargestGroups {
+		resp.LargestGroups[i] = dto.StatsGroupDTO{
+			Hash:             g.Hash,
+			Size:             g.Size,
+			Files:            g.Files,
+			ReclaimableBytes: g.ReclaimableBytes,
+			Path:             g.Path,
+		}
+	}
+	for i, m := range stats.Months {
+		resp.Months[i] = dto.StatsMonthDTO{
+			Month:          m.Month,
+			DuplicateFiles: m.DuplicateFiles,
+			DuplicateBytes: m.DuplicateBytes,
+			DeletedFiles:   m.DeletedFiles,
+			DeletedBytes:   m.DeletedBytes,
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// statsShareDTOs converts the per-directory or per-extension shares of the statistics
+func statsShareDTOs(shares []imaging.ShareStats) []dto.StatsShareDTO {
+	dtos := make([]dto.StatsShareDTO, len(shares))
+	for i, sh := range shares {
+		dtos[i] = dto.StatsShareDTO{Key: sh.Key, Files: sh.Files, Bytes: sh.Bytes}
+	}
+	return dtos
+}
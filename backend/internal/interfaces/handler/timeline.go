@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/dto"
+	"image-toolkit/internal/interfaces/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetTimeline counts the exact duplicate groups per month they were shot in, so
+// that the client can clean them up one month at a time with /duplicates?month=
+func (s *Server) handleGetTimeline(c *gin.Context) {
+	media := imaging.MediaImages
+	if c.Query("media") == string(imaging.MediaVideos) {
+		media = imaging.MediaVideos
+	}
+	filter, err := s.duplicateFilter(media, c.Query("dir"), "", "", "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+	if filter.Location, err = imaging.ParseLocation(c.Query("near"), c.Query("radius")); err != nil {
+		c.JSON(http.StatusBadRequest, i18n.CreateValidationError(i18n.ValidationError))
+		return
+	}
+
+	months, err := imaging.BuildTimeline(s.db, filter)
+	if err != nil {
+		slog.Error("Failed to build the duplicate timeline", "error", err)
+		c.JSON(http.StatusInternalServerError, i18n.ErrorResponse(i18n.MsgScanTimelineFailed))
+		return
+	}
+
+	resp := dto.TimelineResponse{Months: make([]dto.TimelineMonthDTO, len(months))}
+	for i, m := range months {
+		resp.Months[i] = dto.TimelineMonthDTO{
+			Month:            m.Month,
+			Groups:           m.Groups,
+			Files:            m.Files,
+			ReclaimableBytes: m.ReclaimableBytes,
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"image-toolkit/internal/application/events"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+	wsMaxMessage = 4096
+)
+
+// Commands accepted over the WebSocket
+const (
+	wsCommandPause  = "pause"
+	wsCommandResume = "resume"
+	wsCommandCancel = "cancel"
+)
+
+// wsCommand is a client → server message
+type wsCommand struct {
+	Command string `json:"command"`
+}
+
+// wsCommandResult is the server's reply to a wsCommand
+type wsCommandResult struct {
+	Command string `json:"command"`
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+}
+
+// checkWSOrigin accepts same-host connections and the configured CORS origins
+func (s *Server) checkWSOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		return true
+	}
+	for _, allowed := range s.config.CORSOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// handleWebSocket upgrades the connection and streams application events (scan progress,
+// new duplicate groups, deletions) to the client, accepting pause/resume/cancel commands
+func (s *Server) handleWebSocket(c *gin.Context) {
+	upgrader := websocket.Upgrader{CheckOrigin: s.checkWSOrigin}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade has already written an HTTP error response
+		return
+	}
+	defer conn.Close()
+
+	eventsChan, unsubscribe := s.scanManager.Events.Subscribe()
+	defer unsubscribe()
+
+	// Replies to commands are funnelled through the writer goroutine, the only one allowed to write
+	replies := make(chan events.Event, 8)
+	readerDone := make(chan struct{})
+
+	go func() {
+		defer close(readerDone)
+		conn.SetReadLimit(wsMaxMessage)
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		})
+		for {
+			var cmd wsCommand
+			if err := conn.ReadJSON(&cmd); err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+					slog.Debug("WebSocket read error", "error", err)
+				}
+				return
+			}
+			select {
+			case replies <- events.Event{Type: "command.result", Time: time.Now(), Data: s.runWSCommand(cmd.Command)}:
+			default:
+			}
+		}
+	}()
+
+	// Send the current state so the client doesn't wait for the next change
+	initial := events.Event{Type: events.TypeScanStatus, Time: time.Now(), Data: s.scanManager.GetStatus()}
+	conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	if err := conn.WriteJSON(initial); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		var msg events.Event
+		select {
+		case <-readerDone:
+			return
+		case ev, ok := <-eventsChan:
+			if !ok {
+				return
+			}
+			msg = ev
+		case reply := <-replies:
+			msg = reply
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+			continue
+		}
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if err := conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
+
+// runWSCommand applies a scan control command and reports the outcome
+func (s *Server) runWSCommand(command string) wsCommandResult {
+	var err error
+	switch command {
+	case wsCommandPause:
+		err = s.scanManager.Pause()
+	case wsCommandResume:
+		err = s.scanManager.Resume()
+	case wsCommandCancel:
+		err = s.scanManager.Cancel()
+	default:
+		return wsCommandResult{Command: command, Error: "unknown command"}
+	}
+	if err != nil {
+		return wsCommandResult{Command: command, Error: err.Error()}
+	}
+	return wsCommandResult{Command: command, OK: true}
+}
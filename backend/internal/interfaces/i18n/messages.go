@@ -43,6 +43,40 @@ const (
 	MsgScanDuplicateFailed MessageKey = "scan.duplicate_failed"
 	MsgScanNoFilesSelected MessageKey = "scan.no_files_selected"
 	MsgScanTrashDirFailed  MessageKey = "scan.trash_dir_failed"
+	MsgScanPathNotAllowed  MessageKey = "scan.path_not_allowed"
+	MsgScanRunsFailed      MessageKey = "scan.runs_failed"
+	MsgScanJobNotFound     MessageKey = "scan.job_not_found"
+	MsgScanCancelled       MessageKey = "scan.cancelled"
+	MsgScanPaused          MessageKey = "scan.paused"
+	MsgScanResumed         MessageKey = "scan.resumed"
+	MsgScanNotRunning      MessageKey = "scan.not_running"
+	MsgScanAlreadyPaused   MessageKey = "scan.already_paused"
+	MsgScanNotPaused       MessageKey = "scan.not_paused"
+	MsgScanKeepRuleInvalid MessageKey = "scan.keep_rule_invalid"
+	MsgScanSearchFailed    MessageKey = "scan.search_failed"
+	MsgScanGroupNotFound   MessageKey = "scan.group_not_found"
+	MsgScanStatsFailed     MessageKey = "scan.stats_failed"
+	MsgScanSelectionFailed MessageKey = "scan.selection_failed"
+	MsgScanIgnoreFailed    MessageKey = "scan.ignore_failed"
+	MsgScanProtectFailed   MessageKey = "scan.protect_failed"
+	MsgScanReviewFailed    MessageKey = "scan.review_failed"
+	MsgScanLibraryFailed   MessageKey = "scan.library_failed"
+	MsgScanAgentInvalid    MessageKey = "scan.agent_invalid"
+	MsgScanAgentFailed     MessageKey = "scan.agent_failed"
+	MsgScanCorruptFailed   MessageKey = "scan.corrupt_failed"
+	MsgScanConflictsFailed MessageKey = "scan.conflicts_failed"
+	MsgScanTimelineFailed  MessageKey = "scan.timeline_failed"
+
+	// Script messages
+	MsgScriptWriteFailed    MessageKey = "script.write_failed"
+	MsgScriptNotEncodable   MessageKey = "script.not_encodable"
+	MsgScriptRestoreNoTrash MessageKey = "script.restore_no_trash"
+	MsgScriptFormatInvalid  MessageKey = "script.format_invalid"
+
+	// Schedule messages
+	MsgScheduleInvalid    MessageKey = "schedule.invalid"
+	MsgScheduleSaved      MessageKey = "schedule.saved"
+	MsgScheduleSaveFailed MessageKey = "schedule.save_failed"
 
 	// Folder messages
 	MsgFolderPathRequired     MessageKey = "folder.path_required"
@@ -71,6 +105,7 @@ const (
 	MsgImageTrashCleanFailed   MessageKey = "image.trash_clean_failed"
 	MsgImageThumbnailFailed    MessageKey = "image.thumbnail_failed"
 	MsgImageMetadataFailed     MessageKey = "image.metadata_failed"
+	MsgImageConvertFailed      MessageKey = "image.convert_failed"
 
 	// User service messages
 	MsgUserServiceInvalidRole         MessageKey = "user_service.invalid_role"
@@ -89,6 +124,9 @@ const (
 	MsgTrashNotConfigured MessageKey = "trash.not_configured"
 	MsgTrashNotExists     MessageKey = "trash.not_exists"
 	MsgTrashReadFailed    MessageKey = "trash.read_failed"
+	MsgDeletionsFailed    MessageKey = "trash.deletions_failed"
+	MsgDeletionNotFound   MessageKey = "trash.deletion_not_found"
+	MsgRestoreFailed      MessageKey = "trash.restore_failed"
 
 	// Gallery messages
 	MsgGalleryConflict MessageKey = "gallery.conflict"
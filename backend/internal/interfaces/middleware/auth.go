@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"image-toolkit/internal/application/auth"
 	"image-toolkit/internal/domain"
@@ -15,14 +18,19 @@ const (
 	SessionCookieName = "session_id"
 
 	// Context keys for storing user in gin context
-	ContextKeyUser   = "user"
-	ContextKeyUserID = "user_id"
+	ContextKeyUser       = "user"
+	ContextKeyUserID     = "user_id"
+	ContextKeySessionKey = "session_key"
 )
 
-// AuthMiddleware extracts and validates the session from cookie
+// AuthMiddleware extracts and validates the session from cookie, or the
+// Authorization header of non-browser clients
 type AuthMiddleware struct {
 	sessionRepo *auth.SessionRepository
 	authService *auth.AuthService
+
+	// BasicAuthEnabled accepts HTTP basic auth with user login and password
+	BasicAuthEnabled bool
 }
 
 // NewAuthMiddleware creates a new auth middleware
@@ -33,9 +41,16 @@ func NewAuthMiddleware(sessionRepo *auth.SessionRepository, authService *auth.Au
 	}
 }
 
-// RequireAuth validates the session and loads the user into context
+// RequireAuth validates the session and loads the user into context.
+// An Authorization header (bearer token or basic auth, if enabled) takes
+// precedence over the session cookie.
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			m.authenticateHeader(c)
+			return
+		}
+
 		token, err := c.Cookie(SessionCookieName)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, i18n.ErrorResponse(i18n.MsgMiddlewareUnauthorized))
@@ -53,11 +68,46 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		// Set user in context
 		c.Set(ContextKeyUser, user)
 		c.Set(ContextKeyUserID, user.ID)
+		c.Set(ContextKeySessionKey, auth.HashSessionToken(token))
 
 		c.Next()
 	}
 }
 
+// authenticateHeader authenticates a request by its Authorization header
+func (m *AuthMiddleware) authenticateHeader(c *gin.Context) {
+	var user *domain.User
+	var err error
+	basic := false
+
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && m.authService.APITokenEnabled() {
+		user, err = m.authService.AuthenticateToken(strings.TrimSpace(token), c.ClientIP())
+	} else if login, password, ok := c.Request.BasicAuth(); ok && m.BasicAuthEnabled {
+		basic = true
+		user, err = m.authService.AuthenticateBasic(login, password, c.ClientIP())
+	} else {
+		err = domain.ErrInvalidCredentials
+	}
+
+	if err != nil {
+		if errors.Is(err, domain.ErrRateLimited) {
+			c.JSON(http.StatusTooManyRequests, i18n.ErrorResponse(i18n.MsgAuthRateLimited))
+		} else {
+			if basic {
+				c.Header("WWW-Authenticate", `Basic realm="image-dedup", charset="UTF-8"`)
+			}
+			c.JSON(http.StatusUnauthorized, i18n.ErrorResponse(i18n.MsgMiddlewareUnauthorized))
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set(ContextKeyUser, user)
+	c.Set(ContextKeyUserID, user.ID)
+	c.Set(ContextKeySessionKey, fmt.Sprintf("%s%d", domain.UserSelectionKeyPrefix, user.ID))
+	c.Next()
+}
+
 // RequireAdmin ensures the current user has admin role
 func RequireAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -104,3 +154,9 @@ func GetUserID(c *gin.Context) uint {
 	}
 	return userID
 }
+
+// GetSessionKey returns the key of data stored for the current session: the hashed
+// session token, or the user for clients authenticated by a header
+func GetSessionKey(c *gin.Context) string {
+	return c.GetString(ContextKeySessionKey)
+}
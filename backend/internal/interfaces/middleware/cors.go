@@ -18,7 +18,7 @@ func SetupCORS(cfg *config.AppConfig) gin.HandlerFunc {
 	corsConfig := cors.Config{
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "X-Remote-Files"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
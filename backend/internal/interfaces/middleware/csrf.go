@@ -35,8 +35,12 @@ func NewCSRFProtection() *CSRFProtection {
 	}
 }
 
-// ShouldSkipCSRF checks if a path should skip CSRF validation
+// ShouldSkipCSRF checks if a path should skip CSRF validation.
+// Versioned paths (/api/v1/...) match the same entries as /api/...
 func (p *CSRFProtection) ShouldSkipCSRF(path string) bool {
+	if rest, ok := strings.CutPrefix(path, "/api/v1/"); ok {
+		path = "/api/" + rest
+	}
 	for _, skipPath := range p.skipPaths {
 		if path == skipPath {
 			return true
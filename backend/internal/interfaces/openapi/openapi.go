@@ -0,0 +1,287 @@
+// Package openapi generates an OpenAPI 3 document from the registered Gin routes
+// and the Go types of their request and response bodies.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"image-toolkit/internal/interfaces/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the OpenAPI specification version of generated documents
+const Version = "3.0.3"
+
+// Operation describes a single route. Request and Response are sample values
+// (usually zero values of DTO structs) whose types define the body schemas.
+type Operation struct {
+	Summary  string
+	Tag      string
+	Query    []Param
+	Request  any
+	Response any
+	// ContentType of a non-JSON success response, e.g. "text/csv"
+	ContentType string
+	Public      bool // no session required
+}
+
+// Param is a query parameter
+type Param struct {
+	Name        string
+	Type        string // string, integer, number or boolean
+	Description string
+	Required    bool
+}
+
+// Document is the generated OpenAPI document, ready for JSON encoding
+type Document map[string]any
+
+// Build generates a document for all routes under prefix. ops is keyed by
+// "METHOD /path" with the path relative to prefix in Gin syntax (":id").
+// Routes without an entry are documented with a generic JSON response.
+func Build(title, version, prefix string, routes gin.RoutesInfo, ops map[string]Operation) Document {
+	g := &generator{schemas: make(map[string]any)}
+
+	paths := make(map[string]map[string]any)
+	for _, r := range routes {
+		if !strings.HasPrefix(r.Path, prefix+"/") {
+			continue
+		}
+		rel := strings.TrimPrefix(r.Path, prefix)
+		op := ops[r.Method+" "+rel]
+
+		apiPath, pathParams := convertPath(rel)
+		item := paths[apiPath]
+		if item == nil {
+			item = make(map[string]any)
+			paths[apiPath] = item
+		}
+		item[strings.ToLower(r.Method)] = g.operation(r.Method, apiPath, op, pathParams)
+	}
+
+	return Document{
+		"openapi": Version,
+		"info": map[string]any{
+			"title":   title,
+			"version": version,
+		},
+		"servers": []any{map[string]any{"url": prefix}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]any{
+				"session": map[string]any{"type": "apiKey", "in": "cookie", "name": middleware.SessionCookieName},
+				"bearer":  map[string]any{"type": "http", "scheme": "bearer"},
+				"basic":   map[string]any{"type": "http", "scheme": "basic"},
+			},
+		},
+		// Any one of the schemes is sufficient; bearer and basic are optional server features
+		"security": []any{
+			map[string]any{"session": []any{}},
+			map[string]any{"bearer": []any{}},
+			map[string]any{"basic": []any{}},
+		},
+	}
+}
+
+// convertPath turns Gin path parameters (":id", "*path") into OpenAPI templates
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+type generator struct {
+	schemas map[string]any
+}
+
+func (g *generator) operation(method, path string, op Operation, pathParams []string) map[string]any {
+	o := map[string]any{
+		"operationId": operationID(method, path),
+	}
+	if op.Summary != "" {
+		o["summary"] = op.Summary
+	}
+	if op.Tag != "" {
+		o["tags"] = []string{op.Tag}
+	}
+	if op.Public {
+		o["security"] = []any{}
+	}
+
+	var params []any
+	for _, p := range pathParams {
+		params = append(params, map[string]any{
+			"name": p, "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+		})
+	}
+	for _, p := range op.Query {
+		typ := p.Type
+		if typ == "" {
+			typ = "string"
+		}
+		param := map[string]any{"name": p.Name, "in": "query", "schema": map[string]any{"type": typ}}
+		if p.Required {
+			param["required"] = true
+		}
+		if p.Description != "" {
+			param["description"] = p.Description
+		}
+		params = append(params, param)
+	}
+	if len(params) > 0 {
+		o["parameters"] = params
+	}
+
+	if op.Request != nil {
+		o["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(op.Request))},
+			},
+		}
+	}
+
+	var content map[string]any
+	switch {
+	case op.ContentType != "":
+		content = map[string]any{op.ContentType: map[string]any{"schema": map[string]any{"type": "string"}}}
+	case op.Response != nil:
+		content = map[string]any{"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(op.Response))}}
+	default:
+		content = map[string]any{"application/json": map[string]any{"schema": map[string]any{"type": "object"}}}
+	}
+	o["responses"] = map[string]any{
+		"200":     map[string]any{"description": http.StatusText(http.StatusOK), "content": content},
+		"default": map[string]any{"description": "Error", "content": map[string]any{"application/json": map[string]any{"schema": errorSchema}}},
+	}
+	return o
+}
+
+// errorSchema matches i18n.ErrorResponse: {"error": "<message key>"}
+var errorSchema = map[string]any{
+	"type":       "object",
+	"properties": map[string]any{"error": map[string]any{"type": "string"}},
+}
+
+// operationID derives a unique identifier from the route, e.g.
+// GET /scan/jobs/{id} -> getScanJobsId
+func operationID(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	upper := true
+	for _, r := range path {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+			if upper && r >= 'a' && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			sb.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	return sb.String()
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schema returns the schema of t; named structs become $refs to components
+func (g *generator) schema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		s := g.schema(t.Elem())
+		if _, isRef := s["$ref"]; isRef {
+			return map[string]any{"allOf": []any{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = nil // placeholder breaks recursion
+			g.schemas[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]any{}
+}
+
+func (g *generator) structSchema(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	g.addFields(t, props, &required)
+
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+// addFields collects JSON-visible fields, flattening embedded structs like encoding/json
+func (g *generator) addFields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(ft, props, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
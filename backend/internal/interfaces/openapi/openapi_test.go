@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testItem struct {
+	Name     string     `json:"name"`
+	Tags     []string   `json:"tags,omitempty"`
+	Parent   *testItem  `json:"parent"`
+	Created  time.Time  `json:"createdAt"`
+	Deleted  *time.Time `json:"deletedAt"`
+	internal int
+	Skipped  string `json:"-"`
+}
+
+type testResponse struct {
+	Items []testItem `json:"items"`
+	Total int64      `json:"total"`
+}
+
+func TestBuild(t *testing.T) {
+	routes := gin.RoutesInfo{
+		{Method: "GET", Path: "/api/v1/items/:id"},
+		{Method: "POST", Path: "/api/v1/items"},
+		{Method: "GET", Path: "/api/items"}, // legacy alias, not documented
+	}
+	ops := map[string]Operation{
+		"GET /items/:id": {Summary: "Get item", Response: testResponse{}},
+		"POST /items":    {Summary: "Create item", Request: testItem{}, Public: true},
+	}
+
+	doc := Build("Test", "1.0.0", "/api/v1", routes, ops)
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("document is not JSON-encodable: %v", err)
+	}
+
+	paths := doc["paths"].(map[string]map[string]any)
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 paths, got %v", reflect.ValueOf(paths).MapKeys())
+	}
+	get := paths["/items/{id}"]["get"].(map[string]any)
+	if get["operationId"] != "getItemsId" {
+		t.Errorf("operationId = %v", get["operationId"])
+	}
+	params := get["parameters"].([]any)
+	if p := params[0].(map[string]any); p["name"] != "id" || p["in"] != "path" {
+		t.Errorf("path parameter = %v", p)
+	}
+	if post := paths["/items"]["post"].(map[string]any); post["security"] == nil {
+		t.Error("public operation must override security")
+	}
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	item, ok := schemas["testItem"].(map[string]any)
+	if !ok {
+		t.Fatal("testItem schema missing")
+	}
+	props := item["properties"].(map[string]any)
+	if _, ok := props["Skipped"]; ok {
+		t.Error(`json:"-" field must be skipped`)
+	}
+	if _, ok := props["internal"]; ok {
+		t.Error("unexported field must be skipped")
+	}
+	if got := props["createdAt"].(map[string]any)["format"]; got != "date-time" {
+		t.Errorf("time format = %v", got)
+	}
+	if got := props["parent"].(map[string]any)["nullable"]; got != true {
+		t.Errorf("pointer field nullable = %v", got)
+	}
+	if got := item["required"]; !reflect.DeepEqual(got, []string{"createdAt", "name"}) {
+		t.Errorf("required = %v", got)
+	}
+}
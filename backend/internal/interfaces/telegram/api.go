@@ -0,0 +1,129 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// pollTimeout is how long getUpdates waits for updates before returning empty
+const pollTimeout = 50 * time.Second
+
+// api is a minimal client of the Telegram Bot API
+type api struct {
+	baseURL string // https://api.telegram.org/bot<token>
+	client  *http.Client
+}
+
+func newAPI(baseURL string) *api {
+	return &api{baseURL: baseURL, client: &http.Client{Timeout: pollTimeout + 10*time.Second}}
+}
+
+type update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *message       `json:"message"`
+	CallbackQuery *callbackQuery `json:"callback_query"`
+}
+
+type message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type chat struct {
+	ID int64 `json:"id"`
+}
+
+type callbackQuery struct {
+	ID      string   `json:"id"`
+	Message *message `json:"message"`
+	Data    string   `json:"data"`
+}
+
+type inlineKeyboard struct {
+	InlineKeyboard [][]inlineButton `json:"inline_keyboard"`
+}
+
+type inlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// call invokes a Bot API method with JSON parameters and decodes its result
+func (a *api) call(ctx context.Context, method string, params, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		// The URL holds the bot token; keep it out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var reply struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return fmt.Errorf("%s: %s", method, resp.Status)
+	}
+	if !reply.OK {
+		return fmt.Errorf("%s: %s", method, reply.Description)
+	}
+	if result != nil {
+		return json.Unmarshal(reply.Result, result)
+	}
+	return nil
+}
+
+// getUpdates long-polls for the updates after offset
+func (a *api) getUpdates(ctx context.Context, offset int64) ([]update, error) {
+	var updates []update
+	err := a.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(pollTimeout.Seconds()),
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &updates)
+	return updates, err
+}
+
+// sendMessage sends an HTML message, with buttons when keyboard is set
+func (a *api) sendMessage(ctx context.Context, chatID int64, text string, keyboard *inlineKeyboard) (message, error) {
+	params := map[string]any{"chat_id": chatID, "text": text, "parse_mode": "HTML", "disable_web_page_preview": true}
+	if keyboard != nil {
+		params["reply_markup"] = keyboard
+	}
+	var sent message
+	err := a.call(ctx, "sendMessage", params, &sent)
+	return sent, err
+}
+
+// editMessage replaces the text of a message, removing its buttons
+func (a *api) editMessage(ctx context.Context, chatID, messageID int64, text string) error {
+	return a.call(ctx, "editMessageText", map[string]any{
+		"chat_id": chatID, "message_id": messageID, "text": text, "parse_mode": "HTML",
+	}, nil)
+}
+
+// answerCallback acknowledges a button press, showing text to the user
+func (a *api) answerCallback(ctx context.Context, id, text string) error {
+	return a.call(ctx, "answerCallbackQuery", map[string]any{"callback_query_id": id, "text": text}, nil)
+}
@@ -0,0 +1,375 @@
+// Package telegram runs an optional Telegram bot for headless servers: it sends scan
+// summaries and offers deletion plans that are carried out with a button press
+package telegram
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"image-toolkit/internal/application/events"
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/interfaces/dto"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// planLifetime is how long a deletion plan can be approved
+	planLifetime = 24 * time.Hour
+	// planSampleGroups and planSampleFiles bound the groups shown in a plan message
+	// and the files shown per group, within the message size limit
+	planSampleGroups = 5
+	planSampleFiles  = 3
+	// retryDelay is the pause after a failed poll
+	retryDelay = 5 * time.Second
+)
+
+// Bot sends scan summaries to the configured chats and answers their commands and
+// buttons. Other chats are ignored.
+type Bot struct {
+	api         *api
+	chatIDs     []int64
+	db          *gorm.DB
+	scanManager *imaging.ScanManager
+	keepRules   []imaging.KeepRule
+
+	mu      sync.Mutex
+	pending *pendingPlan // the deletion plan offered last, until approved or dismissed
+}
+
+// pendingPlan is a deletion plan waiting for approval, with the messages offering it
+type pendingPlan struct {
+	id       string
+	plan     *imaging.DeletionPlan
+	created  time.Time
+	messages []message
+}
+
+// NewBot creates a bot. chatIDs are the numeric IDs of the chats it serves; the plans
+// keep one file per duplicate group by the keep policies, keep-oldest if none.
+func NewBot(token string, chatIDs, keepPolicies []string, db *gorm.DB, scanManager *imaging.ScanManager) (*Bot, error) {
+	if token == "" {
+		return nil, fmt.Errorf("bot token is not set")
+	}
+	if len(chatIDs) == 0 {
+		return nil, fmt.Errorf("no chat IDs")
+	}
+	b := &Bot{api: newAPI("https://api.telegram.org/bot" + token), db: db, scanManager: scanManager}
+	for _, s := range chatIDs {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chat ID %q", s)
+		}
+		b.chatIDs = append(b.chatIDs, id)
+	}
+	if len(keepPolicies) == 0 {
+		keepPolicies = []string{string(imaging.KeepOldest)}
+	}
+	for _, p := range keepPolicies {
+		b.keepRules = append(b.keepRules, imaging.KeepRule{Policy: imaging.KeepPolicy(p)})
+	}
+	if err := imaging.ValidateKeepRules(b.keepRules); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Start runs the bot in the background until stop is called
+func (b *Bot) Start() (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, unsubscribe := b.scanManager.Events.Subscribe()
+	go func() {
+		for event := range ch {
+			if summary, ok := event.Data.(imaging.ScanSummary); ok {
+				go b.reportScan(ctx, summary)
+			}
+		}
+	}()
+	go b.poll(ctx)
+	return func() {
+		cancel()
+		unsubscribe()
+	}
+}
+
+// poll receives and handles updates until ctx is cancelled
+func (b *Bot) poll(ctx context.Context) {
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := b.api.getUpdates(ctx, offset)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("Telegram: failed to receive updates", "error", err)
+				select {
+				case <-ctx.Done():
+				case <-time.After(retryDelay):
+				}
+			}
+			continue
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			b.handleUpdate(ctx, u)
+		}
+	}
+}
+
+// allowed reports whether a chat is one the bot serves
+func (b *Bot) allowed(chatID int64) bool {
+	for _, id := range b.chatIDs {
+		if id == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bot) handleUpdate(ctx context.Context, u update) {
+	switch {
+	case u.Message != nil:
+		if !b.allowed(u.Message.Chat.ID) {
+			slog.Warn("Telegram: message from an unknown chat ignored", "chat", u.Message.Chat.ID)
+			return
+		}
+		b.handleCommand(ctx, u.Message)
+	case u.CallbackQuery != nil && u.CallbackQuery.Message != nil:
+		if !b.allowed(u.CallbackQuery.Message.Chat.ID) {
+			b.answer(ctx, u.CallbackQuery.ID, "Not allowed")
+			return
+		}
+		b.handleButton(ctx, u.CallbackQuery)
+	}
+}
+
+// handleCommand answers /status, /scan and /plan
+func (b *Bot) handleCommand(ctx context.Context, msg *message) {
+	var command string
+	if fields := strings.Fields(msg.Text); len(fields) > 0 {
+		// Commands in groups are addressed as /status@BotName
+		command, _, _ = strings.Cut(fields[0], "@")
+	}
+	chatID := msg.Chat.ID
+	switch command {
+	case "/status":
+		b.send(ctx, chatID, b.statusText())
+	case "/scan":
+		if jobID, err := b.scanManager.StartScan(); err != nil {
+			b.send(ctx, chatID, "Scan not started: "+html.EscapeString(err.Error()))
+		} else {
+			b.send(ctx, chatID, "Scan started (job <code>"+html.EscapeString(jobID)+"</code>). The summary follows when it finishes.")
+		}
+	case "/plan":
+		b.offerPlan(ctx, []int64{chatID}, true)
+	default:
+		b.send(ctx, chatID, "/status - scan state and duplicates\n/scan - scan all gallery folders\n/plan - plan the removal of duplicates to approve")
+	}
+}
+
+// handleButton carries out or dismisses the pending deletion plan
+func (b *Bot) handleButton(ctx context.Context, query *callbackQuery) {
+	action, id, _ := strings.Cut(query.Data, ":")
+
+	b.mu.Lock()
+	pending := b.pending
+	if pending == nil || pending.id != id || time.Since(pending.created) > planLifetime {
+		b.mu.Unlock()
+		b.answer(ctx, query.ID, "This plan is no longer available")
+		b.edit(ctx, *query.Message, "This deletion plan expired; send /plan for a new one.")
+		return
+	}
+	// Taken before running, so that a second press cannot run it again
+	b.pending = nil
+	b.mu.Unlock()
+
+	if action != "approve" {
+		b.answer(ctx, query.ID, "Dismissed")
+		b.retire(ctx, pending, "Deletion plan dismissed.")
+		return
+	}
+	b.answer(ctx, query.ID, "Deleting...")
+	b.retire(ctx, pending, b.execute(pending.plan))
+}
+
+// execute carries out a plan and describes the outcome
+func (b *Bot) execute(plan *imaging.DeletionPlan) string {
+	var settings domain.AppSettings
+	if err := b.db.First(&settings, 1).Error; err != nil || settings.TrashDir == "" {
+		return "No trash folder is set in the settings. Plans move files there so that they can be restored; set one and send /plan again."
+	}
+	result, err := imaging.ExecuteDeletionPlan(b.db, plan, settings.TrashDir, b.scanManager.GalleryPathGuard())
+	if err != nil {
+		slog.Error("Telegram: deletion plan failed", "error", err)
+		return "Deletion failed: " + html.EscapeString(err.Error())
+	}
+	slog.Info("Telegram: deletion plan carried out", "deleted", result.Deleted, "failed", result.Failed, "batch", result.BatchID)
+	b.scanManager.Events.Publish(events.TypeFilesDeleted, dto.BatchDeleteResponse{
+		Success:     result.Deleted,
+		Failed:      result.Failed,
+		FailedFiles: result.FailedFiles,
+		BatchID:     result.BatchID,
+		FreedBytes:  result.FreedBytes,
+	})
+
+	text := fmt.Sprintf("<b>Deleted %d files</b>, %s freed. They are in the trash folder and can be restored from Recent deletions.",
+		result.Deleted, imaging.FormatSize(result.FreedBytes))
+	if result.Failed > 0 {
+		text += fmt.Sprintf("\n%d files were skipped:", result.Failed)
+		for i, f := range result.FailedFiles {
+			if i == planSampleGroups {
+				text += "\n..."
+				break
+			}
+			text += "\n" + html.EscapeString(f)
+		}
+	}
+	return text
+}
+
+// reportScan sends the summary of a finished scan, then a deletion plan if
+// duplicates are left
+func (b *Bot) reportScan(ctx context.Context, summary imaging.ScanSummary) {
+	for _, chatID := range b.chatIDs {
+		b.send(ctx, chatID, summaryText(summary))
+	}
+	if summary.Run.Status == domain.ScanRunCompleted {
+		b.offerPlan(ctx, b.chatIDs, false)
+	}
+}
+
+// offerPlan computes a deletion plan and sends it with approve and dismiss buttons,
+// replacing the plan offered before. With reportEmpty, an empty plan is reported too.
+func (b *Bot) offerPlan(ctx context.Context, chatIDs []int64, reportEmpty bool) {
+	plan, err := imaging.PlanDeletion(b.db, b.scanManager.Options().DuplicateFilter(imaging.MediaAll), b.scanManager.ResolveKeepRules(b.keepRules))
+	if err != nil {
+		slog.Error("Telegram: failed to plan deletion", "error", err)
+		return
+	}
+	if plan.Files == 0 {
+		if reportEmpty {
+			for _, chatID := range chatIDs {
+				b.send(ctx, chatID, "Nothing to delete: no duplicates left apart from pinned files.")
+			}
+		}
+		return
+	}
+
+	pending := &pendingPlan{id: newPlanID(), plan: plan, created: time.Now()}
+	keyboard := &inlineKeyboard{InlineKeyboard: [][]inlineButton{{
+		{Text: fmt.Sprintf("Delete %d files (%s)", plan.Files, imaging.FormatSize(plan.ReclaimableBytes)), CallbackData: "approve:" + pending.id},
+		{Text: "Dismiss", CallbackData: "dismiss:" + pending.id},
+	}}}
+	text := b.planText(plan)
+	for _, chatID := range chatIDs {
+		sent, err := b.api.sendMessage(ctx, chatID, text, keyboard)
+		if err != nil {
+			slog.Warn("Telegram: failed to send deletion plan", "chat", chatID, "error", err)
+			continue
+		}
+		pending.messages = append(pending.messages, sent)
+	}
+
+	b.mu.Lock()
+	previous := b.pending
+	b.pending = pending
+	b.mu.Unlock()
+	if previous != nil {
+		b.retire(ctx, previous, "Replaced by a newer deletion plan.")
+	}
+}
+
+// retire replaces the messages offering a plan, removing their buttons
+func (b *Bot) retire(ctx context.Context, pending *pendingPlan, text string) {
+	for _, msg := range pending.messages {
+		b.edit(ctx, msg, text)
+	}
+}
+
+func (b *Bot) send(ctx context.Context, chatID int64, text string) {
+	if _, err := b.api.sendMessage(ctx, chatID, text, nil); err != nil {
+		slog.Warn("Telegram: failed to send message", "chat", chatID, "error", err)
+	}
+}
+
+func (b *Bot) edit(ctx context.Context, msg message, text string) {
+	if err := b.api.editMessage(ctx, msg.Chat.ID, msg.MessageID, text); err != nil {
+		slog.Warn("Telegram: failed to edit message", "chat", msg.Chat.ID, "error", err)
+	}
+}
+
+func (b *Bot) answer(ctx context.Context, queryID, text string) {
+	if err := b.api.answerCallback(ctx, queryID, text); err != nil {
+		slog.Warn("Telegram: failed to answer button", "error", err)
+	}
+}
+
+// statusText describes the scan state and the duplicates in the index
+func (b *Bot) statusText() string {
+	status := b.scanManager.GetStatus()
+	text := "No scan is running."
+	if status.Scanning {
+		text = "Scanning: " + html.EscapeString(status.Progress)
+	}
+	totals, err := imaging.CountDuplicates(b.db, b.scanManager.Options().DuplicateFilter(imaging.MediaAll))
+	if err != nil {
+		slog.Error("Telegram: failed to count duplicates", "error", err)
+		return text
+	}
+	return text + fmt.Sprintf("\n%d duplicate groups of %d files, %s reclaimable.", totals.Groups, totals.Files, imaging.FormatSize(totals.ReclaimableBytes))
+}
+
+// summaryText describes a finished scan
+func summaryText(summary imaging.ScanSummary) string {
+	run := summary.Run
+	text := fmt.Sprintf("<b>Scan %s</b> (%s, %s)\nAdded %d, updated %d, removed %d, unreadable %d",
+		html.EscapeString(run.Status), html.EscapeString(run.Kind), html.EscapeString(run.Trigger),
+		run.FilesAdded, run.FilesUpdated, run.FilesRemoved, run.Errors)
+	if run.Error != "" {
+		text += "\nError: " + html.EscapeString(run.Error)
+	}
+	text += fmt.Sprintf("\nNew duplicate groups: %d (%s)\nAll duplicates: %d groups, %s reclaimable",
+		len(summary.NewGroups), imaging.FormatSize(summary.NewGroupsReclaimableBytes()),
+		summary.Duplicates.Groups, imaging.FormatSize(summary.Duplicates.ReclaimableBytes))
+	return text
+}
+
+// planText describes a deletion plan with a few of its groups
+func (b *Bot) planText(plan *imaging.DeletionPlan) string {
+	policies := make([]string, len(b.keepRules))
+	for i, rule := range b.keepRules {
+		policies[i] = string(rule.Policy)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<b>Deletion plan</b>: remove %d files in %d groups, freeing %s (%s). Files are moved to the trash folder.",
+		plan.Files, len(plan.Groups), imaging.FormatSize(plan.ReclaimableBytes), strings.Join(policies, ", "))
+	for i, group := range plan.Groups {
+		if i == planSampleGroups {
+			fmt.Fprintf(&sb, "\n\n...and %d more groups", len(plan.Groups)-planSampleGroups)
+			break
+		}
+		fmt.Fprintf(&sb, "\n\nkeep <code>%s</code>", html.EscapeString(group.Keep))
+		for j, path := range group.Remove {
+			if j == planSampleFiles {
+				fmt.Fprintf(&sb, "\n...and %d more", len(group.Remove)-planSampleFiles)
+				break
+			}
+			fmt.Fprintf(&sb, "\nremove <code>%s</code>", html.EscapeString(path))
+		}
+	}
+	return sb.String()
+}
+
+// newPlanID returns a random identifier for the buttons of a plan
+func newPlanID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
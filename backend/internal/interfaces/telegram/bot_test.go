@@ -0,0 +1,157 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"image-toolkit/internal/application/events"
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+)
+
+// fakeAPI records the Bot API calls of a test
+type fakeAPI struct {
+	mu    sync.Mutex
+	calls []apiCall
+}
+
+type apiCall struct {
+	method string
+	params map[string]any
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params map[string]any
+	json.NewDecoder(r.Body).Decode(&params)
+	f.mu.Lock()
+	f.calls = append(f.calls, apiCall{method: r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], params: params})
+	id := len(f.calls)
+	f.mu.Unlock()
+	json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"message_id": id, "chat": map[string]any{"id": params["chat_id"]}}})
+}
+
+// take returns and clears the recorded calls
+func (f *fakeAPI) take() []apiCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := f.calls
+	f.calls = nil
+	return calls
+}
+
+func TestApprovePlan(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.ImageFile{}, &domain.Deletion{}, &domain.AppSettings{}, &domain.GalleryFolder{},
+		&domain.IgnoredGroup{}, &domain.GroupReview{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	dir := t.TempDir()
+	gallery := filepath.ToSlash(dir)
+	trash := filepath.Join(dir, ".trash")
+	db.Create(&domain.GalleryFolder{Path: gallery})
+	db.Create(&domain.AppSettings{ID: 1, TrashDir: trash})
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"a.jpg", "a-copy.jpg"} {
+		path := gallery + "/" + name
+		os.WriteFile(filepath.FromSlash(path), []byte("same"), 0644)
+		db.Create(&domain.ImageFile{Path: path, Hash: "h", Size: 4, ModTime: old.Add(time.Duration(i) * time.Hour)})
+	}
+
+	fake := &fakeAPI{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	scanManager := imaging.NewScanManager(db, imaging.ScanOptions{})
+	scanManager.Events = events.NewBus()
+	deleted, unsubscribe := scanManager.Events.Subscribe()
+	defer unsubscribe()
+
+	bot, err := NewBot("token", []string{"42"}, nil, db, scanManager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot.api = newAPI(server.URL)
+	ctx := context.Background()
+
+	// Other chats are ignored
+	bot.handleUpdate(ctx, update{Message: &message{Chat: chat{ID: 7}, Text: "/plan"}})
+	if calls := fake.take(); len(calls) != 0 {
+		t.Fatalf("answered an unknown chat: %+v", calls)
+	}
+
+	bot.handleUpdate(ctx, update{Message: &message{Chat: chat{ID: 42}, Text: "/plan@DedupBot"}})
+	calls := fake.take()
+	if len(calls) != 1 || calls[0].method != "sendMessage" || !strings.Contains(calls[0].params["text"].(string), "remove <code>"+gallery+"/a-copy.jpg</code>") {
+		t.Fatalf("plan message = %+v", calls)
+	}
+	keyboard := calls[0].params["reply_markup"].(map[string]any)["inline_keyboard"].([]any)[0].([]any)
+	approve := keyboard[0].(map[string]any)["callback_data"].(string)
+
+	press := func(data string) {
+		bot.handleUpdate(ctx, update{CallbackQuery: &callbackQuery{ID: "q", Data: data, Message: &message{MessageID: 1, Chat: chat{ID: 42}}}})
+	}
+	press(approve)
+	calls = fake.take()
+	var edited string
+	for _, c := range calls {
+		if c.method == "editMessageText" {
+			edited = c.params["text"].(string)
+		}
+	}
+	if !strings.Contains(edited, "Deleted 1 files") {
+		t.Errorf("result message = %q", edited)
+	}
+	if _, err := os.Stat(filepath.Join(trash, "a-copy.jpg")); err != nil {
+		t.Errorf("the copy was not moved to the trash folder: %v", err)
+	}
+	select {
+	case event := <-deleted:
+		if event.Type != events.TypeFilesDeleted {
+			t.Errorf("event = %s", event.Type)
+		}
+	default:
+		t.Error("no files.deleted event")
+	}
+
+	// A plan runs once
+	press(approve)
+	calls = fake.take()
+	if len(calls) != 2 || calls[0].params["text"] != "This plan is no longer available" {
+		t.Errorf("second press = %+v", calls)
+	}
+}
+
+func TestNewBot(t *testing.T) {
+	for name, args := range map[string][]string{
+		"no token":    {"", "1", "keep-oldest"},
+		"no chats":    {"token", "", "keep-oldest"},
+		"bad chat":    {"token", "me", "keep-oldest"},
+		"bad policy":  {"token", "1", "keep-best"},
+		"needs dirs":  {"token", "1", "keep-in-priority-directory"},
+		"valid chats": {"token", "1,-100200", ""},
+	} {
+		split := func(s string) []string {
+			if s == "" {
+				return nil
+			}
+			return strings.Split(s, ",")
+		}
+		_, err := NewBot(args[0], split(args[1]), split(args[2]), nil, nil)
+		if (err == nil) != (name == "valid chats") {
+			t.Errorf("%s: NewBot error = %v", name, err)
+		}
+	}
+}
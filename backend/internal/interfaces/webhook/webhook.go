@@ -0,0 +1,235 @@
+// Package webhook POSTs the results of scans and batch deletes to configured URLs,
+// for chat channels and home automation
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/interfaces/dto"
+
+	"gorm.io/gorm"
+)
+
+// Events of the payloads, sent in the X-Dedup-Event header as well
+const (
+	EventScanFinished   = "scan.finished"
+	EventDeleteFinished = "delete.finished"
+)
+
+// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the body keyed with
+// the secret, when one is configured
+const SignatureHeader = "X-Dedup-Signature"
+
+const (
+	// maxAttempts bounds the deliveries per URL; retries wait 2s, then 4s
+	maxAttempts = 3
+	// requestTimeout bounds each delivery
+	requestTimeout = 10 * time.Second
+)
+
+// Payload is the JSON body of a webhook call. Text is repeated as Content so that
+// Slack (text) and Discord (content) incoming webhooks accept the body as is.
+type Payload struct {
+	Event      string                  `json:"event"`
+	Time       time.Time               `json:"time"`
+	Text       string                  `json:"text"`
+	Content    string                  `json:"content"`
+	Scan       *ScanResult             `json:"scan,omitempty"`
+	Delete     *DeleteResult           `json:"delete,omitempty"`
+	Duplicates imaging.DuplicateTotals `json:"duplicates"` // left in the index
+}
+
+// ScanResult describes a finished scan
+type ScanResult struct {
+	JobID          string     `json:"jobId"`
+	Kind           string     `json:"kind"`
+	Trigger        string     `json:"trigger"` // manual or scheduled
+	Status         string     `json:"status"`  // completed, failed or cancelled
+	Error          string     `json:"error,omitempty"`
+	FilesProcessed int        `json:"filesProcessed"`
+	FilesAdded     int        `json:"filesAdded"`
+	FilesUpdated   int        `json:"filesUpdated"`
+	FilesRemoved   int        `json:"filesRemoved"`
+	Errors         int        `json:"errors"`
+	NewGroups      int        `json:"newGroups"`
+	NewBytes       int64      `json:"newReclaimableBytes"` // freed by keeping one file of every new group
+	StartedAt      time.Time  `json:"startedAt"`
+	FinishedAt     *time.Time `json:"finishedAt,omitempty"`
+}
+
+// DeleteResult describes a finished batch delete
+type DeleteResult struct {
+	Deleted    int    `json:"deleted"`
+	Failed     int    `json:"failed"`
+	Protected  int    `json:"protected"` // pinned files left in place
+	FreedBytes int64  `json:"freedBytes"`
+	BatchID    string `json:"batchId,omitempty"` // for restoring the files from the trash folder
+}
+
+// Notifier delivers the webhooks of the events published by a scan manager
+type Notifier struct {
+	urls        []string
+	secret      string
+	db          *gorm.DB
+	scanManager *imaging.ScanManager
+	client      *http.Client
+	retryDelay  time.Duration
+}
+
+// NewNotifier creates a notifier for http and https URLs
+func NewNotifier(urls []string, secret string, db *gorm.DB, scanManager *imaging.ScanManager) (*Notifier, error) {
+	for _, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q", u)
+		}
+	}
+	return &Notifier{
+		urls:        urls,
+		secret:      secret,
+		db:          db,
+		scanManager: scanManager,
+		client:      &http.Client{Timeout: requestTimeout},
+		retryDelay:  2 * time.Second,
+	}, nil
+}
+
+// Start delivers webhooks in the background until stop is called
+func (n *Notifier) Start() (stop func()) {
+	ch, unsubscribe := n.scanManager.Events.Subscribe()
+	go func() {
+		for event := range ch {
+			switch data := event.Data.(type) {
+			case imaging.ScanSummary:
+				go n.Send(scanPayload(data, event.Time))
+			case dto.BatchDeleteResponse:
+				// Counted off the event loop, which must keep up with scan status events
+				go func() {
+					totals, err := imaging.CountDuplicates(n.db, n.scanManager.Options().DuplicateFilter(imaging.MediaAll))
+					if err != nil {
+						slog.Error("Webhook: failed to count duplicates", "error", err)
+					}
+					n.Send(deletePayload(data, totals, event.Time))
+				}()
+			}
+		}
+	}()
+	return unsubscribe
+}
+
+// Send posts a payload to every URL, retrying failed deliveries
+func (n *Notifier) Send(payload Payload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("Webhook: failed to encode payload", "event", payload.Event, "error", err)
+		return
+	}
+	for _, u := range n.urls {
+		delay := n.retryDelay
+		for attempt := 1; ; attempt++ {
+			retry, err := n.post(u, payload.Event, body)
+			if err == nil {
+				break
+			}
+			if !retry || attempt == maxAttempts {
+				slog.Warn("Webhook delivery failed", "url", redact(u), "event", payload.Event, "attempts", attempt, "error", err)
+				break
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+}
+
+// post delivers the body once. Network errors, 429 and 5xx responses are worth a retry.
+func (n *Notifier) post(u, event string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "image-dedup-webhook")
+	req.Header.Set("X-Dedup-Event", event)
+	if n.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("status %s", resp.Status)
+}
+
+// Sign returns the signature header value of a body: "sha256=" and the hex
+// HMAC-SHA256 keyed with the secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// redact drops the path and query of a URL for logs, as chat webhooks keep their
+// credentials there
+func redact(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// scanPayload builds the payload of a finished scan
+func scanPayload(summary imaging.ScanSummary, at time.Time) Payload {
+	run := summary.Run
+	scan := &ScanResult{
+		JobID:          summary.JobID,
+		Kind:           run.Kind,
+		Trigger:        run.Trigger,
+		Status:         run.Status,
+		Error:          run.Error,
+		FilesProcessed: run.FilesProcessed,
+		FilesAdded:     run.FilesAdded,
+		FilesUpdated:   run.FilesUpdated,
+		FilesRemoved:   run.FilesRemoved,
+		Errors:         run.Errors,
+		NewGroups:      len(summary.NewGroups),
+		NewBytes:       summary.NewGroupsReclaimableBytes(),
+		StartedAt:      run.StartedAt,
+		FinishedAt:     run.FinishedAt,
+	}
+	text := fmt.Sprintf("Scan %s: %d added, %d updated, %d removed, %d errors; %d new duplicate groups. %d groups in total, %s reclaimable.",
+		scan.Status, scan.FilesAdded, scan.FilesUpdated, scan.FilesRemoved, scan.Errors, scan.NewGroups,
+		summary.Duplicates.Groups, imaging.FormatSize(summary.Duplicates.ReclaimableBytes))
+	return Payload{Event: EventScanFinished, Time: at, Text: text, Content: text, Scan: scan, Duplicates: summary.Duplicates}
+}
+
+// deletePayload builds the payload of a finished batch delete
+func deletePayload(resp dto.BatchDeleteResponse, totals imaging.DuplicateTotals, at time.Time) Payload {
+	result := &DeleteResult{
+		Deleted:    resp.Success,
+		Failed:     resp.Failed,
+		Protected:  len(resp.ProtectedFiles),
+		FreedBytes: resp.FreedBytes,
+		BatchID:    resp.BatchID,
+	}
+	text := fmt.Sprintf("Batch delete: %d files removed (%s freed), %d failed. %d duplicate groups left, %s reclaimable.",
+		result.Deleted, imaging.FormatSize(result.FreedBytes), result.Failed, totals.Groups, imaging.FormatSize(totals.ReclaimableBytes))
+	return Payload{Event: EventDeleteFinished, Time: at, Text: text, Content: text, Delete: result, Duplicates: totals}
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"image-toolkit/internal/application/imaging"
+	"image-toolkit/internal/domain"
+	"image-toolkit/internal/interfaces/dto"
+)
+
+func TestSend(t *testing.T) {
+	var calls atomic.Int32
+	var got Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first delivery fails and is retried
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if sig := r.Header.Get(SignatureHeader); sig != Sign("s3cret", body) {
+			t.Errorf("signature = %q, want %q", sig, Sign("s3cret", body))
+		}
+		if event := r.Header.Get("X-Dedup-Event"); event != EventScanFinished {
+			t.Errorf("event header = %q", event)
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	n, err := NewNotifier([]string{server.URL + "/hook"}, "s3cret", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.retryDelay = time.Millisecond
+
+	summary := imaging.ScanSummary{
+		JobID: "job-1",
+		Run: domain.ScanRun{
+			Kind: "scan", Trigger: domain.ScanTriggerScheduled, Status: domain.ScanRunCompleted,
+			FilesAdded: 3, FilesRemoved: 1, Errors: 2,
+		},
+		NewGroups:  []imaging.NewDuplicateGroup{{Hash: "h", Size: 100, Paths: []string{"/a", "/b", "/c"}}},
+		Duplicates: imaging.DuplicateTotals{Groups: 4, Files: 9, Bytes: 5000, ReclaimableBytes: 2048},
+	}
+	n.Send(scanPayload(summary, time.Now()))
+
+	if calls.Load() != 2 {
+		t.Fatalf("deliveries = %d, want 2", calls.Load())
+	}
+	if got.Scan == nil || got.Scan.JobID != "job-1" || got.Scan.FilesAdded != 3 || got.Scan.NewGroups != 1 || got.Scan.NewBytes != 200 {
+		t.Errorf("scan = %+v", got.Scan)
+	}
+	if got.Duplicates.ReclaimableBytes != 2048 || got.Text == "" || got.Text != got.Content {
+		t.Errorf("payload = %+v", got)
+	}
+	if !strings.Contains(got.Text, "2.0 KB reclaimable") {
+		t.Errorf("text = %q", got.Text)
+	}
+}
+
+func TestSendGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get(SignatureHeader) != "" {
+			t.Error("unsigned webhooks carry a signature")
+		}
+		if strings.HasSuffix(r.URL.Path, "/gone") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	n, err := NewNotifier([]string{server.URL + "/gone", server.URL + "/down"}, "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.retryDelay = time.Millisecond
+	n.Send(deletePayload(dto.BatchDeleteResponse{Success: 2, FreedBytes: 10}, imaging.DuplicateTotals{}, time.Now()))
+
+	// 404 is not retried, 503 is retried up to maxAttempts
+	if calls.Load() != 1+maxAttempts {
+		t.Errorf("deliveries = %d, want %d", calls.Load(), 1+maxAttempts)
+	}
+}
+
+func TestNewNotifierRejectsInvalidURLs(t *testing.T) {
+	for _, u := range []string{"ftp://host/hook", "not a url", "https://"} {
+		if _, err := NewNotifier([]string{u}, "", nil, nil); err == nil {
+			t.Errorf("NewNotifier(%q) succeeded", u)
+		}
+	}
+}
@@ -7,6 +7,14 @@ import { SettingsTab } from "@/components/tabs/SettingsTab"
 import { GalleryTab } from "@/components/tabs/GalleryTab"
 import { DeduplicationTab } from "@/components/tabs/DeduplicationTab"
 import { OcrTab } from "@/components/tabs/OcrTab"
+import { DeletionsTab } from "@/components/tabs/DeletionsTab"
+import { StatsTab } from "@/components/tabs/StatsTab"
+import { ScanHistoryTab } from "@/components/tabs/ScanHistoryTab"
+import { IgnoredGroupsTab } from "@/components/tabs/IgnoredGroupsTab"
+import { CorruptFilesTab } from "@/components/tabs/CorruptFilesTab"
+import { NameConflictsTab } from "@/components/tabs/NameConflictsTab"
+import { NameCopiesTab } from "@/components/tabs/NameCopiesTab"
+import { TimelineTab } from "@/components/tabs/TimelineTab"
 import { AdminSettingsTab } from "@/components/tabs/AdminSettingsTab"
 import { fetchFolders } from "@/api/endpoints"
 import { useTranslation } from "@/i18n"
@@ -17,7 +25,7 @@ import { BootstrapSetupScreen } from "@/components/auth/BootstrapSetupScreen"
 import { UserProfile } from "@/components/auth/UserProfile"
 import { AdminPanel } from "@/components/auth/AdminPanel"
 
-type TabValue = "settings" | "gallery-folders" | "gallery-calendar" | "deduplication" | "ocr" | "profile" | "admin-settings" | "admin-users"
+type TabValue = "settings" | "gallery-folders" | "gallery-calendar" | "deduplication" | "videos" | "ocr" | "stats" | "timeline" | "scan-history" | "ignored-groups" | "name-copies" | "corrupt-files" | "name-conflicts" | "deletions" | "profile" | "admin-settings" | "admin-users"
 
 export default function App() {
   const [activeTab, setActiveTab] = useState<TabValue>("gallery-folders")
@@ -122,10 +130,46 @@ export default function App() {
                 <DeduplicationTab />
               </TabsContent>
 
+              <TabsContent value="videos">
+                <DeduplicationTab media="video" />
+              </TabsContent>
+
               <TabsContent value="ocr">
                 <OcrTab />
               </TabsContent>
 
+              <TabsContent value="stats">
+                <StatsTab />
+              </TabsContent>
+
+              <TabsContent value="timeline">
+                <TimelineTab />
+              </TabsContent>
+
+              <TabsContent value="scan-history">
+                <ScanHistoryTab />
+              </TabsContent>
+
+              <TabsContent value="ignored-groups">
+                <IgnoredGroupsTab />
+              </TabsContent>
+
+              <TabsContent value="name-copies">
+                <NameCopiesTab />
+              </TabsContent>
+
+              <TabsContent value="corrupt-files">
+                <CorruptFilesTab />
+              </TabsContent>
+
+              <TabsContent value="name-conflicts">
+                <NameConflictsTab />
+              </TabsContent>
+
+              <TabsContent value="deletions">
+                <DeletionsTab />
+              </TabsContent>
+
               <TabsContent value="admin-users">
                 {user?.role === "admin" ? <AdminPanel /> : (
                   <div className="flex items-center justify-center py-20">
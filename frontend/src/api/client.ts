@@ -4,6 +4,18 @@ import { translations } from "../i18n/translations"
 // Global i18n registry for API calls outside React components
 let globalTranslate: ((key: string) => string) | null = null
 
+// webSocketUrl builds a ws:// or wss:// URL for an API path, honouring VITE_API_URL
+export function webSocketUrl(path: string): string {
+  const url = new URL(`${API_BASE_URL}${path}`, window.location.origin)
+  url.protocol = url.protocol === "https:" ? "wss:" : "ws:"
+  return url.toString()
+}
+
+// apiUrl builds an absolute URL for an API path, honouring VITE_API_URL (for plain links and downloads)
+export function apiUrl(path: string): string {
+  return new URL(`${API_BASE_URL}${path}`, window.location.origin).toString()
+}
+
 export function setGlobalTranslate(fn: (key: string) => string) {
   globalTranslate = fn
 }
@@ -84,6 +96,29 @@ export async function apiPost<T>(path: string, body?: unknown): Promise<T> {
   return data as T
 }
 
+// apiPostDownload posts a JSON body and returns the attachment the server responds
+// with, together with its file name from Content-Disposition
+export async function apiPostDownload(path: string, body: unknown): Promise<{ blob: Blob; filename: string }> {
+  const response = await fetch(`${API_BASE_URL}${path}`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    credentials: "include",
+    body: JSON.stringify(body),
+  })
+
+  if (!response.ok) {
+    if (response.status === 401) {
+      handleUnauthorized()
+    }
+    const data = await response.json()
+    throw new Error(translateApiMessage(data.error || data.message))
+  }
+
+  const disposition = response.headers.get("Content-Disposition") ?? ""
+  const filename = /filename="([^"]+)"/.exec(disposition)?.[1] ?? "download"
+  return { blob: await response.blob(), filename }
+}
+
 export async function apiDelete<T>(path: string): Promise<T> {
   const response = await fetch(`${API_BASE_URL}${path}`, {
     method: "DELETE",
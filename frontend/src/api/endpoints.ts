@@ -1,15 +1,40 @@
-import { apiGet, apiPost, apiDelete, apiPut, apiPatch } from "./client"
+import { apiGet, apiPost, apiPostDownload, apiDelete, apiPut, apiPatch, apiUrl } from "./client"
 import type {
   DuplicatesResponse,
+  DuplicateMode,
+  DuplicateMedia,
+  DuplicateSort,
+  DuplicateFilters,
+  GroupResponse,
+  HostsResponse,
+  StatsResponse,
   ScanResponse,
+  ScanJobDTO,
   FastScanResponse,
   ScanStatusResponse,
-  ThumbnailResponse,
+  ScheduleDTO,
+  UpdateScheduleRequest,
+  ScanRunsResponse,
   DeleteFilesRequest,
   DeleteFilesResponse,
   FolderPatternsResponse,
   BatchDeleteRequest,
   BatchDeleteResponse,
+  ConsolidateRequest,
+  GenerateScriptRequest,
+  ConsolidateResponse,
+  SetProtectedResponse,
+  AutoSelectRequest,
+  AutoSelectResponse,
+  SelectionItemDTO,
+  IgnoredGroupDTO,
+  IgnoredGroupsResponse,
+  CorruptFilesResponse,
+  NameConflictsResponse,
+  TimelineResponse,
+  ReviewFilter,
+  ReviewGroupResponse,
+  SelectionResponse,
   GalleryFoldersResponse,
   AddFolderRequest,
   AddFolderResponse,
@@ -22,6 +47,8 @@ import type {
   UpdateUserSettingsRequest,
   TrashInfoResponse,
   CleanTrashResponse,
+  DeletionsResponse,
+  RestoreResponse,
   ImageMetadataResponse,
   AuthStatusResponse,
   LoginRequest,
@@ -49,53 +76,214 @@ import type {
   WarmupThumbnailsRequest,
 } from "@/types"
 
-export function fetchDuplicates(page: number, pageSize: number): Promise<DuplicatesResponse> {
-  return apiGet<DuplicatesResponse>("/api/duplicates", {
+export function fetchDuplicates(
+  page: number,
+  pageSize: number,
+  mode: DuplicateMode = "exact",
+  threshold?: number,
+  media: DuplicateMedia = "image",
+  sort?: DuplicateSort,
+  filters: DuplicateFilters = {},
+): Promise<DuplicatesResponse> {
+  const params: Record<string, string> = {
     page: String(page),
     pageSize: String(pageSize),
-  })
+    mode,
+    media,
+  }
+  if (threshold !== undefined) {
+    params.threshold = String(threshold)
+  }
+  if (sort) {
+    params.sort = sort
+  }
+  for (const [key, value] of Object.entries(filters)) {
+    if (value) {
+      params[key] = value
+    }
+  }
+  return apiGet<DuplicatesResponse>("/api/v1/duplicates", params)
+}
+
+// fetchGroup returns all files of the exact duplicate group with the content hash
+export function fetchGroup(hash: string): Promise<GroupResponse> {
+  return apiGet<GroupResponse>(`/api/v1/groups/${encodeURIComponent(hash)}`)
+}
+
+// ignoreGroup marks the exact duplicate group with the content hash as not duplicates
+export function ignoreGroup(hash: string): Promise<IgnoredGroupDTO> {
+  return apiPost<IgnoredGroupDTO>(`/api/v1/groups/${encodeURIComponent(hash)}/ignore`)
+}
+
+// reviewGroup marks the exact duplicate group reviewed or deferred; "unreviewed" clears the mark
+export function reviewGroup(hash: string, status: ReviewFilter): Promise<ReviewGroupResponse> {
+  return apiPut<ReviewGroupResponse>(`/api/v1/groups/${encodeURIComponent(hash)}/review`, { status })
+}
+
+// fetchHosts lists the machines with indexed files: this server and the agents
+export function fetchHosts(): Promise<HostsResponse> {
+  return apiGet<HostsResponse>("/api/v1/hosts")
+}
+
+export function fetchIgnoredGroups(): Promise<IgnoredGroupsResponse> {
+  return apiGet<IgnoredGroupsResponse>("/api/v1/ignored-groups")
+}
+
+// fetchCorruptFiles lists the indexed files that are empty or fail to decode
+export function fetchCorruptFiles(limit = 500): Promise<CorruptFilesResponse> {
+  return apiGet<CorruptFilesResponse>(`/api/v1/corrupt-files?limit=${limit}`)
+}
+
+// fetchNameConflicts lists the file names shared by files of different content, under dir if set
+export function fetchNameConflicts(dir = "", limit = 500): Promise<NameConflictsResponse> {
+  const params = new URLSearchParams({ limit: String(limit) })
+  if (dir) {
+    params.set("dir", dir)
+  }
+  return apiGet<NameConflictsResponse>(`/api/v1/name-conflicts?${params}`)
+}
+
+// unignoreGroup lists an ignored group among the duplicates again
+export function unignoreGroup(id: number): Promise<IgnoredGroupsResponse> {
+  return apiDelete<IgnoredGroupsResponse>(`/api/v1/ignored-groups/${id}`)
+}
+
+// fetchTimeline counts the exact duplicate groups per month they were shot in
+export function fetchTimeline(): Promise<TimelineResponse> {
+  return apiGet<TimelineResponse>("/api/v1/timeline")
+}
+
+// fetchStats returns the wasted-space statistics of the exact duplicates
+export function fetchStats(): Promise<StatsResponse> {
+  return apiGet<StatsResponse>("/api/v1/stats")
+}
+
+// triggerScan starts a scan of all gallery folders, or only of directory when given
+export function triggerScan(directory?: string): Promise<ScanResponse> {
+  const query = directory ? `?directory=${encodeURIComponent(directory)}` : ""
+  return apiPost<ScanResponse>(`/api/v1/scan${query}`)
+}
+
+// duplicatesCsvUrl is the download URL of the CSV export of all duplicate groups
+export function duplicatesCsvUrl(): string {
+  return apiUrl("/api/v1/export/csv")
+}
+
+// duplicatesHtmlUrl is the download URL of the standalone HTML duplicate report
+export function duplicatesHtmlUrl(): string {
+  return apiUrl("/api/v1/export/html")
 }
 
-export function triggerScan(): Promise<ScanResponse> {
-  return apiPost<ScanResponse>("/api/scan")
+export function fetchScanJob(id: string): Promise<ScanJobDTO> {
+  return apiGet<ScanJobDTO>(`/api/v1/scan/jobs/${encodeURIComponent(id)}`)
+}
+
+export function cancelScan(): Promise<ScanResponse> {
+  return apiPost<ScanResponse>("/api/v1/scan/cancel")
+}
+
+export function pauseScan(): Promise<ScanResponse> {
+  return apiPost<ScanResponse>("/api/v1/scan/pause")
+}
+
+export function resumeScan(): Promise<ScanResponse> {
+  return apiPost<ScanResponse>("/api/v1/scan/resume")
 }
 
 export function triggerFastScan(): Promise<FastScanResponse> {
-  return apiPost<FastScanResponse>("/api/fast-scan")
+  return apiPost<FastScanResponse>("/api/v1/fast-scan")
+}
+
+export function triggerRehash(): Promise<ScanResponse> {
+  return apiPost<ScanResponse>("/api/v1/rehash")
+}
+
+export function fetchSchedule(): Promise<ScheduleDTO> {
+  return apiGet<ScheduleDTO>("/api/v1/schedule")
+}
+
+export function updateSchedule(req: UpdateScheduleRequest): Promise<ScheduleDTO> {
+  return apiPut<ScheduleDTO>("/api/v1/schedule", req)
+}
+
+export function fetchScanRuns(limit = 20): Promise<ScanRunsResponse> {
+  return apiGet<ScanRunsResponse>("/api/v1/scan-runs", { limit: String(limit) })
 }
 
 export function fetchScanStatus(): Promise<ScanStatusResponse> {
-  return apiGet<ScanStatusResponse>("/api/status")
+  return apiGet<ScanStatusResponse>("/api/v1/status")
+}
+
+// thumbnailSrc resolves a thumbnail URL from a JSON response for an <img> element
+export function thumbnailSrc(thumbnail: string): string {
+  return apiUrl(thumbnail)
 }
 
-export function fetchThumbnail(path: string): Promise<ThumbnailResponse> {
-  return apiGet<ThumbnailResponse>("/api/thumbnail", { path })
+// imageSrc is the URL of the full-size image, for large previews
+export function imageSrc(path: string): string {
+  return apiUrl(`/api/v1/image?path=${encodeURIComponent(path)}`)
 }
 
 export function deleteFiles(req: DeleteFilesRequest): Promise<DeleteFilesResponse> {
-  return apiPost<DeleteFilesResponse>("/api/delete-files", req)
+  return apiPost<DeleteFilesResponse>("/api/v1/delete-files", req)
+}
+
+// setProtected pins files so delete requests leave them in place, or unpins them
+export function setProtected(filePaths: string[], isProtected: boolean): Promise<SetProtectedResponse> {
+  return apiPut<SetProtectedResponse>("/api/v1/files/protected", { filePaths, protected: isProtected })
+}
+
+// downloadScript returns a removal script for the files (or with restore, the script
+// moving them back from the trash) to run on the machine that holds them
+export function downloadScript(req: GenerateScriptRequest): Promise<{ blob: Blob; filename: string }> {
+  return apiPostDownload("/api/v1/generate-script", { ...req, download: true })
 }
 
 export function fetchFolderPatterns(): Promise<FolderPatternsResponse> {
-  return apiGet<FolderPatternsResponse>("/api/folder-patterns")
+  return apiGet<FolderPatternsResponse>("/api/v1/folder-patterns")
 }
 
 export function batchDelete(req: BatchDeleteRequest): Promise<BatchDeleteResponse> {
-  return apiPost<BatchDeleteResponse>("/api/batch-delete", req)
+  return apiPost<BatchDeleteResponse>("/api/v1/batch-delete", req)
+}
+
+// consolidate moves the kept file of each group into a library folder and removes the rest
+export function consolidate(req: ConsolidateRequest): Promise<ConsolidateResponse> {
+  return apiPost<ConsolidateResponse>("/api/v1/consolidate", req)
+}
+
+// autoSelect returns the files each keep policy would remove, without deleting anything
+export function autoSelect(req: AutoSelectRequest): Promise<AutoSelectResponse> {
+  return apiPost<AutoSelectResponse>("/api/v1/auto-select", req)
+}
+
+// Selection marks are stored per session, so they survive paging and reloads
+export function fetchSelection(): Promise<SelectionResponse> {
+  return apiGet<SelectionResponse>("/api/v1/selection")
+}
+
+export function updateSelection(items: SelectionItemDTO[]): Promise<SelectionResponse> {
+  return apiPut<SelectionResponse>("/api/v1/selection", { items })
+}
+
+// clearSelection unmarks the given paths, or every file when none are given
+export function clearSelection(paths: string[] = []): Promise<SelectionResponse> {
+  const query = paths.map((p) => "path=" + encodeURIComponent(p)).join("&")
+  return apiDelete<SelectionResponse>("/api/v1/selection" + (query ? "?" + query : ""))
 }
 
 // --- Gallery Folders ---
 
 export function fetchFolders(): Promise<GalleryFoldersResponse> {
-  return apiGet<GalleryFoldersResponse>("/api/folders")
+  return apiGet<GalleryFoldersResponse>("/api/v1/folders")
 }
 
 export function addFolder(req: AddFolderRequest): Promise<AddFolderResponse> {
-  return apiPost<AddFolderResponse>("/api/folders", req)
+  return apiPost<AddFolderResponse>("/api/v1/folders", req)
 }
 
 export function removeFolder(id: number): Promise<RemoveFolderResponse> {
-  return apiDelete<RemoveFolderResponse>(`/api/folders/${id}`)
+  return apiDelete<RemoveFolderResponse>(`/api/v1/folders/${id}`)
 }
 
 // --- Gallery Images ---
@@ -105,7 +293,7 @@ export function fetchGalleryImages(
   pageSize: number,
   view: string
 ): Promise<GalleryImagesResponse> {
-  return apiGet<GalleryImagesResponse>("/api/gallery", {
+  return apiGet<GalleryImagesResponse>("/api/v1/gallery", {
     page: String(page),
     pageSize: String(pageSize),
     view,
@@ -128,7 +316,7 @@ export function fetchGalleryCalendar(
   if (startDate) params.startDate = startDate
   if (endDate) params.endDate = endDate
   if (monthYear) params.monthYear = monthYear
-  return apiGet<GalleryCalendarResponse>("/api/gallery/calendar", params)
+  return apiGet<GalleryCalendarResponse>("/api/v1/gallery/calendar", params)
 }
 
 // --- Gallery Calendar Month Info (lightweight) ---
@@ -147,183 +335,191 @@ export interface CalendarMonthData {
 }
 
 export function fetchCalendarMonthInfo(monthYear: string): Promise<CalendarMonthData> {
-  return apiGet<CalendarMonthData>("/api/gallery/calendar/month", { monthYear })
+  return apiGet<CalendarMonthData>("/api/v1/gallery/calendar/month", { monthYear })
 }
 
 // --- App Settings ---
 
 export function fetchSettings(): Promise<AppSettingsDTO> {
-  return apiGet<AppSettingsDTO>("/api/settings")
+  return apiGet<AppSettingsDTO>("/api/v1/settings")
 }
 
 export function updateSettings(req: UpdateSettingsRequest): Promise<AppSettingsDTO> {
-  return apiPut<AppSettingsDTO>("/api/settings", req)
+  return apiPut<AppSettingsDTO>("/api/v1/settings", req)
 }
 
 // --- User Settings ---
 
 export function fetchUserSettings(): Promise<UserSettingsDTO> {
-  return apiGet<UserSettingsDTO>("/api/user-settings")
+  return apiGet<UserSettingsDTO>("/api/v1/user-settings")
 }
 
 export function updateUserSettings(req: UpdateUserSettingsRequest): Promise<UserSettingsDTO> {
-  return apiPut<UserSettingsDTO>("/api/user-settings", req)
+  return apiPut<UserSettingsDTO>("/api/v1/user-settings", req)
 }
 
 // --- Trash ---
 
 export function fetchTrashInfo(): Promise<TrashInfoResponse> {
-  return apiGet<TrashInfoResponse>("/api/trash-info")
+  return apiGet<TrashInfoResponse>("/api/v1/trash-info")
 }
 
 export function cleanTrash(): Promise<CleanTrashResponse> {
-  return apiPost<CleanTrashResponse>("/api/trash-clean")
+  return apiPost<CleanTrashResponse>("/api/v1/trash-clean")
+}
+
+export function fetchDeletions(limit = 20): Promise<DeletionsResponse> {
+  return apiGet<DeletionsResponse>("/api/v1/deletions", { limit: String(limit) })
+}
+
+export function restoreDeletion(batchId: string): Promise<RestoreResponse> {
+  return apiPost<RestoreResponse>(`/api/v1/restore/${encodeURIComponent(batchId)}`)
 }
 
 // --- Image Metadata ---
 
 export function fetchImageMetadata(path: string): Promise<ImageMetadataResponse> {
-  return apiGet<ImageMetadataResponse>("/api/image-metadata", { path })
+  return apiGet<ImageMetadataResponse>("/api/v1/image-metadata", { path })
 }
 
 // --- Auth ---
 
 export function fetchAuthStatus(): Promise<AuthStatusResponse> {
-  return apiGet<AuthStatusResponse>("/api/auth/status")
+  return apiGet<AuthStatusResponse>("/api/v1/auth/status")
 }
 
 export function login(req: LoginRequest): Promise<LoginResponse> {
-  return apiPost<LoginResponse>("/api/auth/login", req)
+  return apiPost<LoginResponse>("/api/v1/auth/login", req)
 }
 
 export function logout(): Promise<{ message: string }> {
-  return apiPost<{ message: string }>("/api/auth/logout")
+  return apiPost<{ message: string }>("/api/v1/auth/logout")
 }
 
 export function fetchCurrentUser(): Promise<{ user: import("@/types").UserDTO }> {
-  return apiGet<{ user: import("@/types").UserDTO }>("/api/auth/me")
+  return apiGet<{ user: import("@/types").UserDTO }>("/api/v1/auth/me")
 }
 
 export function changePassword(req: ChangePasswordRequest): Promise<ChangePasswordResponse> {
-  return apiPost<ChangePasswordResponse>("/api/auth/change-password", req)
+  return apiPost<ChangePasswordResponse>("/api/v1/auth/change-password", req)
 }
 
 export function bootstrapSetup(req: BootstrapSetupRequest): Promise<{ user: import("@/types").UserDTO; message: string }> {
-  return apiPost<{ user: import("@/types").UserDTO; message: string }>("/api/auth/bootstrap/setup", req)
+  return apiPost<{ user: import("@/types").UserDTO; message: string }>("/api/v1/auth/bootstrap/setup", req)
 }
 
 export function updateProfile(req: UpdateProfileRequest): Promise<{ user: import("@/types").UserDTO }> {
-  return apiPatch<{ user: import("@/types").UserDTO }>("/api/users/me", req)
+  return apiPatch<{ user: import("@/types").UserDTO }>("/api/v1/users/me", req)
 }
 
 // --- Admin ---
 
 export function fetchUsers(): Promise<UsersListResponse> {
-  return apiGet<UsersListResponse>("/api/admin/users")
+  return apiGet<UsersListResponse>("/api/v1/admin/users")
 }
 
 export function createUser(req: CreateUserRequest): Promise<{ user: import("@/types").UserDTO; message: string }> {
-  return apiPost<{ user: import("@/types").UserDTO; message: string }>("/api/admin/users", req)
+  return apiPost<{ user: import("@/types").UserDTO; message: string }>("/api/v1/admin/users", req)
 }
 
 export function updateUser(id: number, req: UpdateUserRequest): Promise<{ user: import("@/types").UserDTO; message: string }> {
-  return apiPatch<{ user: import("@/types").UserDTO; message: string }>(`/api/admin/users/${id}`, req)
+  return apiPatch<{ user: import("@/types").UserDTO; message: string }>(`/api/v1/admin/users/${id}`, req)
 }
 
 export function deleteUser(id: number): Promise<{ message: string }> {
-  return apiDelete<{ message: string }>(`/api/admin/users/${id}`)
+  return apiDelete<{ message: string }>(`/api/v1/admin/users/${id}`)
 }
 
 export function resetUserPassword(id: number, req: ResetPasswordRequest): Promise<{ message: string }> {
-  return apiPost<{ message: string }>(`/api/admin/users/${id}/reset-password`, req)
+  return apiPost<{ message: string }>(`/api/v1/admin/users/${id}/reset-password`, req)
 }
 
 export function fetchAuditLogs(page: number): Promise<AuditLogsResponse> {
-  return apiGet<AuditLogsResponse>("/api/admin/audit", { page: String(page) })
+  return apiGet<AuditLogsResponse>("/api/v1/admin/audit", { page: String(page) })
 }
 
 // --- OCR Status ---
 
 export function fetchOCRStatus(): Promise<OCRStatusResponse> {
-  return apiGet<OCRStatusResponse>("/api/ocr-status")
+  return apiGet<OCRStatusResponse>("/api/v1/ocr-status")
 }
 
 // --- OCR Classification ---
 
 export function startOcrClassification(): Promise<{ message: string }> {
-  return apiPost<{ message: string }>("/api/ocr/classify")
+  return apiPost<{ message: string }>("/api/v1/ocr/classify")
 }
 
 export function startOcrClassificationChanges(): Promise<{ message: string }> {
-  return apiPost<{ message: string }>("/api/ocr/classify-changes")
+  return apiPost<{ message: string }>("/api/v1/ocr/classify-changes")
 }
 
 export function stopOcrClassification(): Promise<{ message: string }> {
-  return apiPost<{ message: string }>("/api/ocr/stop")
+  return apiPost<{ message: string }>("/api/v1/ocr/stop")
 }
 
 export function fetchOcrClassificationStatus(): Promise<OcrClassificationStatusResponse> {
-  return apiGet<OcrClassificationStatusResponse>("/api/ocr/classify-status")
+  return apiGet<OcrClassificationStatusResponse>("/api/v1/ocr/classify-status")
 }
 
 export function fetchOcrDocuments(
   page: number,
   pageSize: number
 ): Promise<OcrDocumentsResponse> {
-  return apiGet<OcrDocumentsResponse>("/api/ocr/documents", {
+  return apiGet<OcrDocumentsResponse>("/api/v1/ocr/documents", {
     page: String(page),
     pageSize: String(pageSize),
   })
 }
 
 export function fetchOcrData(path: string): Promise<OcrDataResponse> {
-  return apiGet<OcrDataResponse>("/api/ocr/data", { path })
+  return apiGet<OcrDataResponse>("/api/v1/ocr/data", { path })
 }
 
 // --- LLM OCR ---
 
 export function fetchLlmSettings(): Promise<LlmSettingsDTO> {
-  return apiGet<LlmSettingsDTO>("/api/llm/settings")
+  return apiGet<LlmSettingsDTO>("/api/v1/llm/settings")
 }
 
 export function updateLlmSettings(req: UpdateLlmSettingsRequest): Promise<{ message: string }> {
-  return apiPut<{ message: string }>("/api/llm/settings", req)
+  return apiPut<{ message: string }>("/api/v1/llm/settings", req)
 }
 
 export function recognizeWithLlm(req: LlmOcrRequest): Promise<LlmRecognizeStatusResponse> {
-  return apiPost<LlmRecognizeStatusResponse>("/api/llm/recognize", req)
+  return apiPost<LlmRecognizeStatusResponse>("/api/v1/llm/recognize", req)
 }
 
 export function fetchLlmRecognizeStatus(path: string): Promise<LlmRecognizeStatusResponse> {
-  return apiGet<LlmRecognizeStatusResponse>("/api/llm/recognize-status", { path })
+  return apiGet<LlmRecognizeStatusResponse>("/api/v1/llm/recognize-status", { path })
 }
 
 export function fetchLlmRecognition(path: string): Promise<LlmOcrDataResponse> {
-  return apiGet<LlmOcrDataResponse>("/api/llm/recognition", { path })
+  return apiGet<LlmOcrDataResponse>("/api/v1/llm/recognition", { path })
 }
 
 export function fetchLlmModels(): Promise<LlmModelsResponse> {
-  return apiGet<LlmModelsResponse>("/api/llm/models")
+  return apiGet<LlmModelsResponse>("/api/v1/llm/models")
 }
 
 // --- Thumbnail Cache Management ---
 
 export function fetchThumbnailCacheStats(): Promise<ThumbnailCacheStatsResponse> {
-  return apiGet<ThumbnailCacheStatsResponse>("/api/thumbnail/cache/stats")
+  return apiGet<ThumbnailCacheStatsResponse>("/api/v1/thumbnail/cache/stats")
 }
 
 export function invalidateAllThumbnails(): Promise<{ message: string }> {
-  return apiDelete<{ message: string }>("/api/thumbnail/cache/invalidate-all")
+  return apiDelete<{ message: string }>("/api/v1/thumbnail/cache/invalidate-all")
 }
 
 export function warmupThumbnails(req: WarmupThumbnailsRequest): Promise<{ message: string }> {
-  return apiPost<{ message: string }>("/api/thumbnail/cache/warmup", req)
+  return apiPost<{ message: string }>("/api/v1/thumbnail/cache/warmup", req)
 }
 
 export function enableThumbnailCache(): Promise<{ message: string }> {
-  return apiPost<{ message: string }>("/api/thumbnail/cache/enable")
+  return apiPost<{ message: string }>("/api/v1/thumbnail/cache/enable")
 }
 
 export function disableThumbnailCache(): Promise<{ message: string }> {
-  return apiPost<{ message: string }>("/api/thumbnail/cache/disable")
+  return apiPost<{ message: string }>("/api/v1/thumbnail/cache/disable")
 }
@@ -1,27 +1,98 @@
+import { useState } from "react"
+import { Button } from "@/components/ui/button"
 import { Progress } from "@/components/ui/progress"
 import { useTranslation } from "@/i18n"
+import { cancelScan, pauseScan, resumeScan } from "@/api/endpoints"
 import type { ScanStatusResponse } from "@/types"
-import { Loader2 } from "lucide-react"
+import { AlertTriangle, Loader2, Pause, Play, Square } from "lucide-react"
 
 interface ScanProgressBannerProps {
   status: ScanStatusResponse
 }
 
+// formatEta formats a remaining time like the server's progress messages: 45s, 14m or 2h05m
+function formatEta(seconds: number): string {
+  if (seconds < 60) return `${Math.round(seconds)}s`
+  if (seconds < 3600) return `${Math.round(seconds / 60)}m`
+  const minutes = Math.round(seconds / 60)
+  return `${Math.floor(minutes / 60)}h${String(minutes % 60).padStart(2, "0")}m`
+}
+
 export function ScanProgressBanner({ status }: ScanProgressBannerProps) {
   const { t } = useTranslation()
+  const [isBusy, setIsBusy] = useState(false)
 
-  if (!status.scanning) return null
+  // Skipped gallery folders stay reported after the scan, until one finds them back
+  const unavailable = status.unavailableRoots?.length ? (
+    <div className="rounded-lg border border-amber-200 bg-amber-50 p-4 space-y-1 dark:border-amber-800 dark:bg-amber-950">
+      <div className="flex items-center gap-2 text-sm font-medium text-amber-800 dark:text-amber-200">
+        <AlertTriangle className="h-4 w-4" />
+        {t("scanProgress.unavailableRoots")}
+      </div>
+      <ul className="text-xs text-amber-700 dark:text-amber-300 space-y-0.5">
+        {status.unavailableRoots.map((root) => (
+          <li key={root} className="font-mono truncate">
+            {root}
+          </li>
+        ))}
+      </ul>
+    </div>
+  ) : null
+
+  if (!status.scanning) return unavailable
+
+  const runControl = async (action: () => Promise<unknown>) => {
+    setIsBusy(true)
+    try {
+      await action()
+    } catch {
+      // The next status poll reflects the actual state
+    } finally {
+      setIsBusy(false)
+    }
+  }
 
   return (
-    <div className="rounded-lg border border-blue-200 bg-blue-50 p-4 space-y-2 dark:border-blue-800 dark:bg-blue-950">
-      <div className="flex items-center gap-2 text-sm font-medium text-blue-800 dark:text-blue-200">
-        <Loader2 className="h-4 w-4 animate-spin" />
-        {t("scanProgress.scanning")}
-      </div>
-      <Progress value={undefined} className="h-1.5" />
-      <div className="flex items-center justify-between text-xs text-blue-600 dark:text-blue-400">
-        <span className="truncate max-w-md">{status.progress}</span>
-        <span>{t("scanProgress.filesProcessed", { count: status.filesProcessed })}</span>
+    <div className="space-y-2">
+      {unavailable}
+      <div className="rounded-lg border border-blue-200 bg-blue-50 p-4 space-y-2 dark:border-blue-800 dark:bg-blue-950">
+        <div className="flex items-center justify-between gap-2">
+          <div className="flex items-center gap-2 text-sm font-medium text-blue-800 dark:text-blue-200">
+            {status.paused ? <Pause className="h-4 w-4" /> : <Loader2 className="h-4 w-4 animate-spin" />}
+            {status.paused ? t("scanProgress.paused") : t("scanProgress.scanning")}
+          </div>
+          <div className="flex items-center gap-1">
+            {status.paused ? (
+              <Button variant="ghost" size="sm" disabled={isBusy} onClick={() => runControl(resumeScan)}>
+                <Play className="h-4 w-4" />
+                {t("scanProgress.resume")}
+              </Button>
+            ) : (
+              <Button variant="ghost" size="sm" disabled={isBusy} onClick={() => runControl(pauseScan)}>
+                <Pause className="h-4 w-4" />
+                {t("scanProgress.pause")}
+              </Button>
+            )}
+            <Button variant="ghost" size="sm" disabled={isBusy} onClick={() => runControl(cancelScan)}>
+              <Square className="h-4 w-4" />
+              {t("scanProgress.cancel")}
+            </Button>
+          </div>
+        </div>
+        <Progress value={status.filesTotal ? status.percent ?? 0 : undefined} className="h-1.5" />
+        <div className="flex items-center justify-between text-xs text-blue-600 dark:text-blue-400">
+          <span className="truncate max-w-md">{status.progress}</span>
+          <span className="shrink-0">
+            {status.filesTotal
+              ? t("scanProgress.estimate", {
+                  done: status.filesDone ?? 0,
+                  total: status.filesTotal,
+                  percent: (status.percent ?? 0).toFixed(1),
+                })
+              : t("scanProgress.filesProcessed", { count: status.filesProcessed })}
+            {status.etaSeconds ? ` · ${t("scanProgress.eta", { time: formatEta(status.etaSeconds) })}` : ""}
+          </span>
+        </div>
       </div>
     </div>
   )
@@ -0,0 +1,119 @@
+import { useEffect, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import { Filter, MapPin, Monitor, X } from "lucide-react"
+import { fetchHosts } from "@/api/endpoints"
+import { useTranslation, type TranslationKey } from "@/i18n"
+import type { DuplicateFilters, HostDTO, ReviewFilter } from "@/types"
+
+const REVIEW_FILTERS: ReviewFilter[] = ["unreviewed", "deferred", "reviewed"]
+
+interface DuplicateFilterBarProps {
+  filters: DuplicateFilters
+  onChange: (filters: DuplicateFilters) => void
+}
+
+// DuplicateFilterBar narrows the duplicate list to a directory, extensions, a size range,
+// a location, a review status and, with agents, groups with copies on all of the chosen
+// machines
+export function DuplicateFilterBar({ filters, onChange }: DuplicateFilterBarProps) {
+  const [draft, setDraft] = useState<DuplicateFilters>(filters)
+  const [hosts, setHosts] = useState<HostDTO[]>([])
+  const { t } = useTranslation()
+
+  useEffect(() => {
+    setDraft(filters)
+  }, [filters])
+
+  useEffect(() => {
+    fetchHosts()
+      .then((resp) => setHosts(resp.hosts))
+      .catch(() => setHosts([]))
+  }, [])
+
+  const selectedHosts = filters.hosts ? filters.hosts.split(",") : []
+  const toggleHost = (name: string) => {
+    const next = selectedHosts.includes(name)
+      ? selectedHosts.filter((h) => h !== name)
+      : [...selectedHosts, name]
+    onChange({ ...filters, hosts: next.length > 0 ? next.join(",") : undefined })
+  }
+
+  const isActive = Object.values(filters).some(Boolean)
+
+  const handleSubmit = (e: React.FormEvent) => {
+    e.preventDefault()
+    const applied: DuplicateFilters = { review: filters.review, hosts: filters.hosts }
+    for (const key of ["dir", "ext", "minSize", "maxSize", "near", "radius"] as const) {
+      const value = draft[key]?.trim()
+      if (value) applied[key] = value
+    }
+    onChange(applied)
+  }
+
+  const field = (key: keyof DuplicateFilters, className: string) => (
+    <Input
+      value={draft[key] ?? ""}
+      onChange={(e) => setDraft({ ...draft, [key]: e.target.value })}
+      placeholder={t(`filters.${key}` as TranslationKey)}
+      className={`h-8 text-xs ${className}`}
+    />
+  )
+
+  return (
+    <form onSubmit={handleSubmit} className="flex flex-wrap items-center gap-2 rounded-lg border bg-card p-3">
+      {field("dir", "flex-1 min-w-48 font-mono")}
+      {field("ext", "w-32")}
+      {field("minSize", "w-24")}
+      {field("maxSize", "w-24")}
+      <div className="flex items-center gap-1" title={t("filters.nearHint")}>
+        <MapPin className="h-3.5 w-3.5 text-muted-foreground" />
+        {field("near", "w-36")}
+        {field("radius", "w-20")}
+      </div>
+      <Select
+        value={filters.review ?? "all"}
+        onValueChange={(v) => onChange({ ...filters, review: v === "all" ? undefined : (v as ReviewFilter) })}
+      >
+        <SelectTrigger className="w-40 h-8 text-xs">
+          <SelectValue />
+        </SelectTrigger>
+        <SelectContent>
+          <SelectItem value="all">{t("filters.review.all")}</SelectItem>
+          {REVIEW_FILTERS.map((review) => (
+            <SelectItem key={review} value={review}>{t(`filters.review.${review}` as TranslationKey)}</SelectItem>
+          ))}
+        </SelectContent>
+      </Select>
+      {hosts.length > 1 && (
+        <div className="flex flex-wrap items-center gap-1" title={t("filters.hostsHint")}>
+          <span className="text-xs text-muted-foreground">{t("filters.hosts")}</span>
+          {hosts.map((host) => (
+            <Button
+              key={host.name}
+              type="button"
+              size="sm"
+              variant={selectedHosts.includes(host.name) ? "secondary" : "ghost"}
+              className="h-8 text-xs"
+              onClick={() => toggleHost(host.name)}
+            >
+              <Monitor className="mr-1.5 h-3.5 w-3.5" />
+              {host.local ? t("fileItem.localHost") : host.name}
+            </Button>
+          ))}
+        </div>
+      )}
+      <Button type="submit" size="sm" variant="outline">
+        <Filter className="mr-1.5 h-3.5 w-3.5" />
+        {t("filters.apply")}
+      </Button>
+      {isActive && (
+        <Button type="button" size="sm" variant="ghost" onClick={() => onChange({})}>
+          <X className="mr-1.5 h-3.5 w-3.5" />
+          {t("filters.clear")}
+        </Button>
+      )}
+    </form>
+  )
+}
@@ -1,15 +1,22 @@
 import { Card, CardHeader, CardTitle, CardContent } from "@/components/ui/card"
 import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
 import { ThumbnailImage } from "./ThumbnailImage"
 import { FileItem } from "./FileItem"
-import { useTranslation } from "@/i18n"
-import type { DuplicateGroupDTO, FileDTO } from "@/types"
+import { useTranslation, type TranslationKey } from "@/i18n"
+import { thumbnailSrc } from "@/api/endpoints"
+import { Check, Clock, EyeOff, Maximize2 } from "lucide-react"
+import type { DuplicateGroupDTO, FileDTO, ReviewFilter } from "@/types"
 
 interface DuplicateGroupCardProps {
   group: DuplicateGroupDTO
   isSelected: (path: string) => boolean
   onToggleFile: (path: string) => void
   onSelectFolder: (dirPath: string) => void
+  onOpenDetails: (hash: string) => void
+  onIgnore: (hash: string) => void
+  onToggleProtected: (path: string, isProtected: boolean) => void
+  onReview: (hash: string, status: ReviewFilter) => void
 }
 
 export function DuplicateGroupCard({
@@ -17,8 +24,13 @@ export function DuplicateGroupCard({
   isSelected,
   onToggleFile,
   onSelectFolder,
+  onOpenDetails,
+  onIgnore,
+  onToggleProtected,
+  onReview,
 }: DuplicateGroupCardProps) {
   const allFiles: FileDTO[] = group.files
+  const showHost = allFiles.some((file) => file.host)
   const { t } = useTranslation()
 
   return (
@@ -29,12 +41,51 @@ export function DuplicateGroupCard({
           <Badge variant="secondary" className="text-xs">{t("duplicateGroup.files", { count: group.files.length })}</Badge>
           <Badge variant="outline" className="text-xs">{t("duplicateGroup.sizeEach", { size: group.sizeHuman })}</Badge>
           <span className="text-xs text-muted-foreground font-mono">{t("duplicateGroup.md5", { hash: group.hash })}</span>
+          {group.review && (
+            <Badge variant={group.review === "reviewed" ? "default" : "outline"} className="text-xs">
+              {t(`duplicateGroup.review.${group.review}` as TranslationKey)}
+            </Badge>
+          )}
+          <Button
+            size="sm"
+            variant={group.review === "reviewed" ? "secondary" : "ghost"}
+            className="ml-auto h-7 text-xs"
+            title={group.review === "reviewed" ? t("duplicateGroup.reviewHint") : undefined}
+            onClick={() => onReview(group.hash, group.review === "reviewed" ? "unreviewed" : "reviewed")}
+          >
+            <Check className="mr-1.5 h-3.5 w-3.5" />
+            {t("duplicateGroup.markReviewed")}
+          </Button>
+          <Button
+            size="sm"
+            variant={group.review === "deferred" ? "secondary" : "ghost"}
+            className="h-7 text-xs"
+            title={group.review === "deferred" ? t("duplicateGroup.reviewHint") : undefined}
+            onClick={() => onReview(group.hash, group.review === "deferred" ? "unreviewed" : "deferred")}
+          >
+            <Clock className="mr-1.5 h-3.5 w-3.5" />
+            {t("duplicateGroup.markDeferred")}
+          </Button>
+          <Button
+            size="sm"
+            variant="ghost"
+            className="h-7 text-xs"
+            title={t("duplicateGroup.ignoreHint")}
+            onClick={() => onIgnore(group.hash)}
+          >
+            <EyeOff className="mr-1.5 h-3.5 w-3.5" />
+            {t("duplicateGroup.ignore")}
+          </Button>
+          <Button size="sm" variant="ghost" className="h-7 text-xs" onClick={() => onOpenDetails(group.hash)}>
+            <Maximize2 className="mr-1.5 h-3.5 w-3.5" />
+            {t("duplicateGroup.details")}
+          </Button>
         </div>
       </CardHeader>
       <CardContent>
         <div className="flex gap-4">
           <div className="shrink-0">
-            <ThumbnailImage src={group.thumbnail} />
+            <ThumbnailImage src={group.thumbnail && thumbnailSrc(group.thumbnail)} />
           </div>
           <div className="min-w-0 flex-1 space-y-1">
             {allFiles.map((file) => (
@@ -44,6 +95,8 @@ export function DuplicateGroupCard({
                 isSelected={isSelected(file.path)}
                 onToggle={onToggleFile}
                 onSelectFolder={(dirPath) => onSelectFolder(dirPath)}
+                onToggleProtected={onToggleProtected}
+                showHost={showHost}
               />
             ))}
           </div>
@@ -1,5 +1,5 @@
 import { DuplicateGroupCard } from "./DuplicateGroupCard"
-import type { DuplicateGroupDTO, FileDTO } from "@/types"
+import type { DuplicateGroupDTO, FileDTO, ReviewFilter } from "@/types"
 
 interface DuplicateGroupListProps {
   groups: DuplicateGroupDTO[]
@@ -7,6 +7,10 @@ interface DuplicateGroupListProps {
   isSelected: (path: string) => boolean
   onToggleFile: (path: string) => void
   onSelectFolder: (dirPath: string, allFiles: FileDTO[]) => void
+  onOpenDetails: (hash: string) => void
+  onIgnore: (hash: string) => void
+  onToggleProtected: (path: string, isProtected: boolean) => void
+  onReview: (hash: string, status: ReviewFilter) => void
 }
 
 export function DuplicateGroupList({
@@ -15,6 +19,10 @@ export function DuplicateGroupList({
   isSelected,
   onToggleFile,
   onSelectFolder,
+  onOpenDetails,
+  onIgnore,
+  onToggleProtected,
+  onReview,
 }: DuplicateGroupListProps) {
   return (
     <div className="space-y-3">
@@ -25,6 +33,10 @@ export function DuplicateGroupList({
           isSelected={isSelected}
           onToggleFile={onToggleFile}
           onSelectFolder={(dirPath) => onSelectFolder(dirPath, allFiles)}
+          onOpenDetails={onOpenDetails}
+          onIgnore={onIgnore}
+          onToggleProtected={onToggleProtected}
+          onReview={onReview}
         />
       ))}
     </div>
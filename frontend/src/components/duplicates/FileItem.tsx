@@ -1,16 +1,19 @@
 import { Checkbox } from "@/components/ui/checkbox"
 import { useTranslation } from "@/i18n"
 import type { FileDTO } from "@/types"
-import { Folder } from "lucide-react"
+import { FileWarning, Folder, Lock, LockOpen, Monitor } from "lucide-react"
 
 interface FileItemProps {
   file: FileDTO
   isSelected: boolean
   onToggle: (path: string) => void
   onSelectFolder: (dirPath: string) => void
+  onToggleProtected: (path: string, isProtected: boolean) => void
+  // Shows the machine of the file, for groups spanning several
+  showHost?: boolean
 }
 
-export function FileItem({ file, isSelected, onToggle, onSelectFolder }: FileItemProps) {
+export function FileItem({ file, isSelected, onToggle, onSelectFolder, onToggleProtected, showHost }: FileItemProps) {
   const { t } = useTranslation()
 
   return (
@@ -25,7 +28,24 @@ export function FileItem({ file, isSelected, onToggle, onSelectFolder }: FileIte
         className="mt-0.5"
       />
       <div className="min-w-0 flex-1">
-        <div className="text-sm font-medium truncate">{file.fileName}</div>
+        <div className="flex items-center gap-2">
+          <span className="text-sm font-medium truncate">{file.fileName}</span>
+          {file.corrupt && (
+            <span
+              className="flex shrink-0 items-center gap-1 rounded border border-destructive/40 px-1.5 text-xs text-destructive"
+              title={t("fileItem.corruptHint")}
+            >
+              <FileWarning className="h-3 w-3" />
+              {t("fileItem.corrupt")}
+            </span>
+          )}
+          {showHost && (
+            <span className="flex shrink-0 items-center gap-1 rounded border px-1.5 text-xs text-muted-foreground">
+              <Monitor className="h-3 w-3" />
+              {file.host || t("fileItem.localHost")}
+            </span>
+          )}
+        </div>
         <button
           className="flex items-center gap-1 text-xs text-muted-foreground hover:text-primary transition-colors truncate max-w-full text-left"
           onClick={() => onSelectFolder(file.dirPath)}
@@ -37,6 +57,14 @@ export function FileItem({ file, isSelected, onToggle, onSelectFolder }: FileIte
         </button>
         <div className="text-xs text-muted-foreground mt-0.5">{t("fileItem.modified", { date: file.modTime })}</div>
       </div>
+      <button
+        className={`shrink-0 transition-colors ${file.protected ? "text-primary" : "text-muted-foreground/50 hover:text-primary"}`}
+        onClick={() => onToggleProtected(file.path, !file.protected)}
+        title={file.protected ? t("fileItem.unprotect") : t("fileItem.protect")}
+        type="button"
+      >
+        {file.protected ? <Lock className="h-4 w-4" /> : <LockOpen className="h-4 w-4" />}
+      </button>
     </div>
   )
 }
@@ -0,0 +1,148 @@
+import { useState } from "react"
+import { Dialog, DialogContent, DialogHeader, DialogTitle, DialogDescription } from "@/components/ui/dialog"
+import { Badge } from "@/components/ui/badge"
+import { IconButton } from "@/components/ui/icon-button"
+import { Skeleton } from "@/components/ui/skeleton"
+import { ImageLightbox } from "@/components/gallery/ImageLightbox"
+import { DeleteFilesModal } from "@/components/modals/DeleteFilesModal"
+import { useGroup } from "@/hooks/useGroup"
+import { imageSrc, setProtected } from "@/api/endpoints"
+import { useTranslation } from "@/i18n"
+import { formatSize } from "@/lib/utils"
+import { Lock, LockOpen, Maximize2, ShieldCheck, Trash2 } from "lucide-react"
+
+interface GroupDetailDialogProps {
+  hash: string | null
+  onClose: () => void
+  onSuccess: (message: string) => void
+  onError: (message: string) => void
+  // Called after files of the group were deleted, to refresh the duplicate list
+  onComplete: () => void
+}
+
+// GroupDetailDialog shows every file of one duplicate group with a large preview and
+// actions on the single file
+export function GroupDetailDialog({ hash, onClose, onSuccess, onError, onComplete }: GroupDetailDialogProps) {
+  const { data, isLoading, error, refetch } = useGroup(hash)
+  const [lightboxPath, setLightboxPath] = useState<string | null>(null)
+  const [deletePaths, setDeletePaths] = useState<string[]>([])
+  const { t } = useTranslation()
+
+  const files = data?.group.files ?? []
+  const showHost = files.some((file) => file.host)
+
+  const handleDeleted = () => {
+    refetch()
+    onComplete()
+  }
+
+  const handleToggleProtected = async (path: string, isProtected: boolean) => {
+    try {
+      await setProtected([path], isProtected)
+      refetch()
+      onComplete()
+    } catch (err) {
+      onError(err instanceof Error ? err.message : t("api.scan.protect_failed"))
+    }
+  }
+
+  return (
+    <>
+      <Dialog open={!!hash} onOpenChange={(open) => !open && onClose()}>
+        <DialogContent className="max-w-[95vw] max-h-[90vh] overflow-y-auto">
+          <DialogHeader>
+            <DialogTitle>{t("groupDetail.title")}</DialogTitle>
+            <DialogDescription className="font-mono text-xs break-all">{hash}</DialogDescription>
+          </DialogHeader>
+
+          {data && (
+            <div className="flex flex-wrap items-center gap-2">
+              <Badge variant="secondary" className="text-xs">{t("duplicateGroup.files", { count: files.length })}</Badge>
+              <Badge variant="outline" className="text-xs">{t("duplicateGroup.sizeEach", { size: data.group.sizeHuman })}</Badge>
+              <Badge variant="outline" className="text-xs">{t("groupDetail.reclaimable", { size: data.reclaimableHuman })}</Badge>
+            </div>
+          )}
+
+          {error && <p className="text-sm text-destructive">{error}</p>}
+
+          {isLoading && !data ? (
+            <div className="grid gap-4 sm:grid-cols-2 lg:grid-cols-3">
+              {Array.from({ length: 3 }).map((_, i) => (
+                <Skeleton key={i} className="h-72 w-full rounded-md" />
+              ))}
+            </div>
+          ) : (
+            <div className="grid gap-4 sm:grid-cols-2 lg:grid-cols-3">
+              {files.map((file) => (
+                <div key={file.id} className="flex flex-col rounded-lg border bg-card">
+                  <button type="button" className="bg-muted rounded-t-lg" onClick={() => setLightboxPath(file.path)}>
+                    <img
+                      src={imageSrc(file.path)}
+                      alt={file.fileName}
+                      loading="lazy"
+                      className="h-64 w-full object-contain"
+                    />
+                  </button>
+                  <div className="flex-1 space-y-1 p-3">
+                    <div className="text-sm font-medium break-all">{file.fileName}</div>
+                    <div className="text-xs text-muted-foreground break-all">
+                      {showHost && `${file.host || t("fileItem.localHost")}: `}
+                      {file.dirPath}
+                    </div>
+                    <div className="text-xs text-muted-foreground">
+                      {formatSize(file.size)} · {t("fileItem.modified", { date: file.modTime })}
+                    </div>
+                  </div>
+                  <div className="flex flex-wrap gap-2 border-t p-3">
+                    <IconButton size="sm" variant="outline" icon={Maximize2} onClick={() => setLightboxPath(file.path)}>
+                      {t("groupDetail.open")}
+                    </IconButton>
+                    {files.length > 1 && (
+                      <IconButton
+                        size="sm"
+                        variant="outline"
+                        icon={ShieldCheck}
+                        onClick={() => setDeletePaths(files.filter((f) => f.id !== file.id).map((f) => f.path))}
+                      >
+                        {t("groupDetail.keepOnly")}
+                      </IconButton>
+                    )}
+                    <IconButton
+                      size="sm"
+                      variant="outline"
+                      icon={file.protected ? Lock : LockOpen}
+                      title={file.protected ? t("fileItem.unprotect") : t("fileItem.protect")}
+                      onClick={() => handleToggleProtected(file.path, !file.protected)}
+                    >
+                      {file.protected ? t("groupDetail.unprotect") : t("groupDetail.protect")}
+                    </IconButton>
+                    <IconButton
+                      size="sm"
+                      variant="destructive"
+                      icon={Trash2}
+                      disabled={file.protected}
+                      onClick={() => setDeletePaths([file.path])}
+                    >
+                      {t("groupDetail.delete")}
+                    </IconButton>
+                  </div>
+                </div>
+              ))}
+            </div>
+          )}
+        </DialogContent>
+      </Dialog>
+
+      <ImageLightbox imagePath={lightboxPath} onClose={() => setLightboxPath(null)} />
+
+      <DeleteFilesModal
+        open={deletePaths.length > 0}
+        onOpenChange={(open) => !open && setDeletePaths([])}
+        selectedPaths={deletePaths}
+        onSuccess={onSuccess}
+        onError={onError}
+        onComplete={handleDeleted}
+      />
+    </>
+  )
+}
@@ -1,5 +1,5 @@
 import { useCallback, useEffect, useMemo, useRef, useState } from "react"
-import { fetchGalleryCalendar, fetchCalendarMonthInfo } from "@/api/endpoints"
+import { fetchGalleryCalendar, fetchCalendarMonthInfo, thumbnailSrc } from "@/api/endpoints"
 import { Skeleton } from "@/components/ui/skeleton"
 import { ChevronLeft, ChevronRight, Calendar as CalendarIcon } from "lucide-react"
 import { useTranslation } from "@/i18n"
@@ -139,7 +139,7 @@ export function GalleryCalendarView({ onImageClick }: GalleryCalendarViewProps)
   // Preload images when groups change
   useEffect(() => {
     const imageUrls = groups.flatMap((group) => 
-      group.images.map((img) => img.thumbnail).filter(Boolean) as string[]
+      group.images.flatMap((img) => (img.thumbnail ? [thumbnailSrc(img.thumbnail)] : []))
     )
     
     // Preload with slight delay to not block initial render
@@ -178,7 +178,7 @@ export function GalleryCalendarView({ onImageClick }: GalleryCalendarViewProps)
         )
           .then((result) => {
             const imageUrls = result.groups.flatMap((group) =>
-              group.images.map((img) => img.thumbnail).filter(Boolean) as string[]
+              group.images.flatMap((img) => (img.thumbnail ? [thumbnailSrc(img.thumbnail)] : []))
             )
             preloadImages(imageUrls)
           })
@@ -495,7 +495,7 @@ export function GalleryCalendarView({ onImageClick }: GalleryCalendarViewProps)
                         <div className="relative aspect-square overflow-hidden rounded-lg border bg-muted hover:ring-2 hover:ring-ring transition-all">
                           {image.thumbnail ? (
                             <img
-                              src={image.thumbnail}
+                              src={thumbnailSrc(image.thumbnail)}
                               alt={image.fileName}
                               className="h-full w-full object-cover"
                               loading="lazy"
@@ -2,6 +2,7 @@ import { useMemo } from "react"
 import { useTranslation } from "@/i18n"
 import { Folder } from "lucide-react"
 import type { GalleryImageDTO } from "@/types"
+import { thumbnailSrc } from "@/api/endpoints"
 
 interface GalleryImageGridProps {
   images: GalleryImageDTO[]
@@ -65,7 +66,7 @@ export function GalleryImageGrid({ images, onImageClick }: GalleryImageGridProps
                 <div className="relative aspect-square overflow-hidden rounded-lg border bg-muted hover:ring-2 hover:ring-ring transition-all">
                   {image.thumbnail ? (
                     <img
-                      src={image.thumbnail}
+                      src={thumbnailSrc(image.thumbnail)}
                       alt={image.fileName}
                       className="h-full w-full object-cover"
                       loading="lazy"
@@ -25,7 +25,7 @@ export function ImageLightbox({ imagePath, onClose }: ImageLightboxProps) {
 
   if (!imagePath) return null
 
-  const imageUrl = `${API_BASE_URL}/api/image?path=${encodeURIComponent(imagePath)}`
+  const imageUrl = `${API_BASE_URL}/api/v1/image?path=${encodeURIComponent(imagePath)}`
 
   return (
     <Dialog open={!!imagePath} onOpenChange={() => onClose()}>
@@ -207,7 +207,7 @@ export function OcrLightbox({ imagePath, onClose }: OcrLightboxProps) {
 
   const angle = ocrData?.angle || 0
   const imageUrl = imagePath
-    ? `${API_BASE_URL}/api/ocr-image?path=${encodeURIComponent(imagePath)}&angle=${angle}`
+    ? `${API_BASE_URL}/api/v1/ocr-image?path=${encodeURIComponent(imagePath)}&angle=${angle}`
     : ""
 
   // Format processing time
@@ -1,7 +1,8 @@
-import { useCallback, useState } from "react"
+import { useCallback, useEffect, useState } from "react"
 import { useTranslation } from "@/i18n"
-import { Settings, ImageIcon, FileScan, Shield, Users, ChevronDown, ChevronRight, Folder, Calendar, FileText } from "lucide-react"
+import { Settings, ImageIcon, FileScan, Shield, Users, ChevronDown, ChevronRight, Folder, Calendar, FileText, Film, History, BarChart3, ClipboardList, EyeOff, FileWarning, FileStack, Copy, CalendarRange } from "lucide-react"
 import { useAuth } from "@/providers/AuthProvider"
+import { fetchSettings } from "@/api/endpoints"
 import { Button } from "@/components/ui/button"
 import { cn } from "@/lib/utils"
 
@@ -24,6 +25,14 @@ export function Sidebar({ activeTab, onTabChange }: SidebarProps) {
   const [toolsExpanded, setToolsExpanded] = useState(true)
   const [accountExpanded, setAccountExpanded] = useState(true)
   const [adminExpanded, setAdminExpanded] = useState(false)
+  const [videosEnabled, setVideosEnabled] = useState(false)
+
+  // The videos section is only shown when the server scans video files
+  useEffect(() => {
+    fetchSettings()
+      .then((settings) => setVideosEnabled(settings.videoScanEnabled))
+      .catch(() => setVideosEnabled(false))
+  }, [])
 
   const gallerySubModes = [
     { value: "gallery-folders", icon: Folder, label: t("gallery.subModes.folders") },
@@ -32,7 +41,16 @@ export function Sidebar({ activeTab, onTabChange }: SidebarProps) {
 
   const toolsSubModes = [
     { value: "deduplication", icon: FileScan, label: t("tabs.deduplication") },
+    ...(videosEnabled ? [{ value: "videos", icon: Film, label: t("tabs.videos") }] : []),
     { value: "ocr", icon: FileText, label: t("tabs.ocr") },
+    { value: "stats", icon: BarChart3, label: t("tabs.stats") },
+    { value: "timeline", icon: CalendarRange, label: t("tabs.timeline") },
+    { value: "scan-history", icon: ClipboardList, label: t("tabs.scanHistory") },
+    { value: "ignored-groups", icon: EyeOff, label: t("tabs.ignoredGroups") },
+    { value: "name-copies", icon: Copy, label: t("tabs.nameCopies") },
+    { value: "corrupt-files", icon: FileWarning, label: t("tabs.corruptFiles") },
+    { value: "name-conflicts", icon: FileStack, label: t("tabs.nameConflicts") },
+    { value: "deletions", icon: History, label: t("tabs.deletions") },
   ]
 
   const accountTabs: TabItem[] = [
@@ -46,7 +64,7 @@ export function Sidebar({ activeTab, onTabChange }: SidebarProps) {
   ]
 
   const isGalleryActive = activeTab.startsWith("gallery")
-  const isToolsActive = activeTab === "deduplication" || activeTab === "ocr"
+  const isToolsActive = activeTab === "deduplication" || activeTab === "videos" || activeTab === "ocr" || activeTab === "stats" || activeTab === "timeline" || activeTab === "scan-history" || activeTab === "ignored-groups" || activeTab === "name-copies" || activeTab === "corrupt-files" || activeTab === "name-conflicts" || activeTab === "deletions"
   const isAccountActive = activeTab === "settings" || activeTab === "profile"
   const isAdminActive = activeTab === "admin-users" || activeTab === "admin-settings"
 
@@ -1,18 +1,25 @@
 import { IconButton } from "@/components/ui/icon-button"
 import { Badge } from "@/components/ui/badge"
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
-import { PAGE_SIZES } from "@/lib/constants"
-import { RefreshCw, RotateCcw, Trash2, Layers } from "lucide-react"
-import { useTranslation } from "@/i18n"
+import { PAGE_SIZES, KEEP_POLICIES, DUPLICATE_SORTS } from "@/lib/constants"
+import { RefreshCw, RotateCcw, Trash2, Layers, FolderInput, Download, FileText } from "lucide-react"
+import { useTranslation, type TranslationKey } from "@/i18n"
+import type { DuplicateSort, KeepPolicy } from "@/types"
 
 interface ToolbarProps {
   selectedCount: number
   pageSize: number
   onPageSizeChange: (size: number) => void
+  sort?: DuplicateSort
+  onSortChange: (sort: DuplicateSort | undefined) => void
   onRescan: () => void
   onResetSelection: () => void
   onOpenDeleteFiles: () => void
   onOpenBatchDedup: () => void
+  onOpenConsolidate: () => void
+  onAutoSelect: (policy: KeepPolicy) => void
+  onExportCsv: () => void
+  onExportHtml: () => void
   isScanning: boolean
 }
 
@@ -20,10 +27,16 @@ export function Toolbar({
   selectedCount,
   pageSize,
   onPageSizeChange,
+  sort,
+  onSortChange,
   onRescan,
   onResetSelection,
   onOpenDeleteFiles,
   onOpenBatchDedup,
+  onOpenConsolidate,
+  onAutoSelect,
+  onExportCsv,
+  onExportHtml,
   isScanning,
 }: ToolbarProps) {
   const { t } = useTranslation()
@@ -42,6 +55,25 @@ export function Toolbar({
       <IconButton size="sm" variant="outline" icon={Layers} onClick={onOpenBatchDedup}>
         {t("toolbar.batchDedup")}
       </IconButton>
+      <IconButton size="sm" variant="outline" icon={FolderInput} onClick={onOpenConsolidate}>
+        {t("toolbar.consolidate")}
+      </IconButton>
+      <Select value="" onValueChange={(v) => onAutoSelect(v as KeepPolicy)}>
+        <SelectTrigger className="w-48 h-8 text-xs">
+          <SelectValue placeholder={t("toolbar.autoSelect")} />
+        </SelectTrigger>
+        <SelectContent>
+          {KEEP_POLICIES.map((policy) => (
+            <SelectItem key={policy} value={policy}>{t(`batchDedup.policy.${policy}` as TranslationKey)}</SelectItem>
+          ))}
+        </SelectContent>
+      </Select>
+      <IconButton size="sm" variant="outline" icon={Download} onClick={onExportCsv}>
+        {t("toolbar.exportCsv")}
+      </IconButton>
+      <IconButton size="sm" variant="outline" icon={FileText} onClick={onExportHtml}>
+        {t("toolbar.exportHtml")}
+      </IconButton>
 
       <div className="ml-auto flex items-center gap-3">
         {selectedCount > 0 && (
@@ -51,6 +83,22 @@ export function Toolbar({
               : t("toolbar.filesSelected", { count: selectedCount })}
           </Badge>
         )}
+        <div className="flex items-center gap-2">
+          <span className="text-xs text-muted-foreground whitespace-nowrap">{t("toolbar.sortBy")}</span>
+          <Select
+            value={sort ?? "default"}
+            onValueChange={(v) => onSortChange(v === "default" ? undefined : (v as DuplicateSort))}
+          >
+            <SelectTrigger className="w-40 h-8 text-xs">
+              <SelectValue />
+            </SelectTrigger>
+            <SelectContent>
+              {DUPLICATE_SORTS.map((option) => (
+                <SelectItem key={option} value={option}>{t(`toolbar.sort.${option}` as TranslationKey)}</SelectItem>
+              ))}
+            </SelectContent>
+          </Select>
+        </div>
         <div className="flex items-center gap-2">
           <span className="text-xs text-muted-foreground whitespace-nowrap">{t("toolbar.groupsPerPage")}</span>
           <Select value={String(pageSize)} onValueChange={(v) => onPageSizeChange(Number(v))}>
@@ -6,13 +6,16 @@ import { Button } from "@/components/ui/button"
 import { Label } from "@/components/ui/label"
 import { RadioGroup, RadioGroupItem } from "@/components/ui/radio-group"
 import { Checkbox } from "@/components/ui/checkbox"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
 import { Badge } from "@/components/ui/badge"
 import { Skeleton } from "@/components/ui/skeleton"
 import { useFolderPatterns } from "@/hooks/useFolderPatterns"
+import { ReplaceModeSelect } from "@/components/modals/ReplaceModeSelect"
 import { batchDelete } from "@/api/endpoints"
 import { useSettings } from "@/providers/useSettings"
-import { useTranslation } from "@/i18n"
-import type { BatchDeleteRule, FolderPattern } from "@/types"
+import { useTranslation, type TranslationKey } from "@/i18n"
+import { KEEP_POLICIES } from "@/lib/constants"
+import type { BatchDeleteRule, FolderPattern, KeepPolicy, ReplaceMode } from "@/types"
 
 interface BatchDeduplicationModalProps {
   open: boolean
@@ -33,6 +36,10 @@ export function BatchDeduplicationModal({
   const [currentStep, setCurrentStep] = useState(0)
   const [selectedFolders, setSelectedFolders] = useState<Record<string, string>>({})
   const [useTrash, setUseTrash] = useState(true)
+  const [systemTrash, setSystemTrash] = useState(false)
+  const [verify, setVerify] = useState(true)
+  const [keepPolicy, setKeepPolicy] = useState<KeepPolicy | "none">("none")
+  const [replace, setReplace] = useState<ReplaceMode | undefined>(undefined)
   const [isSubmitting, setIsSubmitting] = useState(false)
   const [isCompleted, setIsCompleted] = useState(false)
   const { trashDir } = useSettings()
@@ -50,6 +57,8 @@ export function BatchDeduplicationModal({
       setCurrentStep(0)
       setSelectedFolders({})
       setUseTrash(true)
+      setKeepPolicy("none")
+      setReplace(undefined)
       setIsCompleted(false)
     }
   }, [open, load])
@@ -90,12 +99,12 @@ export function BatchDeduplicationModal({
       .filter(([, folder]) => folder)
       .map(([patternId, keepFolder]) => ({ patternId, keepFolder }))
 
-    if (rules.length === 0) {
+    if (rules.length === 0 && keepPolicy === "none") {
       onError(t("batchDedup.errorNoRules"))
       return
     }
 
-    if (!useTrash || !trashDir) {
+    if (!useTrash || (!systemTrash && !trashDir)) {
       if (!window.confirm(t("batchDedup.confirmPermanent"))) {
         return
       }
@@ -109,7 +118,11 @@ export function BatchDeduplicationModal({
     try {
       const result = await batchDelete({
         rules,
-        trashDir: useTrash ? trashDir : "",
+        keepRules: keepPolicy === "none" ? undefined : [{ policy: keepPolicy }],
+        trashDir: useTrash && !systemTrash ? trashDir : "",
+        systemTrash: useTrash && systemTrash,
+        verify,
+        replace,
       })
       let message: string
       if (result.failed > 0) {
@@ -117,6 +130,9 @@ export function BatchDeduplicationModal({
       } else {
         message = t("batchDedup.success", { count: result.success })
       }
+      if (result.protectedFiles?.length) {
+        message += " " + t("deleteFiles.protectedSkipped", { count: result.protectedFiles.length })
+      }
       onSuccess(message)
       setIsCompleted(true)
       onComplete()
@@ -213,6 +229,22 @@ export function BatchDeduplicationModal({
               )}
             </div>
           ) : null}
+          {!isCompleted && patterns.length > 0 && (
+            <div className="space-y-1 flex-shrink-0 pt-2 border-t">
+              <Label htmlFor="batch-keep-policy" className="text-sm">{t("batchDedup.keepPolicy")}</Label>
+              <Select value={keepPolicy} onValueChange={(value) => setKeepPolicy(value as KeepPolicy | "none")}>
+                <SelectTrigger id="batch-keep-policy">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="none">{t("batchDedup.keepPolicyNone")}</SelectItem>
+                  {KEEP_POLICIES.map((policy) => (
+                    <SelectItem key={policy} value={policy}>{t(`batchDedup.policy.${policy}` as TranslationKey)}</SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+          )}
           <div className="flex items-center gap-2 flex-shrink-0 pt-2 border-t">
             <Checkbox
               id="batch-use-trash"
@@ -223,11 +255,38 @@ export function BatchDeduplicationModal({
               {t("batchDedup.useTrash")}
             </Label>
           </div>
-          {useTrash && !trashDir && (
+          {useTrash && (
+            <div className="flex items-center gap-2 pl-6 flex-shrink-0">
+              <Checkbox
+                id="batch-system-trash"
+                checked={systemTrash}
+                onCheckedChange={(checked) => setSystemTrash(checked === true)}
+              />
+              <Label htmlFor="batch-system-trash" className="text-sm cursor-pointer">
+                {t("batchDedup.useSystemTrash")}
+              </Label>
+            </div>
+          )}
+          {useTrash && !systemTrash && !trashDir && (
             <p className="text-xs text-destructive flex-shrink-0">
               {t("batchDedup.trashNotConfigured")}
             </p>
           )}
+          <div className="flex items-center gap-2 flex-shrink-0">
+            <Checkbox
+              id="batch-verify"
+              checked={verify}
+              onCheckedChange={(checked) => setVerify(checked === true)}
+            />
+            <Label htmlFor="batch-verify" className="text-sm cursor-pointer">
+              {t("batchDedup.verify")}
+            </Label>
+          </div>
+          {!isCompleted && (
+            <div className="flex-shrink-0">
+              <ReplaceModeSelect id="batch-replace" value={replace} onChange={setReplace} />
+            </div>
+          )}
         </div>
         <DialogFooter className="flex-shrink-0">
           {isCompleted ? (
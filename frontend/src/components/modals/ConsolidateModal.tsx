@@ -0,0 +1,147 @@
+import { useEffect, useState } from "react"
+import {
+  Dialog, DialogContent, DialogHeader, DialogTitle, DialogDescription, DialogFooter,
+} from "@/components/ui/dialog"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Checkbox } from "@/components/ui/checkbox"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import { consolidate } from "@/api/endpoints"
+import { useSettings } from "@/providers/useSettings"
+import { useTranslation, type TranslationKey } from "@/i18n"
+import { KEEP_POLICIES } from "@/lib/constants"
+import type { KeepPolicy } from "@/types"
+
+interface ConsolidateModalProps {
+  open: boolean
+  onOpenChange: (open: boolean) => void
+  onSuccess: (message: string) => void
+  onError: (message: string) => void
+  onComplete: () => void
+}
+
+// ConsolidateModal moves the kept file of each group into one library folder and
+// removes the other copies
+export function ConsolidateModal({ open, onOpenChange, onSuccess, onError, onComplete }: ConsolidateModalProps) {
+  const [destination, setDestination] = useState("")
+  const [byDate, setByDate] = useState(true)
+  const [keeper, setKeeper] = useState<KeepPolicy | "selection">("keep-oldest")
+  const [useTrash, setUseTrash] = useState(true)
+  const [systemTrash, setSystemTrash] = useState(false)
+  const [verify, setVerify] = useState(true)
+  const [isSubmitting, setIsSubmitting] = useState(false)
+  const { trashDir } = useSettings()
+  const { t } = useTranslation()
+
+  useEffect(() => {
+    if (open) {
+      setUseTrash(true)
+      setVerify(true)
+    }
+  }, [open])
+
+  const handleApply = async () => {
+    const path = destination.trim()
+    if (!path) {
+      onError(t("consolidate.errorNoDestination"))
+      return
+    }
+    if (!useTrash || (!systemTrash && !trashDir)) {
+      if (!window.confirm(t("batchDedup.confirmPermanent"))) {
+        return
+      }
+    } else if (!window.confirm(t("consolidate.confirm", { path }))) {
+      return
+    }
+
+    setIsSubmitting(true)
+    try {
+      const result = await consolidate({
+        destination: path,
+        byDate,
+        keepRules: keeper === "selection" ? undefined : [{ policy: keeper }],
+        useSelection: keeper === "selection",
+        trashDir: useTrash && !systemTrash ? trashDir : "",
+        systemTrash: useTrash && systemTrash,
+        verify,
+      })
+      let message = result.failed > 0
+        ? t("consolidate.successWithFailed", { moved: result.moved, count: result.success, failed: result.failed })
+        : t("consolidate.success", { moved: result.moved, count: result.success })
+      if (result.protectedFiles?.length) {
+        message += " " + t("deleteFiles.protectedSkipped", { count: result.protectedFiles.length })
+      }
+      onSuccess(message)
+      onComplete()
+      onOpenChange(false)
+    } catch (err) {
+      onError(err instanceof Error ? err.message : t("consolidate.errorFailed"))
+    } finally {
+      setIsSubmitting(false)
+    }
+  }
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-w-xl">
+        <DialogHeader>
+          <DialogTitle>{t("consolidate.title")}</DialogTitle>
+          <DialogDescription>{t("consolidate.description")}</DialogDescription>
+        </DialogHeader>
+        <div className="space-y-4">
+          <div className="space-y-1">
+            <Label htmlFor="consolidate-destination" className="text-sm">{t("consolidate.destination")}</Label>
+            <Input
+              id="consolidate-destination"
+              className="font-mono text-xs"
+              value={destination}
+              onChange={(e) => setDestination(e.target.value)}
+            />
+          </div>
+          <div className="flex items-center gap-2">
+            <Checkbox id="consolidate-by-date" checked={byDate} onCheckedChange={(checked) => setByDate(checked === true)} />
+            <Label htmlFor="consolidate-by-date" className="text-sm cursor-pointer">{t("consolidate.byDate")}</Label>
+          </div>
+          <div className="space-y-1">
+            <Label htmlFor="consolidate-keeper" className="text-sm">{t("consolidate.keeper")}</Label>
+            <Select value={keeper} onValueChange={(value) => setKeeper(value as KeepPolicy | "selection")}>
+              <SelectTrigger id="consolidate-keeper">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                {KEEP_POLICIES.map((policy) => (
+                  <SelectItem key={policy} value={policy}>{t(`batchDedup.policy.${policy}` as TranslationKey)}</SelectItem>
+                ))}
+                <SelectItem value="selection">{t("consolidate.useSelection")}</SelectItem>
+              </SelectContent>
+            </Select>
+          </div>
+          <div className="flex items-center gap-2 pt-2 border-t">
+            <Checkbox id="consolidate-use-trash" checked={useTrash} onCheckedChange={(checked) => setUseTrash(checked === true)} />
+            <Label htmlFor="consolidate-use-trash" className="text-sm cursor-pointer">{t("batchDedup.useTrash")}</Label>
+          </div>
+          {useTrash && (
+            <div className="flex items-center gap-2 pl-6">
+              <Checkbox id="consolidate-system-trash" checked={systemTrash} onCheckedChange={(checked) => setSystemTrash(checked === true)} />
+              <Label htmlFor="consolidate-system-trash" className="text-sm cursor-pointer">{t("batchDedup.useSystemTrash")}</Label>
+            </div>
+          )}
+          {useTrash && !systemTrash && !trashDir && (
+            <p className="text-xs text-destructive">{t("batchDedup.trashNotConfigured")}</p>
+          )}
+          <div className="flex items-center gap-2">
+            <Checkbox id="consolidate-verify" checked={verify} onCheckedChange={(checked) => setVerify(checked === true)} />
+            <Label htmlFor="consolidate-verify" className="text-sm cursor-pointer">{t("batchDedup.verify")}</Label>
+          </div>
+        </div>
+        <DialogFooter>
+          <Button variant="outline" onClick={() => onOpenChange(false)} disabled={isSubmitting}>{t("common.close")}</Button>
+          <Button variant="destructive" onClick={handleApply} disabled={isSubmitting}>
+            {isSubmitting ? t("batchDedup.applying") : t("consolidate.apply")}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  )
+}
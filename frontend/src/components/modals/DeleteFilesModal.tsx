@@ -3,9 +3,13 @@ import { Dialog, DialogContent, DialogHeader, DialogTitle, DialogDescription, Di
 import { Button } from "@/components/ui/button"
 import { Checkbox } from "@/components/ui/checkbox"
 import { Label } from "@/components/ui/label"
-import { deleteFiles } from "@/api/endpoints"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import { ReplaceModeSelect } from "@/components/modals/ReplaceModeSelect"
+import { deleteFiles, downloadScript } from "@/api/endpoints"
 import { useSettings } from "@/providers/useSettings"
 import { useTranslation } from "@/i18n"
+import { SCRIPT_ENCODINGS } from "@/lib/constants"
+import type { ReplaceMode, ScriptType } from "@/types"
 
 interface DeleteFilesModalProps {
   open: boolean
@@ -25,12 +29,18 @@ export function DeleteFilesModal({
   onComplete,
 }: DeleteFilesModalProps) {
   const [useTrash, setUseTrash] = useState(true)
+  const [systemTrash, setSystemTrash] = useState(false)
+  const [verify, setVerify] = useState(false)
+  const [replace, setReplace] = useState<ReplaceMode | undefined>(undefined)
   const [isSubmitting, setIsSubmitting] = useState(false)
+  const [scriptType, setScriptType] = useState<ScriptType>("bash")
+  const [scriptEncoding, setScriptEncoding] = useState("default")
+  const [lineEndings, setLineEndings] = useState<"default" | "lf" | "crlf">("default")
   const { trashDir } = useSettings()
   const { t } = useTranslation()
 
   const handleDelete = async () => {
-    if (!useTrash || !trashDir) {
+    if (!useTrash || (!systemTrash && !trashDir)) {
       if (!window.confirm(t("deleteFiles.confirmPermanent"))) {
         return
       }
@@ -40,13 +50,19 @@ export function DeleteFilesModal({
     try {
       const result = await deleteFiles({
         filePaths: selectedPaths,
-        trashDir: useTrash ? trashDir : "",
+        trashDir: useTrash && !systemTrash ? trashDir : "",
+        systemTrash: useTrash && systemTrash,
+        verify,
+        replace,
       })
       onOpenChange(false)
-      const message =
+      let message =
         result.failed > 0
           ? t("deleteFiles.successWithFailed", { count: result.success, failed: result.failed })
           : t("deleteFiles.success", { count: result.success })
+      if (result.protectedFiles?.length) {
+        message += " " + t("deleteFiles.protectedSkipped", { count: result.protectedFiles.length })
+      }
       onSuccess(message)
       onComplete()
     } catch (err) {
@@ -56,6 +72,28 @@ export function DeleteFilesModal({
     }
   }
 
+  // Scripts run on the machine holding the files, so they use the trash folder and never the system trash
+  const handleDownloadScript = async (restore: boolean) => {
+    try {
+      const { blob, filename } = await downloadScript({
+        filePaths: selectedPaths,
+        trashDir: useTrash ? trashDir : "",
+        scriptType,
+        encoding: scriptEncoding === "default" ? undefined : scriptEncoding,
+        lineEndings: lineEndings === "default" ? undefined : lineEndings,
+        restore,
+      })
+      const url = URL.createObjectURL(blob)
+      const link = document.createElement("a")
+      link.href = url
+      link.download = filename
+      link.click()
+      URL.revokeObjectURL(url)
+    } catch (err) {
+      onError(err instanceof Error ? err.message : t("deleteFiles.scriptFailed"))
+    }
+  }
+
   return (
     <Dialog open={open} onOpenChange={onOpenChange}>
       <DialogContent>
@@ -79,11 +117,91 @@ export function DeleteFilesModal({
               {t("deleteFiles.useTrash")}
             </Label>
           </div>
-          {useTrash && !trashDir && (
+          {useTrash && (
+            <div className="flex items-center gap-2 pl-6">
+              <Checkbox
+                id="delete-system-trash"
+                checked={systemTrash}
+                onCheckedChange={(checked) => setSystemTrash(checked === true)}
+              />
+              <Label htmlFor="delete-system-trash" className="text-sm cursor-pointer">
+                {t("deleteFiles.useSystemTrash")}
+              </Label>
+            </div>
+          )}
+          {useTrash && !systemTrash && !trashDir && (
             <p className="text-xs text-destructive">
               {t("deleteFiles.trashNotConfigured")}
             </p>
           )}
+          <div className="flex items-center gap-2">
+            <Checkbox
+              id="delete-verify"
+              checked={verify}
+              onCheckedChange={(checked) => setVerify(checked === true)}
+            />
+            <Label htmlFor="delete-verify" className="text-sm cursor-pointer">
+              {t("deleteFiles.verify")}
+            </Label>
+          </div>
+          <ReplaceModeSelect id="delete-replace" value={replace} onChange={setReplace} />
+          <div className="space-y-2 border-t pt-3">
+            <p className="text-xs text-muted-foreground">{t("deleteFiles.scriptHint")}</p>
+            <div className="flex flex-wrap items-center gap-2">
+              <Select
+                value={scriptType}
+                onValueChange={(v) => {
+                  setScriptType(v as ScriptType)
+                  // Shells cannot run scripts with CRLF line endings
+                  if ((v === "bash" || v === "sh") && lineEndings === "crlf") {
+                    setLineEndings("default")
+                  }
+                }}
+              >
+                <SelectTrigger className="w-36 h-8 text-xs">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="bash">bash</SelectItem>
+                  <SelectItem value="sh">POSIX sh</SelectItem>
+                  <SelectItem value="powershell">PowerShell</SelectItem>
+                  <SelectItem value="bat">cmd (.bat)</SelectItem>
+                </SelectContent>
+              </Select>
+              <Select value={scriptEncoding} onValueChange={setScriptEncoding}>
+                <SelectTrigger className="w-36 h-8 text-xs" aria-label={t("deleteFiles.scriptEncoding")}>
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="default">{t("deleteFiles.scriptEncodingDefault")}</SelectItem>
+                  {SCRIPT_ENCODINGS.map((name) => (
+                    <SelectItem key={name} value={name}>{name}</SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+              <Select value={lineEndings} onValueChange={(v) => setLineEndings(v as "default" | "lf" | "crlf")}>
+                <SelectTrigger className="w-28 h-8 text-xs" aria-label={t("deleteFiles.lineEndings")}>
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="default">{t("deleteFiles.lineEndingsDefault")}</SelectItem>
+                  <SelectItem value="lf">LF</SelectItem>
+                  <SelectItem value="crlf" disabled={scriptType === "bash" || scriptType === "sh"}>CRLF</SelectItem>
+                </SelectContent>
+              </Select>
+              <Button size="sm" variant="outline" onClick={() => handleDownloadScript(false)} disabled={isSubmitting}>
+                {t("deleteFiles.downloadScript")}
+              </Button>
+              <Button
+                size="sm"
+                variant="outline"
+                onClick={() => handleDownloadScript(true)}
+                disabled={isSubmitting || !useTrash || !trashDir}
+              >
+                {t("deleteFiles.downloadRestoreScript")}
+              </Button>
+            </div>
+          </div>
         </div>
         <DialogFooter>
           <Button variant="outline" onClick={() => onOpenChange(false)} disabled={isSubmitting}>
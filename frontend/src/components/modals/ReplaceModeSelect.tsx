@@ -0,0 +1,35 @@
+import { Label } from "@/components/ui/label"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import { useTranslation } from "@/i18n"
+import type { ReplaceMode } from "@/types"
+
+interface ReplaceModeSelectProps {
+  id: string
+  value: ReplaceMode | undefined
+  onChange: (value: ReplaceMode | undefined) => void
+}
+
+// ReplaceModeSelect chooses what is left in place of removed duplicates
+export function ReplaceModeSelect({ id, value, onChange }: ReplaceModeSelectProps) {
+  const { t } = useTranslation()
+
+  return (
+    <div className="space-y-1">
+      <Label htmlFor={id} className="text-sm">{t("replaceMode.label")}</Label>
+      <Select
+        value={value ?? "none"}
+        onValueChange={(v) => onChange(v === "none" ? undefined : (v as ReplaceMode))}
+      >
+        <SelectTrigger id={id}>
+          <SelectValue />
+        </SelectTrigger>
+        <SelectContent>
+          <SelectItem value="none">{t("replaceMode.none")}</SelectItem>
+          <SelectItem value="relative-symlink">{t("replaceMode.relativeSymlink")}</SelectItem>
+          <SelectItem value="absolute-symlink">{t("replaceMode.absoluteSymlink")}</SelectItem>
+          <SelectItem value="reflink">{t("replaceMode.reflink")}</SelectItem>
+        </SelectContent>
+      </Select>
+    </div>
+  )
+}
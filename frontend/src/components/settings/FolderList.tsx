@@ -9,17 +9,19 @@ import {
   DialogDescription,
   DialogFooter,
 } from "@/components/ui/dialog"
-import { Folder, Trash2, FileImage } from "lucide-react"
+import { Folder, Trash2, FileImage, RefreshCw } from "lucide-react"
 import { useTranslation } from "@/i18n"
 import type { GalleryFolderDTO } from "@/types"
 
 interface FolderListProps {
   folders: GalleryFolderDTO[]
   onRemove: (id: number) => Promise<void>
+  onRescan: (path: string) => Promise<void>
+  rescanDisabled: boolean
   isLoading: boolean
 }
 
-export function FolderList({ folders, onRemove, isLoading }: FolderListProps) {
+export function FolderList({ folders, onRemove, onRescan, rescanDisabled, isLoading }: FolderListProps) {
   const [removingId, setRemovingId] = useState<number | null>(null)
   const [confirmFolder, setConfirmFolder] = useState<GalleryFolderDTO | null>(null)
   const { t } = useTranslation()
@@ -73,6 +75,17 @@ export function FolderList({ folders, onRemove, isLoading }: FolderListProps) {
                 <span>{t("folderList.added", { date: folder.createdAt })}</span>
               </div>
             </div>
+            <Button
+              variant="ghost"
+              size="sm"
+              className="shrink-0"
+              title={t("folderList.rescan")}
+              aria-label={t("folderList.rescan")}
+              onClick={() => onRescan(folder.path)}
+              disabled={rescanDisabled}
+            >
+              <RefreshCw className="h-3.5 w-3.5" />
+            </Button>
             <Button
               variant="ghost"
               size="sm"
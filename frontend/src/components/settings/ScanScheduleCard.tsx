@@ -0,0 +1,106 @@
+import { useCallback, useEffect, useState } from "react"
+import { toast } from "sonner"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { fetchSchedule, updateSchedule, fetchScanRuns } from "@/api/endpoints"
+import { CalendarClock } from "lucide-react"
+import { useTranslation } from "@/i18n"
+import type { ScanRunDTO } from "@/types"
+
+export function ScanScheduleCard() {
+  const { t } = useTranslation()
+
+  const [schedule, setSchedule] = useState("")
+  const [scheduleInput, setScheduleInput] = useState("")
+  const [nextRun, setNextRun] = useState<string | undefined>()
+  const [runs, setRuns] = useState<ScanRunDTO[]>([])
+  const [isSaving, setIsSaving] = useState(false)
+
+  const load = useCallback(() => {
+    fetchSchedule()
+      .then((s) => {
+        setSchedule(s.schedule)
+        setScheduleInput(s.schedule)
+        setNextRun(s.nextRun)
+      })
+      .catch((err) => console.error("Failed to load scan schedule:", err))
+    fetchScanRuns(10)
+      .then((r) => setRuns(r.runs))
+      .catch((err) => console.error("Failed to load scan runs:", err))
+  }, [])
+
+  useEffect(() => {
+    load()
+  }, [load])
+
+  const handleSave = useCallback(async () => {
+    setIsSaving(true)
+    try {
+      const s = await updateSchedule({ schedule: scheduleInput.trim() })
+      setSchedule(s.schedule)
+      setScheduleInput(s.schedule)
+      setNextRun(s.nextRun)
+      toast.success(t("api.schedule.saved"))
+    } catch (err) {
+      toast.error(err instanceof Error ? err.message : t("api.schedule.save_failed"))
+    } finally {
+      setIsSaving(false)
+    }
+  }, [scheduleInput, t])
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <CalendarClock className="h-5 w-5" />
+          {t("schedule.title")}
+        </CardTitle>
+        <CardDescription>{t("schedule.description")}</CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        <div className="space-y-2">
+          <Label htmlFor="scan-schedule-input">{t("schedule.cronLabel")}</Label>
+          <div className="flex gap-2">
+            <Input
+              id="scan-schedule-input"
+              placeholder="0 3 * * *"
+              value={scheduleInput}
+              onChange={(e) => setScheduleInput(e.target.value)}
+              className="flex-1 font-mono text-sm"
+            />
+            <Button onClick={handleSave} disabled={isSaving || scheduleInput.trim() === schedule}>
+              {isSaving ? t("common.saving") : t("trash.save")}
+            </Button>
+          </div>
+          <p className="text-sm text-muted-foreground">
+            {schedule ? t("schedule.nextRun", { time: nextRun ?? "-" }) : t("schedule.disabled")}
+          </p>
+        </div>
+
+        <div className="space-y-2">
+          <h3 className="text-sm font-medium text-muted-foreground">{t("schedule.recentRuns")}</h3>
+          {runs.length === 0 ? (
+            <p className="text-sm text-muted-foreground">{t("schedule.noRuns")}</p>
+          ) : (
+            <ul className="space-y-1 text-sm">
+              {runs.map((run) => (
+                <li key={run.id} className="flex items-center justify-between rounded-md border px-3 py-1.5">
+                  <span className="font-mono">{run.startedAt}</span>
+                  <span className="text-muted-foreground">
+                    {t(run.trigger === "scheduled" ? "schedule.triggerScheduled" : "schedule.triggerManual")}
+                    {" · "}
+                    {run.status === "running"
+                      ? t("schedule.statusRunning")
+                      : t("schedule.filesProcessed", { count: run.filesProcessed })}
+                  </span>
+                </li>
+              ))}
+            </ul>
+          )}
+        </div>
+      </CardContent>
+    </Card>
+  )
+}
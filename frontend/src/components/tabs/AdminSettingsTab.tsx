@@ -9,6 +9,7 @@ import { Checkbox } from "@/components/ui/checkbox"
 import { AddFolderForm } from "@/components/settings/AddFolderForm"
 import { FolderList } from "@/components/settings/FolderList"
 import { ScanProgressBanner } from "@/components/ScanProgressBanner"
+import { ScanScheduleCard } from "@/components/settings/ScanScheduleCard"
 import { useGalleryFolders } from "@/hooks/useGalleryFolders"
 import { useScanStatus } from "@/hooks/useScanStatus"
 import { fetchTrashInfo, cleanTrash, fetchSettings, updateSettings, fetchOCRStatus, startOcrClassification, startOcrClassificationChanges, stopOcrClassification, fetchOcrClassificationStatus, triggerScan, triggerFastScan, fetchLlmSettings, updateLlmSettings, fetchLlmModels, fetchThumbnailCacheStats, enableThumbnailCache, disableThumbnailCache, invalidateAllThumbnails } from "@/api/endpoints"
@@ -377,6 +378,23 @@ export function AdminSettingsTab() {
     }
   }, [folders.length, startPolling, setOnScanComplete, refetch, t])
 
+  const handleRescanFolder = useCallback(
+    async (path: string) => {
+      try {
+        await triggerScan(path)
+        toast.success(t("settings.toastFolderRescanStarted", { path }))
+        setOnScanComplete(() => {
+          refetch()
+          toast.success(t("settings.toastRescanComplete"))
+        })
+        startPolling()
+      } catch (err) {
+        toast.error(err instanceof Error ? err.message : t("settings.toastRescanFailed"))
+      }
+    },
+    [startPolling, setOnScanComplete, refetch, t]
+  )
+
   const handleFastRescanAll = useCallback(async () => {
     if (folders.length === 0) {
       toast.error(t("settings.toastNoFolders"))
@@ -454,6 +472,8 @@ export function AdminSettingsTab() {
               <FolderList
                 folders={folders}
                 onRemove={handleRemove}
+                onRescan={handleRescanFolder}
+                rescanDisabled={status.scanning}
                 isLoading={isLoading}
               />
             </CardContent>
@@ -461,6 +481,9 @@ export function AdminSettingsTab() {
         </>
       )}
 
+      {/* Scan Schedule - Admin Only */}
+      {isAdmin && <ScanScheduleCard />}
+
       {/* Trash Settings - Admin Only */}
       {isAdmin && (
         <Card>
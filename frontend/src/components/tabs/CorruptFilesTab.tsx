@@ -0,0 +1,110 @@
+import { useCallback, useEffect, useState } from "react"
+import { toast } from "sonner"
+import { FileWarning, Trash2 } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { Checkbox } from "@/components/ui/checkbox"
+import { DeleteFilesModal } from "@/components/modals/DeleteFilesModal"
+import { fetchCorruptFiles } from "@/api/endpoints"
+import { useTranslation } from "@/i18n"
+import { formatSize } from "@/lib/utils"
+import type { CorruptFilesResponse } from "@/types"
+
+// CorruptFilesTab lists the empty and undecodable files found by scans and deletes the selected ones
+export function CorruptFilesTab() {
+  const { t } = useTranslation()
+  const [data, setData] = useState<CorruptFilesResponse | null>(null)
+  const [selected, setSelected] = useState<Set<string>>(new Set())
+  const [deleteOpen, setDeleteOpen] = useState(false)
+
+  const load = useCallback(() => {
+    fetchCorruptFiles()
+      .then((r) => {
+        setData(r)
+        setSelected(new Set())
+      })
+      .catch((err) => console.error("Failed to load corrupt files:", err))
+  }, [])
+
+  useEffect(() => {
+    load()
+  }, [load])
+
+  const toggle = useCallback((path: string) => {
+    setSelected((prev) => {
+      const next = new Set(prev)
+      if (next.has(path)) {
+        next.delete(path)
+      } else {
+        next.add(path)
+      }
+      return next
+    })
+  }, [])
+
+  const files = data?.files ?? []
+  const allSelected = files.length > 0 && selected.size === files.length
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <FileWarning className="h-5 w-5" />
+          {t("corruptFiles.title")}
+        </CardTitle>
+        <CardDescription>{t("corruptFiles.description")}</CardDescription>
+      </CardHeader>
+      <CardContent>
+        {data && files.length === 0 && (
+          <p className="text-sm text-muted-foreground">{t("corruptFiles.empty")}</p>
+        )}
+        {files.length > 0 && (
+          <div className="mb-2 flex items-center gap-4">
+            <label className="flex items-center gap-2 text-sm">
+              <Checkbox
+                checked={allSelected}
+                onCheckedChange={() => setSelected(allSelected ? new Set() : new Set(files.map((f) => f.path)))}
+              />
+              {t("corruptFiles.selectAll")}
+            </label>
+            <span className="flex-1 text-sm text-muted-foreground">
+              {t("corruptFiles.summary", { count: data!.total, size: formatSize(data!.totalSize) })}
+              {data!.total > files.length && <span className="ml-3">{t("corruptFiles.shown", { shown: files.length })}</span>}
+            </span>
+            <Button
+              size="sm"
+              variant="destructive"
+              className="gap-2"
+              disabled={selected.size === 0}
+              onClick={() => setDeleteOpen(true)}
+            >
+              <Trash2 className="h-4 w-4" />
+              {t("corruptFiles.deleteSelected", { count: selected.size })}
+            </Button>
+          </div>
+        )}
+        <ul className="divide-y">
+          {files.map((file) => (
+            <li key={file.path} className="flex items-center gap-3 py-2">
+              <Checkbox checked={selected.has(file.path)} onCheckedChange={() => toggle(file.path)} />
+              <div className="min-w-0 flex-1 text-sm">
+                <p className="truncate font-medium" title={file.path}>{file.fileName}</p>
+                <p className="truncate text-xs text-muted-foreground">{file.dirPath}</p>
+              </div>
+              <span className="shrink-0 text-xs text-muted-foreground">{formatSize(file.size)}</span>
+              <span className="shrink-0 text-xs text-muted-foreground">{file.modTime}</span>
+            </li>
+          ))}
+        </ul>
+      </CardContent>
+      <DeleteFilesModal
+        open={deleteOpen}
+        onOpenChange={setDeleteOpen}
+        selectedPaths={[...selected]}
+        onSuccess={(message) => toast.success(message)}
+        onError={(message) => toast.error(message)}
+        onComplete={load}
+      />
+    </Card>
+  )
+}
@@ -2,24 +2,33 @@ import { useCallback, useMemo, useState } from "react"
 import { toast } from "sonner"
 import { Toolbar } from "@/components/layout/Toolbar"
 import { DuplicateGroupList } from "@/components/duplicates/DuplicateGroupList"
+import { DuplicateFilterBar } from "@/components/duplicates/DuplicateFilterBar"
+import { GroupDetailDialog } from "@/components/duplicates/GroupDetailDialog"
 import { Pagination } from "@/components/pagination/Pagination"
 import { EmptyState } from "@/components/EmptyState"
 import { ScanProgressBanner } from "@/components/ScanProgressBanner"
 import { DeleteFilesModal } from "@/components/modals/DeleteFilesModal"
 import { BatchDeduplicationModal } from "@/components/modals/BatchDeduplicationModal"
+import { ConsolidateModal } from "@/components/modals/ConsolidateModal"
 import { useDuplicates } from "@/hooks/useDuplicates"
 import { useSelection } from "@/hooks/useSelection"
 import { useScanStatus } from "@/hooks/useScanStatus"
-import { triggerScan } from "@/api/endpoints"
+import { triggerScan, autoSelect, ignoreGroup, reviewGroup, setProtected, duplicatesCsvUrl, duplicatesHtmlUrl } from "@/api/endpoints"
 import { DEFAULT_PAGE_SIZE } from "@/lib/constants"
 import { Skeleton } from "@/components/ui/skeleton"
 import { useTranslation } from "@/i18n"
-import type { FileDTO } from "@/types"
+import type { DuplicateFilters, DuplicateMedia, DuplicateSort, FileDTO, KeepPolicy, ReviewFilter } from "@/types"
 
-export function DeduplicationTab() {
+interface DeduplicationTabProps {
+  media?: DuplicateMedia
+}
+
+export function DeduplicationTab({ media = "image" }: DeduplicationTabProps) {
   const [page, setPage] = useState(1)
   const [pageSize, setPageSize] = useState(DEFAULT_PAGE_SIZE)
-  const { data, isLoading, error, refetch } = useDuplicates(page, pageSize)
+  const [sort, setSort] = useState<DuplicateSort | undefined>()
+  const [filters, setFilters] = useState<DuplicateFilters>({})
+  const { data, isLoading, error, refetch } = useDuplicates(page, pageSize, media, sort, filters)
   const selection = useSelection()
   const { status, startPolling, setOnScanComplete } = useScanStatus()
   const { t } = useTranslation()
@@ -27,6 +36,8 @@ export function DeduplicationTab() {
   // Modals
   const [deleteModalOpen, setDeleteModalOpen] = useState(false)
   const [batchModalOpen, setBatchModalOpen] = useState(false)
+  const [consolidateModalOpen, setConsolidateModalOpen] = useState(false)
+  const [detailHash, setDetailHash] = useState<string | null>(null)
 
   // Collect all files from current page for folder selection
   const allFiles: FileDTO[] = useMemo(() => {
@@ -49,11 +60,85 @@ export function DeduplicationTab() {
     }
   }, [startPolling, setOnScanComplete, refetch, selection, t])
 
+  // Pre-selects the files the policy would remove, across all pages, for review
+  const handleAutoSelect = useCallback(
+    async (policy: KeepPolicy) => {
+      try {
+        const result = await autoSelect({ keepRules: [{ policy }], media, ...filters })
+        selection.reset()
+        selection.selectAll(result.groups.flatMap((g) => g.remove))
+        toast.success(t("dedup.toastAutoSelected", { count: result.totalRemove, size: result.reclaimableHuman }))
+      } catch (err) {
+        toast.error(err instanceof Error ? err.message : t("dedup.toastAutoSelectFailed"))
+      }
+    },
+    [media, filters, selection, t]
+  )
+
+  // Hides a group of intentional copies; it can be listed again on the ignored groups page
+  const handleIgnoreGroup = useCallback(
+    async (hash: string) => {
+      try {
+        await ignoreGroup(hash)
+        toast.success(t("duplicateGroup.toastIgnored"))
+        refetch()
+      } catch (err) {
+        toast.error(err instanceof Error ? err.message : t("api.scan.ignore_failed"))
+      }
+    },
+    [refetch, t]
+  )
+
+  // The review status is kept across sessions, so the next session can list only unreviewed groups
+  const handleReview = useCallback(
+    async (hash: string, status: ReviewFilter) => {
+      try {
+        await reviewGroup(hash, status)
+        refetch()
+      } catch (err) {
+        toast.error(err instanceof Error ? err.message : t("api.scan.review_failed"))
+      }
+    },
+    [refetch, t]
+  )
+
+  // Pinned files are left in place by every delete request
+  const handleToggleProtected = useCallback(
+    async (path: string, isProtected: boolean) => {
+      try {
+        await setProtected([path], isProtected)
+        toast.success(isProtected ? t("fileItem.toastProtected") : t("fileItem.toastUnprotected"))
+        refetch()
+      } catch (err) {
+        toast.error(err instanceof Error ? err.message : t("api.scan.protect_failed"))
+      }
+    },
+    [refetch, t]
+  )
+
+  const handleExportCsv = useCallback(() => {
+    window.location.href = duplicatesCsvUrl()
+  }, [])
+
+  const handleExportHtml = useCallback(() => {
+    window.location.href = duplicatesHtmlUrl()
+  }, [])
+
   const handlePageSizeChange = useCallback((size: number) => {
     setPageSize(size)
     setPage(1)
   }, [])
 
+  const handleSortChange = useCallback((newSort: DuplicateSort | undefined) => {
+    setSort(newSort)
+    setPage(1)
+  }, [])
+
+  const handleFiltersChange = useCallback((newFilters: DuplicateFilters) => {
+    setFilters(newFilters)
+    setPage(1)
+  }, [])
+
   const handlePageChange = useCallback((newPage: number) => {
     setPage(newPage)
   }, [])
@@ -84,6 +169,8 @@ export function DeduplicationTab() {
         selectedCount={selection.selectedCount}
         pageSize={pageSize}
         onPageSizeChange={handlePageSizeChange}
+        sort={sort}
+        onSortChange={handleSortChange}
         onRescan={handleRescan}
         onResetSelection={selection.reset}
         onOpenDeleteFiles={() => {
@@ -94,9 +181,15 @@ export function DeduplicationTab() {
           setDeleteModalOpen(true)
         }}
         onOpenBatchDedup={() => setBatchModalOpen(true)}
+        onOpenConsolidate={() => setConsolidateModalOpen(true)}
+        onAutoSelect={handleAutoSelect}
+        onExportCsv={handleExportCsv}
+        onExportHtml={handleExportHtml}
         isScanning={status.scanning}
       />
 
+      <DuplicateFilterBar filters={filters} onChange={handleFiltersChange} />
+
       <ScanProgressBanner status={status} />
 
       {error && (
@@ -119,6 +212,10 @@ export function DeduplicationTab() {
             isSelected={selection.isSelected}
             onToggleFile={selection.toggle}
             onSelectFolder={handleSelectFolder}
+            onOpenDetails={setDetailHash}
+            onIgnore={handleIgnoreGroup}
+            onToggleProtected={handleToggleProtected}
+            onReview={handleReview}
           />
           <Pagination
             currentPage={data.currentPage}
@@ -141,6 +238,14 @@ export function DeduplicationTab() {
         onComplete={handleMutationComplete}
       />
 
+      <GroupDetailDialog
+        hash={detailHash}
+        onClose={() => setDetailHash(null)}
+        onSuccess={handleSuccess}
+        onError={handleError}
+        onComplete={handleMutationComplete}
+      />
+
       <BatchDeduplicationModal
         open={batchModalOpen}
         onOpenChange={setBatchModalOpen}
@@ -148,6 +253,14 @@ export function DeduplicationTab() {
         onError={handleError}
         onComplete={handleMutationComplete}
       />
+
+      <ConsolidateModal
+        open={consolidateModalOpen}
+        onOpenChange={setConsolidateModalOpen}
+        onSuccess={handleSuccess}
+        onError={handleError}
+        onComplete={handleMutationComplete}
+      />
     </div>
   )
 }
@@ -0,0 +1,116 @@
+import { useCallback, useEffect, useState } from "react"
+import { toast } from "sonner"
+import { History, RotateCcw } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { fetchDeletions, restoreDeletion } from "@/api/endpoints"
+import { useTranslation } from "@/i18n"
+import { formatSize } from "@/lib/utils"
+import type { DeletionBatchDTO } from "@/types"
+
+// DeletionsTab lists recent moves to the trash folder and restores them on request
+export function DeletionsTab() {
+  const { t } = useTranslation()
+  const [batches, setBatches] = useState<DeletionBatchDTO[]>([])
+  const [isLoading, setIsLoading] = useState(true)
+  const [restoring, setRestoring] = useState<string | null>(null)
+  const [expanded, setExpanded] = useState<string | null>(null)
+
+  const load = useCallback(() => {
+    setIsLoading(true)
+    fetchDeletions(50)
+      .then((r) => setBatches(r.batches))
+      .catch((err) => console.error("Failed to load deletions:", err))
+      .finally(() => setIsLoading(false))
+  }, [])
+
+  useEffect(() => {
+    load()
+  }, [load])
+
+  const handleRestore = useCallback(
+    async (batchId: string) => {
+      setRestoring(batchId)
+      try {
+        const result = await restoreDeletion(batchId)
+        if (result.failed > 0) {
+          toast.warning(t("deletions.restoredWithFailed", { count: result.restored, failed: result.failed }))
+        } else {
+          toast.success(t("deletions.restored", { count: result.restored }))
+        }
+        load()
+      } catch (err) {
+        toast.error(err instanceof Error ? err.message : t("api.trash.restore_failed"))
+      } finally {
+        setRestoring(null)
+      }
+    },
+    [load, t]
+  )
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <History className="h-5 w-5" />
+          {t("deletions.title")}
+        </CardTitle>
+        <CardDescription>{t("deletions.description")}</CardDescription>
+      </CardHeader>
+      <CardContent>
+        {!isLoading && batches.length === 0 && (
+          <p className="text-sm text-muted-foreground">{t("deletions.empty")}</p>
+        )}
+        <ul className="divide-y">
+          {batches.map((batch) => {
+            const pending = batch.fileCount - batch.restoredCount
+            return (
+              <li key={batch.batchId} className="py-3">
+                <div className="flex items-center gap-4">
+                  <button
+                    type="button"
+                    className="flex-1 text-left text-sm"
+                    onClick={() => setExpanded(expanded === batch.batchId ? null : batch.batchId)}
+                  >
+                    <span className="font-medium">{batch.deletedAt}</span>
+                    <span className="ml-3 text-muted-foreground">
+                      {t("deletions.summary", { count: batch.fileCount, size: formatSize(batch.totalSize) })}
+                    </span>
+                    {batch.restoredCount > 0 && (
+                      <span className="ml-3 text-muted-foreground">
+                        {t("deletions.restoredCount", { count: batch.restoredCount })}
+                      </span>
+                    )}
+                  </button>
+                  <Button
+                    size="sm"
+                    variant="outline"
+                    className="gap-2"
+                    disabled={pending === 0 || restoring !== null}
+                    onClick={() => handleRestore(batch.batchId)}
+                  >
+                    <RotateCcw className="h-4 w-4" />
+                    {restoring === batch.batchId ? t("deletions.restoring") : t("deletions.restore")}
+                  </Button>
+                </div>
+                {expanded === batch.batchId && (
+                  <ul className="mt-2 space-y-1 pl-4">
+                    {batch.files.map((file) => (
+                      <li
+                        key={file.trashPath}
+                        className={file.restoredAt ? "truncate text-xs text-muted-foreground line-through" : "truncate text-xs"}
+                        title={file.trashPath}
+                      >
+                        {file.originalPath}
+                      </li>
+                    ))}
+                  </ul>
+                )}
+              </li>
+            )
+          })}
+        </ul>
+      </CardContent>
+    </Card>
+  )
+}
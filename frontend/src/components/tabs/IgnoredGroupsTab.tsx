@@ -0,0 +1,79 @@
+import { useCallback, useEffect, useState } from "react"
+import { toast } from "sonner"
+import { EyeOff, Undo2 } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { fetchIgnoredGroups, unignoreGroup } from "@/api/endpoints"
+import { useTranslation } from "@/i18n"
+import type { IgnoredGroupDTO } from "@/types"
+
+// IgnoredGroupsTab lists the groups marked as not duplicates and lists them again on request
+export function IgnoredGroupsTab() {
+  const { t } = useTranslation()
+  const [groups, setGroups] = useState<IgnoredGroupDTO[]>([])
+  const [isLoading, setIsLoading] = useState(true)
+  const [unignoring, setUnignoring] = useState<number | null>(null)
+
+  useEffect(() => {
+    fetchIgnoredGroups()
+      .then((r) => setGroups(r.groups))
+      .catch((err) => console.error("Failed to load ignored groups:", err))
+      .finally(() => setIsLoading(false))
+  }, [])
+
+  const handleUnignore = useCallback(
+    async (id: number) => {
+      setUnignoring(id)
+      try {
+        const result = await unignoreGroup(id)
+        setGroups(result.groups)
+        toast.success(t("ignoredGroups.unignored"))
+      } catch (err) {
+        toast.error(err instanceof Error ? err.message : t("api.scan.ignore_failed"))
+      } finally {
+        setUnignoring(null)
+      }
+    },
+    [t]
+  )
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <EyeOff className="h-5 w-5" />
+          {t("ignoredGroups.title")}
+        </CardTitle>
+        <CardDescription>{t("ignoredGroups.description")}</CardDescription>
+      </CardHeader>
+      <CardContent>
+        {!isLoading && groups.length === 0 && (
+          <p className="text-sm text-muted-foreground">{t("ignoredGroups.empty")}</p>
+        )}
+        <ul className="divide-y">
+          {groups.map((group) => (
+            <li key={group.id} className="flex items-center gap-4 py-3">
+              <div className="min-w-0 flex-1 text-sm">
+                <p className="truncate font-medium" title={group.path}>{group.path}</p>
+                <p className="text-xs text-muted-foreground">
+                  {t("ignoredGroups.summary", { count: group.files, size: group.sizeHuman, time: group.ignoredAt })}
+                  <span className="ml-3 font-mono">{group.hash}</span>
+                </p>
+              </div>
+              <Button
+                size="sm"
+                variant="outline"
+                className="gap-2"
+                disabled={unignoring !== null}
+                onClick={() => handleUnignore(group.id)}
+              >
+                <Undo2 className="h-4 w-4" />
+                {t("ignoredGroups.unignore")}
+              </Button>
+            </li>
+          ))}
+        </ul>
+      </CardContent>
+    </Card>
+  )
+}
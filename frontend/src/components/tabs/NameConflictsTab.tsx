@@ -0,0 +1,95 @@
+import { useCallback, useEffect, useState } from "react"
+import { toast } from "sonner"
+import { FileStack } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { Input } from "@/components/ui/input"
+import { fetchNameConflicts } from "@/api/endpoints"
+import { useTranslation } from "@/i18n"
+import { formatSize } from "@/lib/utils"
+import type { NameConflictsResponse } from "@/types"
+
+// NameConflictsTab lists the file names shared by files of different content, which
+// would overwrite each other when their folders are merged
+export function NameConflictsTab() {
+  const { t } = useTranslation()
+  const [data, setData] = useState<NameConflictsResponse | null>(null)
+  const [dir, setDir] = useState("")
+  const [isLoading, setIsLoading] = useState(true)
+
+  const load = useCallback(
+    (folder: string) => {
+      setIsLoading(true)
+      fetchNameConflicts(folder)
+        .then(setData)
+        .catch((err) => toast.error(err instanceof Error ? err.message : t("api.scan.conflicts_failed")))
+        .finally(() => setIsLoading(false))
+    },
+    [t]
+  )
+
+  useEffect(() => {
+    load("")
+  }, [load])
+
+  const conflicts = data?.conflicts ?? []
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <FileStack className="h-5 w-5" />
+          {t("nameConflicts.title")}
+        </CardTitle>
+        <CardDescription>{t("nameConflicts.description")}</CardDescription>
+      </CardHeader>
+      <CardContent>
+        <form
+          className="mb-4 flex gap-2"
+          onSubmit={(e) => {
+            e.preventDefault()
+            load(dir.trim())
+          }}
+        >
+          <Input value={dir} onChange={(e) => setDir(e.target.value)} placeholder={t("nameConflicts.dirPlaceholder")} />
+          <Button type="submit" variant="outline" disabled={isLoading}>
+            {t("nameConflicts.apply")}
+          </Button>
+        </form>
+        {data && conflicts.length === 0 && (
+          <p className="text-sm text-muted-foreground">{t("nameConflicts.empty")}</p>
+        )}
+        {conflicts.length > 0 && (
+          <p className="mb-2 text-sm text-muted-foreground">
+            {t("nameConflicts.summary", { count: data!.totalConflicts, files: data!.totalFiles })}
+            {data!.totalConflicts > conflicts.length && (
+              <span className="ml-3">{t("nameConflicts.shown", { shown: conflicts.length })}</span>
+            )}
+          </p>
+        )}
+        <ul className="divide-y">
+          {conflicts.map((conflict) => (
+            <li key={conflict.name} className="py-3">
+              <p className="text-sm">
+                <span className="font-medium">{conflict.name}</span>
+                <span className="ml-3 text-muted-foreground">{t("nameConflicts.versions", { count: conflict.versions })}</span>
+              </p>
+              <ul className="mt-1 space-y-1 pl-4">
+                {conflict.files.map((file) => (
+                  <li key={file.id} className="flex items-center gap-3 text-xs">
+                    <span className="w-20 shrink-0 text-muted-foreground">
+                      {t("nameConflicts.version", { version: file.version })}
+                    </span>
+                    <span className="min-w-0 flex-1 truncate" title={file.path}>{file.dirPath}</span>
+                    <span className="shrink-0 text-muted-foreground">{formatSize(file.size)}</span>
+                    <span className="shrink-0 text-muted-foreground">{file.modTime}</span>
+                  </li>
+                ))}
+              </ul>
+            </li>
+          ))}
+        </ul>
+      </CardContent>
+    </Card>
+  )
+}
@@ -0,0 +1,162 @@
+import { useCallback, useEffect, useState } from "react"
+import { toast } from "sonner"
+import { Copy, Trash2 } from "lucide-react"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { FileItem } from "@/components/duplicates/FileItem"
+import { ThumbnailImage } from "@/components/duplicates/ThumbnailImage"
+import { Pagination } from "@/components/pagination/Pagination"
+import { DeleteFilesModal } from "@/components/modals/DeleteFilesModal"
+import { fetchDuplicates, setProtected, thumbnailSrc } from "@/api/endpoints"
+import { DEFAULT_PAGE_SIZE } from "@/lib/constants"
+import { useTranslation } from "@/i18n"
+import type { DuplicatesResponse, FileDTO } from "@/types"
+
+// NameCopiesTab lists the files of a folder whose names differ only by copy suffixes,
+// such as "photo (1).jpg" next to "photo.jpg", whatever their content
+export function NameCopiesTab() {
+  const { t } = useTranslation()
+  const [page, setPage] = useState(1)
+  const [data, setData] = useState<DuplicatesResponse | null>(null)
+  const [selected, setSelected] = useState<Set<string>>(new Set())
+  const [deleteOpen, setDeleteOpen] = useState(false)
+
+  const load = useCallback(() => {
+    fetchDuplicates(page, DEFAULT_PAGE_SIZE, "name")
+      .then(setData)
+      .catch((err) => toast.error(err instanceof Error ? err.message : t("api.scan.duplicate_failed")))
+  }, [page, t])
+
+  useEffect(() => {
+    load()
+  }, [load])
+
+  const toggle = useCallback((path: string) => {
+    setSelected((prev) => {
+      const next = new Set(prev)
+      if (next.has(path)) {
+        next.delete(path)
+      } else {
+        next.add(path)
+      }
+      return next
+    })
+  }, [])
+
+  const selectFolder = useCallback(
+    (dirPath: string) => {
+      const files: FileDTO[] = data?.groups.flatMap((g) => g.files) ?? []
+      setSelected((prev) => new Set([...prev, ...files.filter((f) => f.dirPath === dirPath).map((f) => f.path)]))
+    },
+    [data]
+  )
+
+  const handleToggleProtected = useCallback(
+    async (path: string, isProtected: boolean) => {
+      try {
+        await setProtected([path], isProtected)
+        toast.success(isProtected ? t("fileItem.toastProtected") : t("fileItem.toastUnprotected"))
+        load()
+      } catch (err) {
+        toast.error(err instanceof Error ? err.message : t("api.scan.protect_failed"))
+      }
+    },
+    [load, t]
+  )
+
+  const groups = data?.groups ?? []
+
+  return (
+    <div className="space-y-4">
+      <Card>
+        <CardHeader>
+          <CardTitle className="flex items-center gap-2">
+            <Copy className="h-5 w-5" />
+            {t("nameCopies.title")}
+          </CardTitle>
+          <CardDescription>{t("nameCopies.description")}</CardDescription>
+        </CardHeader>
+        <CardContent className="flex items-center gap-4">
+          <span className="flex-1 text-sm text-muted-foreground">
+            {data && (groups.length === 0
+              ? t("nameCopies.empty")
+              : t("nameCopies.summary", { count: data.totalGroups, files: data.totalFiles }))}
+          </span>
+          <Button
+            size="sm"
+            variant="destructive"
+            className="gap-2"
+            disabled={selected.size === 0}
+            onClick={() => setDeleteOpen(true)}
+          >
+            <Trash2 className="h-4 w-4" />
+            {t("nameCopies.deleteSelected", { count: selected.size })}
+          </Button>
+        </CardContent>
+      </Card>
+
+      {groups.map((group) => (
+        <Card key={group.hash}>
+          <CardHeader className="pb-2">
+            <div className="flex flex-wrap items-center gap-2">
+              <CardTitle className="text-sm">{t("duplicateGroup.title", { index: group.index })}</CardTitle>
+              <Badge variant="secondary" className="text-xs">{t("duplicateGroup.files", { count: group.files.length })}</Badge>
+            </div>
+          </CardHeader>
+          <CardContent>
+            <div className="flex gap-4">
+              <div className="shrink-0">
+                <ThumbnailImage src={group.thumbnail && thumbnailSrc(group.thumbnail)} />
+              </div>
+              <div className="min-w-0 flex-1 space-y-1">
+                {group.files.map((file) => (
+                  <div key={file.id} className="flex items-center gap-2">
+                    <div className="min-w-0 flex-1">
+                      <FileItem
+                        file={file}
+                        isSelected={selected.has(file.path)}
+                        onToggle={toggle}
+                        onSelectFolder={selectFolder}
+                        onToggleProtected={handleToggleProtected}
+                      />
+                    </div>
+                    <span className="w-28 shrink-0 text-right text-xs text-muted-foreground">
+                      {file.similarity === 1
+                        ? t("nameCopies.identical")
+                        : file.similarity > 0
+                          ? t("nameCopies.similarity", { percent: Math.round(file.similarity * 100) })
+                          : t("nameCopies.different")}
+                    </span>
+                  </div>
+                ))}
+              </div>
+            </div>
+          </CardContent>
+        </Card>
+      ))}
+
+      {data && data.totalPages > 1 && (
+        <Pagination
+          currentPage={data.currentPage}
+          totalPages={data.totalPages}
+          hasPrevPage={data.hasPrevPage}
+          hasNextPage={data.hasNextPage}
+          onPageChange={setPage}
+        />
+      )}
+
+      <DeleteFilesModal
+        open={deleteOpen}
+        onOpenChange={setDeleteOpen}
+        selectedPaths={[...selected]}
+        onSuccess={(message) => toast.success(message)}
+        onError={(message) => toast.error(message)}
+        onComplete={() => {
+          setSelected(new Set())
+          load()
+        }}
+      />
+    </div>
+  )
+}
@@ -2,6 +2,7 @@ import { useEffect, useRef, useCallback, useState } from "react"
 import { FileText, Loader2 } from "lucide-react"
 import { useTranslation } from "@/i18n"
 import type { OcrDocumentDTO } from "@/types"
+import { thumbnailSrc } from "@/api/endpoints"
 import { OcrLightbox } from "@/components/gallery/OcrLightbox"
 import { useOcrDocuments } from "@/hooks/useOcrDocuments"
 
@@ -96,7 +97,7 @@ export function OcrTab() {
                 {/* Thumbnail */}
                 {doc.thumbnail ? (
                   <img
-                    src={doc.thumbnail}
+                    src={thumbnailSrc(doc.thumbnail)}
                     alt={doc.fileName}
                     className="w-full h-full object-cover"
                   />
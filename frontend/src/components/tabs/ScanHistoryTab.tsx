@@ -0,0 +1,109 @@
+import { useCallback, useEffect, useState } from "react"
+import { toast } from "sonner"
+import { ClipboardList, RefreshCw } from "lucide-react"
+import { Badge } from "@/components/ui/badge"
+import { IconButton } from "@/components/ui/icon-button"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { Skeleton } from "@/components/ui/skeleton"
+import { fetchScanRuns } from "@/api/endpoints"
+import { useTranslation, type TranslationKey } from "@/i18n"
+import type { ScanRunDTO } from "@/types"
+
+const statusVariant: Record<ScanRunDTO["status"], "default" | "secondary" | "destructive" | "outline"> = {
+  running: "default",
+  completed: "secondary",
+  cancelled: "outline",
+  failed: "destructive",
+}
+
+// formatElapsed renders a run duration as e.g. "1h 02m", "3m 05s" or "12s"
+function formatElapsed(ms: number): string {
+  const seconds = Math.round(ms / 1000)
+  const h = Math.floor(seconds / 3600)
+  const m = Math.floor((seconds % 3600) / 60)
+  const s = seconds % 60
+  if (h > 0) return `${h}h ${String(m).padStart(2, "0")}m`
+  if (m > 0) return `${m}m ${String(s).padStart(2, "0")}s`
+  return `${s}s`
+}
+
+// ScanHistoryTab lists the recorded scan runs with what each of them changed in the index
+export function ScanHistoryTab() {
+  const { t } = useTranslation()
+  const [runs, setRuns] = useState<ScanRunDTO[]>([])
+  const [isLoading, setIsLoading] = useState(true)
+
+  const load = useCallback(() => {
+    setIsLoading(true)
+    fetchScanRuns(100)
+      .then((r) => setRuns(r.runs))
+      .catch((err) => toast.error(err instanceof Error ? err.message : t("api.scan.runs_failed")))
+      .finally(() => setIsLoading(false))
+  }, [t])
+
+  useEffect(() => {
+    load()
+  }, [load])
+
+  return (
+    <Card>
+      <CardHeader className="flex flex-row items-start justify-between space-y-0">
+        <div className="space-y-1.5">
+          <CardTitle className="flex items-center gap-2">
+            <ClipboardList className="h-5 w-5" />
+            {t("scanHistory.title")}
+          </CardTitle>
+          <CardDescription>
+            {runs.length > 0
+              ? t("scanHistory.lastScan", { time: runs[0].finishedAt ?? runs[0].startedAt })
+              : t("scanHistory.description")}
+          </CardDescription>
+        </div>
+        <IconButton size="sm" variant="outline" icon={RefreshCw} isLoading={isLoading} onClick={load}>
+          {t("scanHistory.refresh")}
+        </IconButton>
+      </CardHeader>
+      <CardContent>
+        {isLoading && runs.length === 0 ? (
+          <div className="space-y-2">
+            {Array.from({ length: 4 }).map((_, i) => (
+              <Skeleton key={i} className="h-16 w-full rounded-md" />
+            ))}
+          </div>
+        ) : runs.length === 0 ? (
+          <p className="text-sm text-muted-foreground">{t("schedule.noRuns")}</p>
+        ) : (
+          <ul className="space-y-2">
+            {runs.map((run) => (
+              <li key={run.id} className="space-y-1.5 rounded-md border px-3 py-2 text-sm">
+                <div className="flex flex-wrap items-center gap-2">
+                  <span className="font-mono">{run.startedAt}</span>
+                  <Badge variant={statusVariant[run.status]} className="text-xs">
+                    {t(`scanHistory.status.${run.status}` as TranslationKey)}
+                  </Badge>
+                  <Badge variant="outline" className="text-xs">{t(`scanHistory.kind.${run.kind}` as TranslationKey)}</Badge>
+                  <span className="text-muted-foreground">
+                    {t(run.trigger === "scheduled" ? "schedule.triggerScheduled" : "schedule.triggerManual")}
+                    {run.elapsedMs !== undefined && ` · ${formatElapsed(run.elapsedMs)}`}
+                  </span>
+                </div>
+                <div className="flex flex-wrap gap-x-4 gap-y-1 text-xs text-muted-foreground">
+                  <span>{t("scanHistory.added", { count: run.filesAdded })}</span>
+                  <span>{t("scanHistory.updated", { count: run.filesUpdated })}</span>
+                  <span>{t("scanHistory.removed", { count: run.filesRemoved })}</span>
+                  {run.errors > 0 && (
+                    <span className="text-destructive">{t("scanHistory.errors", { count: run.errors })}</span>
+                  )}
+                </div>
+                {run.directories.length > 0 && (
+                  <div className="font-mono text-xs text-muted-foreground break-all">{run.directories.join(", ")}</div>
+                )}
+                {run.error && <div className="text-xs text-destructive break-all">{run.error}</div>}
+              </li>
+            ))}
+          </ul>
+        )}
+      </CardContent>
+    </Card>
+  )
+}
@@ -0,0 +1,209 @@
+import { useCallback, useEffect, useState } from "react"
+import { toast } from "sonner"
+import { BarChart3, Maximize2, RefreshCw } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { IconButton } from "@/components/ui/icon-button"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { Skeleton } from "@/components/ui/skeleton"
+import { GroupDetailDialog } from "@/components/duplicates/GroupDetailDialog"
+import { fetchStats } from "@/api/endpoints"
+import { useTranslation } from "@/i18n"
+import { formatSize } from "@/lib/utils"
+import type { StatsResponse, StatsShareDTO } from "@/types"
+
+// StatsTab shows where exact duplicates waste space
+export function StatsTab() {
+  const { t } = useTranslation()
+  const [stats, setStats] = useState<StatsResponse | null>(null)
+  const [isLoading, setIsLoading] = useState(true)
+  const [detailHash, setDetailHash] = useState<string | null>(null)
+
+  const load = useCallback(() => {
+    setIsLoading(true)
+    fetchStats()
+      .then(setStats)
+      .catch((err) => toast.error(err instanceof Error ? err.message : t("api.scan.stats_failed")))
+      .finally(() => setIsLoading(false))
+  }, [t])
+
+  useEffect(() => {
+    load()
+  }, [load])
+
+  if (isLoading && !stats) {
+    return (
+      <div className="space-y-4">
+        <Skeleton className="h-28 w-full rounded-lg" />
+        <Skeleton className="h-64 w-full rounded-lg" />
+      </div>
+    )
+  }
+  if (!stats) return null
+
+  const maxMonthBytes = Math.max(1, ...stats.months.map((m) => Math.max(m.duplicateBytes, m.deletedBytes)))
+
+  return (
+    <div className="space-y-4">
+      <Card>
+        <CardHeader className="flex flex-row items-start justify-between space-y-0">
+          <div className="space-y-1.5">
+            <CardTitle className="flex items-center gap-2">
+              <BarChart3 className="h-5 w-5" />
+              {t("stats.title")}
+            </CardTitle>
+            <CardDescription>{t("stats.description")}</CardDescription>
+          </div>
+          <IconButton size="sm" variant="outline" icon={RefreshCw} isLoading={isLoading} onClick={load}>
+            {t("stats.refresh")}
+          </IconButton>
+        </CardHeader>
+        <CardContent>
+          <div className="grid gap-4 sm:grid-cols-2 lg:grid-cols-4">
+            <Figure label={t("stats.reclaimable")} value={formatSize(stats.reclaimableBytes)} />
+            <Figure label={t("stats.duplicateGroups")} value={String(stats.duplicateGroups)} />
+            <Figure
+              label={t("stats.duplicateFiles")}
+              value={String(stats.duplicateFiles)}
+              hint={formatSize(stats.duplicateBytes)}
+            />
+            <Figure
+              label={t("stats.indexedFiles")}
+              value={String(stats.indexedFiles)}
+              hint={formatSize(stats.indexedBytes)}
+            />
+          </div>
+        </CardContent>
+      </Card>
+
+      <div className="grid gap-4 lg:grid-cols-2">
+        <ShareCard title={t("stats.byDirectory")} shares={stats.directories} />
+        <ShareCard title={t("stats.byExtension")} shares={stats.extensions} />
+      </div>
+
+      <Card>
+        <CardHeader>
+          <CardTitle className="text-base">{t("stats.largestGroups")}</CardTitle>
+        </CardHeader>
+        <CardContent>
+          {stats.largestGroups.length === 0 ? (
+            <p className="text-sm text-muted-foreground">{t("stats.empty")}</p>
+          ) : (
+            <ul className="divide-y">
+              {stats.largestGroups.map((g) => (
+                <li key={g.hash} className="flex items-center gap-4 py-2 text-sm">
+                  <span className="min-w-0 flex-1 truncate font-mono text-xs" title={g.path}>{g.path}</span>
+                  <span className="whitespace-nowrap text-muted-foreground">
+                    {t("stats.groupSummary", { count: g.files, size: formatSize(g.size) })}
+                  </span>
+                  <span className="w-24 whitespace-nowrap text-right font-medium">{formatSize(g.reclaimableBytes)}</span>
+                  <Button size="sm" variant="ghost" className="h-7 w-7 p-0" onClick={() => setDetailHash(g.hash)}>
+                    <Maximize2 className="h-3.5 w-3.5" />
+                  </Button>
+                </li>
+              ))}
+            </ul>
+          )}
+        </CardContent>
+      </Card>
+
+      <Card>
+        <CardHeader>
+          <CardTitle className="text-base">{t("stats.months")}</CardTitle>
+          <CardDescription>{t("stats.monthsDescription")}</CardDescription>
+        </CardHeader>
+        <CardContent>
+          {stats.months.length === 0 ? (
+            <p className="text-sm text-muted-foreground">{t("stats.empty")}</p>
+          ) : (
+            <ul className="space-y-2">
+              {stats.months.map((m) => (
+                <li key={m.month} className="grid grid-cols-[5rem_1fr] items-center gap-3 text-xs">
+                  <span className="font-mono text-muted-foreground">{m.month}</span>
+                  <div className="space-y-1">
+                    <Bar
+                      ratio={m.duplicateBytes / maxMonthBytes}
+                      className="bg-primary"
+                      label={t("stats.monthFound", { count: m.duplicateFiles, size: formatSize(m.duplicateBytes) })}
+                    />
+                    {m.deletedFiles > 0 && (
+                      <Bar
+                        ratio={m.deletedBytes / maxMonthBytes}
+                        className="bg-emerald-500"
+                        label={t("stats.monthDeleted", { count: m.deletedFiles, size: formatSize(m.deletedBytes) })}
+                      />
+                    )}
+                  </div>
+                </li>
+              ))}
+            </ul>
+          )}
+        </CardContent>
+      </Card>
+
+      <GroupDetailDialog
+        hash={detailHash}
+        onClose={() => setDetailHash(null)}
+        onSuccess={(message) => toast.success(message)}
+        onError={(message) => toast.error(message)}
+        onComplete={load}
+      />
+    </div>
+  )
+}
+
+function Figure({ label, value, hint }: { label: string; value: string; hint?: string }) {
+  return (
+    <div className="rounded-lg border p-4">
+      <div className="text-xs text-muted-foreground">{label}</div>
+      <div className="mt-1 text-2xl font-semibold">{value}</div>
+      {hint && <div className="text-xs text-muted-foreground">{hint}</div>}
+    </div>
+  )
+}
+
+function Bar({ ratio, className, label }: { ratio: number; className: string; label: string }) {
+  return (
+    <div className="flex items-center gap-2">
+      <div className="h-2 flex-1 rounded bg-muted">
+        <div className={`h-2 rounded ${className}`} style={{ width: `${Math.max(ratio * 100, 1)}%` }} />
+      </div>
+      <span className="w-44 whitespace-nowrap text-muted-foreground">{label}</span>
+    </div>
+  )
+}
+
+function ShareCard({ title, shares }: { title: string; shares: StatsShareDTO[] }) {
+  const { t } = useTranslation()
+  const maxBytes = Math.max(1, ...shares.map((s) => s.bytes))
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="text-base">{title}</CardTitle>
+      </CardHeader>
+      <CardContent>
+        {shares.length === 0 ? (
+          <p className="text-sm text-muted-foreground">{t("stats.empty")}</p>
+        ) : (
+          <ul className="space-y-2">
+            {shares.map((share) => (
+              <li key={share.key} className="space-y-1 text-xs">
+                <div className="flex gap-2">
+                  <span className="min-w-0 flex-1 truncate font-mono" title={share.key}>
+                    {share.key || t("stats.noExtension")}
+                  </span>
+                  <span className="whitespace-nowrap text-muted-foreground">
+                    {t("stats.shareSummary", { count: share.files, size: formatSize(share.bytes) })}
+                  </span>
+                </div>
+                <div className="h-2 rounded bg-muted">
+                  <div className="h-2 rounded bg-primary" style={{ width: `${Math.max((share.bytes / maxBytes) * 100, 1)}%` }} />
+                </div>
+              </li>
+            ))}
+          </ul>
+        )}
+      </CardContent>
+    </Card>
+  )
+}
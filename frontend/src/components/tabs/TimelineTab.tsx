@@ -0,0 +1,222 @@
+import { useCallback, useEffect, useState } from "react"
+import { toast } from "sonner"
+import { CalendarRange, CheckSquare, Trash2 } from "lucide-react"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
+import { FileItem } from "@/components/duplicates/FileItem"
+import { ThumbnailImage } from "@/components/duplicates/ThumbnailImage"
+import { Pagination } from "@/components/pagination/Pagination"
+import { DeleteFilesModal } from "@/components/modals/DeleteFilesModal"
+import { fetchDuplicates, fetchTimeline, setProtected, thumbnailSrc } from "@/api/endpoints"
+import { DEFAULT_PAGE_SIZE } from "@/lib/constants"
+import { useTranslation } from "@/i18n"
+import { cn, formatSize } from "@/lib/utils"
+import type { DuplicatesResponse, FileDTO, TimelineResponse } from "@/types"
+
+// TimelineTab lists the exact duplicate groups by the month their photos were taken,
+// so that one trip or event can be cleaned up at a time
+export function TimelineTab() {
+  const { t } = useTranslation()
+  const [timeline, setTimeline] = useState<TimelineResponse | null>(null)
+  const [month, setMonth] = useState<string | null>(null)
+  const [page, setPage] = useState(1)
+  const [data, setData] = useState<DuplicatesResponse | null>(null)
+  const [selected, setSelected] = useState<Set<string>>(new Set())
+  const [deleteOpen, setDeleteOpen] = useState(false)
+
+  const loadTimeline = useCallback(() => {
+    fetchTimeline()
+      .then(setTimeline)
+      .catch((err) => toast.error(err instanceof Error ? err.message : t("api.scan.timeline_failed")))
+  }, [t])
+
+  const loadGroups = useCallback(() => {
+    if (!month) {
+      setData(null)
+      return
+    }
+    fetchDuplicates(page, DEFAULT_PAGE_SIZE, "exact", undefined, "image", undefined, { month })
+      .then(setData)
+      .catch((err) => toast.error(err instanceof Error ? err.message : t("api.scan.duplicate_failed")))
+  }, [month, page, t])
+
+  useEffect(() => {
+    loadTimeline()
+  }, [loadTimeline])
+
+  useEffect(() => {
+    loadGroups()
+  }, [loadGroups])
+
+  const pickMonth = useCallback((value: string) => {
+    setMonth(value)
+    setPage(1)
+    setSelected(new Set())
+  }, [])
+
+  const toggle = useCallback((path: string) => {
+    setSelected((prev) => {
+      const next = new Set(prev)
+      if (next.has(path)) {
+        next.delete(path)
+      } else {
+        next.add(path)
+      }
+      return next
+    })
+  }, [])
+
+  const selectFolder = useCallback(
+    (dirPath: string) => {
+      const files: FileDTO[] = data?.groups.flatMap((g) => g.files) ?? []
+      setSelected((prev) => new Set([...prev, ...files.filter((f) => f.dirPath === dirPath).map((f) => f.path)]))
+    },
+    [data]
+  )
+
+  // Selects every file of the page but the first of each group, leaving protected files alone
+  const selectCopies = useCallback(() => {
+    const copies = data?.groups.flatMap((g) => g.files.slice(1).filter((f) => !f.protected)) ?? []
+    setSelected((prev) => new Set([...prev, ...copies.map((f) => f.path)]))
+  }, [data])
+
+  const handleToggleProtected = useCallback(
+    async (path: string, isProtected: boolean) => {
+      try {
+        await setProtected([path], isProtected)
+        toast.success(isProtected ? t("fileItem.toastProtected") : t("fileItem.toastUnprotected"))
+        loadGroups()
+      } catch (err) {
+        toast.error(err instanceof Error ? err.message : t("api.scan.protect_failed"))
+      }
+    },
+    [loadGroups, t]
+  )
+
+  const months = timeline?.months ?? []
+  const maxBytes = Math.max(1, ...months.map((m) => m.reclaimableBytes))
+  const groups = data?.groups ?? []
+
+  return (
+    <div className="space-y-4">
+      <Card>
+        <CardHeader>
+          <CardTitle className="flex items-center gap-2">
+            <CalendarRange className="h-5 w-5" />
+            {t("timeline.title")}
+          </CardTitle>
+          <CardDescription>{t("timeline.description")}</CardDescription>
+        </CardHeader>
+        <CardContent>
+          {timeline && months.length === 0 ? (
+            <p className="text-sm text-muted-foreground">{t("timeline.empty")}</p>
+          ) : (
+            <ul className="space-y-1">
+              {months.map((m) => (
+                <li key={m.month}>
+                  <button
+                    type="button"
+                    onClick={() => pickMonth(m.month)}
+                    className={cn(
+                      "grid w-full grid-cols-[5rem_1fr] items-center gap-3 rounded px-2 py-1 text-left text-xs hover:bg-muted",
+                      m.month === month && "bg-muted"
+                    )}
+                  >
+                    <span className="font-mono text-muted-foreground">{m.month}</span>
+                    <div className="flex items-center gap-2">
+                      <div className="h-2 flex-1 rounded bg-muted">
+                        <div
+                          className="h-2 rounded bg-primary"
+                          style={{ width: `${Math.max(1, (m.reclaimableBytes / maxBytes) * 100)}%` }}
+                        />
+                      </div>
+                      <span className="w-40 shrink-0 text-right text-muted-foreground">
+                        {t("timeline.monthSummary", { count: m.groups, size: formatSize(m.reclaimableBytes) })}
+                      </span>
+                    </div>
+                  </button>
+                </li>
+              ))}
+            </ul>
+          )}
+        </CardContent>
+      </Card>
+
+      <Card>
+        <CardContent className="flex items-center gap-4 pt-6">
+          <span className="flex-1 text-sm text-muted-foreground">
+            {month ? t("timeline.groupsTitle", { month }) : t("timeline.pickMonth")}
+          </span>
+          <Button size="sm" variant="outline" className="gap-2" disabled={groups.length === 0} onClick={selectCopies}>
+            <CheckSquare className="h-4 w-4" />
+            {t("timeline.selectCopies")}
+          </Button>
+          <Button
+            size="sm"
+            variant="destructive"
+            className="gap-2"
+            disabled={selected.size === 0}
+            onClick={() => setDeleteOpen(true)}
+          >
+            <Trash2 className="h-4 w-4" />
+            {t("timeline.deleteSelected", { count: selected.size })}
+          </Button>
+        </CardContent>
+      </Card>
+
+      {groups.map((group) => (
+        <Card key={`${group.hash}-${group.size}`}>
+          <CardHeader className="pb-2">
+            <div className="flex flex-wrap items-center gap-2">
+              <CardTitle className="text-sm">{t("duplicateGroup.title", { index: group.index })}</CardTitle>
+              <Badge variant="secondary" className="text-xs">{t("duplicateGroup.files", { count: group.files.length })}</Badge>
+            </div>
+          </CardHeader>
+          <CardContent>
+            <div className="flex gap-4">
+              <div className="shrink-0">
+                <ThumbnailImage src={group.thumbnail && thumbnailSrc(group.thumbnail)} />
+              </div>
+              <div className="min-w-0 flex-1 space-y-1">
+                {group.files.map((file) => (
+                  <FileItem
+                    key={file.id}
+                    file={file}
+                    isSelected={selected.has(file.path)}
+                    onToggle={toggle}
+                    onSelectFolder={selectFolder}
+                    onToggleProtected={handleToggleProtected}
+                  />
+                ))}
+              </div>
+            </div>
+          </CardContent>
+        </Card>
+      ))}
+
+      {data && data.totalPages > 1 && (
+        <Pagination
+          currentPage={data.currentPage}
+          totalPages={data.totalPages}
+          hasPrevPage={data.hasPrevPage}
+          hasNextPage={data.hasNextPage}
+          onPageChange={setPage}
+        />
+      )}
+
+      <DeleteFilesModal
+        open={deleteOpen}
+        onOpenChange={setDeleteOpen}
+        selectedPaths={[...selected]}
+        onSuccess={(message) => toast.success(message)}
+        onError={(message) => toast.error(message)}
+        onComplete={() => {
+          setSelected(new Set())
+          loadGroups()
+          loadTimeline()
+        }}
+      />
+    </div>
+  )
+}
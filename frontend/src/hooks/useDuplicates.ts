@@ -1,15 +1,26 @@
 import { useCallback, useEffect, useRef, useState } from "react"
 import { fetchDuplicates } from "@/api/endpoints"
-import type { DuplicatesResponse } from "@/types"
+import type { DuplicateFilters, DuplicateMedia, DuplicateSort, DuplicatesResponse } from "@/types"
 
 interface PrefetchEntry {
   page: number
   pageSize: number
+  sort?: DuplicateSort
+  filters?: DuplicateFilters
   data: DuplicatesResponse | null
   promise: Promise<DuplicatesResponse> | null
 }
 
-export function useDuplicates(page: number, pageSize: number) {
+// NO_FILTERS is a stable default, so that the callbacks below are not recreated on every render
+const NO_FILTERS: DuplicateFilters = {}
+
+export function useDuplicates(
+  page: number,
+  pageSize: number,
+  media: DuplicateMedia = "image",
+  sort?: DuplicateSort,
+  filters: DuplicateFilters = NO_FILTERS,
+) {
   const [data, setData] = useState<DuplicatesResponse | null>(null)
   const [isLoading, setIsLoading] = useState(true)
   const [error, setError] = useState<string | null>(null)
@@ -19,15 +30,18 @@ export function useDuplicates(page: number, pageSize: number) {
 
   const startPrefetch = useCallback((nextPage: number, size: number) => {
     const buf = prefetchRef.current
-    if (buf.page === nextPage && buf.pageSize === size && (buf.data || buf.promise)) {
+    if (buf.page === nextPage && buf.pageSize === size && buf.sort === sort && buf.filters === filters && (buf.data || buf.promise)) {
       return // already prefetching/prefetched
     }
     buf.page = nextPage
     buf.pageSize = size
+    buf.sort = sort
+    buf.filters = filters
     buf.data = null
-    buf.promise = fetchDuplicates(nextPage, size)
+    buf.promise = fetchDuplicates(nextPage, size, "exact", undefined, media, sort, filters)
       .then((result) => {
-        if (prefetchRef.current.page === nextPage && prefetchRef.current.pageSize === size) {
+        const current = prefetchRef.current
+        if (current.page === nextPage && current.pageSize === size && current.sort === sort && current.filters === filters) {
           prefetchRef.current.data = result
         }
         return result
@@ -36,11 +50,11 @@ export function useDuplicates(page: number, pageSize: number) {
         prefetchRef.current.promise = null
         return null as unknown as DuplicatesResponse
       })
-  }, [])
+  }, [media, sort, filters])
 
   const consumePrefetch = useCallback((targetPage: number, size: number): DuplicatesResponse | null => {
     const buf = prefetchRef.current
-    if (buf.page === targetPage && buf.pageSize === size && buf.data) {
+    if (buf.page === targetPage && buf.pageSize === size && buf.sort === sort && buf.filters === filters && buf.data) {
       const result = buf.data
       buf.page = 0
       buf.data = null
@@ -48,7 +62,7 @@ export function useDuplicates(page: number, pageSize: number) {
       return result
     }
     return null
-  }, [])
+  }, [sort, filters])
 
   const load = useCallback(async () => {
     setIsLoading(true)
@@ -56,7 +70,7 @@ export function useDuplicates(page: number, pageSize: number) {
     try {
       // Use prefetched data if available
       const prefetched = consumePrefetch(page, pageSize)
-      const result = prefetched ?? await fetchDuplicates(page, pageSize)
+      const result = prefetched ?? await fetchDuplicates(page, pageSize, "exact", undefined, media, sort, filters)
       setData(result)
 
       // Prefetch the next page in background
@@ -68,7 +82,7 @@ export function useDuplicates(page: number, pageSize: number) {
     } finally {
       setIsLoading(false)
     }
-  }, [page, pageSize, consumePrefetch, startPrefetch])
+  }, [page, pageSize, sort, filters, consumePrefetch, startPrefetch])
 
   useEffect(() => {
     load()
@@ -0,0 +1,35 @@
+import { useState, useEffect, useCallback } from "react"
+import { fetchGroup } from "@/api/endpoints"
+import type { GroupResponse } from "@/types"
+
+export function useGroup(hash: string | null) {
+  const [data, setData] = useState<GroupResponse | null>(null)
+  const [isLoading, setIsLoading] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  const load = useCallback(async () => {
+    if (!hash) return
+    setIsLoading(true)
+    setError(null)
+    try {
+      setData(await fetchGroup(hash))
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to load duplicate group")
+      setData(null)
+    } finally {
+      setIsLoading(false)
+    }
+  }, [hash])
+
+  useEffect(() => {
+    if (!hash) {
+      setData(null)
+      setIsLoading(false)
+      setError(null)
+      return
+    }
+    load()
+  }, [hash, load])
+
+  return { data, isLoading, error, refetch: load }
+}
@@ -0,0 +1,59 @@
+import { useCallback, useEffect, useRef, useState } from "react"
+import { webSocketUrl } from "@/api/client"
+import type { ScanCommand, ScanStatusResponse, ServerEvent } from "@/types"
+
+const RECONNECT_DELAY = 3000
+
+/**
+ * Subscribes to server events over /api/v1/ws and exposes scan control commands.
+ * Reconnects automatically while mounted; onEvent receives every event.
+ */
+export function useScanEvents(onEvent?: (event: ServerEvent) => void) {
+  const [status, setStatus] = useState<ScanStatusResponse | null>(null)
+  const [connected, setConnected] = useState(false)
+  const socketRef = useRef<WebSocket | null>(null)
+  const onEventRef = useRef(onEvent)
+  onEventRef.current = onEvent
+
+  useEffect(() => {
+    let closed = false
+    let retryTimer: ReturnType<typeof setTimeout> | undefined
+
+    const connect = () => {
+      const socket = new WebSocket(webSocketUrl("/api/v1/ws"))
+      socketRef.current = socket
+
+      socket.onopen = () => setConnected(true)
+      socket.onmessage = (message) => {
+        const event = JSON.parse(message.data) as ServerEvent
+        if (event.type === "scan.status" || event.type === "scan.finished") {
+          setStatus(event.data)
+        }
+        onEventRef.current?.(event)
+      }
+      socket.onclose = () => {
+        setConnected(false)
+        socketRef.current = null
+        if (!closed) {
+          retryTimer = setTimeout(connect, RECONNECT_DELAY)
+        }
+      }
+    }
+
+    connect()
+    return () => {
+      closed = true
+      clearTimeout(retryTimer)
+      socketRef.current?.close()
+    }
+  }, [])
+
+  const sendCommand = useCallback((command: ScanCommand) => {
+    const socket = socketRef.current
+    if (socket?.readyState === WebSocket.OPEN) {
+      socket.send(JSON.stringify({ command }))
+    }
+  }, [])
+
+  return { status, connected, sendCommand }
+}
@@ -1,46 +1,83 @@
-import { useCallback, useMemo, useState } from "react"
+import { useCallback, useEffect, useMemo, useRef, useState } from "react"
+import { clearSelection, fetchSelection, updateSelection } from "@/api/endpoints"
 import type { FileDTO } from "@/types"
 
+// Files selected for deletion are stored as "delete" marks of the session on the
+// server, so the selection survives paging and page reloads
 export function useSelection() {
   const [selected, setSelected] = useState<Set<string>>(new Set())
+  const selectedRef = useRef(selected)
+  selectedRef.current = selected
 
-  const toggle = useCallback((path: string) => {
-    setSelected((prev) => {
-      const next = new Set(prev)
-      if (next.has(path)) {
-        next.delete(path)
-      } else {
-        next.add(path)
-      }
-      return next
-    })
+  // Changes are sent to the server one after another, so a reset followed by a new
+  // selection is stored in that order. A failed request only means the change is
+  // not restored after a reload; the local selection is kept as is.
+  const pending = useRef<Promise<unknown>>(Promise.resolve())
+  const persist = useCallback((request: () => Promise<unknown>) => {
+    pending.current = pending.current.then(request).catch(() => {})
   }, [])
 
-  const selectByFolder = useCallback((dirPath: string, allFiles: FileDTO[]) => {
+  useEffect(() => {
+    let cancelled = false
+    fetchSelection()
+      .then((res) => {
+        if (cancelled) return
+        setSelected((prev) => {
+          const next = new Set(prev)
+          for (const item of res.items) {
+            if (item.action === "delete") {
+              next.add(item.path)
+            }
+          }
+          return next
+        })
+      })
+      .catch(() => {})
+    return () => {
+      cancelled = true
+    }
+  }, [])
+
+  const add = useCallback((paths: string[]) => {
     setSelected((prev) => {
       const next = new Set(prev)
-      for (const f of allFiles) {
-        if (f.dirPath === dirPath) {
-          next.add(f.path)
-        }
+      for (const p of paths) {
+        next.add(p)
       }
       return next
     })
-  }, [])
+    if (paths.length > 0) {
+      persist(() => updateSelection(paths.map((path) => ({ path, action: "delete" }))))
+    }
+  }, [persist])
 
-  const selectAll = useCallback((paths: string[]) => {
+  const toggle = useCallback((path: string) => {
+    const adding = !selectedRef.current.has(path)
     setSelected((prev) => {
       const next = new Set(prev)
-      for (const p of paths) {
-        next.add(p)
+      if (next.has(path)) {
+        next.delete(path)
+      } else {
+        next.add(path)
       }
       return next
     })
-  }, [])
+    persist(() => updateSelection([{ path, action: adding ? "delete" : "" }]))
+  }, [persist])
+
+  const selectByFolder = useCallback(
+    (dirPath: string, allFiles: FileDTO[]) => {
+      add(allFiles.filter((f) => f.dirPath === dirPath).map((f) => f.path))
+    },
+    [add]
+  )
+
+  const selectAll = useCallback((paths: string[]) => add(paths), [add])
 
   const reset = useCallback(() => {
     setSelected(new Set())
-  }, [])
+    persist(() => clearSelection())
+  }, [persist])
 
   const isSelected = useCallback((path: string) => selected.has(path), [selected])
 
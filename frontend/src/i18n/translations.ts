@@ -11,7 +11,16 @@ export const translations = {
     "tabs.gallery": "Gallery",
     "tabs.tools": "Tools",
     "tabs.deduplication": "Deduplication",
+    "tabs.videos": "Videos",
     "tabs.ocr": "OCR",
+    "tabs.deletions": "Recent deletions",
+    "tabs.stats": "Statistics",
+    "tabs.timeline": "Timeline",
+    "tabs.scanHistory": "Scan history",
+    "tabs.ignoredGroups": "Ignored groups",
+    "tabs.nameCopies": "Likely copies",
+    "tabs.corruptFiles": "Corrupt files",
+    "tabs.nameConflicts": "Name conflicts",
 
     // Loading
     "common.loading": "Loading...",
@@ -60,6 +69,7 @@ export const translations = {
     "settings.toastAddFailed": "Failed to add folder",
     "settings.toastRemoveFailed": "Failed to remove folder",
     "settings.toastRescanFailed": "Failed to start rescan",
+    "settings.toastFolderRescanStarted": "Rescan of {path} started",
     "settings.toastFilesRemoved": "{message} ({count} files removed)",
     "settings.galleryFolders": "Gallery Folders",
     "settings.galleryFoldersDescription": "Manage the folders included in your image gallery. Adding a folder will automatically start scanning it for images.",
@@ -83,6 +93,66 @@ export const translations = {
     "trash.cleanFailed": "Failed to clean trash",
     "trash.saveFailed": "Failed to save trash directory",
 
+    // Scan schedule
+    "ignoredGroups.title": "Ignored groups",
+    "ignoredGroups.description": "Groups marked as not duplicates are hidden from the duplicate list, reports and batch deletion. Unignore a group to list it again.",
+    "ignoredGroups.empty": "No groups are ignored.",
+    "ignoredGroups.summary": "{count} file(s), {size} each, ignored {time}",
+    "ignoredGroups.unignore": "Unignore",
+    "ignoredGroups.unignored": "The group is listed among the duplicates again",
+    "nameCopies.title": "Likely copies",
+    "nameCopies.description": "Files of one folder whose names differ only by suffixes such as \" (1)\", \" - Copy\", \" copy 2\" or \"-edited\", or by the marks of sync conflicts, even when their content differs slightly. Downloads and cloud sync often leave such copies. The file with the shortest name comes first.",
+    "nameCopies.empty": "No likely copies were found.",
+    "nameCopies.summary": "{count} group(s), {files} file(s)",
+    "nameCopies.deleteSelected": "Delete selected ({count})",
+    "nameCopies.identical": "identical",
+    "nameCopies.similarity": "{percent}% similar",
+    "nameCopies.different": "different content",
+    "corruptFiles.title": "Corrupt files",
+    "corruptFiles.description": "Empty files and images that fail to decode, found while scanning. A broken copy is usually the one to delete from a duplicate group.",
+    "corruptFiles.empty": "No corrupt files were found.",
+    "corruptFiles.summary": "{count} file(s), {size}",
+    "corruptFiles.shown": "The first {shown} are listed",
+    "corruptFiles.selectAll": "Select all",
+    "corruptFiles.deleteSelected": "Delete selected ({count})",
+    "timeline.title": "Duplicates by date",
+    "timeline.description": "Exact duplicate groups by the month their photos were taken (EXIF capture time, or modification time without it). Pick a month to clean up one trip or event at a time.",
+    "timeline.empty": "No duplicates",
+    "timeline.monthSummary": "{count} group(s), {size}",
+    "timeline.pickMonth": "Pick a month to see its duplicate groups.",
+    "timeline.groupsTitle": "Duplicates of {month}",
+    "timeline.selectCopies": "Select copies",
+    "timeline.deleteSelected": "Delete selected ({count})",
+    "nameConflicts.title": "Name conflicts",
+    "nameConflicts.description": "Files with the same name but different content, e.g. two different IMG_0001.JPG from two cameras. Merging their folders would overwrite one with the other, so rename one of them first. Files of the same version are identical.",
+    "nameConflicts.dirPlaceholder": "Only under this folder (absolute path)",
+    "nameConflicts.apply": "Show",
+    "nameConflicts.empty": "No file names are shared by files of different content.",
+    "nameConflicts.summary": "{count} name(s), {files} file(s)",
+    "nameConflicts.shown": "The first {shown} are listed",
+    "nameConflicts.versions": "{count} versions",
+    "nameConflicts.version": "version {version}",
+    "deletions.title": "Recent deletions",
+    "deletions.description": "Files moved to the trash folder, grouped by operation. Restoring moves them back to their original locations.",
+    "deletions.empty": "Nothing has been moved to the trash folder yet.",
+    "deletions.summary": "{count} file(s), {size}",
+    "deletions.restoredCount": "{count} restored",
+    "deletions.restore": "Restore",
+    "deletions.restoring": "Restoring...",
+    "deletions.restored": "Restored {count} file(s)",
+    "deletions.restoredWithFailed": "Restored {count} file(s), {failed} could not be restored",
+    "schedule.title": "Scheduled scans",
+    "schedule.description": "Run a full gallery scan automatically on a cron schedule.",
+    "schedule.cronLabel": "Cron expression (empty to disable)",
+    "schedule.nextRun": "Next run: {time}",
+    "schedule.disabled": "Scheduled scans are disabled",
+    "schedule.recentRuns": "Recent scans",
+    "schedule.noRuns": "No scans recorded yet",
+    "schedule.triggerManual": "manual",
+    "schedule.triggerScheduled": "scheduled",
+    "schedule.statusRunning": "running",
+    "schedule.filesProcessed": "{count} file(s) processed",
+
     // Add folder form
     "addFolder.placeholder": "Enter folder path, e.g. C:\\Photos or /home/user/photos",
     "addFolder.button": "Add Folder",
@@ -97,6 +167,7 @@ export const translations = {
     "folderList.removeDescription": "Are you sure you want to remove this folder from the gallery? All indexed files from this folder will be removed from the database. The actual files on disk will NOT be deleted.",
     "folderList.removeButton": "Remove",
     "folderList.removing": "Removing...",
+    "folderList.rescan": "Rescan this folder",
 
     // Gallery tab
     "gallery.imageCount": "{count} image(s) in gallery",
@@ -160,15 +231,43 @@ export const translations = {
     "toolbar.resetSelection": "Reset Selection",
     "toolbar.deleteSelected": "Delete Selected",
     "toolbar.batchDedup": "Batch Dedup",
+    "toolbar.consolidate": "Consolidate",
+    "toolbar.autoSelect": "Auto-select…",
+    "toolbar.exportCsv": "Export CSV",
+    "toolbar.exportHtml": "HTML Report",
     "toolbar.filesSelected": "{count} file(s) selected",
     "toolbar.filesSelectedOne": "{count} file selected",
     "toolbar.groupsPerPage": "Groups per page:",
+    "toolbar.sortBy": "Sort:",
+    "toolbar.sort.default": "Largest files",
+    "toolbar.sort.wasted-space": "Wasted space",
+    "toolbar.sort.file-count": "Number of files",
+    "toolbar.sort.newest": "Newest",
+    "toolbar.sort.oldest": "Oldest",
+    "toolbar.sort.path": "Path",
+    "filters.dir": "Directory, e.g. /photos/2020",
+    "filters.ext": "Extensions: .png,.jpg",
+    "filters.minSize": "Min, e.g. 1MB",
+    "filters.maxSize": "Max size",
+    "filters.near": "Near: 43.70,7.26",
+    "filters.radius": "Radius, km",
+    "filters.nearHint": "Only groups with a copy shot within the radius (10 km by default) by its GPS data",
+    "filters.apply": "Filter",
+    "filters.clear": "Clear",
+    "filters.review.all": "Any review status",
+    "filters.review.unreviewed": "Unreviewed",
+    "filters.review.deferred": "Deferred",
+    "filters.review.reviewed": "Reviewed",
+    "filters.hosts": "On:",
+    "filters.hostsHint": "Only groups with copies on all of the selected machines",
 
     // Deduplication tab
     "dedup.toastScanStarted": "Scan started",
     "dedup.toastScanComplete": "Scan complete!",
     "dedup.toastSelectFile": "Please select at least one file.",
     "dedup.toastScanFailed": "Failed to start scan",
+    "dedup.toastAutoSelected": "Selected {count} file(s) to remove ({size})",
+    "dedup.toastAutoSelectFailed": "Failed to auto-select files",
     "dedup.toastFastScanStarted": "Fast scan started",
     "dedup.toastFastScanComplete": "Fast scan complete",
     "dedup.fastScanStats": "{unchanged} unchanged",
@@ -181,10 +280,68 @@ export const translations = {
     "duplicateGroup.files": "{count} files",
     "duplicateGroup.sizeEach": "{size} each",
     "duplicateGroup.md5": "MD5: {hash}",
+    "duplicateGroup.details": "Details",
+    "duplicateGroup.ignore": "Not duplicates",
+    "duplicateGroup.markReviewed": "Reviewed",
+    "duplicateGroup.markDeferred": "Later",
+    "duplicateGroup.reviewHint": "Click again to mark the group unreviewed",
+    "duplicateGroup.review.reviewed": "Reviewed",
+    "duplicateGroup.review.deferred": "Deferred",
+    "duplicateGroup.ignoreHint": "Hide this group: the copies are intentional",
+    "duplicateGroup.toastIgnored": "The group is hidden. It can be restored on the Ignored groups page.",
+    "groupDetail.title": "Duplicate group",
+    "groupDetail.reclaimable": "{size} reclaimable",
+    "groupDetail.open": "Open",
+    "groupDetail.keepOnly": "Keep only this",
+    "groupDetail.delete": "Delete",
+    "groupDetail.protect": "Protect",
+    "groupDetail.unprotect": "Unprotect",
+    "stats.title": "Wasted space",
+    "stats.description": "Where exact duplicates take up space in the indexed folders",
+    "stats.refresh": "Refresh",
+    "stats.reclaimable": "Reclaimable",
+    "stats.duplicateGroups": "Duplicate groups",
+    "stats.duplicateFiles": "Files in groups",
+    "stats.indexedFiles": "Indexed files",
+    "stats.byDirectory": "By directory",
+    "stats.byExtension": "By extension",
+    "stats.noExtension": "(no extension)",
+    "stats.shareSummary": "{count} files, {size}",
+    "stats.largestGroups": "Largest groups",
+    "stats.groupSummary": "{count} × {size}",
+    "stats.months": "Over time",
+    "stats.monthsDescription": "Duplicates found by the month they were indexed, and files moved to the trash folder",
+    "stats.monthFound": "{count} found, {size}",
+    "stats.monthDeleted": "{count} deleted, {size}",
+    "stats.empty": "No duplicates",
+    "scanHistory.title": "Scan history",
+    "scanHistory.description": "Every scan run with what it changed in the index",
+    "scanHistory.lastScan": "Last scan: {time}",
+    "scanHistory.refresh": "Refresh",
+    "scanHistory.added": "{count} added",
+    "scanHistory.updated": "{count} updated",
+    "scanHistory.removed": "{count} removed",
+    "scanHistory.errors": "{count} error(s)",
+    "scanHistory.status.running": "Running",
+    "scanHistory.status.completed": "Completed",
+    "scanHistory.status.cancelled": "Cancelled",
+    "scanHistory.status.failed": "Failed",
+    "scanHistory.kind.scan": "Full scan",
+    "scanHistory.kind.scan-dir": "Folder scan",
+    "scanHistory.kind.fast-scan": "Fast scan",
+    "scanHistory.kind.fast-scan-dir": "Fast folder scan",
+    "scanHistory.kind.rehash": "Rehash",
 
     // File item
     "fileItem.selectFolder": "Click to select all files from this folder",
     "fileItem.modified": "Modified: {date}",
+    "fileItem.localHost": "this server",
+    "fileItem.corrupt": "corrupt",
+    "fileItem.corruptHint": "The file is empty or fails to decode",
+    "fileItem.protect": "Protect from deletion",
+    "fileItem.unprotect": "Protected from deletion. Click to remove the protection",
+    "fileItem.toastProtected": "The file is protected from deletion",
+    "fileItem.toastUnprotected": "The protection is removed",
 
     // Empty state
     "emptyState.title": "No Duplicates Found",
@@ -193,6 +350,13 @@ export const translations = {
     // Scan progress
     "scanProgress.scanning": "Scanning in progress...",
     "scanProgress.filesProcessed": "{count} files processed",
+    "scanProgress.estimate": "{done}/{total} files ({percent}%)",
+    "scanProgress.eta": "ETA {time}",
+    "scanProgress.paused": "Scan paused",
+    "scanProgress.pause": "Pause",
+    "scanProgress.resume": "Resume",
+    "scanProgress.cancel": "Cancel",
+    "scanProgress.unavailableRoots": "Skipped unavailable gallery folders (missing or not mounted); their files are kept in the index:",
 
     // Pagination
     "pagination.first": "First",
@@ -207,12 +371,28 @@ export const translations = {
     "deleteFiles.warning": "Warning: Deleted files cannot be easily recovered unless trash is enabled.",
     "deleteFiles.useTrash": "Move to trash",
     "deleteFiles.trashNotConfigured": "Trash directory is not configured. Set it in Settings.",
+    "deleteFiles.useSystemTrash": "Move to the system trash of the server instead",
+    "deleteFiles.verify": "Verify byte by byte against a remaining copy first (exact duplicates only)",
     "deleteFiles.button": "Delete Files",
     "deleteFiles.deleting": "Deleting...",
     "deleteFiles.confirmPermanent": "Trash is disabled. Files will be PERMANENTLY deleted. Continue?",
     "deleteFiles.success": "Successfully deleted {count} file(s).",
     "deleteFiles.successWithFailed": "Successfully deleted {count} file(s). Failed: {failed}.",
+    "deleteFiles.protectedSkipped": "Protected files left in place: {count}.",
     "deleteFiles.errorFailed": "Failed to delete files",
+    "deleteFiles.scriptHint": "Or download a script that moves these files to the trash folder (or deletes them), to run on the machine that holds them.",
+    "deleteFiles.downloadScript": "Download script",
+    "deleteFiles.downloadRestoreScript": "Download restore script",
+    "deleteFiles.scriptFailed": "Failed to generate the script",
+    "deleteFiles.scriptEncoding": "Script encoding",
+    "deleteFiles.scriptEncodingDefault": "Default encoding",
+    "deleteFiles.lineEndings": "Line endings",
+    "deleteFiles.lineEndingsDefault": "Default",
+    "replaceMode.label": "Leave in place of removed files",
+    "replaceMode.none": "Nothing",
+    "replaceMode.relativeSymlink": "Relative symlink to the kept copy",
+    "replaceMode.absoluteSymlink": "Absolute symlink to the kept copy",
+    "replaceMode.reflink": "Copy-on-write clone (same path, shared disk space)",
 
     // Batch dedup modal
     "batchDedup.title": "Batch Deduplication",
@@ -222,6 +402,8 @@ export const translations = {
     "batchDedup.noPatterns": "No folder patterns found.",
     "batchDedup.useTrash": "Move to trash",
     "batchDedup.trashNotConfigured": "Trash directory is not configured. Set it in Settings.",
+    "batchDedup.useSystemTrash": "Move to the system trash of the server instead",
+    "batchDedup.verify": "Verify byte by byte against the kept file first; skip the group on mismatch",
     "batchDedup.applyRules": "Apply Rules",
     "batchDedup.applying": "Applying...",
     "batchDedup.errorNoRules": "Please select at least one folder to keep.",
@@ -239,6 +421,27 @@ export const translations = {
     "batchDedup.finish": "Finish",
     "batchDedup.backToPattern": "Back to pattern",
     "batchDedup.skipThis": "Skip this",
+    "batchDedup.keepPolicy": "Groups without a selected folder",
+    "batchDedup.keepPolicyNone": "Leave untouched",
+    "batchDedup.policy.keep-first-scan-dir": "Keep the file in the first gallery folder",
+    "batchDedup.policy.keep-oldest": "Keep the oldest file",
+    "batchDedup.policy.keep-newest": "Keep the newest file",
+    "batchDedup.policy.keep-shortest-path": "Keep the file with the shortest path",
+    "batchDedup.policy.keep-largest-resolution": "Keep the largest resolution",
+
+    // Consolidation
+    "consolidate.title": "Consolidate into a library",
+    "consolidate.description": "Move the kept file of each duplicate group into one folder and remove the other copies.",
+    "consolidate.destination": "Library folder (inside a gallery folder)",
+    "consolidate.byDate": "Organize into year/month folders by the date taken",
+    "consolidate.keeper": "File to keep",
+    "consolidate.useSelection": "Files marked keep in the current selection",
+    "consolidate.errorNoDestination": "Please enter a library folder.",
+    "consolidate.confirm": "Move the kept files into {path} and remove all other copies?",
+    "consolidate.apply": "Consolidate",
+    "consolidate.success": "Moved {moved} file(s) into the library, removed {count} copies.",
+    "consolidate.successWithFailed": "Moved {moved} file(s) into the library, removed {count} copies. Failed: {failed}.",
+    "consolidate.errorFailed": "Failed to consolidate duplicates",
 
     // Admin panel
     "adminPanel.toastUsersLoadFailed": "Failed to load users list",
@@ -391,9 +594,41 @@ export const translations = {
     // Scan messages
     "api.scan.started": "Scan started",
     "api.scan.failed": "Failed to start scan",
+    "api.scan.keep_rule_invalid": "Invalid keep rule",
+    "api.scan.search_failed": "Failed to search files",
+    "api.scan.group_not_found": "Duplicate group not found",
+    "api.scan.stats_failed": "Failed to compute statistics",
+    "api.scan.selection_failed": "Failed to store the selection",
+    "api.scan.ignore_failed": "Failed to update ignored groups",
+    "api.scan.protect_failed": "Failed to read or change protected files",
+    "api.scan.review_failed": "Failed to store the review status",
+    "api.scan.library_failed": "Failed to create the library folder",
+    "api.scan.agent_invalid": "Invalid agent name or file path",
+    "api.scan.agent_failed": "Failed to store the files reported by the agent",
+    "api.scan.corrupt_failed": "Failed to list corrupt files",
+    "api.scan.conflicts_failed": "Failed to find name conflicts",
+    "api.scan.timeline_failed": "Failed to build the duplicate timeline",
+    "api.script.write_failed": "Failed to write the script",
+    "api.script.not_encodable": "A file path cannot be written in the script encoding",
+    "api.script.restore_no_trash": "A restore script needs a trash directory",
+    "api.script.format_invalid": "This script type cannot be written in the selected encoding or line endings",
     "api.scan.duplicate_failed": "Failed to find duplicates",
     "api.scan.no_files_selected": "No files selected",
+    "api.scan.path_not_allowed": "Files outside the gallery folders cannot be deleted",
     "api.scan.trash_dir_failed": "Failed to create trash directory",
+    "api.scan.runs_failed": "Failed to get scan history",
+    "api.scan.job_not_found": "Scan job not found",
+    "api.scan.cancelled": "Scan cancelled",
+    "api.scan.paused": "Scan paused",
+    "api.scan.resumed": "Scan resumed",
+    "api.scan.not_running": "No scan in progress",
+    "api.scan.already_paused": "Scan is already paused",
+    "api.scan.not_paused": "Scan is not paused",
+
+    // Schedule messages
+    "api.schedule.invalid": "Invalid cron expression",
+    "api.schedule.saved": "Scan schedule saved",
+    "api.schedule.save_failed": "Failed to save scan schedule",
 
     // Folder messages
     "api.folder.path_required": "Path is required",
@@ -420,6 +655,7 @@ export const translations = {
     "api.image.trash_not_exists": "Trash directory does not exist",
     "api.image.trash_read_failed": "Failed to read trash directory",
     "api.image.thumbnail_failed": "Failed to generate thumbnail",
+    "api.image.convert_failed": "Failed to convert image for display",
     "api.image.metadata_failed": "Failed to get image metadata",
 
     // Thumbnail cache messages
@@ -442,6 +678,9 @@ export const translations = {
     "api.trash.not_configured": "Trash directory is not configured",
     "api.trash.not_exists": "Trash directory does not exist",
     "api.trash.read_failed": "Failed to read trash directory",
+    "api.trash.deletions_failed": "Failed to get recent deletions",
+    "api.trash.deletion_not_found": "Deletion batch not found",
+    "api.trash.restore_failed": "Failed to restore files",
 
     // Gallery messages
     "api.gallery.conflict": "Gallery folder conflict detected",
@@ -537,7 +776,16 @@ export const translations = {
     "tabs.gallery": "Галерея",
     "tabs.tools": "Инструменты",
     "tabs.deduplication": "Дедупликация",
+    "tabs.videos": "Видео",
     "tabs.ocr": "OCR",
+    "tabs.deletions": "Недавние удаления",
+    "tabs.stats": "Статистика",
+    "tabs.timeline": "Хронология",
+    "tabs.scanHistory": "История сканирований",
+    "tabs.ignoredGroups": "Игнорируемые группы",
+    "tabs.nameCopies": "Похожие копии",
+    "tabs.corruptFiles": "Поврежденные файлы",
+    "tabs.nameConflicts": "Конфликты имен",
 
     // Loading
     "common.loading": "Загрузка...",
@@ -586,6 +834,7 @@ export const translations = {
     "settings.toastAddFailed": "Не удалось добавить папку",
     "settings.toastRemoveFailed": "Не удалось удалить папку",
     "settings.toastRescanFailed": "Не удалось начать сканирование",
+    "settings.toastFolderRescanStarted": "Сканирование папки {path} начато",
     "settings.toastFilesRemoved": "{message} ({count} файлов удалено)",
     "settings.galleryFolders": "Папки галереи",
     "settings.galleryFoldersDescription": "Управляйте папками, включенными в вашу галерею изображений. Добавление папки автоматически запустит сканирование изображений.",
@@ -609,6 +858,66 @@ export const translations = {
     "trash.cleanFailed": "Не удалось очистить корзину",
     "trash.saveFailed": "Не удалось сохранить директорию корзины",
 
+    // Scan schedule
+    "ignoredGroups.title": "Игнорируемые группы",
+    "ignoredGroups.description": "Группы, отмеченные как не дубликаты, скрыты из списка дубликатов, отчетов и пакетного удаления. Верните группу, чтобы она снова появилась в списке.",
+    "ignoredGroups.empty": "Игнорируемых групп нет.",
+    "ignoredGroups.summary": "Файлов: {count}, по {size}, скрыта {time}",
+    "ignoredGroups.unignore": "Вернуть",
+    "ignoredGroups.unignored": "Группа снова показывается среди дубликатов",
+    "nameCopies.title": "Похожие копии",
+    "nameCopies.description": "Файлы одной папки, имена которых отличаются только суффиксами вроде « (1)», « - Copy», « copy 2» или «-edited» либо пометками конфликтов синхронизации, даже если содержимое немного различается. Такие копии часто оставляют загрузки и облачная синхронизация. Первым идет файл с самым коротким именем.",
+    "nameCopies.empty": "Похожих копий не найдено.",
+    "nameCopies.summary": "Групп: {count}, файлов: {files}",
+    "nameCopies.deleteSelected": "Удалить выбранные ({count})",
+    "nameCopies.identical": "идентичен",
+    "nameCopies.similarity": "сходство {percent}%",
+    "nameCopies.different": "другое содержимое",
+    "corruptFiles.title": "Поврежденные файлы",
+    "corruptFiles.description": "Пустые файлы и изображения, которые не удается декодировать, найденные при сканировании. Из группы дубликатов обычно стоит удалить именно поврежденную копию.",
+    "corruptFiles.empty": "Поврежденных файлов не найдено.",
+    "corruptFiles.summary": "Файлов: {count}, {size}",
+    "corruptFiles.shown": "Показаны первые {shown}",
+    "corruptFiles.selectAll": "Выбрать все",
+    "corruptFiles.deleteSelected": "Удалить выбранные ({count})",
+    "timeline.title": "Дубликаты по датам",
+    "timeline.description": "Группы точных дубликатов по месяцу съемки (время съемки из EXIF, а без него -- время изменения файла). Выберите месяц, чтобы разобрать одну поездку или событие за раз.",
+    "timeline.empty": "Дубликатов нет",
+    "timeline.monthSummary": "Групп: {count}, {size}",
+    "timeline.pickMonth": "Выберите месяц, чтобы увидеть его группы дубликатов.",
+    "timeline.groupsTitle": "Дубликаты за {month}",
+    "timeline.selectCopies": "Выбрать копии",
+    "timeline.deleteSelected": "Удалить выбранные ({count})",
+    "nameConflicts.title": "Конфликты имен",
+    "nameConflicts.description": "Файлы с одинаковым именем, но разным содержимым, например два разных IMG_0001.JPG с двух камер. При слиянии их папок один затрет другой, поэтому сначала переименуйте один из них. Файлы одной версии идентичны.",
+    "nameConflicts.dirPlaceholder": "Только в этой папке (абсолютный путь)",
+    "nameConflicts.apply": "Показать",
+    "nameConflicts.empty": "Файлов с одинаковым именем и разным содержимым нет.",
+    "nameConflicts.summary": "Имен: {count}, файлов: {files}",
+    "nameConflicts.shown": "Показаны первые {shown}",
+    "nameConflicts.versions": "версий: {count}",
+    "nameConflicts.version": "версия {version}",
+    "deletions.title": "Недавние удаления",
+    "deletions.description": "Файлы, перемещенные в папку корзины, по операциям. Восстановление возвращает их на прежние места.",
+    "deletions.empty": "В папку корзины еще ничего не перемещалось.",
+    "deletions.summary": "Файлов: {count}, {size}",
+    "deletions.restoredCount": "восстановлено: {count}",
+    "deletions.restore": "Восстановить",
+    "deletions.restoring": "Восстановление...",
+    "deletions.restored": "Восстановлено файлов: {count}",
+    "deletions.restoredWithFailed": "Восстановлено файлов: {count}, не удалось: {failed}",
+    "schedule.title": "Сканирование по расписанию",
+    "schedule.description": "Автоматически запускать полное сканирование галереи по cron-расписанию.",
+    "schedule.cronLabel": "Cron-выражение (пусто -- отключено)",
+    "schedule.nextRun": "Следующий запуск: {time}",
+    "schedule.disabled": "Сканирование по расписанию отключено",
+    "schedule.recentRuns": "Последние сканирования",
+    "schedule.noRuns": "Сканирований пока не было",
+    "schedule.triggerManual": "вручную",
+    "schedule.triggerScheduled": "по расписанию",
+    "schedule.statusRunning": "выполняется",
+    "schedule.filesProcessed": "обработано файлов: {count}",
+
     // Add folder form
     "addFolder.placeholder": "Введите путь к папке, напр. C:\\Фото или /home/user/photos",
     "addFolder.button": "Добавить папку",
@@ -623,6 +932,7 @@ export const translations = {
     "folderList.removeDescription": "Вы уверены, что хотите удалить эту папку из галереи? Все проиндексированные файлы этой папки будут удалены из базы данных. Файлы на диске НЕ будут удалены.",
     "folderList.removeButton": "Удалить",
     "folderList.removing": "Удаление...",
+    "folderList.rescan": "Пересканировать папку",
 
     // Gallery tab
     "gallery.imageCount": "{count} изображений в галерее",
@@ -686,15 +996,43 @@ export const translations = {
     "toolbar.resetSelection": "Сбросить выбор",
     "toolbar.deleteSelected": "Удалить выбранные",
     "toolbar.batchDedup": "Пакетная дедупликация",
+    "toolbar.consolidate": "Собрать в библиотеку",
+    "toolbar.autoSelect": "Автовыбор…",
+    "toolbar.exportCsv": "Экспорт в CSV",
+    "toolbar.exportHtml": "HTML-отчет",
     "toolbar.filesSelected": "{count} файлов выбрано",
     "toolbar.filesSelectedOne": "{count} файл выбран",
     "toolbar.groupsPerPage": "Групп на странице:",
+    "toolbar.sortBy": "Сортировка:",
+    "toolbar.sort.default": "Крупные файлы",
+    "toolbar.sort.wasted-space": "Лишнее место",
+    "toolbar.sort.file-count": "Число файлов",
+    "toolbar.sort.newest": "Сначала новые",
+    "toolbar.sort.oldest": "Сначала старые",
+    "toolbar.sort.path": "Путь",
+    "filters.dir": "Папка, например /photos/2020",
+    "filters.ext": "Расширения: .png,.jpg",
+    "filters.minSize": "От, напр. 1MB",
+    "filters.maxSize": "До",
+    "filters.near": "Рядом: 43.70,7.26",
+    "filters.radius": "Радиус, км",
+    "filters.nearHint": "Только группы, где есть копия, снятая в пределах радиуса (по умолчанию 10 км) по данным GPS",
+    "filters.apply": "Фильтр",
+    "filters.clear": "Сбросить",
+    "filters.review.all": "Любой статус проверки",
+    "filters.review.unreviewed": "Не просмотренные",
+    "filters.review.deferred": "Отложенные",
+    "filters.review.reviewed": "Просмотренные",
+    "filters.hosts": "На:",
+    "filters.hostsHint": "Только группы с копиями на всех выбранных машинах",
 
     // Deduplication tab
     "dedup.toastScanStarted": "Сканирование начато",
     "dedup.toastScanComplete": "Сканирование завершено!",
     "dedup.toastSelectFile": "Выберите хотя бы один файл.",
     "dedup.toastScanFailed": "Не удалось начать сканирование",
+    "dedup.toastAutoSelected": "Выбрано файлов к удалению: {count} ({size})",
+    "dedup.toastAutoSelectFailed": "Не удалось выбрать файлы автоматически",
     "dedup.toastFastScanStarted": "Быстрое сканирование начато",
     "dedup.toastFastScanComplete": "Быстрое сканирование завершено",
     "dedup.fastScanStats": "{unchanged} без изменений",
@@ -707,10 +1045,68 @@ export const translations = {
     "duplicateGroup.files": "{count} файлов",
     "duplicateGroup.sizeEach": "{size} каждый",
     "duplicateGroup.md5": "MD5: {hash}",
+    "duplicateGroup.details": "Подробнее",
+    "duplicateGroup.ignore": "Не дубликаты",
+    "duplicateGroup.markReviewed": "Просмотрено",
+    "duplicateGroup.markDeferred": "Позже",
+    "duplicateGroup.reviewHint": "Нажмите еще раз, чтобы снять отметку",
+    "duplicateGroup.review.reviewed": "Просмотрена",
+    "duplicateGroup.review.deferred": "Отложена",
+    "duplicateGroup.ignoreHint": "Скрыть группу: копии сделаны намеренно",
+    "duplicateGroup.toastIgnored": "Группа скрыта. Вернуть ее можно на странице «Игнорируемые группы».",
+    "groupDetail.title": "Группа дубликатов",
+    "groupDetail.reclaimable": "Освободится {size}",
+    "groupDetail.open": "Открыть",
+    "groupDetail.keepOnly": "Оставить только этот",
+    "groupDetail.delete": "Удалить",
+    "groupDetail.protect": "Защитить",
+    "groupDetail.unprotect": "Снять защиту",
+    "stats.title": "Лишнее место",
+    "stats.description": "Где точные дубликаты занимают место в проиндексированных папках",
+    "stats.refresh": "Обновить",
+    "stats.reclaimable": "Можно освободить",
+    "stats.duplicateGroups": "Групп дубликатов",
+    "stats.duplicateFiles": "Файлов в группах",
+    "stats.indexedFiles": "Проиндексировано файлов",
+    "stats.byDirectory": "По папкам",
+    "stats.byExtension": "По расширениям",
+    "stats.noExtension": "(без расширения)",
+    "stats.shareSummary": "{count} файлов, {size}",
+    "stats.largestGroups": "Крупнейшие группы",
+    "stats.groupSummary": "{count} × {size}",
+    "stats.months": "По месяцам",
+    "stats.monthsDescription": "Найденные дубликаты по месяцу индексации и файлы, перемещенные в корзину",
+    "stats.monthFound": "найдено {count}, {size}",
+    "stats.monthDeleted": "удалено {count}, {size}",
+    "stats.empty": "Дубликатов нет",
+    "scanHistory.title": "История сканирований",
+    "scanHistory.description": "Все запуски сканирования и изменения в индексе",
+    "scanHistory.lastScan": "Последнее сканирование: {time}",
+    "scanHistory.refresh": "Обновить",
+    "scanHistory.added": "добавлено: {count}",
+    "scanHistory.updated": "обновлено: {count}",
+    "scanHistory.removed": "удалено: {count}",
+    "scanHistory.errors": "ошибок: {count}",
+    "scanHistory.status.running": "Выполняется",
+    "scanHistory.status.completed": "Завершено",
+    "scanHistory.status.cancelled": "Отменено",
+    "scanHistory.status.failed": "Ошибка",
+    "scanHistory.kind.scan": "Полное сканирование",
+    "scanHistory.kind.scan-dir": "Сканирование папки",
+    "scanHistory.kind.fast-scan": "Быстрое сканирование",
+    "scanHistory.kind.fast-scan-dir": "Быстрое сканирование папки",
+    "scanHistory.kind.rehash": "Пересчет хешей",
 
     // File item
     "fileItem.selectFolder": "Нажмите, чтобы выбрать все файлы из этой папки",
     "fileItem.modified": "Изменён: {date}",
+    "fileItem.localHost": "этот сервер",
+    "fileItem.corrupt": "поврежден",
+    "fileItem.corruptHint": "Файл пуст или не декодируется",
+    "fileItem.protect": "Защитить от удаления",
+    "fileItem.unprotect": "Защищен от удаления. Нажмите, чтобы снять защиту",
+    "fileItem.toastProtected": "Файл защищен от удаления",
+    "fileItem.toastUnprotected": "Защита снята",
 
     // Empty state
     "emptyState.title": "Дубликаты не найдены",
@@ -719,6 +1115,13 @@ export const translations = {
     // Scan progress
     "scanProgress.scanning": "Сканирование...",
     "scanProgress.filesProcessed": "{count} файлов обработано",
+    "scanProgress.estimate": "{done}/{total} файлов ({percent}%)",
+    "scanProgress.eta": "осталось {time}",
+    "scanProgress.paused": "Сканирование приостановлено",
+    "scanProgress.pause": "Пауза",
+    "scanProgress.resume": "Продолжить",
+    "scanProgress.cancel": "Отменить",
+    "scanProgress.unavailableRoots": "Пропущены недоступные папки галереи (отсутствуют или не смонтированы); их файлы сохранены в индексе:",
 
     // Pagination
     "pagination.first": "Первая",
@@ -733,12 +1136,28 @@ export const translations = {
     "deleteFiles.warning": "Внимание: удалённые файлы невозможно легко восстановить, если корзина не включена.",
     "deleteFiles.useTrash": "Удалять в корзину",
     "deleteFiles.trashNotConfigured": "Директория корзины не настроена. Укажите её в Настройках.",
+    "deleteFiles.useSystemTrash": "Удалять в системную корзину сервера",
+    "deleteFiles.verify": "Сначала побайтно сверить с оставшейся копией (только точные дубликаты)",
     "deleteFiles.button": "Удалить файлы",
     "deleteFiles.deleting": "Удаление...",
     "deleteFiles.confirmPermanent": "Корзина отключена. Файлы будут БЕЗВОЗВРАТНО удалены. Продолжить?",
     "deleteFiles.success": "Успешно удалено {count} файлов.",
     "deleteFiles.successWithFailed": "Успешно удалено {count} файлов. Ошибок: {failed}.",
+    "deleteFiles.protectedSkipped": "Защищенных файлов оставлено: {count}.",
     "deleteFiles.errorFailed": "Не удалось удалить файлы",
+    "deleteFiles.scriptHint": "Или скачайте скрипт, который переместит эти файлы в папку корзины (или удалит их), чтобы запустить его на машине с файлами.",
+    "deleteFiles.downloadScript": "Скачать скрипт",
+    "deleteFiles.downloadRestoreScript": "Скачать скрипт восстановления",
+    "deleteFiles.scriptFailed": "Не удалось сформировать скрипт",
+    "deleteFiles.scriptEncoding": "Кодировка скрипта",
+    "deleteFiles.scriptEncodingDefault": "Кодировка по умолчанию",
+    "deleteFiles.lineEndings": "Окончания строк",
+    "deleteFiles.lineEndingsDefault": "По умолчанию",
+    "replaceMode.label": "Оставить на месте удаленных файлов",
+    "replaceMode.none": "Ничего",
+    "replaceMode.relativeSymlink": "Относительную ссылку на сохраненную копию",
+    "replaceMode.absoluteSymlink": "Абсолютную ссылку на сохраненную копию",
+    "replaceMode.reflink": "Клон copy-on-write (тот же путь, общее место на диске)",
 
     // Batch dedup modal
     "batchDedup.title": "Пакетная дедупликация",
@@ -748,6 +1167,8 @@ export const translations = {
     "batchDedup.noPatterns": "Шаблоны папок не найдены.",
     "batchDedup.useTrash": "Удалять в корзину",
     "batchDedup.trashNotConfigured": "Директория корзины не настроена. Укажите её в Настройках.",
+    "batchDedup.useSystemTrash": "Удалять в системную корзину сервера",
+    "batchDedup.verify": "Сначала побайтно сверить с сохраняемым файлом; при расхождении пропустить группу",
     "batchDedup.applyRules": "Применить правила",
     "batchDedup.applying": "Применение...",
     "batchDedup.errorNoRules": "Выберите хотя бы одну папку для сохранения.",
@@ -765,6 +1186,27 @@ export const translations = {
     "batchDedup.finish": "Завершить",
     "batchDedup.backToPattern": "Вернуться к шаблону",
     "batchDedup.skipThis": "Пропустить этот",
+    "batchDedup.keepPolicy": "Группы без выбранной папки",
+    "batchDedup.keepPolicyNone": "Не трогать",
+    "batchDedup.policy.keep-first-scan-dir": "Оставить файл в первой папке галереи",
+    "batchDedup.policy.keep-oldest": "Оставить самый старый файл",
+    "batchDedup.policy.keep-newest": "Оставить самый новый файл",
+    "batchDedup.policy.keep-shortest-path": "Оставить файл с самым коротким путем",
+    "batchDedup.policy.keep-largest-resolution": "Оставить наибольшее разрешение",
+
+    // Consolidation
+    "consolidate.title": "Собрать в библиотеку",
+    "consolidate.description": "Переместить сохраняемый файл каждой группы дубликатов в одну папку и удалить остальные копии.",
+    "consolidate.destination": "Папка библиотеки (внутри папки галереи)",
+    "consolidate.byDate": "Раскладывать по папкам год/месяц по дате съёмки",
+    "consolidate.keeper": "Какой файл сохранить",
+    "consolidate.useSelection": "Отмеченные для сохранения в текущем выборе",
+    "consolidate.errorNoDestination": "Укажите папку библиотеки.",
+    "consolidate.confirm": "Переместить сохраняемые файлы в {path} и удалить все остальные копии?",
+    "consolidate.apply": "Собрать",
+    "consolidate.success": "Перемещено в библиотеку: {moved}, удалено копий: {count}.",
+    "consolidate.successWithFailed": "Перемещено в библиотеку: {moved}, удалено копий: {count}. Ошибок: {failed}.",
+    "consolidate.errorFailed": "Не удалось собрать дубликаты",
 
     // Admin panel
     "adminPanel.toastUsersLoadFailed": "Не удалось загрузить список пользователей",
@@ -877,9 +1319,41 @@ export const translations = {
     // Scan messages
     "api.scan.started": "Сканирование начато",
     "api.scan.failed": "Не удалось начать сканирование",
+    "api.scan.keep_rule_invalid": "Некорректное правило сохранения",
+    "api.scan.search_failed": "Не удалось выполнить поиск файлов",
+    "api.scan.group_not_found": "Группа дубликатов не найдена",
+    "api.scan.stats_failed": "Не удалось собрать статистику",
+    "api.scan.selection_failed": "Не удалось сохранить выбор",
+    "api.scan.ignore_failed": "Не удалось изменить список игнорируемых групп",
+    "api.scan.protect_failed": "Не удалось прочитать или изменить защиту файлов",
+    "api.scan.review_failed": "Не удалось сохранить статус проверки",
+    "api.scan.library_failed": "Не удалось создать папку библиотеки",
+    "api.scan.agent_invalid": "Недопустимое имя агента или путь к файлу",
+    "api.scan.agent_failed": "Не удалось сохранить файлы, переданные агентом",
+    "api.scan.corrupt_failed": "Не удалось получить список поврежденных файлов",
+    "api.scan.conflicts_failed": "Не удалось найти конфликты имен",
+    "api.scan.timeline_failed": "Не удалось построить хронологию дубликатов",
+    "api.script.write_failed": "Не удалось записать скрипт",
+    "api.script.not_encodable": "Путь к файлу нельзя записать в кодировке скрипта",
+    "api.script.restore_no_trash": "Для скрипта восстановления нужна папка корзины",
+    "api.script.format_invalid": "Скрипт этого типа нельзя записать в выбранной кодировке или с выбранными окончаниями строк",
     "api.scan.duplicate_failed": "Не удалось найти дубликаты",
     "api.scan.no_files_selected": "Файлы не выбраны",
+    "api.scan.path_not_allowed": "Нельзя удалять файлы вне папок галереи",
     "api.scan.trash_dir_failed": "Не удалось создать директорию корзины",
+    "api.scan.runs_failed": "Не удалось получить историю сканирований",
+    "api.scan.job_not_found": "Задача сканирования не найдена",
+    "api.scan.cancelled": "Сканирование отменено",
+    "api.scan.paused": "Сканирование приостановлено",
+    "api.scan.resumed": "Сканирование возобновлено",
+    "api.scan.not_running": "Сканирование не выполняется",
+    "api.scan.already_paused": "Сканирование уже приостановлено",
+    "api.scan.not_paused": "Сканирование не приостановлено",
+
+    // Schedule messages
+    "api.schedule.invalid": "Некорректное cron-выражение",
+    "api.schedule.saved": "Расписание сканирования сохранено",
+    "api.schedule.save_failed": "Не удалось сохранить расписание сканирования",
 
     // Folder messages
     "api.folder.path_required": "Требуется путь",
@@ -906,6 +1380,7 @@ export const translations = {
     "api.image.trash_not_exists": "Директория корзины не существует",
     "api.image.trash_read_failed": "Не удалось прочитать директорию корзины",
     "api.image.thumbnail_failed": "Не удалось создать миниатюру",
+    "api.image.convert_failed": "Не удалось преобразовать изображение для просмотра",
     "api.image.metadata_failed": "Не удалось получить метаданные изображения",
 
     // Thumbnail cache messages
@@ -928,6 +1403,9 @@ export const translations = {
     "api.trash.not_configured": "Директория корзины не настроена",
     "api.trash.not_exists": "Директория корзины не существует",
     "api.trash.read_failed": "Не удалось прочитать директорию корзины",
+    "api.trash.deletions_failed": "Не удалось получить список удалений",
+    "api.trash.deletion_not_found": "Удаление не найдено",
+    "api.trash.restore_failed": "Не удалось восстановить файлы",
 
     // Gallery messages
     "api.gallery.conflict": "Обнаружен конфликт папок галереи",
@@ -1,3 +1,19 @@
 export const PAGE_SIZES = [50, 100, 250, 500] as const
 export const DEFAULT_PAGE_SIZE = 50
 export const SCAN_POLL_INTERVAL = 1000
+
+// Orders of the duplicate list; "default" lists the groups with the largest files first
+export const DUPLICATE_SORTS = ["default", "wasted-space", "file-count", "newest", "oldest", "path"] as const
+
+// Keep policies offered in the UI; keep-in-priority-directory needs a directory
+// list and is available through the API only
+export const KEEP_POLICIES = [
+  "keep-first-scan-dir",
+  "keep-oldest",
+  "keep-newest",
+  "keep-shortest-path",
+  "keep-largest-resolution",
+] as const
+
+// Script encodings offered in the UI; the API accepts any IANA charset name
+export const SCRIPT_ENCODINGS = ["utf-8", "utf-8-bom", "utf-16le", "windows-1251", "windows-1252", "cp866"] as const
@@ -4,6 +4,19 @@ export interface FileDTO {
   fileName: string
   dirPath: string
   modTime: string
+  size: number
+  similarity: number
+  // Scaled duplicates and bursts only; the original or best frame is recommended
+  width?: number
+  height?: number
+  recommended?: boolean
+  takenAt?: string
+  // Pinned against deletion
+  protected?: boolean
+  // Agent that reported the file, unset for the files of this server
+  host?: string
+  // Empty, or an image that fails to decode
+  corrupt?: boolean
 }
 
 export interface DuplicateGroupDTO {
@@ -12,8 +25,27 @@ export interface DuplicateGroupDTO {
   size: number
   sizeHuman: string
   files: FileDTO[]
-  thumbnail: string
+  thumbnail: string // URL of GET /api/v1/thumbnail, resolve with thumbnailSrc
   thumbnailCachePath?: string
+  // Exact duplicates only; unset while the group is unreviewed
+  review?: ReviewStatus
+}
+
+// Review status of an exact duplicate group, kept across cleanup sessions
+export type ReviewStatus = "reviewed" | "deferred"
+export type ReviewFilter = ReviewStatus | "unreviewed"
+
+export interface ReviewGroupResponse {
+  hash: string
+  status: ReviewStatus | ""
+}
+
+// GroupResponse is one exact duplicate group shown on its own
+export interface GroupResponse {
+  group: DuplicateGroupDTO
+  // freed by keeping a single file
+  reclaimableBytes: number
+  reclaimableHuman: string
 }
 
 export interface DuplicatesResponse {
@@ -28,10 +60,66 @@ export interface DuplicatesResponse {
   hasPrevPage: boolean
   hasNextPage: boolean
   pageSizes: number[]
+  mode: DuplicateMode
+  sort?: DuplicateSort
+  media: DuplicateMedia
+  threshold?: number
+  window?: number
+}
+
+export type DuplicateMode = "exact" | "similar" | "pixel" | "scaled" | "burst" | "name" | "exif"
+
+export type DuplicateMedia = "image" | "video"
+
+// DuplicateSort orders the duplicate list; without one, groups with the largest files come first
+export type DuplicateSort = "wasted-space" | "file-count" | "newest" | "oldest" | "path"
+
+// DuplicateFilters narrow the duplicate list; sizes accept units such as "500KB" or "1MB"
+export interface DuplicateFilters {
+  dir?: string
+  ext?: string
+  minSize?: string
+  maxSize?: string
+  review?: ReviewFilter
+  // comma-separated hosts the groups have copies on, "local" for this server
+  hosts?: string
+  // YYYY-MM: exact groups shot in this month, see fetchTimeline
+  month?: string
+  // "latitude,longitude": exact groups with a copy shot within radius km by its GPS data
+  near?: string
+  radius?: string
+}
+
+// HostDTO is a machine files are indexed on: this server or an agent
+export interface HostDTO {
+  name: string
+  local: boolean
+  files: number
+}
+
+export interface HostsResponse {
+  hosts: HostDTO[]
 }
 
 export interface ScanResponse {
   message: string
+  jobId?: string
+}
+
+export type ScanJobState = "queued" | "running" | "done" | "failed" | "cancelled"
+
+export interface ScanJobDTO {
+  id: string
+  kind: string
+  state: ScanJobState
+  progress: string
+  filesProcessed: number
+  errors: number
+  error?: string
+  createdAt: string
+  startedAt?: string
+  finishedAt?: string
+  elapsedMs: number
 }
 
 export interface FastScanResponse {
@@ -40,28 +128,232 @@ export interface FastScanResponse {
   modified: number
   created: number
   deleted: number
+  moved: number
   total: number
 }
 
 export interface ScanStatusResponse {
   scanning: boolean
+  paused?: boolean
+  jobId?: string
   progress: string
   filesProcessed: number
+  // Estimated completion, omitted while the number of files to scan is unknown
+  filesDone?: number
+  filesTotal?: number
+  percent?: number
+  etaSeconds?: number
+  // Gallery folders the last scan skipped as missing or unmounted
+  unavailableRoots?: string[]
+}
+
+// --- WebSocket (/api/v1/ws) Types ---
+
+export interface NewDuplicateGroup {
+  hash: string
+  size: number
+  paths: string[]
+}
+
+export type ScanCommand = "pause" | "resume" | "cancel"
+
+export interface ScanCommandResult {
+  command: ScanCommand
+  ok: boolean
+  error?: string
+}
+
+export type ServerEvent =
+  | { type: "scan.status"; time: string; data: ScanStatusResponse }
+  | { type: "scan.finished"; time: string; data: ScanStatusResponse }
+  | { type: "duplicates.new"; time: string; data: NewDuplicateGroup[] }
+  | { type: "files.deleted"; time: string; data: DeleteFilesResponse }
+  | { type: "command.result"; time: string; data: ScanCommandResult }
+
+export interface ScheduleDTO {
+  schedule: string
+  nextRun?: string
+}
+
+export interface UpdateScheduleRequest {
+  schedule: string
+}
+
+export interface ScanRunDTO {
+  id: number
+  kind: "scan" | "scan-dir" | "fast-scan" | "fast-scan-dir" | "rehash"
+  trigger: "manual" | "scheduled"
+  status: "running" | "completed" | "cancelled" | "failed"
+  directories: string[]
+  filesProcessed: number
+  filesAdded: number
+  filesUpdated: number
+  filesRemoved: number
+  // files that could not be read or hashed
+  errors: number
+  // reason the run failed
+  error?: string
+  startedAt: string
+  finishedAt?: string
+  // set once the run has finished
+  elapsedMs?: number
+}
+
+export interface ScanRunsResponse {
+  runs: ScanRunDTO[]
+}
+
+// Recent deletions (undo)
+export interface DeletedFileDTO {
+  originalPath: string
+  trashPath: string
+  size: number
+  restoredAt?: string
+}
+
+export interface DeletionBatchDTO {
+  batchId: string
+  deletedAt: string
+  fileCount: number
+  restoredCount: number
+  totalSize: number
+  files: DeletedFileDTO[]
+}
+
+export interface DeletionsResponse {
+  batches: DeletionBatchDTO[]
+}
+
+// An exact duplicate group marked as not duplicates; path and files describe it when ignored
+export interface IgnoredGroupDTO {
+  id: number
+  hash: string
+  hashAlgo: string
+  size: number
+  sizeHuman: string
+  path: string
+  files: number
+  ignoredAt: string
+}
+
+export interface IgnoredGroupsResponse {
+  groups: IgnoredGroupDTO[]
 }
 
-export interface ThumbnailResponse {
-  thumbnail: string
+// NameConflictsResponse lists file names shared by files of different content;
+// the totals cover all conflicts, also those beyond the limit
+export interface NameConflictsResponse {
+  totalConflicts: number
+  totalFiles: number
+  conflicts: NameConflictDTO[]
+}
+
+export interface NameConflictDTO {
+  name: string
+  versions: number
+  files: NameConflictFileDTO[]
+}
+
+// Files of the same version are identical
+export interface NameConflictFileDTO {
+  id: number
+  path: string
+  dirPath: string
+  size: number
+  modTime: string
+  version: number
+}
+
+// CorruptFilesResponse lists empty and undecodable files; total and totalSize cover all of them
+export interface CorruptFilesResponse {
+  files: FileDTO[]
+  total: number
+  totalSize: number
+}
+
+// StatsResponse reports where duplicates waste space; sizes are in bytes
+export interface StatsResponse {
+  indexedFiles: number
+  indexedBytes: number
+  duplicateGroups: number
+  // including the copy to keep
+  duplicateFiles: number
+  duplicateBytes: number
+  // freed by keeping one file per group
+  reclaimableBytes: number
+  directories: StatsShareDTO[]
+  extensions: StatsShareDTO[]
+  largestGroups: StatsGroupDTO[]
+  // oldest first
+  months: StatsMonthDTO[]
+}
+
+// StatsShareDTO is the part of the duplicates in one directory or with one extension ("" for none)
+export interface StatsShareDTO {
+  key: string
+  files: number
+  bytes: number
+}
+
+export interface StatsGroupDTO {
+  hash: string
+  size: number
+  files: number
+  reclaimableBytes: number
+  path: string
+}
+
+export interface StatsMonthDTO {
+  month: string // YYYY-MM
+  duplicateFiles: number
+  duplicateBytes: number
+  deletedFiles: number
+  deletedBytes: number
+}
+
+// TimelineResponse counts the exact duplicate groups per month of the earliest
+// capture time (or modification time) of their copies, oldest first
+export interface TimelineResponse {
+  months: TimelineMonthDTO[]
 }
 
+export interface TimelineMonthDTO {
+  month: string // YYYY-MM
+  groups: number
+  files: number
+  reclaimableBytes: number
+}
+
+export interface RestoreResponse {
+  restored: number
+  failed: number
+  failedFiles?: string[]
+}
+
+// ReplaceMode leaves a symlink to a remaining copy in place of a removed duplicate,
+// or turns it into a copy-on-write clone of that copy
+export type ReplaceMode = "relative-symlink" | "absolute-symlink" | "reflink"
+
 export interface DeleteFilesRequest {
   filePaths: string[]
   trashDir: string
+  // systemTrash moves files to the OS trash of the server user instead of trashDir
+  systemTrash?: boolean
+  // verify compares each file byte by byte with a remaining copy before removing it
+  verify?: boolean
+  replace?: ReplaceMode
+  // useSelection deletes the files marked "delete" in the stored selection when filePaths is empty
+  useSelection?: boolean
 }
 
 export interface DeleteFilesResponse {
   success: number
   failed: number
   failedFiles?: string[]
+  // Set when files were moved to the trash folder, for restoring them
+  batchId?: string
+  // Pinned files that were left in place
+  protectedFiles?: string[]
 }
 
 export interface FolderPattern {
@@ -80,15 +372,124 @@ export interface BatchDeleteRule {
   keepFolder: string
 }
 
+export type KeepPolicy =
+  | "keep-oldest"
+  | "keep-newest"
+  | "keep-shortest-path"
+  | "keep-largest-resolution"
+  | "keep-in-priority-directory"
+  | "keep-first-scan-dir"
+
+// KeepRule decides which file survives in groups without a folder rule;
+// rules are applied in order as tie-breakers
+export interface KeepRule {
+  policy: KeepPolicy
+  directories?: string[]
+}
+
 export interface BatchDeleteRequest {
   rules: BatchDeleteRule[]
+  keepRules?: KeepRule[]
+  // useSelection decides groups with marked files by the stored selection first
+  useSelection?: boolean
   trashDir: string
+  // systemTrash moves files to the OS trash of the server user instead of trashDir
+  systemTrash?: boolean
+  // verify compares each file byte by byte with a remaining copy before removing it
+  verify?: boolean
+  replace?: ReplaceMode
+}
+
+export interface AutoSelectRequest {
+  keepRules: KeepRule[]
+  mode?: DuplicateMode
+  threshold?: number
+  // burst mode: longest gap between shots in seconds
+  window?: number
+  media?: DuplicateMedia
+  dir?: string
+  ext?: string
+  minSize?: string
+  maxSize?: string
+  review?: ReviewFilter
+  hosts?: string
+  month?: string
+  near?: string
+  radius?: string
+}
+
+export interface AutoSelectGroupDTO {
+  hash: string
+  keep: string
+  remove: string[]
+}
+
+export interface AutoSelectResponse {
+  mode: DuplicateMode
+  groups: AutoSelectGroupDTO[]
+  totalRemove: number
+  reclaimableBytes: number
+  reclaimableHuman: string
+}
+
+// A file marked in the duplicate view; an empty action removes the mark in an update
+export type SelectionAction = "keep" | "delete" | ""
+
+export interface SelectionItemDTO {
+  path: string
+  action: SelectionAction
+}
+
+export interface SetProtectedResponse {
+  updated: number
+}
+
+export interface SelectionResponse {
+  items: SelectionItemDTO[]
 }
 
 export interface BatchDeleteResponse {
   success: number
   failed: number
   failedFiles?: string[]
+  // Set when files were moved to the trash folder, for restoring them
+  batchId?: string
+  // Pinned files that were left in place
+  protectedFiles?: string[]
+  // Total size of the removed files, set by batch deletes
+  freedBytes?: number
+}
+
+export type ScriptType = "bash" | "sh" | "powershell" | "bat"
+
+// GenerateScriptRequest asks for a script that moves files to trashDir, or deletes
+// them when it is empty
+export interface GenerateScriptRequest {
+  filePaths: string[]
+  trashDir: string
+  scriptType?: ScriptType
+  // encoding and lineEndings default to those of the script type
+  encoding?: string
+  lineEndings?: "lf" | "crlf"
+  // restore selects the script moving the files back from trashDir
+  restore?: boolean
+}
+
+// ConsolidateRequest moves the kept file of each group into a library folder and
+// removes the other copies
+export interface ConsolidateRequest {
+  destination: string
+  // byDate files kept files into year/month folders by the date taken
+  byDate?: boolean
+  keepRules?: KeepRule[]
+  useSelection?: boolean
+  trashDir: string
+  systemTrash?: boolean
+  verify?: boolean
+}
+
+export interface ConsolidateResponse extends BatchDeleteResponse {
+  moved: number
 }
 
 export interface ApiError {
@@ -153,6 +554,8 @@ export interface AppSettingsDTO {
   trashDir: string
   thumbnailCachePath?: string
   thumbnailCacheSize?: number
+  videoScanEnabled: boolean
+  pixelHashEnabled: boolean
 }
 
 export interface UserSettingsDTO {